@@ -26,6 +26,8 @@ type Config interface {
 	Sync() error
 	// Watch a value for changes
 	Watch(path ...string) (Watcher, error)
+	// OnChange register a listener notified when the value at path changes
+	OnChange(f Listener, path ...string) func()
 }
 
 // Watcher is the config watcher
@@ -21,6 +21,8 @@ type config struct {
 	snap *loader.Snapshot
 	// the current values
 	vals reader.Values
+	// change listeners
+	listeners listeners
 }
 
 type watcher struct {
@@ -97,17 +99,26 @@ func (c *config) run() {
 				continue
 			}
 
+			// set values, skip the change when it cannot be resolved
+			vals, err := c.opts.Reader.Values(snap.ChangeSet)
+			if err != nil {
+				c.Unlock()
+				continue
+			}
+
 			// save
 			c.snap = snap
-
-			// set values
-			c.vals, _ = c.opts.Reader.Values(snap.ChangeSet)
+			prev := c.vals
+			c.vals = vals
 			if c.opts.Entity != nil {
 				_ = c.vals.Scan(c.opts.Entity)
 				c.opts.Entity.OnChange()
 			}
+			cur := c.vals
 
 			c.Unlock()
+
+			c.listeners.publish(prev, cur)
 		}
 	}
 
@@ -170,16 +181,18 @@ func (c *config) Sync() error {
 		return err
 	}
 
-	c.Lock()
-	defer c.Unlock()
-
-	c.snap = snap
 	vals, err := c.opts.Reader.Values(snap.ChangeSet)
 	if err != nil {
 		return err
 	}
+
+	c.Lock()
+	prev := c.vals
+	c.snap = snap
 	c.vals = vals
+	c.Unlock()
 
+	c.listeners.publish(prev, vals)
 	return nil
 }
 
@@ -249,19 +262,26 @@ func (c *config) Load(sources ...source.Source) error {
 		return err
 	}
 
-	c.Lock()
-	defer c.Unlock()
-
-	c.snap = snap
 	vals, err := c.opts.Reader.Values(snap.ChangeSet)
 	if err != nil {
 		return err
 	}
+
+	c.Lock()
+	prev := c.vals
+	c.snap = snap
 	c.vals = vals
+	c.Unlock()
 
+	c.listeners.publish(prev, vals)
 	return nil
 }
 
+// OnChange 注册配置变更监听，path为空时监听整个配置
+func (c *config) OnChange(f Listener, path ...string) func() {
+	return c.listeners.add(f, path...)
+}
+
 func (c *config) Watch(path ...string) (Watcher, error) {
 	value := c.Get(path...)
 
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-admin-team/go-admin-core/config/reader"
+)
+
+// RedactMask 敏感配置项的值在差异中的替换值
+const RedactMask = "******"
+
+// RedactKeys 名称中包含这些词(不区分大小写)的配置项视为敏感, 差异中不输出明文
+var RedactKeys = []string{"password", "secret", "token", "key", "source", "dsn", "credential"}
+
+// Change 单个配置项的变更
+type Change struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// Diff 比较两份配置, 返回按路径排序的叶子节点变更, 敏感项的值被替换为 RedactMask
+func Diff(prev, cur reader.Value) []Change {
+	before, after := flatten(prev), flatten(cur)
+	changes := make([]Change, 0)
+	for p, v := range after {
+		old, ok := before[p]
+		if ok && reflect.DeepEqual(old, v) {
+			continue
+		}
+		changes = append(changes, Change{Path: p, Old: old, New: v})
+	}
+	for p, v := range before {
+		if _, ok := after[p]; !ok {
+			changes = append(changes, Change{Path: p, Old: v})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	for i := range changes {
+		if Sensitive(changes[i].Path) {
+			if changes[i].Old != nil {
+				changes[i].Old = RedactMask
+			}
+			if changes[i].New != nil {
+				changes[i].New = RedactMask
+			}
+		}
+	}
+	return changes
+}
+
+// Sensitive 路径的最后一级是否为敏感项
+func Sensitive(path string) bool {
+	name := strings.ToLower(path[strings.LastIndex(path, ".")+1:])
+	for _, k := range RedactKeys {
+		if strings.Contains(name, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func flatten(v reader.Value) map[string]interface{} {
+	out := make(map[string]interface{})
+	if v == nil {
+		return out
+	}
+	var data interface{}
+	if err := v.Scan(&data); err != nil {
+		return out
+	}
+	walk("", data, out)
+	return out
+}
+
+func walk(prefix string, v interface{}, out map[string]interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, item := range t {
+			walk(join(prefix, k), item, out)
+		}
+	case []interface{}:
+		for i, item := range t {
+			walk(join(prefix, strconv.Itoa(i)), item, out)
+		}
+	default:
+		if prefix != "" {
+			out[prefix] = v
+		}
+	}
+}
+
+func join(prefix, k string) string {
+	if prefix == "" {
+		return k
+	}
+	return prefix + "." + k
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/config/reader"
+	"github.com/go-admin-team/go-admin-core/config/reader/json"
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+func values(t *testing.T, data string) reader.Value {
+	v, err := json.NewReader().Values(&source.ChangeSet{Data: []byte(data), Format: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v.Get()
+}
+
+func TestDiff(t *testing.T) {
+	prev := values(t, `{"settings":{"logger":{"level":"info"},"database":{"source":"root:a@/db"},"jwt":{"timeout":3600},"gen":{"dbname":"x"}}}`)
+	cur := values(t, `{"settings":{"logger":{"level":"debug"},"database":{"source":"root:b@/db"},"jwt":{"timeout":3600},"cache":{"memory":""}}}`)
+
+	changes := Diff(prev, cur)
+	expected := []Change{
+		{Path: "settings.cache.memory", New: ""},
+		{Path: "settings.database.source", Old: RedactMask, New: RedactMask},
+		{Path: "settings.gen.dbname", Old: "x"},
+		{Path: "settings.logger.level", Old: "info", New: "debug"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %+v", len(expected), changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], changes[i])
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/config/reader"
+)
+
+// Event 配置变更事件，只有监听路径下的值发生变化时才会发布
+type Event struct {
+	// Path 监听的路径，为空表示整个配置
+	Path []string
+	// Previous 变更前的值
+	Previous reader.Value
+	// Current 变更后的值
+	Current reader.Value
+	// Timestamp 变更时间
+	Timestamp time.Time
+}
+
+// Scan 将变更后的值解析到v
+func (e *Event) Scan(v interface{}) error {
+	return e.Current.Scan(v)
+}
+
+// Listener 配置变更监听函数
+type Listener func(event *Event)
+
+type listener struct {
+	id   uint64
+	path []string
+	f    Listener
+}
+
+type listeners struct {
+	sync.RWMutex
+	seq  uint64
+	list []*listener
+}
+
+func (l *listeners) add(f Listener, path ...string) func() {
+	l.Lock()
+	defer l.Unlock()
+	l.seq++
+	id := l.seq
+	l.list = append(l.list, &listener{id: id, path: path, f: f})
+	return func() {
+		l.Lock()
+		defer l.Unlock()
+		for i := range l.list {
+			if l.list[i].id == id {
+				l.list = append(l.list[:i], l.list[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// publish 对比前后两份配置，向值发生变化的监听者发布事件
+func (l *listeners) publish(prev, cur reader.Values) {
+	if cur == nil {
+		return
+	}
+	l.RLock()
+	list := make([]*listener, len(l.list))
+	copy(list, l.list)
+	l.RUnlock()
+
+	now := time.Now()
+	for _, item := range list {
+		var before reader.Value = newValue()
+		if prev != nil {
+			before = prev.Get(item.path...)
+		}
+		after := cur.Get(item.path...)
+		if bytes.Equal(before.Bytes(), after.Bytes()) {
+			continue
+		}
+		item.f(&Event{
+			Path:      item.path,
+			Previous:  before,
+			Current:   after,
+			Timestamp: now,
+		})
+	}
+}
+
+// OnChange 注册监听函数，返回取消监听的函数
+func OnChange(f Listener, path ...string) func() {
+	return DefaultConfig.OnChange(f, path...)
+}
+
+// Bind 将path对应的配置解析到类型T，变更后以新旧两个值回调f
+func Bind[T any](c Config, f func(prev, cur *T), path ...string) func() {
+	return c.OnChange(func(e *Event) {
+		prev, cur := new(T), new(T)
+		_ = e.Previous.Scan(prev)
+		if err := e.Current.Scan(cur); err != nil {
+			return
+		}
+		f(prev, cur)
+	}, path...)
+}
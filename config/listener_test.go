@@ -0,0 +1,52 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+	"github.com/go-admin-team/go-admin-core/config/source/memory"
+)
+
+func TestConfigOnChange(t *testing.T) {
+	s := memory.NewSource(memory.WithJSON([]byte(`{"logger": {"level": "info"}, "cache": {"ttl": 60}}`)))
+	conf, err := NewConfig(WithSource(s))
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	defer conf.Close()
+
+	type loggerConfig struct {
+		Level string `json:"level"`
+	}
+	levels := make(chan [2]string, 1)
+	Bind(conf, func(prev, cur *loggerConfig) {
+		levels <- [2]string{prev.Level, cur.Level}
+	}, "logger")
+	cacheChanged := make(chan struct{}, 1)
+	cancel := conf.OnChange(func(*Event) {
+		cacheChanged <- struct{}{}
+	}, "cache")
+	cancel()
+
+	// wait for the source watchers to start
+	time.Sleep(100 * time.Millisecond)
+	_ = s.Write(&source.ChangeSet{
+		Data:   []byte(`{"logger": {"level": "debug"}, "cache": {"ttl": 30}}`),
+		Format: "json",
+	})
+
+	select {
+	case l := <-levels:
+		if l[0] != "info" || l[1] != "debug" {
+			t.Errorf("Expected info -> debug but got %s -> %s", l[0], l[1])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected change event for logger")
+	}
+	select {
+	case <-cacheChanged:
+		t.Error("Expected no event after listener cancelled")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
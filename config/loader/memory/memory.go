@@ -68,8 +68,13 @@ func (m *memory) watch(idx int, s source.Source) {
 				return err
 			}
 
-			// set values
-			m.vals, _ = m.opts.Reader.Values(set)
+			// set values, keep the previous values when the new set cannot be resolved
+			vals, err := m.opts.Reader.Values(set)
+			if err != nil {
+				m.Unlock()
+				return err
+			}
+			m.vals = vals
 			m.snap = &loader.Snapshot{
 				ChangeSet: set,
 				Version:   genVer(),
@@ -140,7 +145,12 @@ func (m *memory) reload() error {
 	}
 
 	// set values
-	m.vals, _ = m.opts.Reader.Values(set)
+	vals, err := m.opts.Reader.Values(set)
+	if err != nil {
+		m.Unlock()
+		return err
+	}
+	m.vals = vals
 	m.snap = &loader.Snapshot{
 		ChangeSet: set,
 		Version:   genVer(),
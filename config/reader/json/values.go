@@ -23,8 +23,11 @@ type jsonValue struct {
 
 func newValues(ch *source.ChangeSet) (reader.Values, error) {
 	sj := simple.New()
-	data, _ := reader.ReplaceEnvVars(ch.Data)
-	if err := sj.UnmarshalJSON(data); err != nil {
+	data, err := reader.ReplaceEnvVars(ch.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err = sj.UnmarshalJSON(data); err != nil {
 		sj.SetPath(nil, string(ch.Data))
 	}
 	return &jsonValues{ch, sj}, nil
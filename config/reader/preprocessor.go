@@ -1,23 +1,87 @@
 package reader
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"regexp"
+	"strings"
+	"sync"
 )
 
+// Resolver 解析 ${scheme:ref} 中的ref, 返回实际的值
+type Resolver func(ref string) (string, error)
+
+var (
+	// ${VAR} 或 ${scheme:ref}
+	varRegexp = regexp.MustCompile(`\$\{(?:([a-z]+):([^}\s]+)|([A-Za-z0-9_]+))\}`)
+
+	resolverMu sync.RWMutex
+	resolvers  = map[string]Resolver{
+		"env":   resolveEnv,
+		"file":  resolveFile,
+		"vault": resolveVault,
+	}
+)
+
+// RegisterResolver 注册 ${scheme:ref} 的解析方法, 同名覆盖
+func RegisterResolver(scheme string, r Resolver) {
+	resolverMu.Lock()
+	defer resolverMu.Unlock()
+	resolvers[scheme] = r
+}
+
+// ReplaceEnvVars 替换配置中的变量引用, 在加载和重新加载时执行
+//
+//	${VAR}              环境变量
+//	${env:VAR}          环境变量
+//	${file:/run/secret} 文件内容, 去掉末尾的换行
+//	${vault:path#key}   vault中path下的key, 见 resolveVault
+//
+// 未注册的scheme保持原样
 func ReplaceEnvVars(raw []byte) ([]byte, error) {
-	re := regexp.MustCompile(`\$\{([A-Za-z0-9_]+)\}`)
-	if re.Match(raw) {
-		dataS := string(raw)
-		res := re.ReplaceAllStringFunc(dataS, replaceEnvVars)
-		return []byte(res), nil
-	} else {
+	if !varRegexp.Match(raw) {
 		return raw, nil
 	}
+	var errs []string
+	res := varRegexp.ReplaceAllFunc(raw, func(element []byte) []byte {
+		m := varRegexp.FindSubmatch(element)
+		if len(m[3]) > 0 {
+			return escape(os.Getenv(string(m[3])))
+		}
+		resolverMu.RLock()
+		r, ok := resolvers[string(m[1])]
+		resolverMu.RUnlock()
+		if !ok {
+			return element
+		}
+		v, err := r(string(m[2]))
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", element, err.Error()))
+			return element
+		}
+		return escape(v)
+	})
+	if len(errs) > 0 {
+		return raw, fmt.Errorf("resolve config vars failed: %s", strings.Join(errs, "; "))
+	}
+	return res, nil
+}
+
+// escape 值会被写入json字符串中, 需要转义引号和换行
+func escape(v string) []byte {
+	b, _ := json.Marshal(v)
+	return b[1 : len(b)-1]
 }
 
-func replaceEnvVars(element string) string {
-	v := element[2 : len(element)-1]
-	el := os.Getenv(v)
-	return el
+func resolveEnv(ref string) (string, error) {
+	return os.Getenv(ref), nil
+}
+
+func resolveFile(ref string) (string, error) {
+	b, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
 }
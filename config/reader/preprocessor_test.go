@@ -71,3 +71,30 @@ func TestReplaceEnvVars(t *testing.T) {
 		}
 	}
 }
+
+func TestReplaceSecretVars(t *testing.T) {
+	os.Setenv("REDIS_PASSWORD", `p"ss`)
+	f, err := os.CreateTemp(t.TempDir(), "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("s3cret\n")
+	f.Close()
+	RegisterResolver("test", func(ref string) (string, error) {
+		return "resolved-" + ref, nil
+	})
+
+	data := []byte(`{"redis": "${env:REDIS_PASSWORD}", "db": "${file:` + f.Name() + `}", "t": "${test:a/b#c}", "u": "${unknown:x}"}`)
+	res, err := ReplaceEnvVars(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"redis": "p\"ss", "db": "s3cret", "t": "resolved-a/b#c", "u": "${unknown:x}"}`
+	if string(res) != expected {
+		t.Fatalf("Expected %s got %s", expected, res)
+	}
+
+	if _, err = ReplaceEnvVars([]byte(`{"db": "${file:/not/exists}"}`)); err == nil {
+		t.Fatal("expected error for missing secret file")
+	}
+}
@@ -0,0 +1,63 @@
+package reader
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+var vaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// resolveVault 读取vault中的secret, ref格式为 path#key, 如 secret/data/go-admin#db_password
+// 地址和token取自环境变量 VAULT_ADDR, VAULT_TOKEN, 同时兼容kv v1和v2
+func resolveVault(ref string) (string, error) {
+	i := strings.LastIndex(ref, "#")
+	if i <= 0 || i == len(ref)-1 {
+		return "", fmt.Errorf("vault ref must be path#key")
+	}
+	path, key := strings.Trim(ref[:i], "/"), ref[i+1:]
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		addr = "http://127.0.0.1:8200"
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", os.Getenv("VAULT_TOKEN"))
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+	resp, err := vaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault read %s failed, status %d", path, resp.StatusCode)
+	}
+	var rsp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&rsp); err != nil {
+		return "", err
+	}
+	data := rsp.Data
+	// kv v2 的值在 data.data 中
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+	v, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("vault key %s not found in %s", key, path)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(v), nil
+}
@@ -0,0 +1,69 @@
+// Package aes is a config/secrets implementation that uses AES-GCM
+// to do symmetric encryption / verification
+package aes
+
+import (
+	"github.com/go-admin-team/go-admin-core/config/secrets"
+	"github.com/go-admin-team/go-admin-core/tools/crypto"
+	"github.com/pkg/errors"
+)
+
+type aesGCM struct {
+	options secrets.Options
+	// cipher is shared with tools/crypto
+	cipher crypto.Cipher
+}
+
+// NewSecrets returns an AES-GCM codec, the key must be 16, 24 or 32 bytes long
+func NewSecrets(opts ...secrets.Option) secrets.Secrets {
+	a := &aesGCM{}
+	for _, o := range opts {
+		o(&a.options)
+	}
+	return a
+}
+
+func (a *aesGCM) Init(opts ...secrets.Option) error {
+	for _, o := range opts {
+		o(&a.options)
+	}
+	if len(a.options.Key) == 0 {
+		return errors.New("no secret key is defined")
+	}
+	var err error
+	a.cipher, err = crypto.NewAESGCM(a.options.Key)
+	return err
+}
+
+func (a *aesGCM) Options() secrets.Options {
+	return a.options
+}
+
+func (a *aesGCM) String() string {
+	return "aes-gcm"
+}
+
+func (a *aesGCM) Encrypt(in []byte, opts ...secrets.EncryptOption) ([]byte, error) {
+	if a.cipher == nil {
+		return nil, errors.New("secrets not initialised")
+	}
+	out, err := a.cipher.Encrypt(in)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't obtain a random nonce from crypto/rand")
+	}
+	return out, nil
+}
+
+func (a *aesGCM) Decrypt(in []byte, opts ...secrets.DecryptOption) ([]byte, error) {
+	if a.cipher == nil {
+		return nil, errors.New("secrets not initialised")
+	}
+	out, err := a.cipher.Decrypt(in)
+	if errors.Is(err, crypto.ErrCiphertext) {
+		return nil, errors.New("ciphertext too short")
+	}
+	if err != nil {
+		return nil, errors.New("decryption failed (is the key set correctly?)")
+	}
+	return out, nil
+}
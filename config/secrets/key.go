@@ -0,0 +1,53 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+)
+
+// KeyFromEnv 从环境变量读取密钥, 支持base64和hex编码
+// 密钥来自KMS时, 应用解密数据密钥后通过 Key 传入即可
+func KeyFromEnv(name string) ([]byte, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return nil, fmt.Errorf("secret key env %s not set", name)
+	}
+	return DecodeKey(v)
+}
+
+// DecodeKey 解码base64或hex编码的密钥
+func DecodeKey(v string) ([]byte, error) {
+	if b, err := hex.DecodeString(v); err == nil {
+		return b, nil
+	}
+	if b, err := base64.StdEncoding.DecodeString(v); err == nil {
+		return b, nil
+	}
+	return nil, fmt.Errorf("secret key must be hex or base64 encoded")
+}
+
+// EncryptString 加密并返回base64, 用于配置中的 ${enc:...}
+func EncryptString(s Secrets, plain string) (string, error) {
+	b, err := s.Encrypt([]byte(plain))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// Resolver 返回 ${enc:base64} 的解析方法, 通过 reader.RegisterResolver("enc", ...) 注册
+func Resolver(s Secrets) func(ref string) (string, error) {
+	return func(ref string) (string, error) {
+		b, err := base64.StdEncoding.DecodeString(ref)
+		if err != nil {
+			return "", err
+		}
+		out, err := s.Decrypt(b)
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	}
+}
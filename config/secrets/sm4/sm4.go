@@ -0,0 +1,69 @@
+// Package sm4 is a config/secrets implementation that uses SM4-GCM (GB/T 32907)
+// to do symmetric encryption / verification
+package sm4
+
+import (
+	"github.com/go-admin-team/go-admin-core/config/secrets"
+	"github.com/go-admin-team/go-admin-core/tools/crypto"
+	"github.com/pkg/errors"
+)
+
+type sm4GCM struct {
+	options secrets.Options
+	// cipher is shared with tools/crypto
+	cipher crypto.Cipher
+}
+
+// NewSecrets returns an SM4-GCM codec, the key must be 16 bytes long
+func NewSecrets(opts ...secrets.Option) secrets.Secrets {
+	s := &sm4GCM{}
+	for _, o := range opts {
+		o(&s.options)
+	}
+	return s
+}
+
+func (s *sm4GCM) Init(opts ...secrets.Option) error {
+	for _, o := range opts {
+		o(&s.options)
+	}
+	if len(s.options.Key) == 0 {
+		return errors.New("no secret key is defined")
+	}
+	var err error
+	s.cipher, err = crypto.NewSM4GCM(s.options.Key)
+	return err
+}
+
+func (s *sm4GCM) Options() secrets.Options {
+	return s.options
+}
+
+func (s *sm4GCM) String() string {
+	return "sm4-gcm"
+}
+
+func (s *sm4GCM) Encrypt(in []byte, opts ...secrets.EncryptOption) ([]byte, error) {
+	if s.cipher == nil {
+		return nil, errors.New("secrets not initialised")
+	}
+	out, err := s.cipher.Encrypt(in)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't obtain a random nonce from crypto/rand")
+	}
+	return out, nil
+}
+
+func (s *sm4GCM) Decrypt(in []byte, opts ...secrets.DecryptOption) ([]byte, error) {
+	if s.cipher == nil {
+		return nil, errors.New("secrets not initialised")
+	}
+	out, err := s.cipher.Decrypt(in)
+	if errors.Is(err, crypto.ErrCiphertext) {
+		return nil, errors.New("ciphertext too short")
+	}
+	if err != nil {
+		return nil, errors.New("decryption failed (is the key set correctly?)")
+	}
+	return out, nil
+}
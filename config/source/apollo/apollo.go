@@ -0,0 +1,214 @@
+// Package apollo is an apollo config center source
+package apollo
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+var (
+	DefaultAddress   = "http://127.0.0.1:8080"
+	DefaultAppID     = "go-admin"
+	DefaultCluster   = "default"
+	DefaultNamespace = "application"
+	// DefaultPullTimeout apollo服务端长轮询最长60s
+	DefaultPullTimeout = 60 * time.Second
+)
+
+type apollo struct {
+	address   string
+	appID     string
+	cluster   string
+	namespace string
+	secret    string
+	client    *http.Client
+	opts      source.Options
+}
+
+type configResponse struct {
+	Configurations map[string]string `json:"configurations"`
+	ReleaseKey     string            `json:"releaseKey"`
+}
+
+func (a *apollo) Read() (*source.ChangeSet, error) {
+	return a.get(context.Background())
+}
+
+func (a *apollo) get(ctx context.Context) (*source.ChangeSet, error) {
+	path := fmt.Sprintf("/configs/%s/%s/%s", url.PathEscape(a.appID), url.PathEscape(a.cluster), url.PathEscape(a.namespace))
+	req, err := a.request(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("apollo: read %s/%s failed, status %d", a.appID, a.namespace, resp.StatusCode)
+	}
+	var rsp configResponse
+	if err = json.NewDecoder(resp.Body).Decode(&rsp); err != nil {
+		return nil, err
+	}
+
+	cs := &source.ChangeSet{
+		Source:    a.String(),
+		Timestamp: time.Now(),
+	}
+	if f := format(a.namespace); f != "" {
+		// 非properties的namespace整份内容放在content中
+		cs.Format = f
+		cs.Data = []byte(rsp.Configurations["content"])
+	} else {
+		cs.Format = "json"
+		cs.Data, err = json.Marshal(nest(rsp.Configurations))
+		if err != nil {
+			return nil, err
+		}
+	}
+	cs.Checksum = cs.Sum()
+	return cs, nil
+}
+
+// notify 长轮询, 返回最新的notificationId
+func (a *apollo) notify(ctx context.Context, id int64) (int64, error) {
+	n, _ := json.Marshal([]map[string]interface{}{{
+		"namespaceName":  a.namespace,
+		"notificationId": id,
+	}})
+	q := url.Values{}
+	q.Set("appId", a.appID)
+	q.Set("cluster", a.cluster)
+	q.Set("notifications", string(n))
+	req, err := a.request(ctx, "/notifications/v2?"+q.Encode())
+	if err != nil {
+		return id, err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return id, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return id, nil
+	case http.StatusOK:
+	default:
+		return id, fmt.Errorf("apollo: notifications failed, status %d", resp.StatusCode)
+	}
+	var rsp []struct {
+		NamespaceName  string `json:"namespaceName"`
+		NotificationID int64  `json:"notificationId"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&rsp); err != nil {
+		return id, err
+	}
+	for _, r := range rsp {
+		if r.NamespaceName == a.namespace {
+			return r.NotificationID, nil
+		}
+	}
+	return id, nil
+}
+
+func (a *apollo) request(ctx context.Context, path string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(a.address, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if a.secret != "" {
+		// apollo访问密钥签名: base64(hmac-sha1(timestamp + "\n" + pathWithQuery))
+		ts := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		mac := hmac.New(sha1.New, []byte(a.secret))
+		mac.Write([]byte(ts + "\n" + path))
+		req.Header.Set("Authorization", "Apollo "+a.appID+":"+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+		req.Header.Set("Timestamp", ts)
+	}
+	return req, nil
+}
+
+func (a *apollo) String() string {
+	return "apollo"
+}
+
+func (a *apollo) Watch() (source.Watcher, error) {
+	return newWatcher(a)
+}
+
+func (a *apollo) Write(cs *source.ChangeSet) error {
+	return nil
+}
+
+// format 返回namespace后缀对应的格式, properties返回空
+func format(namespace string) string {
+	i := strings.LastIndex(namespace, ".")
+	if i < 0 {
+		return ""
+	}
+	switch ext := namespace[i+1:]; ext {
+	case "json", "yaml", "yml", "xml", "toml":
+		return ext
+	}
+	return ""
+}
+
+// nest 将properties的点分key转换为嵌套结构, a.b=1 => {"a":{"b":"1"}}
+func nest(kv map[string]string) map[string]interface{} {
+	out := make(map[string]interface{})
+	for k, v := range kv {
+		parts := strings.Split(k, ".")
+		m := out
+		for _, p := range parts[:len(parts)-1] {
+			next, ok := m[p].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				m[p] = next
+			}
+			m = next
+		}
+		m[parts[len(parts)-1]] = v
+	}
+	return out
+}
+
+// NewSource returns an apollo source
+func NewSource(opts ...source.Option) source.Source {
+	options := source.NewOptions(opts...)
+	a := &apollo{
+		address:   DefaultAddress,
+		appID:     DefaultAppID,
+		cluster:   DefaultCluster,
+		namespace: DefaultNamespace,
+		client:    &http.Client{Timeout: DefaultPullTimeout + 10*time.Second},
+		opts:      options,
+	}
+	if v, ok := options.Context.Value(addressKey{}).(string); ok {
+		a.address = v
+	}
+	if v, ok := options.Context.Value(appIDKey{}).(string); ok {
+		a.appID = v
+	}
+	if v, ok := options.Context.Value(clusterKey{}).(string); ok {
+		a.cluster = v
+	}
+	if v, ok := options.Context.Value(namespaceKey{}).(string); ok {
+		a.namespace = v
+	}
+	if v, ok := options.Context.Value(secretKey{}).(string); ok {
+		a.secret = v
+	}
+	return a
+}
@@ -0,0 +1,93 @@
+package apollo
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+func TestApollo(t *testing.T) {
+	var mux sync.Mutex
+	mode, notified := "dev", 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 校验访问密钥签名
+		mac := hmac.New(sha1.New, []byte("secret"))
+		mac.Write([]byte(r.Header.Get("Timestamp") + "\n" + r.URL.RequestURI()))
+		if r.Header.Get("Authorization") != "Apollo app:"+base64.StdEncoding.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mux.Lock()
+		defer mux.Unlock()
+		switch r.URL.Path {
+		case "/configs/app/c1/application":
+			_ = json.NewEncoder(w).Encode(configResponse{Configurations: map[string]string{
+				"settings.application.mode": mode,
+				"settings.application.port": "8000",
+			}})
+		case "/configs/app/c1/config.yaml":
+			_ = json.NewEncoder(w).Encode(configResponse{Configurations: map[string]string{"content": "mode: " + mode}})
+		case "/notifications/v2":
+			var n []map[string]interface{}
+			_ = json.Unmarshal([]byte(r.URL.Query().Get("notifications")), &n)
+			if len(n) != 1 || n[0]["namespaceName"] != "application" {
+				t.Errorf("notifications = %v", n)
+			}
+			// 第一次长轮询超时, 第二次返回新的通知id与变更后的配置
+			notified++
+			if notified == 1 {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			mode = "prod"
+			fmt.Fprint(w, `[{"namespaceName":"application","notificationId":2}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s := NewSource(WithAddress(srv.URL), WithAppID("app"), WithCluster("c1"), WithSecret("secret"))
+	cs, err := s.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cs.Format != "json" || string(cs.Data) != `{"settings":{"application":{"mode":"dev","port":"8000"}}}` {
+		t.Fatalf("unexpected changeset %s %s", cs.Format, cs.Data)
+	}
+
+	w, err := s.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, err = w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(cs.Data) != `{"settings":{"application":{"mode":"prod","port":"8000"}}}` || w.(*watcher).id != 2 {
+		t.Errorf("watch data = %s, id = %d", cs.Data, w.(*watcher).id)
+	}
+	_ = w.Stop()
+	if _, err = w.Next(); err != source.ErrWatcherStopped {
+		t.Errorf("after stop err = %v", err)
+	}
+
+	cs, err = NewSource(WithAddress(srv.URL), WithAppID("app"), WithCluster("c1"), WithNamespace("config.yaml"), WithSecret("secret")).Read()
+	if err != nil || cs.Format != "yaml" || string(cs.Data) != "mode: prod" {
+		t.Errorf("yaml namespace: %v %v", cs, err)
+	}
+	if _, err = NewSource(WithAddress(srv.URL), WithAppID("app"), WithCluster("c1"), WithSecret("wrong")).Read(); err == nil {
+		t.Error("expected error for wrong secret")
+	}
+	if _, err = NewSource(WithAddress(srv.URL), WithAppID("app"), WithCluster("missing"), WithSecret("secret")).Watch(); err == nil {
+		t.Error("expected error for missing namespace")
+	}
+}
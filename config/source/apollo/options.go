@@ -0,0 +1,47 @@
+package apollo
+
+import (
+	"context"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+type addressKey struct{}
+type appIDKey struct{}
+type clusterKey struct{}
+type namespaceKey struct{}
+type secretKey struct{}
+
+func withValue(k, v interface{}) source.Option {
+	return func(o *source.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
+
+// WithAddress sets the apollo config service address, e.g. http://127.0.0.1:8080
+func WithAddress(a string) source.Option {
+	return withValue(addressKey{}, a)
+}
+
+// WithAppID sets the app id
+func WithAppID(id string) source.Option {
+	return withValue(appIDKey{}, id)
+}
+
+// WithCluster sets the cluster, it works as the group selection of apollo
+func WithCluster(c string) source.Option {
+	return withValue(clusterKey{}, c)
+}
+
+// WithNamespace sets the namespace, e.g. application or config.yaml
+func WithNamespace(ns string) source.Option {
+	return withValue(namespaceKey{}, ns)
+}
+
+// WithSecret sets the access key secret of the app
+func WithSecret(s string) source.Option {
+	return withValue(secretKey{}, s)
+}
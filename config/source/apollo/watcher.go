@@ -0,0 +1,64 @@
+package apollo
+
+import (
+	"context"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+type watcher struct {
+	a      *apollo
+	id     int64
+	cs     *source.ChangeSet
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newWatcher(a *apollo) (source.Watcher, error) {
+	cs, err := a.get(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &watcher{
+		a:      a,
+		id:     -1,
+		cs:     cs,
+		ctx:    ctx,
+		cancel: cancel,
+	}, nil
+}
+
+func (w *watcher) Next() (*source.ChangeSet, error) {
+	for {
+		id, err := w.a.notify(w.ctx, w.id)
+		if err != nil {
+			return nil, w.err(err)
+		}
+		if id == w.id {
+			continue
+		}
+		w.id = id
+		cs, err := w.a.get(w.ctx)
+		if err != nil {
+			return nil, w.err(err)
+		}
+		if cs.Checksum == w.cs.Checksum {
+			continue
+		}
+		w.cs = cs
+		return cs, nil
+	}
+}
+
+func (w *watcher) err(err error) error {
+	if w.ctx.Err() != nil {
+		return source.ErrWatcherStopped
+	}
+	return err
+}
+
+func (w *watcher) Stop() error {
+	w.cancel()
+	return nil
+}
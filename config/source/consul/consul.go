@@ -0,0 +1,127 @@
+// Package consul is a consul kv source, the whole config document is stored in one key
+package consul
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+var (
+	DefaultAddress  = "http://127.0.0.1:8500"
+	DefaultKey      = "go-admin/config"
+	DefaultWaitTime = 5 * time.Minute
+)
+
+type consul struct {
+	address    string
+	key        string
+	token      string
+	datacenter string
+	namespace  string
+	waitTime   time.Duration
+	client     *http.Client
+	opts       source.Options
+}
+
+func (c *consul) Read() (*source.ChangeSet, error) {
+	cs, _, err := c.get(context.Background(), 0)
+	return cs, err
+}
+
+// get reads the key, a positive index turns the request into a blocking query
+func (c *consul) get(ctx context.Context, index uint64) (*source.ChangeSet, uint64, error) {
+	q := url.Values{}
+	q.Set("raw", "")
+	if c.datacenter != "" {
+		q.Set("dc", c.datacenter)
+	}
+	if c.namespace != "" {
+		q.Set("ns", c.namespace)
+	}
+	if index > 0 {
+		q.Set("index", strconv.FormatUint(index, 10))
+		q.Set("wait", fmt.Sprintf("%ds", int(c.waitTime.Seconds())))
+	}
+	u := fmt.Sprintf("%s/v1/kv/%s?%s", strings.TrimRight(c.address, "/"), strings.TrimLeft(c.key, "/"), q.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if c.token != "" {
+		req.Header.Set("X-Consul-Token", c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("consul: read key %s failed, status %d", c.key, resp.StatusCode)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	next, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+
+	cs := &source.ChangeSet{
+		Format:    c.opts.Encoder.String(),
+		Source:    c.String(),
+		Timestamp: time.Now(),
+		Data:      b,
+	}
+	cs.Checksum = cs.Sum()
+	return cs, next, nil
+}
+
+func (c *consul) String() string {
+	return "consul"
+}
+
+func (c *consul) Watch() (source.Watcher, error) {
+	return newWatcher(c)
+}
+
+func (c *consul) Write(cs *source.ChangeSet) error {
+	return nil
+}
+
+// NewSource returns a consul kv source
+func NewSource(opts ...source.Option) source.Source {
+	options := source.NewOptions(opts...)
+	c := &consul{
+		address:  DefaultAddress,
+		key:      DefaultKey,
+		waitTime: DefaultWaitTime,
+		opts:     options,
+	}
+	if v, ok := options.Context.Value(addressKey{}).(string); ok {
+		c.address = v
+	}
+	if v, ok := options.Context.Value(keyKey{}).(string); ok {
+		c.key = v
+	}
+	if v, ok := options.Context.Value(tokenKey{}).(string); ok {
+		c.token = v
+	}
+	if v, ok := options.Context.Value(datacenterKey{}).(string); ok {
+		c.datacenter = v
+	}
+	if v, ok := options.Context.Value(namespaceKey{}).(string); ok {
+		c.namespace = v
+	}
+	if v, ok := options.Context.Value(waitTimeKey{}).(time.Duration); ok && v > 0 {
+		c.waitTime = v
+	}
+	// blocking queries are held by consul for up to wait + wait/16
+	c.client = &http.Client{Timeout: c.waitTime + c.waitTime/16 + 10*time.Second}
+	return c
+}
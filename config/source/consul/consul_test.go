@@ -0,0 +1,77 @@
+package consul
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+func TestConsul(t *testing.T) {
+	var mux sync.Mutex
+	value, index := `{"mode":"dev"}`, 10
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/v1/kv/app/config" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("X-Consul-Token") != "tk" || q.Get("dc") != "dc1" || !q.Has("raw") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		mux.Lock()
+		defer mux.Unlock()
+		if q.Get("index") != "" {
+			if q.Get("wait") != "1s" {
+				t.Errorf("wait = %s", q.Get("wait"))
+			}
+			// 第一次阻塞查询超时返回相同内容, 第二次内容变更
+			if q.Get("index") == "10" {
+				index = 11
+			} else {
+				value, index = `{"mode":"prod"}`, 12
+			}
+		}
+		w.Header().Set("X-Consul-Index", fmt.Sprint(index))
+		fmt.Fprint(w, value)
+	}))
+	defer srv.Close()
+
+	opts := []source.Option{WithAddress(srv.URL), WithKey("/app/config"), WithToken("tk"), WithDatacenter("dc1"), WithWaitTime(time.Second)}
+	s := NewSource(opts...)
+	cs, err := s.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(cs.Data) != `{"mode":"dev"}` || cs.Format != "json" {
+		t.Fatalf("unexpected changeset %s %s", cs.Format, cs.Data)
+	}
+
+	w, err := s.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, err = w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(cs.Data) != `{"mode":"prod"}` || w.(*watcher).index != 12 {
+		t.Errorf("watch data = %s, index = %d", cs.Data, w.(*watcher).index)
+	}
+	_ = w.Stop()
+	if _, err = w.Next(); err != source.ErrWatcherStopped {
+		t.Errorf("after stop err = %v", err)
+	}
+
+	if _, err = NewSource(WithAddress(srv.URL), WithKey("missing")).Read(); err == nil {
+		t.Error("expected error for missing key")
+	}
+	if _, err = NewSource(WithAddress(srv.URL), WithKey("app/config")).Watch(); err == nil {
+		t.Error("expected error without token")
+	}
+}
@@ -0,0 +1,54 @@
+package consul
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+type addressKey struct{}
+type keyKey struct{}
+type tokenKey struct{}
+type datacenterKey struct{}
+type namespaceKey struct{}
+type waitTimeKey struct{}
+
+func withValue(k, v interface{}) source.Option {
+	return func(o *source.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
+
+// WithAddress sets the consul http address, e.g. http://127.0.0.1:8500
+func WithAddress(a string) source.Option {
+	return withValue(addressKey{}, a)
+}
+
+// WithKey sets the kv key holding the whole config document
+func WithKey(k string) source.Option {
+	return withValue(keyKey{}, k)
+}
+
+// WithToken sets the acl token
+func WithToken(t string) source.Option {
+	return withValue(tokenKey{}, t)
+}
+
+// WithDatacenter sets the datacenter
+func WithDatacenter(dc string) source.Option {
+	return withValue(datacenterKey{}, dc)
+}
+
+// WithNamespace sets the enterprise namespace
+func WithNamespace(ns string) source.Option {
+	return withValue(namespaceKey{}, ns)
+}
+
+// WithWaitTime sets the max duration of a blocking query
+func WithWaitTime(d time.Duration) source.Option {
+	return withValue(waitTimeKey{}, d)
+}
@@ -0,0 +1,67 @@
+package consul
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+type watcher struct {
+	c      *consul
+	index  uint64
+	sum    string
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newWatcher(c *consul) (source.Watcher, error) {
+	cs, index, err := c.get(context.Background(), 0)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &watcher{
+		c:      c,
+		index:  index,
+		sum:    cs.Checksum,
+		ctx:    ctx,
+		cancel: cancel,
+	}, nil
+}
+
+// Next blocks until the key changes
+func (w *watcher) Next() (*source.ChangeSet, error) {
+	for {
+		select {
+		case <-w.ctx.Done():
+			return nil, source.ErrWatcherStopped
+		default:
+		}
+		cs, index, err := w.c.get(w.ctx, w.index)
+		if err != nil {
+			if w.ctx.Err() != nil {
+				return nil, source.ErrWatcherStopped
+			}
+			return nil, err
+		}
+		// the index may go backwards after a consul restore, reset it
+		if index < w.index {
+			index = 0
+		}
+		w.index = index
+		if cs.Checksum == w.sum {
+			if index == 0 {
+				time.Sleep(time.Second)
+			}
+			continue
+		}
+		w.sum = cs.Checksum
+		return cs, nil
+	}
+}
+
+func (w *watcher) Stop() error {
+	w.cancel()
+	return nil
+}
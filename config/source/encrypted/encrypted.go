@@ -0,0 +1,106 @@
+// Package encrypted wraps a source whose data is encrypted at rest, the data is
+// decrypted transparently when read
+package encrypted
+
+import (
+	"bytes"
+	"encoding/base64"
+
+	"github.com/go-admin-team/go-admin-core/config/secrets"
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+// Ext 加密文件的后缀, 如 settings.yml.enc, 此时格式取自 source.WithEncoder
+const Ext = "enc"
+
+type encrypted struct {
+	s    source.Source
+	sec  secrets.Secrets
+	opts source.Options
+}
+
+func (e *encrypted) Read() (*source.ChangeSet, error) {
+	cs, err := e.s.Read()
+	if err != nil {
+		return nil, err
+	}
+	return e.decrypt(cs)
+}
+
+func (e *encrypted) decrypt(cs *source.ChangeSet) (*source.ChangeSet, error) {
+	data, err := Decrypt(e.sec, cs.Data)
+	if err != nil {
+		return nil, err
+	}
+	out := &source.ChangeSet{
+		Data:      data,
+		Format:    cs.Format,
+		Source:    e.String(),
+		Timestamp: cs.Timestamp,
+	}
+	if out.Format == Ext {
+		out.Format = e.opts.Encoder.String()
+	}
+	out.Checksum = out.Sum()
+	return out, nil
+}
+
+func (e *encrypted) String() string {
+	return "encrypted(" + e.s.String() + ")"
+}
+
+func (e *encrypted) Watch() (source.Watcher, error) {
+	w, err := e.s.Watch()
+	if err != nil {
+		return nil, err
+	}
+	return &watcher{e: e, w: w}, nil
+}
+
+func (e *encrypted) Write(cs *source.ChangeSet) error {
+	return nil
+}
+
+type watcher struct {
+	e *encrypted
+	w source.Watcher
+}
+
+func (w *watcher) Next() (*source.ChangeSet, error) {
+	cs, err := w.w.Next()
+	if err != nil {
+		return nil, err
+	}
+	return w.e.decrypt(cs)
+}
+
+func (w *watcher) Stop() error {
+	return w.w.Stop()
+}
+
+// Encrypt 加密配置内容, 返回base64文本, 写入文件后由 NewSource 读取
+func Encrypt(sec secrets.Secrets, data []byte) ([]byte, error) {
+	b, err := sec.Encrypt(data)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, base64.StdEncoding.EncodedLen(len(b)))
+	base64.StdEncoding.Encode(out, b)
+	return out, nil
+}
+
+// Decrypt 解密配置内容, 支持base64文本和原始密文
+func Decrypt(sec secrets.Secrets, data []byte) ([]byte, error) {
+	text := bytes.TrimSpace(data)
+	b := make([]byte, base64.StdEncoding.DecodedLen(len(text)))
+	n, err := base64.StdEncoding.Decode(b, text)
+	if err != nil {
+		return sec.Decrypt(data)
+	}
+	return sec.Decrypt(b[:n])
+}
+
+// NewSource 包装加密的配置源, sec需已Init
+func NewSource(s source.Source, sec secrets.Secrets, opts ...source.Option) source.Source {
+	return &encrypted{s: s, sec: sec, opts: source.NewOptions(opts...)}
+}
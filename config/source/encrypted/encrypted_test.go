@@ -0,0 +1,53 @@
+package encrypted
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/config/encoder/yaml"
+	"github.com/go-admin-team/go-admin-core/config/reader"
+	"github.com/go-admin-team/go-admin-core/config/secrets"
+	"github.com/go-admin-team/go-admin-core/config/secrets/aes"
+	"github.com/go-admin-team/go-admin-core/config/secrets/sm4"
+	"github.com/go-admin-team/go-admin-core/config/source"
+	"github.com/go-admin-team/go-admin-core/config/source/memory"
+)
+
+func TestEncryptedSource(t *testing.T) {
+	plain := []byte("settings:\n  application:\n    mode: prod\n")
+	for _, sec := range []secrets.Secrets{aes.NewSecrets(), sm4.NewSecrets()} {
+		key := make([]byte, 16)
+		_, _ = rand.Read(key)
+		if err := sec.Init(secrets.Key(key)); err != nil {
+			t.Fatal(err)
+		}
+		data, err := Encrypt(sec, plain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := NewSource(memory.NewSource(memory.WithChangeSet(&source.ChangeSet{
+			Data:   data,
+			Format: Ext,
+		})), sec, source.WithEncoder(yaml.NewEncoder()))
+		cs, err := s.Read()
+		if err != nil {
+			t.Fatal(sec.String(), err)
+		}
+		if string(cs.Data) != string(plain) || cs.Format != "yaml" {
+			t.Fatalf("%s: unexpected changeset %s %s", sec.String(), cs.Format, cs.Data)
+		}
+
+		value, err := secrets.EncryptString(sec, `p"ss`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader.RegisterResolver("enc", secrets.Resolver(sec))
+		res, err := reader.ReplaceEnvVars([]byte(`{"password": "${enc:` + value + `}"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(res) != `{"password": "p\"ss"}` {
+			t.Fatalf("%s: unexpected field %s", sec.String(), res)
+		}
+	}
+}
@@ -17,6 +17,7 @@ var (
 type env struct {
 	prefixes         []string
 	strippedPrefixes []string
+	separator        string
 	opts             source.Options
 }
 
@@ -44,7 +45,11 @@ func (e *env) Read() (*source.ChangeSet, error) {
 
 		pair := strings.SplitN(env, "=", 2)
 		value := pair[1]
-		keys := strings.Split(strings.ToLower(pair[0]), "_")
+		sep := "_"
+		if e.separator != "" && strings.Contains(pair[0], e.separator) {
+			sep = e.separator
+		}
+		keys := strings.Split(strings.ToLower(pair[0]), sep)
 		reverse(keys)
 
 		tmp := make(map[string]interface{})
@@ -142,5 +147,6 @@ func NewSource(opts ...source.Option) source.Source {
 	if len(sp) > 0 || len(pre) > 0 {
 		pre = append(pre, DefaultPrefixes...)
 	}
-	return &env{prefixes: pre, strippedPrefixes: sp, opts: options}
+	sep, _ := options.Context.Value(separatorKey{}).(string)
+	return &env{prefixes: pre, strippedPrefixes: sp, separator: sep, opts: options}
 }
@@ -110,3 +110,31 @@ func containsKey(m map[string]interface{}, s string) bool {
 	}
 	return false
 }
+
+func TestEnv_Separator(t *testing.T) {
+	os.Setenv("SEP_TEST_CACHE__REDIS__POOL_SIZE", "20")
+	os.Setenv("SEP_TEST_APPLICATION_PORT", "8000")
+	defer os.Unsetenv("SEP_TEST_CACHE__REDIS__POOL_SIZE")
+	defer os.Unsetenv("SEP_TEST_APPLICATION_PORT")
+
+	cs, err := NewSource(WithStrippedPrefix("SEP_TEST"), WithSeparator("__")).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual struct {
+		Cache struct {
+			Redis struct {
+				PoolSize int `json:"pool_size"`
+			}
+		}
+		Application struct {
+			Port int
+		}
+	}
+	if err = json.Unmarshal(cs.Data, &actual); err != nil {
+		t.Fatal(err)
+	}
+	if actual.Cache.Redis.PoolSize != 20 || actual.Application.Port != 8000 {
+		t.Errorf("unexpected data %s", cs.Data)
+	}
+}
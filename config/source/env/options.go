@@ -10,6 +10,7 @@ import (
 
 type strippedPrefixKey struct{}
 type prefixKey struct{}
+type separatorKey struct{}
 
 // WithStrippedPrefix sets the environment variable prefixes to scope to.
 // These prefixes will be removed from the actual config entries.
@@ -34,6 +35,21 @@ func WithPrefix(p ...string) source.Option {
 	}
 }
 
+// WithSeparator sets an extra level separator, e.g. "__". Variables containing it are split
+// on it only, so single underscores stay inside the key:
+//
+//	SETTINGS__CACHE__REDIS__POOL_SIZE=20 => settings.cache.redis.pool_size
+//
+// Variables without it are still split on every underscore.
+func WithSeparator(sep string) source.Option {
+	return func(o *source.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, separatorKey{}, sep)
+	}
+}
+
 func appendUnderscore(prefixes []string) []string {
 	//nolint:prealloc
 	var result []string
@@ -0,0 +1,175 @@
+// Package etcd is an etcd v3 source, it talks to the grpc-gateway json api of etcd
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+var (
+	DefaultAddress = "http://127.0.0.1:2379"
+	DefaultKey     = "/go-admin/config"
+)
+
+type etcd struct {
+	address string
+	key     string
+	auth    *authCreds
+	client  *http.Client
+	opts    source.Options
+}
+
+type keyValue struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	ModRevision string `json:"mod_revision"`
+}
+
+type rangeResponse struct {
+	Header struct {
+		Revision string `json:"revision"`
+	} `json:"header"`
+	Kvs []keyValue `json:"kvs"`
+}
+
+func (e *etcd) Read() (*source.ChangeSet, error) {
+	cs, _, err := e.get(context.Background())
+	return cs, err
+}
+
+// get reads the key and returns the store revision of the response
+func (e *etcd) get(ctx context.Context) (*source.ChangeSet, int64, error) {
+	var rsp rangeResponse
+	err := e.post(ctx, "/v3/kv/range", map[string]string{
+		"key": base64.StdEncoding.EncodeToString([]byte(e.key)),
+	}, &rsp)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(rsp.Kvs) == 0 {
+		return nil, 0, fmt.Errorf("etcd: key %s not found", e.key)
+	}
+	revision, _ := strconv.ParseInt(rsp.Header.Revision, 10, 64)
+	cs, err := e.changeSet(rsp.Kvs[0].Value)
+	return cs, revision, err
+}
+
+func (e *etcd) changeSet(value string) (*source.ChangeSet, error) {
+	b, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	cs := &source.ChangeSet{
+		Format:    e.opts.Encoder.String(),
+		Source:    e.String(),
+		Timestamp: time.Now(),
+		Data:      b,
+	}
+	cs.Checksum = cs.Sum()
+	return cs, nil
+}
+
+func (e *etcd) request(ctx context.Context, path string, body interface{}) (*http.Request, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(e.address, "/")+path, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.auth != nil {
+		token, err := e.token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", token)
+	}
+	return req, nil
+}
+
+func (e *etcd) post(ctx context.Context, path string, body, out interface{}) error {
+	req, err := e.request(ctx, path, body)
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("etcd: %s failed, status %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// token 每次请求前重新认证, 避免token过期
+func (e *etcd) token(ctx context.Context) (string, error) {
+	b, _ := json.Marshal(map[string]string{
+		"name":     e.auth.Username,
+		"password": e.auth.Password,
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(e.address, "/")+"/v3/auth/authenticate", bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("etcd: authenticate failed, status %d", resp.StatusCode)
+	}
+	var rsp struct {
+		Token string `json:"token"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&rsp); err != nil {
+		return "", err
+	}
+	return rsp.Token, nil
+}
+
+func (e *etcd) String() string {
+	return "etcd"
+}
+
+func (e *etcd) Watch() (source.Watcher, error) {
+	return newWatcher(e)
+}
+
+func (e *etcd) Write(cs *source.ChangeSet) error {
+	return nil
+}
+
+// NewSource returns an etcd source
+func NewSource(opts ...source.Option) source.Source {
+	options := source.NewOptions(opts...)
+	e := &etcd{
+		address: DefaultAddress,
+		key:     DefaultKey,
+		client:  &http.Client{},
+		opts:    options,
+	}
+	if v, ok := options.Context.Value(addressKey{}).(string); ok {
+		e.address = v
+	}
+	if v, ok := options.Context.Value(keyKey{}).(string); ok {
+		e.key = v
+	}
+	if v, ok := options.Context.Value(authKey{}).(*authCreds); ok {
+		e.auth = v
+	}
+	return e
+}
@@ -0,0 +1,100 @@
+package etcd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+func b64(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func TestEtcd(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/auth/authenticate", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req["name"] != "root" || req["password"] != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"token":"tk"}`)
+	})
+	mux.HandleFunc("/v3/kv/range", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if r.Header.Get("Authorization") != "tk" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if req["key"] != b64("/app/config") {
+			fmt.Fprint(w, `{"header":{"revision":"5"}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"header":{"revision":"5"},"kvs":[{"key":"%s","value":"%s","mod_revision":"3"}]}`,
+			req["key"], b64(`{"mode":"dev"}`))
+	})
+	mux.HandleFunc("/v3/watch", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			CreateRequest struct {
+				StartRevision string `json:"start_revision"`
+			} `json:"create_request"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.CreateRequest.StartRevision != "6" {
+			t.Errorf("start_revision = %s, want 6", req.CreateRequest.StartRevision)
+		}
+		// 创建确认、删除事件与修改事件
+		fmt.Fprint(w, `{"result":{"header":{"revision":"5"},"created":true}}`+"\n")
+		fmt.Fprint(w, `{"result":{"events":[{"type":"DELETE","kv":{"mod_revision":"6"}}]}}`+"\n")
+		fmt.Fprintf(w, `{"result":{"events":[{"kv":{"value":"%s","mod_revision":"7"}}]}}`+"\n", b64(`{"mode":"prod"}`))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	s := NewSource(WithAddress(srv.URL), WithKey("/app/config"), Auth("root", "secret"))
+	cs, err := s.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(cs.Data) != `{"mode":"dev"}` || cs.Format != "json" || cs.Checksum == "" {
+		t.Fatalf("unexpected changeset %s %s %s", cs.Format, cs.Data, cs.Checksum)
+	}
+
+	w, err := s.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, err = w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(cs.Data) != `{"mode":"prod"}` {
+		t.Errorf("watch data = %s", cs.Data)
+	}
+	if got := w.(*watcher).revision; got != 7 {
+		t.Errorf("revision = %d, want 7", got)
+	}
+	_ = w.Stop()
+	if _, err = w.Next(); err != source.ErrWatcherStopped {
+		t.Errorf("after stop err = %v", err)
+	}
+
+	if _, err = NewSource(WithAddress(srv.URL), WithKey("/missing"), Auth("root", "secret")).Read(); err == nil {
+		t.Error("expected error for missing key")
+	}
+	if _, err = NewSource(WithAddress(srv.URL), WithKey("/app/config"), Auth("root", "wrong")).Read(); err == nil {
+		t.Error("expected error for failed authentication")
+	}
+	if _, err = NewSource(WithAddress(srv.URL), WithKey("/app/config")).Watch(); err == nil {
+		t.Error("expected error without token")
+	}
+}
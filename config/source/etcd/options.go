@@ -0,0 +1,40 @@
+package etcd
+
+import (
+	"context"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+type addressKey struct{}
+type keyKey struct{}
+type authKey struct{}
+
+type authCreds struct {
+	Username string
+	Password string
+}
+
+func withValue(k, v interface{}) source.Option {
+	return func(o *source.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
+
+// WithAddress sets the etcd grpc-gateway address, e.g. http://127.0.0.1:2379
+func WithAddress(a string) source.Option {
+	return withValue(addressKey{}, a)
+}
+
+// WithKey sets the key holding the whole config document
+func WithKey(k string) source.Option {
+	return withValue(keyKey{}, k)
+}
+
+// Auth sets the username and password of etcd rbac
+func Auth(username, password string) source.Option {
+	return withValue(authKey{}, &authCreds{Username: username, Password: password})
+}
@@ -0,0 +1,128 @@
+package etcd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+type watchResponse struct {
+	Result struct {
+		Header struct {
+			Revision string `json:"revision"`
+		} `json:"header"`
+		Events []struct {
+			Type string   `json:"type"`
+			Kv   keyValue `json:"kv"`
+		} `json:"events"`
+	} `json:"result"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type watcher struct {
+	e        *etcd
+	revision int64
+	resp     *http.Response
+	dec      *json.Decoder
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func newWatcher(e *etcd) (source.Watcher, error) {
+	_, revision, err := e.get(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &watcher{
+		e:        e,
+		revision: revision,
+		ctx:      ctx,
+		cancel:   cancel,
+	}, nil
+}
+
+// open starts a watch stream from the revision after the last seen one
+func (w *watcher) open() error {
+	req, err := w.e.request(w.ctx, "/v3/watch", map[string]interface{}{
+		"create_request": map[string]interface{}{
+			"key":            base64.StdEncoding.EncodeToString([]byte(w.e.key)),
+			"start_revision": strconv.FormatInt(w.revision+1, 10),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := w.e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return fmt.Errorf("etcd: watch failed, status %d", resp.StatusCode)
+	}
+	w.resp = resp
+	w.dec = json.NewDecoder(resp.Body)
+	return nil
+}
+
+func (w *watcher) Next() (*source.ChangeSet, error) {
+	for {
+		if w.ctx.Err() != nil {
+			return nil, source.ErrWatcherStopped
+		}
+		if w.dec == nil {
+			if err := w.open(); err != nil {
+				return nil, w.err(err)
+			}
+		}
+		var rsp watchResponse
+		if err := w.dec.Decode(&rsp); err != nil {
+			w.close()
+			return nil, w.err(err)
+		}
+		if rsp.Error != nil {
+			w.close()
+			return nil, fmt.Errorf("etcd: watch error %s", rsp.Error.Message)
+		}
+		if len(rsp.Result.Events) == 0 {
+			continue
+		}
+		ev := rsp.Result.Events[len(rsp.Result.Events)-1]
+		if revision, err := strconv.ParseInt(ev.Kv.ModRevision, 10, 64); err == nil {
+			w.revision = revision
+		}
+		// 删除事件忽略, 保留最后一次的配置
+		if ev.Type == "DELETE" {
+			continue
+		}
+		return w.e.changeSet(ev.Kv.Value)
+	}
+}
+
+func (w *watcher) err(err error) error {
+	if w.ctx.Err() != nil {
+		return source.ErrWatcherStopped
+	}
+	return err
+}
+
+func (w *watcher) close() {
+	if w.resp != nil {
+		w.resp.Body.Close()
+	}
+	w.resp = nil
+	w.dec = nil
+}
+
+func (w *watcher) Stop() error {
+	w.cancel()
+	return nil
+}
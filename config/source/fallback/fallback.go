@@ -0,0 +1,108 @@
+// Package fallback wraps a remote source and keeps a local copy of the last
+// successful read, the copy is served when the remote is unavailable
+package fallback
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+var (
+	DefaultPath = "config.remote.cache"
+)
+
+type fallback struct {
+	remote source.Source
+	path   string
+	opts   source.Options
+}
+
+// Read 读取远程配置成功则写入本地缓存, 失败则读取本地缓存
+func (f *fallback) Read() (*source.ChangeSet, error) {
+	cs, err := f.remote.Read()
+	if err == nil {
+		_ = f.save(cs)
+		return cs, nil
+	}
+	local, lerr := f.load()
+	if lerr != nil {
+		return nil, err
+	}
+	return local, nil
+}
+
+func (f *fallback) save(cs *source.ChangeSet) error {
+	b, err := json.Marshal(cs)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(f.path), os.ModePerm); err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	if err = os.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+func (f *fallback) load() (*source.ChangeSet, error) {
+	b, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	cs := &source.ChangeSet{}
+	if err = json.Unmarshal(b, cs); err != nil {
+		return nil, err
+	}
+	cs.Source = f.String()
+	return cs, nil
+}
+
+func (f *fallback) String() string {
+	return "fallback(" + f.remote.String() + ")"
+}
+
+// Watch 监听远程配置, 变更同时写入本地缓存; 远程不可用时loader会定时重试
+func (f *fallback) Watch() (source.Watcher, error) {
+	w, err := f.remote.Watch()
+	if err != nil {
+		return nil, err
+	}
+	return &watcher{f: f, w: w}, nil
+}
+
+func (f *fallback) Write(cs *source.ChangeSet) error {
+	return f.remote.Write(cs)
+}
+
+type watcher struct {
+	f *fallback
+	w source.Watcher
+}
+
+func (w *watcher) Next() (*source.ChangeSet, error) {
+	cs, err := w.w.Next()
+	if err != nil {
+		return nil, err
+	}
+	_ = w.f.save(cs)
+	return cs, nil
+}
+
+func (w *watcher) Stop() error {
+	return w.w.Stop()
+}
+
+// NewSource wraps the remote source with a local file fallback
+func NewSource(remote source.Source, opts ...source.Option) source.Source {
+	options := source.NewOptions(opts...)
+	path := DefaultPath
+	if p, ok := options.Context.Value(pathKey{}).(string); ok {
+		path = p
+	}
+	return &fallback{remote: remote, path: path, opts: options}
+}
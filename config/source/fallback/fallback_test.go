@@ -0,0 +1,50 @@
+package fallback
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+	"github.com/go-admin-team/go-admin-core/config/source/memory"
+)
+
+type brokenSource struct {
+	source.Source
+}
+
+func (b *brokenSource) String() string {
+	return "broken"
+}
+
+func (b *brokenSource) Read() (*source.ChangeSet, error) {
+	return nil, errors.New("remote unavailable")
+}
+
+func TestFallback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "remote.cache")
+	data := []byte(`{"settings":{"application":{"mode":"dev"}}}`)
+
+	s := NewSource(memory.NewSource(memory.WithJSON(data)), WithPath(path))
+	cs, err := s.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(cs.Data) != string(data) {
+		t.Fatalf("expected %s got %s", data, cs.Data)
+	}
+
+	s = NewSource(&brokenSource{}, WithPath(path))
+	cs, err = s.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(cs.Data) != string(data) || cs.Format != "json" {
+		t.Fatalf("unexpected fallback changeset %s %s", cs.Format, cs.Data)
+	}
+
+	s = NewSource(&brokenSource{}, WithPath(path+".missing"))
+	if _, err = s.Read(); err == nil {
+		t.Fatal("expected remote error without local copy")
+	}
+}
@@ -0,0 +1,19 @@
+package fallback
+
+import (
+	"context"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+type pathKey struct{}
+
+// WithPath sets the local file used to cache the last remote config
+func WithPath(p string) source.Option {
+	return func(o *source.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, pathKey{}, p)
+	}
+}
@@ -0,0 +1,208 @@
+// Package nacos is a nacos config center source
+package nacos
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+var (
+	DefaultAddress = "http://127.0.0.1:8848"
+	DefaultDataID  = "go-admin.yml"
+	DefaultGroup   = "DEFAULT_GROUP"
+	// DefaultPullTimeout 长轮询时间
+	DefaultPullTimeout = 30 * time.Second
+)
+
+type nacos struct {
+	address   string
+	dataID    string
+	group     string
+	namespace string
+	auth      *authCreds
+	client    *http.Client
+	opts      source.Options
+}
+
+func (n *nacos) Read() (*source.ChangeSet, error) {
+	return n.get(context.Background())
+}
+
+func (n *nacos) get(ctx context.Context) (*source.ChangeSet, error) {
+	q := n.query()
+	if err := n.login(ctx, q); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.url("/nacos/v1/cs/configs")+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nacos: read %s/%s failed, status %d", n.group, n.dataID, resp.StatusCode)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	cs := &source.ChangeSet{
+		Format:    format(n.dataID, n.opts.Encoder.String()),
+		Source:    n.String(),
+		Timestamp: time.Now(),
+		Data:      b,
+	}
+	cs.Checksum = cs.Sum()
+	return cs, nil
+}
+
+// listen 长轮询, 配置有变更返回true
+func (n *nacos) listen(ctx context.Context, data []byte) (bool, error) {
+	sum := md5.Sum(data)
+	parts := []string{n.dataID, n.group, hex.EncodeToString(sum[:])}
+	if n.namespace != "" {
+		parts = append(parts, n.namespace)
+	}
+	form := url.Values{}
+	form.Set("Listening-Configs", strings.Join(parts, "\x02")+"\x01")
+	q := url.Values{}
+	if err := n.login(ctx, q); err != nil {
+		return false, err
+	}
+	u := n.url("/nacos/v1/cs/configs/listener")
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Long-Pulling-Timeout", fmt.Sprintf("%d", DefaultPullTimeout.Milliseconds()))
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("nacos: listen %s/%s failed, status %d", n.group, n.dataID, resp.StatusCode)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	return len(strings.TrimSpace(string(b))) > 0, nil
+}
+
+// login 开启鉴权时获取accessToken
+func (n *nacos) login(ctx context.Context, q url.Values) error {
+	if n.auth == nil {
+		return nil
+	}
+	form := url.Values{}
+	form.Set("username", n.auth.Username)
+	form.Set("password", n.auth.Password)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url("/nacos/v1/auth/login"), strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("nacos: login failed, status %d", resp.StatusCode)
+	}
+	var rsp struct {
+		AccessToken string `json:"accessToken"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&rsp); err != nil {
+		return err
+	}
+	q.Set("accessToken", rsp.AccessToken)
+	return nil
+}
+
+func (n *nacos) query() url.Values {
+	q := url.Values{}
+	q.Set("dataId", n.dataID)
+	q.Set("group", n.group)
+	if n.namespace != "" {
+		q.Set("tenant", n.namespace)
+	}
+	return q
+}
+
+func (n *nacos) url(path string) string {
+	return strings.TrimRight(n.address, "/") + path
+}
+
+func (n *nacos) String() string {
+	return "nacos"
+}
+
+func (n *nacos) Watch() (source.Watcher, error) {
+	return newWatcher(n)
+}
+
+func (n *nacos) Write(cs *source.ChangeSet) error {
+	return nil
+}
+
+// format 根据dataId后缀确定格式, yml按yaml处理
+func format(dataID, def string) string {
+	i := strings.LastIndex(dataID, ".")
+	if i < 0 {
+		return def
+	}
+	switch ext := dataID[i+1:]; ext {
+	case "yml":
+		return "yaml"
+	case "json", "yaml", "toml", "xml":
+		return ext
+	}
+	return def
+}
+
+// NewSource returns a nacos source
+func NewSource(opts ...source.Option) source.Source {
+	options := source.NewOptions(opts...)
+	n := &nacos{
+		address: DefaultAddress,
+		dataID:  DefaultDataID,
+		group:   DefaultGroup,
+		client:  &http.Client{Timeout: DefaultPullTimeout + 10*time.Second},
+		opts:    options,
+	}
+	if v, ok := options.Context.Value(addressKey{}).(string); ok {
+		n.address = v
+	}
+	if v, ok := options.Context.Value(dataIDKey{}).(string); ok {
+		n.dataID = v
+	}
+	if v, ok := options.Context.Value(groupKey{}).(string); ok {
+		n.group = v
+	}
+	if v, ok := options.Context.Value(namespaceKey{}).(string); ok {
+		n.namespace = v
+	}
+	if v, ok := options.Context.Value(authKey{}).(*authCreds); ok {
+		n.auth = v
+	}
+	return n
+}
@@ -0,0 +1,99 @@
+package nacos
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+func TestNacos(t *testing.T) {
+	var mux sync.Mutex
+	content, listens := "mode: dev\n", 0
+	handler := http.NewServeMux()
+	handler.HandleFunc("/nacos/v1/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("username") != "nacos" || r.FormValue("password") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `{"accessToken":"tk"}`)
+	})
+	handler.HandleFunc("/nacos/v1/cs/configs", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("accessToken") != "tk" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if q.Get("dataId") != "app.yml" || q.Get("group") != "G" || q.Get("tenant") != "ns" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mux.Lock()
+		defer mux.Unlock()
+		fmt.Fprint(w, content)
+	})
+	handler.HandleFunc("/nacos/v1/cs/configs/listener", func(w http.ResponseWriter, r *http.Request) {
+		mux.Lock()
+		defer mux.Unlock()
+		sum := md5.Sum([]byte(content))
+		want := strings.Join([]string{"app.yml", "G", hex.EncodeToString(sum[:]), "ns"}, "\x02") + "\x01"
+		if r.FormValue("Listening-Configs") != want {
+			t.Errorf("Listening-Configs = %q, want %q", r.FormValue("Listening-Configs"), want)
+		}
+		// 第一次长轮询超时无变更, 第二次返回变更
+		listens++
+		if listens > 1 {
+			content = "mode: prod\n"
+			fmt.Fprint(w, "app.yml%02G%02ns%01")
+		}
+	})
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	opts := []source.Option{WithAddress(srv.URL), WithDataID("app.yml"), WithGroup("G"), WithNamespace("ns"), Auth("nacos", "secret")}
+	s := NewSource(opts...)
+	cs, err := s.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(cs.Data) != "mode: dev\n" || cs.Format != "yaml" {
+		t.Fatalf("unexpected changeset %s %s", cs.Format, cs.Data)
+	}
+
+	w, err := s.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, err = w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(cs.Data) != "mode: prod\n" || listens != 2 {
+		t.Errorf("watch data = %s, listens = %d", cs.Data, listens)
+	}
+	_ = w.Stop()
+	if _, err = w.Next(); err != source.ErrWatcherStopped {
+		t.Errorf("after stop err = %v", err)
+	}
+
+	if _, err = NewSource(WithAddress(srv.URL), WithDataID("other.yml"), Auth("nacos", "secret")).Read(); err == nil {
+		t.Error("expected error for missing config")
+	}
+	if _, err = NewSource(append(opts, Auth("nacos", "wrong"))...).Read(); err == nil {
+		t.Error("expected error for failed login")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for id, want := range map[string]string{"a.yml": "yaml", "a.json": "json", "a.properties": "json", "a": "json"} {
+		if got := format(id, "json"); got != want {
+			t.Errorf("format(%s) = %s, want %s", id, got, want)
+		}
+	}
+}
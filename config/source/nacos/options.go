@@ -0,0 +1,52 @@
+package nacos
+
+import (
+	"context"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+type addressKey struct{}
+type dataIDKey struct{}
+type groupKey struct{}
+type namespaceKey struct{}
+type authKey struct{}
+
+type authCreds struct {
+	Username string
+	Password string
+}
+
+func withValue(k, v interface{}) source.Option {
+	return func(o *source.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
+
+// WithAddress sets the nacos server address, e.g. http://127.0.0.1:8848
+func WithAddress(a string) source.Option {
+	return withValue(addressKey{}, a)
+}
+
+// WithDataID sets the data id of the config
+func WithDataID(id string) source.Option {
+	return withValue(dataIDKey{}, id)
+}
+
+// WithGroup sets the group of the config
+func WithGroup(g string) source.Option {
+	return withValue(groupKey{}, g)
+}
+
+// WithNamespace sets the namespace id (tenant) of the config
+func WithNamespace(ns string) source.Option {
+	return withValue(namespaceKey{}, ns)
+}
+
+// Auth sets the username and password when nacos auth is enabled
+func Auth(username, password string) source.Option {
+	return withValue(authKey{}, &authCreds{Username: username, Password: password})
+}
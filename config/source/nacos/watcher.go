@@ -0,0 +1,61 @@
+package nacos
+
+import (
+	"context"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+)
+
+type watcher struct {
+	n      *nacos
+	cs     *source.ChangeSet
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newWatcher(n *nacos) (source.Watcher, error) {
+	cs, err := n.get(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &watcher{
+		n:      n,
+		cs:     cs,
+		ctx:    ctx,
+		cancel: cancel,
+	}, nil
+}
+
+func (w *watcher) Next() (*source.ChangeSet, error) {
+	for {
+		changed, err := w.n.listen(w.ctx, w.cs.Data)
+		if err != nil {
+			return nil, w.err(err)
+		}
+		if !changed {
+			continue
+		}
+		cs, err := w.n.get(w.ctx)
+		if err != nil {
+			return nil, w.err(err)
+		}
+		if cs.Checksum == w.cs.Checksum {
+			continue
+		}
+		w.cs = cs
+		return cs, nil
+	}
+}
+
+func (w *watcher) err(err error) error {
+	if w.ctx.Err() != nil {
+		return source.ErrWatcherStopped
+	}
+	return err
+}
+
+func (w *watcher) Stop() error {
+	w.cancel()
+	return nil
+}
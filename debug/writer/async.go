@@ -0,0 +1,77 @@
+package writer
+
+import (
+	"errors"
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+// ErrClosed writer已关闭
+var ErrClosed = errors.New("writer closed")
+
+// AsyncWriter 带缓冲的异步写入, 用于控制台、syslog等较慢的输出
+type AsyncWriter struct {
+	w      io.Writer
+	input  chan []byte
+	done   chan struct{}
+	mux    sync.RWMutex
+	closed bool
+}
+
+// NewAsyncWriter 实例化AsyncWriter, size为缓冲条数
+func NewAsyncWriter(w io.Writer, size int) *AsyncWriter {
+	if size <= 0 {
+		size = 100
+	}
+	p := &AsyncWriter{
+		w:     w,
+		input: make(chan []byte, size),
+		done:  make(chan struct{}),
+	}
+	go p.write()
+	return p
+}
+
+func (p *AsyncWriter) write() {
+	defer close(p.done)
+	for d := range p.input {
+		if _, err := p.w.Write(d); err != nil {
+			log.Printf("async write failed, %s\n", err.Error())
+		}
+	}
+}
+
+// Write 写入方法
+func (p *AsyncWriter) Write(data []byte) (n int, err error) {
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+	if p.closed {
+		return 0, ErrClosed
+	}
+	b := make([]byte, len(data))
+	copy(b, data)
+	p.input <- b
+	return len(data), nil
+}
+
+// Close 写完缓冲后关闭, 标准输出不会被关闭
+func (p *AsyncWriter) Close() error {
+	p.mux.Lock()
+	if p.closed {
+		p.mux.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.input)
+	p.mux.Unlock()
+	<-p.done
+	if p.w == os.Stdout || p.w == os.Stderr {
+		return nil
+	}
+	if c, ok := p.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
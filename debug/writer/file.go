@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -21,6 +22,9 @@ type FileWriter struct {
 	num          uint
 	opts         Options
 	input        chan []byte
+	done         chan struct{}
+	mux          sync.RWMutex
+	closed       bool
 }
 
 // NewFileWriter 实例化FileWriter, 支持大文件分割
@@ -57,21 +61,20 @@ func NewFileWriter(opts ...Option) (*FileWriter, error) {
 	if err != nil {
 		return nil, err
 	}
-	p.input = make(chan []byte, 100)
+	p.input = make(chan []byte, p.opts.buffer)
+	p.done = make(chan struct{})
 	go p.write()
 	return p, nil
 }
 
 func (p *FileWriter) write() {
-	for {
-		select {
-		case d := <-p.input:
-			_, err := p.file.Write(d)
-			if err != nil {
-				log.Printf("write file failed, %s\n", err.Error())
-			}
-			p.checkFile()
+	defer close(p.done)
+	for d := range p.input {
+		_, err := p.file.Write(d)
+		if err != nil {
+			log.Printf("write file failed, %s\n", err.Error())
 		}
+		p.checkFile()
 	}
 }
 
@@ -99,11 +102,30 @@ func (p *FileWriter) Write(data []byte) (n int, err error) {
 	if p.file == nil {
 		return 0, errors.New("file not opened")
 	}
-	n = len(data)
-	go func() {
-		p.input <- data
-	}()
-	return n, nil
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+	if p.closed {
+		return 0, ErrClosed
+	}
+	// 调用方可能复用data, 异步写入前先复制
+	b := make([]byte, len(data))
+	copy(b, data)
+	p.input <- b
+	return len(data), nil
+}
+
+// Close 写完缓冲中的日志后关闭文件
+func (p *FileWriter) Close() error {
+	p.mux.Lock()
+	if p.closed {
+		p.mux.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.input)
+	p.mux.Unlock()
+	<-p.done
+	return p.file.Close()
 }
 
 // getFilename 获取log文件名
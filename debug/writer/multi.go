@@ -0,0 +1,35 @@
+package writer
+
+import "io"
+
+// MultiWriter 同时输出到多个writer, 单个输出失败不影响其他输出
+type MultiWriter struct {
+	writers []io.Writer
+}
+
+// NewMultiWriter 实例化MultiWriter
+func NewMultiWriter(writers ...io.Writer) *MultiWriter {
+	return &MultiWriter{writers: writers}
+}
+
+// Write 写入所有writer, 返回第一个错误
+func (m *MultiWriter) Write(data []byte) (n int, err error) {
+	for _, w := range m.writers {
+		if _, e := w.Write(data); e != nil && err == nil {
+			err = e
+		}
+	}
+	return len(data), err
+}
+
+// Close 关闭所有实现了 io.Closer 的writer, 缓冲中的日志会先写完
+func (m *MultiWriter) Close() (err error) {
+	for _, w := range m.writers {
+		if c, ok := w.(io.Closer); ok {
+			if e := c.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	return err
+}
@@ -12,12 +12,14 @@ type Options struct {
 	path   string
 	suffix string //文件扩展名
 	cap    uint
+	buffer int //异步写入缓冲条数
 }
 
 func setDefault() Options {
 	return Options{
 		path:   "/tmp/go-admin",
 		suffix: "log",
+		buffer: 100,
 	}
 }
 
@@ -44,3 +46,12 @@ func WithCap(n uint) Option {
 		o.cap = n
 	}
 }
+
+// WithBuffer set async buffer size
+func WithBuffer(n int) Option {
+	return func(o *Options) {
+		if n > 0 {
+			o.buffer = n
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package ship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Elasticsearch 通过 bulk api 写入 Elasticsearch
+type Elasticsearch struct {
+	// Address eg: http://127.0.0.1:9200
+	Address string
+	Index   string
+	// DateLayout 按日期分索引, eg: 2006.01.02 时索引为 go-admin-2023.01.02
+	DateLayout string
+	// Header 额外的请求头, 如 Authorization
+	Header http.Header
+	Client *http.Client
+}
+
+// NewElasticsearch 实例化Elasticsearch
+func NewElasticsearch(address, index string) *Elasticsearch {
+	if index == "" {
+		index = "go-admin"
+	}
+	return &Elasticsearch{Address: address, Index: index}
+}
+
+func (*Elasticsearch) String() string {
+	return "elasticsearch"
+}
+
+// Ship 发送一批日志
+func (e *Elasticsearch) Ship(ctx context.Context, entries []Entry) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		meta, err := json.Marshal(map[string]interface{}{
+			"index": map[string]string{"_index": e.index(entry)},
+		})
+		if err != nil {
+			return err
+		}
+		buf.Write(meta)
+		buf.WriteByte('\n')
+		buf.Write(document(entry))
+		buf.WriteByte('\n')
+	}
+	b, err := post(ctx, e.Client, strings.TrimRight(e.Address, "/")+"/_bulk", "application/x-ndjson", buf.Bytes(), e.Header)
+	if err != nil {
+		return err
+	}
+	rsp := struct {
+		Errors bool `json:"errors"`
+	}{}
+	if err = json.Unmarshal(b, &rsp); err != nil {
+		return err
+	}
+	if rsp.Errors {
+		return errors.New("elasticsearch bulk has errors")
+	}
+	return nil
+}
+
+func (e *Elasticsearch) index(entry Entry) string {
+	if e.DateLayout == "" {
+		return e.Index
+	}
+	return e.Index + "-" + entry.Time.Format(e.DateLayout)
+}
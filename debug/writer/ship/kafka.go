@@ -0,0 +1,46 @@
+package ship
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Kafka 通过 Kafka REST Proxy(v2) 写入topic, 无需引入kafka客户端
+type Kafka struct {
+	// Address REST Proxy地址, eg: http://127.0.0.1:8082
+	Address string
+	Topic   string
+	// Header 额外的请求头, 如 Authorization
+	Header http.Header
+	Client *http.Client
+}
+
+// NewKafka 实例化Kafka
+func NewKafka(address, topic string) *Kafka {
+	if topic == "" {
+		topic = "go-admin-logs"
+	}
+	return &Kafka{Address: address, Topic: topic}
+}
+
+func (*Kafka) String() string {
+	return "kafka"
+}
+
+// Ship 发送一批日志
+func (k *Kafka) Ship(ctx context.Context, entries []Entry) error {
+	records := make([]map[string]json.RawMessage, 0, len(entries))
+	for _, e := range entries {
+		records = append(records, map[string]json.RawMessage{"value": document(e)})
+	}
+	body, err := json.Marshal(map[string]interface{}{"records": records})
+	if err != nil {
+		return err
+	}
+	u := strings.TrimRight(k.Address, "/") + "/topics/" + url.PathEscape(k.Topic)
+	_, err = post(ctx, k.Client, u, "application/vnd.kafka.json.v2+json", body, k.Header)
+	return err
+}
@@ -0,0 +1,51 @@
+package ship
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Loki 通过 push api 发送到 Loki
+type Loki struct {
+	// Address eg: http://127.0.0.1:3100
+	Address string
+	// Labels stream标签, 为空时为 {job="go-admin"}
+	Labels map[string]string
+	// Header 额外的请求头, 如 X-Scope-OrgID、Authorization
+	Header http.Header
+	Client *http.Client
+}
+
+// NewLoki 实例化Loki
+func NewLoki(address string, labels map[string]string) *Loki {
+	if len(labels) == 0 {
+		labels = map[string]string{"job": "go-admin"}
+	}
+	return &Loki{Address: address, Labels: labels}
+}
+
+func (*Loki) String() string {
+	return "loki"
+}
+
+// Ship 发送一批日志
+func (l *Loki) Ship(ctx context.Context, entries []Entry) error {
+	values := make([][2]string, 0, len(entries))
+	for _, e := range entries {
+		values = append(values, [2]string{strconv.FormatInt(e.Time.UnixNano(), 10), e.Line})
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"streams": []map[string]interface{}{{
+			"stream": l.Labels,
+			"values": values,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = post(ctx, l.Client, strings.TrimRight(l.Address, "/")+"/loki/api/v1/push", "application/json", body, l.Header)
+	return err
+}
@@ -0,0 +1,77 @@
+package ship
+
+import "time"
+
+// Options 可配置参数
+type Options struct {
+	batchSize     int
+	flushInterval time.Duration
+	buffer        int
+	maxRetry      int
+	retryBackoff  time.Duration
+	timeout       time.Duration
+	spillPath     string
+}
+
+func setDefault() Options {
+	return Options{
+		batchSize:     100,
+		flushInterval: time.Second,
+		buffer:        1000,
+		maxRetry:      3,
+		retryBackoff:  500 * time.Millisecond,
+		timeout:       5 * time.Second,
+	}
+}
+
+// Option set options
+type Option func(*Options)
+
+// WithBatchSize 单次发送的最大条数
+func WithBatchSize(n int) Option {
+	return func(o *Options) {
+		if n > 0 {
+			o.batchSize = n
+		}
+	}
+}
+
+// WithFlushInterval 未满一批时的发送间隔
+func WithFlushInterval(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.flushInterval = d
+		}
+	}
+}
+
+// WithBuffer 待发送缓冲条数, 满了之后直接写入落盘文件
+func WithBuffer(n int) Option {
+	return func(o *Options) {
+		if n > 0 {
+			o.buffer = n
+		}
+	}
+}
+
+// WithRetry 发送失败的重试次数及首次重试间隔, 间隔逐次翻倍
+func WithRetry(n int, backoff time.Duration) Option {
+	return func(o *Options) {
+		o.maxRetry = n
+		o.retryBackoff = backoff
+	}
+}
+
+// WithTimeout 单次发送超时
+func WithTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.timeout = d
+	}
+}
+
+// WithSpillPath 远端不可用时日志落盘的目录, 恢复后补发; 为空时丢弃
+func WithSpillPath(s string) Option {
+	return func(o *Options) {
+		o.spillPath = s
+	}
+}
@@ -0,0 +1,185 @@
+// Package ship 将日志批量发送到 Loki、Elasticsearch、Kafka 等集中式日志系统
+package ship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrClosed writer已关闭
+var ErrClosed = errors.New("shipper closed")
+
+// Entry 一条日志
+type Entry struct {
+	Time time.Time `json:"time"`
+	Line string    `json:"line"`
+}
+
+// Client 远端日志系统
+type Client interface {
+	String() string
+	Ship(ctx context.Context, entries []Entry) error
+}
+
+// Shipper 实现 io.WriteCloser, 批量异步发送日志, 发送失败时重试并落盘
+type Shipper struct {
+	client Client
+	opts   Options
+	input  chan Entry
+	done   chan struct{}
+	mux    sync.RWMutex
+	closed bool
+	spill  *spill
+}
+
+// NewShipper 实例化Shipper
+func NewShipper(client Client, opts ...Option) (*Shipper, error) {
+	s := &Shipper{
+		client: client,
+		opts:   setDefault(),
+	}
+	for _, o := range opts {
+		o(&s.opts)
+	}
+	if s.opts.spillPath != "" {
+		var err error
+		s.spill, err = newSpill(s.opts.spillPath, client.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+	s.input = make(chan Entry, s.opts.buffer)
+	s.done = make(chan struct{})
+	go s.run()
+	return s, nil
+}
+
+// Write 写入方法, 缓冲已满时直接落盘
+func (s *Shipper) Write(data []byte) (n int, err error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	if s.closed {
+		return 0, ErrClosed
+	}
+	e := Entry{Time: time.Now(), Line: string(bytes.TrimRight(data, "\n"))}
+	select {
+	case s.input <- e:
+	default:
+		s.save([]Entry{e})
+	}
+	return len(data), nil
+}
+
+// Close 发送缓冲中的日志后关闭, 发送失败的日志落盘
+func (s *Shipper) Close() error {
+	s.mux.Lock()
+	if s.closed {
+		s.mux.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.input)
+	s.mux.Unlock()
+	<-s.done
+	return nil
+}
+
+func (s *Shipper) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.opts.flushInterval)
+	defer ticker.Stop()
+	batch := make([]Entry, 0, s.opts.batchSize)
+	for {
+		select {
+		case e, ok := <-s.input:
+			if !ok {
+				s.flush(batch)
+				return
+			}
+			batch = append(batch, e)
+			if len(batch) >= s.opts.batchSize {
+				s.flush(batch)
+				batch = make([]Entry, 0, s.opts.batchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				s.flush(batch)
+				batch = make([]Entry, 0, s.opts.batchSize)
+			} else {
+				s.replay()
+			}
+		}
+	}
+}
+
+// flush 发送一批日志, 成功后补发落盘的日志
+func (s *Shipper) flush(batch []Entry) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := s.send(batch); err != nil {
+		log.Printf("ship logs to %s failed, %s\n", s.client.String(), err.Error())
+		s.save(batch)
+		return
+	}
+	s.replay()
+}
+
+// send 发送并按退避间隔重试
+func (s *Shipper) send(batch []Entry) (err error) {
+	backoff := s.opts.retryBackoff
+	for i := 0; i <= s.opts.maxRetry; i++ {
+		if i > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), s.opts.timeout)
+		err = s.client.Ship(ctx, batch)
+		cancel()
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (s *Shipper) save(entries []Entry) {
+	if s.spill == nil {
+		return
+	}
+	if err := s.spill.append(entries); err != nil {
+		log.Printf("spill logs failed, %s\n", err.Error())
+	}
+}
+
+// replay 补发落盘的日志, 失败时保留等待下次
+func (s *Shipper) replay() {
+	if s.spill == nil {
+		return
+	}
+	err := s.spill.replay(s.opts.batchSize, func(entries []Entry) error {
+		ctx, cancel := context.WithTimeout(context.Background(), s.opts.timeout)
+		defer cancel()
+		return s.client.Ship(ctx, entries)
+	})
+	if err != nil {
+		log.Printf("replay spilled logs to %s failed, %s\n", s.client.String(), err.Error())
+	}
+}
+
+// document 日志为json时原样发送, 否则包装为 {"@timestamp", "message"}
+func document(e Entry) json.RawMessage {
+	if json.Valid([]byte(e.Line)) && len(e.Line) > 0 && e.Line[0] == '{' {
+		return json.RawMessage(e.Line)
+	}
+	b, _ := json.Marshal(map[string]string{
+		"@timestamp": e.Time.Format(time.RFC3339Nano),
+		"message":    e.Line,
+	})
+	return b
+}
@@ -0,0 +1,86 @@
+package ship
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShipper_Loki(t *testing.T) {
+	var (
+		mux   sync.Mutex
+		lines []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/loki/api/v1/push" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		body := struct {
+			Streams []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][2]string       `json:"values"`
+			} `json:"streams"`
+		}{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mux.Lock()
+		for _, v := range body.Streams[0].Values {
+			lines = append(lines, v[1])
+		}
+		mux.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	s, err := NewShipper(NewLoki(srv.URL, nil), WithBatchSize(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range []string{"a\n", "b\n", "c\n"} {
+		_, _ = s.Write([]byte(l))
+	}
+	_ = s.Close()
+	if len(lines) != 3 || lines[2] != "c" {
+		t.Errorf("unexpected lines %v", lines)
+	}
+}
+
+func TestShipper_Spill(t *testing.T) {
+	var (
+		down     int32 = 1
+		received int32
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&down) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		body := struct {
+			Records []json.RawMessage `json:"records"`
+		}{}
+		_ = json.Unmarshal(b, &body)
+		atomic.AddInt32(&received, int32(len(body.Records)))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	s, err := NewShipper(NewKafka(srv.URL, "logs"),
+		WithRetry(0, 0), WithSpillPath(dir), WithFlushInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = s.Write([]byte("lost while down\n"))
+	time.Sleep(50 * time.Millisecond)
+
+	atomic.StoreInt32(&down, 0)
+	_, _ = s.Write([]byte(`{"msg":"back"}` + "\n"))
+	_ = s.Close()
+	if n := atomic.LoadInt32(&received); n != 2 {
+		t.Errorf("expected spilled log to be replayed, received %d", n)
+	}
+}
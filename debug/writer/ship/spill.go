@@ -0,0 +1,99 @@
+package ship
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// spill 远端不可用时按行保存日志的本地文件
+type spill struct {
+	filename string
+	mux      sync.Mutex
+}
+
+func newSpill(path, name string) (*spill, error) {
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, err
+	}
+	return &spill{filename: filepath.Join(path, name+".spill")}, nil
+}
+
+func (s *spill) append(entries []Entry) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	f, err := os.OpenFile(s.filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for i := range entries {
+		if err = enc.Encode(&entries[i]); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// replay 分批调用f, 全部成功后删除文件; 中途失败时保留未发送的部分
+func (s *spill) replay(size int, f func([]Entry) error) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	file, err := os.Open(s.filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	for scanner.Scan() {
+		var e Entry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	_ = file.Close()
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+	for len(entries) > 0 {
+		n := size
+		if n > len(entries) {
+			n = len(entries)
+		}
+		if err = f(entries[:n]); err != nil {
+			if e := s.rewrite(entries); e != nil {
+				return e
+			}
+			return err
+		}
+		entries = entries[n:]
+	}
+	return os.Remove(s.filename)
+}
+
+// rewrite 用未发送的日志覆盖文件
+func (s *spill) rewrite(entries []Entry) error {
+	tmp := s.filename + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for i := range entries {
+		if err = enc.Encode(&entries[i]); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.filename)
+}
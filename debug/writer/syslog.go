@@ -0,0 +1,14 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package writer
+
+import (
+	"io"
+	"log/syslog"
+)
+
+// NewSyslogWriter 输出到syslog, network和addr为空时使用本机syslog
+func NewSyslogWriter(network, addr, tag string) (io.WriteCloser, error) {
+	return syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_USER, tag)
+}
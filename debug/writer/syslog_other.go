@@ -0,0 +1,14 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package writer
+
+import (
+	"errors"
+	"io"
+)
+
+// NewSyslogWriter 当前系统不支持syslog
+func NewSyslogWriter(network, addr, tag string) (io.WriteCloser, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
@@ -0,0 +1,51 @@
+package writer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileWriter_Close(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewFileWriter(WithPath(dir), WithBuffer(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := []byte("first\n")
+	_, _ = w.Write(buf)
+	copy(buf, "reuse")
+	_, _ = w.Write([]byte("second\n"))
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write([]byte("closed\n")); err != ErrClosed {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, time.Now().Format(timeFormat)+".log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "first\nsecond\n" {
+		t.Errorf("unexpected content %q", string(b))
+	}
+}
+
+func TestMultiWriter(t *testing.T) {
+	var a, b bytes.Buffer
+	async := NewAsyncWriter(&b, 1)
+	m := NewMultiWriter(&a, async)
+	for i := 0; i < 3; i++ {
+		_, _ = m.Write([]byte("line\n"))
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != b.String() || strings.Count(b.String(), "line") != 3 {
+		t.Errorf("sinks differ: %q %q", a.String(), b.String())
+	}
+}
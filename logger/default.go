@@ -30,6 +30,8 @@ type defaultLogger struct {
 
 // Init (opts...) should only overwrite provided options
 func (l *defaultLogger) Init(opts ...Option) error {
+	l.Lock()
+	defer l.Unlock()
 	for _, o := range opts {
 		o(&l.opts)
 	}
@@ -89,13 +91,13 @@ func (l *defaultLogger) Logf(level Level, format string, v ...interface{}) {
 
 func (l *defaultLogger) logf(level Level, format string, v ...interface{}) {
 	// TODO decide does we need to write message if log level not used?
-	if !l.opts.Level.Enabled(level) {
-		return
-	}
-
 	l.RLock()
+	enabled := l.opts.Level.Enabled(level)
 	fields := copyFields(l.opts.Fields)
 	l.RUnlock()
+	if !enabled {
+		return
+	}
 
 	fields["level"] = level.String()
 
@@ -123,10 +125,14 @@ func (l *defaultLogger) logf(level Level, format string, v ...interface{}) {
 	metadata := ""
 
 	for i, k := range keys {
-		if i == 0 {
+		if i > 0 {
+			metadata += " "
+		}
+		// level和file只输出值, 其余字段输出 key=value
+		if k == "level" || k == "file" {
 			metadata += fmt.Sprintf("%v", fields[k])
 		} else {
-			metadata += fmt.Sprintf(" %v", fields[k])
+			metadata += fmt.Sprintf("%s=%v", k, fields[k])
 		}
 	}
 
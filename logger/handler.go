@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// levelBody 级别接口的请求与响应, 模块级别为空字符串时移除该模块级别
+type levelBody struct {
+	Level   string            `json:"level,omitempty"`
+	Modules map[string]string `json:"modules,omitempty"`
+}
+
+// LevelHandler 运行时查看和修改日志级别, 需自行挂载到有鉴权的路由
+//
+//	GET  返回 {"level":"info","modules":{"storage.queue":"debug"}}
+//	PUT  提交 {"level":"warn","modules":{"storage.queue":"debug","sdk.ws":""}}
+//
+// gin 中可以使用 gin.WrapH(logger.LevelHandler())
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var body levelBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := applyLevelBody(body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		body := levelBody{
+			Level:   CurrentLevel().String(),
+			Modules: make(map[string]string),
+		}
+		for k, v := range ModuleLevels() {
+			body.Modules[k] = v.String()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	})
+}
+
+// applyLevelBody 先校验全部级别再修改, 避免只生效一部分
+func applyLevelBody(body levelBody) error {
+	var (
+		global  *Level
+		modules = make(map[string]*Level, len(body.Modules))
+	)
+	if body.Level != "" {
+		l, err := GetLevel(body.Level)
+		if err != nil {
+			return err
+		}
+		global = &l
+	}
+	for k, v := range body.Modules {
+		if k == "" {
+			return fmt.Errorf("module name is empty")
+		}
+		if v == "" {
+			modules[k] = nil
+			continue
+		}
+		l, err := GetLevel(v)
+		if err != nil {
+			return err
+		}
+		modules[k] = &l
+	}
+	if global != nil {
+		if err := SetLevel(*global); err != nil {
+			return err
+		}
+	}
+	for k, v := range modules {
+		var err error
+		if v == nil {
+			err = ResetModuleLevel(k)
+		} else {
+			err = SetModuleLevel(k, *v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -81,42 +81,72 @@ func GetLevel(levelStr string) (Level, error) {
 }
 
 func Info(args ...interface{}) {
+	if !globalEnabled(InfoLevel) {
+		return
+	}
 	DefaultLogger.Log(InfoLevel, args...)
 }
 
 func Infof(template string, args ...interface{}) {
+	if !globalEnabled(InfoLevel) {
+		return
+	}
 	DefaultLogger.Logf(InfoLevel, template, args...)
 }
 
 func Trace(args ...interface{}) {
+	if !globalEnabled(TraceLevel) {
+		return
+	}
 	DefaultLogger.Log(TraceLevel, args...)
 }
 
 func Tracef(template string, args ...interface{}) {
+	if !globalEnabled(TraceLevel) {
+		return
+	}
 	DefaultLogger.Logf(TraceLevel, template, args...)
 }
 
 func Debug(args ...interface{}) {
+	if !globalEnabled(DebugLevel) {
+		return
+	}
 	DefaultLogger.Log(DebugLevel, args...)
 }
 
 func Debugf(template string, args ...interface{}) {
+	if !globalEnabled(DebugLevel) {
+		return
+	}
 	DefaultLogger.Logf(DebugLevel, template, args...)
 }
 
 func Warn(args ...interface{}) {
+	if !globalEnabled(WarnLevel) {
+		return
+	}
 	DefaultLogger.Log(WarnLevel, args...)
 }
 
 func Warnf(template string, args ...interface{}) {
+	if !globalEnabled(WarnLevel) {
+		return
+	}
 	DefaultLogger.Logf(WarnLevel, template, args...)
 }
 
 func Error(args ...interface{}) {
+	if !globalEnabled(ErrorLevel) {
+		return
+	}
 	DefaultLogger.Log(ErrorLevel, args...)
 }
 
 func Errorf(template string, args ...interface{}) {
+	if !globalEnabled(ErrorLevel) {
+		return
+	}
 	DefaultLogger.Logf(ErrorLevel, template, args...)
 }
 
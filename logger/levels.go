@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+)
+
+// levels 运行时级别, 未调用 SetLevel 时以 DefaultLogger 的级别为准
+//
+// 模块级别可以比全局级别更详细, 因此底层 DefaultLogger 的级别会被设为所有级别中的最低值,
+// 由 Helper、Structured 及包级函数按全局/模块级别过滤
+var levels = struct {
+	sync.RWMutex
+	set     bool
+	global  Level
+	modules map[string]Level
+}{modules: make(map[string]Level)}
+
+// SetLevel 运行时修改全局级别
+func SetLevel(level Level) error {
+	levels.Lock()
+	levels.set = true
+	levels.global = level
+	levels.Unlock()
+	return applyLevel()
+}
+
+// CurrentLevel 返回当前全局级别
+func CurrentLevel() Level {
+	levels.RLock()
+	defer levels.RUnlock()
+	if levels.set {
+		return levels.global
+	}
+	return DefaultLogger.Options().Level
+}
+
+// SetModuleLevel 设置模块级别, 模块名以"."分级, 如 storage.queue 同时作用于 storage.queue.redis
+func SetModuleLevel(module string, level Level) error {
+	levels.Lock()
+	if !levels.set {
+		levels.set = true
+		levels.global = DefaultLogger.Options().Level
+	}
+	levels.modules[module] = level
+	levels.Unlock()
+	return applyLevel()
+}
+
+// ResetModuleLevel 移除模块级别, 恢复使用全局级别
+func ResetModuleLevel(module string) error {
+	levels.Lock()
+	delete(levels.modules, module)
+	levels.Unlock()
+	return applyLevel()
+}
+
+// ModuleLevels 返回已设置的模块级别
+func ModuleLevels() map[string]Level {
+	levels.RLock()
+	defer levels.RUnlock()
+	m := make(map[string]Level, len(levels.modules))
+	for k, v := range levels.modules {
+		m[k] = v
+	}
+	return m
+}
+
+// Enabled 判断模块的级别是否开启, module 为空时使用全局级别
+func Enabled(module string, level Level) bool {
+	levels.RLock()
+	defer levels.RUnlock()
+	if !levels.set {
+		return DefaultLogger.Options().Level.Enabled(level)
+	}
+	if l, ok := moduleLevel(module); ok {
+		return l.Enabled(level)
+	}
+	return levels.global.Enabled(level)
+}
+
+// globalEnabled 判断非模块日志是否开启, 未调用 SetLevel 时不过滤
+func globalEnabled(level Level) bool {
+	levels.RLock()
+	defer levels.RUnlock()
+	return !levels.set || levels.global.Enabled(level)
+}
+
+// moduleLevel 从最长的模块名开始向上查找
+func moduleLevel(module string) (Level, bool) {
+	for module != "" {
+		if l, ok := levels.modules[module]; ok {
+			return l, true
+		}
+		i := strings.LastIndexByte(module, '.')
+		if i < 0 {
+			break
+		}
+		module = module[:i]
+	}
+	return 0, false
+}
+
+// applyLevel 将 DefaultLogger 的级别设为全局和模块级别中的最低值
+func applyLevel() error {
+	levels.RLock()
+	floor := levels.global
+	for _, l := range levels.modules {
+		if l < floor {
+			floor = l
+		}
+	}
+	levels.RUnlock()
+	if DefaultLogger == nil {
+		return nil
+	}
+	return DefaultLogger.Init(WithLevel(floor))
+}
@@ -30,10 +30,16 @@ func Fields(fields map[string]interface{}) Logger {
 }
 
 func Log(level Level, v ...interface{}) {
+	if !globalEnabled(level) {
+		return
+	}
 	DefaultLogger.Log(level, v...)
 }
 
 func Logf(level Level, format string, v ...interface{}) {
+	if !globalEnabled(level) {
+		return
+	}
 	DefaultLogger.Logf(level, format, v...)
 }
 
@@ -1,7 +1,11 @@
 package logger
 
 import (
+	"bytes"
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -23,3 +27,110 @@ func TestLogger(t *testing.T) {
 	ll := v.(*Helper)
 	ll.Info("test_msg")
 }
+
+func TestStructured(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithLevel(InfoLevel), WithOutput(&buf))
+	RegisterContextFields(func(ctx context.Context) map[string]interface{} {
+		if v, ok := ctx.Value("tenant").(string); ok {
+			return map[string]interface{}{"tenant": v}
+		}
+		return nil
+	})
+	s := NewStructured(l).With("component", "queue")
+	s.Debug("ignored")
+	s.WithContext(context.WithValue(context.Background(), "tenant", "a.com")).
+		Info("consume failed", "stream", "sms", "retry", 2)
+
+	out := buf.String()
+	if strings.Contains(out, "ignored") {
+		t.Fatalf("debug should be disabled: %s", out)
+	}
+	for _, want := range []string{"info", "component=queue", "stream=sms", "retry=2", "tenant=a.com", "consume failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in %q", want, out)
+		}
+	}
+}
+
+func TestTraceFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithLevel(InfoLevel), WithOutput(&buf))
+	ctx := WithRequestID(context.Background(), "r1")
+	ctx = WithTraceID(ctx, "t1")
+	ctx = WithOperatorID(ctx, "7")
+
+	NewStructured(l).WithContext(ctx).Info("saved")
+	for _, want := range []string{"x-request-id=r1", "trace-id=t1", "operator-id=7"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in %q", want, buf.String())
+		}
+	}
+
+	copied := CopyTrace(context.Background(), ctx)
+	if RequestID(copied) != "r1" || TraceID(copied) != "t1" || OperatorID(copied) != "7" {
+		t.Errorf("unexpected copied trace: %v", TraceFields(copied))
+	}
+}
+
+func TestModuleLevel(t *testing.T) {
+	var buf bytes.Buffer
+	prev := DefaultLogger
+	DefaultLogger = NewHelper(NewLogger(WithLevel(InfoLevel), WithOutput(&buf)))
+	defer func() {
+		DefaultLogger = prev
+		levels.set = false
+		levels.modules = make(map[string]Level)
+	}()
+
+	if err := SetModuleLevel("storage.queue", DebugLevel); err != nil {
+		t.Fatal(err)
+	}
+	Module("storage.queue.redis").Debug("queue debug")
+	Module("sdk").Debug("sdk debug")
+	Debug("global debug")
+	DefaultLogger.(*Helper).Debug("helper debug")
+
+	out := buf.String()
+	if !strings.Contains(out, "queue debug") || !strings.Contains(out, "module=storage.queue.redis") {
+		t.Errorf("module debug expected in %q", out)
+	}
+	for _, s := range []string{"sdk debug", "global debug", "helper debug"} {
+		if strings.Contains(out, s) {
+			t.Errorf("%q should be filtered: %q", s, out)
+		}
+	}
+
+	buf.Reset()
+	req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"warn","modules":{"storage.queue":""}}`))
+	rec := httptest.NewRecorder()
+	LevelHandler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || CurrentLevel() != WarnLevel || len(ModuleLevels()) != 0 {
+		t.Fatalf("unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+	Module("storage.queue").Info("queue info")
+	if buf.Len() != 0 {
+		t.Errorf("info should be filtered after reset: %q", buf.String())
+	}
+}
+
+func TestRedactWriter(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRedactor(RedactFields, RedactPatterns["phone"], RedactPatterns["idcard"])
+	l := NewLogger(WithLevel(InfoLevel), WithOutput(NewRedactWriter(&buf, r)))
+
+	NewStructured(l).Info("login", "password", "p@ss", "x-token", "abc", "phone", "13800138000")
+	_, _ = NewRedactWriter(&buf, r).Write([]byte(`{"user":"a","Password":"q\"w","id":"11010519491231002X"}`))
+
+	out := buf.String()
+	for _, leak := range []string{"p@ss", "abc", "13800138000", `q\"w`, "11010519491231002X"} {
+		if strings.Contains(out, leak) {
+			t.Errorf("%q leaked in %q", leak, out)
+		}
+	}
+	for _, want := range []string{"password=******", "x-token=******", `"Password":"******"`, `"user":"a"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in %q", want, out)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// RedactMask 敏感数据在日志中的替换值
+const RedactMask = "******"
+
+// RedactFields 默认脱敏的字段名, 字段名包含其中之一(不区分大小写)即脱敏
+var RedactFields = []string{"password", "passwd", "secret", "token", "authorization", "cookie"}
+
+// 内置的敏感数据格式, 可在配置中按名称引用
+var RedactPatterns = map[string]*regexp.Regexp{
+	// 手机号
+	"phone": regexp.MustCompile(`\b1[3-9]\d{9}\b`),
+	// 18位身份证号
+	"idcard": regexp.MustCompile(`\b\d{17}[\dXx]\b`),
+	// jwt
+	"jwt": regexp.MustCompile(`\beyJ[\w-]+\.[\w-]+\.[\w-]+`),
+	// 银行卡号
+	"bankcard": regexp.MustCompile(`\b\d{16,19}\b`),
+}
+
+// Redactor 按字段名和正则脱敏日志
+type Redactor struct {
+	field    *regexp.Regexp
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor 字段名匹配 key=value、"key":"value"、key: value 三种格式, patterns 匹配到的内容整体替换
+func NewRedactor(fields []string, patterns ...*regexp.Regexp) *Redactor {
+	r := &Redactor{patterns: patterns}
+	if len(fields) > 0 {
+		names := make([]string, 0, len(fields))
+		for _, f := range fields {
+			names = append(names, regexp.QuoteMeta(f))
+		}
+		r.field = regexp.MustCompile(`(?i)("?[\w.-]*(?:` + strings.Join(names, "|") +
+			`)[\w.-]*"?\s*[=:]\s*)("(?:[^"\\]|\\.)*"|[^\s,}&]+)`)
+	}
+	return r
+}
+
+// Redact 返回脱敏后的内容
+func (r *Redactor) Redact(b []byte) []byte {
+	if r == nil {
+		return b
+	}
+	if r.field != nil {
+		b = r.field.ReplaceAllFunc(b, func(m []byte) []byte {
+			sub := r.field.FindSubmatchIndex(m)
+			key, value := m[:sub[3]], m[sub[4]:sub[5]]
+			out := make([]byte, 0, len(key)+len(RedactMask)+2)
+			out = append(out, key...)
+			if len(value) > 0 && value[0] == '"' {
+				return append(append(append(out, '"'), RedactMask...), '"')
+			}
+			return append(out, RedactMask...)
+		})
+	}
+	for _, p := range r.patterns {
+		b = p.ReplaceAll(b, []byte(RedactMask))
+	}
+	return b
+}
+
+// RedactString 返回脱敏后的字符串
+func (r *Redactor) RedactString(s string) string {
+	return string(r.Redact([]byte(s)))
+}
+
+type redactWriter struct {
+	w io.Writer
+	r *Redactor
+}
+
+// NewRedactWriter 写入前脱敏, 包装在所有输出之前, 保证任何输出都拿不到明文
+func NewRedactWriter(w io.Writer, r *Redactor) io.Writer {
+	if c, ok := w.(io.Closer); ok {
+		return &redactWriteCloser{redactWriter{w: w, r: r}, c}
+	}
+	return &redactWriter{w: w, r: r}
+}
+
+func (w *redactWriter) Write(p []byte) (int, error) {
+	if _, err := w.w.Write(w.r.Redact(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+type redactWriteCloser struct {
+	redactWriter
+	io.Closer
+}
@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Structured 结构化日志, 字段以键值对传入, 底层输出到任意 Logger 实现(default, zap, logrus, slog)
+//
+//	logger.S().Error("queue consume failed", "stream", name, "error", err)
+type Structured interface {
+	Debug(msg string, kv ...interface{})
+	Info(msg string, kv ...interface{})
+	Warn(msg string, kv ...interface{})
+	Error(msg string, kv ...interface{})
+	// With 返回附加了字段的logger
+	With(kv ...interface{}) Structured
+	// WithContext 返回附加了ctx中字段的logger, 见 RegisterContextFields
+	WithContext(ctx context.Context) Structured
+	// Named 返回属于模块的logger, 级别见 SetModuleLevel
+	Named(module string) Structured
+}
+
+// ContextFields 从ctx中提取日志字段
+type ContextFields func(ctx context.Context) map[string]interface{}
+
+var (
+	contextMux    sync.RWMutex
+	contextFields []ContextFields
+)
+
+// RegisterContextFields 注册ctx字段提取方法, WithContext 时调用
+func RegisterContextFields(f ContextFields) {
+	contextMux.Lock()
+	defer contextMux.Unlock()
+	contextFields = append(contextFields, f)
+}
+
+type structured struct {
+	// l 为nil时使用 DefaultLogger, SetupLogger 之后的替换也能生效
+	l      Logger
+	fields map[string]interface{}
+	module string
+}
+
+// S 返回输出到 DefaultLogger 的结构化日志
+func S() Structured {
+	return &structured{}
+}
+
+// Module 返回属于模块的结构化日志, 如 logger.Module("storage.queue")
+func Module(module string) Structured {
+	return &structured{module: module}
+}
+
+// NewStructured 返回输出到l的结构化日志
+func NewStructured(l Logger) Structured {
+	return &structured{l: l}
+}
+
+func (s *structured) logger() Logger {
+	if s.l != nil {
+		return s.l
+	}
+	return DefaultLogger
+}
+
+// entry 返回带字段的Logger, 级别未开启时返回nil
+// 各级别方法直接调用其Log, 保证 CallerSkipCount 与 Helper 一致
+func (s *structured) entry(level Level, kv []interface{}) Logger {
+	l := s.logger()
+	if l == nil || !l.Options().Level.Enabled(level) {
+		return nil
+	}
+	fields := copyFields(s.fields)
+	if s.module == "" {
+		if !globalEnabled(level) {
+			return nil
+		}
+	} else {
+		if !Enabled(s.module, level) {
+			return nil
+		}
+		fields["module"] = s.module
+	}
+	return l.Fields(appendKV(fields, kv))
+}
+
+func (s *structured) Debug(msg string, kv ...interface{}) {
+	if l := s.entry(DebugLevel, kv); l != nil {
+		l.Log(DebugLevel, msg)
+	}
+}
+
+func (s *structured) Info(msg string, kv ...interface{}) {
+	if l := s.entry(InfoLevel, kv); l != nil {
+		l.Log(InfoLevel, msg)
+	}
+}
+
+func (s *structured) Warn(msg string, kv ...interface{}) {
+	if l := s.entry(WarnLevel, kv); l != nil {
+		l.Log(WarnLevel, msg)
+	}
+}
+
+func (s *structured) Error(msg string, kv ...interface{}) {
+	if l := s.entry(ErrorLevel, kv); l != nil {
+		l.Log(ErrorLevel, msg)
+	}
+}
+
+func (s *structured) With(kv ...interface{}) Structured {
+	return &structured{l: s.l, fields: appendKV(copyFields(s.fields), kv), module: s.module}
+}
+
+func (s *structured) Named(module string) Structured {
+	return &structured{l: s.l, fields: copyFields(s.fields), module: module}
+}
+
+func (s *structured) WithContext(ctx context.Context) Structured {
+	if ctx == nil {
+		return s
+	}
+	n := &structured{l: s.l, fields: copyFields(s.fields), module: s.module}
+	// ctx中有请求logger时使用它的Logger和字段
+	if h, ok := FromContext(ctx); ok && h != nil {
+		if n.l == nil {
+			n.l = h.Logger
+		}
+		for k, v := range h.fields {
+			if _, ok := n.fields[k]; !ok {
+				n.fields[k] = v
+			}
+		}
+	}
+	contextMux.RLock()
+	list := contextFields
+	contextMux.RUnlock()
+	for _, f := range list {
+		for k, v := range f(ctx) {
+			n.fields[k] = v
+		}
+	}
+	return n
+}
+
+// appendKV 将键值对写入fields, 奇数个时最后一个值的key为 "extra"
+func appendKV(fields map[string]interface{}, kv []interface{}) map[string]interface{} {
+	for i := 0; i < len(kv); i += 2 {
+		if i+1 == len(kv) {
+			fields["extra"] = kv[i]
+			break
+		}
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprint(kv[i])
+		}
+		fields[key] = kv[i+1]
+	}
+	return fields
+}
@@ -0,0 +1,80 @@
+package logger
+
+import "context"
+
+// 请求链路字段名, 所有经 WithContext 输出的日志都会带上
+const (
+	RequestIDKey  = "x-request-id"
+	TraceIDKey    = "trace-id"
+	OperatorIDKey = "operator-id"
+)
+
+type requestIDKey struct{}
+type traceIDKey struct{}
+type operatorIDKey struct{}
+
+func init() {
+	RegisterContextFields(TraceFields)
+}
+
+// WithRequestID ctx附加请求id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID 获取ctx中的请求id
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithTraceID ctx附加链路id
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+// TraceID 获取ctx中的链路id
+func TraceID(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+// WithOperatorID ctx附加操作人id
+func WithOperatorID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, operatorIDKey{}, id)
+}
+
+// OperatorID 获取ctx中的操作人id
+func OperatorID(ctx context.Context) string {
+	id, _ := ctx.Value(operatorIDKey{}).(string)
+	return id
+}
+
+// TraceFields 返回ctx中的链路字段, 未设置的不返回
+func TraceFields(ctx context.Context) map[string]interface{} {
+	fields := make(map[string]interface{}, 3)
+	if id := RequestID(ctx); id != "" {
+		fields[RequestIDKey] = id
+	}
+	if id := TraceID(ctx); id != "" {
+		fields[TraceIDKey] = id
+	}
+	if id := OperatorID(ctx); id != "" {
+		fields[OperatorIDKey] = id
+	}
+	return fields
+}
+
+// CopyTrace 将src中的链路字段复制到dst, 用于脱离请求生命周期的异步任务
+func CopyTrace(dst, src context.Context) context.Context {
+	if id := RequestID(src); id != "" {
+		dst = WithRequestID(dst, id)
+	}
+	if id := TraceID(src); id != "" {
+		dst = WithTraceID(dst, id)
+	}
+	if id := OperatorID(src); id != "" {
+		dst = WithOperatorID(dst, id)
+	}
+	return dst
+}
@@ -0,0 +1,7 @@
+module github.com/go-admin-team/go-admin-core/plugins/logger/slog
+
+go 1.21
+
+require github.com/go-admin-team/go-admin-core v1.4.0
+
+replace github.com/go-admin-team/go-admin-core v1.4.0 => ../../../
@@ -0,0 +1,26 @@
+package slog
+
+import (
+	"log/slog"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+type Options struct {
+	logger.Options
+	Handler slog.Handler
+}
+
+type handlerKey struct{}
+
+// WithHandler 使用自定义 slog.Handler，未设置时按 Out 输出 JSON
+func WithHandler(h slog.Handler) logger.Option {
+	return logger.SetOption(handlerKey{}, h)
+}
+
+type slogLoggerKey struct{}
+
+// WithLogger 直接使用已有的 *slog.Logger
+func WithLogger(l *slog.Logger) logger.Option {
+	return logger.SetOption(slogLoggerKey{}, l)
+}
@@ -0,0 +1,116 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+const (
+	levelTrace = slog.LevelDebug - 4
+	levelFatal = slog.LevelError + 4
+)
+
+type slogLogger struct {
+	log   *slog.Logger
+	level *slog.LevelVar
+	opts  Options
+}
+
+func (l *slogLogger) Init(opts ...logger.Option) error {
+	for _, o := range opts {
+		o(&l.opts.Options)
+	}
+	if l.level == nil {
+		l.level = new(slog.LevelVar)
+	}
+	l.level.Set(loggerToSlogLevel(l.opts.Level))
+
+	if h, ok := l.opts.Context.Value(handlerKey{}).(slog.Handler); ok {
+		l.opts.Handler = h
+	}
+	if sl, ok := l.opts.Context.Value(slogLoggerKey{}).(*slog.Logger); ok {
+		l.log = sl
+	} else {
+		if l.opts.Handler == nil {
+			l.opts.Handler = slog.NewJSONHandler(l.opts.Out, &slog.HandlerOptions{Level: l.level})
+		}
+		l.log = slog.New(l.opts.Handler)
+	}
+
+	if len(l.opts.Fields) > 0 {
+		l.log = l.log.With(attrs(l.opts.Fields)...)
+	}
+	return nil
+}
+
+func (l *slogLogger) String() string {
+	return "slog"
+}
+
+func (l *slogLogger) Fields(fields map[string]interface{}) logger.Logger {
+	return &slogLogger{log: l.log.With(attrs(fields)...), level: l.level, opts: l.opts}
+}
+
+func (l *slogLogger) Log(level logger.Level, args ...interface{}) {
+	l.log.Log(context.Background(), loggerToSlogLevel(level), fmt.Sprint(args...))
+	if level == logger.FatalLevel {
+		os.Exit(1)
+	}
+}
+
+func (l *slogLogger) Logf(level logger.Level, format string, args ...interface{}) {
+	l.log.Log(context.Background(), loggerToSlogLevel(level), fmt.Sprintf(format, args...))
+	if level == logger.FatalLevel {
+		os.Exit(1)
+	}
+}
+
+func (l *slogLogger) Options() logger.Options {
+	return l.opts.Options
+}
+
+// NewLogger 基于 log/slog 构建 logger
+func NewLogger(opts ...logger.Option) logger.Logger {
+	options := Options{
+		Options: logger.Options{
+			Level:   logger.InfoLevel,
+			Fields:  make(map[string]interface{}),
+			Out:     os.Stderr,
+			Context: context.Background(),
+		},
+	}
+	l := &slogLogger{opts: options}
+	_ = l.Init(opts...)
+	return l
+}
+
+func attrs(fields map[string]interface{}) []any {
+	args := make([]any, 0, len(fields))
+	for k, v := range fields {
+		args = append(args, slog.Any(k, v))
+	}
+	return args
+}
+
+func loggerToSlogLevel(level logger.Level) slog.Level {
+	switch level {
+	case logger.TraceLevel:
+		return levelTrace
+	case logger.DebugLevel:
+		return slog.LevelDebug
+	case logger.InfoLevel:
+		return slog.LevelInfo
+	case logger.WarnLevel:
+		return slog.LevelWarn
+	case logger.ErrorLevel:
+		return slog.LevelError
+	case logger.FatalLevel:
+		return levelFatal
+	default:
+		return slog.LevelInfo
+	}
+}
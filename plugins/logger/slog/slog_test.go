@@ -0,0 +1,42 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+func TestName(t *testing.T) {
+	l := NewLogger()
+
+	if l.String() != "slog" {
+		t.Errorf("error: name expected 'slog' actual: %s", l.String())
+	}
+}
+
+func TestStructured(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := NewLogger(logger.WithOutput(buf), logger.WithLevel(logger.DebugLevel))
+
+	logger.NewStructured(l).With("stream", "orders").Debug("consume failed", "id", 7)
+
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("unexpected output %q: %v", buf.String(), err)
+	}
+	if m["level"] != "DEBUG" || m["msg"] != "consume failed" || m["stream"] != "orders" || m["id"] != float64(7) {
+		t.Errorf("unexpected record: %v", m)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := NewLogger(logger.WithOutput(buf), logger.WithLevel(logger.WarnLevel))
+
+	l.Log(logger.InfoLevel, "skipped")
+	if buf.Len() != 0 {
+		t.Errorf("info should be filtered: %s", buf.String())
+	}
+}
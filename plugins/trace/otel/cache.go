@@ -0,0 +1,153 @@
+package otel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+// Cache 为缓存操作创建span, storage.AdapterCache 不传递ctx, 需要先调用 WithContext 绑定请求的ctx
+//
+//	c := otel.NewCache(sdk.Runtime.GetCacheAdapter())
+//	c.WithContext(ctx).Get(key)
+type Cache struct {
+	storage.AdapterCache
+	ctx context.Context
+}
+
+// NewCache 包装缓存
+func NewCache(c storage.AdapterCache) *Cache {
+	return &Cache{AdapterCache: c, ctx: context.Background()}
+}
+
+// WithContext 返回绑定ctx的副本
+func (c *Cache) WithContext(ctx context.Context) *Cache {
+	return &Cache{AdapterCache: c.AdapterCache, ctx: ctx}
+}
+
+// Unwrap 内部缓存
+func (c *Cache) Unwrap() storage.AdapterCache {
+	return c.AdapterCache
+}
+
+func (c *Cache) do(operation, key string, f func() error) error {
+	_, span := tracer().Start(c.ctx, "cache."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", c.AdapterCache.String()),
+			attribute.String("db.operation.name", operation),
+			attribute.String("cache.key", key),
+		),
+	)
+	defer span.End()
+	err := f()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+func (c *Cache) Get(key string) (val string, err error) {
+	err = c.do("get", key, func() error {
+		val, err = c.AdapterCache.Get(key)
+		return err
+	})
+	return
+}
+
+func (c *Cache) Set(key string, val interface{}, expire int) error {
+	return c.do("set", key, func() error {
+		return c.AdapterCache.Set(key, val, expire)
+	})
+}
+
+func (c *Cache) Del(key string) error {
+	return c.do("del", key, func() error {
+		return c.AdapterCache.Del(key)
+	})
+}
+
+func (c *Cache) HashGet(hk, key string) (val string, err error) {
+	err = c.do("hget", hk, func() error {
+		val, err = c.AdapterCache.HashGet(hk, key)
+		return err
+	})
+	return
+}
+
+func (c *Cache) HashDel(hk, key string) error {
+	return c.do("hdel", hk, func() error {
+		return c.AdapterCache.HashDel(hk, key)
+	})
+}
+
+func (c *Cache) Increase(key string) error {
+	return c.do("incr", key, func() error {
+		return c.AdapterCache.Increase(key)
+	})
+}
+
+func (c *Cache) Decrease(key string) error {
+	return c.do("decr", key, func() error {
+		return c.AdapterCache.Decrease(key)
+	})
+}
+
+func (c *Cache) Expire(key string, dur time.Duration) error {
+	return c.do("expire", key, func() error {
+		return c.AdapterCache.Expire(key, dur)
+	})
+}
+
+func (c *Cache) GetTouch(key string, ttl time.Duration) (val string, err error) {
+	err = c.do("gettouch", key, func() error {
+		val, err = cache.GetTouch(c.AdapterCache, key, ttl)
+		return err
+	})
+	return
+}
+
+func (c *Cache) SetNX(key string, val interface{}, expire int) (ok bool, err error) {
+	err = c.do("setnx", key, func() error {
+		ok, err = cache.SetNX(c.AdapterCache, key, val, expire)
+		return err
+	})
+	return
+}
+
+func (c *Cache) CompareAndSwap(key, old string, val interface{}, expire int) (ok bool, err error) {
+	err = c.do("cas", key, func() error {
+		ok, err = cache.CompareAndSwap(c.AdapterCache, key, old, val, expire)
+		return err
+	})
+	return
+}
+
+func (c *Cache) HashSet(hk, key string, val interface{}) error {
+	return c.do("hset", hk, func() error {
+		return cache.HashSet(c.AdapterCache, hk, key, val)
+	})
+}
+
+func (c *Cache) HashGetAll(hk string) (values map[string]string, err error) {
+	err = c.do("hgetall", hk, func() error {
+		values, err = cache.HashGetAll(c.AdapterCache, hk)
+		return err
+	})
+	return
+}
+
+func (c *Cache) Keys(prefix string) (keys []string, err error) {
+	err = c.do("keys", prefix, func() error {
+		keys, err = cache.Keys(c.AdapterCache, prefix)
+		return err
+	})
+	return
+}
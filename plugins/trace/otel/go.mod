@@ -0,0 +1,16 @@
+module github.com/go-admin-team/go-admin-core/plugins/trace/otel
+
+go 1.25.0
+
+require (
+	github.com/gin-gonic/gin v1.8.1
+	github.com/go-admin-team/go-admin-core v1.4.0
+	go.opentelemetry.io/otel v1.43.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc v1.43.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.43.0
+	go.opentelemetry.io/otel/sdk v1.43.0
+	go.opentelemetry.io/otel/trace v1.43.0
+	gorm.io/gorm v1.23.10
+)
+
+replace github.com/go-admin-team/go-admin-core v1.4.0 => ../../../
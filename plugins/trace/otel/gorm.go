@@ -0,0 +1,77 @@
+package otel
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "otel:span"
+
+// GormPlugin 为 gorm 的增删改查创建客户端span, 需要通过 db.WithContext 传入请求的ctx
+//
+//	db.Use(otel.GormPlugin{})
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "otel"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("otel:before_create", before("create")),
+		cb.Create().After("gorm:create").Register("otel:after_create", after),
+		cb.Query().Before("gorm:query").Register("otel:before_query", before("query")),
+		cb.Query().After("gorm:query").Register("otel:after_query", after),
+		cb.Update().Before("gorm:update").Register("otel:before_update", before("update")),
+		cb.Update().After("gorm:update").Register("otel:after_update", after),
+		cb.Delete().Before("gorm:delete").Register("otel:before_delete", before("delete")),
+		cb.Delete().After("gorm:delete").Register("otel:after_delete", after),
+		cb.Row().Before("gorm:row").Register("otel:before_row", before("row")),
+		cb.Row().After("gorm:row").Register("otel:after_row", after),
+		cb.Raw().Before("gorm:raw").Register("otel:before_raw", before("raw")),
+		cb.Raw().After("gorm:raw").Register("otel:after_raw", after),
+	)
+}
+
+func before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement == nil || db.Statement.Context == nil {
+			return
+		}
+		ctx, span := tracer().Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", db.Dialector.Name()),
+				attribute.String("db.operation.name", operation),
+			),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func after(db *gorm.DB) {
+	v, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("db.collection.name", db.Statement.Table),
+		attribute.String("db.query.text", db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -0,0 +1,104 @@
+package otel
+
+import (
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// 支持的导出方式, jaeger 1.35 及以上版本直接接收 otlp
+const (
+	ExporterOTLPGRPC = "otlp-grpc"
+	ExporterOTLPHTTP = "otlp-http"
+	ExporterJaeger   = "jaeger"
+)
+
+// Config 链路追踪配置, 可直接放到应用配置的 extend 中
+type Config struct {
+	ServiceName string            `yaml:"serviceName" json:"serviceName"`
+	Exporter    string            `yaml:"exporter" json:"exporter"`
+	Endpoint    string            `yaml:"endpoint" json:"endpoint"`
+	Insecure    bool              `yaml:"insecure" json:"insecure"`
+	SampleRatio float64           `yaml:"sampleRatio" json:"sampleRatio"`
+	Attributes  map[string]string `yaml:"attributes" json:"attributes"`
+}
+
+type Option func(*options)
+
+type options struct {
+	Config
+	exporter sdktrace.SpanExporter
+}
+
+func setDefault() options {
+	return options{
+		Config: Config{
+			ServiceName: "go-admin",
+			Exporter:    ExporterOTLPGRPC,
+			SampleRatio: 1,
+		},
+	}
+}
+
+// WithConfig 使用配置文件中的参数
+func WithConfig(c Config) Option {
+	return func(o *options) {
+		if c.ServiceName != "" {
+			o.ServiceName = c.ServiceName
+		}
+		if c.Exporter != "" {
+			o.Exporter = c.Exporter
+		}
+		o.Endpoint = c.Endpoint
+		o.Insecure = c.Insecure
+		o.SampleRatio = c.SampleRatio
+		o.Attributes = c.Attributes
+	}
+}
+
+// WithServiceName 服务名称, 对应 resource 的 service.name
+func WithServiceName(name string) Option {
+	return func(o *options) {
+		o.ServiceName = name
+	}
+}
+
+// WithExporter 导出方式, 见 ExporterOTLPGRPC 等
+func WithExporter(exporter string) Option {
+	return func(o *options) {
+		o.Exporter = exporter
+	}
+}
+
+// WithEndpoint 采集端地址, 如 localhost:4317
+func WithEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.Endpoint = endpoint
+	}
+}
+
+// WithInsecure 不使用tls连接采集端
+func WithInsecure(insecure bool) Option {
+	return func(o *options) {
+		o.Insecure = insecure
+	}
+}
+
+// WithSampleRatio 采样比例 0~1, 已有父span时跟随父span的采样结果
+func WithSampleRatio(ratio float64) Option {
+	return func(o *options) {
+		o.SampleRatio = ratio
+	}
+}
+
+// WithAttributes 附加的 resource 属性, 如 deployment.environment
+func WithAttributes(attrs map[string]string) Option {
+	return func(o *options) {
+		o.Attributes = attrs
+	}
+}
+
+// WithSpanExporter 使用自定义的导出器, 设置后忽略 Exporter 与 Endpoint
+func WithSpanExporter(exporter sdktrace.SpanExporter) Option {
+	return func(o *options) {
+		o.exporter = exporter
+	}
+}
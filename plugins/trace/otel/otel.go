@@ -0,0 +1,83 @@
+// Package otel 根据配置初始化 OpenTelemetry 链路追踪, 并为 http、gorm、缓存、队列提供埋点
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName 埋点使用的 tracer 名称
+const ScopeName = "github.com/go-admin-team/go-admin-core/plugins/trace/otel"
+
+func tracer() trace.Tracer {
+	return otel.Tracer(ScopeName)
+}
+
+// Setup 创建并设置全局的 TracerProvider 与 W3C 传播器, 返回的函数用于退出时刷新未导出的span
+func Setup(ctx context.Context, opts ...Option) (func(context.Context) error, error) {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	exporter := o.exporter
+	if exporter == nil {
+		var err error
+		exporter, err = newExporter(ctx, o)
+		if err != nil {
+			return nil, err
+		}
+	}
+	attrs := []attribute.KeyValue{semconv.ServiceName(o.ServiceName)}
+	for k, v := range o.Attributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attrs...))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+	return tp.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, o options) (sdktrace.SpanExporter, error) {
+	switch o.Exporter {
+	case ExporterOTLPGRPC:
+		var opts []otlptracegrpc.Option
+		if o.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(o.Endpoint))
+		}
+		if o.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case ExporterOTLPHTTP, ExporterJaeger:
+		var opts []otlptracehttp.Option
+		if o.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(o.Endpoint))
+		}
+		if o.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("otel exporter %q not supported", o.Exporter)
+	}
+}
@@ -0,0 +1,134 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+func setupRecorder() *tracetest.SpanRecorder {
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return sr
+}
+
+func TestMiddleware(t *testing.T) {
+	sr := setupRecorder()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	var traceID string
+	r.GET("/users/:id", func(c *gin.Context) {
+		traceID = logger.TraceID(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := sr.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d", len(spans))
+	}
+	if spans[0].Name() != "GET /users/:id" {
+		t.Errorf("name = %s", spans[0].Name())
+	}
+	if got := spans[0].Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("parent = %s", got)
+	}
+	if traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("logger trace id = %s", traceID)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	sr := setupRecorder()
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+	q := NewQueue(queue.NewMemory(10))
+	done := make(chan trace.SpanContext, 1)
+	q.Register("test", func(message storage.Messager) error {
+		done <- trace.SpanContextFromContext(Extract(message))
+		return nil
+	})
+	go q.Run()
+	defer q.Shutdown()
+
+	m := new(queue.Message)
+	m.SetStream("test")
+	m.SetValues(map[string]interface{}{"key": "value"})
+	Inject(ctx, m)
+	if err := q.Append(m); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+	select {
+	case sc := <-done:
+		if sc.TraceID() != parent.SpanContext().TraceID() {
+			t.Errorf("consumer trace id = %s", sc.TraceID())
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("message not consumed")
+	}
+	time.Sleep(10 * time.Millisecond)
+	names := map[string]bool{}
+	for _, s := range sr.Ended() {
+		names[s.Name()] = true
+	}
+	if !names["queue.append test"] || !names["queue.consume test"] {
+		t.Errorf("spans = %v", names)
+	}
+}
+
+func TestCache(t *testing.T) {
+	sr := setupRecorder()
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+	c := NewCache(cache.NewMemory()).WithContext(ctx)
+	_ = c.Set("k", "v", 60)
+	if v, _ := c.Get("k"); v != "v" {
+		t.Errorf("Get() = %s", v)
+	}
+	parent.End()
+	spans := sr.Ended()
+	if len(spans) != 3 || spans[1].Name() != "cache.get" ||
+		spans[1].Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("unexpected spans")
+	}
+}
+
+func TestTransport(t *testing.T) {
+	sr := setupRecorder()
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	c := &http.Client{Transport: Transport(nil)}
+	resp, err := c.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	spans := sr.Ended()
+	if len(spans) != 1 || spans[0].Name() != "HTTP GET" || spans[0].SpanKind() != trace.SpanKindClient {
+		t.Fatalf("unexpected spans")
+	}
+	if want := "00-" + spans[0].SpanContext().TraceID().String() + "-" + spans[0].SpanContext().SpanID().String() + "-01"; traceparent != want {
+		t.Errorf("traceparent = %s, want %s", traceparent, want)
+	}
+}
@@ -0,0 +1,100 @@
+package otel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// messageCarrier 把链路信息(traceparent等)存放在消息 Values 中
+type messageCarrier map[string]interface{}
+
+func (c messageCarrier) Get(key string) string {
+	v, _ := c[key].(string)
+	return v
+}
+
+func (c messageCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c messageCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Inject 将ctx中的链路写入消息, 生产端在 Append 前调用
+func Inject(ctx context.Context, message storage.Messager) {
+	values := message.GetValues()
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+	otel.GetTextMapPropagator().Inject(ctx, messageCarrier(values))
+	message.SetValues(values)
+}
+
+// Extract 从消息中恢复链路, 消费端用于创建子span
+func Extract(message storage.Messager) context.Context {
+	values := message.GetValues()
+	if values == nil {
+		return context.Background()
+	}
+	return otel.GetTextMapPropagator().Extract(context.Background(), messageCarrier(values))
+}
+
+// Queue 为消息的投递与消费创建span, 消费函数内可用 Extract(message) 继续链路
+type Queue struct {
+	storage.AdapterQueue
+}
+
+// NewQueue 包装队列
+func NewQueue(q storage.AdapterQueue) *Queue {
+	return &Queue{AdapterQueue: q}
+}
+
+func (q *Queue) Append(message storage.Messager) error {
+	ctx, span := tracer().Start(Extract(message), "queue.append "+message.GetStream(),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", q.AdapterQueue.String()),
+			attribute.String("messaging.destination.name", message.GetStream()),
+		),
+	)
+	defer span.End()
+	Inject(ctx, message)
+	err := q.AdapterQueue.Append(message)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+func (q *Queue) Register(name string, f storage.ConsumerFunc) {
+	q.AdapterQueue.Register(name, func(message storage.Messager) error {
+		ctx, span := tracer().Start(Extract(message), "queue.consume "+name,
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(
+				attribute.String("messaging.system", q.AdapterQueue.String()),
+				attribute.String("messaging.destination.name", name),
+				attribute.String("messaging.message.id", message.GetID()),
+			),
+		)
+		defer span.End()
+		Inject(ctx, message)
+		err := f(message)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return err
+	})
+}
@@ -12,7 +12,9 @@ import (
 	"github.com/go-admin-team/go-admin-core/logger"
 	"github.com/go-admin-team/go-admin-core/sdk"
 	"github.com/go-admin-team/go-admin-core/sdk/pkg"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/i18n"
 	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/validate"
 	"github.com/go-admin-team/go-admin-core/sdk/service"
 	"github.com/go-admin-team/go-admin-core/storage"
 	"github.com/go-admin-team/go-admin-core/tools/language"
@@ -81,6 +83,14 @@ func (e *Api) Bind(d interface{}, bindings ...binding.Binding) *Api {
 	return e
 }
 
+// BindValid 使用 validate.Bind 绑定并校验参数, 错误可直接交给 Fail 返回字段级错误
+func (e *Api) BindValid(d interface{}, bindings ...binding.Binding) *Api {
+	if err := validate.Bind(e.Context, d, bindings...); err != nil {
+		e.AddError(err)
+	}
+	return e
+}
+
 // GetOrm 获取Orm DB
 func (e Api) GetOrm() (*gorm.DB, error) {
 	db, err := pkg.GetOrm(e.Context)
@@ -121,6 +131,11 @@ func (e Api) Error(code int, err error, msg string) {
 	response.Error(e.Context, code, err, msg)
 }
 
+// Fail 按业务错误处理, 见 response.FromError
+func (e Api) Fail(err error) {
+	response.Fail(e.Context, err)
+}
+
 // OK 通常成功数据处理
 func (e Api) OK(data interface{}, msg string) {
 	response.OK(e.Context, data, msg)
@@ -136,12 +151,20 @@ func (e Api) Custom(data gin.H) {
 	response.Custum(e.Context, data)
 }
 
+// T 按请求语言翻译文案
+func (e Api) T(id string, data map[string]interface{}) string {
+	return i18n.T(e.Context, id, data)
+}
+
 func (e Api) Translate(form, to interface{}) {
 	pkg.Translate(form, to)
 }
 
 // getAcceptLanguage 获取当前语言
 func (e *Api) getAcceptLanguage() string {
+	if lang := e.Context.GetString(i18n.LangKey); lang != "" {
+		return lang
+	}
 	languages := language.ParseAcceptLanguage(e.Context.GetHeader("Accept-Language"), nil)
 	if len(languages) == 0 {
 		return DefaultLanguage
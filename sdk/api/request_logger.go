@@ -24,18 +24,21 @@ func GetRequestLogger(c *gin.Context) *logger.Helper {
 		}
 	}
 	//如果没有在上下文中放入logger
-	requestId := pkg.GenerateMsgIDFromContext(c)
-	log = logger.NewHelper(sdk.Runtime.GetLogger()).WithFields(map[string]interface{}{
-		strings.ToLower(pkg.TrafficKey): requestId,
-	})
-	return log
+	return logger.NewHelper(sdk.Runtime.GetLogger()).WithFields(requestFields(c))
 }
 
 // SetRequestLogger 设置logger中间件
 func SetRequestLogger(c *gin.Context) {
-	requestId := pkg.GenerateMsgIDFromContext(c)
-	log := logger.NewHelper(sdk.Runtime.GetLogger()).WithFields(map[string]interface{}{
-		strings.ToLower(pkg.TrafficKey): requestId,
-	})
+	log := logger.NewHelper(sdk.Runtime.GetLogger()).WithFields(requestFields(c))
 	c.Set(pkg.LoggerKey, log)
 }
+
+// requestFields 请求id及 middleware.Trace 写入ctx的链路字段
+func requestFields(c *gin.Context) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if c.Request != nil {
+		fields = logger.TraceFields(c.Request.Context())
+	}
+	fields[strings.ToLower(pkg.TrafficKey)] = pkg.GenerateMsgIDFromContext(c)
+	return fields
+}
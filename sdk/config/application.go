@@ -1,15 +1,53 @@
 package config
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/server/listener"
+)
+
 type Application struct {
-	ReadTimeout   int
-	WriterTimeout int
-	Host          string
-	Port          int64
-	Name          string
-	JwtSecret     string
-	Mode          string
-	DemoMsg       string
-	EnableDP      bool
+	ReadTimeout    int
+	WriterTimeout  int
+	IdleTimeout    int
+	MaxHeaderBytes int
+	H2C            bool
+	Host           string
+	Port           int64 `validate:"gte=0,lte=65535"`
+	Name           string
+	JwtSecret      string
+	Mode           string `validate:"omitempty,oneof=dev test prod demo"`
+	DemoMsg        string
+	EnableDP       bool
 }
 
 var ApplicationConfig = new(Application)
+
+// ServerOptions 根据 application 与 ssl 配置生成 listener 参数, 超时单位为秒
+func ServerOptions() []listener.Option {
+	e := ApplicationConfig
+	opts := []listener.Option{
+		listener.WithAddr(fmt.Sprintf("%s:%d", e.Host, e.Port)),
+		listener.WithH2C(e.H2C),
+	}
+	if e.ReadTimeout > 0 {
+		opts = append(opts, listener.WithReadTimeout(time.Duration(e.ReadTimeout)*time.Second))
+	}
+	if e.WriterTimeout > 0 {
+		opts = append(opts, listener.WithWriteTimeout(time.Duration(e.WriterTimeout)*time.Second))
+	}
+	if e.IdleTimeout > 0 {
+		opts = append(opts, listener.WithIdleTimeout(time.Duration(e.IdleTimeout)*time.Second))
+	}
+	if e.MaxHeaderBytes > 0 {
+		opts = append(opts, listener.WithMaxHeaderBytes(e.MaxHeaderBytes))
+	}
+	if SslConfig.Enable {
+		opts = append(opts, listener.WithCert(SslConfig.Pem), listener.WithKey(SslConfig.KeyStr))
+		if SslConfig.ReloadInterval > 0 {
+			opts = append(opts, listener.WithReloadInterval(time.Duration(SslConfig.ReloadInterval)*time.Second))
+		}
+	}
+	return opts
+}
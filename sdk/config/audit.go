@@ -0,0 +1,62 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/go-admin-team/go-admin-core/config"
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+// AuditStream 配置变更审计事件的队列名
+const AuditStream = "config.audit"
+
+var (
+	auditMux   sync.RWMutex
+	auditQueue storage.AdapterQueue
+)
+
+// SetAuditQueue 热更新时将配置差异作为审计事件发送到队列, 消费 AuditStream 即可
+// 消息values: host, timestamp, changes(json, 敏感项已脱敏)
+func SetAuditQueue(q storage.AdapterQueue) {
+	auditMux.Lock()
+	defer auditMux.Unlock()
+	auditQueue = q
+}
+
+// audit 输出热更新前后的配置差异
+func audit(e *config.Event) {
+	changes := config.Diff(e.Previous, e.Current)
+	if len(changes) == 0 {
+		return
+	}
+	for _, c := range changes {
+		logger.S().Info("config change", "path", c.Path, "old", c.Old, "new", c.New)
+	}
+	auditMux.RLock()
+	q := auditQueue
+	auditMux.RUnlock()
+	if q == nil {
+		return
+	}
+	b, err := json.Marshal(changes)
+	if err != nil {
+		logger.S().Error("config audit marshal failed", "error", err)
+		return
+	}
+	host, _ := os.Hostname()
+	message := &queue.Message{}
+	message.SetStream(AuditStream)
+	message.SetValues(map[string]interface{}{
+		"host":      host,
+		"timestamp": strconv.FormatInt(e.Timestamp.Unix(), 10),
+		"changes":   string(b),
+	})
+	if err = q.Append(message); err != nil {
+		logger.S().Error("config audit append failed", "stream", AuditStream, "error", err)
+	}
+}
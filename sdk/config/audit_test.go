@@ -0,0 +1,40 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/config"
+	"github.com/go-admin-team/go-admin-core/config/reader/json"
+	"github.com/go-admin-team/go-admin-core/config/source"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+type captureQueue struct {
+	storage.AdapterQueue
+	messages []storage.Messager
+}
+
+func (q *captureQueue) Append(m storage.Messager) error {
+	q.messages = append(q.messages, m)
+	return nil
+}
+
+func TestAudit(t *testing.T) {
+	r := json.NewReader()
+	prev, _ := r.Values(&source.ChangeSet{Data: []byte(`{"settings":{"jwt":{"secret":"a","timeout":1}}}`), Format: "json"})
+	cur, _ := r.Values(&source.ChangeSet{Data: []byte(`{"settings":{"jwt":{"secret":"b","timeout":2}}}`), Format: "json"})
+
+	q := &captureQueue{}
+	SetAuditQueue(q)
+	defer SetAuditQueue(nil)
+	audit(&config.Event{Previous: prev.Get(), Current: cur.Get()})
+
+	if len(q.messages) != 1 || q.messages[0].GetStream() != AuditStream {
+		t.Fatalf("expected one audit message, got %d", len(q.messages))
+	}
+	changes := q.messages[0].GetValues()["changes"].(string)
+	if strings.Contains(changes, `"b"`) || !strings.Contains(changes, "settings.jwt.timeout") {
+		t.Fatalf("unexpected changes %s", changes)
+	}
+}
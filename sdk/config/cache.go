@@ -3,31 +3,52 @@ package config
 import (
 	"github.com/go-admin-team/go-admin-core/storage"
 	"github.com/go-admin-team/go-admin-core/storage/cache"
+	"github.com/go-admin-team/go-admin-core/storage/namespace"
 )
 
 type Cache struct {
-	Redis  *RedisConnectOptions
-	Memory interface{}
+	// Driver 通过注册表选择驱动，为空时沿用 redis > memory 的判断
+	Driver  string `validate:"omitempty,cache_driver"`
+	Options map[string]interface{}
+	Redis   *RedisConnectOptions
+	Memory  interface{}
+	// Environment 环境名(dev、staging、prod), 设置后key自动带上环境前缀, 多个环境可共用一个redis
+	Environment string
 }
 
 // CacheConfig cache配置
 var CacheConfig = new(Cache)
 
-// Setup 构造cache 顺序 redis > 其他 > memory
+// Setup 构造cache 顺序 driver > redis > 其他 > memory, 设置 Environment 时按环境隔离
 func (e Cache) Setup() (storage.AdapterCache, error) {
+	c, err := e.setup()
+	if err != nil || e.Environment == "" {
+		return c, err
+	}
+	return namespace.NewCache(c, namespace.Parse(e.Environment)), nil
+}
+
+func (e Cache) setup() (storage.AdapterCache, error) {
+	if e.Driver != "" {
+		return storage.NewCache(e.Driver, e.Options)
+	}
 	if e.Redis != nil {
-		options, err := e.Redis.GetRedisOptions()
-		if err != nil {
-			return nil, err
-		}
-		r, err := cache.NewRedis(GetRedisClient(), options)
-		if err != nil {
-			return nil, err
-		}
-		if _redis == nil {
-			_redis = r.GetClient()
-		}
-		return r, nil
+		return setupRedisCache(e.Redis)
 	}
 	return cache.NewMemory(), nil
 }
+
+func setupRedisCache(c *RedisConnectOptions) (storage.AdapterCache, error) {
+	options, err := c.GetRedisOptions()
+	if err != nil {
+		return nil, err
+	}
+	r, err := cache.NewRedis(GetRedisClient(), options)
+	if err != nil {
+		return nil, err
+	}
+	if _redis == nil {
+		_redis = r.GetClient()
+	}
+	return r, nil
+}
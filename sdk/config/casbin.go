@@ -0,0 +1,15 @@
+package config
+
+type Casbin struct {
+	// Model 模型文件路径, 为空时使用内置的 RESTful 模型
+	Model string
+	// Prefix、Table 策略表为 Prefix_Table, 默认 sys_casbin_rule
+	Prefix string
+	Table  string
+	// CacheTTL 鉴权结果缓存时间, 单位秒, 0 不缓存
+	CacheTTL int `validate:"gte=0"`
+	// Channel 多实例同步策略的 redis 频道
+	Channel string
+}
+
+var CasbinConfig = new(Casbin)
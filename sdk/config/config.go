@@ -26,17 +26,25 @@ func (e *Settings) runCallback() {
 }
 
 func (e *Settings) OnChange() {
+	if err := Validate(&e.Settings); err != nil {
+		log.Printf("config change not applied, %s", err.Error())
+		return
+	}
 	e.init()
 	log.Println("config change and reload")
 }
 
 func (e *Settings) Init() {
+	if err := Validate(&e.Settings); err != nil {
+		log.Fatal(err.Error())
+	}
 	e.init()
 	log.Println("config init")
 }
 
 func (e *Settings) init() {
 	e.Settings.Logger.Setup()
+	e.Settings.Password.Setup()
 	e.Settings.multiDatabase()
 	e.runCallback()
 }
@@ -48,11 +56,15 @@ type Config struct {
 	Logger      *Logger               `yaml:"logger"`
 	Jwt         *Jwt                  `yaml:"jwt"`
 	Database    *Database             `yaml:"database"`
-	Databases   *map[string]*Database `yaml:"databases"`
+	Databases   *map[string]*Database `yaml:"databases" validate:"omitempty,dive"`
 	Gen         *Gen                  `yaml:"gen"`
 	Cache       *Cache                `yaml:"cache"`
 	Queue       *Queue                `yaml:"queue"`
 	Locker      *Locker               `yaml:"locker"`
+	Casbin      *Casbin               `yaml:"casbin"`
+	Grpc        *Grpc                 `yaml:"grpc"`
+	Password    *Password             `yaml:"password"`
+	Security    *Security             `yaml:"security"`
 	Extend      interface{}           `yaml:"extend"`
 }
 
@@ -69,6 +81,10 @@ func (e *Config) multiDatabase() {
 // Setup 载入配置文件
 func Setup(s source.Source,
 	fs ...func()) {
+	setup([]source.Source{s}, fs...)
+}
+
+func setup(ss []source.Source, fs ...func()) {
 	_cfg = &Settings{
 		Settings: Config{
 			Application: ApplicationConfig,
@@ -81,17 +97,23 @@ func Setup(s source.Source,
 			Cache:       CacheConfig,
 			Queue:       QueueConfig,
 			Locker:      LockerConfig,
-			Extend:      ExtendConfig,
+			Casbin:      CasbinConfig,
+			Grpc:        GrpcConfig,
+			Password:    PasswordConfig,
+			Security:    SecurityConfig,
+			Extend:      &extendSections{},
 		},
 		callbacks: fs,
 	}
+	opts := []config.Option{config.WithEntity(_cfg)}
+	for i := range ss {
+		opts = append(opts, config.WithSource(ss[i]))
+	}
 	var err error
-	config.DefaultConfig, err = config.NewConfig(
-		config.WithSource(s),
-		config.WithEntity(_cfg),
-	)
+	config.DefaultConfig, err = config.NewConfig(opts...)
 	if err != nil {
 		log.Fatal(fmt.Sprintf("New config object fail: %s", err.Error()))
 	}
+	config.DefaultConfig.OnChange(audit)
 	_cfg.Init()
 }
@@ -1,23 +1,99 @@
 package config
 
+import (
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/tools/database"
+	"gorm.io/gorm"
+)
+
 type Database struct {
-	Driver          string
-	Source          string
-	ConnMaxIdleTime int
-	ConnMaxLifeTime int
-	MaxIdleConns    int
-	MaxOpenConns    int
-	Registers       []DBResolverConfig
+	// Driver 见 database.Drivers, 除 mysql 外需由应用通过 database.RegisterDialector 注册驱动
+	Driver          string `validate:"omitempty,database_driver"`
+	Source          string `validate:"required_with=Driver,database_dsn"`
+	ConnMaxIdleTime int    `validate:"gte=0"`
+	ConnMaxLifeTime int    `validate:"gte=0"`
+	MaxIdleConns    int    `validate:"gte=0"`
+	MaxOpenConns    int    `validate:"gte=0"`
+	// PrepareStmt 缓存预编译语句
+	PrepareStmt bool
+	// PoolCheck 连接池检查间隔(秒), 0不检查; 使用率达到 PoolSaturation 或出现等待连接时告警
+	PoolCheck int `validate:"gte=0"`
+	// PoolSaturation 连接池使用率告警阈值, 默认0.8
+	PoolSaturation float64 `validate:"gte=0,lte=1"`
+	// Replicas 读库, Source为写库, 未在Registers中指定的表读写分离到这里
+	Replicas []string `validate:"dive,database_dsn"`
+	// Policy 读库选择策略 random, round_robin
+	Policy string `validate:"omitempty,oneof=random round_robin"`
+	// HealthCheck 读库健康检查间隔(秒), 0不检查
+	HealthCheck int `validate:"gte=0"`
+	Registers   []DBResolverConfig
 }
 
 type DBResolverConfig struct {
-	Sources  []string
-	Replicas []string
-	Policy   string
-	Tables   []string
+	// Driver 与主库不同时设置, 如分析类的表使用 clickhouse
+	Driver      string `validate:"omitempty,database_driver"`
+	Sources     []string
+	Replicas    []string
+	Policy      string `validate:"omitempty,oneof=random round_robin"`
+	Tables      []string
+	HealthCheck int `validate:"gte=0"`
 }
 
 var (
 	DatabaseConfig  = new(Database)
 	DatabasesConfig = make(map[string]*Database)
 )
+
+// Configure 转换为 tools/database 的配置
+func (e *Database) Configure() database.Configure {
+	registers := make([]database.ResolverConfigure, 0, len(e.Registers)+1)
+	for i := range e.Registers {
+		r := e.Registers[i]
+		opts := []database.ResolverOption{database.WithHealthCheck(time.Duration(r.HealthCheck) * time.Second)}
+		if r.Driver != "" && r.Driver != e.Driver {
+			if open, err := database.Dialect(r.Driver); err == nil {
+				opts = append(opts, database.WithDialector(open))
+			}
+		}
+		registers = append(registers, database.NewResolverConfigure(
+			r.Sources, r.Replicas, r.Policy, r.Tables, opts...))
+	}
+	if len(e.Replicas) > 0 {
+		// 全局的读写分离, 不指定表
+		registers = append(registers, database.NewResolverConfigure(
+			[]string{e.Source}, e.Replicas, e.Policy, nil,
+			database.WithHealthCheck(time.Duration(e.HealthCheck)*time.Second)))
+	}
+	return database.NewConfigure(e.Source,
+		e.MaxIdleConns, e.MaxOpenConns,
+		e.ConnMaxIdleTime, e.ConnMaxLifeTime,
+		registers,
+		database.WithPrepareStmt(e.PrepareStmt))
+}
+
+// Open 按 Driver 打开数据库, 驱动未注册或dsn格式错误时返回错误
+func (e *Database) Open(config *gorm.Config) (*gorm.DB, error) {
+	for _, r := range e.Registers {
+		if r.Driver == "" {
+			continue
+		}
+		if _, err := database.Dialect(r.Driver); err != nil {
+			return nil, err
+		}
+	}
+	return database.Open(e.Driver, e.Configure(), config)
+}
+
+// PoolMonitor 按 PoolCheck 创建连接池监控, 未开启时返回nil
+// 返回值实现 runtime.Component, 由调用方加入生命周期
+func (e *Database) PoolMonitor(name string, db *gorm.DB) (*database.PoolMonitor, error) {
+	if e.PoolCheck <= 0 {
+		return nil, nil
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return database.NewPoolMonitor(name, sqlDB, time.Duration(e.PoolCheck)*time.Second, e.PoolSaturation), nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+// 内置驱动注册，第三方驱动在各自的包中调用 storage.RegisterXXX 即可通过配置的 driver 选择
+func init() {
+	storage.RegisterCache("memory", func(map[string]interface{}) (storage.AdapterCache, error) {
+		return cache.NewMemory(), nil
+	})
+	storage.RegisterCache("redis", func(options map[string]interface{}) (storage.AdapterCache, error) {
+		c := new(RedisConnectOptions)
+		if err := storage.DecodeOptions(options, c); err != nil {
+			return nil, err
+		}
+		return setupRedisCache(c)
+	})
+
+	storage.RegisterQueue("memory", func(options map[string]interface{}) (storage.AdapterQueue, error) {
+		c := new(QueueMemory)
+		if err := storage.DecodeOptions(options, c); err != nil {
+			return nil, err
+		}
+		return setupMemoryQueue(c)
+	})
+	storage.RegisterQueue("redis", func(options map[string]interface{}) (storage.AdapterQueue, error) {
+		c := new(QueueRedis)
+		if err := storage.DecodeOptions(options, c); err != nil {
+			return nil, err
+		}
+		return setupRedisQueue(c)
+	})
+	storage.RegisterQueue("nsq", func(options map[string]interface{}) (storage.AdapterQueue, error) {
+		c := new(QueueNSQ)
+		if err := storage.DecodeOptions(options, c); err != nil {
+			return nil, err
+		}
+		return setupNSQQueue(c)
+	})
+
+	storage.RegisterLocker("redis", func(options map[string]interface{}) (storage.AdapterLocker, error) {
+		c := new(RedisConnectOptions)
+		if err := storage.DecodeOptions(options, c); err != nil {
+			return nil, err
+		}
+		return setupRedisLocker(c)
+	})
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+var (
+	extendMux sync.RWMutex
+	extends   = make(map[string]interface{})
+)
+
+// RegisterExtend 注册扩展配置, 对应 settings.extend.<key>, v必须是指针
+// 与核心配置一起载入、校验和热更新, 需在 Setup 之前调用
+//
+//	config.RegisterExtend("sms", &SmsConfig{})
+func RegisterExtend(key string, v interface{}) {
+	extendMux.Lock()
+	defer extendMux.Unlock()
+	extends[key] = v
+}
+
+// GetExtend 获取注册的扩展配置
+func GetExtend(key string) interface{} {
+	extendMux.RLock()
+	defer extendMux.RUnlock()
+	return extends[key]
+}
+
+func extendKeys() []string {
+	keys := make([]string, 0, len(extends))
+	for k := range extends {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// extendSections 将 settings.extend 分发到 ExtendConfig 和注册的扩展配置
+type extendSections struct{}
+
+func (extendSections) UnmarshalJSON(b []byte) error {
+	if ExtendConfig != nil {
+		if err := json.Unmarshal(b, ExtendConfig); err != nil {
+			return err
+		}
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	extendMux.RLock()
+	defer extendMux.RUnlock()
+	for key, v := range extends {
+		data, ok := raw[key]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (extendSections) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{})
+	if ExtendConfig != nil {
+		b, err := json.Marshal(ExtendConfig)
+		if err != nil {
+			return nil, err
+		}
+		_ = json.Unmarshal(b, &m)
+	}
+	extendMux.RLock()
+	defer extendMux.RUnlock()
+	for key, v := range extends {
+		m[key] = v
+	}
+	return json.Marshal(m)
+}
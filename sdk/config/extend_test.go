@@ -0,0 +1,44 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/config"
+	"github.com/go-admin-team/go-admin-core/config/source/memory"
+)
+
+type smsConfig struct {
+	Provider string `validate:"oneof=aliyun tencent"`
+	Sign     string
+}
+
+func TestRegisterExtend(t *testing.T) {
+	sms := &smsConfig{}
+	RegisterExtend("sms", sms)
+	defer delete(extends, "sms")
+
+	data := []byte(`
+settings:
+  extend:
+    sms:
+      provider: qcloud
+      sign: go-admin
+`)
+	c, err := config.NewConfig(config.WithSource(memory.NewSource(memory.WithYAML(data))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Settings{Settings: Config{Extend: &extendSections{}}}
+	if err = c.Scan(s); err != nil {
+		t.Fatal(err)
+	}
+	if sms.Provider != "qcloud" || sms.Sign != "go-admin" {
+		t.Fatalf("extend not populated: %+v", sms)
+	}
+
+	err = Validate(&s.Settings)
+	ve, ok := err.(*ValidateError)
+	if !ok || len(ve.Problems) != 1 || ve.Problems[0].Path != "settings.extend.sms.provider" {
+		t.Fatalf("unexpected validate result: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+)
+
+var GrpcConfig = new(Grpc)
+
+// Grpc 拆分微服务时的 gRPC 服务端与依赖服务的客户端
+type Grpc struct {
+	Server *GrpcServer
+	// Clients 依赖的服务, key 为服务名, 通过 runtime 的 GetGrpcClient 获取连接
+	Clients map[string]*GrpcClient `validate:"omitempty,dive"`
+}
+
+type GrpcServer struct {
+	Addr string `validate:"required"`
+	// Timeout 连接空闲超时, 单位秒
+	Timeout int
+	// MaxMsgSize 单个消息的最大字节数
+	MaxMsgSize int
+	CertFile   string `validate:"required_with=KeyFile"`
+	KeyFile    string `validate:"required_with=CertFile"`
+	// SkipAuth 不需要鉴权的方法全名, 如 /grpc.health.v1.Health/Check
+	SkipAuth []string
+}
+
+type GrpcClient struct {
+	Target string `validate:"required"`
+	// Timeout 建立连接的超时时间, 单位秒, 为0时不等待连接建立
+	Timeout int
+	Tls     bool
+	// CaFile 校验服务端证书的 ca, 为空时使用系统证书
+	CaFile     string
+	ServerName string
+}
+
+// TLS 证书配置, 未配置证书时返回nil
+func (e *GrpcServer) TLS() (*tls.Config, error) {
+	if e.CertFile == "" {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(e.CertFile, e.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}}, nil
+}
+
+// TLS 客户端证书配置, 未启用时返回nil
+func (e *GrpcClient) TLS() (*tls.Config, error) {
+	if !e.Tls {
+		return nil, nil
+	}
+	c := &tls.Config{ServerName: e.ServerName}
+	if e.CaFile != "" {
+		pem, err := os.ReadFile(e.CaFile)
+		if err != nil {
+			return nil, err
+		}
+		c.RootCAs = x509.NewCertPool()
+		if !c.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New("grpc: invalid ca file " + e.CaFile)
+		}
+	}
+	return c, nil
+}
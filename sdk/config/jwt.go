@@ -2,7 +2,13 @@ package config
 
 type Jwt struct {
 	Secret  string
-	Timeout int64
+	Timeout int64 `validate:"gte=0"`
+	// SigningAlgorithm 默认 HS256, RS/PS/ES 系列需要配置密钥文件
+	SigningAlgorithm string `validate:"omitempty,oneof=HS256 HS384 HS512 RS256 RS384 RS512 PS256 PS384 PS512 ES256 ES384 ES512"`
+	PrivKeyFile      string
+	PubKeyFile       string
+	// MaxRefresh 可刷新的最长时间, 单位秒
+	MaxRefresh int64 `validate:"gte=0"`
 }
 
 var JwtConfig = new(Jwt)
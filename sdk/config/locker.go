@@ -1,6 +1,7 @@
 package config
 
 import (
+	"github.com/go-admin-team/go-admin-core/logger"
 	"github.com/go-admin-team/go-admin-core/storage"
 	"github.com/go-admin-team/go-admin-core/storage/locker"
 	"github.com/go-redis/redis/v9"
@@ -9,27 +10,41 @@ import (
 var LockerConfig = new(Locker)
 
 type Locker struct {
-	Redis *RedisConnectOptions
+	// Driver 通过注册表选择驱动，为空时沿用 redis 的判断
+	Driver  string `validate:"omitempty,locker_driver"`
+	Options map[string]interface{}
+	Redis   *RedisConnectOptions
 }
 
 // Empty 空设置
 func (e Locker) Empty() bool {
-	return e.Redis == nil
+	return e.Driver == "" && e.Redis == nil
 }
 
-// Setup 启用顺序 redis > 其他 > memory
+// Setup 启用顺序 driver > redis > 其他 > memory
 func (e Locker) Setup() (storage.AdapterLocker, error) {
+	if e.Driver != "" {
+		return storage.NewLocker(e.Driver, e.Options)
+	}
 	if e.Redis != nil {
-		client := GetRedisClient()
-		if client == nil {
-			options, err := e.Redis.GetRedisOptions()
-			if err != nil {
-				return nil, err
-			}
-			client = redis.NewClient(options)
-			_redis = client
-		}
-		return locker.NewRedis(client), nil
+		return setupRedisLocker(e.Redis)
 	}
 	return nil, nil
 }
+
+func setupRedisLocker(c *RedisConnectOptions) (storage.AdapterLocker, error) {
+	client := GetRedisClient()
+	if client == nil {
+		options, err := c.GetRedisOptions()
+		if err != nil {
+			return nil, err
+		}
+		client = redis.NewClient(options)
+		_redis = client
+	}
+	if !c.detectCompat(client).Locker() {
+		logger.S().Warn("redis server does not support scripting, locker disabled", "addr", c.Addr)
+		return nil, nil
+	}
+	return locker.NewRedis(client), nil
+}
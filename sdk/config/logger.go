@@ -4,22 +4,67 @@ import "github.com/go-admin-team/go-admin-core/sdk/pkg/logger"
 
 type Logger struct {
 	Type      string
-	Path      string
-	Level     string
+	Path      string `validate:"required_if=Stdout file"`
+	Level     string `validate:"required,oneof=trace debug info warn error fatal"`
 	Stdout    string
 	EnabledDB bool
 	Cap       uint
+	// Sinks 同时输出到多个目标, 设置后忽略 Stdout
+	Sinks []LoggerSink `validate:"dive"`
+	// Modules 模块级别, 可在运行时通过 logger.LevelHandler 修改
+	Modules map[string]string `validate:"dive,oneof=trace debug info warn error fatal"`
+	// Redact 日志脱敏, 对所有输出生效
+	Redact *LoggerRedact
+}
+
+// LoggerRedact 日志脱敏
+type LoggerRedact struct {
+	Enabled bool
+	// Fields 追加的敏感字段名, 默认已包含 password token secret 等
+	Fields []string
+	// Patterns 内置格式名称(phone idcard jwt bankcard)或正则, 为空时使用 phone idcard jwt
+	Patterns []string
+}
+
+// LoggerSink 日志输出目标
+type LoggerSink struct {
+	Type    string `validate:"required,oneof=console file syslog loki elasticsearch kafka"`
+	Path    string `validate:"required_if=Type file"`
+	Cap     uint
+	Network string
+	Addr    string
+	Tag     string
+	Buffer  int
+	// Address Topic Labels SpillPath 用于 loki elasticsearch kafka
+	Address   string
+	Topic     string
+	Labels    map[string]string
+	SpillPath string
 }
 
 // Setup 设置logger
 func (e Logger) Setup() {
-	logger.SetupLogger(
+	opts := []logger.Option{
 		logger.WithType(e.Type),
 		logger.WithPath(e.Path),
 		logger.WithLevel(e.Level),
 		logger.WithStdout(e.Stdout),
 		logger.WithCap(e.Cap),
-	)
+		logger.WithSinks(e.sinks()...),
+		logger.WithModules(e.Modules),
+	}
+	if e.Redact != nil && e.Redact.Enabled {
+		opts = append(opts, logger.WithRedact(e.Redact.Fields, e.Redact.Patterns))
+	}
+	logger.SetupLogger(opts...)
+}
+
+func (e Logger) sinks() []logger.Sink {
+	sinks := make([]logger.Sink, 0, len(e.Sinks))
+	for _, s := range e.Sinks {
+		sinks = append(sinks, logger.Sink(s))
+	}
+	return sinks
 }
 
 var LoggerConfig = new(Logger)
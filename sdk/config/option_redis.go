@@ -4,10 +4,16 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"strings"
+	"time"
 
 	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage/compat"
 )
 
 var _redis *redis.Client
@@ -26,65 +32,156 @@ func SetRedisClient(c *redis.Client) {
 }
 
 type RedisConnectOptions struct {
-	Network    string `yaml:"network" json:"network"`
-	Addr       string `yaml:"addr" json:"addr"`
-	Username   string `yaml:"username" json:"username"`
-	Password   string `yaml:"password" json:"password"`
-	DB         int    `yaml:"db" json:"db"`
-	PoolSize   int    `yaml:"pool_size" json:"pool_size"`
-	Tls        *Tls   `yaml:"tls" json:"tls"`
-	MaxRetries int    `yaml:"max_retries" json:"max_retries"`
+	Network string `yaml:"network" json:"network"`
+	Addr    string `yaml:"addr" json:"addr" validate:"required"`
+	// Username redis 6 ACL 用户名, 为空时使用 default 用户
+	Username string `yaml:"username" json:"username"`
+	Password string `yaml:"password" json:"password"`
+	// PasswordFile 每次建立连接时读取的密码文件, 用于密码轮换, 如挂载的 k8s secret; 读取失败时使用 Password
+	PasswordFile string `yaml:"password_file" json:"password_file"`
+	DB           int    `yaml:"db" json:"db" validate:"gte=0"`
+	PoolSize     int    `yaml:"pool_size" json:"pool_size" validate:"gte=0"`
+	Tls          *Tls   `yaml:"tls" json:"tls"`
+	MaxRetries   int    `yaml:"max_retries" json:"max_retries"`
+	// MinIdleConns 保持的最少空闲连接数, 不能大于 PoolSize
+	MinIdleConns int `yaml:"min_idle_conns" json:"min_idle_conns" validate:"gte=0"`
+	// PoolTimeout 连接池已满时等待空闲连接的时间(秒), 0为 ReadTimeout+1秒
+	PoolTimeout int `yaml:"pool_timeout" json:"pool_timeout" validate:"gte=0"`
+	// IdleTimeout 空闲连接的关闭时间(秒), 0为5分钟, -1不关闭
+	IdleTimeout int `yaml:"idle_timeout" json:"idle_timeout" validate:"gte=-1"`
+	// MaxConnAge 连接的最长使用时间(秒), 0不限制
+	MaxConnAge int `yaml:"max_conn_age" json:"max_conn_age" validate:"gte=0"`
+	// DialTimeout 建立连接的超时(秒), 0为5秒
+	DialTimeout int `yaml:"dial_timeout" json:"dial_timeout" validate:"gte=0"`
+	// ReadTimeout 读取的超时(秒), 0为3秒, -1不超时
+	ReadTimeout int `yaml:"read_timeout" json:"read_timeout" validate:"gte=-1"`
+	// WriteTimeout 写入的超时(秒), 0与 ReadTimeout 相同, -1不超时
+	WriteTimeout int `yaml:"write_timeout" json:"write_timeout" validate:"gte=-1"`
+	// Compat 连接 Dragonfly、Valkey、KeyDB 等兼容服务时开启, 启动时探测功能,
+	// 不支持消费组时队列退回内存模式, 不支持脚本时不启用锁
+	Compat bool `yaml:"compat" json:"compat"`
 }
 
 type Tls struct {
-	Cert string `yaml:"cert" json:"cert"`
-	Key  string `yaml:"key" json:"key"`
-	Ca   string `yaml:"ca" json:"ca"`
+	// Enable 只校验服务端证书时设置, 如使用公共 CA 的云 redis; 设置了 Cert 或 Ca 时自动启用
+	Enable bool   `yaml:"enable" json:"enable"`
+	Cert   string `yaml:"cert" json:"cert" validate:"required_with=Key"`
+	Key    string `yaml:"key" json:"key" validate:"required_with=Cert"`
+	// Ca 校验服务端证书的 CA, 为空时使用系统证书
+	Ca string `yaml:"ca" json:"ca"`
+	// ServerName 校验的服务端证书域名, 为空时使用 Addr 中的主机名
+	ServerName string `yaml:"server_name" json:"server_name"`
+	// InsecureSkipVerify 不校验服务端证书, 仅用于测试
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+}
+
+var redisCredentials func() (username, password string)
+
+// SetRedisCredentialsProvider 每次建立连接时获取用户名与密码, 用于从密钥服务轮换密码; 优先于 PasswordFile,
+// 需在 Setup 之前调用
+func SetRedisCredentialsProvider(f func() (username, password string)) {
+	redisCredentials = f
 }
 
+// ErrRedisMinIdleConns MinIdleConns 大于 PoolSize
+var ErrRedisMinIdleConns = errors.New("config: redis min_idle_conns must not exceed pool_size")
+
 func (e RedisConnectOptions) GetRedisOptions() (*redis.Options, error) {
+	if e.PoolSize > 0 && e.MinIdleConns > e.PoolSize {
+		return nil, ErrRedisMinIdleConns
+	}
 	r := &redis.Options{
-		Network:    e.Network,
-		Addr:       e.Addr,
-		Username:   e.Username,
-		Password:   e.Password,
-		DB:         e.DB,
-		MaxRetries: e.MaxRetries,
-		PoolSize:   e.PoolSize,
+		Network:      e.Network,
+		Addr:         e.Addr,
+		Username:     e.Username,
+		Password:     e.Password,
+		DB:           e.DB,
+		MaxRetries:   e.MaxRetries,
+		PoolSize:     e.PoolSize,
+		MinIdleConns: e.MinIdleConns,
+		PoolTimeout:  seconds(e.PoolTimeout),
+		IdleTimeout:  seconds(e.IdleTimeout),
+		MaxConnAge:   seconds(e.MaxConnAge),
+		DialTimeout:  seconds(e.DialTimeout),
+		ReadTimeout:  seconds(e.ReadTimeout),
+		WriteTimeout: seconds(e.WriteTimeout),
+	}
+	switch {
+	case redisCredentials != nil:
+		r.CredentialsProvider = redisCredentials
+	case e.PasswordFile != "":
+		r.CredentialsProvider = e.passwordFromFile
 	}
 	var err error
 	r.TLSConfig, err = getTLS(e.Tls)
 	return r, err
 }
 
+// passwordFromFile 读取最新的密码, 文件末尾的换行会被去掉
+func (e RedisConnectOptions) passwordFromFile() (string, string) {
+	b, err := ioutil.ReadFile(e.PasswordFile)
+	if err != nil {
+		logger.S().Error("read redis password file failed", "file", e.PasswordFile, "error", err)
+		return e.Username, e.Password
+	}
+	return e.Username, strings.TrimRight(string(b), "\r\n")
+}
+
+// detectCompat 未开启 Compat 时认为全部功能可用
+func (e RedisConnectOptions) detectCompat(client redis.UniversalClient) compat.Features {
+	all := compat.Features{Server: compat.Redis, Streams: true, ConsumerGroups: true, PendingIdle: true, Scripting: true}
+	if !e.Compat {
+		return all
+	}
+	f, err := compat.Detect(context.TODO(), client)
+	if err != nil {
+		logger.S().Warn("detect redis features failed", "addr", e.Addr, "error", err)
+		return all
+	}
+	logger.S().Info("redis features detected", "addr", e.Addr, "server", f.Server, "version", f.Version,
+		"queue", f.Queue(), "locker", f.Locker(), "pending_idle", f.PendingIdle)
+	return f
+}
+
+// seconds 秒转为 time.Duration, -1 保持为 -1 表示不限制
+func seconds(n int) time.Duration {
+	if n < 0 {
+		return -1
+	}
+	return time.Duration(n) * time.Second
+}
+
 func getTLS(c *Tls) (*tls.Config, error) {
-	if c != nil && c.Cert != "" {
-		// 从证书相关文件中读取和解析信息，得到证书公钥、密钥对
+	if c == nil || !c.Enable && c.Cert == "" && c.Ca == "" {
+		return nil, nil
+	}
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.Cert != "" {
+		// 客户端证书, 服务端要求双向认证时使用
 		cert, err := tls.LoadX509KeyPair(c.Cert, c.Key)
 		if err != nil {
-			fmt.Printf("tls.LoadX509KeyPair err: %v\n", err)
+			logger.S().Error("load redis tls key pair failed", "cert", c.Cert, "error", err)
 			return nil, err
 		}
-		// 创建一个新的、空的 CertPool，并尝试解析 PEM 编码的证书，解析成功会将其加到 CertPool 中
-		certPool := x509.NewCertPool()
+		config.Certificates = []tls.Certificate{cert}
+	}
+	if c.Ca != "" {
+		// 校验服务端证书的根证书
 		ca, err := ioutil.ReadFile(c.Ca)
 		if err != nil {
-			fmt.Printf("ioutil.ReadFile err: %v\n", err)
+			logger.S().Error("read redis tls ca failed", "ca", c.Ca, "error", err)
 			return nil, err
 		}
-
+		certPool := x509.NewCertPool()
 		if ok := certPool.AppendCertsFromPEM(ca); !ok {
-			fmt.Println("certPool.AppendCertsFromPEM err")
-			return nil, err
+			logger.S().Error("append redis tls ca failed", "ca", c.Ca)
+			return nil, fmt.Errorf("config: no certificate found in redis tls ca %s", c.Ca)
 		}
-		return &tls.Config{
-			// 设置证书链，允许包含一个或多个
-			Certificates: []tls.Certificate{cert},
-			// 要求必须校验客户端的证书
-			ClientAuth: tls.RequireAndVerifyClientCert,
-			// 设置根证书的集合，校验方式使用 ClientAuth 中设定的模式
-			ClientCAs: certPool,
-		}, nil
+		config.RootCAs = certPool
 	}
-	return nil, nil
+	return config, nil
 }
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGetRedisOptions(t *testing.T) {
+	o, err := RedisConnectOptions{Addr: "127.0.0.1:6379", PoolSize: 20, MinIdleConns: 5,
+		PoolTimeout: 4, DialTimeout: 2, ReadTimeout: -1}.GetRedisOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.PoolSize != 20 || o.MinIdleConns != 5 || o.PoolTimeout != 4*time.Second ||
+		o.DialTimeout != 2*time.Second || o.ReadTimeout != -1 || o.WriteTimeout != 0 {
+		t.Errorf("options = %+v", o)
+	}
+	if _, err = (RedisConnectOptions{PoolSize: 2, MinIdleConns: 3}).GetRedisOptions(); !errors.Is(err, ErrRedisMinIdleConns) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRedisCredentials(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "password")
+	_ = os.WriteFile(file, []byte("v1\n"), 0600)
+	o, _ := RedisConnectOptions{Username: "app", Password: "fallback", PasswordFile: file}.GetRedisOptions()
+	if u, p := o.CredentialsProvider(); u != "app" || p != "v1" {
+		t.Errorf("credentials = %s, %s", u, p)
+	}
+	_ = os.WriteFile(file, []byte("v2"), 0600)
+	if _, p := o.CredentialsProvider(); p != "v2" {
+		t.Errorf("rotated password = %s", p)
+	}
+	_ = os.Remove(file)
+	if _, p := o.CredentialsProvider(); p != "fallback" {
+		t.Errorf("fallback password = %s", p)
+	}
+
+	SetRedisCredentialsProvider(func() (string, string) { return "vault", "secret" })
+	defer SetRedisCredentialsProvider(nil)
+	o, _ = RedisConnectOptions{PasswordFile: file}.GetRedisOptions()
+	if u, _ := o.CredentialsProvider(); u != "vault" {
+		t.Errorf("provider should take precedence, got %s", u)
+	}
+}
+
+func TestRedisTLS(t *testing.T) {
+	if c, _ := getTLS(&Tls{}); c != nil {
+		t.Error("tls should be disabled by default")
+	}
+	c, err := getTLS(&Tls{Enable: true, ServerName: "redis.example.com"})
+	if err != nil || c == nil || c.ServerName != "redis.example.com" || c.RootCAs != nil {
+		t.Errorf("config = %+v, err = %v", c, err)
+	}
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	_ = os.WriteFile(ca, []byte("not a certificate"), 0600)
+	if _, err = getTLS(&Tls{Ca: ca}); err == nil {
+		t.Error("invalid ca should fail")
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-admin-team/go-admin-core/config"
+	"github.com/go-admin-team/go-admin-core/config/source"
+	"github.com/go-admin-team/go-admin-core/config/source/env"
+	sourceFlag "github.com/go-admin-team/go-admin-core/config/source/flag"
+)
+
+// DefaultEnvPrefix 默认的环境变量前缀
+const DefaultEnvPrefix = "GO_ADMIN"
+
+// Overlay 在配置文件之上叠加环境变量和命令行参数, 优先级从低到高:
+//
+//  1. 代码中的默认值
+//  2. 配置文件 s
+//  3. 以prefix开头的环境变量, 前缀会被去掉, 下划线分隔层级, 不区分大小写
+//     GO_ADMIN_SETTINGS_APPLICATION_PORT=8080 => settings.application.port
+//     名称中含下划线的配置项使用双下划线分隔层级, 此时单个下划线保留在名称中
+//     GO_ADMIN_SETTINGS__CACHE__REDIS__POOL_SIZE=20 => settings.cache.redis.pool_size
+//  4. 命令行参数, 需在flag.Parse之后调用, 中划线分隔层级
+//     -settings-application-port=8080 => settings.application.port
+func Overlay(s source.Source, prefix string) []source.Source {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+	ss := []source.Source{
+		s,
+		env.NewSource(env.WithStrippedPrefix(prefix), env.WithSeparator("__")),
+	}
+	if flag.Parsed() {
+		ss = append(ss, sourceFlag.NewSource())
+	}
+	return ss
+}
+
+// SetupOverlay 载入配置文件, 并叠加环境变量和命令行参数, 优先级见 Overlay
+func SetupOverlay(s source.Source, prefix string,
+	fs ...func()) {
+	setup(Overlay(s, prefix), fs...)
+}
+
+// Dump 返回当前生效的配置(yaml), 包含默认值和各层覆盖后的结果; 敏感项的值替换为 config.RedactMask, 规则见 config.Sensitive
+func Dump() ([]byte, error) {
+	if _cfg == nil {
+		return nil, fmt.Errorf("config not setup")
+	}
+	b, err := yaml.Marshal(map[string]interface{}{
+		"settings": _cfg.Settings,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var settings map[string]interface{}
+	if err = yaml.Unmarshal(b, &settings); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(redact(settings))
+}
+
+// redact 替换敏感项的值, 与 config.Diff 一致只判断叶子节点的名称, 如 settings.password 下的策略仍会输出
+func redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if _, ok := child.(map[string]interface{}); !ok && child != nil && config.Sensitive(k) {
+				v[k] = config.RedactMask
+				continue
+			}
+			v[k] = redact(child)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+	}
+	return v
+}
+
+// Check 载入配置并校验, 不影响当前生效的配置, 用于发布前检查配置文件
+// 扩展配置仍会载入到 RegisterExtend 注册的对象中
+func Check(ss ...source.Source) error {
+	databases := make(map[string]*Database)
+	s := &Settings{
+		Settings: Config{
+			Application: new(Application),
+			Ssl:         new(Ssl),
+			Logger:      new(Logger),
+			Jwt:         new(Jwt),
+			Database:    new(Database),
+			Databases:   &databases,
+			Gen:         new(Gen),
+			Cache:       new(Cache),
+			Queue:       new(Queue),
+			Locker:      new(Locker),
+			Casbin:      new(Casbin),
+			Grpc:        new(Grpc),
+			Password:    new(Password),
+			Security:    new(Security),
+			Extend:      &extendSections{},
+		},
+	}
+	c, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if err = c.Load(ss...); err != nil {
+		return err
+	}
+	if err = c.Scan(s); err != nil {
+		return err
+	}
+	return Validate(&s.Settings)
+}
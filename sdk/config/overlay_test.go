@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/config"
+	"github.com/go-admin-team/go-admin-core/config/source/memory"
+)
+
+func TestOverlay(t *testing.T) {
+	data := []byte(`
+settings:
+  application:
+    host: 127.0.0.1
+    port: 8000
+    mode: dev
+`)
+	os.Setenv("GO_ADMIN_TEST_SETTINGS_APPLICATION_PORT", "9000")
+	defer os.Unsetenv("GO_ADMIN_TEST_SETTINGS_APPLICATION_PORT")
+	// 名称中含下划线的配置项
+	os.Setenv("GO_ADMIN_TEST_SETTINGS__CACHE__REDIS__POOL_SIZE", "20")
+	defer os.Unsetenv("GO_ADMIN_TEST_SETTINGS__CACHE__REDIS__POOL_SIZE")
+	os.Setenv("GO_ADMIN_TEST_SETTINGS__CACHE__REDIS__PASSWORD_FILE", "/run/secrets/redis")
+	defer os.Unsetenv("GO_ADMIN_TEST_SETTINGS__CACHE__REDIS__PASSWORD_FILE")
+
+	c, err := config.NewConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Load(Overlay(memory.NewSource(memory.WithYAML(data)), "GO_ADMIN_TEST")...); err != nil {
+		t.Fatal(err)
+	}
+	s := &Settings{Settings: Config{Application: &Application{Name: "default"}, Cache: &Cache{}}}
+	if err = c.Scan(s); err != nil {
+		t.Fatal(err)
+	}
+	app := s.Settings.Application
+	if app.Port != 9000 {
+		t.Errorf("expected env to override port, got %d", app.Port)
+	}
+	if app.Host != "127.0.0.1" || app.Mode != "dev" {
+		t.Errorf("expected file values kept, got %s %s", app.Host, app.Mode)
+	}
+	if app.Name != "default" {
+		t.Errorf("expected default value kept, got %s", app.Name)
+	}
+	if r := s.Settings.Cache.Redis; r == nil || r.PoolSize != 20 || r.PasswordFile != "/run/secrets/redis" {
+		t.Errorf("expected env to override snake_case keys, got %+v", r)
+	}
+}
+
+func TestDump(t *testing.T) {
+	prev := _cfg
+	defer func() { _cfg = prev }()
+	_cfg = &Settings{Settings: Config{
+		Application: &Application{Name: "admin"},
+		Jwt:         &Jwt{Secret: "jwt-secret", Timeout: 3600},
+		Database:    &Database{Driver: "mysql", Source: "root:pwd@tcp(db)/admin"},
+		Cache:       &Cache{Redis: &RedisConnectOptions{Addr: "redis:6379", Password: "redis-pwd"}},
+	}}
+	b, err := Dump()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(b)
+	for _, secret := range []string{"jwt-secret", "root:pwd", "redis-pwd"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("dump contains %q:\n%s", secret, out)
+		}
+	}
+	for _, kept := range []string{"admin", "mysql", "redis:6379", "3600", config.RedactMask} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("dump missing %q:\n%s", kept, out)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	ok := []byte(`
+settings:
+  application:
+    port: 8000
+  logger:
+    level: info
+`)
+	if err := Check(memory.NewSource(memory.WithYAML(ok))); err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+	bad := []byte(`
+settings:
+  application:
+    port: 70000
+    mode: unknown
+  logger:
+    level: info
+`)
+	err := Check(memory.NewSource(memory.WithYAML(bad)))
+	ve, ok2 := err.(*ValidateError)
+	if !ok2 || len(ve.Problems) != 2 {
+		t.Errorf("Check() error = %v", err)
+	}
+	if ApplicationConfig.Port == 70000 {
+		t.Error("Check() should not change the current config")
+	}
+}
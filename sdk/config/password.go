@@ -0,0 +1,71 @@
+package config
+
+import (
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/security"
+)
+
+var PasswordConfig = new(Password)
+
+// Password 密码哈希算法与复杂度、有效期策略, 未配置的项使用 security.DefaultPolicy
+type Password struct {
+	// Algorithm 新密码使用的算法, 默认 bcrypt, 修改后旧密码仍可校验
+	Algorithm string `validate:"omitempty,oneof=bcrypt argon2id"`
+	// Cost bcrypt 的 cost, 默认10
+	Cost          int `validate:"omitempty,min=4,max=31"`
+	MinLength     int `validate:"gte=0"`
+	MaxLength     int `validate:"omitempty,gtefield=MinLength"`
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// MinClasses 至少包含大写、小写、数字、符号中的几类
+	MinClasses         int `validate:"gte=0,lte=4"`
+	NotContainUsername bool
+	// History 不能与最近几次的密码相同
+	History int `validate:"gte=0"`
+	// MaxAge 密码有效期, 单位天, 0为永不过期
+	MaxAge int `validate:"gte=0"`
+	// BreachCheck 拒绝常见弱密码
+	BreachCheck bool
+}
+
+// Setup 设置 security 包的默认算法与全局策略
+func (e *Password) Setup() {
+	if e == nil {
+		return
+	}
+	switch e.Algorithm {
+	case "argon2id":
+		security.SetDefault(security.NewArgon2id())
+	default:
+		security.SetDefault(security.NewBcrypt(e.Cost))
+	}
+	security.SetPolicy(e.Policy())
+}
+
+// Policy 转换为 security.Policy
+func (e *Password) Policy() *security.Policy {
+	p := security.DefaultPolicy()
+	if e.MinLength > 0 {
+		p.MinLength = e.MinLength
+	}
+	if e.MaxLength > 0 {
+		p.MaxLength = e.MaxLength
+	}
+	if e.MinClasses > 0 {
+		p.MinClasses = e.MinClasses
+	}
+	p.RequireUpper = e.RequireUpper
+	p.RequireLower = e.RequireLower
+	p.RequireDigit = e.RequireDigit
+	p.RequireSymbol = e.RequireSymbol
+	p.NotContainUsername = e.NotContainUsername
+	p.History = e.History
+	p.MaxAge = time.Duration(e.MaxAge) * 24 * time.Hour
+	if e.BreachCheck {
+		p.Breach = security.BreachList(security.CommonPasswords...)
+	}
+	return p
+}
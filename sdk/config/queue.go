@@ -1,17 +1,25 @@
 package config
 
 import (
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
 	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/namespace"
 	"github.com/go-admin-team/go-admin-core/storage/queue"
 	"github.com/go-admin-team/redisqueue/v2"
 	"github.com/go-redis/redis/v9"
-	"time"
 )
 
 type Queue struct {
-	Redis  *QueueRedis
-	Memory *QueueMemory
-	NSQ    *QueueNSQ `json:"nsq" yaml:"nsq"`
+	// Driver 通过注册表选择驱动，为空时沿用 redis > nsq > memory 的判断
+	Driver  string `validate:"omitempty,queue_driver"`
+	Options map[string]interface{}
+	Redis   *QueueRedis
+	Memory  *QueueMemory
+	NSQ     *QueueNSQ `json:"nsq" yaml:"nsq"`
+	// Environment 环境名(dev、staging、prod), 设置后stream自动带上环境前缀, 多个环境可共用一个redis
+	Environment string
 }
 
 type QueueRedis struct {
@@ -33,34 +41,71 @@ var QueueConfig = new(Queue)
 
 // Empty 空设置
 func (e Queue) Empty() bool {
-	return e.Memory == nil && e.Redis == nil && e.NSQ == nil
+	return e.Driver == "" && e.Memory == nil && e.Redis == nil && e.NSQ == nil
 }
 
-// Setup 启用顺序 redis > 其他 > memory
+// Setup 启用顺序 driver > redis > 其他 > memory, 设置 Environment 时按环境隔离
 func (e Queue) Setup() (storage.AdapterQueue, error) {
+	q, err := e.setup()
+	if err != nil || e.Environment == "" {
+		return q, err
+	}
+	return namespace.NewQueue(q, namespace.Parse(e.Environment)), nil
+}
+
+func (e Queue) setup() (storage.AdapterQueue, error) {
+	if e.Driver != "" {
+		return storage.NewQueue(e.Driver, e.Options)
+	}
 	if e.Redis != nil {
-		e.Redis.Consumer.ReclaimInterval = e.Redis.Consumer.ReclaimInterval * time.Second
-		e.Redis.Consumer.BlockingTimeout = e.Redis.Consumer.BlockingTimeout * time.Second
-		e.Redis.Consumer.VisibilityTimeout = e.Redis.Consumer.VisibilityTimeout * time.Second
-		client := GetRedisClient()
-		if client == nil {
-			options, err := e.Redis.RedisConnectOptions.GetRedisOptions()
-			if err != nil {
-				return nil, err
-			}
-			client = redis.NewClient(options)
-			_redis = client
-		}
-		e.Redis.Producer.RedisClient = client
-		e.Redis.Consumer.RedisClient = client
-		return queue.NewRedis(e.Redis.Producer, e.Redis.Consumer)
+		return setupRedisQueue(e.Redis)
 	}
 	if e.NSQ != nil {
-		cfg, err := e.NSQ.GetNSQOptions()
+		return setupNSQQueue(e.NSQ)
+	}
+	return setupMemoryQueue(e.Memory)
+}
+
+func setupRedisQueue(c *QueueRedis) (storage.AdapterQueue, error) {
+	if c.Producer == nil {
+		c.Producer = &redisqueue.ProducerOptions{}
+	}
+	if c.Consumer == nil {
+		c.Consumer = &redisqueue.ConsumerOptions{}
+	}
+	c.Consumer.ReclaimInterval = c.Consumer.ReclaimInterval * time.Second
+	c.Consumer.BlockingTimeout = c.Consumer.BlockingTimeout * time.Second
+	c.Consumer.VisibilityTimeout = c.Consumer.VisibilityTimeout * time.Second
+	client := GetRedisClient()
+	if client == nil {
+		options, err := c.RedisConnectOptions.GetRedisOptions()
 		if err != nil {
 			return nil, err
 		}
-		return queue.NewNSQ(e.NSQ.Addresses, cfg, e.NSQ.ChannelPrefix)
+		client = redis.NewClient(options)
+		_redis = client
+	}
+	if !c.detectCompat(client).Queue() {
+		logger.S().Warn("redis server does not support consumer groups, falling back to memory queue", "addr", c.Addr)
+		return setupMemoryQueue(nil)
+	}
+	c.Producer.RedisClient = client
+	c.Consumer.RedisClient = client
+	return queue.NewRedis(c.Producer, c.Consumer)
+}
+
+func setupNSQQueue(c *QueueNSQ) (storage.AdapterQueue, error) {
+	cfg, err := c.GetNSQOptions()
+	if err != nil {
+		return nil, err
+	}
+	return queue.NewNSQ(c.Addresses, cfg, c.ChannelPrefix)
+}
+
+func setupMemoryQueue(c *QueueMemory) (storage.AdapterQueue, error) {
+	var poolSize uint
+	if c != nil {
+		poolSize = c.PoolSize
 	}
-	return queue.NewMemory(e.Memory.PoolSize), nil
+	return queue.NewMemory(poolSize), nil
 }
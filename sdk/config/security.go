@@ -0,0 +1,40 @@
+package config
+
+// Security 跨域与安全响应头, 由 middleware.Cors、middleware.SecureHeaders 使用
+type Security struct {
+	Cors    Cors
+	Headers SecurityHeaders
+}
+
+// Cors 跨域配置
+type Cors struct {
+	Enable bool
+	// AllowOrigins 允许的来源, 支持 * 与 https://*.example.com 形式的子域通配
+	AllowOrigins []string `validate:"required_if=Enable true"`
+	// AllowMethods 默认 GET、POST、PUT、PATCH、DELETE、HEAD、OPTIONS
+	AllowMethods []string
+	// AllowHeaders 为空时允许预检请求中声明的请求头
+	AllowHeaders  []string
+	ExposeHeaders []string
+	// AllowCredentials 允许携带cookie, 来源为 * 时不生效
+	AllowCredentials bool
+	// MaxAge 预检结果的缓存时间, 单位秒
+	MaxAge int `validate:"gte=0"`
+}
+
+// SecurityHeaders 安全响应头, 未配置的项使用默认值
+type SecurityHeaders struct {
+	Enable bool
+	// ContentSecurityPolicy 为空时不设置
+	ContentSecurityPolicy string
+	// HSTSMaxAge Strict-Transport-Security 的有效期, 单位秒, 0为不设置, 只在https请求中返回
+	HSTSMaxAge            int `validate:"gte=0"`
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+	// FrameOptions 默认 SAMEORIGIN
+	FrameOptions string `validate:"omitempty,oneof=DENY SAMEORIGIN"`
+	// ReferrerPolicy 默认 strict-origin-when-cross-origin
+	ReferrerPolicy string
+}
+
+var SecurityConfig = new(Security)
@@ -1,10 +1,12 @@
 package config
 
 type Ssl struct {
-	KeyStr string
-	Pem    string
+	KeyStr string `validate:"required_if=Enable true"`
+	Pem    string `validate:"required_if=Enable true"`
 	Enable bool
 	Domain string
+	// ReloadInterval 检查证书文件变更的间隔, 单位秒
+	ReloadInterval int
 }
 
 var SslConfig = new(Ssl)
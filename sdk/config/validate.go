@@ -0,0 +1,177 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/tools/database"
+	"github.com/go-playground/validator/v10"
+)
+
+// anonymous 嵌入结构体在路径中的占位, 嵌入字段在yaml中是平铺的
+const anonymous = "~"
+
+var (
+	_validate   *validator.Validate
+	indexRegexp = regexp.MustCompile(`\[([^\]]*)\]`)
+	driverTags  = make(map[string]func() []string)
+)
+
+func init() {
+	_validate = validator.New()
+	_validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		if field.Anonymous {
+			return anonymous
+		}
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			// 配置按json解析, 字段名不区分大小写
+			name = strings.ToLower(field.Name)
+		}
+		return name
+	})
+	registerDriver("cache_driver", storage.CacheDrivers)
+	registerDriver("queue_driver", storage.QueueDrivers)
+	registerDriver("locker_driver", storage.LockerDrivers)
+	registerDriver("database_driver", database.Drivers)
+	// database_dsn 按同级的 Driver 校验dsn格式
+	_ = _validate.RegisterValidation("database_dsn", func(fl validator.FieldLevel) bool {
+		parent := reflect.Indirect(fl.Parent())
+		if parent.Kind() != reflect.Struct {
+			return true
+		}
+		driver := parent.FieldByName("Driver")
+		if !driver.IsValid() || driver.String() == "" || fl.Field().String() == "" {
+			return true
+		}
+		return database.ValidateDSN(driver.String(), fl.Field().String()) == nil
+	})
+}
+
+// registerDriver 校验驱动已在 storage 注册表中注册
+func registerDriver(tag string, drivers func() []string) {
+	_ = _validate.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+		for _, d := range drivers() {
+			if d == fl.Field().String() {
+				return true
+			}
+		}
+		return false
+	})
+	driverTags[tag] = drivers
+}
+
+// Problem 单个配置项的校验问题
+type Problem struct {
+	// Path yaml中的位置, 如 settings.cache.redis.addr
+	Path    string
+	Message string
+}
+
+// ValidateError 汇总所有不合法的配置项
+type ValidateError struct {
+	Problems []Problem
+}
+
+func (e *ValidateError) Error() string {
+	s := make([]string, 0, len(e.Problems)+1)
+	s = append(s, fmt.Sprintf("config has %d problem(s):", len(e.Problems)))
+	for _, p := range e.Problems {
+		s = append(s, fmt.Sprintf("  %s: %s", p.Path, p.Message))
+	}
+	return strings.Join(s, "\n")
+}
+
+// Validate 按validate标签校验配置, 一次返回全部问题
+// 支持 go-playground/validator 的全部规则, 常用: required, min, max, gte, lte, oneof, required_with
+// 通过 RegisterExtend 注册的扩展配置一并校验
+func Validate(c *Config) error {
+	e := &ValidateError{}
+	if err := e.add("settings", _validate.Struct(c)); err != nil {
+		return err
+	}
+	extendMux.RLock()
+	for _, key := range extendKeys() {
+		if reflect.Indirect(reflect.ValueOf(extends[key])).Kind() != reflect.Struct {
+			continue
+		}
+		if err := e.add("settings.extend."+key, _validate.Struct(extends[key])); err != nil {
+			extendMux.RUnlock()
+			return err
+		}
+	}
+	extendMux.RUnlock()
+	if len(e.Problems) == 0 {
+		return nil
+	}
+	return e
+}
+
+// add 收集校验问题, 非校验错误直接返回
+func (e *ValidateError) add(prefix string, err error) error {
+	if err == nil {
+		return nil
+	}
+	errs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+	for _, fe := range errs {
+		e.Problems = append(e.Problems, Problem{
+			Path:    path(prefix, fe.Namespace()),
+			Message: message(fe),
+		})
+	}
+	return nil
+}
+
+// path Config.cache.redis.~.addr => settings.cache.redis.addr
+func path(prefix, namespace string) string {
+	parts := strings.Split(indexRegexp.ReplaceAllString(namespace, ".$1"), ".")
+	out := []string{prefix}
+	for _, p := range parts[1:] {
+		if p != anonymous && p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, ".")
+}
+
+func hasLen(k reflect.Kind) bool {
+	return k == reflect.String || k == reflect.Slice || k == reflect.Map || k == reflect.Array
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_with":
+		return fmt.Sprintf("is required when %s is set", strings.ToLower(fe.Param()))
+	case "required_if":
+		return fmt.Sprintf("is required when %s", strings.ToLower(fe.Param()))
+	case "database_dsn":
+		return "invalid dsn for the driver"
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s], got %q", strings.ReplaceAll(fe.Param(), " ", ", "), fmt.Sprint(fe.Value()))
+	case "min", "gte":
+		if hasLen(fe.Kind()) {
+			return fmt.Sprintf("length must be >= %s", fe.Param())
+		}
+		return fmt.Sprintf("must be >= %s, got %v", fe.Param(), fe.Value())
+	case "max", "lte":
+		if hasLen(fe.Kind()) {
+			return fmt.Sprintf("length must be <= %s", fe.Param())
+		}
+		return fmt.Sprintf("must be <= %s, got %v", fe.Param(), fe.Value())
+	}
+	if drivers, ok := driverTags[fe.Tag()]; ok {
+		return fmt.Sprintf("driver %q not registered, available: [%s]", fmt.Sprint(fe.Value()), strings.Join(drivers(), ", "))
+	}
+	return fmt.Sprintf("failed on the %q rule", fe.Tag())
+}
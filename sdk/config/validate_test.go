@@ -0,0 +1,86 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	c := &Config{
+		Application: &Application{Port: 70000, Mode: "debug"},
+		Logger:      &Logger{Level: "info", Stdout: "file"},
+		Database:    &Database{Driver: "mysql"},
+		Databases: &map[string]*Database{
+			"tenant": {Driver: "oracle", Source: "dsn"},
+		},
+		Cache: &Cache{Redis: &RedisConnectOptions{}},
+		Queue: &Queue{Driver: "kafka"},
+	}
+	err := Validate(c)
+	if err == nil {
+		t.Fatal("expected validate error")
+	}
+	e, ok := err.(*ValidateError)
+	if !ok {
+		t.Fatalf("unexpected error type %T", err)
+	}
+	expected := []string{
+		"settings.application.port",
+		"settings.application.mode",
+		"settings.logger.path",
+		"settings.database.source",
+		"settings.databases.tenant.driver",
+		"settings.cache.redis.addr",
+		"settings.queue.driver",
+	}
+	paths := make(map[string]bool)
+	for _, p := range e.Problems {
+		paths[p.Path] = true
+	}
+	for _, p := range expected {
+		if !paths[p] {
+			t.Errorf("expected problem at %s, got:\n%s", p, err.Error())
+		}
+	}
+	if len(e.Problems) != len(expected) {
+		t.Errorf("expected %d problems, got:\n%s", len(expected), err.Error())
+	}
+	if !strings.Contains(err.Error(), `must be one of [dev, test, prod, demo], got "debug"`) {
+		t.Errorf("unexpected message:\n%s", err.Error())
+	}
+
+	c = &Config{
+		Application: &Application{Port: 8000, Mode: "dev"},
+		Logger:      &Logger{Level: "info"},
+		Queue:       &Queue{Driver: "memory"},
+	}
+	if err = Validate(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateDSN(t *testing.T) {
+	c := &Config{
+		Application: &Application{Port: 8000, Mode: "dev"},
+		Logger:      &Logger{Level: "info"},
+		Database: &Database{
+			Driver:   "postgres",
+			Source:   "root:secret@tcp(127.0.0.1:3306)/admin",
+			Replicas: []string{"host=10.0.0.2 dbname=admin", "postgres://"},
+			Registers: []DBResolverConfig{
+				{Driver: "clickhouse", Sources: []string{"clickhouse://10.0.0.3:9000/analytics"}},
+			},
+		},
+	}
+	err := Validate(c)
+	e, ok := err.(*ValidateError)
+	if !ok || len(e.Problems) != 2 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.Problems[0].Path != "settings.database.source" || e.Problems[1].Path != "settings.database.replicas.1" {
+		t.Errorf("unexpected problems:\n%s", err.Error())
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("dsn leaked:\n%s", err.Error())
+	}
+}
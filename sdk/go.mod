@@ -8,6 +8,7 @@ require (
 	github.com/casbin/casbin/v2 v2.55.1
 	github.com/casbin/redis-watcher/v2 v2.2.0
 	github.com/chanxuehong/wechat v0.0.0-20211009063332-41a5c6d8b38b
+	github.com/ghodss/yaml v1.0.0
 	github.com/gin-gonic/gin v1.8.1
 	github.com/go-admin-team/go-admin-core v1.4.0
 	github.com/go-admin-team/go-admin-core/plugins/logger/zap v1.4.0
@@ -26,7 +27,10 @@ require (
 	github.com/shamsher31/goimgext v1.0.0
 	github.com/slok/go-http-metrics v0.10.0
 	github.com/smartystreets/goconvey v1.6.4
+	github.com/spf13/cobra v1.6.0
 	golang.org/x/crypto v0.0.0-20220926161630-eccd6366d1be
+	google.golang.org/grpc v1.49.0
+	gorm.io/driver/sqlite v1.3.6
 	gorm.io/gorm v1.23.10
 )
 
@@ -50,7 +54,6 @@ require (
 	github.com/fatih/color v1.13.0 // indirect
 	github.com/fatih/structs v1.1.0 // indirect
 	github.com/fsnotify/fsnotify v1.5.4 // indirect
-	github.com/ghodss/yaml v1.0.0 // indirect
 	github.com/gin-contrib/sse v0.1.0 // indirect
 	github.com/git-chglog/git-chglog v0.15.1 // indirect
 	github.com/go-sql-driver/mysql v1.6.0 // indirect
@@ -65,6 +68,7 @@ require (
 	github.com/henrylee2cn/ameda v1.5.0 // indirect
 	github.com/huandu/xstrings v1.3.2 // indirect
 	github.com/imdario/mergo v0.3.13 // indirect
+	github.com/inconshreveable/mousetrap v1.0.1 // indirect
 	github.com/jackc/chunkreader/v2 v2.0.1 // indirect
 	github.com/jackc/pgconn v1.13.0 // indirect
 	github.com/jackc/pgio v1.0.0 // indirect
@@ -102,6 +106,7 @@ require (
 	github.com/shopspring/decimal v1.3.1 // indirect
 	github.com/smartystreets/assertions v0.0.0-20180927180507-b2de0cb4f26d // indirect
 	github.com/spf13/cast v1.5.0 // indirect
+	github.com/spf13/pflag v1.0.5 // indirect
 	github.com/trivago/tgo v1.0.7 // indirect
 	github.com/tsuyoshiwada/go-gitcmd v0.0.0-20180205145712-5f1f5f9475df // indirect
 	github.com/ugorji/go/codec v1.2.7 // indirect
@@ -117,11 +122,11 @@ require (
 	golang.org/x/term v0.0.0-20220919170432-7a66f970e087 // indirect
 	golang.org/x/text v0.3.7 // indirect
 	golang.org/x/tools v0.1.12 // indirect
+	google.golang.org/genproto v0.0.0-20220926220553-6981cbe3cfce // indirect
 	google.golang.org/protobuf v1.28.1 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gorm.io/driver/mysql v1.3.6 // indirect
 	gorm.io/driver/postgres v1.3.10 // indirect
-	gorm.io/driver/sqlite v1.3.6 // indirect
 	gorm.io/driver/sqlserver v1.3.2 // indirect
 	gorm.io/plugin/dbresolver v1.2.3 // indirect
 )
@@ -0,0 +1,261 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Encoder 构造压缩写入器, level 为压缩级别
+type Encoder func(w io.Writer, level int) (io.WriteCloser, error)
+
+var (
+	encoderMux sync.RWMutex
+	// encoders 按优先级排列, 客户端权重相同时靠前的优先
+	encoders = []namedEncoder{
+		{"gzip", func(w io.Writer, level int) (io.WriteCloser, error) {
+			return gzip.NewWriterLevel(w, level)
+		}},
+		{"deflate", func(w io.Writer, level int) (io.WriteCloser, error) {
+			return flate.NewWriter(w, level)
+		}},
+	}
+)
+
+type namedEncoder struct {
+	name string
+	f    Encoder
+}
+
+// RegisterEncoder 注册压缩算法, 后注册的优先, 如使用 github.com/andybalholm/brotli 支持 br:
+//
+//	middleware.RegisterEncoder("br", func(w io.Writer, level int) (io.WriteCloser, error) {
+//		return brotli.NewWriterLevel(w, brotli.DefaultCompression), nil
+//	})
+func RegisterEncoder(name string, f Encoder) {
+	encoderMux.Lock()
+	defer encoderMux.Unlock()
+	list := []namedEncoder{{name, f}}
+	for _, e := range encoders {
+		if e.name != name {
+			list = append(list, e)
+		}
+	}
+	encoders = list
+}
+
+type CompressOption func(*compressOptions)
+
+type compressOptions struct {
+	level   int
+	minSize int
+	types   []string
+	skipper func(c *gin.Context) bool
+}
+
+// WithCompressLevel 压缩级别, 默认 gzip.DefaultCompression
+func WithCompressLevel(level int) CompressOption {
+	return func(o *compressOptions) {
+		o.level = level
+	}
+}
+
+// WithCompressMinSize 响应体小于 n 字节时不压缩, 默认1024
+func WithCompressMinSize(n int) CompressOption {
+	return func(o *compressOptions) {
+		o.minSize = n
+	}
+}
+
+// WithCompressTypes 允许压缩的 Content-Type, 以 / 结尾时按前缀匹配, 如 text/
+func WithCompressTypes(types ...string) CompressOption {
+	return func(o *compressOptions) {
+		o.types = types
+	}
+}
+
+// WithCompressSkipper 返回true时不压缩, 如文件下载
+func WithCompressSkipper(f func(c *gin.Context) bool) CompressOption {
+	return func(o *compressOptions) {
+		o.skipper = f
+	}
+}
+
+// Compress 按 Accept-Encoding 压缩响应, 默认支持 gzip 与 deflate;
+// 响应体先缓存到 minSize, 不足时原样输出, Flush 时立即开始输出以支持 SSE
+func Compress(opts ...CompressOption) gin.HandlerFunc {
+	o := compressOptions{
+		level:   gzip.DefaultCompression,
+		minSize: 1024,
+		types: []string{
+			"text/",
+			"application/json",
+			"application/javascript",
+			"application/xml",
+			"application/x-msgpack",
+			"application/msgpack",
+			"image/svg+xml",
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(c *gin.Context) {
+		if (o.skipper != nil && o.skipper(c)) || c.Request.Method == http.MethodHead ||
+			c.GetHeader("Upgrade") != "" || c.GetHeader("Range") != "" {
+			c.Next()
+			return
+		}
+		name, enc := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if enc == nil {
+			c.Next()
+			return
+		}
+		w := &compressWriter{ResponseWriter: c.Writer, o: &o, name: name, enc: enc}
+		c.Writer = w
+		defer func() {
+			w.close()
+			c.Writer = w.ResponseWriter
+		}()
+		c.Next()
+	}
+}
+
+// negotiateEncoding 选择客户端接受且权重最高的算法, q=0 表示拒绝
+func negotiateEncoding(header string) (string, Encoder) {
+	if header == "" {
+		return "", nil
+	}
+	accept := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			if f, err := strconv.ParseFloat(params[2:], 64); err == nil {
+				q = f
+			}
+		}
+		accept[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+	encoderMux.RLock()
+	defer encoderMux.RUnlock()
+	var best namedEncoder
+	var bestQ float64
+	for _, e := range encoders {
+		q, ok := accept[e.name]
+		if !ok {
+			q, ok = accept["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = e, q
+		}
+	}
+	return best.name, best.f
+}
+
+// compressWriter 缓存响应直到确定是否压缩
+type compressWriter struct {
+	gin.ResponseWriter
+	o       *compressOptions
+	name    string
+	enc     Encoder
+	buf     bytes.Buffer
+	decided bool
+	zw      io.WriteCloser
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.zw != nil {
+			return w.zw.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+	w.buf.Write(b)
+	if w.buf.Len() >= w.o.minSize {
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide()
+	}
+	if f, ok := w.zw.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.ResponseWriter.Hijack()
+}
+
+// decide 按状态码、Content-Type 与已有的 Content-Encoding 决定是否压缩, 并写出缓存
+func (w *compressWriter) decide() error {
+	w.decided = true
+	h := w.Header()
+	if w.buf.Len() >= w.o.minSize && h.Get("Content-Encoding") == "" && w.compressible(h.Get("Content-Type")) {
+		switch w.Status() {
+		case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+		default:
+			zw, err := w.enc(w.ResponseWriter, w.o.level)
+			if err != nil {
+				return err
+			}
+			w.zw = zw
+			h.Set("Content-Encoding", w.name)
+			h.Del("Content-Length")
+		}
+	}
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	b := w.buf.Bytes()
+	w.buf = bytes.Buffer{}
+	if w.zw != nil {
+		_, err := w.zw.Write(b)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(b)
+	return err
+}
+
+func (w *compressWriter) compressible(contentType string) bool {
+	if contentType == "" {
+		return false
+	}
+	contentType = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	for _, t := range w.o.types {
+		if strings.HasSuffix(t, "/") && strings.HasPrefix(contentType, t) || contentType == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *compressWriter) close() {
+	if !w.decided {
+		_ = w.decide()
+	}
+	if w.zw != nil {
+		_ = w.zw.Close()
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCompress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	large := strings.Repeat("go-admin ", 200)
+	r := gin.New()
+	r.Use(Compress())
+	r.GET("/large", func(c *gin.Context) { c.String(http.StatusOK, large) })
+	r.GET("/small", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	r.GET("/png", func(c *gin.Context) { c.Data(http.StatusOK, "image/png", []byte(large)) })
+
+	do := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept-Encoding", accept)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("/large", "gzip, deflate, br")
+	if w.Header().Get("Content-Encoding") != "gzip" || w.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("header = %v", w.Header())
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := io.ReadAll(zr); string(b) != large {
+		t.Errorf("body length = %d", len(b))
+	}
+
+	if w = do("/large", "gzip;q=0.5, deflate"); w.Header().Get("Content-Encoding") != "deflate" {
+		t.Errorf("q value: encoding = %q", w.Header().Get("Content-Encoding"))
+	}
+	if w = do("/large", "gzip;q=0"); w.Header().Get("Content-Encoding") != "" || w.Body.String() != large {
+		t.Errorf("q=0: encoding = %q", w.Header().Get("Content-Encoding"))
+	}
+	if w = do("/large", ""); w.Header().Get("Content-Encoding") != "" || w.Body.String() != large {
+		t.Errorf("no accept: encoding = %q", w.Header().Get("Content-Encoding"))
+	}
+	if w = do("/small", "gzip"); w.Header().Get("Content-Encoding") != "" || w.Body.String() != "ok" {
+		t.Errorf("small: encoding = %q, body = %q", w.Header().Get("Content-Encoding"), w.Body.String())
+	}
+	if w = do("/png", "gzip"); w.Header().Get("Content-Encoding") != "" || w.Body.Len() != len(large) {
+		t.Errorf("png: encoding = %q", w.Header().Get("Content-Encoding"))
+	}
+}
@@ -0,0 +1,256 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+const (
+	// HeaderIdempotencyKey 客户端生成的幂等键, 同一次提交重试时保持不变
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed 响应来自缓存时返回 true
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+type IdempotencyOption func(*idempotencyOptions)
+
+type idempotencyOptions struct {
+	header      string
+	prefix      string
+	ttl         time.Duration
+	lockTTL     time.Duration
+	maxBody     int
+	maxRequest  int64
+	methods     map[string]bool
+	scope       func(c *gin.Context) string
+	cacheStatus func(status int) bool
+}
+
+// WithIdempotencyHeader 幂等键请求头, 默认 Idempotency-Key
+func WithIdempotencyHeader(name string) IdempotencyOption {
+	return func(o *idempotencyOptions) {
+		o.header = name
+	}
+}
+
+// WithIdempotencyPrefix 缓存key前缀, 默认 idempotency:
+func WithIdempotencyPrefix(prefix string) IdempotencyOption {
+	return func(o *idempotencyOptions) {
+		o.prefix = prefix
+	}
+}
+
+// WithIdempotencyTTL 响应缓存时间, 默认24小时
+func WithIdempotencyTTL(d time.Duration) IdempotencyOption {
+	return func(o *idempotencyOptions) {
+		o.ttl = d
+	}
+}
+
+// WithIdempotencyLockTTL 处理中标记的有效期, 应大于接口最长耗时, 默认1分钟
+func WithIdempotencyLockTTL(d time.Duration) IdempotencyOption {
+	return func(o *idempotencyOptions) {
+		o.lockTTL = d
+	}
+}
+
+// WithIdempotencyMaxBody 可缓存的最大响应字节数, 超出时不缓存, 默认1MB
+func WithIdempotencyMaxBody(n int) IdempotencyOption {
+	return func(o *idempotencyOptions) {
+		o.maxBody = n
+	}
+}
+
+// WithIdempotencyMaxRequest 参与指纹计算的最大请求体字节数, 超出时返回 413, 默认10MB
+func WithIdempotencyMaxRequest(n int64) IdempotencyOption {
+	return func(o *idempotencyOptions) {
+		o.maxRequest = n
+	}
+}
+
+// WithIdempotencyMethods 生效的请求方法, 默认 POST、PUT、PATCH、DELETE
+func WithIdempotencyMethods(methods ...string) IdempotencyOption {
+	return func(o *idempotencyOptions) {
+		o.methods = make(map[string]bool, len(methods))
+		for _, m := range methods {
+			o.methods[m] = true
+		}
+	}
+}
+
+// WithIdempotencyScope 幂等键的隔离范围, 默认按操作人隔离, 匿名请求按客户端ip隔离, 避免不同用户的键互相命中
+func WithIdempotencyScope(f func(c *gin.Context) string) IdempotencyOption {
+	return func(o *idempotencyOptions) {
+		o.scope = f
+	}
+}
+
+// WithIdempotencyCacheStatus 需要缓存的响应状态码, 默认只缓存 2xx, 其余响应会释放幂等键允许重试
+func WithIdempotencyCacheStatus(f func(status int) bool) IdempotencyOption {
+	return func(o *idempotencyOptions) {
+		o.cacheStatus = f
+	}
+}
+
+// idempotencyRecord 缓存的处理状态与响应
+type idempotencyRecord struct {
+	Done        bool   `json:"done"`
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Idempotency 按 Idempotency-Key 去重, 首次请求的响应缓存后, 有效期内相同键的请求直接返回缓存的响应;
+// 处理中的重复请求返回 409, 相同键但请求内容不同时返回 422; 未携带幂等键的请求不受影响。
+// 处理前以 cache.SetNX 占用幂等键, 缓存未实现 storage.AdapterSetNXCache 时仅在当前进程内互斥
+func Idempotency(cache storage.AdapterCache, opts ...IdempotencyOption) gin.HandlerFunc {
+	o := idempotencyOptions{
+		header:     HeaderIdempotencyKey,
+		prefix:     "idempotency:",
+		ttl:        24 * time.Hour,
+		lockTTL:    time.Minute,
+		maxBody:    1 << 20,
+		maxRequest: 10 << 20,
+		methods: map[string]bool{
+			http.MethodPost:   true,
+			http.MethodPut:    true,
+			http.MethodPatch:  true,
+			http.MethodDelete: true,
+		},
+		scope: func(c *gin.Context) string {
+			if id := logger.OperatorID(c.Request.Context()); id != "" {
+				return id
+			}
+			return "ip:" + c.ClientIP()
+		},
+		cacheStatus: func(status int) bool {
+			return status >= 200 && status < 300
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	log := logger.Module("sdk.idempotency")
+	return func(c *gin.Context) {
+		key := c.GetHeader(o.header)
+		if key == "" || !o.methods[c.Request.Method] {
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"code": http.StatusBadRequest,
+				"msg":  "幂等键过长",
+			})
+			return
+		}
+		var body []byte
+		if c.Request.Body != nil {
+			body, _ = io.ReadAll(io.LimitReader(c.Request.Body, o.maxRequest+1))
+			if int64(len(body)) > o.maxRequest {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+					"code": http.StatusRequestEntityTooLarge,
+					"msg":  "请求体过大",
+				})
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		sum := sha256.Sum256(append([]byte(c.Request.Method+" "+c.Request.URL.RequestURI()+"\n"), body...))
+		fingerprint := hex.EncodeToString(sum[:])
+		cacheKey := o.prefix + o.scope(c) + ":" + key
+
+		ok, err := reserveIdempotencyKey(cache, cacheKey, fingerprint, o.lockTTL)
+		if err != nil {
+			// 无法占用幂等键时拒绝请求, 否则重复提交会被执行多次
+			log.WithContext(c.Request.Context()).Error("idempotency lock failed", "key", key, "error", err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"code": http.StatusServiceUnavailable,
+				"msg":  "服务暂不可用, 请稍后重试",
+			})
+			return
+		}
+		if !ok {
+			replayIdempotency(c, cache, cacheKey, fingerprint)
+			return
+		}
+
+		w := &bodyWriter{ResponseWriter: c.Writer, max: o.maxBody + 1}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		status := w.Status()
+		if !o.cacheStatus(status) || w.body.Len() > o.maxBody {
+			if err := cache.Del(cacheKey); err != nil {
+				log.WithContext(c.Request.Context()).Warn("idempotency release failed", "key", key, "error", err)
+			}
+			return
+		}
+		err = setIdempotencyRecord(cache, cacheKey, &idempotencyRecord{
+			Done:        true,
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: w.Header().Get("Content-Type"),
+			Body:        w.body.Bytes(),
+		}, o.ttl)
+		if err != nil {
+			log.WithContext(c.Request.Context()).Warn("idempotency save failed", "key", key, "error", err)
+		}
+	}
+}
+
+// reserveIdempotencyKey 写入处理中标记, 幂等键已被占用时返回 false
+func reserveIdempotencyKey(c storage.AdapterCache, key, fingerprint string, ttl time.Duration) (bool, error) {
+	b, err := json.Marshal(&idempotencyRecord{Fingerprint: fingerprint})
+	if err != nil {
+		return false, err
+	}
+	return cache.SetNX(c, key, string(b), int(ttl/time.Second))
+}
+
+// replayIdempotency 幂等键已被占用: 内容不同返回 422, 处理中返回 409, 已完成返回缓存的响应
+func replayIdempotency(c *gin.Context, cache storage.AdapterCache, key, fingerprint string) {
+	var r idempotencyRecord
+	v, _ := cache.Get(key)
+	if v == "" || json.Unmarshal([]byte(v), &r) != nil {
+		// 占用后已释放或过期, 按处理中返回, 由客户端重试
+		r = idempotencyRecord{Fingerprint: fingerprint}
+	}
+	switch {
+	case r.Fingerprint != fingerprint:
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"code": http.StatusUnprocessableEntity,
+			"msg":  "幂等键已被其他请求使用",
+		})
+	case !r.Done:
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"code": http.StatusConflict,
+			"msg":  "请求正在处理中, 请勿重复提交",
+		})
+	default:
+		c.Header(HeaderIdempotentReplayed, "true")
+		c.Data(r.Status, r.ContentType, r.Body)
+		c.Abort()
+	}
+}
+
+func setIdempotencyRecord(cache storage.AdapterCache, key string, r *idempotencyRecord, ttl time.Duration) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return cache.Set(key, string(b), int(ttl/time.Second))
+}
@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+	"github.com/go-admin-team/go-admin-core/storage/storagemock"
+)
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var created, failed int
+	r := gin.New()
+	r.Use(Idempotency(cache.NewMemory()))
+	r.POST("/orders", func(c *gin.Context) {
+		created++
+		c.JSON(http.StatusOK, gin.H{"id": created})
+	})
+	r.POST("/fail", func(c *gin.Context) {
+		failed++
+		c.JSON(http.StatusInternalServerError, gin.H{"msg": "error"})
+	})
+
+	do := func(path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(HeaderIdempotencyKey, key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := do("/orders", "k1", `{"a":1}`)
+	second := do("/orders", "k1", `{"a":1}`)
+	if created != 1 {
+		t.Fatalf("created = %d", created)
+	}
+	if second.Code != http.StatusOK || second.Body.String() != first.Body.String() ||
+		second.Header().Get(HeaderIdempotentReplayed) != "true" ||
+		second.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Errorf("replay: code = %d, body = %s, header = %v", second.Code, second.Body.String(), second.Header())
+	}
+	if first.Header().Get(HeaderIdempotentReplayed) != "" {
+		t.Error("first response should not be marked as replayed")
+	}
+
+	if w := do("/orders", "k1", `{"a":2}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("mismatch: code = %d", w.Code)
+	}
+
+	do("/orders", "", `{"a":1}`)
+	do("/orders", "", `{"a":1}`)
+	if created != 3 {
+		t.Errorf("without key: created = %d", created)
+	}
+
+	// 失败的响应不缓存, 允许重试
+	do("/fail", "k2", "")
+	do("/fail", "k2", "")
+	if failed != 2 {
+		t.Errorf("failed = %d", failed)
+	}
+}
+
+func TestIdempotencyProcessing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Idempotency(cache.NewMemory()))
+	var inner *httptest.ResponseRecorder
+	r.POST("/orders", func(c *gin.Context) {
+		if inner == nil {
+			// 处理过程中到达的重复请求
+			req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+			req.Header.Set(HeaderIdempotencyKey, "k1")
+			inner = httptest.NewRecorder()
+			r.ServeHTTP(inner, req)
+		}
+		c.Status(http.StatusCreated)
+	})
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set(HeaderIdempotencyKey, "k1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated || inner.Code != http.StatusConflict {
+		t.Errorf("code = %d, inner = %d", w.Code, inner.Code)
+	}
+}
+
+func TestIdempotencyConcurrent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var created int32
+	release := make(chan struct{})
+	r := gin.New()
+	r.Use(Idempotency(cache.NewMemory()))
+	r.POST("/orders", func(c *gin.Context) {
+		atomic.AddInt32(&created, 1)
+		<-release
+		c.Status(http.StatusCreated)
+	})
+
+	codes := make(chan int, 20)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"a":1}`))
+			req.Header.Set(HeaderIdempotencyKey, "k1")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			codes <- w.Code
+		}()
+	}
+	// 除占用幂等键的请求外, 其余请求都应立即返回 409
+	for i := 0; i < 19; i++ {
+		if code := <-codes; code != http.StatusConflict {
+			t.Errorf("code = %d, want 409", code)
+		}
+	}
+	close(release)
+	wg.Wait()
+	if code := <-codes; code != http.StatusCreated || created != 1 {
+		t.Errorf("code = %d, created = %d", code, created)
+	}
+}
+
+func TestIdempotencyScopeAndLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var created int
+	r := gin.New()
+	r.Use(Idempotency(cache.NewMemory(), WithIdempotencyMaxRequest(8)))
+	r.POST("/orders", func(c *gin.Context) {
+		created++
+		c.Status(http.StatusCreated)
+	})
+	do := func(ip, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+		req.Header.Set(HeaderIdempotencyKey, "k1")
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// 匿名请求按客户端ip隔离
+	do("10.0.0.1", "{}")
+	if w := do("10.0.0.2", "{}"); w.Code != http.StatusCreated || created != 2 {
+		t.Errorf("other ip: code = %d, created = %d", w.Code, created)
+	}
+	if w := do("10.0.0.1", "{}"); w.Header().Get(HeaderIdempotentReplayed) != "true" || created != 2 {
+		t.Errorf("same ip: header = %v, created = %d", w.Header(), created)
+	}
+
+	if w := do("10.0.0.3", `{"a":"too long"}`); w.Code != http.StatusRequestEntityTooLarge || created != 2 {
+		t.Errorf("large body: code = %d, created = %d", w.Code, created)
+	}
+}
+
+func TestIdempotencyCacheError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var created int
+	r := gin.New()
+	r.Use(Idempotency(&storagemock.Cache{GetFunc: func(string) (string, error) {
+		return "", errors.New("connection refused")
+	}}))
+	r.POST("/orders", func(c *gin.Context) {
+		created++
+		c.Status(http.StatusCreated)
+	})
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set(HeaderIdempotencyKey, "k1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable || created != 0 {
+		t.Errorf("code = %d, created = %d", w.Code, created)
+	}
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/jwtauth"
+	"github.com/go-admin-team/go-admin-core/storage/ratelimit"
+)
+
+// RateLimitKeyFunc 限流维度, 返回空字符串时不限流
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// RateLimitByIP 按客户端ip限流
+func RateLimitByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimitByUser 按jwt中的用户限流, 未登录时按ip
+func RateLimitByUser(c *gin.Context) string {
+	if id, err := jwtauth.ExtractClaims(c).Identity(); err == nil {
+		return "user:" + strconv.FormatInt(id, 10)
+	}
+	return RateLimitByIP(c)
+}
+
+// RateLimitByRoute 按路由限流, 所有调用方共享额度
+func RateLimitByRoute(c *gin.Context) string {
+	return "route:" + c.Request.Method + " " + c.FullPath()
+}
+
+type RateLimitOption func(*rateLimitOptions)
+
+type rateLimitOptions struct {
+	prefix   string
+	key      RateLimitKeyFunc
+	limit    ratelimit.Limit
+	routes   map[string]ratelimit.Limit
+	exceeded func(c *gin.Context, res ratelimit.Result)
+}
+
+// WithRateLimitKey 限流维度, 默认按ip
+func WithRateLimitKey(f RateLimitKeyFunc) RateLimitOption {
+	return func(o *rateLimitOptions) {
+		o.key = f
+	}
+}
+
+// WithRateLimitPrefix 存储key前缀, 多个限流中间件共用limiter时用于区分
+func WithRateLimitPrefix(prefix string) RateLimitOption {
+	return func(o *rateLimitOptions) {
+		o.prefix = prefix
+	}
+}
+
+// WithRouteLimit 单独设置某个路由的限制, route 为 "GET /api/v1/user/:id" 格式, 各路由额度独立
+func WithRouteLimit(route string, limit ratelimit.Limit) RateLimitOption {
+	return func(o *rateLimitOptions) {
+		o.routes[route] = limit
+	}
+}
+
+// WithRateLimitExceeded 自定义超出限制时的响应, 需要调用 c.Abort
+func WithRateLimitExceeded(f func(c *gin.Context, res ratelimit.Result)) RateLimitOption {
+	return func(o *rateLimitOptions) {
+		o.exceeded = f
+	}
+}
+
+// RateLimit 限流, 响应头带上 RateLimit-Limit、RateLimit-Remaining、RateLimit-Reset,
+// 超出限制时带上 Retry-After; limiter 出错时放行
+func RateLimit(limiter ratelimit.Limiter, limit ratelimit.Limit, opts ...RateLimitOption) gin.HandlerFunc {
+	o := rateLimitOptions{
+		prefix:   "ratelimit:",
+		key:      RateLimitByIP,
+		limit:    limit,
+		routes:   make(map[string]ratelimit.Limit),
+		exceeded: rateLimitExceeded,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(c *gin.Context) {
+		key := o.key(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+		l := o.limit
+		route := c.Request.Method + " " + c.FullPath()
+		if rl, ok := o.routes[route]; ok {
+			l = rl
+			key += ":" + route
+		}
+		if l.IsZero() {
+			c.Next()
+			return
+		}
+		res, err := limiter.Allow(c.Request.Context(), o.prefix+key, l)
+		if err != nil {
+			logger.Module("sdk.ratelimit").WithContext(c.Request.Context()).
+				Warn("rate limiter failed, request allowed", "limiter", limiter.String(), "error", err)
+			c.Next()
+			return
+		}
+		c.Header("RateLimit-Limit", strconv.Itoa(l.Rate))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", seconds(res.ResetAfter))
+		if !res.Allowed {
+			c.Header("Retry-After", seconds(res.RetryAfter))
+			o.exceeded(c, res)
+			return
+		}
+		c.Next()
+	}
+}
+
+func rateLimitExceeded(c *gin.Context, _ ratelimit.Result) {
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+		"code": http.StatusTooManyRequests,
+		"msg":  "请求过于频繁，请稍后再试",
+	})
+}
+
+// seconds 向上取整的秒数
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/storage/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RateLimit(ratelimit.NewMemory(), ratelimit.PerMinute(2),
+		WithRouteLimit("GET /login", ratelimit.PerMinute(1))))
+	r.GET("/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	if w := do("/users"); w.Code != http.StatusOK || w.Header().Get("RateLimit-Remaining") != "1" {
+		t.Fatalf("code = %d, remaining = %s", w.Code, w.Header().Get("RateLimit-Remaining"))
+	}
+	if w := do("/login"); w.Code != http.StatusOK {
+		t.Fatalf("route limit should be independent, code = %d", w.Code)
+	}
+	if w := do("/login"); w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("code = %d, retry-after = %s", w.Code, w.Header().Get("Retry-After"))
+	}
+	if w := do("/users"); w.Code != http.StatusOK {
+		t.Errorf("code = %d", w.Code)
+	}
+	if w := do("/users"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("code = %d", w.Code)
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/recovery"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+)
+
+// Recovery 捕获接口中的 panic, 记录堆栈并通过 recovery.AddReporter 添加的方式上报,
+// 返回 response.ErrInternal; 放在 Trace 之后, 日志与上报才会带上链路字段
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			recovery.Capture(c.Request.Context(), recovery.SourceHTTP, v,
+				"method", c.Request.Method, "path", c.Request.URL.Path, "route", c.FullPath())
+			// 连接已断开或已经写入响应时不再写
+			if err, ok := v.(error); ok && (errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)) {
+				c.Abort()
+				return
+			}
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			response.Fail(c, response.ErrInternal)
+			c.Abort()
+		}()
+		c.Next()
+	}
+}
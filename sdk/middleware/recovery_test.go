@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Recovery())
+	r.GET("/panic", func(*gin.Context) { panic("boom") })
+	r.GET("/written", func(c *gin.Context) {
+		c.String(http.StatusOK, "partial")
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	res := struct {
+		Code int `json:"code"`
+	}{}
+	_ = json.Unmarshal(w.Body.Bytes(), &res)
+	if w.Code != http.StatusInternalServerError || res.Code != 500 {
+		t.Errorf("code = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/written", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "partial" {
+		t.Errorf("code = %d, body = %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,237 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/audit"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+// RequestLogStream 请求日志的默认队列名
+const RequestLogStream = "request_log"
+
+// RequestLogEntry 一次请求的日志内容, 发送到队列时以json保存在 values 的 log 中
+type RequestLogEntry struct {
+	Method       string        `json:"method"`
+	Path         string        `json:"path"`
+	Route        string        `json:"route"`
+	Query        string        `json:"query,omitempty"`
+	Status       int           `json:"status"`
+	Latency      time.Duration `json:"latency"`
+	IP           string        `json:"ip"`
+	UserAgent    string        `json:"userAgent"`
+	OperatorID   string        `json:"operatorId,omitempty"`
+	RequestID    string        `json:"requestId,omitempty"`
+	TraceID      string        `json:"traceId,omitempty"`
+	RequestBody  string        `json:"requestBody,omitempty"`
+	ResponseBody string        `json:"responseBody,omitempty"`
+	Time         time.Time     `json:"time"`
+}
+
+type RequestLogOption func(*requestLogOptions)
+
+type requestLogOptions struct {
+	requestBody  int
+	responseBody int
+	redactor     *logger.Redactor
+	skipper      func(c *gin.Context) bool
+	queue        storage.AdapterQueue
+	stream       string
+	audit        bool
+}
+
+// WithLogRequestBody 记录请求体, 超过 max 字节的部分截断, 0为不记录
+func WithLogRequestBody(max int) RequestLogOption {
+	return func(o *requestLogOptions) {
+		o.requestBody = max
+	}
+}
+
+// WithLogResponseBody 记录响应体, 超过 max 字节的部分截断, 0为不记录
+func WithLogResponseBody(max int) RequestLogOption {
+	return func(o *requestLogOptions) {
+		o.responseBody = max
+	}
+}
+
+// WithLogRedactor 请求体、响应体与查询参数的脱敏规则, 默认脱敏 logger.RedactFields 及手机号、身份证号、jwt
+func WithLogRedactor(r *logger.Redactor) RequestLogOption {
+	return func(o *requestLogOptions) {
+		o.redactor = r
+	}
+}
+
+// WithLogSkipper 返回true时不记录, 如健康检查、静态文件
+func WithLogSkipper(f func(c *gin.Context) bool) RequestLogOption {
+	return func(o *requestLogOptions) {
+		o.skipper = f
+	}
+}
+
+// WithLogQueue 同时发送到队列, stream 为空时使用 RequestLogStream
+func WithLogQueue(q storage.AdapterQueue, stream string) RequestLogOption {
+	return func(o *requestLogOptions) {
+		o.queue = q
+		o.stream = stream
+		if o.stream == "" {
+			o.stream = RequestLogStream
+		}
+	}
+}
+
+// WithLogAudit 同时以 audit.ActionRequest 写入审计, 需要先 audit.SetSink
+func WithLogAudit() RequestLogOption {
+	return func(o *requestLogOptions) {
+		o.audit = true
+	}
+}
+
+// RequestLog 记录请求日志, 5xx 为 Error, 4xx 为 Warn, 其余为 Info;
+// 放在 Trace 与鉴权中间件之后, 日志才会带上链路字段与操作人
+func RequestLog(opts ...RequestLogOption) gin.HandlerFunc {
+	var o requestLogOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.redactor == nil {
+		o.redactor = logger.NewRedactor(logger.RedactFields,
+			logger.RedactPatterns["phone"], logger.RedactPatterns["idcard"], logger.RedactPatterns["jwt"])
+	}
+	log := logger.Module("sdk.request")
+	return func(c *gin.Context) {
+		if o.skipper != nil && o.skipper(c) {
+			c.Next()
+			return
+		}
+		start := time.Now()
+		var reqBody []byte
+		if o.requestBody > 0 && c.Request.Body != nil {
+			reqBody, _ = io.ReadAll(io.LimitReader(c.Request.Body, int64(o.requestBody)))
+			c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(reqBody), c.Request.Body), c.Request.Body}
+		}
+		var w *bodyWriter
+		if o.responseBody > 0 {
+			w = &bodyWriter{ResponseWriter: c.Writer, max: o.responseBody}
+			c.Writer = w
+		}
+
+		c.Next()
+
+		ctx := c.Request.Context()
+		e := &RequestLogEntry{
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			Route:      c.FullPath(),
+			Query:      o.redactor.RedactString(c.Request.URL.RawQuery),
+			Status:     c.Writer.Status(),
+			Latency:    time.Since(start),
+			IP:         c.ClientIP(),
+			UserAgent:  c.Request.UserAgent(),
+			OperatorID: logger.OperatorID(ctx),
+			RequestID:  logger.RequestID(ctx),
+			TraceID:    logger.TraceID(ctx),
+			Time:       start,
+		}
+		if len(reqBody) > 0 {
+			e.RequestBody = string(o.redactor.Redact(reqBody))
+		}
+		if w != nil && w.body.Len() > 0 {
+			e.ResponseBody = string(o.redactor.Redact(w.body.Bytes()))
+		}
+
+		kv := []interface{}{
+			"method", e.Method, "path", e.Path, "route", e.Route, "status", e.Status,
+			"latency", e.Latency.String(), "ip", e.IP,
+		}
+		if e.Query != "" {
+			kv = append(kv, "query", e.Query)
+		}
+		if e.RequestBody != "" {
+			kv = append(kv, "request_body", e.RequestBody)
+		}
+		if e.ResponseBody != "" {
+			kv = append(kv, "response_body", e.ResponseBody)
+		}
+		if len(c.Errors) > 0 {
+			kv = append(kv, "error", c.Errors.String())
+		}
+		l := log.WithContext(ctx)
+		switch {
+		case e.Status >= 500:
+			l.Error("request", kv...)
+		case e.Status >= 400:
+			l.Warn("request", kv...)
+		default:
+			l.Info("request", kv...)
+		}
+
+		if o.queue != nil {
+			publishRequestLog(o.queue, o.stream, c, e)
+		}
+		if o.audit {
+			_ = audit.Write(ctx, &audit.Record{
+				Action:    audit.ActionRequest,
+				Resource:  e.Route,
+				Method:    e.Method,
+				Path:      e.Path,
+				IP:        e.IP,
+				UserAgent: e.UserAgent,
+				Status:    e.Status,
+				After:     e.RequestBody,
+			})
+		}
+	}
+}
+
+func publishRequestLog(q storage.AdapterQueue, stream string, c *gin.Context, e *RequestLogEntry) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	m := new(queue.Message)
+	m.SetStream(stream)
+	m.SetValues(map[string]interface{}{"log": string(b)})
+	queue.InjectTrace(c.Request.Context(), m)
+	if err = q.Append(m); err != nil {
+		logger.Module("sdk.request").WithContext(c.Request.Context()).
+			Warn("request log publish failed", "stream", stream, "error", err)
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyWriter 写响应的同时保留前 max 字节
+type bodyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+	max  int
+}
+
+func (w *bodyWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyWriter) capture(b []byte) {
+	if n := w.max - w.body.Len(); n > 0 {
+		if len(b) > n {
+			b = b[:n]
+		}
+		w.body.Write(b)
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+func TestRequestLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	q := queue.NewMemory(10)
+	entries := make(chan RequestLogEntry, 1)
+	q.Register(RequestLogStream, func(m storage.Messager) error {
+		var e RequestLogEntry
+		_ = json.Unmarshal([]byte(m.GetValues()["log"].(string)), &e)
+		entries <- e
+		return nil
+	})
+	go q.Run()
+
+	r := gin.New()
+	r.Use(RequestLog(WithLogRequestBody(64), WithLogResponseBody(8), WithLogQueue(q, "")))
+	var body string
+	r.POST("/login", func(c *gin.Context) {
+		b, _ := c.GetRawData()
+		body = string(b)
+		c.String(http.StatusOK, "0123456789")
+	})
+	req := httptest.NewRequest(http.MethodPost, "/login",
+		strings.NewReader(`{"username":"admin","password":"123456"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if body != `{"username":"admin","password":"123456"}` || w.Body.String() != "0123456789" {
+		t.Fatalf("handler body = %s, response = %s", body, w.Body.String())
+	}
+
+	select {
+	case e := <-entries:
+		if e.Route != "/login" || e.Status != http.StatusOK {
+			t.Errorf("entry = %+v", e)
+		}
+		if strings.Contains(e.RequestBody, "123456") || !strings.Contains(e.RequestBody, "admin") {
+			t.Errorf("request body = %s", e.RequestBody)
+		}
+		if e.ResponseBody != "01234567" {
+			t.Errorf("response body = %s", e.ResponseBody)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("request log not published")
+	}
+}
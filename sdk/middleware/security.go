@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/config"
+)
+
+var defaultCorsMethods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodHead, http.MethodOptions,
+}
+
+// Security 按 settings.security 配置的跨域与安全响应头, 每次请求读取配置, 支持热更新
+func Security() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		SecureHeaders(&config.SecurityConfig.Headers),
+		Cors(&config.SecurityConfig.Cors),
+	}
+}
+
+// Cors 跨域处理, 来源不在白名单时不返回跨域头, 预检请求返回 403;
+// 来源配置为 * 时返回 Access-Control-Allow-Origin: *, 此时浏览器不会携带cookie, 需要cookie时应配置具体来源
+func Cors(e *config.Cors) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if !e.Enable || origin == "" {
+			c.Next()
+			return
+		}
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		h := c.Writer.Header()
+		h.Add("Vary", "Origin")
+		wildcard, ok := matchOrigin(e.AllowOrigins, origin)
+		if !ok {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+		if wildcard {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+			if e.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+		if !preflight {
+			if len(e.ExposeHeaders) > 0 {
+				h.Set("Access-Control-Expose-Headers", strings.Join(e.ExposeHeaders, ", "))
+			}
+			c.Next()
+			return
+		}
+		methods := e.AllowMethods
+		if len(methods) == 0 {
+			methods = defaultCorsMethods
+		}
+		h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+		if len(e.AllowHeaders) > 0 {
+			h.Set("Access-Control-Allow-Headers", strings.Join(e.AllowHeaders, ", "))
+		} else if v := c.GetHeader("Access-Control-Request-Headers"); v != "" {
+			h.Add("Vary", "Access-Control-Request-Headers")
+			h.Set("Access-Control-Allow-Headers", v)
+		}
+		if e.MaxAge > 0 {
+			h.Set("Access-Control-Max-Age", strconv.Itoa(e.MaxAge))
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// matchOrigin 返回是否允许, 以及是否由 * 匹配
+func matchOrigin(allows []string, origin string) (wildcard bool, ok bool) {
+	for _, allow := range allows {
+		switch {
+		case allow == "*":
+			wildcard = true
+		case strings.EqualFold(allow, origin):
+			return false, true
+		case strings.Contains(allow, "://*."):
+			// https://*.example.com 匹配任意子域, 不匹配 example.com 本身
+			i := strings.Index(allow, "*")
+			if len(origin) > len(allow)-1 &&
+				strings.EqualFold(origin[:i], allow[:i]) &&
+				strings.HasSuffix(strings.ToLower(origin), strings.ToLower(allow[i+1:])) {
+				return false, true
+			}
+		}
+	}
+	return wildcard, wildcard
+}
+
+// SecureHeaders 设置 X-Content-Type-Options、X-Frame-Options、Referrer-Policy, 以及配置了的 CSP 与 HSTS
+func SecureHeaders(e *config.SecurityHeaders) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !e.Enable {
+			c.Next()
+			return
+		}
+		h := c.Writer.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		frame := e.FrameOptions
+		if frame == "" {
+			frame = "SAMEORIGIN"
+		}
+		h.Set("X-Frame-Options", frame)
+		referrer := e.ReferrerPolicy
+		if referrer == "" {
+			referrer = "strict-origin-when-cross-origin"
+		}
+		h.Set("Referrer-Policy", referrer)
+		if e.ContentSecurityPolicy != "" {
+			h.Set("Content-Security-Policy", e.ContentSecurityPolicy)
+		}
+		if e.HSTSMaxAge > 0 && (c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https") {
+			v := "max-age=" + strconv.Itoa(e.HSTSMaxAge)
+			if e.HSTSIncludeSubdomains {
+				v += "; includeSubDomains"
+			}
+			if e.HSTSPreload {
+				v += "; preload"
+			}
+			h.Set("Strict-Transport-Security", v)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/config"
+)
+
+func TestCors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Cors{
+		Enable:           true,
+		AllowOrigins:     []string{"https://admin.example.com", "https://*.example.org"},
+		AllowHeaders:     []string{"Authorization", "Content-Type"},
+		ExposeHeaders:    []string{"X-Request-Id"},
+		AllowCredentials: true,
+		MaxAge:           600,
+	}
+	r := gin.New()
+	r.Use(Cors(cfg))
+	r.Any("/api", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(method, origin string, preflight bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api", nil)
+		req.Header.Set("Origin", origin)
+		if preflight {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodGet, "https://admin.example.com", false)
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://admin.example.com" ||
+		w.Header().Get("Access-Control-Allow-Credentials") != "true" ||
+		w.Header().Get("Access-Control-Expose-Headers") != "X-Request-Id" {
+		t.Errorf("simple: header = %v", w.Header())
+	}
+
+	w = do(http.MethodOptions, "https://a.example.org", true)
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://a.example.org" ||
+		w.Header().Get("Access-Control-Allow-Headers") != "Authorization, Content-Type" ||
+		w.Header().Get("Access-Control-Max-Age") != "600" ||
+		w.Header().Get("Access-Control-Allow-Methods") == "" {
+		t.Errorf("preflight: code = %d, header = %v", w.Code, w.Header())
+	}
+
+	for _, origin := range []string{"https://evil.com", "https://example.org", "http://a.example.org"} {
+		w = do(http.MethodGet, origin, false)
+		if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("%s: code = %d, header = %v", origin, w.Code, w.Header())
+		}
+		if w = do(http.MethodOptions, origin, true); w.Code != http.StatusForbidden {
+			t.Errorf("%s preflight: code = %d", origin, w.Code)
+		}
+	}
+
+	// * 不与 cookie 同时生效
+	cfg.AllowOrigins = []string{"*"}
+	w = do(http.MethodGet, "https://any.com", false)
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" || w.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Errorf("wildcard: header = %v", w.Header())
+	}
+
+	cfg.Enable = false
+	if w = do(http.MethodGet, "https://any.com", false); w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("disabled: header = %v", w.Header())
+	}
+}
+
+func TestSecureHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.SecurityHeaders{
+		Enable:                true,
+		ContentSecurityPolicy: "default-src 'self'",
+		HSTSMaxAge:            31536000,
+		HSTSIncludeSubdomains: true,
+	}
+	r := gin.New()
+	r.Use(SecureHeaders(cfg))
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	want := map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "SAMEORIGIN",
+		"Referrer-Policy":           "strict-origin-when-cross-origin",
+		"Content-Security-Policy":   "default-src 'self'",
+		"Strict-Transport-Security": "",
+	}
+	for k, v := range want {
+		if got := w.Header().Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if got := w.Header().Get("Strict-Transport-Security"); got != "max-age=31536000; includeSubDomains" {
+		t.Errorf("hsts = %q", got)
+	}
+}
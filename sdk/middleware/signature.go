@@ -0,0 +1,263 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+// 开放接口签名使用的请求头
+const (
+	HeaderAppKey    = "X-App-Key"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderNonce     = "X-Nonce"
+	HeaderSignature = "X-Signature"
+
+	// SignatureAppKey 验签通过后 gin.Context 中调用方的 appKey
+	SignatureAppKey = "signature_app_key"
+)
+
+var (
+	ErrSignatureMissing = errors.New("signature: missing headers")
+	ErrSignatureExpired = errors.New("signature: timestamp out of window")
+	ErrSignatureAppKey  = errors.New("signature: unknown app key")
+	ErrSignatureInvalid = errors.New("signature: invalid signature")
+	ErrSignatureReplay  = errors.New("signature: nonce already used")
+)
+
+// SecretFunc 根据 appKey 获取密钥, 不存在时返回空字符串
+type SecretFunc func(ctx context.Context, appKey string) (string, error)
+
+// StaticSecrets 固定的 appKey 与密钥
+func StaticSecrets(secrets map[string]string) SecretFunc {
+	return func(_ context.Context, appKey string) (string, error) {
+		return secrets[appKey], nil
+	}
+}
+
+type SignatureOption func(*signatureOptions)
+
+type signatureOptions struct {
+	cache   storage.AdapterCache
+	prefix  string
+	window  time.Duration
+	hash    func() hash.Hash
+	maxBody int64
+	failed  func(c *gin.Context, err error)
+}
+
+// WithSignatureCache 记录已使用的nonce防止重放, 多实例部署时应使用redis; 未设置时只校验时间戳
+func WithSignatureCache(c storage.AdapterCache) SignatureOption {
+	return func(o *signatureOptions) {
+		o.cache = c
+	}
+}
+
+// WithSignatureWindow 时间戳允许的误差, 默认5分钟
+func WithSignatureWindow(d time.Duration) SignatureOption {
+	return func(o *signatureOptions) {
+		o.window = d
+	}
+}
+
+// WithSignatureHash HMAC 使用的哈希, 默认 sha256, 调用方与服务端需一致
+func WithSignatureHash(h func() hash.Hash) SignatureOption {
+	return func(o *signatureOptions) {
+		o.hash = h
+	}
+}
+
+// WithSignatureMaxBody 参与签名的请求体最大字节数, 默认10MB, 超出时拒绝
+func WithSignatureMaxBody(n int64) SignatureOption {
+	return func(o *signatureOptions) {
+		o.maxBody = n
+	}
+}
+
+// WithSignatureFailed 自定义验签失败时的响应, 需要调用 c.Abort
+func WithSignatureFailed(f func(c *gin.Context, err error)) SignatureOption {
+	return func(o *signatureOptions) {
+		o.failed = f
+	}
+}
+
+func newSignatureOptions(opts []SignatureOption) signatureOptions {
+	o := signatureOptions{
+		prefix:  "signature:nonce:",
+		window:  5 * time.Minute,
+		hash:    sha256.New,
+		maxBody: 10 << 20,
+		failed:  signatureFailed,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Signature 开放接口验签, 调用方按 StringToSign 拼接待签名串, 使用 HMAC 计算后base64编码放入 X-Signature;
+// 先验签再登记nonce, 避免伪造请求占用nonce
+func Signature(secrets SecretFunc, opts ...SignatureOption) gin.HandlerFunc {
+	o := newSignatureOptions(opts)
+	return func(c *gin.Context) {
+		appKey, err := o.verify(c, secrets)
+		if err != nil {
+			logger.Module("sdk.signature").WithContext(c.Request.Context()).
+				Warn("signature verify failed", "app_key", c.GetHeader(HeaderAppKey), "path", c.Request.URL.Path, "error", err)
+			o.failed(c, err)
+			return
+		}
+		c.Set(SignatureAppKey, appKey)
+		c.Next()
+	}
+}
+
+func (o *signatureOptions) verify(c *gin.Context, secrets SecretFunc) (string, error) {
+	r := c.Request
+	appKey := r.Header.Get(HeaderAppKey)
+	timestamp := r.Header.Get(HeaderTimestamp)
+	nonce := r.Header.Get(HeaderNonce)
+	signature := r.Header.Get(HeaderSignature)
+	if appKey == "" || timestamp == "" || nonce == "" || signature == "" {
+		return "", ErrSignatureMissing
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", ErrSignatureExpired
+	}
+	if d := time.Since(time.Unix(ts, 0)); d > o.window || d < -o.window {
+		return "", ErrSignatureExpired
+	}
+	secret, err := secrets(r.Context(), appKey)
+	if err != nil {
+		return "", err
+	}
+	if secret == "" {
+		return "", ErrSignatureAppKey
+	}
+	var body []byte
+	if r.Body != nil {
+		body, err = io.ReadAll(io.LimitReader(r.Body, o.maxBody+1))
+		if err != nil {
+			return "", err
+		}
+		if int64(len(body)) > o.maxBody {
+			return "", ErrSignatureInvalid
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	want := sign(o.hash, secret, StringToSign(r.Method, r.URL.Path, r.URL.Query(), appKey, timestamp, nonce, body))
+	got, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(want, got) {
+		return "", ErrSignatureInvalid
+	}
+	if o.cache != nil {
+		key := o.prefix + appKey + ":" + nonce
+		// 时间戳在前后 window 内有效, nonce 至少保留 2*window; 判断与写入需原子, 否则并发的重放可能同时通过
+		ok, err := cache.SetNX(o.cache, key, timestamp, int(2*o.window/time.Second)+1)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "", ErrSignatureReplay
+		}
+	}
+	return appKey, nil
+}
+
+// StringToSign 待签名串, 各部分以换行分隔:
+// METHOD、PATH、按key排序并url编码的query、appKey、timestamp、nonce、请求体sha256的十六进制
+func StringToSign(method, path string, query url.Values, appKey, timestamp, nonce string, body []byte) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, url.QueryEscape(k)+"="+url.QueryEscape(v))
+		}
+	}
+	sum := sha256.Sum256(body)
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		strings.Join(pairs, "&"),
+		appKey,
+		timestamp,
+		nonce,
+		hex.EncodeToString(sum[:]),
+	}, "\n")
+}
+
+func sign(h func() hash.Hash, secret, s string) []byte {
+	mac := hmac.New(h, []byte(secret))
+	mac.Write([]byte(s))
+	return mac.Sum(nil)
+}
+
+// SignRequest 为请求添加签名头, 供调用方或测试使用, 默认 sha256; 会读取并还原请求体
+func SignRequest(r *http.Request, appKey, secret string, h ...func() hash.Hash) error {
+	hf := sha256.New
+	if len(h) > 0 {
+		hf = h[0]
+	}
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	nonce := hex.EncodeToString(b)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	s := StringToSign(r.Method, r.URL.Path, r.URL.Query(), appKey, timestamp, nonce, body)
+	r.Header.Set(HeaderAppKey, appKey)
+	r.Header.Set(HeaderTimestamp, timestamp)
+	r.Header.Set(HeaderNonce, nonce)
+	r.Header.Set(HeaderSignature, base64.StdEncoding.EncodeToString(sign(hf, secret, s)))
+	return nil
+}
+
+func signatureFailed(c *gin.Context, err error) {
+	msg := "签名错误"
+	switch {
+	case errors.Is(err, ErrSignatureMissing):
+		msg = "缺少签名参数"
+	case errors.Is(err, ErrSignatureExpired):
+		msg = "请求已过期"
+	case errors.Is(err, ErrSignatureReplay):
+		msg = "重复的请求"
+	}
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+		"code": http.StatusUnauthorized,
+		"msg":  msg,
+	})
+}
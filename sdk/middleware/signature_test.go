@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+func TestSignature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Signature(StaticSecrets(map[string]string{"app1": "secret1"}), WithSignatureCache(cache.NewMemory())))
+	r.POST("/open/orders", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, c.GetString(SignatureAppKey)+":"+string(body))
+	})
+
+	newReq := func() *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/open/orders?b=2&a=1&a=0", strings.NewReader(`{"id":1}`))
+	}
+	do := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	req := newReq()
+	if err := SignRequest(req, "app1", "secret1"); err != nil {
+		t.Fatal(err)
+	}
+	replay := req.Clone(req.Context())
+	replay.Body = io.NopCloser(strings.NewReader(`{"id":1}`))
+	if w := do(req); w.Code != http.StatusOK || w.Body.String() != `app1:{"id":1}` {
+		t.Fatalf("code = %d, body = %s", w.Code, w.Body.String())
+	}
+	if w := do(replay); w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), "重复的请求") {
+		t.Errorf("replay: code = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	// 篡改请求体
+	req = newReq()
+	_ = SignRequest(req, "app1", "secret1")
+	req.Body = io.NopCloser(strings.NewReader(`{"id":2}`))
+	if w := do(req); w.Code != http.StatusUnauthorized {
+		t.Errorf("tampered: code = %d", w.Code)
+	}
+
+	req = newReq()
+	_ = SignRequest(req, "app1", "wrong")
+	if w := do(req); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong secret: code = %d", w.Code)
+	}
+
+	req = newReq()
+	_ = SignRequest(req, "app2", "secret1")
+	if w := do(req); w.Code != http.StatusUnauthorized {
+		t.Errorf("unknown app: code = %d", w.Code)
+	}
+
+	req = newReq()
+	_ = SignRequest(req, "app1", "secret1")
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10))
+	if w := do(req); w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), "请求已过期") {
+		t.Errorf("expired: code = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	if w := do(newReq()); w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), "缺少签名参数") {
+		t.Errorf("missing: code = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSignatureConcurrentReplay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Signature(StaticSecrets(map[string]string{"app1": "secret1"}), WithSignatureCache(cache.NewMemory())))
+	r.POST("/open/orders", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/open/orders", strings.NewReader(`{"id":1}`))
+	if err := SignRequest(req, "app1", "secret1"); err != nil {
+		t.Fatal(err)
+	}
+	var passed int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		replay := req.Clone(req.Context())
+		replay.Body = io.NopCloser(strings.NewReader(`{"id":1}`))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, replay)
+			if w.Code == http.StatusOK {
+				atomic.AddInt32(&passed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if passed != 1 {
+		t.Errorf("passed = %d, want 1", passed)
+	}
+}
+
+func TestStringToSign(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/open/users?name=a+b&id=2&id=1", nil)
+	s := StringToSign(req.Method, req.URL.Path, req.URL.Query(), "app1", "1700000000", "n1", nil)
+	want := "GET\n/open/users\nid=1&id=2&name=a+b\napp1\n1700000000\nn1\ne3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	if s != want {
+		t.Errorf("got %q", s)
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg"
+)
+
+// TraceIDHeader 上游传入链路id的header, 未传时读取 W3C traceparent
+const TraceIDHeader = "X-Trace-Id"
+
+// Trace 将请求id、链路id写入 c.Request 的ctx,
+// 之后 logger.S().WithContext(c.Request.Context()) 、gorm日志、InjectTrace 后的队列消息都会带上这些字段
+func Trace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := pkg.GenerateMsgIDFromContext(c)
+		traceId := traceID(c)
+		if traceId == "" {
+			traceId = requestId
+		}
+		ctx := logger.WithRequestID(c.Request.Context(), requestId)
+		ctx = logger.WithTraceID(ctx, traceId)
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(pkg.TrafficKey, requestId)
+		c.Next()
+	}
+}
+
+// traceID traceparent 格式: version-traceid-parentid-flags
+func traceID(c *gin.Context) string {
+	if id := c.GetHeader(TraceIDHeader); id != "" {
+		return id
+	}
+	parts := strings.Split(c.GetHeader("traceparent"), "-")
+	if len(parts) == 4 && len(parts[1]) == 32 {
+		return parts[1]
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package audit
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+)
+
+// QueryHandler 审计记录查询接口, 查询参数见 Query, 需自行挂载到有鉴权的路由
+func QueryHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		q := &Query{}
+		if err := c.ShouldBindQuery(q); err != nil {
+			response.Error(c, http.StatusUnprocessableEntity, err, "参数错误")
+			return
+		}
+		list, count, err := Search(c.Request.Context(), q)
+		if err != nil {
+			code := http.StatusInternalServerError
+			if errors.Is(err, ErrNoSink) || errors.Is(err, ErrNotQueryable) {
+				code = http.StatusNotImplemented
+			}
+			response.Error(c, code, err, "")
+			return
+		}
+		response.PageOK(c, list, int(count), q.PageIndex, q.PageSize, "")
+	}
+}
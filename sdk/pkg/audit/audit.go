@@ -0,0 +1,175 @@
+// Package audit 记录操作人的操作(谁、何时、做了什么、变更前后及差异), 写入可替换的 Sink
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/ctxkit"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/tenant"
+	"github.com/go-admin-team/go-admin-core/tools/search"
+)
+
+// 常用的操作类型
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+	ActionLogin  = "login"
+	ActionLogout = "logout"
+	// ActionRequest 请求日志, 见 middleware.RequestLog
+	ActionRequest = "request"
+)
+
+var (
+	// ErrNoSink 未设置 Sink
+	ErrNoSink = errors.New("audit sink not set")
+	// ErrNotQueryable Sink 不支持查询
+	ErrNotQueryable = errors.New("audit sink is not queryable")
+)
+
+// Sink 审计记录的存储
+type Sink interface {
+	String() string
+	Write(ctx context.Context, r *Record) error
+}
+
+// Querier 支持查询的 Sink
+type Querier interface {
+	Query(ctx context.Context, q *Query) ([]Record, int64, error)
+}
+
+// Query 查询条件, 空值不作为条件; Tenant 由 Search 按ctx中的租户设置, 只能查询本租户的记录
+type Query struct {
+	Tenant     string    `form:"-"`
+	OperatorID string    `form:"operatorId"`
+	Action     string    `form:"action"`
+	Resource   string    `form:"resource"`
+	ResourceID string    `form:"resourceId"`
+	RequestID  string    `form:"requestId"`
+	Start      time.Time `form:"start" time_format:"2006-01-02 15:04:05"`
+	End        time.Time `form:"end" time_format:"2006-01-02 15:04:05"`
+	PageIndex  int       `form:"pageIndex"`
+	PageSize   int       `form:"pageSize"`
+}
+
+var (
+	mux  sync.RWMutex
+	sink Sink
+)
+
+// SetSink 设置审计记录的存储
+func SetSink(s Sink) {
+	mux.Lock()
+	defer mux.Unlock()
+	sink = s
+}
+
+// GetSink 获取审计记录的存储
+func GetSink() Sink {
+	mux.RLock()
+	defer mux.RUnlock()
+	return sink
+}
+
+// Log 记录对资源的操作, before/after 为变更前后的对象, 新增时before为nil, 删除时after为nil
+// 操作人、租户、请求信息从ctx中获取, 见 Middleware 和 logger.WithOperatorID
+func Log(ctx context.Context, action, resource, resourceID string, before, after interface{}) error {
+	r := &Record{
+		Action:     action,
+		Resource:   resource,
+		ResourceID: resourceID,
+	}
+	var err error
+	if r.Before, err = Marshal(before); err != nil {
+		return err
+	}
+	if r.After, err = Marshal(after); err != nil {
+		return err
+	}
+	if r.Changes, err = Diff(before, after); err != nil {
+		return err
+	}
+	return Write(ctx, r)
+}
+
+// Write 补充ctx中的信息后写入 Sink
+func Write(ctx context.Context, r *Record) error {
+	s := GetSink()
+	if s == nil {
+		return ErrNoSink
+	}
+	fill(ctx, r)
+	if err := s.Write(ctx, r); err != nil {
+		logger.Module("sdk.audit").WithContext(ctx).
+			Error("audit write failed", "sink", s.String(), "action", r.Action, "resource", r.Resource, "error", err)
+		return err
+	}
+	return nil
+}
+
+// tenantOf ctx中的租户, DefaultKey 记录为空
+func tenantOf(ctx context.Context) string {
+	if key := tenant.FromContext(ctx); key != tenant.DefaultKey {
+		return key
+	}
+	return ""
+}
+
+// Search 查询审计记录, Sink 需实现 Querier; 只查询ctx中租户的记录, 每页条数不超过100
+func Search(ctx context.Context, q *Query) ([]Record, int64, error) {
+	s := GetSink()
+	if s == nil {
+		return nil, 0, ErrNoSink
+	}
+	querier, ok := s.(Querier)
+	if !ok {
+		return nil, 0, ErrNotQueryable
+	}
+	q.Tenant = tenantOf(ctx)
+	p := search.Page{PageIndex: q.PageIndex, PageSize: q.PageSize}
+	p.Normalize()
+	q.PageIndex, q.PageSize = p.PageIndex, p.PageSize
+	return querier.Query(ctx, q)
+}
+
+func fill(ctx context.Context, r *Record) {
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
+	if ctx == nil {
+		return
+	}
+	if r.OperatorID == "" {
+		r.OperatorID = logger.OperatorID(ctx)
+	}
+	if r.RequestID == "" {
+		r.RequestID = logger.RequestID(ctx)
+	}
+	if r.TraceID == "" {
+		r.TraceID = logger.TraceID(ctx)
+	}
+	if r.Tenant == "" {
+		r.Tenant = tenantOf(ctx)
+	}
+	if info, ok := ctx.Value(requestKey{}).(*request); ok {
+		if r.Method == "" {
+			r.Method = info.method
+		}
+		if r.Path == "" {
+			r.Path = info.path
+		}
+		if r.IP == "" {
+			r.IP = info.ip
+		}
+		if r.UserAgent == "" {
+			r.UserAgent = info.userAgent
+		}
+	}
+	if r.IP == "" {
+		r.IP = ctxkit.ClientIP(ctx)
+	}
+}
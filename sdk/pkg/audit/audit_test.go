@@ -0,0 +1,138 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/tenant"
+)
+
+type user struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+	Roles    []int  `json:"roles"`
+}
+
+func TestLog(t *testing.T) {
+	var buf bytes.Buffer
+	SetSink(NewFileSink(&buf))
+	defer SetSink(nil)
+
+	ctx := logger.WithOperatorID(context.Background(), "1")
+	before := user{Name: "a", Password: "x", Roles: []int{1}}
+	after := user{Name: "b", Password: "y", Roles: []int{1, 2}}
+	if err := Log(ctx, ActionUpdate, "sys_user", "7", before, after); err != nil {
+		t.Fatal(err)
+	}
+
+	var r Record
+	if err := json.Unmarshal(buf.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.OperatorID != "1" || r.ResourceID != "7" || r.CreatedAt.IsZero() {
+		t.Errorf("unexpected record %+v", r)
+	}
+	if strings.Contains(r.Before+r.After, `"x"`) || strings.Contains(r.After, `"y"`) {
+		t.Errorf("password should be redacted: %s %s", r.Before, r.After)
+	}
+	want := Changes{
+		{Path: "name", Old: "a", New: "b"},
+		{Path: "password", Old: RedactMask, New: RedactMask},
+		{Path: "roles.1", New: float64(2)},
+	}
+	got, _ := json.Marshal(r.Changes)
+	exp, _ := json.Marshal(want)
+	if string(got) != string(exp) {
+		t.Errorf("changes = %s, want %s", got, exp)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	SetSink(NewFileSink(&buf))
+	defer SetSink(nil)
+
+	r := gin.New()
+	r.Use(Middleware())
+	r.PUT("/user/:id", func(c *gin.Context) {
+		var u user
+		_ = c.ShouldBindJSON(&u)
+		c.String(http.StatusOK, u.Name)
+	})
+	r.GET("/audit", QueryHandler())
+
+	req := httptest.NewRequest(http.MethodPut, "/user/3", strings.NewReader(`{"name":"c","password":"p"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Body.String() != "c" {
+		t.Fatalf("body should still be readable, got %q", rec.Body.String())
+	}
+
+	var record Record
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Action != ActionUpdate || record.Resource != "/user/:id" || record.ResourceID != "3" ||
+		record.Status != http.StatusOK || record.Method != http.MethodPut {
+		t.Errorf("unexpected record %+v", record)
+	}
+	if record.After != `{"name":"c","password":"******"}` {
+		t.Errorf("unexpected after %s", record.After)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/audit", nil))
+	if !strings.Contains(rec.Body.String(), ErrNotQueryable.Error()) {
+		t.Errorf("file sink should not be queryable: %s", rec.Body.String())
+	}
+}
+
+type querySink struct {
+	q *Query
+}
+
+func (*querySink) String() string {
+	return "query"
+}
+
+func (*querySink) Write(context.Context, *Record) error {
+	return nil
+}
+
+func (s *querySink) Query(_ context.Context, q *Query) ([]Record, int64, error) {
+	s.q = q
+	return nil, 0, nil
+}
+
+func TestQueryHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &querySink{}
+	SetSink(s)
+	defer SetSink(nil)
+
+	r := gin.New()
+	r.GET("/audit", tenant.Middleware(tenant.Header(tenant.DefaultHeader)), QueryHandler())
+	for _, c := range []struct {
+		header, want string
+	}{
+		{"a", "a"},
+		{"", ""},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/audit?tenant=b&pageSize=10000000", nil)
+		req.Header.Set(tenant.DefaultHeader, c.header)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+		if s.q == nil || s.q.Tenant != c.want || s.q.PageSize != 100 || s.q.PageIndex != 1 {
+			t.Errorf("header %q: query = %+v", c.header, s.q)
+		}
+	}
+}
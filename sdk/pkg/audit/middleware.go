@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/gin-gonic/gin"
+)
+
+type requestKey struct{}
+
+// request 中间件写入ctx的请求信息, 业务中调用 Log 时自动补充
+type request struct {
+	method    string
+	path      string
+	ip        string
+	userAgent string
+}
+
+// Middleware 将请求信息写入ctx, 并按请求方法记录写操作, 请求体作为 After 记录
+// 业务中需要记录变更前后差异时, 使用 WithSkipper 跳过对应路由并调用 Log
+func Middleware(opts ...Option) gin.HandlerFunc {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(c *gin.Context) {
+		ctx := context.WithValue(c.Request.Context(), requestKey{}, &request{
+			method:    c.Request.Method,
+			path:      c.Request.URL.Path,
+			ip:        c.ClientIP(),
+			userAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+
+		action, ok := o.methods[c.Request.Method]
+		if !ok || (o.skipper != nil && o.skipper(c)) {
+			c.Next()
+			return
+		}
+		body := readBody(c, o.maxBody)
+		c.Next()
+
+		r := &Record{
+			Action:     action,
+			Resource:   o.resource(c),
+			ResourceID: c.Param("id"),
+			Status:     c.Writer.Status(),
+		}
+		if len(body) > 0 {
+			if after, err := Marshal(body); err == nil {
+				r.After = after
+			}
+		}
+		// 鉴权中间件可能在之后写入操作人, 使用处理完成后的ctx
+		_ = Write(c.Request.Context(), r)
+	}
+}
+
+// readBody 读取json请求体并放回, 超过max时不记录
+func readBody(c *gin.Context, max int64) []byte {
+	if max <= 0 || c.Request.Body == nil || c.ContentType() != gin.MIMEJSON {
+		return nil
+	}
+	if c.Request.ContentLength > max {
+		return nil
+	}
+	b, err := io.ReadAll(io.LimitReader(c.Request.Body, max+1))
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(b), c.Request.Body))
+	if err != nil || int64(len(b)) > max {
+		return nil
+	}
+	return b
+}
@@ -0,0 +1,55 @@
+package audit
+
+import "github.com/gin-gonic/gin"
+
+type Option func(*options)
+
+type options struct {
+	methods  map[string]string
+	skipper  func(c *gin.Context) bool
+	resource func(c *gin.Context) string
+	maxBody  int64
+}
+
+func setDefault() options {
+	return options{
+		methods: map[string]string{
+			"POST":   ActionCreate,
+			"PUT":    ActionUpdate,
+			"PATCH":  ActionUpdate,
+			"DELETE": ActionDelete,
+		},
+		resource: func(c *gin.Context) string {
+			return c.FullPath()
+		},
+		maxBody: 4 << 10,
+	}
+}
+
+// WithMethod 记录的请求方法及对应的操作类型, 默认记录 POST PUT PATCH DELETE
+func WithMethod(method, action string) Option {
+	return func(o *options) {
+		o.methods[method] = action
+	}
+}
+
+// WithSkipper 返回true时不记录
+func WithSkipper(f func(c *gin.Context) bool) Option {
+	return func(o *options) {
+		o.skipper = f
+	}
+}
+
+// WithResource 资源名称, 默认为路由路径
+func WithResource(f func(c *gin.Context) string) Option {
+	return func(o *options) {
+		o.resource = f
+	}
+}
+
+// WithMaxBody 记录请求体的最大字节数, 超出时不记录请求体, 0为不记录
+func WithMaxBody(n int64) Option {
+	return func(o *options) {
+		o.maxBody = n
+	}
+}
@@ -0,0 +1,219 @@
+package audit
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RedactMask 敏感字段在审计记录中的替换值
+const RedactMask = "******"
+
+// RedactKeys 名称中包含这些词(不区分大小写)的字段视为敏感, 不记录明文
+var RedactKeys = []string{"password", "secret", "token", "credential"}
+
+// Record 审计记录
+type Record struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Tenant     string    `json:"tenant" gorm:"size:128;index"`
+	OperatorID string    `json:"operatorId" gorm:"size:64;index"`
+	Action     string    `json:"action" gorm:"size:64;index"`
+	Resource   string    `json:"resource" gorm:"size:255;index"`
+	ResourceID string    `json:"resourceId" gorm:"size:128"`
+	Before     string    `json:"before" gorm:"type:text"`
+	After      string    `json:"after" gorm:"type:text"`
+	Changes    Changes   `json:"changes" gorm:"type:text"`
+	Method     string    `json:"method" gorm:"size:16"`
+	Path       string    `json:"path" gorm:"size:255"`
+	IP         string    `json:"ip" gorm:"size:64"`
+	UserAgent  string    `json:"userAgent" gorm:"size:255"`
+	Status     int       `json:"status"`
+	RequestID  string    `json:"requestId" gorm:"size:64;index"`
+	TraceID    string    `json:"traceId" gorm:"size:64"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"index"`
+}
+
+func (Record) TableName() string {
+	return "sys_audit_record"
+}
+
+// Change 单个字段的变更
+type Change struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// Changes 以json保存到数据库
+type Changes []Change
+
+// Value 实现 driver.Valuer
+func (c Changes) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(c)
+	return string(b), err
+}
+
+// Scan 实现 sql.Scanner
+func (c *Changes) Scan(v interface{}) error {
+	switch t := v.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(t, c)
+	case string:
+		return json.Unmarshal([]byte(t), c)
+	}
+	return errors.New("unsupported audit changes type")
+}
+
+// Diff 比较变更前后的对象(struct、map或json), 返回按路径排序的叶子字段变更, 敏感字段被替换为 RedactMask
+func Diff(before, after interface{}) (Changes, error) {
+	b, err := flatten(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := flatten(after)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(Changes, 0)
+	for p, v := range a {
+		old, ok := b[p]
+		if ok && reflect.DeepEqual(old, v) {
+			continue
+		}
+		changes = append(changes, Change{Path: p, Old: old, New: v})
+	}
+	for p, v := range b {
+		if _, ok := a[p]; !ok {
+			changes = append(changes, Change{Path: p, Old: v})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	for i := range changes {
+		if sensitive(changes[i].Path) {
+			if changes[i].Old != nil {
+				changes[i].Old = RedactMask
+			}
+			if changes[i].New != nil {
+				changes[i].New = RedactMask
+			}
+		}
+	}
+	return changes, nil
+}
+
+// Marshal 转为json并替换敏感字段, nil 返回空字符串
+func Marshal(v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	data, err := normalize(v)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(redact("", data))
+	return string(b), err
+}
+
+func sensitive(path string) bool {
+	name := strings.ToLower(path[strings.LastIndex(path, ".")+1:])
+	for _, k := range RedactKeys {
+		if strings.Contains(name, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize 统一转为json的通用结构
+func normalize(v interface{}) (interface{}, error) {
+	var b []byte
+	switch t := v.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		b = t
+	case string:
+		b = []byte(t)
+	case json.RawMessage:
+		b = t
+	default:
+		var err error
+		if b, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	if len(b) == 0 {
+		return nil, nil
+	}
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func flatten(v interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+	data, err := normalize(v)
+	if err != nil {
+		return nil, err
+	}
+	walk("", data, out)
+	return out, nil
+}
+
+func walk(prefix string, v interface{}, out map[string]interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, sub := range t {
+			walk(join(prefix, k), sub, out)
+		}
+	case []interface{}:
+		for i, sub := range t {
+			walk(join(prefix, strconv.Itoa(i)), sub, out)
+		}
+	default:
+		if prefix != "" {
+			out[prefix] = v
+		}
+	}
+}
+
+func redact(prefix string, v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, sub := range t {
+			p := join(prefix, k)
+			if sensitive(p) {
+				t[k] = RedactMask
+				continue
+			}
+			t[k] = redact(p, sub)
+		}
+	case []interface{}:
+		for i, sub := range t {
+			t[i] = redact(join(prefix, strconv.Itoa(i)), sub)
+		}
+	}
+	return v
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
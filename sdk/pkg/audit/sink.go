@@ -0,0 +1,129 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+// DefaultStream 队列模式的默认队列名
+const DefaultStream = "audit"
+
+// GormSink 写入数据库表 sys_audit_record, 支持查询
+type GormSink struct {
+	db *gorm.DB
+}
+
+// NewGormSink 写入数据库, migrate 为true时自动建表
+func NewGormSink(db *gorm.DB, migrate bool) (*GormSink, error) {
+	if migrate {
+		if err := db.AutoMigrate(&Record{}); err != nil {
+			return nil, err
+		}
+	}
+	return &GormSink{db: db}, nil
+}
+
+func (*GormSink) String() string {
+	return "gorm"
+}
+
+func (s *GormSink) Write(ctx context.Context, r *Record) error {
+	return s.db.WithContext(ctx).Create(r).Error
+}
+
+func (s *GormSink) Query(ctx context.Context, q *Query) ([]Record, int64, error) {
+	// 租户为空表示 DefaultKey, 同样作为条件
+	db := s.db.WithContext(ctx).Model(&Record{}).Where("tenant = ?", q.Tenant)
+	for _, w := range [][2]string{
+		{"operator_id", q.OperatorID},
+		{"action", q.Action},
+		{"resource", q.Resource},
+		{"resource_id", q.ResourceID},
+		{"request_id", q.RequestID},
+	} {
+		if w[1] != "" {
+			db = db.Where(w[0]+" = ?", w[1])
+		}
+	}
+	if !q.Start.IsZero() {
+		db = db.Where("created_at >= ?", q.Start)
+	}
+	if !q.End.IsZero() {
+		db = db.Where("created_at < ?", q.End)
+	}
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	list := make([]Record, 0)
+	err := db.Order("id desc").
+		Offset((q.PageIndex - 1) * q.PageSize).
+		Limit(q.PageSize).
+		Find(&list).Error
+	return list, count, err
+}
+
+// QueueSink 发送到队列, values 中 record 为json格式的审计记录
+type QueueSink struct {
+	q      storage.AdapterQueue
+	stream string
+}
+
+// NewQueueSink 发送到队列, stream 为空时使用 DefaultStream
+func NewQueueSink(q storage.AdapterQueue, stream string) *QueueSink {
+	if stream == "" {
+		stream = DefaultStream
+	}
+	return &QueueSink{q: q, stream: stream}
+}
+
+func (*QueueSink) String() string {
+	return "queue"
+}
+
+func (s *QueueSink) Write(ctx context.Context, r *Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	message := &queue.Message{}
+	message.SetStream(s.stream)
+	message.SetValues(map[string]interface{}{
+		"record": string(b),
+	})
+	queue.InjectTrace(ctx, message)
+	return s.q.Append(message)
+}
+
+// FileSink 按行写入json, 可配合 writer.NewFileWriter 按天切割
+type FileSink struct {
+	w   io.Writer
+	mux sync.Mutex
+}
+
+// NewFileSink 写入w
+func NewFileSink(w io.Writer) *FileSink {
+	return &FileSink{w: w}
+}
+
+func (*FileSink) String() string {
+	return "file"
+}
+
+func (s *FileSink) Write(_ context.Context, r *Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	_, err = s.w.Write(append(b, '\n'))
+	return err
+}
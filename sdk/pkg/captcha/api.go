@@ -0,0 +1,46 @@
+package captcha
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+)
+
+// Handler 获取图形/滑块验证码的接口, 参数 type 为 digit、string、math 或 slide, 默认 digit
+func (s *Service) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			data interface{}
+			err  error
+		)
+		switch t := c.DefaultQuery("type", string(Digit)); t {
+		case "slide":
+			data, err = s.Slide(c.ClientIP())
+		default:
+			data, err = s.Image(ImageType(t), c.ClientIP())
+		}
+		if err != nil {
+			response.Fail(c, err)
+			return
+		}
+		response.OK(c, data, "")
+	}
+}
+
+// SendCodeHandler 发送短信/邮件验证码的接口, 请求体为 {"to": "手机号或邮箱"}
+func (s *Service) SendCodeHandler(channel Channel) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := struct {
+			To string `json:"to" binding:"required"`
+		}{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Fail(c, response.ErrBadRequest.Wrap(err))
+			return
+		}
+		if err := s.SendCode(c.Request.Context(), channel, req.To, c.ClientIP()); err != nil {
+			response.Fail(c, err)
+			return
+		}
+		response.OK(c, nil, "")
+	}
+}
@@ -0,0 +1,71 @@
+package captcha
+
+import (
+	"crypto/subtle"
+	"image/color"
+	"strings"
+
+	"github.com/mojocn/base64Captcha"
+)
+
+// ImageType 图形验证码类型
+type ImageType string
+
+const (
+	// Digit 纯数字
+	Digit ImageType = "digit"
+	// Alphanumeric 数字与字母, 校验时不区分大小写
+	Alphanumeric ImageType = "string"
+	// Math 算术题, 答案为计算结果
+	Math ImageType = "math"
+)
+
+// Image 图形验证码, Image 为 data:image/png;base64 格式
+type Image struct {
+	Id    string `json:"id"`
+	Image string `json:"image"`
+}
+
+func newDrivers() map[ImageType]base64Captcha.Driver {
+	bg := &color.RGBA{R: 240, G: 240, B: 246, A: 246}
+	fonts := []string{"wqy-microhei.ttc"}
+	return map[ImageType]base64Captcha.Driver{
+		Digit:        base64Captcha.NewDriverDigit(80, 240, 4, 0.7, 80),
+		Alphanumeric: base64Captcha.NewDriverString(46, 140, 2, 2, 4, "234567890abcdefghjkmnpqrstuvwxyz", bg, nil, fonts).ConvertFonts(),
+		Math:         base64Captcha.NewDriverMath(46, 140, 2, 2, bg, nil, fonts).ConvertFonts(),
+	}
+}
+
+// Image 生成图形验证码, ip不为空时按ip限制获取频率
+func (s *Service) Image(t ImageType, ip string) (*Image, error) {
+	driver, ok := s.drivers[t]
+	if !ok {
+		return nil, ErrUnknownType
+	}
+	if err := s.allowIP(ip); err != nil {
+		return nil, err
+	}
+	_, question, answer := driver.GenerateIdQuestionAnswer()
+	item, err := driver.DrawCaptcha(question)
+	if err != nil {
+		return nil, err
+	}
+	id := newID()
+	if err = s.cache.Set(s.opts.prefix+"image:"+id, answer, s.expireSeconds()); err != nil {
+		return nil, err
+	}
+	return &Image{Id: id, Image: item.EncodeB64string()}, nil
+}
+
+// VerifyImage 校验图形验证码, 无论结果如何验证码都会作废
+func (s *Service) VerifyImage(id, answer string) bool {
+	if id == "" {
+		return false
+	}
+	want := s.take(s.opts.prefix + "image:" + id)
+	if want == "" {
+		return false
+	}
+	got := strings.ToLower(strings.TrimSpace(answer))
+	return subtle.ConstantTimeCompare([]byte(strings.ToLower(want)), []byte(got)) == 1
+}
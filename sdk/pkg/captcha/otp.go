@@ -0,0 +1,113 @@
+package captcha
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/mailer"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/sms"
+)
+
+// Channel 短信/邮件验证码的发送渠道
+type Channel string
+
+const (
+	SMS   Channel = "sms"
+	Email Channel = "email"
+)
+
+// Sender 发送验证码
+type Sender interface {
+	Send(ctx context.Context, to, code string, expire time.Duration) error
+}
+
+// SenderFunc 函数形式的 Sender
+type SenderFunc func(ctx context.Context, to, code string, expire time.Duration) error
+
+func (f SenderFunc) Send(ctx context.Context, to, code string, expire time.Duration) error {
+	return f(ctx, to, code, expire)
+}
+
+// SMSSender 使用短信模板发送, param 为模板中验证码的参数名, 如 "code"
+func SMSSender(s *sms.Sender, template, param string) Sender {
+	return SenderFunc(func(ctx context.Context, to, code string, _ time.Duration) error {
+		_, err := s.Send(ctx, to, template, map[string]string{param: code})
+		return err
+	})
+}
+
+// MailSender 使用邮件模板发送, 模板数据为 {{.Code}} 与有效分钟数 {{.Expire}}
+func MailSender(m *mailer.Mailer, template string) Sender {
+	return SenderFunc(func(ctx context.Context, to, code string, expire time.Duration) error {
+		return m.SendTemplate(ctx, []string{to}, template, map[string]interface{}{
+			"Code":   code,
+			"Expire": int(expire / time.Minute),
+		})
+	})
+}
+
+func (s *Service) codeKey(c Channel, to string) string {
+	return s.opts.prefix + string(c) + ":code:" + to
+}
+
+func (s *Service) attemptsKey(c Channel, to string) string {
+	return s.opts.prefix + string(c) + ":attempts:" + to
+}
+
+// SendCode 生成并发送短信/邮件验证码, 按ip与接收方限制发送频率; 重新发送会使之前的验证码作废
+func (s *Service) SendCode(ctx context.Context, c Channel, to, ip string) error {
+	sender, ok := s.opts.senders[c]
+	if !ok {
+		return ErrNoSender
+	}
+	to = strings.TrimSpace(to)
+	if err := s.allowIP(ip); err != nil {
+		return err
+	}
+	if err := s.allowAccount(c, to); err != nil {
+		return err
+	}
+	code := make([]byte, s.opts.codeLength)
+	for i := range code {
+		code[i] = byte('0' + randInt(10))
+	}
+	key := s.codeKey(c, to)
+	_ = s.cache.Del(s.attemptsKey(c, to))
+	if err := s.cache.Set(key, string(code), s.expireSeconds()); err != nil {
+		return err
+	}
+	if err := sender.Send(ctx, to, string(code), s.opts.expire); err != nil {
+		_ = s.cache.Del(key)
+		logger.Module("sdk.captcha").WithContext(ctx).Error("send code failed", "channel", string(c), "error", err)
+		return err
+	}
+	return nil
+}
+
+// VerifyCode 校验短信/邮件验证码, 通过后作废; 失败次数达到 WithMaxAttempts 时也作废
+func (s *Service) VerifyCode(c Channel, to, code string) bool {
+	to = strings.TrimSpace(to)
+	key := s.codeKey(c, to)
+	want, err := s.cache.Get(key)
+	if err != nil || want == "" {
+		return false
+	}
+	// 先计数再比较, 并发的猜测各自占用一次尝试
+	attemptsKey := s.attemptsKey(c, to)
+	n, err := s.incr(attemptsKey, s.opts.expire)
+	if err != nil {
+		return false
+	}
+	if n <= s.opts.maxAttempts &&
+		subtle.ConstantTimeCompare([]byte(want), []byte(strings.TrimSpace(code))) == 1 && s.consume(key, want) {
+		_ = s.cache.Del(attemptsKey)
+		return true
+	}
+	if n >= s.opts.maxAttempts {
+		_ = s.cache.Del(key)
+	}
+	return false
+}
@@ -0,0 +1,236 @@
+package captcha
+
+import (
+	"errors"
+	"image"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mojocn/base64Captcha"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+var (
+	ErrTooFrequent   = errors.New("captcha: issue too frequently")
+	ErrLimitExceeded = errors.New("captcha: limit exceeded")
+	ErrNoSender      = errors.New("captcha: sender not configured")
+	ErrUnknownType   = errors.New("captcha: unknown type")
+)
+
+func init() {
+	response.RegisterError(ErrTooFrequent, response.NewError(429, http.StatusTooManyRequests, "captcha.too_frequent", "验证码获取过于频繁, 请稍后再试"))
+	response.RegisterError(ErrLimitExceeded, response.NewError(429, http.StatusTooManyRequests, "captcha.limit_exceeded", "验证码获取次数已达上限"))
+	response.RegisterError(ErrUnknownType, response.NewError(400, http.StatusBadRequest, "captcha.unknown_type", "不支持的验证码类型"))
+}
+
+type Option func(*options)
+
+type options struct {
+	prefix      string
+	expire      time.Duration
+	ipLimit     int
+	ipWindow    time.Duration
+	interval    time.Duration
+	dailyLimit  int
+	maxAttempts int
+	codeLength  int
+	tolerance   int
+	senders     map[Channel]Sender
+	backgrounds []image.Image
+}
+
+func setDefault() options {
+	return options{
+		prefix:      "captcha:",
+		expire:      5 * time.Minute,
+		ipLimit:     30,
+		ipWindow:    time.Minute,
+		interval:    time.Minute,
+		dailyLimit:  10,
+		maxAttempts: 5,
+		codeLength:  6,
+		tolerance:   5,
+		senders:     make(map[Channel]Sender),
+	}
+}
+
+// WithKeyPrefix 缓存key的前缀, 默认 "captcha:"
+func WithKeyPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithExpire 验证码有效期, 默认5分钟
+func WithExpire(d time.Duration) Option {
+	return func(o *options) {
+		o.expire = d
+	}
+}
+
+// WithIPLimit 同一ip在 window 内最多获取 n 次验证码, 默认每分钟30次, 0为不限制
+func WithIPLimit(n int, window time.Duration) Option {
+	return func(o *options) {
+		o.ipLimit = n
+		o.ipWindow = window
+	}
+}
+
+// WithAccountLimit 同一手机号/邮箱两次发送的最小间隔与每天的上限, 默认1分钟、10次, 0为不限制
+func WithAccountLimit(interval time.Duration, daily int) Option {
+	return func(o *options) {
+		o.interval = interval
+		o.dailyLimit = daily
+	}
+}
+
+// WithMaxAttempts 短信/邮件验证码最多校验次数, 超过后作废, 默认5次
+func WithMaxAttempts(n int) Option {
+	return func(o *options) {
+		o.maxAttempts = n
+	}
+}
+
+// WithCodeLength 短信/邮件验证码位数, 默认6
+func WithCodeLength(n int) Option {
+	return func(o *options) {
+		o.codeLength = n
+	}
+}
+
+// WithTolerance 滑块验证允许的横向误差像素, 默认5
+func WithTolerance(px int) Option {
+	return func(o *options) {
+		o.tolerance = px
+	}
+}
+
+// WithSender 短信/邮件验证码的发送方式, 见 SMSSender、MailSender
+func WithSender(c Channel, s Sender) Option {
+	return func(o *options) {
+		o.senders[c] = s
+	}
+}
+
+// WithBackgrounds 滑块验证码的背景图, 随机选取并缩放, 未设置时随机生成
+func WithBackgrounds(images ...image.Image) Option {
+	return func(o *options) {
+		o.backgrounds = append(o.backgrounds, images...)
+	}
+}
+
+// Service 验证码服务, 答案保存在缓存中, 校验后即作废
+type Service struct {
+	cache   storage.AdapterCache
+	opts    options
+	drivers map[ImageType]base64Captcha.Driver
+}
+
+// New 创建验证码服务, 多实例部署时 cache 应使用redis
+func New(cache storage.AdapterCache, opts ...Option) *Service {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Service{cache: cache, opts: o, drivers: newDrivers()}
+}
+
+func (s *Service) expireSeconds() int {
+	return seconds(s.opts.expire)
+}
+
+func seconds(d time.Duration) int {
+	n := int(d / time.Second)
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+func newID() string {
+	return strings.ReplaceAll(uuid.New().String(), "-", "")
+}
+
+// take 读取并删除答案, 保证只能校验一次
+func (s *Service) take(key string) string {
+	v, err := s.cache.Get(key)
+	if err != nil || v == "" || !s.consume(key, v) {
+		return ""
+	}
+	return v
+}
+
+// consume 值仍为 v 时将其作废, 并发的请求中只有一个成功; 缓存需支持 CompareAndSwap
+func (s *Service) consume(key, v string) bool {
+	ok, err := cache.CompareAndSwap(s.cache, key, v, "", 1)
+	return err == nil && ok
+}
+
+// allowIP 按ip限制获取频率, ip为空时不限制
+func (s *Service) allowIP(ip string) error {
+	if ip == "" || s.opts.ipLimit <= 0 {
+		return nil
+	}
+	return s.count(s.opts.prefix+"ip:"+ip, s.opts.ipLimit, s.opts.ipWindow, ErrTooFrequent)
+}
+
+// allowAccount 按手机号/邮箱限制发送间隔与每天的次数
+func (s *Service) allowAccount(c Channel, target string) error {
+	key := s.opts.prefix + string(c) + ":"
+	intervalKey := key + "interval:" + target
+	if s.opts.interval > 0 {
+		if v, _ := s.cache.Get(intervalKey); v != "" {
+			return ErrTooFrequent
+		}
+	}
+	if s.opts.dailyLimit > 0 {
+		now := time.Now()
+		end := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+		dailyKey := key + "daily:" + now.Format("20060102") + ":" + target
+		if err := s.count(dailyKey, s.opts.dailyLimit, end.Sub(now)+time.Second, ErrLimitExceeded); err != nil {
+			return err
+		}
+	}
+	if s.opts.interval > 0 {
+		return s.cache.Set(intervalKey, 1, seconds(s.opts.interval))
+	}
+	return nil
+}
+
+// count 固定窗口计数, 超过 limit 时返回 exceeded
+func (s *Service) count(key string, limit int, window time.Duration, exceeded error) error {
+	n, err := s.incr(key, window)
+	if err != nil {
+		return err
+	}
+	if n > limit {
+		return exceeded
+	}
+	return nil
+}
+
+// incr 计数加一并返回新值, 有效期在第一次计数时设置; 并发的请求各自得到不同的值
+func (s *Service) incr(key string, window time.Duration) (int, error) {
+	if _, err := cache.SetNX(s.cache, key, 0, seconds(window)); err != nil {
+		return 0, err
+	}
+	if err := s.cache.Increase(key); err != nil {
+		return 0, err
+	}
+	v, err := s.cache.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(v)
+	if n == 1 {
+		// 占位后key恰好过期时, 自增创建的key没有有效期
+		_ = s.cache.Expire(key, time.Duration(seconds(window))*time.Second)
+	}
+	return n, err
+}
@@ -0,0 +1,187 @@
+package captcha
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"image/png"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+func TestImage(t *testing.T) {
+	c := cache.NewMemory()
+	s := New(c, WithIPLimit(2, time.Minute))
+	img, err := s.Image(Digit, "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer, _ := c.Get("captcha:image:" + img.Id)
+	if answer == "" || !strings.HasPrefix(img.Image, "data:image/png;base64,") {
+		t.Fatalf("answer = %q, image = %.30s", answer, img.Image)
+	}
+	if !s.VerifyImage(img.Id, " "+answer+" ") {
+		t.Error("verify failed")
+	}
+	if s.VerifyImage(img.Id, answer) {
+		t.Error("captcha should be used only once")
+	}
+
+	img, _ = s.Image(Alphanumeric, "10.0.0.1")
+	if s.VerifyImage(img.Id, "wrong") || s.VerifyImage(img.Id, "") {
+		t.Error("wrong answer should fail and clear the captcha")
+	}
+	if _, err = s.Image(Digit, "10.0.0.1"); !errors.Is(err, ErrTooFrequent) {
+		t.Errorf("err = %v", err)
+	}
+	if _, err = s.Image(Math, "10.0.0.2"); err != nil {
+		t.Error(err)
+	}
+	if _, err = s.Image("audio", ""); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSlide(t *testing.T) {
+	c := cache.NewMemory()
+	s := New(c)
+	slide, err := s.Slide("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(slide.Background, "data:image/png;base64,"))
+	bg, err := png.Decode(strings.NewReader(string(raw)))
+	if err != nil || bg.Bounds().Dx() != SlideWidth || bg.Bounds().Dy() != SlideHeight {
+		t.Fatalf("background err = %v", err)
+	}
+	if slide.Y < 0 || slide.Y+PieceSize > SlideHeight {
+		t.Errorf("y = %d", slide.Y)
+	}
+	v, _ := c.Get("captcha:slide:" + slide.Id)
+	x, _ := strconv.Atoi(v)
+	if !s.VerifySlide(slide.Id, x+3) {
+		t.Error("verify failed")
+	}
+	if s.VerifySlide(slide.Id, x) {
+		t.Error("captcha should be used only once")
+	}
+	slide, _ = s.Slide("")
+	v, _ = c.Get("captcha:slide:" + slide.Id)
+	x, _ = strconv.Atoi(v)
+	if s.VerifySlide(slide.Id, x+20) {
+		t.Error("offset out of tolerance should fail")
+	}
+}
+
+func TestCode(t *testing.T) {
+	sent := make(map[string]string)
+	sender := SenderFunc(func(_ context.Context, to, code string, _ time.Duration) error {
+		sent[to] = code
+		return nil
+	})
+	s := New(cache.NewMemory(), WithSender(SMS, sender), WithMaxAttempts(3), WithAccountLimit(time.Minute, 2))
+	ctx := context.Background()
+	if err := s.SendCode(ctx, Email, "a@example.com", ""); !errors.Is(err, ErrNoSender) {
+		t.Errorf("err = %v", err)
+	}
+	if err := s.SendCode(ctx, SMS, "13800000000", ""); err != nil {
+		t.Fatal(err)
+	}
+	code := sent["13800000000"]
+	if len(code) != 6 {
+		t.Fatalf("code = %q", code)
+	}
+	if err := s.SendCode(ctx, SMS, "13800000000", ""); !errors.Is(err, ErrTooFrequent) {
+		t.Errorf("err = %v", err)
+	}
+	if s.VerifyCode(SMS, "13800000000", "000000x") || !s.VerifyCode(SMS, "13800000000", code) {
+		t.Error("verify failed")
+	}
+	if s.VerifyCode(SMS, "13800000000", code) {
+		t.Error("code should be used only once")
+	}
+
+	// 超过最大校验次数后作废
+	if err := s.SendCode(ctx, SMS, "13900000000", ""); err != nil {
+		t.Fatal(err)
+	}
+	code = sent["13900000000"]
+	for i := 0; i < 3; i++ {
+		s.VerifyCode(SMS, "13900000000", "wrong")
+	}
+	if s.VerifyCode(SMS, "13900000000", code) {
+		t.Error("code should be cleared after max attempts")
+	}
+}
+
+func TestAccountDailyLimit(t *testing.T) {
+	sender := SenderFunc(func(context.Context, string, string, time.Duration) error { return nil })
+	s := New(cache.NewMemory(), WithSender(Email, sender), WithAccountLimit(0, 2))
+	for i := 0; i < 2; i++ {
+		if err := s.SendCode(context.Background(), Email, "a@example.com", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.SendCode(context.Background(), Email, "a@example.com", ""); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestConcurrentVerify(t *testing.T) {
+	c := cache.NewMemory()
+	var code string
+	sender := SenderFunc(func(_ context.Context, _, c string, _ time.Duration) error {
+		code = c
+		return nil
+	})
+	s := New(c, WithSender(SMS, sender), WithMaxAttempts(3))
+	if err := s.SendCode(context.Background(), SMS, "13800000000", ""); err != nil {
+		t.Fatal(err)
+	}
+	img, err := s.Image(Digit, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer, _ := c.Get("captcha:image:" + img.Id)
+
+	// 并发猜测只能占用 maxAttempts 次尝试, 答对的验证码与图形验证码都只能通过一次
+	var codeOK, imageOK int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			guess := code
+			if i%2 == 0 {
+				guess = "wrong"
+			}
+			if s.VerifyCode(SMS, "13800000000", guess) {
+				atomic.AddInt32(&codeOK, 1)
+			}
+			if s.VerifyImage(img.Id, answer) {
+				atomic.AddInt32(&imageOK, 1)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if codeOK > 1 || imageOK != 1 {
+		t.Errorf("codeOK = %d, imageOK = %d", codeOK, imageOK)
+	}
+
+	// 达到最大次数后, 并发的其余猜测即使正确也失败
+	if err = s.SendCode(context.Background(), SMS, "13900000000", ""); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		s.VerifyCode(SMS, "13900000000", "wrong")
+	}
+	if s.VerifyCode(SMS, "13900000000", code) {
+		t.Error("code should be cleared after max attempts")
+	}
+}
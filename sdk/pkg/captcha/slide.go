@@ -0,0 +1,153 @@
+package captcha
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math/big"
+	"strconv"
+)
+
+// 滑块验证码的尺寸
+const (
+	SlideWidth  = 300
+	SlideHeight = 150
+	PieceSize   = 50
+)
+
+// Slide 滑块验证码, 前端将 Piece 放在纵坐标 Y 处, 用户拖动后提交横坐标
+type Slide struct {
+	Id         string `json:"id"`
+	Background string `json:"background"`
+	Piece      string `json:"piece"`
+	Y          int    `json:"y"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+}
+
+// Slide 生成滑块验证码, ip不为空时按ip限制获取频率
+func (s *Service) Slide(ip string) (*Slide, error) {
+	if err := s.allowIP(ip); err != nil {
+		return nil, err
+	}
+	bg := s.background()
+	x := PieceSize + randInt(SlideWidth-2*PieceSize-10)
+	y := randInt(SlideHeight - PieceSize)
+	piece := image.NewRGBA(image.Rect(0, 0, PieceSize, PieceSize))
+	for py := 0; py < PieceSize; py++ {
+		for px := 0; px < PieceSize; px++ {
+			if !inPiece(px, py) {
+				continue
+			}
+			c := bg.RGBAAt(x+px, y+py)
+			if !inPiece(px-2, py) || !inPiece(px+2, py) || !inPiece(px, py-2) || !inPiece(px, py+2) {
+				// 描边
+				piece.SetRGBA(px, py, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+			} else {
+				piece.SetRGBA(px, py, c)
+			}
+			bg.SetRGBA(x+px, y+py, color.RGBA{R: c.R / 3, G: c.G / 3, B: c.B / 3, A: 255})
+		}
+	}
+	background, err := encodePNG(bg)
+	if err != nil {
+		return nil, err
+	}
+	p, err := encodePNG(piece)
+	if err != nil {
+		return nil, err
+	}
+	id := newID()
+	if err = s.cache.Set(s.opts.prefix+"slide:"+id, x, s.expireSeconds()); err != nil {
+		return nil, err
+	}
+	return &Slide{Id: id, Background: background, Piece: p, Y: y, Width: SlideWidth, Height: SlideHeight}, nil
+}
+
+// VerifySlide 校验滑块的横坐标, 误差在 WithTolerance 以内即通过, 无论结果如何验证码都会作废
+func (s *Service) VerifySlide(id string, x int) bool {
+	if id == "" {
+		return false
+	}
+	want, err := strconv.Atoi(s.take(s.opts.prefix + "slide:" + id))
+	if err != nil {
+		return false
+	}
+	d := want - x
+	if d < 0 {
+		d = -d
+	}
+	return d <= s.opts.tolerance
+}
+
+// inPiece 拼图形状: 40x40 的方块, 上边与右边各有一个半径6的凸起
+func inPiece(x, y int) bool {
+	if x >= 4 && x < 44 && y >= 10 && y < 50 {
+		return true
+	}
+	return inCircle(x, y, 24, 10, 6) || inCircle(x, y, 44, 30, 6)
+}
+
+func inCircle(x, y, cx, cy, r int) bool {
+	return (x-cx)*(x-cx)+(y-cy)*(y-cy) <= r*r
+}
+
+// background 随机选取背景图并缩放, 未设置时生成渐变色背景
+func (s *Service) background() *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, SlideWidth, SlideHeight))
+	if n := len(s.opts.backgrounds); n > 0 {
+		src := s.opts.backgrounds[randInt(n)]
+		b := src.Bounds()
+		for y := 0; y < SlideHeight; y++ {
+			for x := 0; x < SlideWidth; x++ {
+				dst.Set(x, y, src.At(b.Min.X+x*b.Dx()/SlideWidth, b.Min.Y+y*b.Dy()/SlideHeight))
+			}
+		}
+		return dst
+	}
+	from := color.RGBA{R: uint8(randInt(256)), G: uint8(randInt(256)), B: uint8(randInt(256)), A: 255}
+	to := color.RGBA{R: uint8(randInt(256)), G: uint8(randInt(256)), B: uint8(randInt(256)), A: 255}
+	for x := 0; x < SlideWidth; x++ {
+		c := color.RGBA{
+			R: uint8(int(from.R) + (int(to.R)-int(from.R))*x/SlideWidth),
+			G: uint8(int(from.G) + (int(to.G)-int(from.G))*x/SlideWidth),
+			B: uint8(int(from.B) + (int(to.B)-int(from.B))*x/SlideWidth),
+			A: 255,
+		}
+		draw.Draw(dst, image.Rect(x, 0, x+1, SlideHeight), &image.Uniform{C: c}, image.Point{}, draw.Src)
+	}
+	// 随机色块, 增加识别难度
+	for i := 0; i < 12; i++ {
+		cx, cy, r := randInt(SlideWidth), randInt(SlideHeight), 8+randInt(20)
+		c := color.RGBA{R: uint8(randInt(256)), G: uint8(randInt(256)), B: uint8(randInt(256)), A: 255}
+		for y := cy - r; y <= cy+r; y++ {
+			for x := cx - r; x <= cx+r; x++ {
+				if inCircle(x, y, cx, cy, r) && image.Pt(x, y).In(dst.Rect) {
+					dst.SetRGBA(x, y, c)
+				}
+			}
+		}
+	}
+	return dst
+}
+
+func encodePNG(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// randInt [0, n) 的随机数
+func randInt(n int) int {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0
+	}
+	return int(v.Int64())
+}
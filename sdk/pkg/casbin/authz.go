@@ -0,0 +1,119 @@
+package mycasbin
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/jwtauth"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+const (
+	decisionPrefix = "casbin:decision:"
+	// generationKey 策略版本, 策略变更后更新, 旧版本的缓存结果不再命中
+	generationKey = "casbin:generation"
+	// generationTTL 策略版本的保存时间, 需要远大于鉴权结果的缓存时间
+	generationTTL = 30 * 24 * 3600
+)
+
+// ErrNotSetup 未调用 Setup 初始化
+var ErrNotSetup = errors.New("casbin enforcer not setup")
+
+var authorizer *Authorizer
+
+// Authorizer 鉴权, 通过缓存保存鉴权结果
+type Authorizer struct {
+	enforcer *casbin.SyncedEnforcer
+	cache    storage.AdapterCache
+	ttl      int
+}
+
+// NewAuthorizer cache 为空或 ttl 为0时不缓存鉴权结果
+func NewAuthorizer(enforcer *casbin.SyncedEnforcer, cache storage.AdapterCache, ttl int) *Authorizer {
+	return &Authorizer{enforcer: enforcer, cache: cache, ttl: ttl}
+}
+
+func (a *Authorizer) cached() bool {
+	return a.cache != nil && a.ttl > 0
+}
+
+// Check 判断 sub 是否可以对 obj 执行 act
+func (a *Authorizer) Check(sub, obj, act string) (bool, error) {
+	if !a.cached() {
+		return a.enforcer.Enforce(sub, obj, act)
+	}
+	generation, _ := a.cache.Get(generationKey)
+	key := decisionPrefix + generation + ":" + strings.Join([]string{sub, obj, act}, ":")
+	if v, err := a.cache.Get(key); err == nil && v != "" {
+		return v == "1", nil
+	}
+	ok, err := a.enforcer.Enforce(sub, obj, act)
+	if err != nil {
+		return false, err
+	}
+	v := "0"
+	if ok {
+		v = "1"
+	}
+	_ = a.cache.Set(key, v, a.ttl)
+	return ok, nil
+}
+
+// Invalidate 策略变更后使缓存的鉴权结果失效, 本地修改策略后也需要调用
+func (a *Authorizer) Invalidate() error {
+	if a == nil || !a.cached() {
+		return nil
+	}
+	return a.cache.Set(generationKey, time.Now().UnixNano(), generationTTL)
+}
+
+// Middleware 以 subject 返回值为主体, 请求路径为资源, 请求方法为操作鉴权
+func (a *Authorizer) Middleware(subject func(c *gin.Context) string) gin.HandlerFunc {
+	if subject == nil {
+		subject = RoleSubject
+	}
+	return func(c *gin.Context) {
+		ok, err := a.Check(subject(c), c.Request.URL.Path, c.Request.Method)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, err, "")
+			return
+		}
+		if !ok {
+			response.Error(c, http.StatusForbidden, nil, "对不起，您没有该接口访问权限，请联系管理员")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RoleSubject 以jwt中的角色key为主体
+func RoleSubject(c *gin.Context) string {
+	return jwtauth.ExtractClaims(c).String(jwtauth.RoleKey)
+}
+
+// Check 使用 Setup 初始化的 enforcer 鉴权
+func Check(sub, obj, act string) (bool, error) {
+	if authorizer == nil {
+		return false, ErrNotSetup
+	}
+	return authorizer.Check(sub, obj, act)
+}
+
+// Middleware 使用 Setup 初始化的 enforcer 鉴权, 需要在 Setup 之后调用
+func Middleware(subject func(c *gin.Context) string) gin.HandlerFunc {
+	if authorizer == nil {
+		panic(ErrNotSetup)
+	}
+	return authorizer.Middleware(subject)
+}
+
+// Invalidate 使 Setup 初始化的鉴权缓存失效
+func Invalidate() error {
+	return authorizer.Invalidate()
+}
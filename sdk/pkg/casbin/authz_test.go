@@ -0,0 +1,37 @@
+package mycasbin
+
+import (
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+func TestAuthorizer_Check(t *testing.T) {
+	m, err := model.NewModelFromString(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := casbin.NewSyncedEnforcer(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = e.AddPolicy("admin", "/api/v1/users/:id", "GET")
+
+	a := NewAuthorizer(e, cache.NewMemory(), 60)
+	if ok, _ := a.Check("admin", "/api/v1/users/1", "GET"); !ok {
+		t.Fatal("admin should be allowed")
+	}
+	_, _ = e.RemovePolicy("admin", "/api/v1/users/:id", "GET")
+	if ok, _ := a.Check("admin", "/api/v1/users/1", "GET"); !ok {
+		t.Error("decision should be served from cache")
+	}
+	if err = a.Invalidate(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := a.Check("admin", "/api/v1/users/1", "GET"); ok {
+		t.Error("decision should be re-evaluated after Invalidate")
+	}
+}
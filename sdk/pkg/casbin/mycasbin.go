@@ -38,12 +38,25 @@ var (
 
 func Setup(db *gorm.DB, _ string) *casbin.SyncedEnforcer {
 	once.Do(func() {
-		Apter, err := gormAdapter.NewAdapterByDBUseTableName(db, "sys", "casbin_rule")
+		c := config.CasbinConfig
+		prefix, table := c.Prefix, c.Table
+		if prefix == "" {
+			prefix = "sys"
+		}
+		if table == "" {
+			table = "casbin_rule"
+		}
+		Apter, err := gormAdapter.NewAdapterByDBUseTableName(db, prefix, table)
 		if err != nil && err.Error() != "invalid DDL" {
 			panic(err)
 		}
 
-		m, err := model.NewModelFromString(text)
+		var m model.Model
+		if c.Model != "" {
+			m, err = model.NewModelFromFile(c.Model)
+		} else {
+			m, err = model.NewModelFromString(text)
+		}
 		if err != nil {
 			panic(err)
 		}
@@ -62,7 +75,7 @@ func Setup(db *gorm.DB, _ string) *casbin.SyncedEnforcer {
 					Network:  "tcp",
 					Password: config.CacheConfig.Redis.Password,
 				},
-				Channel:    "/casbin",
+				Channel:    channel(),
 				IgnoreSelf: false,
 			})
 			if err != nil {
@@ -81,6 +94,11 @@ func Setup(db *gorm.DB, _ string) *casbin.SyncedEnforcer {
 
 		log.SetLogger(&Logger{})
 		enforcer.EnableLog(true)
+		if c.CacheTTL > 0 {
+			authorizer = NewAuthorizer(enforcer, sdk.Runtime.GetCacheAdapter(), c.CacheTTL)
+		} else {
+			authorizer = NewAuthorizer(enforcer, nil, 0)
+		}
 	})
 
 	return enforcer
@@ -93,4 +111,14 @@ func updateCallback(msg string) {
 	if err != nil {
 		l.Errorf("casbin LoadPolicy err: %v", err)
 	}
+	if err = authorizer.Invalidate(); err != nil {
+		l.Errorf("casbin invalidate decision cache err: %v", err)
+	}
+}
+
+func channel() string {
+	if config.CasbinConfig.Channel != "" {
+		return config.CasbinConfig.Channel
+	}
+	return "/casbin"
 }
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-admin-team/go-admin-core/sdk/config"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+func (a *App) cacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect the configured cache",
+	}
+
+	var (
+		prefix string
+		depth  int
+		sample int
+	)
+	usage := &cobra.Command{
+		Use:   "usage",
+		Short: "Report key count, size and TTL distribution grouped by prefix",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := a.LoadConfig(); err != nil {
+				return err
+			}
+			c, err := config.CacheConfig.Setup()
+			if err != nil {
+				return err
+			}
+			report, err := cache.Analyze(cmd.Context(), c,
+				cache.WithUsageMatch(prefix), cache.WithUsageDepth(depth), cache.WithUsageSample(sample))
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintf(w, "%s: sampled %d of %d keys\n", c.String(), report.Sampled, report.Total)
+			fmt.Fprint(w, "PREFIX\tKEYS\tBYTES")
+			for _, b := range cache.TTLBuckets {
+				fmt.Fprintf(w, "\tTTL %s", b)
+			}
+			fmt.Fprintln(w)
+			for _, u := range report.Prefixes {
+				fmt.Fprintf(w, "%s\t%d\t%d", u.Prefix, u.Keys, u.Bytes)
+				for _, b := range cache.TTLBuckets {
+					fmt.Fprintf(w, "\t%d", u.TTL[b])
+				}
+				fmt.Fprintln(w)
+			}
+			return w.Flush()
+		},
+	}
+	usage.Flags().StringVar(&prefix, "prefix", "", "only analyze keys with this prefix")
+	usage.Flags().IntVar(&depth, "depth", 1, "number of key segments used for grouping")
+	usage.Flags().IntVar(&sample, "sample", 10000, "maximum keys to inspect, 0 for all")
+
+	cmd.AddCommand(usage)
+	return cmd
+}
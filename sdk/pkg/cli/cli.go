@@ -0,0 +1,281 @@
+// Package cli 基于 cobra 的命令行入口, 提供 server、migrate、gen、cache、version、config-check 子命令,
+// 下游项目通过 Option 或 Register 追加自己的命令, 保持一致的二进制接口
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+
+	"github.com/go-admin-team/go-admin-core/config/source"
+	"github.com/go-admin-team/go-admin-core/config/source/file"
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/config"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/migrate"
+	"github.com/go-admin-team/go-admin-core/server/health"
+	"github.com/go-admin-team/go-admin-core/server/metrics"
+	"github.com/go-admin-team/go-admin-core/tools/buildinfo"
+)
+
+// 构建信息, 通过 -ldflags 注入, 非空时覆盖 buildinfo 中的值; 新项目直接注入 buildinfo 即可
+//
+//	go build -ldflags "-X github.com/go-admin-team/go-admin-core/sdk/pkg/cli.Version=v1.0.0"
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+var (
+	ErrNoServer = errors.New("cli: server not configured")
+	ErrNoDB     = errors.New("cli: database not configured")
+)
+
+var (
+	commandMux sync.Mutex
+	commands   []func(a *App) *cobra.Command
+)
+
+// Register 插件注册的子命令, 在 init 中调用, 创建 App 时加入
+func Register(f func(a *App) *cobra.Command) {
+	commandMux.Lock()
+	defer commandMux.Unlock()
+	commands = append(commands, f)
+}
+
+type Option func(*options)
+
+type options struct {
+	short       string
+	config      string
+	envPrefix   string
+	callbacks   []func()
+	server      func(ctx context.Context, a *App) error
+	db          func() (*gorm.DB, error)
+	migrateOpts []migrate.Option
+	commands    []*cobra.Command
+	readiness   time.Duration
+}
+
+func setDefault() options {
+	return options{
+		config:    "config/settings.yml",
+		envPrefix: config.DefaultEnvPrefix,
+	}
+}
+
+// WithShort 根命令的说明
+func WithShort(s string) Option {
+	return func(o *options) {
+		o.short = s
+	}
+}
+
+// WithDefaultConfig 默认的配置文件路径
+func WithDefaultConfig(path string) Option {
+	return func(o *options) {
+		o.config = path
+	}
+}
+
+// WithEnvPrefix 覆盖配置的环境变量前缀, 见 config.Overlay
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) {
+		o.envPrefix = prefix
+	}
+}
+
+// WithConfigCallback 配置载入后的回调, 见 config.Setup
+func WithConfigCallback(fs ...func()) Option {
+	return func(o *options) {
+		o.callbacks = append(o.callbacks, fs...)
+	}
+}
+
+// WithServer server 命令启动服务, ctx 在收到 SIGINT、SIGTERM 时取消, f 应在 ctx 取消后优雅退出
+func WithServer(f func(ctx context.Context, a *App) error) Option {
+	return func(o *options) {
+		o.server = f
+	}
+}
+
+// WithStartupGate server 命令启动前等待 health 默认注册表中的必需检查(数据库、redis、队列)通过,
+// 超过 timeout 仍未通过时退出; 用 health.Optional 注册的检查失败只记录警告
+func WithStartupGate(timeout time.Duration) Option {
+	return func(o *options) {
+		o.readiness = timeout
+	}
+}
+
+// WithDB migrate 命令使用的数据库, 在配置载入后调用
+func WithDB(f func() (*gorm.DB, error)) Option {
+	return func(o *options) {
+		o.db = f
+	}
+}
+
+// WithMigrate migrate 命令的选项, 未设置 WithLocker 时使用配置中的 locker
+func WithMigrate(opts ...migrate.Option) Option {
+	return func(o *options) {
+		o.migrateOpts = append(o.migrateOpts, opts...)
+	}
+}
+
+// WithCommand 追加子命令
+func WithCommand(cmds ...*cobra.Command) Option {
+	return func(o *options) {
+		o.commands = append(o.commands, cmds...)
+	}
+}
+
+// App 命令行应用
+type App struct {
+	name       string
+	opts       options
+	configFile string
+	root       *cobra.Command
+}
+
+func New(name string, opts ...Option) *App {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	syncBuildInfo()
+	a := &App{name: name, opts: o}
+	a.root = &cobra.Command{
+		Use:           name,
+		Short:         o.short,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	a.root.PersistentFlags().StringVarP(&a.configFile, "config", "c", o.config, "config file path")
+	a.root.AddCommand(a.serverCommand(), a.migrateCommand(), a.genCommand(), a.cacheCommand(), a.versionCommand(), a.configCheckCommand())
+	commandMux.Lock()
+	for _, f := range commands {
+		a.root.AddCommand(f(a))
+	}
+	commandMux.Unlock()
+	a.root.AddCommand(o.commands...)
+	return a
+}
+
+// Root 根命令, 可继续追加命令或修改 flag
+func (a *App) Root() *cobra.Command {
+	return a.root
+}
+
+// ConfigFile --config 指定的配置文件
+func (a *App) ConfigFile() string {
+	return a.configFile
+}
+
+func (a *App) sources() []source.Source {
+	return config.Overlay(file.NewSource(file.WithPath(a.configFile)), a.opts.envPrefix)
+}
+
+// LoadConfig 载入配置文件并叠加环境变量, 需要配置的命令在执行前调用
+func (a *App) LoadConfig() error {
+	if _, err := os.Stat(a.configFile); err != nil {
+		return fmt.Errorf("cli: config file: %w", err)
+	}
+	if err := config.Check(a.sources()...); err != nil {
+		return err
+	}
+	config.SetupOverlay(file.NewSource(file.WithPath(a.configFile)), a.opts.envPrefix, a.opts.callbacks...)
+	return nil
+}
+
+// Execute 解析参数并执行, 出错时输出到 stderr
+func (a *App) Execute() error {
+	err := a.root.Execute()
+	if err != nil {
+		fmt.Fprintln(a.root.ErrOrStderr(), "Error:", err)
+	}
+	return err
+}
+
+func (a *App) serverCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "server",
+		Short: "Start the server",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if a.opts.server == nil {
+				return ErrNoServer
+			}
+			if err := a.LoadConfig(); err != nil {
+				return err
+			}
+			i := buildinfo.Get()
+			logger.Module("cli").Info("server starting", append([]interface{}{"name", a.name}, i.Fields()...)...)
+			if err := metrics.Default().RegisterBuildInfo(); err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			if a.opts.readiness > 0 {
+				if err := health.WaitReady(ctx, a.opts.readiness, 0); err != nil {
+					return err
+				}
+			}
+			return a.opts.server(ctx, a)
+		},
+	}
+}
+
+func (a *App) versionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print the version",
+		Run: func(cmd *cobra.Command, _ []string) {
+			w := cmd.OutOrStdout()
+			i := buildinfo.Get()
+			fmt.Fprintf(w, "%s %s\n", a.name, i.Version)
+			if i.Commit != "" {
+				fmt.Fprintf(w, "commit: %s\n", i.Commit)
+			}
+			if i.BuildTime != "" {
+				fmt.Fprintf(w, "build time: %s\n", i.BuildTime)
+			}
+			fmt.Fprintf(w, "go: %s %s\n", i.GoVersion, i.Platform)
+		},
+	}
+}
+
+// syncBuildInfo 把注入到 cli 的构建信息同步到 buildinfo
+func syncBuildInfo() {
+	if Version != "" && Version != "dev" {
+		buildinfo.Version = Version
+	}
+	if Commit != "" {
+		buildinfo.Commit = Commit
+	}
+	if BuildTime != "" {
+		buildinfo.BuildTime = BuildTime
+	}
+}
+
+func (a *App) configCheckCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "config-check",
+		Short: "Validate the config file",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if _, err := os.Stat(a.configFile); err != nil {
+				return fmt.Errorf("cli: config file: %w", err)
+			}
+			if err := config.Check(a.sources()...); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: ok\n", a.configFile)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/go-admin-team/go-admin-core/sdk/config"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/migrate"
+	"github.com/go-admin-team/go-admin-core/server/health"
+)
+
+const settings = `
+settings:
+  application:
+    port: 8000
+  logger:
+    level: info
+    path: {dir}/logs
+`
+
+func writeConfig(t *testing.T, content string) string {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "settings.yml")
+	content = strings.ReplaceAll(content, "{dir}", dir)
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func run(a *App, args ...string) (string, error) {
+	out := new(bytes.Buffer)
+	a.Root().SetOut(out)
+	a.Root().SetErr(out)
+	a.Root().SetArgs(args)
+	err := a.Execute()
+	return out.String(), err
+}
+
+func TestVersion(t *testing.T) {
+	out, err := run(New("go-admin"), "version")
+	if err != nil || !strings.HasPrefix(out, "go-admin dev\n") {
+		t.Errorf("out = %q, err = %v", out, err)
+	}
+}
+
+func TestConfigCheck(t *testing.T) {
+	out, err := run(New("go-admin"), "config-check", "-c", writeConfig(t, settings))
+	if err != nil || !strings.Contains(out, "ok") {
+		t.Errorf("out = %q, err = %v", out, err)
+	}
+	_, err = run(New("go-admin"), "config-check", "-c", writeConfig(t, strings.Replace(settings, "8000", "70000", 1)))
+	if _, ok := err.(*config.ValidateError); !ok {
+		t.Errorf("err = %v", err)
+	}
+	if _, err = run(New("go-admin"), "config-check", "-c", "not-exist.yml"); err == nil {
+		t.Error("missing config file should fail")
+	}
+}
+
+func TestServer(t *testing.T) {
+	if _, err := run(New("go-admin"), "server"); err != ErrNoServer {
+		t.Errorf("err = %v", err)
+	}
+	var port int64
+	a := New("go-admin", WithServer(func(ctx context.Context, a *App) error {
+		port = config.ApplicationConfig.Port
+		return nil
+	}))
+	if _, err := run(a, "server", "--config", writeConfig(t, settings)); err != nil || port != 8000 {
+		t.Errorf("port = %d, err = %v", port, err)
+	}
+
+	health.Register("cli-test", func(context.Context) error { return errors.New("refused") })
+	defer health.Default().Unregister("cli-test")
+	port = 0
+	a = New("go-admin", WithStartupGate(50*time.Millisecond), WithServer(func(ctx context.Context, a *App) error {
+		port = config.ApplicationConfig.Port
+		return nil
+	}))
+	if _, err := run(a, "server", "--config", writeConfig(t, settings)); !errors.Is(err, health.ErrNotReady) || port != 0 {
+		t.Errorf("gate: port = %d, err = %v", port, err)
+	}
+}
+
+type user struct {
+	ID int
+}
+
+func TestMigrate(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := writeConfig(t, settings)
+	newApp := func() *App {
+		return New("go-admin",
+			WithDefaultConfig(cfg),
+			WithDB(func() (*gorm.DB, error) { return db, nil }),
+			WithMigrate(migrate.WithMigrations(&migrate.Migration{
+				Version: "20221020000001",
+				Name:    "create user",
+				Up: func(tx *gorm.DB) error {
+					return tx.Migrator().CreateTable(&user{})
+				},
+			})),
+		)
+	}
+	out, err := run(newApp(), "migrate", "up", "--dry-run")
+	if err != nil || !strings.Contains(out, "CREATE TABLE") {
+		t.Errorf("out = %q, err = %v", out, err)
+	}
+	if _, err = run(newApp(), "migrate", "up"); err != nil || !db.Migrator().HasTable(&user{}) {
+		t.Errorf("err = %v", err)
+	}
+	out, err = run(newApp(), "migrate", "status")
+	if err != nil || !strings.Contains(out, "20221020000001") || strings.Contains(out, "pending") {
+		t.Errorf("out = %q, err = %v", out, err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(func(a *App) *cobra.Command {
+		return &cobra.Command{Use: "plugin", Run: func(cmd *cobra.Command, _ []string) {
+			cmd.Print(a.ConfigFile())
+		}}
+	})
+	out, err := run(New("go-admin"), "plugin", "-c", "app.yml")
+	if err != nil || out != "app.yml" {
+		t.Errorf("out = %q, err = %v", out, err)
+	}
+}
+
+func TestGen(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.Exec("CREATE TABLE sys_dict (dict_id integer PRIMARY KEY, dict_name varchar(64))").Error; err != nil {
+		t.Fatal(err)
+	}
+	a := New("go-admin",
+		WithDefaultConfig(writeConfig(t, settings)),
+		WithDB(func() (*gorm.DB, error) { return db, nil }),
+	)
+	out, err := run(a, "gen", "-t", "sys_dict", "-m", "go-admin/app/admin", "-o", t.TempDir(), "--dry-run")
+	if err != nil || strings.Count(out, "sys_dict.go") != 5 {
+		t.Errorf("out = %q, err = %v", out, err)
+	}
+}
+
+func TestCacheUsage(t *testing.T) {
+	out, err := run(New("go-admin"), "cache", "usage", "-c", writeConfig(t, settings), "--depth", "2")
+	if err != nil || !strings.Contains(out, "memory: sampled 0 of 0 keys") || !strings.Contains(out, "TTL none") {
+		t.Errorf("out = %q, err = %v", out, err)
+	}
+}
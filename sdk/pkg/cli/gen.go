@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/gen"
+)
+
+func (a *App) genCommand() *cobra.Command {
+	var (
+		tables    []string
+		module    string
+		output    string
+		templates string
+		force     bool
+		dryRun    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "gen",
+		Short: "Generate CRUD code from database tables",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if a.opts.db == nil {
+				return ErrNoDB
+			}
+			if err := a.LoadConfig(); err != nil {
+				return err
+			}
+			db, err := a.opts.db()
+			if err != nil {
+				return err
+			}
+			opts := []gen.Option{gen.WithModule(module), gen.WithOutput(output), gen.WithForce(force)}
+			if templates != "" {
+				opts = append(opts, gen.WithTemplates(os.DirFS(templates)))
+			}
+			g, err := gen.New(opts...)
+			if err != nil {
+				return err
+			}
+			files := make([]gen.File, 0)
+			for _, name := range tables {
+				t, err := gen.FromDB(db, name)
+				if err != nil {
+					return err
+				}
+				list, err := g.Generate(t)
+				if err != nil {
+					return err
+				}
+				files = append(files, list...)
+			}
+			if !dryRun {
+				if err = g.Write(files); err != nil {
+					return err
+				}
+			}
+			for _, f := range files {
+				fmt.Fprintln(cmd.OutOrStdout(), f.Path)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVarP(&tables, "table", "t", nil, "table names")
+	cmd.Flags().StringVarP(&module, "module", "m", "", "import path of the output directory, e.g. go-admin/app/admin")
+	cmd.Flags().StringVarP(&output, "output", "o", ".", "output directory")
+	cmd.Flags().StringVar(&templates, "templates", "", "directory of templates overriding the defaults")
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite existing files")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the file list without writing")
+	_ = cmd.MarkFlagRequired("table")
+	_ = cmd.MarkFlagRequired("module")
+	return cmd
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-admin-team/go-admin-core/sdk/config"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/migrate"
+)
+
+func (a *App) migrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Run database migrations",
+	}
+
+	var (
+		target string
+		dryRun bool
+		steps  int
+	)
+	up := &cobra.Command{
+		Use:   "up",
+		Short: "Apply pending migrations",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			m, err := a.migrator()
+			if err != nil {
+				return err
+			}
+			plans, err := m.Up(cmd.Context(), target, dryRun)
+			if err == nil || len(plans) > 0 {
+				printPlans(cmd.OutOrStdout(), plans, dryRun)
+			}
+			return err
+		},
+	}
+	up.Flags().StringVar(&target, "target", "", "apply up to this version (inclusive)")
+	up.Flags().BoolVar(&dryRun, "dry-run", false, "print the plan and SQL without applying")
+
+	down := &cobra.Command{
+		Use:   "down",
+		Short: "Roll back applied migrations",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			m, err := a.migrator()
+			if err != nil {
+				return err
+			}
+			plans, err := m.Down(cmd.Context(), steps, dryRun)
+			if err == nil || len(plans) > 0 {
+				printPlans(cmd.OutOrStdout(), plans, dryRun)
+			}
+			return err
+		},
+	}
+	down.Flags().IntVar(&steps, "steps", 1, "number of migrations to roll back")
+	down.Flags().BoolVar(&dryRun, "dry-run", false, "print the plan and SQL without applying")
+
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "Show migration status",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			m, err := a.migrator()
+			if err != nil {
+				return err
+			}
+			list, err := m.Status(cmd.Context())
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+			for _, s := range list {
+				at := "pending"
+				if s.AppliedAt != nil {
+					at = s.AppliedAt.Format("2006-01-02 15:04:05")
+				}
+				if s.Missing {
+					at += " (missing)"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", s.Version, s.Name, at)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.AddCommand(up, down, status)
+	return cmd
+}
+
+// migrator 载入配置后创建, 未通过 WithMigrate 指定锁时使用配置中的 locker
+func (a *App) migrator() (*migrate.Migrator, error) {
+	if a.opts.db == nil {
+		return nil, ErrNoDB
+	}
+	if err := a.LoadConfig(); err != nil {
+		return nil, err
+	}
+	db, err := a.opts.db()
+	if err != nil {
+		return nil, err
+	}
+	opts := make([]migrate.Option, 0, len(a.opts.migrateOpts)+1)
+	if !config.LockerConfig.Empty() {
+		l, err := config.LockerConfig.Setup()
+		if err != nil {
+			return nil, err
+		}
+		if l != nil {
+			opts = append(opts, migrate.WithLocker(l, 0))
+		}
+	}
+	opts = append(opts, a.opts.migrateOpts...)
+	return migrate.New(db, opts...), nil
+}
+
+func printPlans(w io.Writer, plans []migrate.Plan, dryRun bool) {
+	if len(plans) == 0 {
+		fmt.Fprintln(w, "nothing to do")
+		return
+	}
+	for _, p := range plans {
+		fmt.Fprintf(w, "%s %s\n", p.Version, p.Name)
+		if dryRun {
+			for _, sql := range p.SQL {
+				fmt.Fprintf(w, "    %s;\n", sql)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package cronjob
+
+import (
+	"time"
+)
+
+// 任务状态
+const (
+	JobEnabled  = 1
+	JobDisabled = 2
+)
+
+// 错过执行时间(服务停机等)后的处理策略
+const (
+	// MisfireIgnore 忽略错过的执行, 等待下一次
+	MisfireIgnore = 0
+	// MisfireFireOnce 启动时立即补执行一次
+	MisfireFireOnce = 1
+)
+
+// 触发方式
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+	TriggerMisfire  = "misfire"
+)
+
+// 执行结果
+const (
+	RunSuccess = "success"
+	RunFailed  = "failed"
+	// RunSkipped 上次执行未结束或其他节点已执行
+	RunSkipped = "skipped"
+)
+
+// Job 定时任务定义, 表 sys_cron_job
+type Job struct {
+	ID   int    `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name string `json:"name" gorm:"size:128"`
+	// Group 任务分组, 仅用于展示
+	Group string `json:"group" gorm:"size:64;index"`
+	// Spec cron 表达式, 支持秒, 如 "0 */5 * * * *"、"@every 1m"
+	Spec string `json:"spec" gorm:"size:64"`
+	// Target 通过 Register 注册的处理函数名
+	Target string `json:"target" gorm:"size:128"`
+	// Args 传给处理函数的参数, 格式由处理函数决定
+	Args    string `json:"args" gorm:"type:text"`
+	Status  int    `json:"status" gorm:"index"`
+	Misfire int    `json:"misfire"`
+	// Concurrent 为false时上次执行未结束则跳过本次
+	Concurrent bool `json:"concurrent"`
+	// Exclusive 多节点部署时通过 Locker 保证每次只有一个节点执行
+	Exclusive bool `json:"exclusive"`
+	// Timeout 执行超时(秒), 0为不限制
+	Timeout   int        `json:"timeout"`
+	Remark    string     `json:"remark" gorm:"size:255"`
+	LastRunAt *time.Time `json:"lastRunAt"`
+	NextRunAt *time.Time `json:"nextRunAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+func (Job) TableName() string {
+	return "sys_cron_job"
+}
+
+// JobLog 执行记录, 表 sys_cron_job_log
+type JobLog struct {
+	ID      int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	JobID   int    `json:"jobId" gorm:"index"`
+	Name    string `json:"name" gorm:"size:128"`
+	Trigger string `json:"trigger" gorm:"size:16"`
+	Status  string `json:"status" gorm:"size:16;index"`
+	Error   string `json:"error" gorm:"type:text"`
+	// Node 执行节点, 默认为主机名
+	Node      string    `json:"node" gorm:"size:128"`
+	StartedAt time.Time `json:"startedAt" gorm:"index"`
+	// Duration 执行耗时(毫秒)
+	Duration int64 `json:"duration"`
+}
+
+func (JobLog) TableName() string {
+	return "sys_cron_job_log"
+}
@@ -0,0 +1,414 @@
+package cronjob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robfig/cron/v3"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/tools/clock"
+)
+
+var (
+	ErrJobNotFound     = errors.New("cronjob: job not found")
+	ErrHandlerNotFound = errors.New("cronjob: handler not found")
+)
+
+func init() {
+	response.RegisterError(ErrJobNotFound, response.ErrNotFound)
+}
+
+// Handler 任务处理函数, ctx 在超时或停止调度时取消
+type Handler func(ctx context.Context, job *Job) error
+
+var (
+	handlerMux sync.RWMutex
+	handlers   = make(map[string]Handler)
+)
+
+// Register 注册处理函数, 任务的 Target 对应 name
+func Register(name string, h Handler) {
+	handlerMux.Lock()
+	defer handlerMux.Unlock()
+	handlers[name] = h
+}
+
+// Handlers 已注册的处理函数名, 供任务管理页面选择
+func Handlers() []string {
+	handlerMux.RLock()
+	defer handlerMux.RUnlock()
+	names := make([]string, 0, len(handlers))
+	for name := range handlers {
+		names = append(names, name)
+	}
+	return names
+}
+
+func getHandler(name string) (Handler, bool) {
+	handlerMux.RLock()
+	defer handlerMux.RUnlock()
+	h, ok := handlers[name]
+	return h, ok
+}
+
+type Option func(*options)
+
+type options struct {
+	cron    *cron.Cron
+	parser  cron.Parser
+	locker  storage.AdapterLocker
+	lockTTL int64
+	node    string
+	hooks   []func(ctx context.Context, job *Job, l *JobLog)
+	clock   clock.Clock
+}
+
+func setDefault() options {
+	node, _ := os.Hostname()
+	return options{
+		parser: cron.NewParser(cron.Second | cron.Minute |
+			cron.Hour | cron.Dom | cron.Month | cron.DowOptional | cron.Descriptor),
+		lockTTL: 60,
+		node:    node,
+		clock:   clock.Real,
+	}
+}
+
+// WithCron 使用已有的 cron, 其 parser 需支持秒, 默认 NewWithSeconds
+func WithCron(c *cron.Cron) Option {
+	return func(o *options) {
+		o.cron = c
+	}
+}
+
+// WithLocker Exclusive 任务使用的分布式锁, ttl 为锁的有效期(秒), 应不小于节点间的时钟偏差
+func WithLocker(l storage.AdapterLocker, ttl int64) Option {
+	return func(o *options) {
+		o.locker = l
+		if ttl > 0 {
+			o.lockTTL = ttl
+		}
+	}
+}
+
+// WithNode 节点名, 记录到执行记录中
+func WithNode(node string) Option {
+	return func(o *options) {
+		o.node = node
+	}
+}
+
+// WithHook 每次执行结束后调用, 可用于推送执行结果到页面或发送告警
+func WithHook(f func(ctx context.Context, job *Job, l *JobLog)) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, f)
+	}
+}
+
+// WithClock 判断错过的执行、记录执行时间与耗时使用的时间源, 测试中使用 clock.Fake;
+// cron 自身的触发仍使用系统时间
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// Scheduler 按 Store 中的任务定义调度执行
+type Scheduler struct {
+	store   Store
+	opts    options
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mux     sync.Mutex
+	entries map[int]cron.EntryID
+	running map[int]bool
+	wg      sync.WaitGroup
+}
+
+func New(store Store, opts ...Option) *Scheduler {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.cron == nil {
+		o.cron = NewWithSeconds()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		store:   store,
+		opts:    o,
+		ctx:     ctx,
+		cancel:  cancel,
+		entries: make(map[int]cron.EntryID),
+		running: make(map[int]bool),
+	}
+}
+
+// Cron 底层的 cron, 可通过 runtime 的 SetCrontab 管理
+func (s *Scheduler) Cron() *cron.Cron {
+	return s.opts.cron
+}
+
+// Start 加载全部启用的任务并开始调度, 按 Misfire 策略处理停机期间错过的执行
+func (s *Scheduler) Start(ctx context.Context) error {
+	list, err := s.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	now := s.opts.clock.Now()
+	for i := range list {
+		job := &list[i]
+		if job.Status != JobEnabled {
+			continue
+		}
+		if err = s.add(job); err != nil {
+			logger.Module("sdk.cronjob").WithContext(ctx).
+				Error("add job failed", "job", job.ID, "name", job.Name, "error", err)
+			continue
+		}
+		if missed := s.missed(job, now); !missed.IsZero() && job.Misfire == MisfireFireOnce {
+			s.goRun(job, TriggerMisfire, missed)
+		}
+	}
+	s.opts.cron.Start()
+	return nil
+}
+
+// Stop 停止调度并等待执行中的任务结束, ctx 超时后取消任务的 ctx
+func (s *Scheduler) Stop(ctx context.Context) {
+	s.opts.cron.Stop()
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.cancel()
+		<-done
+	}
+}
+
+// missed 最近一次执行之后第一个应执行而未执行的时间点, 没有时为零值
+func (s *Scheduler) missed(job *Job, now time.Time) time.Time {
+	if job.LastRunAt == nil {
+		return time.Time{}
+	}
+	schedule, err := s.opts.parser.Parse(job.Spec)
+	if err != nil {
+		return time.Time{}
+	}
+	if next := schedule.Next(*job.LastRunAt); !next.IsZero() && next.Before(now) {
+		return next
+	}
+	return time.Time{}
+}
+
+// Reload 修改任务后重新加载, 已删除或停用的任务会从调度中移除
+func (s *Scheduler) Reload(ctx context.Context, id int) error {
+	s.Remove(id)
+	job, err := s.store.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrJobNotFound) {
+			return nil
+		}
+		return err
+	}
+	if job.Status != JobEnabled {
+		return nil
+	}
+	return s.add(job)
+}
+
+// Remove 从调度中移除任务
+func (s *Scheduler) Remove(id int) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if entry, ok := s.entries[id]; ok {
+		s.opts.cron.Remove(entry)
+		delete(s.entries, id)
+	}
+}
+
+// Next 任务的下次执行时间, 未调度时为零值
+func (s *Scheduler) Next(id int) time.Time {
+	s.mux.Lock()
+	entry, ok := s.entries[id]
+	s.mux.Unlock()
+	if !ok {
+		return time.Time{}
+	}
+	return s.opts.cron.Entry(entry).Next
+}
+
+func (s *Scheduler) add(job *Job) error {
+	if _, ok := getHandler(job.Target); !ok {
+		return fmt.Errorf("%w: %s", ErrHandlerNotFound, job.Target)
+	}
+	schedule, err := s.opts.parser.Parse(job.Spec)
+	if err != nil {
+		return err
+	}
+	id := job.ID
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.entries[id] = s.opts.cron.Schedule(schedule, cron.FuncJob(func() {
+		s.wg.Add(1)
+		defer s.wg.Done()
+		// 每次执行前读取最新的定义, 参数修改无需 Reload
+		current, err := s.store.Get(s.ctx, id)
+		if err != nil {
+			logger.Module("sdk.cronjob").Error("load job failed", "job", id, "error", err)
+			return
+		}
+		s.run(current, TriggerSchedule, s.prev(id))
+	}))
+	return nil
+}
+
+// Trigger 立即异步执行一次, 不受启用状态与分布式锁限制
+func (s *Scheduler) Trigger(ctx context.Context, id int) error {
+	job, err := s.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, ok := getHandler(job.Target); !ok {
+		return fmt.Errorf("%w: %s", ErrHandlerNotFound, job.Target)
+	}
+	s.goRun(job, TriggerManual, s.opts.clock.Now())
+	return nil
+}
+
+// TriggerHandler 手动执行接口, 路径参数 id 为任务id, 需自行挂载到有鉴权的路由
+func (s *Scheduler) TriggerHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			response.Fail(c, response.ErrBadRequest.Wrap(err))
+			return
+		}
+		if err = s.Trigger(c.Request.Context(), id); err != nil {
+			response.Fail(c, err)
+			return
+		}
+		response.OK(c, id, "")
+	}
+}
+
+// prev 本次计划执行的时间
+func (s *Scheduler) prev(id int) time.Time {
+	s.mux.Lock()
+	entry, ok := s.entries[id]
+	s.mux.Unlock()
+	if ok {
+		if prev := s.opts.cron.Entry(entry).Prev; !prev.IsZero() {
+			return prev
+		}
+	}
+	return s.opts.clock.Now()
+}
+
+func (s *Scheduler) goRun(job *Job, trigger string, scheduled time.Time) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(job, trigger, scheduled)
+	}()
+}
+
+func (s *Scheduler) run(job *Job, trigger string, scheduled time.Time) {
+	start := s.opts.clock.Now()
+	l := &JobLog{JobID: job.ID, Name: job.Name, Trigger: trigger, Node: s.opts.node, StartedAt: start}
+	log := logger.Module("sdk.cronjob").With("job", job.ID, "name", job.Name, "trigger", trigger)
+
+	if reason := s.acquire(job, trigger, scheduled); reason != "" {
+		l.Status, l.Error = RunSkipped, reason
+		log.Info("job skipped", "reason", reason)
+		s.finish(job, l, false)
+		return
+	}
+	defer s.release(job)
+
+	ctx := s.ctx
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(job.Timeout)*time.Second)
+		defer cancel()
+	}
+	err := s.call(ctx, job)
+	l.Duration = s.opts.clock.Since(start).Milliseconds()
+	l.Status = RunSuccess
+	if err != nil {
+		l.Status, l.Error = RunFailed, err.Error()
+		log.Error("job failed", "duration", l.Duration, "error", err)
+	} else {
+		log.Info("job finished", "duration", l.Duration)
+	}
+	s.finish(job, l, true)
+}
+
+// acquire 检查并发与分布式锁, 返回跳过的原因
+func (s *Scheduler) acquire(job *Job, trigger string, scheduled time.Time) string {
+	s.mux.Lock()
+	if !job.Concurrent && s.running[job.ID] {
+		s.mux.Unlock()
+		return "previous run not finished"
+	}
+	s.running[job.ID] = true
+	s.mux.Unlock()
+	if trigger == TriggerManual || !job.Exclusive || s.opts.locker == nil {
+		return ""
+	}
+	// 以计划执行时间为key且不主动释放, 保证各节点同一时刻只执行一次
+	key := "cronjob:" + strconv.Itoa(job.ID) + ":" + strconv.FormatInt(scheduled.Unix(), 10)
+	if _, err := s.opts.locker.Lock(key, s.opts.lockTTL, nil); err != nil {
+		s.release(job)
+		return "locked by other node"
+	}
+	return ""
+}
+
+func (s *Scheduler) release(job *Job) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	delete(s.running, job.ID)
+}
+
+func (s *Scheduler) call(ctx context.Context, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cronjob: panic: %v", r)
+		}
+	}()
+	h, ok := getHandler(job.Target)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrHandlerNotFound, job.Target)
+	}
+	return h(ctx, job)
+}
+
+func (s *Scheduler) finish(job *Job, l *JobLog, ran bool) {
+	ctx := context.Background()
+	log := logger.Module("sdk.cronjob").With("job", job.ID, "name", job.Name)
+	if err := s.store.AddLog(ctx, l); err != nil {
+		log.Error("save job log failed", "error", err)
+	}
+	if ran {
+		if err := s.store.UpdateRun(ctx, job.ID, l.StartedAt, s.Next(job.ID)); err != nil {
+			log.Error("update job failed", "error", err)
+		}
+	}
+	for _, hook := range s.opts.hooks {
+		hook(ctx, job, l)
+	}
+}
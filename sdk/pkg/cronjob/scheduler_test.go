@@ -0,0 +1,240 @@
+package cronjob
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsm/redislock"
+
+	"github.com/go-admin-team/go-admin-core/tools/clock"
+)
+
+type memoryStore struct {
+	mux  sync.Mutex
+	jobs map[int]*Job
+	logs []JobLog
+}
+
+func newMemoryStore(jobs ...Job) *memoryStore {
+	s := &memoryStore{jobs: make(map[int]*Job)}
+	for i := range jobs {
+		s.jobs[jobs[i].ID] = &jobs[i]
+	}
+	return s
+}
+
+func (s *memoryStore) List(context.Context) ([]Job, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	list := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		list = append(list, *job)
+	}
+	return list, nil
+}
+
+func (s *memoryStore) Get(_ context.Context, id int) (*Job, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	c := *job
+	return &c, nil
+}
+
+func (s *memoryStore) UpdateRun(_ context.Context, id int, last, _ time.Time) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.jobs[id].LastRunAt = &last
+	return nil
+}
+
+func (s *memoryStore) AddLog(_ context.Context, l *JobLog) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.logs = append(s.logs, *l)
+	return nil
+}
+
+func (s *memoryStore) results() []JobLog {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return append([]JobLog(nil), s.logs...)
+}
+
+type memoryLocker struct {
+	mux  sync.Mutex
+	keys map[string]bool
+}
+
+func (*memoryLocker) String() string { return "memory" }
+
+func (l *memoryLocker) Lock(key string, _ int64, _ *redislock.Options) (*redislock.Lock, error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if l.keys[key] {
+		return nil, redislock.ErrNotObtained
+	}
+	l.keys[key] = true
+	return nil, nil
+}
+
+func TestTrigger(t *testing.T) {
+	block := make(chan struct{})
+	Register("test.block", func(ctx context.Context, job *Job) error {
+		<-block
+		if job.Args == "fail" {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	Register("test.panic", func(context.Context, *Job) error {
+		panic("boom")
+	})
+	store := newMemoryStore(
+		Job{ID: 1, Name: "block", Spec: "@every 1h", Target: "test.block", Args: "fail", Status: JobEnabled},
+		Job{ID: 2, Name: "panic", Spec: "@every 1h", Target: "test.panic", Status: JobDisabled},
+		Job{ID: 3, Name: "unknown", Spec: "@every 1h", Target: "test.unknown", Status: JobEnabled},
+	)
+	done := make(chan *JobLog, 10)
+	s := New(store, WithNode("node1"), WithHook(func(_ context.Context, _ *Job, l *JobLog) {
+		done <- l
+	}))
+	ctx := context.Background()
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop(ctx)
+
+	if err := s.Trigger(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if err := s.Trigger(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if l := <-done; l.Status != RunSkipped {
+		t.Errorf("second run should be skipped, got %+v", l)
+	}
+	close(block)
+	if l := <-done; l.Status != RunFailed || l.Error != "failed" || l.Trigger != TriggerManual || l.Node != "node1" {
+		t.Errorf("unexpected log %+v", l)
+	}
+	if err := s.Trigger(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if l := <-done; l.Status != RunFailed {
+		t.Errorf("panic should be recovered as failure, got %+v", l)
+	}
+	if err := s.Trigger(ctx, 3); !errors.Is(err, ErrHandlerNotFound) {
+		t.Errorf("err = %v", err)
+	}
+	if err := s.Trigger(ctx, 4); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("err = %v", err)
+	}
+	if job, _ := store.Get(ctx, 1); job.LastRunAt == nil {
+		t.Error("last run not updated")
+	}
+	if s.Next(1).IsZero() || !s.Next(2).IsZero() {
+		t.Error("only enabled jobs should be scheduled")
+	}
+}
+
+func TestExclusive(t *testing.T) {
+	var (
+		mux   sync.Mutex
+		count int
+	)
+	Register("test.count", func(context.Context, *Job) error {
+		mux.Lock()
+		defer mux.Unlock()
+		count++
+		return nil
+	})
+	job := Job{ID: 1, Name: "count", Spec: "* * * * * *", Target: "test.count", Status: JobEnabled, Exclusive: true}
+	locker := &memoryLocker{keys: make(map[string]bool)}
+	ctx := context.Background()
+	var stores []*memoryStore
+	for i := 0; i < 2; i++ {
+		store := newMemoryStore(job)
+		stores = append(stores, store)
+		s := New(store, WithLocker(locker, 10))
+		if err := s.Start(ctx); err != nil {
+			t.Fatal(err)
+		}
+		defer s.Stop(ctx)
+	}
+	time.Sleep(1500 * time.Millisecond)
+
+	mux.Lock()
+	defer mux.Unlock()
+	skipped := 0
+	for _, store := range stores {
+		for _, l := range store.results() {
+			if l.Status == RunSkipped {
+				skipped++
+			}
+		}
+	}
+	if count == 0 || skipped != count {
+		t.Errorf("count = %d, skipped = %d", count, skipped)
+	}
+}
+
+func TestMisfire(t *testing.T) {
+	ran := make(chan string, 2)
+	Register("test.misfire", func(_ context.Context, job *Job) error {
+		ran <- job.Name
+		return nil
+	})
+	last := time.Now().Add(-10 * time.Minute)
+	store := newMemoryStore(
+		Job{ID: 1, Name: "once", Spec: "@every 1m", Target: "test.misfire", Status: JobEnabled, Misfire: MisfireFireOnce, LastRunAt: &last},
+		Job{ID: 2, Name: "ignore", Spec: "@every 1m", Target: "test.misfire", Status: JobEnabled, LastRunAt: &last},
+	)
+	s := New(store)
+	ctx := context.Background()
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s.Stop(ctx)
+	close(ran)
+	var names []string
+	for name := range ran {
+		names = append(names, name)
+	}
+	if len(names) != 1 || names[0] != "once" {
+		t.Errorf("ran = %v", names)
+	}
+	if logs := store.results(); len(logs) != 1 || logs[0].Trigger != TriggerMisfire {
+		t.Errorf("logs = %+v", logs)
+	}
+}
+
+func TestClock(t *testing.T) {
+	Register("test.clock", func(context.Context, *Job) error { return nil })
+	last := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := newMemoryStore(Job{ID: 1, Name: "clock", Spec: "@every 1h", Target: "test.clock",
+		Status: JobEnabled, Misfire: MisfireFireOnce, LastRunAt: &last})
+	c := clock.NewFake(last.Add(30 * time.Minute))
+	s := New(store, WithClock(c))
+	ctx := context.Background()
+	_ = s.Start(ctx)
+	s.Stop(ctx)
+	if logs := store.results(); len(logs) != 0 {
+		t.Fatalf("not missed yet, logs = %+v", logs)
+	}
+
+	c.Advance(time.Hour)
+	s = New(store, WithClock(c))
+	_ = s.Start(ctx)
+	s.Stop(ctx)
+	if logs := store.results(); len(logs) != 1 || !logs[0].StartedAt.Equal(c.Now()) {
+		t.Errorf("logs = %+v", logs)
+	}
+}
@@ -0,0 +1,90 @@
+package cronjob
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Store 任务定义与执行记录的存储
+type Store interface {
+	// List 全部任务
+	List(ctx context.Context) ([]Job, error)
+	Get(ctx context.Context, id int) (*Job, error)
+	// UpdateRun 更新最近与下次执行时间
+	UpdateRun(ctx context.Context, id int, last, next time.Time) error
+	AddLog(ctx context.Context, l *JobLog) error
+}
+
+// GormStore 存储到数据库
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore migrate 为true时自动建表
+func NewGormStore(db *gorm.DB, migrate bool) (*GormStore, error) {
+	if migrate {
+		if err := db.AutoMigrate(&Job{}, &JobLog{}); err != nil {
+			return nil, err
+		}
+	}
+	return &GormStore{db: db}, nil
+}
+
+func (s *GormStore) List(ctx context.Context) ([]Job, error) {
+	list := make([]Job, 0)
+	err := s.db.WithContext(ctx).Order("id").Find(&list).Error
+	return list, err
+}
+
+func (s *GormStore) Get(ctx context.Context, id int) (*Job, error) {
+	job := &Job{}
+	if err := s.db.WithContext(ctx).First(job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *GormStore) UpdateRun(ctx context.Context, id int, last, next time.Time) error {
+	values := map[string]interface{}{"last_run_at": last}
+	if !next.IsZero() {
+		values["next_run_at"] = next
+	}
+	return s.db.WithContext(ctx).Model(&Job{}).Where("id = ?", id).UpdateColumns(values).Error
+}
+
+func (s *GormStore) AddLog(ctx context.Context, l *JobLog) error {
+	return s.db.WithContext(ctx).Create(l).Error
+}
+
+// Logs 分页查询执行记录, jobID 为0时查询全部
+func (s *GormStore) Logs(ctx context.Context, jobID, pageIndex, pageSize int) ([]JobLog, int64, error) {
+	db := s.db.WithContext(ctx).Model(&JobLog{})
+	if jobID > 0 {
+		db = db.Where("job_id = ?", jobID)
+	}
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	if pageIndex < 1 {
+		pageIndex = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	list := make([]JobLog, 0)
+	err := db.Order("id desc").Offset((pageIndex - 1) * pageSize).Limit(pageSize).Find(&list).Error
+	return list, count, err
+}
+
+// Clean 删除 before 之前的执行记录
+func (s *GormStore) Clean(ctx context.Context, before time.Time) (int64, error) {
+	db := s.db.WithContext(ctx).Where("started_at < ?", before).Delete(&JobLog{})
+	return db.RowsAffected, db.Error
+}
@@ -0,0 +1,177 @@
+// Package ctxkit 请求上下文中的常用值(操作人、角色、部门、权限、客户端ip、语言)的存取,
+// 中间件写入一次, service、gorm scope、审计与日志统一从 ctx 读取, 不再各自解析 jwt claims
+//
+//	r.Use(authMiddleware.MiddlewareFunc(), ctxkit.Middleware())
+//	deptID, ok := ctxkit.DeptID(ctx)
+package ctxkit
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/i18n"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/jwtauth"
+)
+
+type bagKey struct{}
+
+// bag ctx中保存的值, 修改时复制, 不影响父ctx
+type bag struct {
+	roleIDs     []int
+	deptID      int
+	hasDept     bool
+	permissions map[string]bool
+	clientIP    string
+}
+
+func from(ctx context.Context) bag {
+	if ctx == nil {
+		return bag{}
+	}
+	b, _ := ctx.Value(bagKey{}).(*bag)
+	if b == nil {
+		return bag{}
+	}
+	return *b
+}
+
+func with(ctx context.Context, f func(b *bag)) context.Context {
+	b := from(ctx)
+	f(&b)
+	return context.WithValue(ctx, bagKey{}, &b)
+}
+
+// WithOperatorID 设置操作人, 与 logger.WithOperatorID 相同, 日志与审计自动带上
+func WithOperatorID(ctx context.Context, id string) context.Context {
+	return logger.WithOperatorID(ctx, id)
+}
+
+// OperatorID 操作人, 未设置时为空
+func OperatorID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	return logger.OperatorID(ctx)
+}
+
+// OperatorInt64 数字形式的操作人
+func OperatorInt64(ctx context.Context) (int64, bool) {
+	id, err := strconv.ParseInt(OperatorID(ctx), 10, 64)
+	return id, err == nil && id != 0
+}
+
+// WithRoleIDs 设置角色
+func WithRoleIDs(ctx context.Context, ids ...int) context.Context {
+	return with(ctx, func(b *bag) {
+		b.roleIDs = append([]int(nil), ids...)
+	})
+}
+
+// RoleIDs 角色, 未设置时为空
+func RoleIDs(ctx context.Context) []int {
+	return append([]int(nil), from(ctx).roleIDs...)
+}
+
+// HasRole 是否拥有角色
+func HasRole(ctx context.Context, id int) bool {
+	for _, r := range from(ctx).roleIDs {
+		if r == id {
+			return true
+		}
+	}
+	return false
+}
+
+// WithDeptID 设置部门
+func WithDeptID(ctx context.Context, id int) context.Context {
+	return with(ctx, func(b *bag) {
+		b.deptID, b.hasDept = id, true
+	})
+}
+
+// DeptID 部门, 未设置时返回 false
+func DeptID(ctx context.Context) (int, bool) {
+	b := from(ctx)
+	return b.deptID, b.hasDept
+}
+
+// WithPermissions 设置权限标识, 如 "system:user:add"
+func WithPermissions(ctx context.Context, perms ...string) context.Context {
+	return with(ctx, func(b *bag) {
+		b.permissions = make(map[string]bool, len(perms))
+		for _, p := range perms {
+			b.permissions[p] = true
+		}
+	})
+}
+
+// Permissions 权限标识, 无序
+func Permissions(ctx context.Context) []string {
+	perms := from(ctx).permissions
+	list := make([]string, 0, len(perms))
+	for p := range perms {
+		list = append(list, p)
+	}
+	return list
+}
+
+// HasPermission 是否拥有权限标识
+func HasPermission(ctx context.Context, perm string) bool {
+	return from(ctx).permissions[perm]
+}
+
+// WithClientIP 设置客户端ip
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return with(ctx, func(b *bag) {
+		b.clientIP = ip
+	})
+}
+
+// ClientIP 客户端ip, 未设置时为空
+func ClientIP(ctx context.Context) string {
+	return from(ctx).clientIP
+}
+
+// WithLocale 设置语言, 与 i18n.WithLang 相同
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return i18n.WithLang(ctx, locale)
+}
+
+// Locale 语言, 未设置时为 i18n 的默认语言
+func Locale(ctx context.Context) string {
+	if ctx == nil {
+		return i18n.Default().Default()
+	}
+	return i18n.FromContext(ctx)
+}
+
+// FromClaims 按 jwt claims 设置操作人、角色与部门, 缺少的 claim 跳过
+func FromClaims(ctx context.Context, claims jwtauth.MapClaims) context.Context {
+	if id, err := claims.Identity(); err == nil {
+		ctx = WithOperatorID(ctx, strconv.FormatInt(id, 10))
+	}
+	if id, err := claims.Int(jwtauth.RoleIdKey); err == nil {
+		ctx = WithRoleIDs(ctx, id)
+	}
+	if id, err := claims.Int("deptid"); err == nil {
+		ctx = WithDeptID(ctx, id)
+	}
+	return ctx
+}
+
+// Middleware 挂在 jwt 鉴权之后, 从 claims 写入操作人、角色、部门, 并写入客户端ip与请求语言
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if claims := jwtauth.ClaimsFromContext(ctx); len(claims) > 0 {
+			ctx = FromClaims(ctx, claims)
+		}
+		ctx = WithClientIP(ctx, c.ClientIP())
+		ctx = WithLocale(ctx, i18n.Lang(c))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,55 @@
+package ctxkit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/jwtauth"
+)
+
+func TestValues(t *testing.T) {
+	parent := WithRoleIDs(context.Background(), 1, 2)
+	ctx := WithPermissions(WithDeptID(parent, 3), "system:user:add")
+	if !HasRole(ctx, 2) || HasRole(ctx, 3) || len(RoleIDs(ctx)) != 2 {
+		t.Errorf("roles = %v", RoleIDs(ctx))
+	}
+	if id, ok := DeptID(ctx); !ok || id != 3 {
+		t.Errorf("dept = %d", id)
+	}
+	if _, ok := DeptID(parent); ok {
+		t.Error("parent ctx should not be modified")
+	}
+	if !HasPermission(ctx, "system:user:add") || HasPermission(ctx, "system:user:remove") {
+		t.Errorf("permissions = %v", Permissions(ctx))
+	}
+	if _, ok := OperatorInt64(ctx); ok || ClientIP(ctx) != "" {
+		t.Error("unset values should be empty")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		claims := jwtauth.MapClaims{"identity": float64(7), "roleid": float64(1), "deptid": float64(4)}
+		c.Request = c.Request.WithContext(jwtauth.WithClaims(c.Request.Context(), claims))
+	}, Middleware())
+	var ctx context.Context
+	r.GET("/", func(c *gin.Context) {
+		ctx = c.Request.Context()
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("Accept-Language", "zh-CN")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	dept, _ := DeptID(ctx)
+	if OperatorID(ctx) != "7" || !HasRole(ctx, 1) || dept != 4 || ClientIP(ctx) != "10.0.0.1" || Locale(ctx) != "zh-cn" {
+		t.Errorf("operator = %s, roles = %v, dept = %d, ip = %s, locale = %s",
+			OperatorID(ctx), RoleIDs(ctx), dept, ClientIP(ctx), Locale(ctx))
+	}
+}
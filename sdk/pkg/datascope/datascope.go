@@ -0,0 +1,219 @@
+// Package datascope 数据权限与软删除的 gorm scope, 以及按请求上下文写入 create_by、update_by 的插件
+//
+//	db.WithContext(ctx).Scopes(datascope.Context("sys_post")).Find(&list)
+package datascope
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"gorm.io/gorm"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/config"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/ctxkit"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/jwtauth"
+)
+
+// 数据范围, 与角色的 data_scope 对应
+const (
+	// All 全部数据
+	All = "1"
+	// Custom 角色自定义的部门(sys_role_dept)
+	Custom = "2"
+	// Dept 本部门
+	Dept = "3"
+	// DeptAndChildren 本部门及以下
+	DeptAndChildren = "4"
+	// Self 仅本人
+	Self = "5"
+)
+
+// Permission 当前用户的数据权限
+type Permission struct {
+	DataScope string
+	UserId    int
+	DeptId    int
+	RoleId    int
+}
+
+type permissionKey struct{}
+
+// WithPermission 写入ctx, 优先于 jwt claims
+func WithPermission(ctx context.Context, p *Permission) context.Context {
+	return context.WithValue(ctx, permissionKey{}, p)
+}
+
+// FromContext 从ctx获取数据权限, 未调用 WithPermission 时从 jwt claims 读取, 没有 claims 时取 ctxkit 中的
+// 操作人、部门与角色(数据范围为空, 不过滤), 都没有时返回nil
+func FromContext(ctx context.Context) *Permission {
+	if ctx == nil {
+		return nil
+	}
+	if p, ok := ctx.Value(permissionKey{}).(*Permission); ok {
+		return p
+	}
+	claims := jwtauth.ClaimsFromContext(ctx)
+	if len(claims) == 0 {
+		return fromKit(ctx)
+	}
+	p := &Permission{DataScope: claims.String(jwtauth.DataScopeKey)}
+	p.UserId, _ = claims.Int(jwtauth.IdentityKey)
+	p.DeptId, _ = claims.Int("deptid")
+	p.RoleId, _ = claims.Int(jwtauth.RoleIdKey)
+	return p
+}
+
+func fromKit(ctx context.Context) *Permission {
+	id, ok := ctxkit.OperatorInt64(ctx)
+	if !ok {
+		return nil
+	}
+	p := &Permission{UserId: int(id)}
+	p.DeptId, _ = ctxkit.DeptID(ctx)
+	if roles := ctxkit.RoleIDs(ctx); len(roles) > 0 {
+		p.RoleId = roles[0]
+	}
+	return p
+}
+
+// Operator 当前操作人, 依次取 WithPermission、jwt claims 和 logger.WithOperatorID
+func Operator(ctx context.Context) (int64, bool) {
+	if p := FromContext(ctx); p != nil && p.UserId != 0 {
+		return int64(p.UserId), true
+	}
+	if ctx == nil {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(logger.OperatorID(ctx), 10, 64)
+	return id, err == nil && id != 0
+}
+
+type Option func(*options)
+
+type options struct {
+	creator       string
+	updater       string
+	userTable     string
+	deptTable     string
+	roleDeptTable string
+	enabled       func() bool
+	tables        map[string]bool
+}
+
+func setDefault() options {
+	return options{
+		creator:       "create_by",
+		updater:       "update_by",
+		userTable:     "sys_user",
+		deptTable:     "sys_dept",
+		roleDeptTable: "sys_role_dept",
+		enabled: func() bool {
+			return config.ApplicationConfig.EnableDP
+		},
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithColumns 创建人、更新人的列名, 默认 create_by、update_by
+func WithColumns(creator, updater string) Option {
+	return func(o *options) {
+		o.creator = creator
+		o.updater = updater
+	}
+}
+
+// WithTables 用户表、部门表、角色部门表, 默认 sys_user、sys_dept、sys_role_dept
+// 用户表需包含 user_id、dept_id, 部门表需包含 dept_id、dept_path(如 /0/1/3/)
+func WithTables(user, dept, roleDept string) Option {
+	return func(o *options) {
+		o.userTable = user
+		o.deptTable = dept
+		o.roleDeptTable = roleDept
+	}
+}
+
+// WithEnabled 是否启用数据权限, 默认读取 settings.application.enabledp
+func WithEnabled(f func() bool) Option {
+	return func(o *options) {
+		o.enabled = f
+	}
+}
+
+// WithFilter Plugin 自动为这些表的查询、更新、删除加上当前用户的数据权限
+func WithFilter(tables ...string) Option {
+	return func(o *options) {
+		if o.tables == nil {
+			o.tables = make(map[string]bool)
+		}
+		for _, t := range tables {
+			o.tables[t] = true
+		}
+	}
+}
+
+// Scope 按数据权限过滤 table, p为nil或未启用数据权限时不过滤
+func Scope(table string, p *Permission, opts ...Option) func(db *gorm.DB) *gorm.DB {
+	o := newOptions(opts)
+	return func(db *gorm.DB) *gorm.DB {
+		if p == nil || !o.enabled() {
+			return db
+		}
+		return o.where(db, table, p)
+	}
+}
+
+// Context 按 db.Statement.Context 中的数据权限过滤 table, 需先调用 db.WithContext
+func Context(table string, opts ...Option) func(db *gorm.DB) *gorm.DB {
+	o := newOptions(opts)
+	return func(db *gorm.DB) *gorm.DB {
+		p := FromContext(db.Statement.Context)
+		if p == nil || !o.enabled() {
+			return db
+		}
+		return o.where(db, table, p)
+	}
+}
+
+func (o *options) where(db *gorm.DB, table string, p *Permission) *gorm.DB {
+	creator := o.creator
+	if table != "" {
+		creator = table + "." + o.creator
+	}
+	switch p.DataScope {
+	case Custom:
+		return db.Where(fmt.Sprintf("%s IN (SELECT u.user_id FROM %s r LEFT JOIN %s u ON u.dept_id = r.dept_id WHERE r.role_id = ?)",
+			creator, o.roleDeptTable, o.userTable), p.RoleId)
+	case Dept:
+		return db.Where(fmt.Sprintf("%s IN (SELECT user_id FROM %s WHERE dept_id = ?)", creator, o.userTable), p.DeptId)
+	case DeptAndChildren:
+		return db.Where(fmt.Sprintf("%s IN (SELECT user_id FROM %s WHERE dept_id IN (SELECT dept_id FROM %s WHERE dept_path LIKE ?))",
+			creator, o.userTable, o.deptTable), "%/"+strconv.Itoa(p.DeptId)+"/%")
+	case Self:
+		return db.Where(creator+" = ?", p.UserId)
+	}
+	return db
+}
+
+// WithDeleted 包含已软删除的数据
+func WithDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// OnlyDeleted 只查询已软删除的数据, column 为空时使用 deleted_at
+func OnlyDeleted(column string) func(db *gorm.DB) *gorm.DB {
+	if column == "" {
+		column = "deleted_at"
+	}
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Where(column + " IS NOT NULL")
+	}
+}
@@ -0,0 +1,153 @@
+package datascope
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/jwtauth"
+)
+
+type SysUser struct {
+	UserId int `gorm:"primaryKey"`
+	DeptId int
+}
+
+type SysDept struct {
+	DeptId   int `gorm:"primaryKey"`
+	DeptPath string
+}
+
+type SysRoleDept struct {
+	RoleId int `gorm:"primaryKey"`
+	DeptId int `gorm:"primaryKey"`
+}
+
+type SysPost struct {
+	Id        int `gorm:"primaryKey"`
+	Name      string
+	CreateBy  int
+	UpdateBy  int
+	DeletedAt gorm.DeletedAt
+}
+
+func (SysUser) TableName() string     { return "sys_user" }
+func (SysDept) TableName() string     { return "sys_dept" }
+func (SysRoleDept) TableName() string { return "sys_role_dept" }
+
+func enabled() bool { return true }
+
+func newDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err = db.AutoMigrate(&SysUser{}, &SysDept{}, &SysRoleDept{}, &SysPost{}); err != nil {
+		t.Fatal(err)
+	}
+	// 部门 1 > 2 > 3, 用户 10、20、30 分别属于部门 1、2、3
+	db.Create(&[]SysDept{{1, "/0/1/"}, {2, "/0/1/2/"}, {3, "/0/1/2/3/"}})
+	db.Create(&[]SysUser{{10, 1}, {20, 2}, {30, 3}})
+	db.Create(&[]SysRoleDept{{RoleId: 7, DeptId: 3}})
+	db.Create(&[]SysPost{{Id: 1, CreateBy: 10}, {Id: 2, CreateBy: 20}, {Id: 3, CreateBy: 30}})
+	return db
+}
+
+func TestScope(t *testing.T) {
+	db := newDB(t)
+	tests := []struct {
+		p    *Permission
+		want int
+	}{
+		{nil, 3},
+		{&Permission{DataScope: All}, 3},
+		{&Permission{DataScope: Custom, RoleId: 7}, 1},
+		{&Permission{DataScope: Dept, DeptId: 2}, 1},
+		{&Permission{DataScope: DeptAndChildren, DeptId: 2}, 2},
+		{&Permission{DataScope: Self, UserId: 10}, 1},
+	}
+	for _, tt := range tests {
+		var list []SysPost
+		if err := db.Scopes(Scope("sys_posts", tt.p, WithEnabled(enabled))).Find(&list).Error; err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != tt.want {
+			t.Errorf("%+v: got %d, want %d", tt.p, len(list), tt.want)
+		}
+	}
+
+	var count int64
+	db.Model(&SysPost{}).Scopes(Scope("sys_posts", &Permission{DataScope: Self, UserId: 10})).Count(&count)
+	if count != 3 {
+		t.Errorf("disabled data permission should not filter, got %d", count)
+	}
+}
+
+func TestContext(t *testing.T) {
+	db := newDB(t)
+	ctx := jwtauth.WithClaims(context.Background(), jwtauth.MapClaims{
+		"identity": float64(20), "deptid": float64(2), "datascope": "4",
+	})
+	if p := FromContext(ctx); p == nil || p.UserId != 20 || p.DeptId != 2 || p.DataScope != DeptAndChildren {
+		t.Fatalf("permission = %+v", p)
+	}
+	var list []SysPost
+	db.WithContext(ctx).Scopes(Context("sys_posts", WithEnabled(enabled))).Find(&list)
+	if len(list) != 2 {
+		t.Errorf("got %d", len(list))
+	}
+	ctx = WithPermission(ctx, &Permission{DataScope: Self, UserId: 30})
+	db.WithContext(ctx).Scopes(Context("sys_posts", WithEnabled(enabled))).Find(&list)
+	if len(list) != 1 || list[0].Id != 3 {
+		t.Errorf("list = %+v", list)
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	db := newDB(t)
+	if err := db.Use(Plugin(WithEnabled(enabled), WithFilter("sys_posts"))); err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithPermission(context.Background(), &Permission{DataScope: Self, UserId: 10})
+	posts := []SysPost{{Id: 4}, {Id: 5}}
+	if err := db.WithContext(ctx).Create(&posts).Error; err != nil {
+		t.Fatal(err)
+	}
+	var list []SysPost
+	db.WithContext(ctx).Order("id").Find(&list)
+	if len(list) != 3 || list[1].CreateBy != 10 || list[2].CreateBy != 10 {
+		t.Errorf("list = %+v", list)
+	}
+
+	// 更新其他人的数据不生效
+	other := WithPermission(context.Background(), &Permission{DataScope: Self, UserId: 20})
+	db.WithContext(other).Model(&SysPost{}).Where("id IN ?", []int{1, 2}).Update("name", "x")
+	var post SysPost
+	db.Unscoped().Session(&gorm.Session{NewDB: true}).Table("sys_posts").Where("id = 2").Take(&post)
+	if post.Name != "x" || post.UpdateBy != 20 {
+		t.Errorf("post = %+v", post)
+	}
+	var own SysPost
+	db.Table("sys_posts").Where("id = 1").Take(&own)
+	if own.Name != "" {
+		t.Errorf("post = %+v", own)
+	}
+
+	// 软删除
+	db.WithContext(ctx).Delete(&SysPost{}, 4)
+	var deleted []SysPost
+	db.Scopes(OnlyDeleted("")).Find(&deleted)
+	if len(deleted) != 1 || deleted[0].Id != 4 {
+		t.Errorf("deleted = %+v", deleted)
+	}
+	var all int64
+	db.Model(&SysPost{}).Scopes(WithDeleted).Count(&all)
+	if all != 5 {
+		t.Errorf("all = %d", all)
+	}
+}
@@ -0,0 +1,80 @@
+package datascope
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// Plugin 新增时按当前操作人写入 create_by, 更新时写入 update_by, 模型没有对应列时忽略;
+// 设置 WithFilter 时自动为这些表加上数据权限
+//
+//	db.Use(datascope.Plugin(datascope.WithFilter("sys_post")))
+func Plugin(opts ...Option) gorm.Plugin {
+	return &plugin{opts: newOptions(opts)}
+}
+
+type plugin struct {
+	opts options
+}
+
+func (*plugin) Name() string {
+	return "datascope"
+}
+
+func (p *plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().Before("gorm:create").Register("datascope:create", p.stamp(p.opts.creator)); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("datascope:update", p.stamp(p.opts.updater)); err != nil {
+		return err
+	}
+	if len(p.opts.tables) == 0 {
+		return nil
+	}
+	if err := cb.Query().Before("gorm:query").Register("datascope:query", p.filter); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("datascope:update_filter", p.filter); err != nil {
+		return err
+	}
+	return cb.Delete().Before("gorm:delete").Register("datascope:delete", p.filter)
+}
+
+// stamp 写入操作人, 批量新增时写入每一条
+func (p *plugin) stamp(column string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.Statement.Schema == nil {
+			return
+		}
+		field := db.Statement.Schema.LookUpField(column)
+		if field == nil {
+			return
+		}
+		id, ok := Operator(db.Statement.Context)
+		if !ok {
+			return
+		}
+		rv := db.Statement.ReflectValue
+		if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			cur := db.Statement.CurDestIndex
+			for i := 0; i < rv.Len(); i++ {
+				db.Statement.CurDestIndex = i
+				db.Statement.SetColumn(field.Name, id, true)
+			}
+			db.Statement.CurDestIndex = cur
+			return
+		}
+		db.Statement.SetColumn(field.Name, id, true)
+	}
+}
+
+func (p *plugin) filter(db *gorm.DB) {
+	if db.Error != nil || !p.opts.tables[db.Statement.Table] || !p.opts.enabled() {
+		return
+	}
+	if perm := FromContext(db.Statement.Context); perm != nil {
+		p.opts.where(db, db.Statement.Table, perm)
+	}
+}
@@ -0,0 +1,19 @@
+package dict
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+)
+
+// Handler 字典选项接口, 路径参数 type 为字典类型, 供前端下拉框使用
+func (m *Manager) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		list, err := m.Options(c.Request.Context(), c.Param("type"))
+		if err != nil {
+			response.Fail(c, err)
+			return
+		}
+		response.OK(c, list, "")
+	}
+}
@@ -0,0 +1,175 @@
+// Package dict 数据字典, 按类型缓存字典项, 提供取显示文本与下拉选项的方法;
+// 修改字典后调用 Invalidate 清除对应类型或全部缓存
+//
+//	m := dict.New(dict.NewGormStore(db, ""), sdk.Runtime.GetCacheAdapter())
+//	sdk.Runtime.AddComponent(runtime.OrderStorage+1, m) // 启动时预加载
+//	m.Label(ctx, "sys_user_sex", "0") // 男
+//	m.Options(ctx, "sys_normal_disable")
+package dict
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+type Option func(*options)
+
+type options struct {
+	prefix string
+	ttl    int
+}
+
+func setDefault() options {
+	return options{
+		prefix: "dict:",
+		ttl:    3600,
+	}
+}
+
+// WithPrefix 缓存key前缀, 默认 dict:
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithTTL 字典项的缓存时间, 单位秒, 默认1小时
+func WithTTL(ttl int) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// versionTTL 命名空间版本的保存时间, 需大于字典项的缓存时间
+const versionTTL = 30 * 24 * 3600
+
+// Manager 字典查询
+type Manager struct {
+	store Store
+	cache storage.AdapterCache
+	o     options
+}
+
+func New(store Store, cache storage.AdapterCache, opts ...Option) *Manager {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Manager{store: store, cache: cache, o: o}
+}
+
+// Options 类型的全部字典项, 按排序号排列
+func (m *Manager) Options(ctx context.Context, typ string) ([]Item, error) {
+	key := m.key(typ)
+	if v, err := m.cache.Get(key); err == nil && v != "" {
+		list := make([]Item, 0)
+		if err = json.Unmarshal([]byte(v), &list); err == nil {
+			return list, nil
+		}
+	}
+	list, err := m.store.Load(ctx, typ)
+	if err != nil {
+		return nil, err
+	}
+	// 不存在的类型同样缓存, 避免每次查询数据库
+	m.set(ctx, key, list)
+	return list, nil
+}
+
+// Labels 类型的值到显示文本, 批量翻译列表时使用
+func (m *Manager) Labels(ctx context.Context, typ string) map[string]string {
+	list, err := m.Options(ctx, typ)
+	if err != nil {
+		logger.Module("sdk.dict").WithContext(ctx).Warn("load dict failed", "type", typ, "error", err)
+	}
+	labels := make(map[string]string, len(list))
+	for _, item := range list {
+		labels[item.Value] = item.Label
+	}
+	return labels
+}
+
+// Label 值的显示文本, 未找到时返回值本身
+func (m *Manager) Label(ctx context.Context, typ, value string) string {
+	if label, ok := m.Labels(ctx, typ)[value]; ok {
+		return label
+	}
+	return value
+}
+
+// Value 显示文本对应的值, 用于导入
+func (m *Manager) Value(ctx context.Context, typ, label string) (string, bool) {
+	for value, l := range m.Labels(ctx, typ) {
+		if l == label {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// Invalidate 清除类型的缓存, 未指定类型时清除全部字典的缓存
+func (m *Manager) Invalidate(ctx context.Context, types ...string) error {
+	if len(types) == 0 {
+		// 更换命名空间版本, 旧的缓存不再被读取, 到期后自动清除
+		if err := m.cache.Set(m.o.prefix+"version", strconv.FormatInt(time.Now().UnixNano(), 36), versionTTL); err != nil {
+			return err
+		}
+	}
+	for _, typ := range types {
+		if err := m.cache.Del(m.key(typ)); err != nil {
+			return err
+		}
+	}
+	logger.Module("sdk.dict").WithContext(ctx).Info("dict invalidated", "types", types)
+	return nil
+}
+
+// Preload 加载全部字典写入缓存
+func (m *Manager) Preload(ctx context.Context) error {
+	list, err := m.store.Load(ctx, "")
+	if err != nil {
+		return err
+	}
+	groups := make(map[string][]Item)
+	for _, item := range list {
+		groups[item.Type] = append(groups[item.Type], item)
+	}
+	for typ, items := range groups {
+		m.set(ctx, m.key(typ), items)
+	}
+	logger.Module("sdk.dict").WithContext(ctx).Info("dict preloaded", "types", len(groups), "items", len(list))
+	return nil
+}
+
+func (m *Manager) String() string {
+	return "dict"
+}
+
+// Start 作为 runtime.Component 启动时预加载
+func (m *Manager) Start(ctx context.Context) error {
+	return m.Preload(ctx)
+}
+
+func (m *Manager) Stop(context.Context) error {
+	return nil
+}
+
+func (m *Manager) key(typ string) string {
+	version, _ := m.cache.Get(m.o.prefix + "version")
+	return m.o.prefix + version + ":" + typ
+}
+
+func (m *Manager) set(ctx context.Context, key string, list []Item) {
+	b, err := json.Marshal(list)
+	if err == nil {
+		err = m.cache.Set(key, string(b), m.o.ttl)
+	}
+	if err != nil {
+		logger.Module("sdk.dict").WithContext(ctx).Warn("cache dict failed", "key", key, "error", err)
+	}
+}
@@ -0,0 +1,75 @@
+package dict
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+type countStore struct {
+	Store
+	loads int
+}
+
+func (s *countStore) Load(ctx context.Context, typ string) ([]Item, error) {
+	s.loads++
+	return s.Store.Load(ctx, typ)
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+	items := MapStore{
+		"sys_user_sex": {{Value: "1", Label: "女", Sort: 2}, {Value: "0", Label: "男", Sort: 1}},
+	}
+	store := &countStore{Store: items}
+	m := New(store, cache.NewMemory())
+	if err := m.Preload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	list, _ := m.Options(ctx, "sys_user_sex")
+	if len(list) != 2 || list[0].Label != "男" || store.loads != 1 {
+		t.Fatalf("options = %+v, loads = %d", list, store.loads)
+	}
+	if m.Label(ctx, "sys_user_sex", "1") != "女" || m.Label(ctx, "sys_user_sex", "9") != "9" {
+		t.Error("label")
+	}
+	if v, ok := m.Value(ctx, "sys_user_sex", "男"); !ok || v != "0" {
+		t.Errorf("value = %s", v)
+	}
+
+	items["sys_user_sex"] = append(items["sys_user_sex"], Item{Value: "2", Label: "未知", Sort: 3})
+	if len(m.Labels(ctx, "sys_user_sex")) != 2 {
+		t.Error("should read from cache before invalidate")
+	}
+	_ = m.Invalidate(ctx, "sys_user_sex")
+	if len(m.Labels(ctx, "sys_user_sex")) != 3 {
+		t.Error("type invalidate")
+	}
+	items["sys_user_sex"] = items["sys_user_sex"][:1]
+	_ = m.Invalidate(ctx)
+	if len(m.Labels(ctx, "sys_user_sex")) != 1 {
+		t.Error("namespace invalidate")
+	}
+}
+
+func TestGormStore(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.Exec("CREATE TABLE sys_dict_data (dict_code integer PRIMARY KEY, dict_sort integer, dict_label varchar(128), " +
+		"dict_value varchar(255), dict_type varchar(64), css_class varchar(128), list_class varchar(128), status integer)").Error; err != nil {
+		t.Fatal(err)
+	}
+	db.Exec("INSERT INTO sys_dict_data VALUES (1, 1, '正常', '2', 'sys_normal_disable', '', 'primary', 2), " +
+		"(2, 2, '停用', '1', 'sys_normal_disable', '', 'danger', 2), (3, 1, '男', '0', 'sys_user_sex', '', '', 1)")
+	list, err := NewGormStore(db, "").Load(context.Background(), "")
+	if err != nil || len(list) != 2 || list[0].Label != "正常" || list[1].ListClass != "danger" {
+		t.Errorf("list = %+v, err = %v", list, err)
+	}
+}
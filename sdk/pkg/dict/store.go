@@ -0,0 +1,76 @@
+package dict
+
+import (
+	"context"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// Item 字典项
+type Item struct {
+	Type      string `json:"type" gorm:"column:type"`
+	Value     string `json:"value" gorm:"column:value"`
+	Label     string `json:"label" gorm:"column:label"`
+	Sort      int    `json:"sort" gorm:"column:sort"`
+	CSSClass  string `json:"cssClass,omitempty" gorm:"column:css_class"`
+	ListClass string `json:"listClass,omitempty" gorm:"column:list_class"`
+}
+
+// Store 字典数据的来源
+type Store interface {
+	// Load 加载字典项, typ 为空时加载全部
+	Load(ctx context.Context, typ string) ([]Item, error)
+}
+
+// DefaultTable go-admin 的字典数据表
+const DefaultTable = "sys_dict_data"
+
+// GormStore 从数据库的字典数据表加载, 只加载 status 为2(正常)的数据
+type GormStore struct {
+	db    *gorm.DB
+	table string
+}
+
+// NewGormStore table 为空时使用 DefaultTable
+func NewGormStore(db *gorm.DB, table string) *GormStore {
+	if table == "" {
+		table = DefaultTable
+	}
+	return &GormStore{db: db, table: table}
+}
+
+func (s *GormStore) Load(ctx context.Context, typ string) ([]Item, error) {
+	list := make([]Item, 0)
+	q := s.db.WithContext(ctx).Table(s.table).
+		Select("dict_type AS type, dict_value AS value, dict_label AS label, dict_sort AS sort, css_class, list_class").
+		Where("status = ?", 2)
+	if typ != "" {
+		q = q.Where("dict_type = ?", typ)
+	}
+	err := q.Order("dict_type").Order("dict_sort").Find(&list).Error
+	return list, err
+}
+
+// MapStore 固定的字典, 用于测试或不需要维护的枚举
+type MapStore map[string][]Item
+
+func (s MapStore) Load(_ context.Context, typ string) ([]Item, error) {
+	list := make([]Item, 0)
+	for t, items := range s {
+		if typ != "" && t != typ {
+			continue
+		}
+		for _, item := range items {
+			item.Type = t
+			list = append(list, item)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Type != list[j].Type {
+			return list[i].Type < list[j].Type
+		}
+		return list[i].Sort < list[j].Sort
+	})
+	return list, nil
+}
@@ -0,0 +1,91 @@
+package discovery
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// Config 注册中心配置, 可通过 config.RegisterExtend("discovery", &discovery.Config{}) 从配置文件加载
+type Config struct {
+	// Driver consul、etcd、nacos
+	Driver string `json:"driver" validate:"oneof=consul etcd nacos"`
+	// Name 本服务的名称
+	Name string `json:"name"`
+	// Host 注册的地址, 为空时使用本机第一个非回环的 ipv4 地址
+	Host string `json:"host"`
+	// HttpPort、GrpcPort 为0时不注册对应协议
+	HttpPort int `json:"httpPort"`
+	GrpcPort int `json:"grpcPort"`
+	// TTL 健康检查有效期(秒), 默认15
+	TTL int `json:"ttl"`
+	// Refresh 实例列表的缓存时间(秒), 默认10
+	Refresh  int               `json:"refresh"`
+	Metadata map[string]string `json:"metadata"`
+	Consul   ConsulConfig      `json:"consul"`
+	Etcd     EtcdConfig        `json:"etcd"`
+	Nacos    NacosConfig       `json:"nacos"`
+}
+
+// Backend 按 Driver 创建注册中心
+func (c *Config) Backend() (Backend, error) {
+	switch c.Driver {
+	case "consul":
+		return NewConsul(c.Consul), nil
+	case "etcd":
+		return NewEtcd(c.Etcd), nil
+	case "nacos":
+		return NewNacos(c.Nacos), nil
+	}
+	return nil, fmt.Errorf("discovery: unknown driver %q", c.Driver)
+}
+
+// Registrar 按配置注册本服务的 http 与 gRPC 实例, 需加入 runtime 的生命周期
+//
+//	sdk.Runtime.AddComponent(runtime.OrderServer+1, registrar)
+func (c *Config) Registrar(b Backend) (*Registrar, error) {
+	host := c.Host
+	if host == "" {
+		var err error
+		if host, err = localIP(); err != nil {
+			return nil, err
+		}
+	}
+	instances := make([]*Instance, 0, 2)
+	for _, p := range []struct {
+		scheme string
+		port   int
+	}{{SchemeHTTP, c.HttpPort}, {SchemeGRPC, c.GrpcPort}} {
+		if p.port <= 0 {
+			continue
+		}
+		ins := NewInstance(c.Name, p.scheme, host, p.port)
+		ins.Metadata = c.Metadata
+		instances = append(instances, ins)
+	}
+	return NewRegistrar(b, time.Duration(c.TTL)*time.Second, instances...), nil
+}
+
+// Resolver 按配置创建 Resolver
+func (c *Config) Resolver(b Backend, opts ...ResolverOption) *Resolver {
+	if c.Refresh > 0 {
+		opts = append([]ResolverOption{WithRefresh(time.Duration(c.Refresh) * time.Second)}, opts...)
+	}
+	return NewResolver(b, opts...)
+}
+
+// localIP 本机第一个非回环的 ipv4 地址
+func localIP() (string, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", err
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() {
+			if ip := ipNet.IP.To4(); ip != nil {
+				return ip.String(), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("discovery: no available ip, set host in config")
+}
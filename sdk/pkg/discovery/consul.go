@@ -0,0 +1,147 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ConsulConfig consul agent 的 http 地址, 如 http://127.0.0.1:8500
+type ConsulConfig struct {
+	Address    string `json:"address"`
+	Token      string `json:"token"`
+	Datacenter string `json:"datacenter"`
+	// DeregisterAfter 健康检查失败多少秒后自动注销, 默认60
+	DeregisterAfter int `json:"deregisterAfter"`
+}
+
+// Consul 使用 agent 的 TTL 检查维持健康状态
+type Consul struct {
+	cfg    ConsulConfig
+	client *http.Client
+}
+
+func NewConsul(cfg ConsulConfig) *Consul {
+	if cfg.Address == "" {
+		cfg.Address = "http://127.0.0.1:8500"
+	}
+	if cfg.DeregisterAfter <= 0 {
+		cfg.DeregisterAfter = 60
+	}
+	cfg.Address = strings.TrimRight(cfg.Address, "/")
+	return &Consul{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (*Consul) String() string {
+	return "consul"
+}
+
+func (c *Consul) do(ctx context.Context, method, path string, query url.Values, body, v interface{}) error {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return err
+		}
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	if c.cfg.Datacenter != "" {
+		query.Set("dc", c.cfg.Datacenter)
+	}
+	u := c.cfg.Address + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.cfg.Token != "" {
+		req.Header.Set("X-Consul-Token", c.cfg.Token)
+	}
+	return doJSON(c.client, req, v)
+}
+
+func checkID(ins *Instance) string {
+	return "service:" + ins.ID
+}
+
+func (c *Consul) Register(ctx context.Context, ins *Instance, ttl time.Duration) error {
+	meta := map[string]string{"scheme": ins.Scheme}
+	for k, v := range ins.Metadata {
+		meta[k] = v
+	}
+	err := c.do(ctx, http.MethodPut, "/v1/agent/service/register", nil, map[string]interface{}{
+		"ID":      ins.ID,
+		"Name":    ins.Name,
+		"Tags":    []string{ins.Scheme},
+		"Address": ins.Host,
+		"Port":    ins.Port,
+		"Meta":    meta,
+		"Check": map[string]interface{}{
+			"CheckID":                        checkID(ins),
+			"TTL":                            ttl.String(),
+			"DeregisterCriticalServiceAfter": (time.Duration(c.cfg.DeregisterAfter) * time.Second).String(),
+		},
+	}, nil)
+	if err != nil {
+		return err
+	}
+	// 注册后检查状态为 critical, 立即上报一次
+	return c.Heartbeat(ctx, ins)
+}
+
+func (c *Consul) Heartbeat(ctx context.Context, ins *Instance) error {
+	err := c.do(ctx, http.MethodPut, "/v1/agent/check/pass/"+url.PathEscape(checkID(ins)), nil, nil, nil)
+	// 旧版本 agent 对未知检查返回500
+	if err != nil && strings.Contains(err.Error(), "Unknown check") {
+		return ErrNotRegistered
+	}
+	return err
+}
+
+func (c *Consul) Deregister(ctx context.Context, ins *Instance) error {
+	return c.do(ctx, http.MethodPut, "/v1/agent/service/deregister/"+url.PathEscape(ins.ID), nil, nil, nil)
+}
+
+func (c *Consul) Instances(ctx context.Context, name string) ([]*Instance, error) {
+	var entries []struct {
+		Service struct {
+			ID      string
+			Service string
+			Address string
+			Port    int
+			Meta    map[string]string
+		}
+		Node struct {
+			Address string
+		}
+	}
+	err := c.do(ctx, http.MethodGet, "/v1/health/service/"+url.PathEscape(name),
+		url.Values{"passing": {"true"}}, nil, &entries)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Instance, 0, len(entries))
+	for _, e := range entries {
+		ins := &Instance{
+			ID:       e.Service.ID,
+			Name:     e.Service.Service,
+			Scheme:   e.Service.Meta["scheme"],
+			Host:     e.Service.Address,
+			Port:     e.Service.Port,
+			Metadata: e.Service.Meta,
+		}
+		if ins.Host == "" {
+			ins.Host = e.Node.Address
+		}
+		list = append(list, ins)
+	}
+	return list, nil
+}
@@ -0,0 +1,174 @@
+// Package discovery 服务注册与发现, 支持 consul、etcd、nacos,
+// 拆分微服务后通过服务名调用其他服务的 http 与 gRPC 接口
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+var (
+	ErrNoInstance = errors.New("discovery: no available instance")
+	// ErrNotRegistered 心跳时实例已不存在, 如租约过期, Registrar 收到后重新注册
+	ErrNotRegistered = errors.New("discovery: instance not registered")
+)
+
+// 实例的协议
+const (
+	SchemeHTTP = "http"
+	SchemeGRPC = "grpc"
+)
+
+// Instance 服务实例, 同一服务的 http 与 gRPC 端口分别注册为两个实例
+type Instance struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Scheme   string            `json:"scheme"`
+	Host     string            `json:"host"`
+	Port     int               `json:"port"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Addr host:port
+func (i *Instance) Addr() string {
+	return net.JoinHostPort(i.Host, strconv.Itoa(i.Port))
+}
+
+// NewInstance 创建实例, id 由服务名、协议与随机串组成
+func NewInstance(name, scheme, host string, port int) *Instance {
+	return &Instance{
+		ID:     name + "-" + scheme + "-" + uuid.New().String()[:8],
+		Name:   name,
+		Scheme: scheme,
+		Host:   host,
+		Port:   port,
+	}
+}
+
+// Backend 注册中心
+type Backend interface {
+	String() string
+	// Register 注册实例, ttl 内未收到心跳的实例视为不健康
+	Register(ctx context.Context, ins *Instance, ttl time.Duration) error
+	// Heartbeat 续期, 实例已不存在时返回 ErrNotRegistered
+	Heartbeat(ctx context.Context, ins *Instance) error
+	Deregister(ctx context.Context, ins *Instance) error
+	// Instances 服务的健康实例
+	Instances(ctx context.Context, name string) ([]*Instance, error)
+}
+
+// Registrar 注册实例并定时发送心跳, 实现 runtime.Component, 停止时注销
+type Registrar struct {
+	backend   Backend
+	instances []*Instance
+	ttl       time.Duration
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// NewRegistrar ttl 为健康检查的有效期, 心跳间隔为 ttl/3
+func NewRegistrar(b Backend, ttl time.Duration, instances ...*Instance) *Registrar {
+	if ttl <= 0 {
+		ttl = 15 * time.Second
+	}
+	return &Registrar{backend: b, instances: instances, ttl: ttl}
+}
+
+func (r *Registrar) String() string {
+	return "discovery-" + r.backend.String()
+}
+
+// Start 注册全部实例, 任一失败时注销已注册的实例
+func (r *Registrar) Start(ctx context.Context) error {
+	for i, ins := range r.instances {
+		if err := r.backend.Register(ctx, ins, r.ttl); err != nil {
+			for _, registered := range r.instances[:i] {
+				_ = r.backend.Deregister(ctx, registered)
+			}
+			return fmt.Errorf("discovery: register %s failed: %w", ins.ID, err)
+		}
+	}
+	var loopCtx context.Context
+	loopCtx, r.cancel = context.WithCancel(context.Background())
+	r.wg.Add(1)
+	go r.heartbeat(loopCtx)
+	return nil
+}
+
+func (r *Registrar) heartbeat(ctx context.Context) {
+	defer r.wg.Done()
+	log := logger.Module("sdk.discovery").With("backend", r.backend.String())
+	ticker := time.NewTicker(r.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, ins := range r.instances {
+			err := r.backend.Heartbeat(ctx, ins)
+			if errors.Is(err, ErrNotRegistered) {
+				log.Warn("instance lost, register again", "instance", ins.ID)
+				err = r.backend.Register(ctx, ins, r.ttl)
+			}
+			if err != nil && ctx.Err() == nil {
+				log.Error("heartbeat failed", "instance", ins.ID, "error", err)
+			}
+		}
+	}
+}
+
+// Stop 停止心跳并注销实例
+func (r *Registrar) Stop(ctx context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+		r.wg.Wait()
+	}
+	var errs []string
+	for _, ins := range r.instances {
+		if err := r.backend.Deregister(ctx, ins); err != nil {
+			errs = append(errs, ins.ID+": "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New("discovery: deregister failed: " + strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// doJSON 发送请求并解析json响应, v 为nil时丢弃响应
+func doJSON(client *http.Client, req *http.Request, v interface{}) error {
+	rsp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(rsp.Body, 4<<20))
+	if err != nil {
+		return err
+	}
+	if rsp.StatusCode == http.StatusNotFound {
+		return ErrNotRegistered
+	}
+	if rsp.StatusCode >= 300 {
+		return fmt.Errorf("discovery: %s: %s", rsp.Status, strings.TrimSpace(string(b)))
+	}
+	if v == nil || len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,245 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type memoryBackend struct {
+	mux        sync.Mutex
+	instances  map[string]*Instance
+	registered int
+}
+
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{instances: make(map[string]*Instance)}
+}
+
+func (*memoryBackend) String() string { return "memory" }
+
+func (b *memoryBackend) Register(_ context.Context, ins *Instance, _ time.Duration) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.instances[ins.ID] = ins
+	b.registered++
+	return nil
+}
+
+func (b *memoryBackend) Heartbeat(_ context.Context, ins *Instance) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if _, ok := b.instances[ins.ID]; !ok {
+		return ErrNotRegistered
+	}
+	return nil
+}
+
+func (b *memoryBackend) Deregister(_ context.Context, ins *Instance) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	delete(b.instances, ins.ID)
+	return nil
+}
+
+func (b *memoryBackend) Instances(_ context.Context, name string) ([]*Instance, error) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	list := make([]*Instance, 0)
+	for _, ins := range b.instances {
+		if ins.Name == name {
+			list = append(list, ins)
+		}
+	}
+	return list, nil
+}
+
+func (b *memoryBackend) count() (int, int) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return len(b.instances), b.registered
+}
+
+func TestRegistrar(t *testing.T) {
+	b := newMemoryBackend()
+	ins := NewInstance("sys-user", SchemeHTTP, "127.0.0.1", 8000)
+	r := NewRegistrar(b, 30*time.Millisecond, ins)
+	ctx := context.Background()
+	if err := r.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// 模拟注册中心丢失实例, 心跳时重新注册
+	_ = b.Deregister(ctx, ins)
+	time.Sleep(50 * time.Millisecond)
+	if n, registered := b.count(); n != 1 || registered != 2 {
+		t.Errorf("instances = %d, registered = %d", n, registered)
+	}
+	if err := r.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := b.count(); n != 0 {
+		t.Errorf("instances after stop = %d", n)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	hits := make(map[string]int)
+	var mux sync.Mutex
+	b := newMemoryBackend()
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			mux.Lock()
+			hits[req.Host]++
+			mux.Unlock()
+		}))
+		defer srv.Close()
+		host, port := splitAddr(t, srv.URL)
+		_ = b.Register(ctx, NewInstance("sys-user", SchemeHTTP, host, port), 0)
+	}
+	_ = b.Register(ctx, NewInstance("sys-user", SchemeGRPC, "127.0.0.1", 9000), 0)
+
+	r := NewResolver(b)
+	if list, _ := r.Instances(ctx, "sys-user", SchemeGRPC); len(list) != 1 {
+		t.Errorf("grpc instances = %d", len(list))
+	}
+	client := &http.Client{Transport: r.Transport(nil)}
+	for i := 0; i < 4; i++ {
+		rsp, err := client.Get("http://sys-user/api/v1/user")
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsp.Body.Close()
+	}
+	if len(hits) != 2 {
+		t.Errorf("hits = %v", hits)
+	}
+	for host, n := range hits {
+		if n != 2 {
+			t.Errorf("%s hit %d times", host, n)
+		}
+	}
+	if _, err := r.Pick(ctx, "unknown", ""); err != ErrNoInstance {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func splitAddr(t *testing.T, u string) (string, int) {
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(u, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := strconv.Atoi(port)
+	return host, p
+}
+
+func TestGrpcResolver(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	b := newMemoryBackend()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	host, port := splitAddr(t, lis.Addr().String())
+	_ = b.Register(ctx, NewInstance("sys-user", SchemeGRPC, host, port), 0)
+
+	r := NewResolver(b, WithRefresh(20*time.Millisecond))
+	conn, err := grpc.DialContext(ctx, Scheme+":///sys-user",
+		append(r.DialOptions(), grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConsul(t *testing.T) {
+	var (
+		mux      sync.Mutex
+		services = make(map[string]map[string]interface{})
+		passed   = make(map[string]bool)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mux.Lock()
+		defer mux.Unlock()
+		if req.Header.Get("X-Consul-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case req.URL.Path == "/v1/agent/service/register":
+			var s map[string]interface{}
+			_ = json.NewDecoder(req.Body).Decode(&s)
+			services[s["ID"].(string)] = s
+		case strings.HasPrefix(req.URL.Path, "/v1/agent/check/pass/service:"):
+			id := strings.TrimPrefix(req.URL.Path, "/v1/agent/check/pass/service:")
+			if _, ok := services[id]; !ok {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("Unknown check ID"))
+				return
+			}
+			passed[id] = true
+		case strings.HasPrefix(req.URL.Path, "/v1/agent/service/deregister/"):
+			delete(services, strings.TrimPrefix(req.URL.Path, "/v1/agent/service/deregister/"))
+		case strings.HasPrefix(req.URL.Path, "/v1/health/service/"):
+			list := make([]interface{}, 0)
+			for id, s := range services {
+				if passed[id] && req.URL.Query().Get("passing") == "true" {
+					list = append(list, map[string]interface{}{"Service": map[string]interface{}{
+						"ID": id, "Service": s["Name"], "Address": s["Address"], "Port": s["Port"], "Meta": s["Meta"],
+					}})
+				}
+			}
+			_ = json.NewEncoder(w).Encode(list)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewConsul(ConsulConfig{Address: srv.URL, Token: "token"})
+	ctx := context.Background()
+	ins := NewInstance("sys-user", SchemeGRPC, "10.0.0.1", 9000)
+	if err := c.Register(ctx, ins, 15*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	list, err := c.Instances(ctx, "sys-user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Addr() != "10.0.0.1:9000" || list[0].Scheme != SchemeGRPC {
+		t.Errorf("instances = %+v", list)
+	}
+	if err = c.Deregister(ctx, ins); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Heartbeat(ctx, ins); err != ErrNotRegistered {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestPrefixEnd(t *testing.T) {
+	if got := prefixEnd("/services/a/"); got != "/services/a0" {
+		t.Errorf("prefixEnd = %q", got)
+	}
+}
@@ -0,0 +1,212 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EtcdConfig 通过 etcd v3 的 json 网关访问, 如 http://127.0.0.1:2379
+type EtcdConfig struct {
+	Endpoint string `json:"endpoint"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Prefix key 前缀, 实例保存在 <prefix>/<name>/<id>
+	Prefix string `json:"prefix"`
+}
+
+// Etcd 实例绑定租约, 心跳即续租, 租约过期后实例自动删除
+type Etcd struct {
+	cfg    EtcdConfig
+	client *http.Client
+	mux    sync.Mutex
+	token  string
+	leases map[string]string
+}
+
+func NewEtcd(cfg EtcdConfig) *Etcd {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "http://127.0.0.1:2379"
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = "/go-admin/services"
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	cfg.Prefix = strings.TrimRight(cfg.Prefix, "/")
+	return &Etcd{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}, leases: make(map[string]string)}
+}
+
+func (*Etcd) String() string {
+	return "etcd"
+}
+
+func (e *Etcd) do(ctx context.Context, path string, body, v interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.Endpoint+path, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.cfg.Username != "" && path != "/v3/auth/authenticate" {
+		token, err := e.authenticate(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", token)
+	}
+	err = doJSON(e.client, req, v)
+	if err != nil && e.cfg.Username != "" && strings.Contains(err.Error(), "auth token") {
+		// token 失效, 下次请求重新认证
+		e.mux.Lock()
+		e.token = ""
+		e.mux.Unlock()
+	}
+	return err
+}
+
+// authenticate 获取并缓存 token
+func (e *Etcd) authenticate(ctx context.Context) (string, error) {
+	e.mux.Lock()
+	token := e.token
+	e.mux.Unlock()
+	if token != "" {
+		return token, nil
+	}
+	var rsp struct {
+		Token string `json:"token"`
+	}
+	err := e.do(ctx, "/v3/auth/authenticate", map[string]string{
+		"name": e.cfg.Username, "password": e.cfg.Password,
+	}, &rsp)
+	if err != nil {
+		return "", err
+	}
+	e.mux.Lock()
+	e.token = rsp.Token
+	e.mux.Unlock()
+	return rsp.Token, nil
+}
+
+func (e *Etcd) key(name, id string) string {
+	return e.cfg.Prefix + "/" + name + "/" + id
+}
+
+func b64(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func (e *Etcd) Register(ctx context.Context, ins *Instance, ttl time.Duration) error {
+	var lease struct {
+		ID string `json:"ID"`
+	}
+	seconds := int64(ttl / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	if err := e.do(ctx, "/v3/lease/grant", map[string]interface{}{"TTL": seconds}, &lease); err != nil {
+		return err
+	}
+	value, err := json.Marshal(ins)
+	if err != nil {
+		return err
+	}
+	err = e.do(ctx, "/v3/kv/put", map[string]interface{}{
+		"key":   b64(e.key(ins.Name, ins.ID)),
+		"value": b64(string(value)),
+		"lease": lease.ID,
+	}, nil)
+	if err != nil {
+		return err
+	}
+	e.mux.Lock()
+	e.leases[ins.ID] = lease.ID
+	e.mux.Unlock()
+	return nil
+}
+
+func (e *Etcd) lease(ins *Instance) string {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	return e.leases[ins.ID]
+}
+
+func (e *Etcd) Heartbeat(ctx context.Context, ins *Instance) error {
+	id := e.lease(ins)
+	if id == "" {
+		return ErrNotRegistered
+	}
+	var rsp struct {
+		Result struct {
+			TTL string `json:"TTL"`
+		} `json:"result"`
+	}
+	if err := e.do(ctx, "/v3/lease/keepalive", map[string]string{"ID": id}, &rsp); err != nil {
+		return err
+	}
+	// 租约已过期时返回的 TTL 为空或0
+	if ttl, _ := strconv.ParseInt(rsp.Result.TTL, 10, 64); ttl <= 0 {
+		return ErrNotRegistered
+	}
+	return nil
+}
+
+func (e *Etcd) Deregister(ctx context.Context, ins *Instance) error {
+	id := e.lease(ins)
+	if id == "" {
+		return nil
+	}
+	e.mux.Lock()
+	delete(e.leases, ins.ID)
+	e.mux.Unlock()
+	return e.do(ctx, "/v3/lease/revoke", map[string]string{"ID": id}, nil)
+}
+
+func (e *Etcd) Instances(ctx context.Context, name string) ([]*Instance, error) {
+	prefix := e.key(name, "")
+	var rsp struct {
+		Kvs []struct {
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}
+	err := e.do(ctx, "/v3/kv/range", map[string]string{
+		"key":       b64(prefix),
+		"range_end": b64(prefixEnd(prefix)),
+	}, &rsp)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Instance, 0, len(rsp.Kvs))
+	for _, kv := range rsp.Kvs {
+		b, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		ins := &Instance{}
+		if err = json.Unmarshal(b, ins); err != nil {
+			return nil, err
+		}
+		list = append(list, ins)
+	}
+	return list, nil
+}
+
+// prefixEnd 前缀查询的 range_end, 最后一个字节加1
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	return "\x00"
+}
@@ -0,0 +1,109 @@
+package discovery
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+)
+
+// Scheme gRPC 连接的 target 前缀, 如 discovery:///sys-user
+const Scheme = "discovery"
+
+// roundRobinConfig 在实例间轮询
+const roundRobinConfig = `{"loadBalancingConfig":[{"round_robin":{}}]}`
+
+// DialOptions 通过服务名连接 gRPC 服务, 实例变化时自动更新连接, 请求在实例间轮询
+//
+//	grpc.Dial(ctx, "discovery:///sys-user", grpc.WithClientDialOptions(r.DialOptions()...))
+func (r *Resolver) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithResolvers(&builder{resolver: r}),
+		grpc.WithDefaultServiceConfig(roundRobinConfig),
+	}
+}
+
+type builder struct {
+	resolver *Resolver
+}
+
+func (b *builder) Scheme() string {
+	return Scheme
+}
+
+func (b *builder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	name := strings.TrimPrefix(target.URL.Path, "/")
+	if name == "" {
+		name = target.URL.Opaque
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &watcher{
+		resolver: b.resolver,
+		name:     name,
+		cc:       cc,
+		cancel:   cancel,
+		now:      make(chan struct{}, 1),
+	}
+	w.wg.Add(1)
+	go w.watch(ctx)
+	return w, nil
+}
+
+// watcher 按 Resolver 的刷新间隔查询实例并更新连接
+type watcher struct {
+	resolver *Resolver
+	name     string
+	cc       resolver.ClientConn
+	cancel   context.CancelFunc
+	now      chan struct{}
+	wg       sync.WaitGroup
+}
+
+func (w *watcher) watch(ctx context.Context) {
+	defer w.wg.Done()
+	ticker := time.NewTicker(w.resolver.opts.refresh)
+	defer ticker.Stop()
+	for {
+		w.update(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.now:
+			w.resolver.Invalidate(w.name)
+		}
+	}
+}
+
+func (w *watcher) update(ctx context.Context) {
+	list, err := w.resolver.Instances(ctx, w.name, SchemeGRPC)
+	if err != nil {
+		w.cc.ReportError(err)
+		return
+	}
+	if len(list) == 0 {
+		w.cc.ReportError(ErrNoInstance)
+		return
+	}
+	addrs := make([]resolver.Address, 0, len(list))
+	for _, ins := range list {
+		addrs = append(addrs, resolver.Address{Addr: ins.Addr()})
+	}
+	_ = w.cc.UpdateState(resolver.State{Addresses: addrs})
+}
+
+// ResolveNow 连接失败时由 grpc 调用, 立即刷新实例
+func (w *watcher) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case w.now <- struct{}{}:
+	default:
+	}
+}
+
+func (w *watcher) Close() {
+	w.cancel()
+	w.wg.Wait()
+}
@@ -0,0 +1,187 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NacosConfig nacos 服务地址, 如 http://127.0.0.1:8848
+type NacosConfig struct {
+	Address   string `json:"address"`
+	Namespace string `json:"namespace"`
+	Group     string `json:"group"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+}
+
+// Nacos 注册临时实例, 服务端在15秒未收到心跳后标记为不健康
+type Nacos struct {
+	cfg    NacosConfig
+	client *http.Client
+	mux    sync.Mutex
+	token  string
+	expire time.Time
+}
+
+// nacos 心跳返回实例不存在的代码
+const nacosNotFound = 20404
+
+func NewNacos(cfg NacosConfig) *Nacos {
+	if cfg.Address == "" {
+		cfg.Address = "http://127.0.0.1:8848"
+	}
+	if cfg.Group == "" {
+		cfg.Group = "DEFAULT_GROUP"
+	}
+	cfg.Address = strings.TrimRight(cfg.Address, "/")
+	return &Nacos{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (*Nacos) String() string {
+	return "nacos"
+}
+
+func (n *Nacos) do(ctx context.Context, method, path string, query url.Values, v interface{}) error {
+	if n.cfg.Namespace != "" {
+		query.Set("namespaceId", n.cfg.Namespace)
+	}
+	if n.cfg.Username != "" {
+		token, err := n.login(ctx)
+		if err != nil {
+			return err
+		}
+		query.Set("accessToken", token)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, n.cfg.Address+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	return doJSON(n.client, req, v)
+}
+
+// login 获取 accessToken, 过期前重新登录
+func (n *Nacos) login(ctx context.Context) (string, error) {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	if n.token != "" && time.Now().Before(n.expire) {
+		return n.token, nil
+	}
+	form := url.Values{"username": {n.cfg.Username}, "password": {n.cfg.Password}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.Address+"/nacos/v1/auth/login",
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var rsp struct {
+		AccessToken string `json:"accessToken"`
+		TokenTtl    int64  `json:"tokenTtl"`
+	}
+	if err = doJSON(n.client, req, &rsp); err != nil {
+		return "", err
+	}
+	n.token = rsp.AccessToken
+	// 提前一分钟过期
+	n.expire = time.Now().Add(time.Duration(rsp.TokenTtl)*time.Second - time.Minute)
+	return n.token, nil
+}
+
+func (n *Nacos) instanceQuery(ins *Instance) url.Values {
+	return url.Values{
+		"serviceName": {ins.Name},
+		"groupName":   {n.cfg.Group},
+		"ip":          {ins.Host},
+		"port":        {strconv.Itoa(ins.Port)},
+		"ephemeral":   {"true"},
+	}
+}
+
+func (n *Nacos) metadata(ins *Instance) string {
+	meta := map[string]string{"scheme": ins.Scheme, "id": ins.ID}
+	for k, v := range ins.Metadata {
+		meta[k] = v
+	}
+	b, _ := json.Marshal(meta)
+	return string(b)
+}
+
+func (n *Nacos) Register(ctx context.Context, ins *Instance, _ time.Duration) error {
+	query := n.instanceQuery(ins)
+	query.Set("metadata", n.metadata(ins))
+	query.Set("weight", "1")
+	query.Set("enabled", "true")
+	query.Set("healthy", "true")
+	return n.do(ctx, http.MethodPost, "/nacos/v1/ns/instance", query, nil)
+}
+
+func (n *Nacos) Heartbeat(ctx context.Context, ins *Instance) error {
+	beat, _ := json.Marshal(map[string]interface{}{
+		"serviceName": n.cfg.Group + "@@" + ins.Name,
+		"ip":          ins.Host,
+		"port":        ins.Port,
+		"metadata":    json.RawMessage(n.metadata(ins)),
+		"scheduled":   true,
+	})
+	query := n.instanceQuery(ins)
+	query.Set("beat", string(beat))
+	var rsp struct {
+		Code int `json:"code"`
+	}
+	if err := n.do(ctx, http.MethodPut, "/nacos/v1/ns/instance/beat", query, &rsp); err != nil {
+		return err
+	}
+	if rsp.Code == nacosNotFound {
+		return ErrNotRegistered
+	}
+	return nil
+}
+
+func (n *Nacos) Deregister(ctx context.Context, ins *Instance) error {
+	return n.do(ctx, http.MethodDelete, "/nacos/v1/ns/instance", n.instanceQuery(ins), nil)
+}
+
+func (n *Nacos) Instances(ctx context.Context, name string) ([]*Instance, error) {
+	var rsp struct {
+		Hosts []struct {
+			InstanceID string            `json:"instanceId"`
+			IP         string            `json:"ip"`
+			Port       int               `json:"port"`
+			Healthy    bool              `json:"healthy"`
+			Enabled    bool              `json:"enabled"`
+			Metadata   map[string]string `json:"metadata"`
+		} `json:"hosts"`
+	}
+	query := url.Values{
+		"serviceName": {name},
+		"groupName":   {n.cfg.Group},
+		"healthyOnly": {"true"},
+	}
+	if err := n.do(ctx, http.MethodGet, "/nacos/v1/ns/instance/list", query, &rsp); err != nil {
+		return nil, err
+	}
+	list := make([]*Instance, 0, len(rsp.Hosts))
+	for _, h := range rsp.Hosts {
+		if !h.Healthy || !h.Enabled {
+			continue
+		}
+		id := h.Metadata["id"]
+		if id == "" {
+			id = h.InstanceID
+		}
+		list = append(list, &Instance{
+			ID:       id,
+			Name:     name,
+			Scheme:   h.Metadata["scheme"],
+			Host:     h.IP,
+			Port:     h.Port,
+			Metadata: h.Metadata,
+		})
+	}
+	return list, nil
+}
@@ -0,0 +1,166 @@
+package discovery
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+// Balancer 从实例列表中选择一个, list 不为空
+type Balancer interface {
+	Pick(list []*Instance) *Instance
+}
+
+// RoundRobin 轮询
+type RoundRobin struct {
+	next uint64
+}
+
+func (b *RoundRobin) Pick(list []*Instance) *Instance {
+	n := atomic.AddUint64(&b.next, 1)
+	return list[(n-1)%uint64(len(list))]
+}
+
+// Random 随机
+type Random struct{}
+
+func (Random) Pick(list []*Instance) *Instance {
+	return list[rand.Intn(len(list))]
+}
+
+type ResolverOption func(*resolverOptions)
+
+type resolverOptions struct {
+	refresh  time.Duration
+	balancer func() Balancer
+}
+
+// WithRefresh 实例列表的缓存时间, 默认10秒
+func WithRefresh(d time.Duration) ResolverOption {
+	return func(o *resolverOptions) {
+		o.refresh = d
+	}
+}
+
+// WithBalancer 每个服务创建一个 Balancer, 默认轮询
+func WithBalancer(f func() Balancer) ResolverOption {
+	return func(o *resolverOptions) {
+		o.balancer = f
+	}
+}
+
+type service struct {
+	mux      sync.Mutex
+	list     []*Instance
+	updated  time.Time
+	balancer Balancer
+}
+
+// Resolver 按服务名查询实例, 缓存实例列表并在客户端做负载均衡
+type Resolver struct {
+	backend  Backend
+	opts     resolverOptions
+	mux      sync.Mutex
+	services map[string]*service
+}
+
+func NewResolver(b Backend, opts ...ResolverOption) *Resolver {
+	o := resolverOptions{
+		refresh:  10 * time.Second,
+		balancer: func() Balancer { return &RoundRobin{} },
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Resolver{backend: b, opts: o, services: make(map[string]*service)}
+}
+
+func (r *Resolver) service(name string) *service {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	s, ok := r.services[name]
+	if !ok {
+		s = &service{balancer: r.opts.balancer()}
+		r.services[name] = s
+	}
+	return s
+}
+
+// Instances 服务的健康实例, scheme 为空时不过滤协议;
+// 刷新失败时沿用上次的列表, 避免注册中心短暂不可用影响调用
+func (r *Resolver) Instances(ctx context.Context, name, scheme string) ([]*Instance, error) {
+	s := r.service(name)
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.list == nil || time.Since(s.updated) >= r.opts.refresh {
+		list, err := r.backend.Instances(ctx, name)
+		if err != nil {
+			if s.list == nil {
+				return nil, err
+			}
+			logger.Module("sdk.discovery").WithContext(ctx).
+				Warn("refresh instances failed, use cached", "service", name, "error", err)
+		} else {
+			s.list = list
+		}
+		s.updated = time.Now()
+	}
+	if scheme == "" {
+		return s.list, nil
+	}
+	list := make([]*Instance, 0, len(s.list))
+	for _, ins := range s.list {
+		if ins.Scheme == scheme {
+			list = append(list, ins)
+		}
+	}
+	return list, nil
+}
+
+// Pick 按负载均衡策略选择一个实例
+func (r *Resolver) Pick(ctx context.Context, name, scheme string) (*Instance, error) {
+	list, err := r.Instances(ctx, name, scheme)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, ErrNoInstance
+	}
+	return r.service(name).balancer.Pick(list), nil
+}
+
+// Invalidate 清除缓存, 调用失败时可立即刷新
+func (r *Resolver) Invalidate(name string) {
+	s := r.service(name)
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.updated = time.Time{}
+}
+
+// Transport 将请求 url 中的 host 作为服务名替换为实例地址, 如 http://sys-user/api/v1/user
+func (r *Resolver) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripper(func(req *http.Request) (*http.Response, error) {
+		ins, err := r.Pick(req.Context(), req.URL.Hostname(), SchemeHTTP)
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.URL.Host = ins.Addr()
+		req.Host = ins.Addr()
+		return next.RoundTrip(req)
+	})
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
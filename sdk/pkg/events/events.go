@@ -0,0 +1,182 @@
+// Package events 领域事件的发布订阅, 经由队列投递, 支持事务 outbox 和进程内同步模式
+//
+//	bus := events.New(events.WithQueue(q), events.WithOutbox(db, 0))
+//	events.Subscribe(bus, func(ctx context.Context, e OrderCreated) error { ... })
+//	txn.Do(ctx, db, func(ctx context.Context) error {
+//		...
+//		return bus.Publish(ctx, OrderCreated{Id: order.Id})
+//	})
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/txn"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+var ErrNoQueue = errors.New("events: queue not configured")
+
+// Event 领域事件, 使用json序列化
+type Event interface {
+	EventName() string
+}
+
+// 消息 Values 中的key
+const (
+	idKey       = "event_id"
+	nameKey     = "event"
+	payloadKey  = "payload"
+	occurredKey = "occurred_at"
+)
+
+type handler func(ctx context.Context, payload []byte) error
+
+// Bus 事件总线, 实现了 runtime.Component, Start 后定时补偿投递 outbox
+type Bus struct {
+	opts options
+
+	mux      sync.RWMutex
+	handlers map[string][]handler
+
+	relayMux sync.Mutex
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// New 创建事件总线, 需设置 WithQueue 或 WithSync
+func New(opts ...Option) *Bus {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Bus{opts: o, handlers: make(map[string][]handler)}
+}
+
+func (*Bus) String() string {
+	return "events"
+}
+
+// Publish 发布事件; ctx中有事务(txn.Do)时在提交后才投递, 回滚则丢弃
+func (b *Bus) Publish(ctx context.Context, events ...Event) error {
+	messages := make([]storage.Messager, 0, len(events))
+	for _, e := range events {
+		m, err := b.message(ctx, e)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, m)
+	}
+	switch {
+	case b.opts.sync:
+		if !txn.InTransaction(ctx) {
+			return b.dispatchAll(messages)
+		}
+		txn.AfterCommit(ctx, func() {
+			if err := b.dispatchAll(messages); err != nil {
+				logger.Module("sdk.events").WithContext(ctx).Error("handle event failed", "error", err)
+			}
+		})
+		return nil
+	case b.opts.queue == nil:
+		return ErrNoQueue
+	case b.opts.db != nil:
+		return b.save(ctx, messages)
+	}
+	for _, m := range messages {
+		if err := txn.Publish(ctx, b.opts.queue, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Subscribe 订阅事件, 同一事件可以有多个处理函数; 队列模式需在队列 Run 之前调用
+func Subscribe[T Event](b *Bus, f func(ctx context.Context, e T) error) {
+	b.subscribe(nameOf[T](), func(ctx context.Context, payload []byte) error {
+		var e T
+		if err := json.Unmarshal(payload, &e); err != nil {
+			return err
+		}
+		return f(ctx, e)
+	})
+}
+
+func (b *Bus) subscribe(name string, h handler) {
+	b.mux.Lock()
+	first := len(b.handlers[name]) == 0
+	b.handlers[name] = append(b.handlers[name], h)
+	b.mux.Unlock()
+	if first && !b.opts.sync && b.opts.queue != nil {
+		b.opts.queue.Register(b.opts.prefix+name, b.dispatch)
+	}
+}
+
+func (b *Bus) message(ctx context.Context, e Event) (storage.Messager, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("events: marshal %s: %w", e.EventName(), err)
+	}
+	m := new(queue.Message)
+	m.SetStream(b.opts.prefix + e.EventName())
+	m.SetValues(map[string]interface{}{
+		idKey:       uuid.New().String(),
+		nameKey:     e.EventName(),
+		payloadKey:  string(payload),
+		occurredKey: time.Now().Format(time.RFC3339Nano),
+	})
+	queue.InjectTrace(ctx, m)
+	return m, nil
+}
+
+// dispatch 调用全部处理函数, 返回第一个错误
+func (b *Bus) dispatch(m storage.Messager) error {
+	values := m.GetValues()
+	name, _ := values[nameKey].(string)
+	payload, _ := values[payloadKey].(string)
+	b.mux.RLock()
+	handlers := b.handlers[name]
+	b.mux.RUnlock()
+	ctx := queue.TraceContext(m)
+	var first error
+	for _, h := range handlers {
+		if err := h(ctx, []byte(payload)); err != nil {
+			logger.Module("sdk.events").WithContext(ctx).Error("handle event failed",
+				"event", name, "id", values[idKey], "error", err)
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+func (b *Bus) dispatchAll(messages []storage.Messager) error {
+	var first error
+	for _, m := range messages {
+		if err := b.dispatch(m); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// nameOf 事件名, T 为指针时使用新建的值, 避免值接收者方法对nil解引用
+func nameOf[T Event]() string {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Ptr {
+		return reflect.New(t.Elem()).Interface().(Event).EventName()
+	}
+	var zero T
+	return zero.EventName()
+}
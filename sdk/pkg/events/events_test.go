@@ -0,0 +1,180 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/txn"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+type OrderCreated struct {
+	Id int `json:"id"`
+}
+
+func (OrderCreated) EventName() string { return "order.created" }
+
+type OrderPaid struct {
+	Id int `json:"id"`
+}
+
+func (*OrderPaid) EventName() string { return "order.paid" }
+
+type fakeQueue struct {
+	storage.AdapterQueue
+	fail     error
+	messages []storage.Messager
+}
+
+func (*fakeQueue) String() string { return "fake" }
+
+func (q *fakeQueue) Register(string, storage.ConsumerFunc) {}
+
+func (q *fakeQueue) Append(m storage.Messager) error {
+	if q.fail != nil {
+		return q.fail
+	}
+	q.messages = append(q.messages, m)
+	return nil
+}
+
+func newDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	return db
+}
+
+var errFail = errors.New("fail")
+
+func TestSync(t *testing.T) {
+	bus := New(WithSync())
+	var got []int
+	Subscribe(bus, func(ctx context.Context, e OrderCreated) error {
+		got = append(got, e.Id)
+		return nil
+	})
+	Subscribe(bus, func(ctx context.Context, e *OrderPaid) error {
+		if e.Id == 0 {
+			return errFail
+		}
+		got = append(got, -e.Id)
+		return nil
+	})
+	if err := bus.Publish(context.Background(), OrderCreated{Id: 1}, &OrderPaid{Id: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Publish(context.Background(), &OrderPaid{}); !errors.Is(err, errFail) {
+		t.Errorf("err = %v", err)
+	}
+
+	db := newDB(t)
+	_ = txn.Do(context.Background(), db, func(ctx context.Context) error {
+		_ = bus.Publish(ctx, OrderCreated{Id: 2})
+		return errFail
+	})
+	_ = txn.Do(context.Background(), db, func(ctx context.Context) error {
+		_ = bus.Publish(ctx, OrderCreated{Id: 3})
+		if len(got) != 2 {
+			t.Error("handled before commit")
+		}
+		return nil
+	})
+	if len(got) != 3 || got[0] != 1 || got[1] != -1 || got[2] != 3 {
+		t.Errorf("got = %v", got)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	q := queue.NewMemory(10)
+	bus := New(WithQueue(q))
+	got := make(chan OrderCreated, 1)
+	Subscribe(bus, func(ctx context.Context, e OrderCreated) error {
+		got <- e
+		return nil
+	})
+	go q.Run()
+	defer q.Shutdown()
+
+	if err := bus.Publish(context.Background(), OrderCreated{Id: 7}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case e := <-got:
+		if e.Id != 7 {
+			t.Errorf("event = %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not received")
+	}
+
+	if err := New().Publish(context.Background(), OrderCreated{}); err != ErrNoQueue {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	db := newDB(t)
+	q := &fakeQueue{}
+	bus := New(WithQueue(q), WithOutbox(db, time.Hour))
+	if err := bus.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	pending := func() int64 {
+		var n int64
+		db.Model(&Outbox{}).Where("sent_at IS NULL").Count(&n)
+		return n
+	}
+
+	_ = txn.Do(context.Background(), db, func(ctx context.Context) error {
+		_ = bus.Publish(ctx, OrderCreated{Id: 1})
+		return errFail
+	})
+	var total int64
+	db.Model(&Outbox{}).Count(&total)
+	if total != 0 {
+		t.Fatalf("rollback should discard outbox, total = %d", total)
+	}
+
+	err := txn.Do(context.Background(), db, func(ctx context.Context) error {
+		if err := bus.Publish(ctx, OrderCreated{Id: 2}); err != nil {
+			return err
+		}
+		if len(q.messages) != 0 {
+			t.Error("published before commit")
+		}
+		return nil
+	})
+	if err != nil || len(q.messages) != 1 || pending() != 0 {
+		t.Fatalf("err = %v, published = %d, pending = %d", err, len(q.messages), pending())
+	}
+	values := q.messages[0].GetValues()
+	if q.messages[0].GetStream() != "events.order.created" || values["payload"] != `{"id":2}` || values["event_id"] == "" {
+		t.Errorf("message = %s %v", q.messages[0].GetStream(), values)
+	}
+
+	// 投递失败时保留, 之后补偿投递
+	q.fail = errFail
+	if err = bus.Publish(context.Background(), OrderCreated{Id: 3}); err != nil {
+		t.Fatal(err)
+	}
+	var row Outbox
+	db.Where("sent_at IS NULL").Take(&row)
+	if row.Attempts != 1 || row.LastError != "fail" {
+		t.Errorf("row = %+v", row)
+	}
+	q.fail = nil
+	if n, err := bus.Relay(context.Background()); err != nil || n != 1 || pending() != 0 || len(q.messages) != 2 {
+		t.Errorf("n = %d, err = %v, pending = %d", n, err, pending())
+	}
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// DefaultPrefix 事件队列名前缀, 队列名为 前缀+事件名
+const DefaultPrefix = "events."
+
+type Option func(*options)
+
+type options struct {
+	queue    storage.AdapterQueue
+	prefix   string
+	sync     bool
+	db       *gorm.DB
+	interval time.Duration
+	batch    int
+}
+
+func setDefault() options {
+	return options{
+		prefix:   DefaultPrefix,
+		interval: 5 * time.Second,
+		batch:    100,
+	}
+}
+
+// WithQueue 通过队列投递事件
+func WithQueue(q storage.AdapterQueue) Option {
+	return func(o *options) {
+		o.queue = q
+	}
+}
+
+// WithPrefix 队列名前缀, 默认 events.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithSync 进程内同步投递, 不经过队列和 outbox, 用于测试和单体部署
+func WithSync() Option {
+	return func(o *options) {
+		o.sync = true
+	}
+}
+
+// WithOutbox 先写入 outbox 表(与业务在同一事务), 提交后再投递到队列;
+// interval 为补偿投递的轮询间隔, 0时使用默认的5s
+func WithOutbox(db *gorm.DB, interval time.Duration) Option {
+	return func(o *options) {
+		o.db = db
+		if interval > 0 {
+			o.interval = interval
+		}
+	}
+}
+
+// WithBatch 每次补偿投递的最大条数, 默认100
+func WithBatch(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.batch = n
+		}
+	}
+}
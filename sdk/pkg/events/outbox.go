@@ -0,0 +1,134 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/txn"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+// Outbox 待投递的事件, 与业务数据在同一事务中写入
+type Outbox struct {
+	Id        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Stream    string     `json:"stream" gorm:"size:128"`
+	Values    string     `json:"values" gorm:"type:text"`
+	Attempts  int        `json:"attempts"`
+	LastError string     `json:"lastError" gorm:"size:512"`
+	CreatedAt time.Time  `json:"createdAt"`
+	SentAt    *time.Time `json:"sentAt" gorm:"index"`
+}
+
+func (Outbox) TableName() string {
+	return "sys_event_outbox"
+}
+
+// Migrate 创建 outbox 表
+func (b *Bus) Migrate() error {
+	if b.opts.db == nil {
+		return nil
+	}
+	return b.opts.db.AutoMigrate(&Outbox{})
+}
+
+// save 写入 outbox, 提交后立即尝试投递, 失败的由 Start 启动的轮询补偿
+func (b *Bus) save(ctx context.Context, messages []storage.Messager) error {
+	rows := make([]Outbox, 0, len(messages))
+	for _, m := range messages {
+		values, err := json.Marshal(m.GetValues())
+		if err != nil {
+			return err
+		}
+		rows = append(rows, Outbox{Stream: m.GetStream(), Values: string(values)})
+	}
+	if err := txn.DB(ctx, b.opts.db).Create(&rows).Error; err != nil {
+		return err
+	}
+	txn.AfterCommit(ctx, func() {
+		if _, err := b.Relay(context.Background()); err != nil {
+			logger.Module("sdk.events").WithContext(ctx).Warn("relay outbox failed", "error", err)
+		}
+	})
+	return nil
+}
+
+// Relay 按写入顺序投递未发送的事件, 返回成功条数; 遇到投递失败时停止, 保证顺序
+// 多实例同时投递时可能重复, 消费方应按 event_id 幂等
+func (b *Bus) Relay(ctx context.Context) (int, error) {
+	if b.opts.db == nil || b.opts.queue == nil {
+		return 0, nil
+	}
+	b.relayMux.Lock()
+	defer b.relayMux.Unlock()
+	db := b.opts.db.WithContext(ctx)
+	var rows []Outbox
+	if err := db.Where("sent_at IS NULL").Order("id").Limit(b.opts.batch).Find(&rows).Error; err != nil {
+		return 0, err
+	}
+	for i, row := range rows {
+		values := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(row.Values), &values); err != nil {
+			return i, err
+		}
+		m := new(queue.Message)
+		m.SetStream(row.Stream)
+		m.SetValues(values)
+		if err := b.opts.queue.Append(m); err != nil {
+			reason := err.Error()
+			if len(reason) > 512 {
+				reason = reason[:512]
+			}
+			db.Model(&Outbox{}).Where("id = ?", row.Id).Updates(map[string]interface{}{
+				"attempts":   row.Attempts + 1,
+				"last_error": reason,
+			})
+			return i, err
+		}
+		if err := db.Model(&Outbox{}).Where("id = ?", row.Id).Update("sent_at", time.Now()).Error; err != nil {
+			return i, err
+		}
+	}
+	return len(rows), nil
+}
+
+// Start 启用 outbox 时后台定时补偿投递, 不阻塞
+func (b *Bus) Start(ctx context.Context) error {
+	if b.opts.db == nil || b.opts.queue == nil {
+		return nil
+	}
+	ctx, b.cancel = context.WithCancel(context.Background())
+	b.done = make(chan struct{})
+	go func() {
+		defer close(b.done)
+		ticker := time.NewTicker(b.opts.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := b.Relay(ctx); err != nil && ctx.Err() == nil {
+					logger.Module("sdk.events").Warn("relay outbox failed", "error", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop 停止补偿投递
+func (b *Bus) Stop(ctx context.Context) error {
+	if b.cancel == nil {
+		return nil
+	}
+	b.cancel()
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package flags
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+)
+
+// Routes 开关管理接口, 需自行挂载到有鉴权的路由组
+//
+//	GET    /            全部开关
+//	GET    /:key        单个开关
+//	PUT    /:key        新增或修改
+//	DELETE /:key        删除
+//	PUT    /:key/toggle 打开或关闭, body 为 {"enabled": true}
+func (m *Manager) Routes(r gin.IRouter) {
+	r.GET("", m.listHandler)
+	r.GET("/:key", m.getHandler)
+	r.PUT("/:key", m.saveHandler)
+	r.DELETE("/:key", m.deleteHandler)
+	r.PUT("/:key/toggle", m.toggleHandler)
+}
+
+// EvaluateHandler 计算当前请求下的开关, 查询参数 keys 可传多个, 返回 {key: bool}, 供前端使用;
+// 请求 ctx 中的属性需要由之前的中间件通过 WithAttributes 写入
+func (m *Manager) EvaluateHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		result := make(map[string]bool)
+		for _, key := range c.QueryArray("keys") {
+			result[key] = m.Evaluate(ctx, key)
+		}
+		response.OK(c, result, "")
+	}
+}
+
+func (m *Manager) listHandler(c *gin.Context) {
+	list, err := m.List(c.Request.Context())
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+	response.OK(c, list, "")
+}
+
+func (m *Manager) getHandler(c *gin.Context) {
+	f, err := m.Get(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+	response.OK(c, f, "")
+}
+
+func (m *Manager) saveHandler(c *gin.Context) {
+	f := &Flag{}
+	if err := c.ShouldBindJSON(f); err != nil {
+		response.Fail(c, response.ErrBadRequest.Wrap(err))
+		return
+	}
+	f.Key = c.Param("key")
+	if err := m.Save(c.Request.Context(), f); err != nil {
+		response.Fail(c, err)
+		return
+	}
+	response.OK(c, f, "")
+}
+
+func (m *Manager) deleteHandler(c *gin.Context) {
+	key := c.Param("key")
+	if err := m.Delete(c.Request.Context(), key); err != nil {
+		response.Fail(c, err)
+		return
+	}
+	response.OK(c, key, "")
+}
+
+func (m *Manager) toggleHandler(c *gin.Context) {
+	req := struct {
+		Enabled bool `json:"enabled"`
+	}{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrBadRequest.Wrap(err))
+		return
+	}
+	key := c.Param("key")
+	if err := m.Toggle(c.Request.Context(), key, req.Enabled); err != nil {
+		response.Fail(c, err)
+		return
+	}
+	response.OK(c, key, "")
+}
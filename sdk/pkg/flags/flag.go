@@ -0,0 +1,214 @@
+package flags
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+// 开关类型
+const (
+	// TypeBool 只看 Enabled
+	TypeBool = "bool"
+	// TypePercentage 按 BucketBy 属性哈希灰度, 同一用户的结果稳定
+	TypePercentage = "percentage"
+	// TypeAttribute 满足任一规则时开启
+	TypeAttribute = "attribute"
+)
+
+// 规则的比较方式
+const (
+	OpIn     = "in"
+	OpNotIn  = "not_in"
+	OpPrefix = "prefix"
+)
+
+// DefaultBucketBy 灰度默认使用的属性, 未设置时取 logger.OperatorID
+const DefaultBucketBy = "userId"
+
+var (
+	ErrFlagNotFound = errors.New("flags: flag not found")
+	ErrInvalidFlag  = errors.New("flags: invalid flag")
+)
+
+// Rule 属性规则, 如 {"attribute":"tenant","operator":"in","values":["a","b"]}
+type Rule struct {
+	Attribute string   `json:"attribute" yaml:"attribute"`
+	Operator  string   `json:"operator" yaml:"operator"`
+	Values    []string `json:"values" yaml:"values"`
+}
+
+func (r Rule) match(attrs Attributes) bool {
+	v, ok := attrs[r.Attribute]
+	switch r.Operator {
+	case OpNotIn:
+		return !ok || !contains(r.Values, v)
+	case OpPrefix:
+		if !ok {
+			return false
+		}
+		for _, p := range r.Values {
+			if strings.HasPrefix(v, p) {
+				return true
+			}
+		}
+		return false
+	default:
+		return ok && contains(r.Values, v)
+	}
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Rules 以json保存到数据库
+type Rules []Rule
+
+func (r Rules) Value() (driver.Value, error) {
+	if r == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(r)
+	return string(b), err
+}
+
+func (r *Rules) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		*r = nil
+		return nil
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("flags: unsupported rules type %T", src)
+	}
+	return json.Unmarshal(b, r)
+}
+
+// Flag 功能开关, 表 sys_feature_flag, 也可以写在配置文件中
+type Flag struct {
+	Key     string `json:"key" yaml:"key" gorm:"primaryKey;size:128"`
+	Type    string `json:"type" yaml:"type" gorm:"size:16"`
+	Enabled bool   `json:"enabled" yaml:"enabled"`
+	// Percentage 灰度比例 0~100, TypePercentage 使用
+	Percentage int `json:"percentage" yaml:"percentage"`
+	// BucketBy 灰度使用的属性, 默认 DefaultBucketBy
+	BucketBy string `json:"bucketBy" yaml:"bucketBy" gorm:"size:64"`
+	// Rules TypeAttribute 使用, 任一规则满足即开启
+	Rules       Rules     `json:"rules" yaml:"rules" gorm:"type:text"`
+	Description string    `json:"description" yaml:"description" gorm:"size:255"`
+	UpdatedAt   time.Time `json:"updatedAt" yaml:"-"`
+}
+
+func (Flag) TableName() string {
+	return "sys_feature_flag"
+}
+
+// Validate 检查类型与灰度比例
+func (f *Flag) Validate() error {
+	if f.Key == "" {
+		return fmt.Errorf("%w: empty key", ErrInvalidFlag)
+	}
+	switch f.Type {
+	case "":
+		f.Type = TypeBool
+	case TypeBool, TypePercentage, TypeAttribute:
+	default:
+		return fmt.Errorf("%w: unknown type %s", ErrInvalidFlag, f.Type)
+	}
+	if f.Percentage < 0 || f.Percentage > 100 {
+		return fmt.Errorf("%w: percentage must be between 0 and 100", ErrInvalidFlag)
+	}
+	return nil
+}
+
+// Evaluate 按 ctx 中的属性计算开关是否开启, 关闭的开关总是 false
+func (f *Flag) Evaluate(ctx context.Context) bool {
+	if f == nil || !f.Enabled {
+		return false
+	}
+	switch f.Type {
+	case TypePercentage:
+		if f.Percentage >= 100 {
+			return true
+		}
+		by := f.BucketBy
+		if by == "" {
+			by = DefaultBucketBy
+		}
+		id := AttributesFrom(ctx)[by]
+		if id == "" || f.Percentage <= 0 {
+			return false
+		}
+		return bucket(f.Key, id) < f.Percentage
+	case TypeAttribute:
+		attrs := AttributesFrom(ctx)
+		for _, r := range f.Rules {
+			if r.match(attrs) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// bucket 0~99, 加上 key 使不同开关的灰度用户不同
+func bucket(key, id string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key + ":" + id))
+	return int(h.Sum32() % 100)
+}
+
+// Attributes 用于计算开关的属性, 如用户、租户、版本
+type Attributes map[string]string
+
+type attributesKey struct{}
+
+// WithAttributes ctx 附加属性, 与已有的合并
+func WithAttributes(ctx context.Context, attrs Attributes) context.Context {
+	merged := make(Attributes)
+	if old, ok := ctx.Value(attributesKey{}).(Attributes); ok {
+		for k, v := range old {
+			merged[k] = v
+		}
+	}
+	for k, v := range attrs {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, attributesKey{}, merged)
+}
+
+// AttributesFrom 获取ctx中的属性, 未设置 userId 时取 logger.OperatorID
+func AttributesFrom(ctx context.Context) Attributes {
+	attrs, _ := ctx.Value(attributesKey{}).(Attributes)
+	if _, ok := attrs[DefaultBucketBy]; ok {
+		return attrs
+	}
+	if id := logger.OperatorID(ctx); id != "" {
+		merged := make(Attributes, len(attrs)+1)
+		for k, v := range attrs {
+			merged[k] = v
+		}
+		merged[DefaultBucketBy] = id
+		return merged
+	}
+	return attrs
+}
@@ -0,0 +1,291 @@
+// Package flags 功能开关, 支持开关、灰度比例与属性规则三种类型;
+// 开关保存在数据库或配置文件中, 通过缓存共享, 修改后经队列通知各实例刷新
+//
+//	m := flags.New(store, flags.WithCache(cache, 300), flags.WithQueue(q, ""))
+//	if m.Evaluate(ctx, "new-order-page") { ... }
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+// DefaultStream 开关变更通知的队列
+const DefaultStream = "flags.changed"
+
+func init() {
+	response.RegisterError(ErrFlagNotFound, response.ErrNotFound)
+	response.RegisterError(ErrInvalidFlag, response.ErrBadRequest)
+	response.RegisterError(ErrReadOnly, response.NewError(405, http.StatusMethodNotAllowed, "flags.read_only", "配置文件中的开关不能修改"))
+}
+
+type Option func(*options)
+
+type options struct {
+	cache    storage.AdapterCache
+	cacheKey string
+	ttl      int
+	queue    storage.AdapterQueue
+	stream   string
+	refresh  time.Duration
+}
+
+func setDefault() options {
+	return options{
+		cacheKey: "flags:snapshot",
+		ttl:      300,
+		refresh:  30 * time.Second,
+	}
+}
+
+// WithCache 全部开关以json缓存, 多个实例共用, 减少数据库查询; ttl 单位秒
+func WithCache(cache storage.AdapterCache, ttl int) Option {
+	return func(o *options) {
+		o.cache = cache
+		if ttl > 0 {
+			o.ttl = ttl
+		}
+	}
+}
+
+// WithCacheKey 缓存key, 默认 flags:snapshot
+func WithCacheKey(key string) Option {
+	return func(o *options) {
+		o.cacheKey = key
+	}
+}
+
+// WithQueue 修改开关后投递变更通知, 消费到通知的实例立即刷新本地开关; stream 为空时使用 DefaultStream;
+// 多个实例竞争消费同一队列时只有一个实例立即刷新, 其余的在 WithRefresh 间隔后生效
+func WithQueue(q storage.AdapterQueue, stream string) Option {
+	return func(o *options) {
+		o.queue = q
+		if stream != "" {
+			o.stream = stream
+		}
+	}
+}
+
+// WithRefresh 本地开关的刷新间隔, 默认30s; 未收到变更通知的实例最迟在此间隔后生效
+func WithRefresh(d time.Duration) Option {
+	return func(o *options) {
+		o.refresh = d
+	}
+}
+
+// Manager 加载并计算开关, 进程内保存一份开关, 计算时不访问缓存与数据库
+type Manager struct {
+	store     Store
+	o         options
+	mux       sync.RWMutex
+	flags     map[string]*Flag
+	loaded    time.Time
+	reloadMux sync.Mutex
+}
+
+// New 创建 Manager, 配置了队列时需要在队列 Run 之前调用
+func New(store Store, opts ...Option) *Manager {
+	o := setDefault()
+	o.stream = DefaultStream
+	for _, opt := range opts {
+		opt(&o)
+	}
+	m := &Manager{store: store, o: o}
+	if o.queue != nil {
+		o.queue.Register(o.stream, m.consume)
+	}
+	return m
+}
+
+// Evaluate 开关在 ctx 下是否开启, 开关不存在或加载失败时为 false
+func (m *Manager) Evaluate(ctx context.Context, key string) bool {
+	f, err := m.Get(ctx, key)
+	if err != nil {
+		return false
+	}
+	return f.Evaluate(ctx)
+}
+
+// Get 获取开关
+func (m *Manager) Get(ctx context.Context, key string) (*Flag, error) {
+	flags, err := m.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	f, ok := flags[key]
+	if !ok {
+		return nil, ErrFlagNotFound
+	}
+	return f, nil
+}
+
+// List 全部开关
+func (m *Manager) List(ctx context.Context) ([]Flag, error) {
+	flags, err := m.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Flag, 0, len(flags))
+	for _, f := range flags {
+		list = append(list, *f)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list, nil
+}
+
+// Save 新增或修改开关, 并通知各实例刷新
+func (m *Manager) Save(ctx context.Context, f *Flag) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	if err := m.store.Save(ctx, f); err != nil {
+		return err
+	}
+	logger.Module("sdk.flags").WithContext(ctx).Info("flag saved", "key", f.Key, "enabled", f.Enabled)
+	return m.Invalidate(ctx)
+}
+
+// Toggle 打开或关闭开关
+func (m *Manager) Toggle(ctx context.Context, key string, enabled bool) error {
+	list, err := m.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		if list[i].Key == key {
+			list[i].Enabled = enabled
+			return m.Save(ctx, &list[i])
+		}
+	}
+	return ErrFlagNotFound
+}
+
+// Delete 删除开关
+func (m *Manager) Delete(ctx context.Context, key string) error {
+	if err := m.store.Delete(ctx, key); err != nil {
+		return err
+	}
+	logger.Module("sdk.flags").WithContext(ctx).Info("flag deleted", "key", key)
+	return m.Invalidate(ctx)
+}
+
+// Invalidate 清除缓存并通知各实例刷新, 直接修改存储后需要调用
+func (m *Manager) Invalidate(ctx context.Context) error {
+	m.expire()
+	if m.o.cache != nil {
+		if err := m.o.cache.Del(m.o.cacheKey); err != nil {
+			return err
+		}
+	}
+	if m.o.queue == nil {
+		return nil
+	}
+	message := new(queue.Message)
+	message.SetStream(m.o.stream)
+	message.SetValues(map[string]interface{}{"at": time.Now().UnixNano()})
+	queue.InjectTrace(ctx, message)
+	return m.o.queue.Append(message)
+}
+
+// Reload 立即重新加载, 跳过本地开关
+func (m *Manager) Reload(ctx context.Context) error {
+	m.expire()
+	_, err := m.snapshot(ctx)
+	return err
+}
+
+func (m *Manager) consume(storage.Messager) error {
+	m.expire()
+	return nil
+}
+
+func (m *Manager) expire() {
+	m.mux.Lock()
+	m.loaded = time.Time{}
+	m.mux.Unlock()
+}
+
+// snapshot 本地开关过期后重新加载, 加载失败时继续使用旧的开关
+func (m *Manager) snapshot(ctx context.Context) (map[string]*Flag, error) {
+	m.mux.RLock()
+	flags, loaded := m.flags, m.loaded
+	m.mux.RUnlock()
+	if !loaded.IsZero() && time.Since(loaded) < m.o.refresh {
+		return flags, nil
+	}
+	m.reloadMux.Lock()
+	defer m.reloadMux.Unlock()
+	m.mux.RLock()
+	flags, loaded = m.flags, m.loaded
+	m.mux.RUnlock()
+	if !loaded.IsZero() && time.Since(loaded) < m.o.refresh {
+		return flags, nil
+	}
+	list, err := m.load(ctx)
+	if err != nil {
+		if flags != nil {
+			logger.Module("sdk.flags").WithContext(ctx).Warn("reload flags failed, using stale flags", "error", err)
+			return flags, nil
+		}
+		return nil, err
+	}
+	flags = make(map[string]*Flag, len(list))
+	for i := range list {
+		flags[list[i].Key] = &list[i]
+	}
+	m.mux.Lock()
+	m.flags, m.loaded = flags, time.Now()
+	m.mux.Unlock()
+	return flags, nil
+}
+
+// load 先读缓存, 未命中时读存储并写入缓存
+func (m *Manager) load(ctx context.Context) ([]Flag, error) {
+	if m.o.cache != nil {
+		if v, err := m.o.cache.Get(m.o.cacheKey); err == nil && v != "" {
+			list := make([]Flag, 0)
+			if err = json.Unmarshal([]byte(v), &list); err == nil {
+				return list, nil
+			}
+		}
+	}
+	list, err := m.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if m.o.cache != nil {
+		if b, err := json.Marshal(list); err == nil {
+			_ = m.o.cache.Set(m.o.cacheKey, string(b), m.o.ttl)
+		}
+	}
+	return list, nil
+}
+
+var std *Manager
+
+// Setup 设置默认 Manager, 供包级函数使用
+func Setup(m *Manager) {
+	std = m
+}
+
+// Default 默认 Manager, 未调用 Setup 时为 nil
+func Default() *Manager {
+	return std
+}
+
+// Evaluate 使用默认 Manager 计算, 未调用 Setup 时为 false
+func Evaluate(ctx context.Context, key string) bool {
+	if std == nil {
+		return false
+	}
+	return std.Evaluate(ctx, key)
+}
@@ -0,0 +1,179 @@
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+// broadcastQueue 每条消息投递给全部消费者, 模拟各实例各自消费
+type broadcastQueue struct {
+	storage.AdapterQueue
+	consumers []storage.ConsumerFunc
+}
+
+func (q *broadcastQueue) Register(_ string, f storage.ConsumerFunc) {
+	q.consumers = append(q.consumers, f)
+}
+
+func (q *broadcastQueue) Append(m storage.Messager) error {
+	for _, f := range q.consumers {
+		_ = f(m)
+	}
+	return nil
+}
+
+func newStore(t *testing.T) *GormStore {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: gormlogger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	s, err := NewGormStore(db, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestEvaluate(t *testing.T) {
+	ctx := WithAttributes(context.Background(), Attributes{"tenant": "acme", "version": "2.1.0"})
+	cases := []struct {
+		flag Flag
+		want bool
+	}{
+		{Flag{Type: TypeBool, Enabled: true}, true},
+		{Flag{Type: TypeBool}, false},
+		{Flag{Type: TypePercentage, Enabled: true, Percentage: 100}, true},
+		{Flag{Type: TypePercentage, Enabled: true, Percentage: 50}, false},
+		{Flag{Type: TypeAttribute, Enabled: true, Rules: Rules{{Attribute: "tenant", Values: []string{"acme"}}}}, true},
+		{Flag{Type: TypeAttribute, Enabled: true, Rules: Rules{{Attribute: "tenant", Operator: OpNotIn, Values: []string{"acme"}}}}, false},
+		{Flag{Type: TypeAttribute, Enabled: true, Rules: Rules{{Attribute: "version", Operator: OpPrefix, Values: []string{"1.", "2."}}}}, true},
+		{Flag{Type: TypeAttribute, Enabled: false, Rules: Rules{{Attribute: "tenant", Values: []string{"acme"}}}}, false},
+	}
+	for i, c := range cases {
+		if got := c.flag.Evaluate(ctx); got != c.want {
+			t.Errorf("case %d: got %v", i, got)
+		}
+	}
+
+	// 灰度结果稳定, 比例接近设置值
+	f := &Flag{Key: "gray", Type: TypePercentage, Enabled: true, Percentage: 30}
+	var on int
+	for i := 0; i < 1000; i++ {
+		ctx := logger.WithOperatorID(context.Background(), fmt.Sprint(i))
+		v := f.Evaluate(ctx)
+		if v != f.Evaluate(ctx) {
+			t.Fatal("unstable")
+		}
+		if v {
+			on++
+		}
+	}
+	if on < 250 || on > 350 {
+		t.Errorf("on = %d", on)
+	}
+}
+
+func TestManager(t *testing.T) {
+	store := newStore(t)
+	c := cache.NewMemory()
+	q := &broadcastQueue{}
+	m := New(store, WithCache(c, 60), WithQueue(q, ""), WithRefresh(time.Hour))
+	other := New(store, WithCache(c, 60), WithQueue(q, ""), WithRefresh(time.Hour))
+	ctx := context.Background()
+
+	if err := m.Save(ctx, &Flag{Key: "a", Type: TypeBool, Enabled: true,
+		Rules: Rules{{Attribute: "tenant", Values: []string{"x"}}}}); err != nil {
+		t.Fatal(err)
+	}
+	if !m.Evaluate(ctx, "a") || !other.Evaluate(ctx, "a") || m.Evaluate(ctx, "missing") {
+		t.Fatal("unexpected evaluate")
+	}
+	if f, err := other.Get(ctx, "a"); err != nil || len(f.Rules) != 1 || f.Rules[0].Values[0] != "x" {
+		t.Fatalf("get = %+v, err = %v", f, err)
+	}
+	if v, _ := c.Get("flags:snapshot"); !strings.Contains(v, `"key":"a"`) {
+		t.Errorf("cache = %s", v)
+	}
+
+	// other 通过队列收到变更通知后刷新
+	if err := m.Toggle(ctx, "a", false); err != nil {
+		t.Fatal(err)
+	}
+	if m.Evaluate(ctx, "a") || other.Evaluate(ctx, "a") {
+		t.Error("not refreshed")
+	}
+
+	if err := m.Save(ctx, &Flag{Key: "b", Percentage: 120}); !errors.Is(err, ErrInvalidFlag) {
+		t.Errorf("invalid: err = %v", err)
+	}
+	if err := m.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Delete(ctx, "a"); !errors.Is(err, ErrFlagNotFound) {
+		t.Errorf("delete missing: err = %v", err)
+	}
+
+	ro := New(NewConfigStore([]Flag{{Key: "c", Type: TypeBool, Enabled: true}}))
+	if !ro.Evaluate(ctx, "c") {
+		t.Error("config flag")
+	}
+	if err := ro.Toggle(ctx, "c", false); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("read only: err = %v", err)
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := New(newStore(t))
+	r := gin.New()
+	m.Routes(r.Group("/flags"))
+	r.GET("/evaluate", m.EvaluateHandler())
+	do := func(method, path, body string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		res := make(map[string]interface{})
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+		return w.Code, res
+	}
+	if code, _ := do(http.MethodPut, "/flags/a", `{"type":"bool","enabled":true}`); code != http.StatusOK {
+		t.Fatalf("save: code = %d", code)
+	}
+	if code, _ := do(http.MethodPut, "/flags/b", `{"type":"unknown"}`); code != http.StatusBadRequest {
+		t.Errorf("invalid: code = %d", code)
+	}
+	if _, res := do(http.MethodGet, "/evaluate?keys=a&keys=b", ""); fmt.Sprint(res["data"]) != "map[a:true b:false]" {
+		t.Errorf("evaluate = %v", res)
+	}
+	if code, _ := do(http.MethodPut, "/flags/a/toggle", `{"enabled":false}`); code != http.StatusOK {
+		t.Fatalf("toggle: code = %d", code)
+	}
+	if _, res := do(http.MethodGet, "/flags/a", ""); res["data"].(map[string]interface{})["enabled"] != false {
+		t.Errorf("get = %v", res)
+	}
+	if code, _ := do(http.MethodGet, "/flags/b", ""); code != http.StatusNotFound {
+		t.Errorf("missing: code = %d", code)
+	}
+	if code, _ := do(http.MethodDelete, "/flags/a", ""); code != http.StatusOK {
+		t.Errorf("delete: code = %d", code)
+	}
+}
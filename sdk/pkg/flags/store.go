@@ -0,0 +1,94 @@
+package flags
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrReadOnly 配置文件中的开关不能修改
+var ErrReadOnly = errors.New("flags: store is read only")
+
+// Store 开关的存储
+type Store interface {
+	List(ctx context.Context) ([]Flag, error)
+	Save(ctx context.Context, f *Flag) error
+	Delete(ctx context.Context, key string) error
+}
+
+// GormStore 存储到数据库
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore migrate 为true时自动建表
+func NewGormStore(db *gorm.DB, migrate bool) (*GormStore, error) {
+	if migrate {
+		if err := db.AutoMigrate(&Flag{}); err != nil {
+			return nil, err
+		}
+	}
+	return &GormStore{db: db}, nil
+}
+
+func (s *GormStore) List(ctx context.Context) ([]Flag, error) {
+	list := make([]Flag, 0)
+	err := s.db.WithContext(ctx).Order(clause.OrderByColumn{Column: clause.Column{Name: "key"}}).Find(&list).Error
+	return list, err
+}
+
+func (s *GormStore) Save(ctx context.Context, f *Flag) error {
+	return s.db.WithContext(ctx).Save(f).Error
+}
+
+func (s *GormStore) Delete(ctx context.Context, key string) error {
+	res := s.db.WithContext(ctx).Delete(&Flag{Key: key})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrFlagNotFound
+	}
+	return nil
+}
+
+// ConfigStore 配置文件中的开关, 只读
+type ConfigStore struct {
+	mux  sync.RWMutex
+	list []Flag
+}
+
+// NewConfigStore 如放在 settings.extend.flags 中
+//
+//	flags:
+//	  - key: new-order-page
+//	    type: percentage
+//	    enabled: true
+//	    percentage: 20
+func NewConfigStore(list []Flag) *ConfigStore {
+	return &ConfigStore{list: list}
+}
+
+func (s *ConfigStore) List(context.Context) ([]Flag, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return append([]Flag(nil), s.list...), nil
+}
+
+func (s *ConfigStore) Save(context.Context, *Flag) error {
+	return ErrReadOnly
+}
+
+func (s *ConfigStore) Delete(context.Context, string) error {
+	return ErrReadOnly
+}
+
+// Reset 配置热更新后替换全部开关, 之后需要调用 Manager.Invalidate
+func (s *ConfigStore) Reset(list []Flag) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.list = list
+}
@@ -0,0 +1,151 @@
+// Package gen 按表结构或模型生成 CRUD 代码, 包括 model、dto、service、api 和路由注册,
+// 模板见 templates 目录, 可通过 WithTemplates 按文件名覆盖
+package gen
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+var ErrExists = errors.New("gen: file already exists")
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// targets 模板与生成文件的目录, 文件名为 Table.File
+var targets = []struct {
+	tmpl string
+	dir  string
+}{
+	{"model.go.tmpl", "models"},
+	{"dto.go.tmpl", filepath.Join("service", "dto")},
+	{"service.go.tmpl", "service"},
+	{"api.go.tmpl", "apis"},
+	{"router.go.tmpl", "router"},
+}
+
+type Option func(*options)
+
+type options struct {
+	module    string
+	output    string
+	templates fs.FS
+	force     bool
+}
+
+func setDefault() options {
+	return options{output: "."}
+}
+
+// WithModule 生成代码所在包的导入路径, 与 WithOutput 对应, 如 go-admin/app/admin
+func WithModule(module string) Option {
+	return func(o *options) {
+		o.module = module
+	}
+}
+
+// WithOutput 输出目录, 默认为当前目录
+func WithOutput(dir string) Option {
+	return func(o *options) {
+		o.output = dir
+	}
+}
+
+// WithTemplates 覆盖同名的默认模板
+func WithTemplates(fsys fs.FS) Option {
+	return func(o *options) {
+		o.templates = fsys
+	}
+}
+
+// WithForce 覆盖已存在的文件
+func WithForce(force bool) Option {
+	return func(o *options) {
+		o.force = force
+	}
+}
+
+// File 生成的文件
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// Generator 代码生成
+type Generator struct {
+	opts options
+	tmpl *template.Template
+}
+
+func New(opts ...Option) (*Generator, error) {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.module == "" {
+		return nil, errors.New("gen: module is required")
+	}
+	tmpl, err := template.New("gen").Funcs(template.FuncMap{"has": hasType}).
+		ParseFS(templates, "templates/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	if o.templates != nil {
+		if tmpl, err = tmpl.ParseFS(o.templates, "*.tmpl"); err != nil {
+			return nil, err
+		}
+	}
+	return &Generator{opts: o, tmpl: tmpl}, nil
+}
+
+// Generate 渲染并格式化, 不写入文件
+func (g *Generator) Generate(t *Table) ([]File, error) {
+	if err := t.check(); err != nil {
+		return nil, err
+	}
+	data := struct {
+		*Table
+		Module string
+	}{t, g.opts.module}
+	files := make([]File, 0, len(targets))
+	for _, target := range targets {
+		var buf bytes.Buffer
+		if err := g.tmpl.ExecuteTemplate(&buf, target.tmpl, data); err != nil {
+			return nil, err
+		}
+		path := filepath.Join(g.opts.output, target.dir, t.File+".go")
+		content, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("gen: format %s: %w", path, err)
+		}
+		files = append(files, File{Path: path, Content: content})
+	}
+	return files, nil
+}
+
+// Write 写入文件, 未设置 WithForce 时有文件已存在则不写入任何文件
+func (g *Generator) Write(files []File) error {
+	if !g.opts.force {
+		for _, f := range files {
+			if _, err := os.Stat(f.Path); err == nil {
+				return fmt.Errorf("%w: %s", ErrExists, f.Path)
+			}
+		}
+	}
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(f.Path, f.Content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
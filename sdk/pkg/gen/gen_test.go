@@ -0,0 +1,132 @@
+package gen
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type SysPost struct {
+	PostId    int    `gorm:"primaryKey;autoIncrement;comment:岗位ID"`
+	PostName  string `gorm:"size:128;comment:岗位名称"`
+	Sort      int    `gorm:"comment:排序"`
+	Remark    string `gorm:"type:text"`
+	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+func newDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestFromModel(t *testing.T) {
+	table, err := FromModel(newDB(t), &SysPost{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Name != "sys_posts" || table.Struct != "SysPost" || table.Route != "sys-posts" {
+		t.Errorf("table = %+v", table)
+	}
+	pk := table.PrimaryKey()
+	if pk.Name != "post_id" || !pk.Auto() || pk.Search != "" {
+		t.Errorf("pk = %+v", pk)
+	}
+	if got := table.Columns[1].Tag(); got != "column:post_name;size:128;comment:岗位名称" {
+		t.Errorf("tag = %s", got)
+	}
+	if got := table.Columns[3].Tag(); got != "column:remark;type:text" {
+		t.Errorf("tag = %s", got)
+	}
+	if len(table.Editable()) != 3 || len(table.Searches()) != 3 {
+		t.Errorf("editable = %d, searches = %d", len(table.Editable()), len(table.Searches()))
+	}
+	if _, err = FromModel(newDB(t), &struct{ Name string }{}); !errors.Is(err, ErrNoPrimaryKey) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestFromDB(t *testing.T) {
+	db := newDB(t)
+	if err := db.Exec("CREATE TABLE sys_dict (dict_id integer PRIMARY KEY, dict_name varchar(64), " +
+		"status tinyint, created_at datetime)").Error; err != nil {
+		t.Fatal(err)
+	}
+	table, err := FromDB(db, "sys_dict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"DictId": "int", "DictName": "string", "Status": "int", "CreatedAt": "time.Time"}
+	for _, c := range table.Columns {
+		if want[c.Field] != c.GoType {
+			t.Errorf("%s = %s", c.Field, c.GoType)
+		}
+	}
+	if !table.PrimaryKey().PrimaryKey || table.Columns[1].Size != 64 {
+		t.Errorf("columns = %+v", table.Columns)
+	}
+	if _, err = FromDB(db, "not_exist"); err == nil {
+		t.Error("missing table should fail")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	table, err := FromModel(newDB(t), &SysPost{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	g, err := New(WithModule("go-admin/app/admin"), WithOutput(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := g.Generate(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		filepath.Join(dir, "models", "sys_posts.go"):         `DeletedAt gorm.DeletedAt`,
+		filepath.Join(dir, "service", "dto", "sys_posts.go"): `search:"type:contains;column:post_name;table:sys_posts"`,
+		filepath.Join(dir, "service", "sys_posts.go"):        `"go-admin/app/admin/service/dto"`,
+		filepath.Join(dir, "apis", "sys_posts.go"):           `func (e SysPost) Delete(c *gin.Context)`,
+		filepath.Join(dir, "router", "sys_posts.go"):         `r.PUT("/:postId", api.Update)`,
+	}
+	if len(files) != len(want) {
+		t.Fatalf("files = %d", len(files))
+	}
+	for _, f := range files {
+		if !strings.Contains(string(f.Content), want[f.Path]) {
+			t.Errorf("%s:\n%s", f.Path, f.Content)
+		}
+	}
+	if strings.Contains(string(files[1].Content), `"time"`) {
+		t.Errorf("dto should not import time:\n%s", files[1].Content)
+	}
+
+	if err = g.Write(files); err != nil {
+		t.Fatal(err)
+	}
+	if err = g.Write(files); !errors.Is(err, ErrExists) {
+		t.Errorf("err = %v", err)
+	}
+
+	g, err = New(WithModule("go-admin/app/admin"), WithTemplates(fstest.MapFS{
+		"router.go.tmpl": {Data: []byte("package router\n\n// {{.Struct}}\n")},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files, err = g.Generate(table); err != nil || string(files[4].Content) != "package router\n\n// SysPost\n" {
+		t.Errorf("router = %s, err = %v", files[4].Content, err)
+	}
+}
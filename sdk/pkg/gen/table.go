@@ -0,0 +1,322 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var ErrNoPrimaryKey = errors.New("gen: table has no primary key")
+
+// 查询方式, 对应 tools/search 的 type
+const (
+	SearchExact    = "exact"
+	SearchContains = "contains"
+)
+
+// Column 字段
+type Column struct {
+	// Name 列名
+	Name string
+	// Field 结构体字段名
+	Field string
+	// JSON json 字段名
+	JSON   string
+	GoType string
+	DBType string
+	Size   int64
+	// Comment 列注释, 为空时使用 Field
+	Comment       string
+	PrimaryKey    bool
+	AutoIncrement bool
+	Nullable      bool
+	// Search 列表查询方式, 为空时不参与查询
+	Search string
+}
+
+// Auto 由数据库或 gorm 维护, 不出现在新增、修改参数中
+func (c *Column) Auto() bool {
+	if c.PrimaryKey && c.AutoIncrement {
+		return true
+	}
+	switch c.Name {
+	case "created_at", "updated_at", "deleted_at":
+		return true
+	}
+	return false
+}
+
+// Tag gorm 标签
+func (c *Column) Tag() string {
+	tags := []string{"column:" + c.Name}
+	if c.PrimaryKey {
+		tags = append(tags, "primaryKey")
+	}
+	if c.AutoIncrement {
+		tags = append(tags, "autoIncrement")
+	}
+	switch {
+	case c.GoType == "gorm.DeletedAt":
+	case c.DBType != "":
+		t := c.DBType
+		if c.Size > 0 && !strings.Contains(t, "(") {
+			t = fmt.Sprintf("%s(%d)", t, c.Size)
+		}
+		tags = append(tags, "type:"+t)
+	case c.Size > 0:
+		tags = append(tags, fmt.Sprintf("size:%d", c.Size))
+	}
+	if c.Comment != "" {
+		tags = append(tags, "comment:"+strings.ReplaceAll(c.Comment, ";", ","))
+	}
+	return strings.Join(tags, ";")
+}
+
+// Label 注释, 为空时使用字段名
+func (c *Column) Label() string {
+	if c.Comment != "" {
+		return c.Comment
+	}
+	return c.Field
+}
+
+// Table 生成代码的表
+type Table struct {
+	// Name 表名
+	Name    string
+	Comment string
+	// Struct 模型名
+	Struct string
+	// File 生成的文件名, 默认为表名
+	File string
+	// Route 路由, 默认为中划线分隔的表名
+	Route   string
+	Columns []*Column
+}
+
+// PrimaryKey 主键, 只支持单一主键
+func (t *Table) PrimaryKey() *Column {
+	for _, c := range t.Columns {
+		if c.PrimaryKey {
+			return c
+		}
+	}
+	return nil
+}
+
+// Label 表注释, 为空时使用模型名
+func (t *Table) Label() string {
+	if t.Comment != "" {
+		return t.Comment
+	}
+	return t.Struct
+}
+
+// Has 是否使用了某一类型, 用于生成 import
+func (t *Table) Has(goType string) bool {
+	return hasType(t.Columns, goType)
+}
+
+func hasType(list []*Column, goType string) bool {
+	for _, c := range list {
+		if strings.TrimLeft(c.GoType, "*[]") == goType {
+			return true
+		}
+	}
+	return false
+}
+
+// Searches 参与列表查询的字段
+func (t *Table) Searches() []*Column {
+	list := make([]*Column, 0)
+	for _, c := range t.Columns {
+		if c.Search != "" {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+// Editable 新增、修改参数中的字段
+func (t *Table) Editable() []*Column {
+	list := make([]*Column, 0)
+	for _, c := range t.Columns {
+		if !c.Auto() && !c.PrimaryKey {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+func (t *Table) check() error {
+	if t.PrimaryKey() == nil {
+		return fmt.Errorf("%w: %s", ErrNoPrimaryKey, t.Name)
+	}
+	if t.Struct == "" {
+		t.Struct = Camel(t.Name)
+	}
+	if t.File == "" {
+		t.File = t.Name
+	}
+	if t.Route == "" {
+		t.Route = strings.ReplaceAll(t.Name, "_", "-")
+	}
+	return nil
+}
+
+// FromDB 读取数据库中的表结构
+func FromDB(db *gorm.DB, table string) (*Table, error) {
+	types, err := db.Migrator().ColumnTypes(table)
+	if err != nil {
+		return nil, err
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("gen: table %s not found", table)
+	}
+	t := &Table{Name: table, Comment: tableComment(db, table)}
+	for _, ct := range types {
+		c := &Column{Name: ct.Name(), DBType: strings.ToLower(ct.DatabaseTypeName())}
+		c.PrimaryKey, _ = ct.PrimaryKey()
+		c.AutoIncrement, _ = ct.AutoIncrement()
+		c.Nullable, _ = ct.Nullable()
+		c.Comment, _ = ct.Comment()
+		if c.DBType == "varchar" || c.DBType == "char" {
+			c.Size, _ = ct.Length()
+		}
+		c.GoType = goType(c.Name, c.DBType)
+		t.Columns = append(t.Columns, c)
+	}
+	fill(t)
+	return t, t.check()
+}
+
+func tableComment(db *gorm.DB, table string) string {
+	var comment string
+	if db.Dialector.Name() == "mysql" {
+		db.Raw("SELECT table_comment FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?",
+			table).Scan(&comment)
+	}
+	return comment
+}
+
+// FromModel 读取模型的结构, model 为结构体指针
+func FromModel(db *gorm.DB, model interface{}) (*Table, error) {
+	s, err := schema.Parse(model, &sync.Map{}, db.NamingStrategy)
+	if err != nil {
+		return nil, err
+	}
+	t := &Table{Name: s.Table, Struct: s.Name}
+	for _, f := range s.Fields {
+		if f.DBName == "" {
+			continue
+		}
+		c := &Column{
+			Name:          f.DBName,
+			Field:         f.Name,
+			DBType:        string(f.DataType),
+			Comment:       f.Comment,
+			PrimaryKey:    f.PrimaryKey,
+			AutoIncrement: f.AutoIncrement,
+			Nullable:      !f.NotNull && !f.PrimaryKey,
+			GoType:        typeName(f.FieldType),
+		}
+		switch f.DataType {
+		case schema.String:
+			c.DBType, c.Size = "", int64(f.Size)
+		case schema.Bool, schema.Int, schema.Uint, schema.Float, schema.Time, schema.Bytes:
+			c.DBType = ""
+		}
+		t.Columns = append(t.Columns, c)
+	}
+	fill(t)
+	return t, t.check()
+}
+
+// typeName 模型字段的类型, 其他包的自定义类型使用其底层类型
+func typeName(t reflect.Type) string {
+	if t.PkgPath() == "" {
+		return t.String()
+	}
+	switch t.String() {
+	case "time.Time", "gorm.DeletedAt":
+		return t.String()
+	}
+	if t.Kind() == reflect.Struct {
+		return "string"
+	}
+	return t.Kind().String()
+}
+
+// fill 补全字段名和默认的查询方式
+func fill(t *Table) {
+	for _, c := range t.Columns {
+		if c.Field == "" {
+			c.Field = Camel(c.Name)
+		}
+		if c.JSON == "" {
+			c.JSON = lowerFirst(c.Field)
+			if c.GoType == "gorm.DeletedAt" {
+				c.JSON = "-"
+			}
+		}
+		if c.Search != "" || c.Auto() {
+			continue
+		}
+		switch c.GoType {
+		case "string":
+			c.Search = SearchContains
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "bool":
+			c.Search = SearchExact
+		}
+	}
+}
+
+// goType 数据库类型对应的 Go 类型
+func goType(name, dbType string) string {
+	if name == "deleted_at" {
+		return "gorm.DeletedAt"
+	}
+	switch dbType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "int2", "int4", "serial":
+		return "int"
+	case "bigint", "int8", "bigserial":
+		return "int64"
+	case "float", "real", "float4":
+		return "float32"
+	case "double", "decimal", "numeric", "float8", "double precision", "money":
+		return "float64"
+	case "bool", "boolean", "bit":
+		return "bool"
+	case "date", "datetime", "timestamp", "timestamptz", "time", "datetime2", "smalldatetime",
+		"timestamp without time zone", "timestamp with time zone":
+		return "time.Time"
+	case "blob", "longblob", "mediumblob", "binary", "varbinary", "bytea":
+		return "[]byte"
+	}
+	return "string"
+}
+
+// Camel 下划线转大驼峰, sys_user_id => SysUserId
+func Camel(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' '
+	})
+	var b strings.Builder
+	for _, p := range parts {
+		b.WriteString(strings.ToUpper(p[:1]))
+		b.WriteString(p[1:])
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,210 @@
+package i18n
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Message 一条文案, 按数量选择 Zero/One/Other, 没有对应形式时使用 Other;
+// 文案可使用 text/template 语法引用参数, 如 {{.Count}}
+type Message struct {
+	ID    string `json:"id,omitempty"`
+	Zero  string `json:"zero,omitempty"`
+	One   string `json:"one,omitempty"`
+	Other string `json:"other"`
+}
+
+// PluralFunc 返回数量 n 对应的复数形式: zero one other
+type PluralFunc func(n int) string
+
+// CountKey 模板参数中表示数量的键, 用于选择复数形式
+const CountKey = "Count"
+
+// Bundle 多语言文案集合
+type Bundle struct {
+	mux      sync.RWMutex
+	def      string
+	messages map[string]map[string]*Message
+	plurals  map[string]PluralFunc
+	tmpl     sync.Map
+}
+
+// NewBundle def 为默认语言, 请求语言缺少文案时回退到默认语言
+func NewBundle(def string) *Bundle {
+	return &Bundle{
+		def:      normalize(def),
+		messages: make(map[string]map[string]*Message),
+		plurals: map[string]PluralFunc{
+			"zh": pluralOther,
+			"ja": pluralOther,
+			"ko": pluralOther,
+		},
+	}
+}
+
+// Default 默认语言
+func (b *Bundle) Default() string {
+	return b.def
+}
+
+// AddMessages 添加文案, 同 id 覆盖
+func (b *Bundle) AddMessages(lang string, msgs ...*Message) {
+	lang = normalize(lang)
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	m, ok := b.messages[lang]
+	if !ok {
+		m = make(map[string]*Message, len(msgs))
+		b.messages[lang] = m
+	}
+	for _, msg := range msgs {
+		m[msg.ID] = msg
+	}
+}
+
+// RegisterPlural 设置语言的复数规则, 未设置时 1 为 one, 其余为 other
+func (b *Bundle) RegisterPlural(lang string, f PluralFunc) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.plurals[base(normalize(lang))] = f
+}
+
+// Languages 已加载文案的语言
+func (b *Bundle) Languages() []string {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	langs := make([]string, 0, len(b.messages))
+	for lang := range b.messages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Match 按顺序返回第一个支持的语言, 先完全匹配再按主语言匹配(en-US 可匹配 en),
+// 都不支持时返回默认语言
+func (b *Bundle) Match(langs ...string) string {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	for _, lang := range langs {
+		lang = normalize(lang)
+		if lang == "" {
+			continue
+		}
+		if _, ok := b.messages[lang]; ok {
+			return lang
+		}
+		l := base(lang)
+		if _, ok := b.messages[l]; ok {
+			return l
+		}
+		for supported := range b.messages {
+			if base(supported) == l {
+				return supported
+			}
+		}
+	}
+	return b.def
+}
+
+// Translate 翻译文案, 请求语言与默认语言都没有时返回空串
+func (b *Bundle) Translate(lang, id string, data map[string]interface{}) string {
+	msg, lang := b.lookup(normalize(lang), id)
+	if msg == nil {
+		return ""
+	}
+	text := msg.Other
+	if n, ok := count(data); ok {
+		switch form := b.plural(lang)(n); {
+		case (form == "zero" || n == 0) && msg.Zero != "":
+			text = msg.Zero
+		case form == "one" && msg.One != "":
+			text = msg.One
+		}
+	}
+	return b.render(text, data)
+}
+
+func (b *Bundle) lookup(lang, id string) (*Message, string) {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	for _, l := range []string{lang, base(lang), b.def} {
+		if msg, ok := b.messages[l][id]; ok {
+			return msg, l
+		}
+	}
+	return nil, ""
+}
+
+func (b *Bundle) plural(lang string) PluralFunc {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	if f, ok := b.plurals[base(lang)]; ok {
+		return f
+	}
+	return pluralOne
+}
+
+func (b *Bundle) render(text string, data map[string]interface{}) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+	var t *template.Template
+	if v, ok := b.tmpl.Load(text); ok {
+		t = v.(*template.Template)
+	} else {
+		var err error
+		t, err = template.New("").Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return text
+		}
+		b.tmpl.Store(text, t)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return text
+	}
+	return buf.String()
+}
+
+func pluralOne(n int) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+func pluralOther(int) string {
+	return "other"
+}
+
+func count(data map[string]interface{}) (int, bool) {
+	switch n := data[CountKey].(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case uint:
+		return int(n), true
+	case float64:
+		return int(n), true
+	}
+	return 0, false
+}
+
+// normalize 统一为小写并以 - 分隔, 如 zh_CN -> zh-cn
+func normalize(lang string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(lang)), "_", "-")
+}
+
+func base(lang string) string {
+	if i := strings.IndexByte(lang, '-'); i > 0 {
+		return lang[:i]
+	}
+	return lang
+}
@@ -0,0 +1,82 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+)
+
+func testBundle(t *testing.T) *Bundle {
+	b := NewBundle("zh-CN")
+	err := b.LoadFS(fstest.MapFS{
+		"zh-CN.json": {Data: []byte(`{"hello":"你好, {{.Name}}","items":"{{.Count}} 条数据","error.not_found":"数据不存在"}`)},
+		"en.yaml":    {Data: []byte("hello: Hello, {{.Name}}\nitems:\n  zero: no items\n  one: one item\n  other: '{{.Count}} items'\nerror.not_found: not found\n")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestTranslate(t *testing.T) {
+	b := testBundle(t)
+	tests := []struct {
+		lang, id string
+		data     map[string]interface{}
+		want     string
+	}{
+		{"zh-CN", "hello", map[string]interface{}{"Name": "admin"}, "你好, admin"},
+		{"en-US", "hello", map[string]interface{}{"Name": "admin"}, "Hello, admin"},
+		{"en", "items", map[string]interface{}{"Count": 0}, "no items"},
+		{"en", "items", map[string]interface{}{"Count": 1}, "one item"},
+		{"en", "items", map[string]interface{}{"Count": 3}, "3 items"},
+		{"zh-CN", "items", map[string]interface{}{"Count": 1}, "1 条数据"},
+		{"fr", "hello", map[string]interface{}{"Name": "admin"}, "你好, admin"},
+		{"en", "missing", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := b.Translate(tt.lang, tt.id, tt.data); got != tt.want {
+			t.Errorf("Translate(%s, %s) = %q, want %q", tt.lang, tt.id, got, tt.want)
+		}
+	}
+	if got := b.Match("fr", "en-GB"); got != "en" {
+		t.Errorf("Match = %s, want en", got)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	Setup(testBundle(t))
+	defer Setup(NewBundle("zh-CN"))
+
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/", func(c *gin.Context) {
+		if FromContext(c.Request.Context()) != Lang(c) {
+			t.Error("request context language mismatch")
+		}
+		response.Fail(c, response.ErrNotFound)
+	})
+	tests := []struct {
+		url, accept, want string
+	}{
+		{"/", "en-US,en;q=0.9", `"msg":"not found"`},
+		{"/?lang=zh-CN", "en", `"msg":"数据不存在"`},
+		{"/", "", `"msg":"数据不存在"`},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		req.Header.Set("Accept-Language", tt.accept)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if !strings.Contains(w.Body.String(), tt.want) {
+			t.Errorf("%s %s: body = %s, want %s", tt.url, tt.accept, w.Body.String(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"gorm.io/gorm"
+)
+
+// LoadFS 加载文案文件, 支持 json/yaml, 文件名(不含扩展名)为语言, 如 zh-CN.yaml;
+// 值为字符串或 {zero, one, other} 对象, 可配合 embed.FS 使用
+func (b *Bundle) LoadFS(fsys fs.FS, patterns ...string) error {
+	if len(patterns) == 0 {
+		patterns = []string{"*.json", "*.yaml", "*.yml"}
+	}
+	for _, pattern := range patterns {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return err
+			}
+			ext := path.Ext(file)
+			if ext == ".yaml" || ext == ".yml" {
+				if data, err = yaml.YAMLToJSON(data); err != nil {
+					return fmt.Errorf("i18n: %s: %w", file, err)
+				}
+			}
+			msgs, err := parse(data)
+			if err != nil {
+				return fmt.Errorf("i18n: %s: %w", file, err)
+			}
+			b.AddMessages(strings.TrimSuffix(path.Base(file), ext), msgs...)
+		}
+	}
+	return nil
+}
+
+func parse(data []byte) ([]*Message, error) {
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	msgs := make([]*Message, 0, len(raw))
+	for id, v := range raw {
+		msg := &Message{ID: id}
+		if err := json.Unmarshal(v, &msg.Other); err != nil {
+			if err = json.Unmarshal(v, msg); err != nil {
+				return nil, fmt.Errorf("message %s: %w", id, err)
+			}
+			msg.ID = id
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// Record 数据库中的文案, 可在后台维护, 修改后重新调用 LoadDB 生效
+type Record struct {
+	ID    int    `json:"id" gorm:"primaryKey;autoIncrement"`
+	Lang  string `json:"lang" gorm:"size:16;uniqueIndex:idx_i18n_lang_key"`
+	Key   string `json:"key" gorm:"size:128;uniqueIndex:idx_i18n_lang_key"`
+	Zero  string `json:"zero" gorm:"size:512"`
+	One   string `json:"one" gorm:"size:512"`
+	Other string `json:"other" gorm:"size:512"`
+}
+
+func (Record) TableName() string {
+	return "sys_i18n"
+}
+
+// LoadDB 从数据库加载文案, 覆盖同语言同 id 的文件文案
+func (b *Bundle) LoadDB(db *gorm.DB) error {
+	var list []Record
+	if err := db.Find(&list).Error; err != nil {
+		return err
+	}
+	for i := range list {
+		b.AddMessages(list[i].Lang, &Message{
+			ID:    list[i].Key,
+			Zero:  list[i].Zero,
+			One:   list[i].One,
+			Other: list[i].Other,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package i18n
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+	"github.com/go-admin-team/go-admin-core/tools/language"
+)
+
+// LangKey gin.Context 中保存请求语言的键
+const LangKey = "i18n-lang"
+
+type langKey struct{}
+
+var (
+	mux           sync.RWMutex
+	defaultBundle = NewBundle("zh-CN")
+)
+
+// Setup 设置默认文案集合, 并让 response 的错误文案按请求语言翻译
+func Setup(b *Bundle) {
+	mux.Lock()
+	defaultBundle = b
+	mux.Unlock()
+	response.SetTranslator(func(c *gin.Context, id string, data map[string]interface{}) string {
+		return b.Translate(Lang(c), id, data)
+	})
+}
+
+// Default 默认文案集合
+func Default() *Bundle {
+	mux.RLock()
+	defer mux.RUnlock()
+	return defaultBundle
+}
+
+// WithLang 在 ctx 中保存语言, 供 service 等非 http 层使用
+func WithLang(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, langKey{}, lang)
+}
+
+// FromContext 获取 ctx 中的语言, 没有时返回默认语言
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(langKey{}).(string); ok && lang != "" {
+		return lang
+	}
+	return Default().Default()
+}
+
+// Lang 获取请求语言, 未经过 Middleware 时按 Accept-Language 协商
+func Lang(c *gin.Context) string {
+	if c == nil {
+		return Default().Default()
+	}
+	if lang := c.GetString(LangKey); lang != "" {
+		return lang
+	}
+	return Default().Match(language.ParseAcceptLanguage(c.GetHeader("Accept-Language"), nil)...)
+}
+
+// T 按请求语言翻译, 找不到时返回 id
+func T(c *gin.Context, id string, data map[string]interface{}) string {
+	if s := Default().Translate(Lang(c), id, data); s != "" {
+		return s
+	}
+	return id
+}
+
+// TCtx 按 ctx 中的语言翻译, 找不到时返回 id
+func TCtx(ctx context.Context, id string, data map[string]interface{}) string {
+	if s := Default().Translate(FromContext(ctx), id, data); s != "" {
+		return s
+	}
+	return id
+}
+
+type Option func(*options)
+
+type options struct {
+	query  string
+	header string
+	cookie string
+}
+
+func setDefault() options {
+	return options{
+		query:  "lang",
+		header: "X-Language",
+	}
+}
+
+// WithQuery 指定语言的查询参数, 默认 lang, 空串为不使用
+func WithQuery(name string) Option {
+	return func(o *options) {
+		o.query = name
+	}
+}
+
+// WithHeader 指定语言的请求头, 默认 X-Language, 空串为不使用
+func WithHeader(name string) Option {
+	return func(o *options) {
+		o.header = name
+	}
+}
+
+// WithCookie 指定语言的cookie, 默认不使用
+func WithCookie(name string) Option {
+	return func(o *options) {
+		o.cookie = name
+	}
+}
+
+// Middleware 协商请求语言, 依次取查询参数、请求头、cookie、Accept-Language,
+// 结果保存到 gin.Context 与 request context 中
+func Middleware(opts ...Option) gin.HandlerFunc {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(c *gin.Context) {
+		candidates := make([]string, 0, 4)
+		if o.query != "" {
+			candidates = append(candidates, c.Query(o.query))
+		}
+		if o.header != "" {
+			candidates = append(candidates, c.GetHeader(o.header))
+		}
+		if o.cookie != "" {
+			if v, err := c.Cookie(o.cookie); err == nil {
+				candidates = append(candidates, v)
+			}
+		}
+		candidates = append(candidates, language.ParseAcceptLanguage(c.GetHeader("Accept-Language"), nil)...)
+		lang := Default().Match(candidates...)
+		c.Set(LangKey, lang)
+		c.Request = c.Request.WithContext(WithLang(c.Request.Context(), lang))
+		c.Header("Content-Language", lang)
+		c.Next()
+	}
+}
@@ -0,0 +1,194 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsm/redislock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSnowflake(t *testing.T) {
+	s := NewSnowflake(WithWorkerID(5))
+	var prev int64
+	for i := 0; i < 10000; i++ {
+		id, err := s.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= prev {
+			t.Fatalf("id %d not greater than %d", id, prev)
+		}
+		prev = id
+	}
+	if w := prev >> seqBits & MaxWorkerID; w != 5 {
+		t.Errorf("worker id = %d", w)
+	}
+	if d := time.Since(s.Time(prev)); d < 0 || d > time.Second {
+		t.Errorf("time = %v", s.Time(prev))
+	}
+
+	s.last += 1000
+	if _, err := s.Next(); !errors.Is(err, ErrClockBackwards) {
+		t.Errorf("err = %v", err)
+	}
+	if err := NewSnowflake(WithWorkerID(MaxWorkerID + 1)).Start(context.Background()); err == nil {
+		t.Error("worker id out of range should fail")
+	}
+}
+
+// fakeLeases 模拟分布式锁
+type fakeLeases struct {
+	mux  sync.Mutex
+	held map[string]bool
+	fail bool
+}
+
+type fakeLease struct {
+	f   *fakeLeases
+	key string
+}
+
+func (l *fakeLease) refresh(context.Context) error {
+	l.f.mux.Lock()
+	defer l.f.mux.Unlock()
+	if l.f.fail {
+		return redislock.ErrNotObtained
+	}
+	return nil
+}
+
+func (l *fakeLease) release(context.Context) error {
+	l.f.mux.Lock()
+	defer l.f.mux.Unlock()
+	delete(l.f.held, l.key)
+	return nil
+}
+
+func (f *fakeLeases) obtain(key string, _ time.Duration) (lease, error) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	if f.held[key] || f.fail {
+		return nil, redislock.ErrNotObtained
+	}
+	f.held[key] = true
+	return &fakeLease{f: f, key: key}, nil
+}
+
+func newLeased(f *fakeLeases, opts ...Option) *Snowflake {
+	s := NewSnowflake(opts...)
+	s.workerID = -1
+	s.obtain = f.obtain
+	return s
+}
+
+func TestWorkerAllocation(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeLeases{held: make(map[string]bool)}
+	seen := make(map[int64]bool)
+	var list []*Snowflake
+	for i := 0; i < 20; i++ {
+		s := newLeased(f)
+		if _, err := s.Next(); !errors.Is(err, ErrNoWorkerID) {
+			t.Fatalf("before start: err = %v", err)
+		}
+		if err := s.Start(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if seen[s.WorkerID()] {
+			t.Fatalf("duplicate worker id %d", s.WorkerID())
+		}
+		seen[s.WorkerID()] = true
+		list = append(list, s)
+	}
+	for _, s := range list {
+		if err := s.Stop(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(f.held) != 0 {
+		t.Errorf("held = %v", f.held)
+	}
+
+	// 续期失败后停止发号
+	s := newLeased(f, WithLeaseTTL(30*time.Millisecond))
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	f.mux.Lock()
+	f.fail = true
+	f.mux.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	if _, err := s.Next(); !errors.Is(err, ErrNoWorkerID) {
+		t.Errorf("lease lost: err = %v", err)
+	}
+	_ = s.Stop(ctx)
+}
+
+func TestULID(t *testing.T) {
+	re := regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`)
+	var list []string
+	for i := 0; i < 3; i++ {
+		id := ULID()
+		if !re.MatchString(id) {
+			t.Fatalf("ulid = %s", id)
+		}
+		list = append(list, id)
+		time.Sleep(2 * time.Millisecond)
+	}
+	if !sort.StringsAreSorted(list) {
+		t.Errorf("ulid not sorted: %v", list)
+	}
+
+	re = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	a := UUIDv7()
+	time.Sleep(2 * time.Millisecond)
+	b := UUIDv7()
+	if !re.MatchString(a) || !re.MatchString(b) || a >= b {
+		t.Errorf("uuidv7 = %s, %s", a, b)
+	}
+}
+
+type order struct {
+	Id   int64  `gorm:"primaryKey;autoIncrement:false" idgen:"snowflake"`
+	Code string `idgen:"ulid"`
+	Ref  string `idgen:"uuidv7"`
+}
+
+func TestPlugin(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.Use(Plugin{Snowflake: NewSnowflake(WithWorkerID(1))}); err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&order{}); err != nil {
+		t.Fatal(err)
+	}
+	one := order{Code: "fixed"}
+	list := []*order{{}, {}}
+	if err = db.Create(&one).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err = db.Create(&list).Error; err != nil {
+		t.Fatal(err)
+	}
+	if one.Id == 0 || one.Code != "fixed" || one.Ref == "" {
+		t.Errorf("one = %+v", one)
+	}
+	if list[0].Id == 0 || list[0].Id == list[1].Id || len(list[1].Code) != 26 {
+		t.Errorf("list = %+v, %+v", list[0], list[1])
+	}
+	var count int64
+	db.Model(&order{}).Count(&count)
+	if count != 3 {
+		t.Errorf("count = %d", count)
+	}
+}
@@ -0,0 +1,88 @@
+package idgen
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Tag 字段标签, 取值 snowflake、ulid、uuidv7
+const Tag = "idgen"
+
+// Plugin 创建记录前为带 idgen 标签且为零值的字段生成id
+//
+//	db.Use(idgen.Plugin{})
+//	type Order struct {
+//		Id   int64  `gorm:"primaryKey;autoIncrement:false" idgen:"snowflake"`
+//		Code string `gorm:"size:26" idgen:"ulid"`
+//	}
+type Plugin struct {
+	// Snowflake 为nil时使用 Default()
+	Snowflake *Snowflake
+}
+
+func (Plugin) Name() string {
+	return "idgen"
+}
+
+func (p Plugin) Initialize(db *gorm.DB) error {
+	return db.Callback().Create().Before("gorm:create").Register("idgen:assign", p.assign)
+}
+
+func (p Plugin) assign(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	var fields []*schema.Field
+	for _, f := range db.Statement.Schema.Fields {
+		if f.Tag.Get(Tag) != "" {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			p.set(db, fields, reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		p.set(db, fields, rv)
+	}
+}
+
+func (p Plugin) set(db *gorm.DB, fields []*schema.Field, rv reflect.Value) {
+	ctx := db.Statement.Context
+	for _, f := range fields {
+		if _, zero := f.ValueOf(ctx, rv); !zero {
+			continue
+		}
+		var v interface{}
+		switch f.Tag.Get(Tag) {
+		case "snowflake":
+			s := p.Snowflake
+			if s == nil {
+				s = Default()
+			}
+			id, err := s.Next()
+			if err != nil {
+				_ = db.AddError(err)
+				return
+			}
+			v = id
+		case "ulid":
+			v = ULID()
+		case "uuidv7":
+			v = UUIDv7()
+		default:
+			continue
+		}
+		if err := f.Set(ctx, rv, v); err != nil {
+			_ = db.AddError(err)
+			return
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bsm/redislock"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+const (
+	workerBits = 10
+	seqBits    = 12
+	// MaxWorkerID 可用的最大机器id
+	MaxWorkerID = 1<<workerBits - 1
+	maxSeq      = 1<<seqBits - 1
+)
+
+var (
+	// ErrNoWorkerID 未分配到机器id, 或续期失败后机器id已失效
+	ErrNoWorkerID = errors.New("idgen: worker id not available")
+	// ErrClockBackwards 时钟回拨超过允许范围
+	ErrClockBackwards = errors.New("idgen: clock moved backwards")
+)
+
+type Option func(*options)
+
+type options struct {
+	workerID     int64
+	epoch        time.Time
+	locker       storage.AdapterLocker
+	prefix       string
+	ttl          time.Duration
+	maxBackwards time.Duration
+}
+
+func setDefault() options {
+	return options{
+		workerID:     -1,
+		epoch:        time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		prefix:       "idgen:worker:",
+		ttl:          time.Minute,
+		maxBackwards: 10 * time.Millisecond,
+	}
+}
+
+// WithWorkerID 固定机器id, 0~1023, 多副本部署时各副本需不同; 设置后不再通过 locker 分配
+func WithWorkerID(id int64) Option {
+	return func(o *options) {
+		o.workerID = id
+	}
+}
+
+// WithEpoch 起始时间, 41位毫秒时间戳可使用约69年, 已有数据后不能修改, 默认 2020-01-01
+func WithEpoch(t time.Time) Option {
+	return func(o *options) {
+		o.epoch = t
+	}
+}
+
+// WithLocker 启动时通过分布式锁抢占空闲的机器id并定期续期, 避免多副本id冲突
+func WithLocker(l storage.AdapterLocker) Option {
+	return func(o *options) {
+		o.locker = l
+	}
+}
+
+// WithKeyPrefix 机器id锁的key前缀, 默认 idgen:worker:
+func WithKeyPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithLeaseTTL 机器id锁的有效期, 每 ttl/3 续期一次, 默认1分钟
+func WithLeaseTTL(d time.Duration) Option {
+	return func(o *options) {
+		o.ttl = d
+	}
+}
+
+// WithMaxBackwards 允许等待的时钟回拨, 超出时返回 ErrClockBackwards, 默认10ms
+func WithMaxBackwards(d time.Duration) Option {
+	return func(o *options) {
+		o.maxBackwards = d
+	}
+}
+
+// lease 机器id的租约
+type lease interface {
+	refresh(ctx context.Context) error
+	release(ctx context.Context) error
+}
+
+type obtainFunc func(key string, ttl time.Duration) (lease, error)
+
+type lockLease struct {
+	lock *redislock.Lock
+	ttl  time.Duration
+}
+
+func (l *lockLease) refresh(ctx context.Context) error {
+	return l.lock.Refresh(ctx, l.ttl, nil)
+}
+
+func (l *lockLease) release(ctx context.Context) error {
+	return l.lock.Release(ctx)
+}
+
+// Snowflake 64位有序id: 1位符号 + 41位毫秒时间戳 + 10位机器id + 12位序号, 每毫秒每个机器最多4096个;
+// 实现了 runtime.Component, 使用 locker 时需要先 Start
+type Snowflake struct {
+	o        options
+	obtain   obtainFunc
+	mux      sync.Mutex
+	workerID int64
+	last     int64
+	seq      int64
+	lease    lease
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewSnowflake 未设置 WithWorkerID 与 WithLocker 时机器id为0
+func NewSnowflake(opts ...Option) *Snowflake {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	s := &Snowflake{o: o, workerID: o.workerID}
+	if o.locker != nil && o.workerID < 0 {
+		s.obtain = func(key string, ttl time.Duration) (lease, error) {
+			l, err := o.locker.Lock(key, int64(ttl/time.Second), nil)
+			if err != nil {
+				return nil, err
+			}
+			return &lockLease{lock: l, ttl: ttl}, nil
+		}
+	} else if s.workerID < 0 {
+		s.workerID = 0
+	}
+	return s
+}
+
+func (s *Snowflake) String() string {
+	return "idgen"
+}
+
+// WorkerID 当前的机器id, 未分配时为-1
+func (s *Snowflake) WorkerID() int64 {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.workerID
+}
+
+// Start 使用 locker 时抢占机器id, 从随机位置开始依次尝试
+func (s *Snowflake) Start(ctx context.Context) error {
+	if s.obtain == nil {
+		if s.workerID > MaxWorkerID {
+			return fmt.Errorf("idgen: worker id %d out of range", s.workerID)
+		}
+		return nil
+	}
+	start := rand.Int63n(MaxWorkerID + 1)
+	for i := int64(0); i <= MaxWorkerID; i++ {
+		id := (start + i) % (MaxWorkerID + 1)
+		l, err := s.obtain(s.key(id), s.o.ttl)
+		if errors.Is(err, redislock.ErrNotObtained) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		s.mux.Lock()
+		s.workerID, s.lease = id, l
+		s.mux.Unlock()
+		ctx, s.cancel = context.WithCancel(context.Background())
+		s.done = make(chan struct{})
+		go s.keepalive(ctx, id)
+		logger.Module("sdk.idgen").Info("worker id obtained", "worker_id", id)
+		return nil
+	}
+	return ErrNoWorkerID
+}
+
+// keepalive 续期失败时暂停发号并尝试重新抢占同一id, 避免与其他副本冲突
+func (s *Snowflake) keepalive(ctx context.Context, id int64) {
+	defer close(s.done)
+	log := logger.Module("sdk.idgen").With("worker_id", id)
+	ticker := time.NewTicker(s.o.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.mux.Lock()
+		l := s.lease
+		s.mux.Unlock()
+		if l != nil && l.refresh(ctx) == nil {
+			continue
+		}
+		l, err := s.obtain(s.key(id), s.o.ttl)
+		s.mux.Lock()
+		if err != nil {
+			s.workerID, s.lease = -1, nil
+		} else {
+			s.workerID, s.lease = id, l
+		}
+		s.mux.Unlock()
+		if err != nil {
+			log.Error("worker id lease lost", "error", err)
+		}
+	}
+}
+
+// Stop 停止续期并释放机器id
+func (s *Snowflake) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+	<-s.done
+	s.mux.Lock()
+	l := s.lease
+	s.workerID, s.lease = -1, nil
+	s.mux.Unlock()
+	if l != nil {
+		return l.release(ctx)
+	}
+	return nil
+}
+
+func (s *Snowflake) key(id int64) string {
+	return s.o.prefix + strconv.FormatInt(id, 10)
+}
+
+func (s *Snowflake) now() int64 {
+	return time.Since(s.o.epoch).Milliseconds()
+}
+
+// Next 生成id
+func (s *Snowflake) Next() (int64, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.workerID < 0 {
+		return 0, ErrNoWorkerID
+	}
+	now := s.now()
+	if now < s.last {
+		if time.Duration(s.last-now)*time.Millisecond > s.o.maxBackwards {
+			return 0, ErrClockBackwards
+		}
+		for now < s.last {
+			time.Sleep(time.Duration(s.last-now) * time.Millisecond)
+			now = s.now()
+		}
+	}
+	if now == s.last {
+		s.seq = (s.seq + 1) & maxSeq
+		if s.seq == 0 {
+			// 当前毫秒的序号已用完
+			for now <= s.last {
+				time.Sleep(100 * time.Microsecond)
+				now = s.now()
+			}
+		}
+	} else {
+		s.seq = 0
+	}
+	s.last = now
+	return now<<(workerBits+seqBits) | s.workerID<<seqBits | s.seq, nil
+}
+
+// Time 解析id中的生成时间
+func (s *Snowflake) Time(id int64) time.Time {
+	return s.o.epoch.Add(time.Duration(id>>(workerBits+seqBits)) * time.Millisecond)
+}
+
+var (
+	stdMux sync.RWMutex
+	std    = NewSnowflake()
+)
+
+// SetDefault 设置默认的生成器
+func SetDefault(s *Snowflake) {
+	stdMux.Lock()
+	defer stdMux.Unlock()
+	std = s
+}
+
+// Default 默认的生成器, 未设置时机器id为0
+func Default() *Snowflake {
+	stdMux.RLock()
+	defer stdMux.RUnlock()
+	return std
+}
+
+// NextID 使用默认生成器生成id
+func NextID() (int64, error) {
+	return Default().Next()
+}
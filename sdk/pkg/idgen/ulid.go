@@ -0,0 +1,47 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID 26位字符串, 48位毫秒时间戳 + 80位随机数, 按字符串排序即按时间排序, 同一毫秒内无序
+func ULID() string {
+	var b [16]byte
+	putMillis(b[:], time.Now())
+	_, _ = rand.Read(b[6:])
+	// 128位前补2个0后按5位一组使用 Crockford Base32 编码
+	var dst [26]byte
+	for i := range dst {
+		var v byte
+		for j := 0; j < 5; j++ {
+			v <<= 1
+			if p := i*5 - 2 + j; p >= 0 && b[p/8]>>(7-p%8)&1 == 1 {
+				v |= 1
+			}
+		}
+		dst[i] = crockford[v]
+	}
+	return string(dst[:])
+}
+
+// UUIDv7 RFC 9562 的 UUID version 7, 前48位为毫秒时间戳, 适合作为数据库主键或链路id
+func UUIDv7() string {
+	var u uuid.UUID
+	putMillis(u[:], time.Now())
+	_, _ = rand.Read(u[6:])
+	u[6] = u[6]&0x0f | 0x70
+	u[8] = u[8]&0x3f | 0x80
+	return u.String()
+}
+
+func putMillis(b []byte, t time.Time) {
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(t.UnixMilli()))
+	copy(b[:6], ms[2:])
+}
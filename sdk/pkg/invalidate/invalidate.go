@@ -0,0 +1,177 @@
+// Package invalidate 按命名空间失效缓存: 命名空间内的key带版本号, 失效时更换版本, 不需要逐个删除;
+// 注册为 gorm 插件后, 表有新增、修改、删除时自动失效对应的命名空间, 并通过队列通知其他实例
+//
+//	inv := invalidate.New(cache, invalidate.WithTable("sys_menu", "menu"),
+//		invalidate.WithTable("sys_role_menu", "menu", "role"), invalidate.WithQueue(q, ""))
+//	_ = db.Use(inv.Plugin())
+//	menus := inv.Namespace("menu") // 与普通缓存用法相同
+//	_ = menus.Set("tree:1", tree, 3600)
+package invalidate
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+// DefaultStream 失效通知的队列
+const DefaultStream = "cache.invalidate"
+
+// versionTTL 命名空间版本的保存时间, 需大于命名空间内key的缓存时间
+const versionTTL = 30 * 24 * 3600
+
+type Option func(*options)
+
+type options struct {
+	prefix string
+	tables map[string][]string
+	queue  storage.AdapterQueue
+	stream string
+}
+
+func setDefault() options {
+	return options{
+		prefix: "ns:",
+		tables: make(map[string][]string),
+		stream: DefaultStream,
+	}
+}
+
+// WithPrefix 命名空间key的前缀, 默认 ns:
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithTable 表有写操作时失效的命名空间, 可多次调用
+func WithTable(table string, namespaces ...string) Option {
+	return func(o *options) {
+		o.tables[table] = append(o.tables[table], namespaces...)
+	}
+}
+
+// WithQueue 失效后投递通知, 其他实例收到后执行 OnInvalidate 注册的方法, 用于清理进程内缓存; stream 为空时使用 DefaultStream;
+// 多个实例竞争消费同一队列时只有一个实例收到, 需使用广播的队列
+func WithQueue(q storage.AdapterQueue, stream string) Option {
+	return func(o *options) {
+		o.queue = q
+		if stream != "" {
+			o.stream = stream
+		}
+	}
+}
+
+// Invalidator 命名空间失效
+type Invalidator struct {
+	cache     storage.AdapterCache
+	o         options
+	mux       sync.RWMutex
+	listeners map[string][]func(ctx context.Context)
+}
+
+// New 设置 WithQueue 时注册通知的消费者
+func New(cache storage.AdapterCache, opts ...Option) *Invalidator {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	i := &Invalidator{cache: cache, o: o, listeners: make(map[string][]func(ctx context.Context))}
+	if o.queue != nil {
+		o.queue.Register(o.stream, i.consume)
+	}
+	return i
+}
+
+// Namespace 命名空间内的缓存, key 自动带上命名空间与当前版本
+func (i *Invalidator) Namespace(name string) storage.AdapterCache {
+	return &namespace{i: i, name: name}
+}
+
+// OnInvalidate 命名空间失效时执行, 包括其他实例的失效通知
+func (i *Invalidator) OnInvalidate(name string, f func(ctx context.Context)) {
+	i.mux.Lock()
+	defer i.mux.Unlock()
+	i.listeners[name] = append(i.listeners[name], f)
+}
+
+// Invalidate 失效命名空间并通知其他实例
+func (i *Invalidator) Invalidate(ctx context.Context, namespaces ...string) error {
+	if err := i.invalidate(ctx, namespaces); err != nil {
+		return err
+	}
+	if i.o.queue == nil {
+		return nil
+	}
+	m := new(queue.Message)
+	m.SetStream(i.o.stream)
+	m.SetValues(map[string]interface{}{"namespaces": strings.Join(namespaces, ",")})
+	queue.InjectTrace(ctx, m)
+	return i.o.queue.Append(m)
+}
+
+// Tables 表对应的命名空间, 已去重排序
+func (i *Invalidator) Tables(tables ...string) []string {
+	seen := make(map[string]bool)
+	var list []string
+	for _, t := range tables {
+		for _, ns := range i.o.tables[t] {
+			if !seen[ns] {
+				seen[ns] = true
+				list = append(list, ns)
+			}
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+func (i *Invalidator) invalidate(ctx context.Context, namespaces []string) error {
+	version := strconv.FormatInt(time.Now().UnixNano(), 36)
+	for _, ns := range namespaces {
+		if err := i.cache.Set(i.versionKey(ns), version, versionTTL); err != nil {
+			return err
+		}
+	}
+	logger.Module("sdk.invalidate").WithContext(ctx).Info("cache invalidated", "namespaces", namespaces)
+	i.notify(ctx, namespaces)
+	return nil
+}
+
+func (i *Invalidator) notify(ctx context.Context, namespaces []string) {
+	i.mux.RLock()
+	var fs []func(ctx context.Context)
+	for _, ns := range namespaces {
+		fs = append(fs, i.listeners[ns]...)
+	}
+	i.mux.RUnlock()
+	for _, f := range fs {
+		f(ctx)
+	}
+}
+
+// consume 其他实例的通知, 版本已更换, 只需执行本地的清理
+func (i *Invalidator) consume(m storage.Messager) error {
+	s, _ := m.GetValues()["namespaces"].(string)
+	if s == "" {
+		return nil
+	}
+	i.notify(queue.TraceContext(m), strings.Split(s, ","))
+	return nil
+}
+
+func (i *Invalidator) versionKey(ns string) string {
+	return i.o.prefix + ns + ":version"
+}
+
+func (i *Invalidator) keyPrefix(ns string) string {
+	version, _ := i.cache.Get(i.versionKey(ns))
+	return i.o.prefix + ns + ":" + version + ":"
+}
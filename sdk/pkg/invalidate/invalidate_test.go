@@ -0,0 +1,70 @@
+package invalidate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/txn"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+type menu struct {
+	ID   int
+	Name string
+}
+
+func (menu) TableName() string {
+	return "sys_menu"
+}
+
+func TestPlugin(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&menu{}); err != nil {
+		t.Fatal(err)
+	}
+	inv := New(cache.NewMemory(), WithTable("sys_menu", "menu", "role"))
+	if err = db.Use(inv.Plugin()); err != nil {
+		t.Fatal(err)
+	}
+	var fired []string
+	inv.OnInvalidate("role", func(context.Context) { fired = append(fired, "role") })
+	menus := inv.Namespace("menu")
+	ctx := context.Background()
+
+	_ = menus.Set("tree", "v1", 3600)
+	if v, _ := menus.Get("tree"); v != "v1" {
+		t.Fatalf("get = %q", v)
+	}
+	db.WithContext(ctx).Create(&menu{ID: 1, Name: "系统管理"})
+	if v, _ := menus.Get("tree"); v != "" || len(fired) != 1 {
+		t.Fatalf("create should invalidate, get = %q, fired = %v", v, fired)
+	}
+
+	_ = menus.Set("tree", "v2", 3600)
+	_ = txn.Do(ctx, db, func(ctx context.Context) error {
+		txn.DB(ctx, db).Model(&menu{ID: 1}).Update("name", "系统")
+		if v, _ := menus.Get("tree"); v != "v2" {
+			t.Error("should not invalidate before commit")
+		}
+		return errors.New("rollback")
+	})
+	if v, _ := menus.Get("tree"); v != "v2" || len(fired) != 1 {
+		t.Errorf("rollback should not invalidate, get = %q", v)
+	}
+	db.WithContext(ctx).Where("id = ?", 2).Delete(&menu{})
+	if len(fired) != 1 {
+		t.Error("no rows affected should not invalidate")
+	}
+	db.WithContext(ctx).Delete(&menu{ID: 1})
+	if v, _ := menus.Get("tree"); v != "" || len(fired) != 2 {
+		t.Errorf("delete should invalidate, get = %q", v)
+	}
+}
@@ -0,0 +1,55 @@
+package invalidate
+
+import (
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// namespace 命名空间内的缓存, 每次操作读取一次当前版本
+type namespace struct {
+	i    *Invalidator
+	name string
+}
+
+var _ storage.AdapterCache = (*namespace)(nil)
+
+func (n *namespace) String() string {
+	return n.i.cache.String()
+}
+
+func (n *namespace) key(key string) string {
+	return n.i.keyPrefix(n.name) + key
+}
+
+func (n *namespace) Get(key string) (string, error) {
+	return n.i.cache.Get(n.key(key))
+}
+
+func (n *namespace) Set(key string, val interface{}, expire int) error {
+	return n.i.cache.Set(n.key(key), val, expire)
+}
+
+func (n *namespace) Del(key string) error {
+	return n.i.cache.Del(n.key(key))
+}
+
+func (n *namespace) HashGet(hk, key string) (string, error) {
+	return n.i.cache.HashGet(n.key(hk), key)
+}
+
+func (n *namespace) HashDel(hk, key string) error {
+	return n.i.cache.HashDel(n.key(hk), key)
+}
+
+func (n *namespace) Increase(key string) error {
+	return n.i.cache.Increase(n.key(key))
+}
+
+func (n *namespace) Decrease(key string) error {
+	return n.i.cache.Decrease(n.key(key))
+}
+
+func (n *namespace) Expire(key string, dur time.Duration) error {
+	return n.i.cache.Expire(n.key(key), dur)
+}
@@ -0,0 +1,53 @@
+package invalidate
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/txn"
+)
+
+// Plugin 表有新增、修改、删除时失效 WithTable 设置的命名空间; 在 txn.Do 的事务中时提交后再失效, 回滚时不失效;
+// db.Exec 执行的原生sql不经过这些回调, 需自行调用 Invalidate
+//
+//	db.Use(inv.Plugin())
+func (i *Invalidator) Plugin() gorm.Plugin {
+	return &plugin{i: i}
+}
+
+type plugin struct {
+	i *Invalidator
+}
+
+func (*plugin) Name() string {
+	return "invalidate"
+}
+
+func (p *plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().After("gorm:create").Register("invalidate:create", p.after); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("invalidate:update", p.after); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:delete").Register("invalidate:delete", p.after)
+}
+
+func (p *plugin) after(db *gorm.DB) {
+	if db.Error != nil || db.RowsAffected == 0 {
+		return
+	}
+	table := db.Statement.Table
+	namespaces := p.i.Tables(table)
+	if len(namespaces) == 0 {
+		return
+	}
+	ctx := db.Statement.Context
+	txn.AfterCommit(ctx, func() {
+		if err := p.i.Invalidate(ctx, namespaces...); err != nil {
+			logger.Module("sdk.invalidate").WithContext(ctx).Error("invalidate failed",
+				"table", table, "namespaces", namespaces, "error", err)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package jwtauth
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -11,6 +12,21 @@ import (
 // MapClaims represents a jwt.MapClaims
 type MapClaims jwt.MapClaims
 
+type claimsKey struct{}
+
+// WithClaims 将claims写入ctx, 中间件校验通过后自动写入请求ctx
+func WithClaims(ctx context.Context, claims MapClaims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext 从ctx中获取claims, 不存在时返回空的claims
+func ClaimsFromContext(ctx context.Context) MapClaims {
+	if claims, ok := ctx.Value(claimsKey{}).(MapClaims); ok {
+		return claims
+	}
+	return make(MapClaims)
+}
+
 // Exp returns value of exp
 func (m MapClaims) Exp() (int64, error) {
 	return m.Int64("exp")
@@ -0,0 +1,37 @@
+package jwtauth
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/server/grpc/interceptors/auth"
+)
+
+// GrpcAuthFunc 使用同一套密钥校验 gRPC 请求的 token, 校验通过后 claims 与操作人id写入 ctx
+func (mw *GinJWTMiddleware) GrpcAuthFunc() auth.AuthFunc {
+	return func(ctx context.Context, token string) (context.Context, error) {
+		t, err := mw.ParseTokenString(token)
+		if err != nil {
+			return nil, err
+		}
+		claims := MapClaims(t.Claims.(jwt.MapClaims))
+		exp, err := claims.Exp()
+		if err != nil {
+			return nil, err
+		}
+		if exp < mw.TimeFunc().Unix() {
+			return nil, ErrExpiredToken
+		}
+		if mw.IsRevoked(claims) {
+			return nil, ErrRevokedToken
+		}
+		ctx = WithClaims(ctx, claims)
+		if id, err := claims.Identity(); err == nil {
+			ctx = logger.WithOperatorID(ctx, strconv.FormatInt(id, 10))
+		}
+		return ctx, nil
+	}
+}
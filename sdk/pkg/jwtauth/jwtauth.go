@@ -1,15 +1,21 @@
 package jwtauth
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"errors"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
 )
 
 const JwtPayloadKey = "JWT_PAYLOAD"
@@ -23,7 +29,8 @@ type GinJWTMiddleware struct {
 	// Realm name to display to the user. Required.
 	Realm string
 
-	// signing algorithm - possible values are HS256, HS384, HS512
+	// signing algorithm - possible values are HS256, HS384, HS512, RS256, RS384, RS512,
+	// PS256, PS384, PS512, ES256, ES384, ES512
 	// Optional, default is HS256.
 	SigningAlgorithm string
 
@@ -114,10 +121,18 @@ type GinJWTMiddleware struct {
 	PubKeyFile string
 
 	// Private key
-	privKey *rsa.PrivateKey
+	privKey crypto.PrivateKey
 
 	// Public key
-	pubKey *rsa.PublicKey
+	pubKey crypto.PublicKey
+
+	// Cache 存放吊销列表, 为空时不支持吊销
+	Cache storage.AdapterCache
+
+	// 通过 AddKey 添加的轮换密钥, 按 kid 校验
+	keyMux sync.RWMutex
+	keys   map[string]*Key
+	active *Key
 
 	// Optionally return the token as a cookie
 	SendCookie bool
@@ -252,7 +267,7 @@ func (mw *GinJWTMiddleware) privateKey() error {
 	if err != nil {
 		return ErrNoPrivKeyFile
 	}
-	key, err := jwt.ParseRSAPrivateKeyFromPEM(keyData)
+	key, err := parsePrivateKey(mw.SigningAlgorithm, keyData)
 	if err != nil {
 		return ErrInvalidPrivKey
 	}
@@ -265,7 +280,7 @@ func (mw *GinJWTMiddleware) publicKey() error {
 	if err != nil {
 		return ErrNoPubKeyFile
 	}
-	key, err := jwt.ParseRSAPublicKeyFromPEM(keyData)
+	key, err := parsePublicKey(mw.SigningAlgorithm, keyData)
 	if err != nil {
 		return ErrInvalidPubKey
 	}
@@ -275,7 +290,7 @@ func (mw *GinJWTMiddleware) publicKey() error {
 
 func (mw *GinJWTMiddleware) usingPublicKeyAlgo() bool {
 	switch mw.SigningAlgorithm {
-	case "RS256", "RS512", "RS384":
+	case "RS256", "RS512", "RS384", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512":
 		return true
 	}
 	return false
@@ -373,11 +388,16 @@ func (mw *GinJWTMiddleware) MiddlewareInit() error {
 		mw.CookieName = "jwt"
 	}
 
+	// 已通过 AddKey 设置签发密钥
+	if mw.activeKey() != nil {
+		return nil
+	}
+
 	if mw.usingPublicKeyAlgo() {
 		return mw.readKeys()
 	}
 
-	if mw.Key == nil {
+	if len(mw.Key) == 0 {
 		return ErrMissingSecretKey
 	}
 	return nil
@@ -405,8 +425,18 @@ func (mw *GinJWTMiddleware) middlewareImpl(c *gin.Context) {
 		mw.unauthorized(c, 6401, mw.HTTPStatusMessageFunc(ErrExpiredToken, c))
 		return
 	}
+	if mw.IsRevoked(claims) {
+		mw.unauthorized(c, http.StatusUnauthorized, mw.HTTPStatusMessageFunc(ErrRevokedToken, c))
+		return
+	}
 
 	c.Set(JwtPayloadKey, claims)
+	ctx := WithClaims(c.Request.Context(), claims)
+	if id, err := claims.Identity(); err == nil {
+		// 操作人id写入请求ctx, 后续日志自动带上
+		ctx = logger.WithOperatorID(ctx, strconv.FormatInt(id, 10))
+	}
+	c.Request = c.Request.WithContext(ctx)
 	identity := mw.IdentityHandler(c)
 
 	if identity != nil {
@@ -452,17 +482,13 @@ func (mw *GinJWTMiddleware) LoginHandler(c *gin.Context) {
 		return
 	}
 	// Create the token
-	token := jwt.New(jwt.GetSigningMethod(mw.SigningAlgorithm))
-	claims := token.Claims.(jwt.MapClaims)
+	claims := MapClaims{}
 	if mw.PayloadFunc != nil {
 		for key, value := range mw.PayloadFunc(data) {
 			claims[key] = value
 		}
 	}
-	expire := mw.TimeFunc().Add(mw.Timeout)
-	claims["exp"] = expire.Unix()
-	claims["orig_iat"] = mw.TimeFunc().Unix()
-	tokenString, err := mw.signedString(token)
+	tokenString, expire, err := mw.createToken(claims)
 
 	if err != nil {
 		mw.unauthorized(c, http.StatusOK, mw.HTTPStatusMessageFunc(ErrFailedTokenCreation, c))
@@ -486,15 +512,31 @@ func (mw *GinJWTMiddleware) LoginHandler(c *gin.Context) {
 	mw.AntdLoginResponse(c, http.StatusOK, tokenString, expire)
 }
 
-func (mw *GinJWTMiddleware) signedString(token *jwt.Token) (string, error) {
-	var tokenString string
-	var err error
-	if mw.usingPublicKeyAlgo() {
-		tokenString, err = token.SignedString(mw.privKey)
+// createToken 签发token, 设置过期时间、签发时间与用于吊销的jti
+func (mw *GinJWTMiddleware) createToken(claims MapClaims) (string, time.Time, error) {
+	expire := mw.TimeFunc().Add(mw.Timeout)
+	claims["exp"] = expire.Unix()
+	claims["orig_iat"] = mw.TimeFunc().Unix()
+	claims["jti"] = uuid.New().String()
+
+	var token *jwt.Token
+	var key interface{}
+	if k := mw.activeKey(); k != nil {
+		token = jwt.NewWithClaims(k.method(), jwt.MapClaims(claims))
+		token.Header["kid"] = k.Kid
+		key = k.Private
 	} else {
-		tokenString, err = token.SignedString(mw.Key)
+		token = jwt.NewWithClaims(jwt.GetSigningMethod(mw.SigningAlgorithm), jwt.MapClaims(claims))
+		key = mw.Key
+		if mw.usingPublicKeyAlgo() {
+			key = mw.privKey
+		}
 	}
-	return tokenString, err
+	tokenString, err := token.SignedString(key)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return tokenString, expire, nil
 }
 
 // RefreshHandler can be used to refresh a token. The token still needs to be valid on refresh.
@@ -518,21 +560,19 @@ func (mw *GinJWTMiddleware) RefreshToken(c *gin.Context) (string, time.Time, err
 	}
 
 	// Create the token
-	newToken := jwt.New(jwt.GetSigningMethod(mw.SigningAlgorithm))
-	newClaims := newToken.Claims.(jwt.MapClaims)
-
+	newClaims := MapClaims{}
 	for key := range claims {
 		newClaims[key] = claims[key]
 	}
-
-	expire := mw.TimeFunc().Add(mw.Timeout)
-	newClaims["exp"] = expire.Unix()
-	newClaims["orig_iat"] = mw.TimeFunc().Unix()
-	tokenString, err := mw.signedString(newToken)
+	tokenString, expire, err := mw.createToken(newClaims)
 
 	if err != nil {
 		return "", time.Now(), err
 	}
+	// 刷新后旧token作废
+	if err = mw.revoke(MapClaims(claims)); err != nil {
+		return "", time.Now(), err
+	}
 
 	// set cookie
 	if mw.SendCookie {
@@ -568,6 +608,9 @@ func (mw *GinJWTMiddleware) CheckIfTokenExpire(c *gin.Context) (jwt.MapClaims, e
 	}
 
 	claims := MapClaims(token.Claims.(jwt.MapClaims))
+	if mw.IsRevoked(claims) {
+		return nil, ErrRevokedToken
+	}
 	origIat, err := claims.OrigIat()
 	if err != nil {
 		return nil, err
@@ -582,8 +625,7 @@ func (mw *GinJWTMiddleware) CheckIfTokenExpire(c *gin.Context) (jwt.MapClaims, e
 
 // TokenGenerator method that clients can use to get a jwt token.
 func (mw *GinJWTMiddleware) TokenGenerator(data interface{}) (string, time.Time, error) {
-	token := jwt.New(jwt.GetSigningMethod(mw.SigningAlgorithm))
-	claims := token.Claims.(jwt.MapClaims)
+	claims := MapClaims{}
 
 	if mw.PayloadFunc != nil {
 		for key, value := range mw.PayloadFunc(data) {
@@ -591,15 +633,12 @@ func (mw *GinJWTMiddleware) TokenGenerator(data interface{}) (string, time.Time,
 		}
 	}
 
-	expire := mw.TimeFunc().UTC().Add(mw.Timeout)
-	claims["exp"] = expire.Unix()
-	claims["orig_iat"] = mw.TimeFunc().Unix()
-	tokenString, err := mw.signedString(token)
+	tokenString, expire, err := mw.createToken(claims)
 	if err != nil {
 		return "", time.Time{}, err
 	}
 
-	return tokenString, expire, nil
+	return tokenString, expire.UTC(), nil
 }
 
 func (mw *GinJWTMiddleware) jwtFromHeader(c *gin.Context, key string) (string, error) {
@@ -677,30 +716,19 @@ func (mw *GinJWTMiddleware) ParseToken(c *gin.Context) (*jwt.Token, error) {
 	}
 
 	return jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
-		if jwt.GetSigningMethod(mw.SigningAlgorithm) != t.Method {
-			return nil, ErrInvalidSigningAlgorithm
-		}
-		if mw.usingPublicKeyAlgo() {
-			return mw.pubKey, nil
+		key, err := mw.keyFunc(t)
+		if err != nil {
+			return nil, err
 		}
 		c.Set("JWT_TOKEN", token)
 
-		return mw.Key, nil
+		return key, nil
 	}, jwt.WithJSONNumber())
 }
 
 // ParseTokenString parse jwt token string
 func (mw *GinJWTMiddleware) ParseTokenString(token string) (*jwt.Token, error) {
-	return jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
-		if jwt.GetSigningMethod(mw.SigningAlgorithm) != t.Method {
-			return nil, ErrInvalidSigningAlgorithm
-		}
-		if mw.usingPublicKeyAlgo() {
-			return mw.pubKey, nil
-		}
-
-		return mw.Key, nil
-	})
+	return jwt.Parse(token, mw.keyFunc)
 }
 
 func (mw *GinJWTMiddleware) unauthorized(c *gin.Context, code int, message string) {
@@ -0,0 +1,160 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+func newMiddleware(t *testing.T) *GinJWTMiddleware {
+	mw, err := New(&GinJWTMiddleware{
+		Key:        []byte("secret"),
+		Timeout:    time.Hour,
+		MaxRefresh: time.Hour,
+		Cache:      cache.NewMemory(),
+		PayloadFunc: func(data interface{}) MapClaims {
+			return MapClaims{IdentityKey: data}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mw
+}
+
+func serve(mw *GinJWTMiddleware, token string) (int, MapClaims) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	var claims MapClaims
+	r.GET("/", mw.MiddlewareFunc(), func(c *gin.Context) {
+		claims = ClaimsFromContext(c.Request.Context())
+		c.Status(http.StatusNoContent)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code, claims
+}
+
+func TestRevokeToken(t *testing.T) {
+	mw := newMiddleware(t)
+	token, _, err := mw.TokenGenerator(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, claims := serve(mw, token)
+	if code != http.StatusNoContent {
+		t.Fatalf("code = %d", code)
+	}
+	if id, _ := claims.Identity(); id != 1 {
+		t.Errorf("identity = %d", id)
+	}
+	if err = mw.RevokeToken(token); err != nil {
+		t.Fatal(err)
+	}
+	if code, _ = serve(mw, token); code == http.StatusNoContent {
+		t.Error("revoked token accepted")
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	mw := newMiddleware(t)
+	newKey := func(kid string) Key {
+		pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return Key{Kid: kid, Algorithm: "ES256", Private: pk, Public: &pk.PublicKey}
+	}
+	if err := mw.AddKey(newKey("k1"), true); err != nil {
+		t.Fatal(err)
+	}
+	old, _, err := mw.TokenGenerator(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mw.AddKey(newKey("k2"), true); err != nil {
+		t.Fatal(err)
+	}
+	current, _, _ := mw.TokenGenerator(1)
+	for _, token := range []string{old, current} {
+		if _, err = mw.ParseTokenString(token); err != nil {
+			t.Errorf("ParseTokenString() error = %v", err)
+		}
+	}
+	mw.RemoveKey("k1")
+	if _, err = mw.ParseTokenString(old); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("ParseTokenString() error = %v, want ErrUnknownKey", err)
+	}
+	if _, err = mw.ParseTokenString(current); err != nil {
+		t.Errorf("ParseTokenString() error = %v", err)
+	}
+
+	// 设置密钥后不再接受没有 kid 的token, 空密钥签名的token也不能通过
+	for _, secret := range [][]byte{[]byte("secret"), {}} {
+		forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			IdentityKey: 1, "exp": time.Now().Add(time.Hour).Unix(),
+		}).SignedString(secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = mw.ParseTokenString(forged); !errors.Is(err, ErrMissingKid) {
+			t.Errorf("ParseTokenString() error = %v, want ErrMissingKid", err)
+		}
+	}
+	if err = mw.AddKey(Key{Kid: "h1", Algorithm: "HS256", Private: []byte{}}, false); !errors.Is(err, ErrMissingSecretKey) {
+		t.Errorf("AddKey() error = %v, want ErrMissingSecretKey", err)
+	}
+}
+
+func TestEmptySecret(t *testing.T) {
+	mw := newMiddleware(t)
+	mw.Key = []byte{}
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		IdentityKey: 1, "exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = mw.ParseTokenString(forged); !errors.Is(err, ErrMissingSecretKey) {
+		t.Errorf("ParseTokenString() error = %v, want ErrMissingSecretKey", err)
+	}
+	if _, err = New(&GinJWTMiddleware{Key: []byte{}}); !errors.Is(err, ErrMissingSecretKey) {
+		t.Errorf("New() error = %v, want ErrMissingSecretKey", err)
+	}
+}
+
+func TestGrpcAuthFunc(t *testing.T) {
+	mw := newMiddleware(t)
+	token, _, err := mw.TokenGenerator(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := mw.GrpcAuthFunc()
+	ctx, err := f(context.Background(), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := ClaimsFromContext(ctx).Identity(); id != 1 || logger.OperatorID(ctx) != "1" {
+		t.Errorf("identity = %d, operator = %s", id, logger.OperatorID(ctx))
+	}
+	if err = mw.RevokeToken(token); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f(context.Background(), token); !errors.Is(err, ErrRevokedToken) {
+		t.Errorf("err = %v", err)
+	}
+}
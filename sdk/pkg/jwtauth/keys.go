@@ -0,0 +1,162 @@
+package jwtauth
+
+import (
+	"crypto"
+	"errors"
+	"io/ioutil"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// ErrUnknownKey token 头部的 kid 没有对应的密钥, 可能已被轮换移除
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// ErrMissingKid 已通过 AddKey 设置密钥时, token 头部必须带 kid
+var ErrMissingKid = errors.New("token has no kid")
+
+// Key 签名密钥, Kid 写入token头部;
+// 轮换时新密钥用于签发, 旧密钥保留到其签发的token全部过期后再移除
+type Key struct {
+	Kid       string
+	Algorithm string
+	// Private HS 系列为 []byte 密钥, RS/PS 为 *rsa.PrivateKey, ES 为 *ecdsa.PrivateKey; 只用于校验的旧密钥可以为空
+	Private crypto.PrivateKey
+	// Public HS 系列不需要
+	Public crypto.PublicKey
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+func (k *Key) verifyKey() interface{} {
+	if isHMAC(k.Algorithm) {
+		return k.Private
+	}
+	return k.Public
+}
+
+func isHMAC(algorithm string) bool {
+	return strings.HasPrefix(algorithm, "HS")
+}
+
+func isECDSA(algorithm string) bool {
+	return strings.HasPrefix(algorithm, "ES")
+}
+
+// LoadKey 从PEM文件读取非对称密钥, privFile 为空时只能用于校验
+func LoadKey(kid, algorithm, privFile, pubFile string) (Key, error) {
+	k := Key{Kid: kid, Algorithm: algorithm}
+	if jwt.GetSigningMethod(algorithm) == nil || isHMAC(algorithm) {
+		return k, ErrInvalidSigningAlgorithm
+	}
+	if privFile != "" {
+		data, err := ioutil.ReadFile(privFile)
+		if err != nil {
+			return k, ErrNoPrivKeyFile
+		}
+		if k.Private, err = parsePrivateKey(algorithm, data); err != nil {
+			return k, ErrInvalidPrivKey
+		}
+	}
+	data, err := ioutil.ReadFile(pubFile)
+	if err != nil {
+		return k, ErrNoPubKeyFile
+	}
+	if k.Public, err = parsePublicKey(algorithm, data); err != nil {
+		return k, ErrInvalidPubKey
+	}
+	return k, nil
+}
+
+func parsePrivateKey(algorithm string, data []byte) (crypto.PrivateKey, error) {
+	if isECDSA(algorithm) {
+		return jwt.ParseECPrivateKeyFromPEM(data)
+	}
+	return jwt.ParseRSAPrivateKeyFromPEM(data)
+}
+
+func parsePublicKey(algorithm string, data []byte) (crypto.PublicKey, error) {
+	if isECDSA(algorithm) {
+		return jwt.ParseECPublicKeyFromPEM(data)
+	}
+	return jwt.ParseRSAPublicKeyFromPEM(data)
+}
+
+// AddKey 添加密钥, active 为 true 时之后签发的token使用该密钥
+func (mw *GinJWTMiddleware) AddKey(k Key, active bool) error {
+	if k.method() == nil {
+		return ErrInvalidSigningAlgorithm
+	}
+	if active && k.Private == nil {
+		return ErrInvalidPrivKey
+	}
+	if _, err := hmacKey(k.Private); isHMAC(k.Algorithm) && err != nil {
+		return err
+	}
+	mw.keyMux.Lock()
+	defer mw.keyMux.Unlock()
+	if mw.keys == nil {
+		mw.keys = make(map[string]*Key)
+	}
+	mw.keys[k.Kid] = &k
+	if active {
+		mw.active = &k
+	}
+	return nil
+}
+
+// RemoveKey 移除轮换下来的旧密钥, 不能移除当前签发使用的密钥
+func (mw *GinJWTMiddleware) RemoveKey(kid string) {
+	mw.keyMux.Lock()
+	defer mw.keyMux.Unlock()
+	if mw.active == nil || mw.active.Kid != kid {
+		delete(mw.keys, kid)
+	}
+}
+
+// activeKey 当前签发使用的密钥, 未通过 AddKey 设置时返回nil
+func (mw *GinJWTMiddleware) activeKey() *Key {
+	mw.keyMux.RLock()
+	defer mw.keyMux.RUnlock()
+	return mw.active
+}
+
+// keyFunc 按 kid 选择校验密钥; 没有 kid 时使用 SigningAlgorithm 与 Key/PubKeyFile,
+// 但已通过 AddKey 设置密钥后不再接受没有 kid 的token; HMAC 密钥为空时一律拒绝, 否则空密钥签名的token也能通过校验
+func (mw *GinJWTMiddleware) keyFunc(t *jwt.Token) (interface{}, error) {
+	kid, ok := t.Header["kid"].(string)
+	mw.keyMux.RLock()
+	k, hasKeys := mw.keys[kid], len(mw.keys) > 0
+	mw.keyMux.RUnlock()
+	if ok {
+		if k == nil {
+			return nil, ErrUnknownKey
+		}
+		if k.method() != t.Method {
+			return nil, ErrInvalidSigningAlgorithm
+		}
+		if isHMAC(k.Algorithm) {
+			return hmacKey(k.Private)
+		}
+		return k.verifyKey(), nil
+	}
+	if hasKeys {
+		return nil, ErrMissingKid
+	}
+	if jwt.GetSigningMethod(mw.SigningAlgorithm) != t.Method {
+		return nil, ErrInvalidSigningAlgorithm
+	}
+	if mw.usingPublicKeyAlgo() {
+		return mw.pubKey, nil
+	}
+	return hmacKey(mw.Key)
+}
+
+func hmacKey(key interface{}) (interface{}, error) {
+	if b, ok := key.([]byte); !ok || len(b) == 0 {
+		return nil, ErrMissingSecretKey
+	}
+	return key, nil
+}
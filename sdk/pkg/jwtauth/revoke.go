@@ -0,0 +1,79 @@
+package jwtauth
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// RevokedPrefix 吊销列表在缓存中的key前缀
+const RevokedPrefix = "jwt:revoked:"
+
+// ErrRevokedToken token 已被吊销(退出登录或已刷新)
+var ErrRevokedToken = errors.New("token is revoked")
+
+// IsRevoked 判断token是否在吊销列表中, 未配置 Cache 时总是返回false
+func (mw *GinJWTMiddleware) IsRevoked(claims MapClaims) bool {
+	if mw.Cache == nil {
+		return false
+	}
+	jti := claims.String("jti")
+	if jti == "" {
+		return false
+	}
+	v, err := mw.Cache.Get(RevokedPrefix + jti)
+	return err == nil && v != ""
+}
+
+// revoke 将jti写入吊销列表, 保留到token无法再被刷新为止
+func (mw *GinJWTMiddleware) revoke(claims MapClaims) error {
+	if mw.Cache == nil {
+		return nil
+	}
+	jti := claims.String("jti")
+	if jti == "" {
+		return nil
+	}
+	ttl := int(mw.MaxRefresh / time.Second)
+	if exp, err := claims.Exp(); err == nil {
+		ttl += int(exp - mw.TimeFunc().Unix())
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	return mw.Cache.Set(RevokedPrefix+jti, 1, ttl)
+}
+
+// RevokeToken 吊销token, 已过期但仍可刷新的token同样会被吊销
+func (mw *GinJWTMiddleware) RevokeToken(token string) error {
+	t, err := mw.ParseTokenString(token)
+	if err != nil {
+		var validationErr *jwt.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Errors != jwt.ValidationErrorExpired {
+			return err
+		}
+	}
+	return mw.revoke(ExtractClaimsFromToken(t))
+}
+
+// LogoutHandler 吊销当前请求的token并清除cookie
+func (mw *GinJWTMiddleware) LogoutHandler(c *gin.Context) {
+	token, err := mw.ParseToken(c)
+	if err != nil {
+		mw.unauthorized(c, http.StatusUnauthorized, mw.HTTPStatusMessageFunc(err, c))
+		return
+	}
+	if err = mw.revoke(ExtractClaimsFromToken(token)); err != nil {
+		mw.unauthorized(c, http.StatusInternalServerError, mw.HTTPStatusMessageFunc(err, c))
+		return
+	}
+	if mw.SendCookie {
+		c.SetCookie(mw.CookieName, "", -1, "/", mw.CookieDomain, mw.SecureCookie, mw.CookieHTTPOnly)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code": http.StatusOK,
+	})
+}
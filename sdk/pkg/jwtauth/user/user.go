@@ -1,13 +1,18 @@
 package user
 
 import (
-	"fmt"
-
 	"github.com/gin-gonic/gin"
-	"github.com/go-admin-team/go-admin-core/sdk/pkg"
+	"github.com/go-admin-team/go-admin-core/logger"
 	jwt "github.com/go-admin-team/go-admin-core/sdk/pkg/jwtauth"
 )
 
+// warn 记录缺少claims的请求
+func warn(c *gin.Context, msg string, kv ...interface{}) {
+	logger.S().WithContext(c.Request.Context()).
+		With("method", c.Request.Method, "path", c.Request.URL.Path).
+		Warn(msg, kv...)
+}
+
 func ExtractClaims(c *gin.Context) jwt.MapClaims {
 	claims, exists := c.Get(jwt.JwtPayloadKey)
 	if !exists {
@@ -23,7 +28,7 @@ func Get(c *gin.Context, key string) interface{} {
 		return data[key]
 	}
 
-	fmt.Println(pkg.GetCurrentTimeStr() + " [WARING] " + c.Request.Method + " " + c.Request.URL.Path + " Get 缺少 " + key)
+	warn(c, "claims missing key", "key", key)
 
 	return nil
 }
@@ -33,7 +38,7 @@ func GetUserId(c *gin.Context) int {
 	data := ExtractClaims(c)
 	identity, err := data.Identity()
 	if err != nil {
-		fmt.Println(pkg.GetCurrentTimeStr() + " [WARING] " + c.Request.Method + " " + c.Request.URL.Path + " GetUserId 缺少 identity error: " + err.Error())
+		warn(c, "claims missing key", "key", "identity", "error", err)
 		return 0
 	}
 
@@ -45,7 +50,7 @@ func GetUserIdInt64(c *gin.Context) int64 {
 	data := ExtractClaims(c)
 	identity, err := data.Identity()
 	if err != nil {
-		fmt.Println(pkg.GetCurrentTimeStr() + " [WARING] " + c.Request.Method + " " + c.Request.URL.Path + " GetUserId 缺少 identity error: " + err.Error())
+		warn(c, "claims missing key", "key", "identity", "error", err)
 		return 0
 	}
 
@@ -69,7 +74,7 @@ func GetRoleName(c *gin.Context) string {
 func GetRoleId(c *gin.Context) int {
 	roleId, err := ExtractClaims(c).Int("roleid")
 	if err != nil {
-		fmt.Println(pkg.GetCurrentTimeStr() + " [WARING] " + c.Request.Method + " " + c.Request.URL.Path + " GetRoleId 缺少 roleid error: " + err.Error())
+		warn(c, "claims missing key", "key", "roleid", "error", err)
 		return 0
 	}
 
@@ -79,7 +84,7 @@ func GetRoleId(c *gin.Context) int {
 func GetDeptId(c *gin.Context) int {
 	deptId, err := ExtractClaims(c).Int("deptid")
 	if err != nil {
-		fmt.Println(pkg.GetCurrentTimeStr() + " [WARING] " + c.Request.Method + " " + c.Request.URL.Path + " GetDeptId 缺少 deptid error: " + err.Error())
+		warn(c, "claims missing key", "key", "deptid", "error", err)
 		return 0
 	}
 
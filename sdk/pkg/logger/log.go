@@ -26,8 +26,13 @@ func SetupLogger(opts ...Option) logger.Logger {
 	}
 	var err error
 	var output io.Writer
-	switch op.stdout {
-	case "file":
+	switch {
+	case len(op.sinks) > 0:
+		output, err = newMultiSink(op.sinks)
+		if err != nil {
+			log.Fatalf("logger setup error: %s", err.Error())
+		}
+	case op.stdout == "file":
 		output, err = writer.NewFileWriter(
 			writer.WithPath(op.path),
 			writer.WithCap(op.cap<<10),
@@ -38,6 +43,15 @@ func SetupLogger(opts ...Option) logger.Logger {
 	default:
 		output = os.Stdout
 	}
+	// 脱敏包装在所有输出之前; 关闭时仍使用原始输出
+	out := output
+	if op.redact != nil {
+		r, err := newRedactor(op.redact)
+		if err != nil {
+			log.Fatalf("logger setup error: %s", err.Error())
+		}
+		out = logger.NewRedactWriter(output, r)
+	}
 	var level logger.Level
 	level, err = logger.GetLevel(op.level)
 	if err != nil {
@@ -46,14 +60,24 @@ func SetupLogger(opts ...Option) logger.Logger {
 
 	switch op.driver {
 	case "zap":
-		log.DefaultLogger, err = zap.NewLogger(logger.WithLevel(level), zap.WithOutput(output), zap.WithCallerSkip(2))
+		log.DefaultLogger, err = zap.NewLogger(logger.WithLevel(level), zap.WithOutput(out), zap.WithCallerSkip(2))
 		if err != nil {
 			log.Fatalf("new zap logger error, %s", err.Error())
 		}
 	//case "logrus":
 	//	setLogger = logrus.NewLogger(logger.WithLevel(level), logger.WithOutput(output), logrus.ReportCaller())
 	default:
-		log.DefaultLogger = logger.NewLogger(logger.WithLevel(level), logger.WithOutput(output))
+		log.DefaultLogger = logger.NewLogger(logger.WithLevel(level), logger.WithOutput(out))
+	}
+	setOutput(output)
+	for module, l := range op.modules {
+		ml, err := logger.GetLevel(l)
+		if err != nil {
+			log.Fatalf("get logger level of module %s error, %s", module, err.Error())
+		}
+		_ = logger.SetModuleLevel(module, ml)
 	}
+	// 重新应用运行时级别, 模块级别比全局更详细时底层logger需要放开
+	_ = logger.SetLevel(level)
 	return log.DefaultLogger
 }
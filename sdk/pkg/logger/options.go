@@ -15,6 +15,15 @@ type options struct {
 	level  string
 	stdout string
 	cap    uint
+	sinks  []Sink
+	// modules 模块级别, eg: storage.queue: debug
+	modules map[string]string
+	redact  *redact
+}
+
+type redact struct {
+	fields   []string
+	patterns []string
 }
 
 func setDefault() options {
@@ -55,3 +64,25 @@ func WithCap(n uint) Option {
 		o.cap = n
 	}
 }
+
+// WithSinks 同时输出到多个sink, 设置后忽略 stdout
+func WithSinks(sinks ...Sink) Option {
+	return func(o *options) {
+		o.sinks = append(o.sinks, sinks...)
+	}
+}
+
+// WithModules 设置模块级别, 见 logger.SetModuleLevel
+func WithModules(m map[string]string) Option {
+	return func(o *options) {
+		o.modules = m
+	}
+}
+
+// WithRedact 开启日志脱敏, fields 追加到 logger.RedactFields,
+// patterns 为 logger.RedactPatterns 中的名称或正则, 为空时使用 phone idcard jwt
+func WithRedact(fields, patterns []string) Option {
+	return func(o *options) {
+		o.redact = &redact{fields: fields, patterns: patterns}
+	}
+}
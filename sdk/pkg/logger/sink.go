@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+
+	"github.com/go-admin-team/go-admin-core/debug/writer"
+	"github.com/go-admin-team/go-admin-core/debug/writer/ship"
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg"
+)
+
+// Sink 日志输出
+type Sink struct {
+	// Type console, file, syslog, loki, elasticsearch, kafka
+	Type string
+	// Path file日志目录
+	Path string
+	// Cap file单个文件大小, 单位为kb, 0为只按天切割
+	Cap uint
+	// Network Addr Tag syslog地址, 为空时使用本机syslog
+	Network string
+	Addr    string
+	Tag     string
+	// Buffer 异步写入缓冲条数
+	Buffer int
+	// Address loki、elasticsearch、kafka rest proxy 地址
+	Address string
+	// Topic elasticsearch索引或kafka topic
+	Topic string
+	// Labels loki stream标签
+	Labels map[string]string
+	// SpillPath 远端不可用时落盘目录, 恢复后补发
+	SpillPath string
+}
+
+var (
+	outputMux sync.Mutex
+	output    io.Writer
+)
+
+// newSink 创建单个输出
+func newSink(s Sink) (io.Writer, error) {
+	switch s.Type {
+	case "console", "":
+		return writer.NewAsyncWriter(os.Stdout, s.Buffer), nil
+	case "file":
+		if !pkg.PathExist(s.Path) {
+			if err := pkg.PathCreate(s.Path); err != nil {
+				return nil, err
+			}
+		}
+		return writer.NewFileWriter(
+			writer.WithPath(s.Path),
+			writer.WithCap(s.Cap<<10),
+			writer.WithBuffer(s.Buffer),
+		)
+	case "syslog":
+		w, err := writer.NewSyslogWriter(s.Network, s.Addr, s.Tag)
+		if err != nil {
+			return nil, err
+		}
+		return writer.NewAsyncWriter(w, s.Buffer), nil
+	case "loki", "elasticsearch", "kafka":
+		return newShipper(s)
+	default:
+		return nil, fmt.Errorf("unsupported log sink %s", s.Type)
+	}
+}
+
+// newMultiSink 创建多个输出, 失败时关闭已创建的输出
+func newMultiSink(sinks []Sink) (io.Writer, error) {
+	ws := make([]io.Writer, 0, len(sinks))
+	for _, s := range sinks {
+		w, err := newSink(s)
+		if err != nil {
+			_ = writer.NewMultiWriter(ws...).Close()
+			return nil, err
+		}
+		ws = append(ws, w)
+	}
+	return writer.NewMultiWriter(ws...), nil
+}
+
+// setOutput 记录当前输出, 关闭上一次 SetupLogger 创建的输出
+func setOutput(w io.Writer) {
+	outputMux.Lock()
+	prev := output
+	output = w
+	outputMux.Unlock()
+	if prev != w {
+		_ = closeOutput(prev)
+	}
+}
+
+// Close 写完缓冲中的日志并关闭输出, 退出前调用
+func Close() error {
+	outputMux.Lock()
+	w := output
+	output = nil
+	outputMux.Unlock()
+	return closeOutput(w)
+}
+
+func closeOutput(w io.Writer) error {
+	if w == os.Stdout || w == os.Stderr {
+		return nil
+	}
+	if c, ok := w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// newShipper 创建远端日志输出
+func newShipper(s Sink) (io.Writer, error) {
+	if s.Address == "" {
+		return nil, fmt.Errorf("log sink %s address is empty", s.Type)
+	}
+	var client ship.Client
+	switch s.Type {
+	case "loki":
+		client = ship.NewLoki(s.Address, s.Labels)
+	case "elasticsearch":
+		client = ship.NewElasticsearch(s.Address, s.Topic)
+	default:
+		client = ship.NewKafka(s.Address, s.Topic)
+	}
+	return ship.NewShipper(client, ship.WithBuffer(s.Buffer), ship.WithSpillPath(s.SpillPath))
+}
+
+// newRedactor 按配置创建脱敏
+func newRedactor(r *redact) (*logger.Redactor, error) {
+	names := r.patterns
+	if len(names) == 0 {
+		names = []string{"phone", "idcard", "jwt"}
+	}
+	patterns := make([]*regexp.Regexp, 0, len(names))
+	for _, name := range names {
+		if p, ok := logger.RedactPatterns[name]; ok {
+			patterns = append(patterns, p)
+			continue
+		}
+		p, err := regexp.Compile(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %s, %s", name, err.Error())
+		}
+		patterns = append(patterns, p)
+	}
+	fields := append(append([]string{}, logger.RedactFields...), r.fields...)
+	return logger.NewRedactor(fields, patterns...), nil
+}
@@ -0,0 +1,263 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/mail"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SendGridConfig SendGrid 配置
+type SendGridConfig struct {
+	APIKey string `json:"apiKey"`
+	// Endpoint 默认 https://api.sendgrid.com/v3/mail/send
+	Endpoint string `json:"endpoint"`
+}
+
+// SendGrid 通过 SendGrid v3 接口发送
+type SendGrid struct {
+	c      SendGridConfig
+	client *http.Client
+}
+
+func NewSendGrid(c SendGridConfig) *SendGrid {
+	if c.Endpoint == "" {
+		c.Endpoint = "https://api.sendgrid.com/v3/mail/send"
+	}
+	return &SendGrid{c: c, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (*SendGrid) String() string {
+	return "sendgrid"
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+func sendGridAddresses(list []string) ([]sendGridAddress, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	addrs := make([]sendGridAddress, 0, len(list))
+	for _, s := range list {
+		addr, err := mail.ParseAddress(s)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, sendGridAddress{Email: addr.Address, Name: addr.Name})
+	}
+	return addrs, nil
+}
+
+func (s *SendGrid) Send(ctx context.Context, m *Message) error {
+	if _, err := m.Recipients(); err != nil {
+		return err
+	}
+	type personalization struct {
+		To  []sendGridAddress `json:"to"`
+		Cc  []sendGridAddress `json:"cc,omitempty"`
+		Bcc []sendGridAddress `json:"bcc,omitempty"`
+	}
+	type content struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	type attachment struct {
+		Content     string `json:"content"`
+		Type        string `json:"type,omitempty"`
+		Filename    string `json:"filename"`
+		Disposition string `json:"disposition,omitempty"`
+		ContentID   string `json:"content_id,omitempty"`
+	}
+	var (
+		p   personalization
+		err error
+	)
+	if p.To, err = sendGridAddresses(m.To); err != nil {
+		return err
+	}
+	if p.Cc, err = sendGridAddresses(m.Cc); err != nil {
+		return err
+	}
+	if p.Bcc, err = sendGridAddresses(m.Bcc); err != nil {
+		return err
+	}
+	from, err := sendGridAddresses([]string{m.From})
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"personalizations": []personalization{p},
+		"from":             from[0],
+		"subject":          m.Subject,
+	}
+	if m.ReplyTo != "" {
+		replyTo, err := sendGridAddresses([]string{m.ReplyTo})
+		if err != nil {
+			return err
+		}
+		body["reply_to"] = replyTo[0]
+	}
+	contents := make([]content, 0, 2)
+	if m.Text != "" {
+		contents = append(contents, content{"text/plain", m.Text})
+	}
+	if m.HTML != "" {
+		contents = append(contents, content{"text/html", m.HTML})
+	}
+	body["content"] = contents
+	if len(m.Attachments) > 0 {
+		list := make([]attachment, 0, len(m.Attachments))
+		for _, a := range m.Attachments {
+			item := attachment{
+				Content:  base64.StdEncoding.EncodeToString(a.Data),
+				Type:     a.ContentType,
+				Filename: a.Filename,
+			}
+			if item.Type == "" {
+				item.Type = mime.TypeByExtension(filepath.Ext(a.Filename))
+			}
+			if a.Inline {
+				item.Disposition, item.ContentID = "inline", a.Filename
+			}
+			list = append(list, item)
+		}
+		body["attachments"] = list
+	}
+	if len(m.Headers) > 0 {
+		body["headers"] = m.Headers
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.c.Endpoint, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.c.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+	return doRequest(s.client, req, "sendgrid")
+}
+
+// SESConfig Amazon SES 配置
+type SESConfig struct {
+	Region    string `json:"region"`
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+	// Endpoint 默认 https://email.<Region>.amazonaws.com
+	Endpoint string `json:"endpoint"`
+}
+
+// SES 通过 Amazon SES v2 接口发送原始邮件, 支持附件
+type SES struct {
+	c      SESConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewSES(c SESConfig) *SES {
+	if c.Region == "" {
+		c.Region = "us-east-1"
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = "https://email." + c.Region + ".amazonaws.com"
+	}
+	c.Endpoint = strings.TrimSuffix(c.Endpoint, "/")
+	return &SES{c: c, client: &http.Client{Timeout: 30 * time.Second}, now: time.Now}
+}
+
+func (*SES) String() string {
+	return "ses"
+}
+
+func (s *SES) Send(ctx context.Context, m *Message) error {
+	rcpt, err := m.Recipients()
+	if err != nil {
+		return err
+	}
+	raw, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(map[string]interface{}{
+		"FromEmailAddress": from.Address,
+		"Destination":      map[string]interface{}{"ToAddresses": rcpt},
+		"Content":          map[string]interface{}{"Raw": map[string]interface{}{"Data": raw}},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.c.Endpoint+"/v2/email/outbound-emails", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	s.sign(req, b)
+	return doRequest(s.client, req, "ses")
+}
+
+// sign AWS Signature V4
+func (s *SES) sign(r *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payload := sha256.Sum256(body)
+	r.Header.Set("X-Amz-Date", amzDate)
+	r.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payload[:]))
+
+	headers := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonical := r.Method + "\n" + r.URL.EscapedPath() + "\n" + r.URL.RawQuery + "\n" +
+		"content-type:" + r.Header.Get("Content-Type") + "\n" +
+		"host:" + r.URL.Host + "\n" +
+		"x-amz-content-sha256:" + hex.EncodeToString(payload[:]) + "\n" +
+		"x-amz-date:" + amzDate + "\n\n" +
+		headers + "\n" + hex.EncodeToString(payload[:])
+	hash := sha256.Sum256([]byte(canonical))
+	scope := date + "/" + s.c.Region + "/ses/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.c.SecretKey), date)
+	key = hmacSHA256(key, s.c.Region)
+	key = hmacSHA256(key, "ses")
+	key = hmacSHA256(key, "aws4_request")
+	r.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.c.AccessKey+"/"+scope+
+		", SignedHeaders="+headers+", Signature="+hex.EncodeToString(hmacSHA256(key, toSign)))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func doRequest(client *http.Client, req *http.Request, name string) error {
+	rsp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(rsp.Body, 1<<10))
+		return fmt.Errorf("mailer: %s: %s: %s", name, rsp.Status, strings.TrimSpace(string(b)))
+	}
+	_, _ = io.Copy(io.Discard, rsp.Body)
+	return nil
+}
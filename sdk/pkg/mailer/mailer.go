@@ -0,0 +1,224 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+const (
+	DefaultStream     = "mail"
+	DefaultDeadStream = "mail_dead"
+)
+
+var ErrNoQueue = errors.New("mailer: queue not configured")
+
+// Provider 邮件发送服务
+type Provider interface {
+	String() string
+	Send(ctx context.Context, m *Message) error
+}
+
+type Option func(*options)
+
+type options struct {
+	from       string
+	templates  *Templates
+	queue      storage.AdapterQueue
+	stream     string
+	deadStream string
+	maxRetry   int
+	backoff    time.Duration
+}
+
+func setDefault() options {
+	return options{
+		stream:     DefaultStream,
+		deadStream: DefaultDeadStream,
+		maxRetry:   3,
+		backoff:    5 * time.Second,
+	}
+}
+
+// WithFrom 默认发件人
+func WithFrom(from string) Option {
+	return func(o *options) {
+		o.from = from
+	}
+}
+
+// WithTemplates 邮件模板, SendTemplate 使用
+func WithTemplates(t *Templates) Option {
+	return func(o *options) {
+		o.templates = t
+	}
+}
+
+// WithQueue 异步发送使用的队列, stream 为空时使用 DefaultStream
+func WithQueue(q storage.AdapterQueue, stream string) Option {
+	return func(o *options) {
+		o.queue = q
+		if stream != "" {
+			o.stream = stream
+		}
+	}
+}
+
+// WithRetry 异步发送失败的重试次数与间隔, 第 n 次重试等待 n*backoff
+func WithRetry(max int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.maxRetry = max
+		o.backoff = backoff
+	}
+}
+
+// WithDeadLetter 重试仍失败的邮件写入的 stream, 为空时不保存
+func WithDeadLetter(stream string) Option {
+	return func(o *options) {
+		o.deadStream = stream
+	}
+}
+
+// Mailer 邮件发送
+type Mailer struct {
+	provider Provider
+	opts     options
+}
+
+func New(p Provider, opts ...Option) *Mailer {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Mailer{provider: p, opts: o}
+}
+
+func (e *Mailer) String() string {
+	return e.provider.String()
+}
+
+// Send 同步发送, 未设置发件人时使用默认发件人
+func (e *Mailer) Send(ctx context.Context, m *Message) error {
+	if m.From == "" {
+		m.From = e.opts.from
+	}
+	return e.provider.Send(ctx, m)
+}
+
+// Render 使用模板生成邮件
+func (e *Mailer) Render(to []string, name string, data interface{}) (*Message, error) {
+	if e.opts.templates == nil {
+		return nil, errors.New("mailer: templates not configured")
+	}
+	subject, html, text, err := e.opts.templates.Render(name, data)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{From: e.opts.from, To: to, Subject: subject, HTML: html, Text: text}, nil
+}
+
+// SendTemplate 使用模板同步发送
+func (e *Mailer) SendTemplate(ctx context.Context, to []string, name string, data interface{}) error {
+	m, err := e.Render(to, name, data)
+	if err != nil {
+		return err
+	}
+	return e.Send(ctx, m)
+}
+
+// SendAsync 写入队列异步发送, 需先调用 Start 注册消费者
+func (e *Mailer) SendAsync(ctx context.Context, m *Message) error {
+	if e.opts.queue == nil {
+		return ErrNoQueue
+	}
+	if m.From == "" {
+		m.From = e.opts.from
+	}
+	if _, err := m.Recipients(); err != nil {
+		return err
+	}
+	return e.publish(ctx, e.opts.stream, m, 0, "")
+}
+
+// SendTemplateAsync 使用模板异步发送
+func (e *Mailer) SendTemplateAsync(ctx context.Context, to []string, name string, data interface{}) error {
+	m, err := e.Render(to, name, data)
+	if err != nil {
+		return err
+	}
+	return e.SendAsync(ctx, m)
+}
+
+// Start 注册队列消费者
+func (e *Mailer) Start() error {
+	if e.opts.queue == nil {
+		return ErrNoQueue
+	}
+	e.opts.queue.Register(e.opts.stream, e.consume)
+	return nil
+}
+
+func (e *Mailer) publish(ctx context.Context, stream string, m *Message, attempt int, reason string) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	values := map[string]interface{}{
+		"mail":    string(b),
+		"attempt": strconv.Itoa(attempt),
+	}
+	if reason != "" {
+		values["error"] = reason
+	}
+	message := new(queue.Message)
+	message.SetStream(stream)
+	message.SetValues(values)
+	queue.InjectTrace(ctx, message)
+	return e.opts.queue.Append(message)
+}
+
+// consume 自行处理重试, 始终返回 nil, 避免队列的重试导致重复发送
+func (e *Mailer) consume(message storage.Messager) error {
+	ctx := queue.TraceContext(message)
+	log := logger.Module("sdk.mailer").WithContext(ctx).With("provider", e.provider.String())
+	values := message.GetValues()
+	raw, _ := values["mail"].(string)
+	m := new(Message)
+	if err := json.Unmarshal([]byte(raw), m); err != nil {
+		log.Error("invalid mail message", "id", message.GetID(), "error", err)
+		return nil
+	}
+	attempt := 0
+	if s, ok := values["attempt"].(string); ok {
+		attempt, _ = strconv.Atoi(s)
+	}
+	err := e.provider.Send(ctx, m)
+	if err == nil {
+		return nil
+	}
+	log = log.With("to", m.To, "subject", m.Subject, "attempt", attempt+1)
+	if attempt < e.opts.maxRetry {
+		log.Warn("send mail failed, retry", "error", err)
+		time.AfterFunc(e.opts.backoff*time.Duration(attempt+1), func() {
+			if err := e.publish(ctx, e.opts.stream, m, attempt+1, ""); err != nil {
+				log.Error("requeue mail failed", "error", err)
+			}
+		})
+		return nil
+	}
+	if e.opts.deadStream == "" {
+		log.Error("send mail failed, dropped", "error", err)
+		return nil
+	}
+	log.Error("send mail failed, moved to dead letter", "stream", e.opts.deadStream, "error", err)
+	if err := e.publish(ctx, e.opts.deadStream, m, attempt+1, err.Error()); err != nil {
+		log.Error("append dead letter failed", "error", err)
+	}
+	return nil
+}
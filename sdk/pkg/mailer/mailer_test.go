@@ -0,0 +1,180 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+func TestMessageBytes(t *testing.T) {
+	m := &Message{
+		From:    "系统 <noreply@example.com>",
+		To:      []string{"a@example.com"},
+		Bcc:     []string{"b@example.com"},
+		Subject: "欢迎",
+		Text:    "hello",
+		HTML:    "<p>hello</p>",
+	}
+	m.Attach("报表.csv", []byte("a,b\n1,2\n"))
+	raw, err := m.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject")); subject != "欢迎" {
+		t.Errorf("subject = %q", subject)
+	}
+	if msg.Header.Get("Bcc") != "" {
+		t.Errorf("bcc should not be written")
+	}
+	mediaType, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if mediaType != "multipart/mixed" {
+		t.Fatalf("content type = %s", mediaType)
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var types []string
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		mediaType, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		types = append(types, mediaType)
+	}
+	if strings.Join(types, ",") != "multipart/alternative,text/csv" {
+		t.Errorf("parts = %v", types)
+	}
+	rcpt, _ := m.Recipients()
+	if len(rcpt) != 2 {
+		t.Errorf("recipients = %v", rcpt)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layouts/base.html": {Data: []byte(`{{define "layout"}}<html>{{block "content" .}}{{end}}</html>{{end}}`)},
+		"welcome.html": {Data: []byte(`{{define "subject"}}欢迎 {{.Name}} & 加入{{end}}` +
+			`{{define "content"}}<b>{{.Name}}</b>{{end}}{{define "text"}}hi {{.Name}}{{end}}`)},
+		"plain.html": {Data: []byte(`<p>{{.Name}}</p>`)},
+	}
+	tpl, err := NewTemplates(fsys, "layouts/*.html", "*.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, html, text, err := tpl.Render("welcome", map[string]string{"Name": "<张三>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "欢迎 <张三> & 加入" {
+		t.Errorf("subject = %q", subject)
+	}
+	if html != "<html><b>&lt;张三&gt;</b></html>" {
+		t.Errorf("html = %q", html)
+	}
+	if text != "hi <张三>" {
+		t.Errorf("text = %q", text)
+	}
+	if _, _, _, err = tpl.Render("missing", nil); err == nil {
+		t.Error("expected error for missing template")
+	}
+}
+
+func TestSendGrid(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	m := New(NewSendGrid(SendGridConfig{APIKey: "key", Endpoint: srv.URL}), WithFrom("noreply@example.com"))
+	err := m.Send(context.Background(), &Message{To: []string{"Tom <tom@example.com>"}, Subject: "hi", Text: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, _ := body["from"].(map[string]interface{})
+	if from["email"] != "noreply@example.com" {
+		t.Errorf("from = %v", body["from"])
+	}
+
+	m = New(NewSendGrid(SendGridConfig{APIKey: "bad", Endpoint: srv.URL}), WithFrom("noreply@example.com"))
+	if err = m.Send(context.Background(), &Message{To: []string{"tom@example.com"}, Text: "x"}); err == nil {
+		t.Error("expected error")
+	}
+}
+
+type failProvider struct {
+	mux   sync.Mutex
+	calls int
+}
+
+func (*failProvider) String() string { return "fail" }
+
+func (p *failProvider) Send(context.Context, *Message) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.calls++
+	return errors.New("unavailable")
+}
+
+func TestSendAsyncDeadLetter(t *testing.T) {
+	q := queue.NewMemory(10)
+	p := &failProvider{}
+	m := New(p, WithFrom("noreply@example.com"), WithQueue(q, ""), WithRetry(2, time.Millisecond))
+	dead := make(chan storage.Messager, 1)
+	q.Register(DefaultDeadStream, func(message storage.Messager) error {
+		dead <- message
+		return nil
+	})
+	if err := m.Start(); err != nil {
+		t.Fatal(err)
+	}
+	go q.Run()
+	defer q.Shutdown()
+
+	if err := m.SendAsync(context.Background(), &Message{To: []string{"a@example.com"}, Subject: "hi", Text: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case message := <-dead:
+		values := message.GetValues()
+		if values["error"] != "unavailable" || values["attempt"] != "3" {
+			t.Errorf("dead letter = %v", values)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("dead letter not received")
+	}
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.calls != 3 {
+		t.Errorf("calls = %d, want 3", p.calls)
+	}
+
+	if err := New(p).SendAsync(context.Background(), &Message{}); err != ErrNoQueue {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var ErrNoRecipient = errors.New("mailer: no recipient")
+
+// Message 邮件, 地址可以是 "名称 <user@example.com>" 形式
+type Message struct {
+	From        string            `json:"from"`
+	To          []string          `json:"to"`
+	Cc          []string          `json:"cc,omitempty"`
+	Bcc         []string          `json:"bcc,omitempty"`
+	ReplyTo     string            `json:"replyTo,omitempty"`
+	Subject     string            `json:"subject"`
+	Text        string            `json:"text,omitempty"`
+	HTML        string            `json:"html,omitempty"`
+	Attachments []Attachment      `json:"attachments,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+// Attachment 附件, ContentType 为空时按文件名推断
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType,omitempty"`
+	Data        []byte `json:"data"`
+	// Inline 内嵌图片, html 中以 cid:<Filename> 引用
+	Inline bool `json:"inline,omitempty"`
+}
+
+// Attach 添加附件
+func (m *Message) Attach(filename string, data []byte) *Message {
+	m.Attachments = append(m.Attachments, Attachment{Filename: filename, Data: data})
+	return m
+}
+
+// Recipients 全部收件地址(不含名称), 包括抄送与密送
+func (m *Message) Recipients() ([]string, error) {
+	list := make([]string, 0, len(m.To)+len(m.Cc)+len(m.Bcc))
+	for _, group := range [][]string{m.To, m.Cc, m.Bcc} {
+		for _, s := range group {
+			addr, err := mail.ParseAddress(s)
+			if err != nil {
+				return nil, fmt.Errorf("mailer: invalid address %q: %w", s, err)
+			}
+			list = append(list, addr.Address)
+		}
+	}
+	if len(list) == 0 {
+		return nil, ErrNoRecipient
+	}
+	return list, nil
+}
+
+// Bytes 生成 RFC 5322 格式的邮件内容, 密送地址不写入邮件头
+func (m *Message) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	header := textproto.MIMEHeader{}
+	header.Set("From", formatAddress(m.From))
+	header.Set("To", formatAddressList(m.To))
+	if len(m.Cc) > 0 {
+		header.Set("Cc", formatAddressList(m.Cc))
+	}
+	if m.ReplyTo != "" {
+		header.Set("Reply-To", formatAddress(m.ReplyTo))
+	}
+	header.Set("Subject", mime.BEncoding.Encode("UTF-8", m.Subject))
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Message-Id", messageID(m.From))
+	header.Set("MIME-Version", "1.0")
+	for k, v := range m.Headers {
+		header.Set(k, v)
+	}
+
+	if len(m.Attachments) == 0 {
+		err := writeBody(func(h textproto.MIMEHeader) (io.Writer, error) {
+			for k, v := range h {
+				header[k] = v
+			}
+			writeHeader(&buf, header)
+			return &buf, nil
+		}, m)
+		if err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&buf)
+	header.Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+	writeHeader(&buf, header)
+	if err := writeBody(mw.CreatePart, m); err != nil {
+		return nil, err
+	}
+	for _, a := range m.Attachments {
+		contentType := a.ContentType
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(a.Filename))
+		}
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Type", contentType)
+		h.Set("Content-Transfer-Encoding", "base64")
+		filename := mime.BEncoding.Encode("UTF-8", a.Filename)
+		if a.Inline {
+			h.Set("Content-Disposition", `inline; filename="`+filename+`"`)
+			h.Set("Content-Id", "<"+a.Filename+">")
+		} else {
+			h.Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		}
+		w, err := mw.CreatePart(h)
+		if err != nil {
+			return nil, err
+		}
+		if err = writeBase64(w, a.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBody 写入正文, 同时有纯文本与 html 时为 multipart/alternative
+func writeBody(create func(textproto.MIMEHeader) (io.Writer, error), m *Message) error {
+	if m.Text == "" || m.HTML == "" {
+		body, contentType := m.Text, "text/plain; charset=UTF-8"
+		if m.HTML != "" {
+			body, contentType = m.HTML, "text/html; charset=UTF-8"
+		}
+		w, err := create(textPart(contentType))
+		if err != nil {
+			return err
+		}
+		return writeQuotedPrintable(w, body)
+	}
+	var body bytes.Buffer
+	aw := multipart.NewWriter(&body)
+	for _, part := range []struct{ body, contentType string }{
+		{m.Text, "text/plain; charset=UTF-8"},
+		{m.HTML, "text/html; charset=UTF-8"},
+	} {
+		w, err := aw.CreatePart(textPart(part.contentType))
+		if err != nil {
+			return err
+		}
+		if err = writeQuotedPrintable(w, part.body); err != nil {
+			return err
+		}
+	}
+	if err := aw.Close(); err != nil {
+		return err
+	}
+	w, err := create(textproto.MIMEHeader{"Content-Type": {"multipart/alternative; boundary=" + aw.Boundary()}})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(body.Bytes())
+	return err
+}
+
+func textPart(contentType string) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	}
+}
+
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	for k, vs := range header {
+		for _, v := range vs {
+			buf.WriteString(k + ": " + v + "\r\n")
+		}
+	}
+	buf.WriteString("\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qw := quotedprintable.NewWriter(w)
+	if _, err := io.WriteString(qw, s); err != nil {
+		return err
+	}
+	return qw.Close()
+}
+
+// writeBase64 base64 编码, 每行76个字符
+func writeBase64(w io.Writer, data []byte) error {
+	s := base64.StdEncoding.EncodeToString(data)
+	for len(s) > 76 {
+		if _, err := io.WriteString(w, s[:76]+"\r\n"); err != nil {
+			return err
+		}
+		s = s[76:]
+	}
+	_, err := io.WriteString(w, s+"\r\n")
+	return err
+}
+
+func formatAddress(s string) string {
+	addr, err := mail.ParseAddress(s)
+	if err != nil {
+		return s
+	}
+	return addr.String()
+}
+
+func formatAddressList(list []string) string {
+	formatted := make([]string, len(list))
+	for i, s := range list {
+		formatted[i] = formatAddress(s)
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if i := strings.LastIndexByte(addr.Address, '@'); i >= 0 {
+			domain = addr.Address[i+1:]
+		}
+	}
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
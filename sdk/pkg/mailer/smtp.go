@@ -0,0 +1,114 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPConfig SMTP 服务配置
+type SMTPConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// SSL 直接使用 TLS 连接, 一般为465端口; 否则在服务端支持时使用 STARTTLS
+	SSL bool `json:"ssl"`
+	// Timeout 连接超时, 默认10秒
+	Timeout time.Duration `json:"timeout"`
+}
+
+// SMTP 通过 SMTP 服务发送
+type SMTP struct {
+	c SMTPConfig
+}
+
+func NewSMTP(c SMTPConfig) *SMTP {
+	if c.Port == 0 {
+		c.Port = 25
+		if c.SSL {
+			c.Port = 465
+		}
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 10 * time.Second
+	}
+	return &SMTP{c: c}
+}
+
+func (*SMTP) String() string {
+	return "smtp"
+}
+
+func (s *SMTP) Send(ctx context.Context, m *Message) error {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return err
+	}
+	rcpt, err := m.Recipients()
+	if err != nil {
+		return err
+	}
+	raw, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.c.Host, strconv.Itoa(s.c.Port))
+	dialer := &net.Dialer{Timeout: s.c.Timeout}
+	var conn net.Conn
+	if s.c.SSL {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.c.Host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, s.c.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if !s.c.SSL {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err = c.StartTLS(&tls.Config{ServerName: s.c.Host}); err != nil {
+				return err
+			}
+		}
+	}
+	if s.c.Username != "" {
+		if ok, _ := c.Extension("AUTH"); ok {
+			if err = c.Auth(smtp.PlainAuth("", s.c.Username, s.c.Password, s.c.Host)); err != nil {
+				return err
+			}
+		}
+	}
+	if err = c.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, to := range rcpt {
+		if err = c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(raw); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
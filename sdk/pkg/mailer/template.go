@@ -0,0 +1,133 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Templates 邮件模板, 使用 html/template.
+// 布局文件定义 "layout" 模板, 并通过 {{block "content" .}} 引用页面内容;
+// 页面文件可以定义 "subject"、"content"、"text" 三个模板, 其中 "text" 为纯文本正文
+type Templates struct {
+	fsys   fs.FS
+	funcs  template.FuncMap
+	mux    sync.RWMutex
+	pages  map[string]*template.Template
+	layout string
+	page   string
+}
+
+// NewTemplates 从 fsys 加载模板, layout、page 为 fs.Glob 匹配规则, 如 "layouts/*.html"、"*.html";
+// 页面名为去掉扩展名的文件名
+func NewTemplates(fsys fs.FS, layout, page string, funcs ...template.FuncMap) (*Templates, error) {
+	t := &Templates{
+		fsys:   fsys,
+		funcs:  template.FuncMap{},
+		pages:  make(map[string]*template.Template),
+		layout: layout,
+		page:   page,
+	}
+	for _, f := range funcs {
+		for k, v := range f {
+			t.funcs[k] = v
+		}
+	}
+	return t, t.Reload()
+}
+
+// Reload 重新加载全部模板
+func (t *Templates) Reload() error {
+	base := template.New("").Funcs(t.funcs)
+	if t.layout != "" {
+		names, err := fs.Glob(t.fsys, t.layout)
+		if err != nil {
+			return err
+		}
+		if len(names) > 0 {
+			if base, err = base.ParseFS(t.fsys, names...); err != nil {
+				return err
+			}
+		}
+	}
+	names, err := fs.Glob(t.fsys, t.page)
+	if err != nil {
+		return err
+	}
+	pages := make(map[string]*template.Template, len(names))
+	for _, name := range names {
+		if matched, _ := path.Match(t.layout, name); matched {
+			continue
+		}
+		clone, err := base.Clone()
+		if err != nil {
+			return err
+		}
+		b, err := fs.ReadFile(t.fsys, name)
+		if err != nil {
+			return err
+		}
+		key := strings.TrimSuffix(path.Base(name), path.Ext(name))
+		if pages[key], err = clone.New(key).Parse(string(b)); err != nil {
+			return err
+		}
+	}
+	t.mux.Lock()
+	t.pages = pages
+	t.mux.Unlock()
+	return nil
+}
+
+// Names 已加载的页面
+func (t *Templates) Names() []string {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+	names := make([]string, 0, len(t.pages))
+	for name := range t.pages {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Render 渲染页面, 返回标题、html 正文与纯文本正文
+func (t *Templates) Render(name string, data interface{}) (subject, htmlBody, text string, err error) {
+	t.mux.RLock()
+	tpl, ok := t.pages[name]
+	t.mux.RUnlock()
+	if !ok {
+		return "", "", "", fmt.Errorf("mailer: template %s not found", name)
+	}
+	execute := func(name string) (string, error) {
+		if tpl.Lookup(name) == nil {
+			return "", nil
+		}
+		var buf bytes.Buffer
+		if err := tpl.ExecuteTemplate(&buf, name, data); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(buf.String()), nil
+	}
+	if subject, err = execute("subject"); err != nil {
+		return
+	}
+	// 标题不是 html, 还原转义的字符
+	subject = html.UnescapeString(subject)
+	if tpl.Lookup("layout") != nil {
+		htmlBody, err = execute("layout")
+	} else {
+		htmlBody, err = execute(name)
+	}
+	if err != nil {
+		return
+	}
+	if text, err = execute("text"); err != nil {
+		return
+	}
+	text = html.UnescapeString(text)
+	return
+}
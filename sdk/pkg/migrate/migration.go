@@ -0,0 +1,84 @@
+// Package migrate 数据库版本迁移, 迁移在代码中注册, 按版本号顺序执行并记录到历史表
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrLocked        = errors.New("migrate: another migration is running")
+	ErrNoDown        = errors.New("migrate: migration has no down")
+	ErrUnknownTarget = errors.New("migrate: unknown target version")
+)
+
+// Migration 一次迁移, Version 按字符串排序, 建议使用时间戳如 20221020153000
+type Migration struct {
+	Version string
+	Name    string
+	Up      func(tx *gorm.DB) error
+	// Down 回滚, 为nil时不可回滚
+	Down func(tx *gorm.DB) error
+}
+
+// History 已执行的迁移
+type History struct {
+	Version   string    `json:"version" gorm:"primaryKey;size:64"`
+	Name      string    `json:"name" gorm:"size:255"`
+	AppliedAt time.Time `json:"appliedAt"`
+	// Duration 执行耗时(毫秒)
+	Duration int64 `json:"duration"`
+}
+
+func (History) TableName() string {
+	return "sys_migration"
+}
+
+var (
+	registryMux sync.RWMutex
+	registry    = make(map[string]*Migration)
+)
+
+// Register 注册迁移, 一般在各模块的 init 中调用, 版本号重复时 panic
+func Register(list ...*Migration) {
+	registryMux.Lock()
+	defer registryMux.Unlock()
+	for _, m := range list {
+		if m.Version == "" || m.Up == nil {
+			panic("migrate: version and up are required")
+		}
+		if _, ok := registry[m.Version]; ok {
+			panic(fmt.Sprintf("migrate: duplicate version %s", m.Version))
+		}
+		registry[m.Version] = m
+	}
+}
+
+// Migrations 已注册的迁移, 按版本号排序
+func Migrations() []*Migration {
+	registryMux.RLock()
+	defer registryMux.RUnlock()
+	list := make([]*Migration, 0, len(registry))
+	for _, m := range registry {
+		list = append(list, m)
+	}
+	sortMigrations(list)
+	return list
+}
+
+func sortMigrations(list []*Migration) {
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+}
+
+func sortStatus(list []Status) {
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+}
@@ -0,0 +1,268 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bsm/redislock"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// LockKey 执行迁移时持有的分布式锁
+const LockKey = "migrate:lock"
+
+type Option func(*options)
+
+type options struct {
+	locker     storage.AdapterLocker
+	lockTTL    int64
+	migrations []*Migration
+}
+
+func setDefault() options {
+	return options{lockTTL: 600}
+}
+
+// WithLocker 多个实例同时启动时只有一个执行迁移, ttl 为锁的有效期(秒), 应大于迁移耗时
+func WithLocker(l storage.AdapterLocker, ttl int64) Option {
+	return func(o *options) {
+		o.locker = l
+		if ttl > 0 {
+			o.lockTTL = ttl
+		}
+	}
+}
+
+// WithMigrations 使用指定的迁移, 默认使用 Register 注册的全部迁移
+func WithMigrations(list ...*Migration) Option {
+	return func(o *options) {
+		o.migrations = list
+	}
+}
+
+// Status 迁移的执行状态
+type Status struct {
+	Version   string     `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt"`
+	// Missing 历史表中有记录但代码中未注册
+	Missing bool `json:"missing"`
+}
+
+// Plan 将要执行的迁移, dry-run 时 SQL 为 Up/Down 生成的语句
+type Plan struct {
+	Version string   `json:"version"`
+	Name    string   `json:"name"`
+	SQL     []string `json:"sql"`
+}
+
+// Migrator 执行迁移
+type Migrator struct {
+	db   *gorm.DB
+	opts options
+}
+
+func New(db *gorm.DB, opts ...Option) *Migrator {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.migrations == nil {
+		o.migrations = Migrations()
+	} else {
+		o.migrations = append([]*Migration(nil), o.migrations...)
+		sortMigrations(o.migrations)
+	}
+	return &Migrator{db: db, opts: o}
+}
+
+func (m *Migrator) history(ctx context.Context) (map[string]History, error) {
+	db := m.db.WithContext(ctx)
+	if err := db.AutoMigrate(&History{}); err != nil {
+		return nil, err
+	}
+	list := make([]History, 0)
+	if err := db.Order("version").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[string]History, len(list))
+	for _, h := range list {
+		applied[h.Version] = h
+	}
+	return applied, nil
+}
+
+// Status 全部迁移的状态, 按版本号排序
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.history(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Status, 0, len(m.opts.migrations))
+	for _, mg := range m.opts.migrations {
+		s := Status{Version: mg.Version, Name: mg.Name}
+		if h, ok := applied[mg.Version]; ok {
+			at := h.AppliedAt
+			s.Applied, s.AppliedAt = true, &at
+			delete(applied, mg.Version)
+		}
+		list = append(list, s)
+	}
+	for _, h := range applied {
+		at := h.AppliedAt
+		list = append(list, Status{Version: h.Version, Name: h.Name, Applied: true, AppliedAt: &at, Missing: true})
+	}
+	sortStatus(list)
+	return list, nil
+}
+
+// Up 执行未执行的迁移直到 target(含), target 为空时执行全部; dryRun 时只返回计划与生成的SQL
+func (m *Migrator) Up(ctx context.Context, target string, dryRun bool) ([]Plan, error) {
+	if target != "" && m.find(target) == nil {
+		return nil, ErrUnknownTarget
+	}
+	return m.locked(ctx, dryRun, func(applied map[string]History) ([]Plan, error) {
+		plans := make([]Plan, 0)
+		for _, mg := range m.opts.migrations {
+			if target != "" && mg.Version > target {
+				break
+			}
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+			plan, err := m.apply(ctx, mg, mg.Up, dryRun, true)
+			if err != nil {
+				return plans, err
+			}
+			plans = append(plans, plan)
+		}
+		return plans, nil
+	})
+}
+
+// Down 按版本号倒序回滚最近执行的 steps 个迁移
+func (m *Migrator) Down(ctx context.Context, steps int, dryRun bool) ([]Plan, error) {
+	return m.locked(ctx, dryRun, func(applied map[string]History) ([]Plan, error) {
+		plans := make([]Plan, 0, steps)
+		for i := len(m.opts.migrations) - 1; i >= 0 && len(plans) < steps; i-- {
+			mg := m.opts.migrations[i]
+			if _, ok := applied[mg.Version]; !ok {
+				continue
+			}
+			if mg.Down == nil {
+				return plans, ErrNoDown
+			}
+			plan, err := m.apply(ctx, mg, mg.Down, dryRun, false)
+			if err != nil {
+				return plans, err
+			}
+			plans = append(plans, plan)
+		}
+		return plans, nil
+	})
+}
+
+func (m *Migrator) find(version string) *Migration {
+	for _, mg := range m.opts.migrations {
+		if mg.Version == version {
+			return mg
+		}
+	}
+	return nil
+}
+
+// locked 持有分布式锁时执行 f, dry-run 不加锁
+func (m *Migrator) locked(ctx context.Context, dryRun bool,
+	f func(applied map[string]History) ([]Plan, error)) ([]Plan, error) {
+	if m.opts.locker != nil && !dryRun {
+		lock, err := m.opts.locker.Lock(LockKey, m.opts.lockTTL, nil)
+		if err != nil {
+			if errors.Is(err, redislock.ErrNotObtained) {
+				return nil, ErrLocked
+			}
+			return nil, err
+		}
+		if lock != nil {
+			defer func() {
+				_ = lock.Release(context.Background())
+			}()
+		}
+	}
+	applied, err := m.history(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return f(applied)
+}
+
+// apply 在事务中执行迁移并更新历史表
+func (m *Migrator) apply(ctx context.Context, mg *Migration, f func(*gorm.DB) error, dryRun, up bool) (Plan, error) {
+	plan := Plan{Version: mg.Version, Name: mg.Name}
+	log := logger.Module("sdk.migrate").WithContext(ctx).With("version", mg.Version, "name", mg.Name, "up", up)
+	if dryRun {
+		rec := &recorder{Interface: m.db.Logger}
+		err := f(m.db.Session(&gorm.Session{DryRun: true, Logger: rec, Context: ctx}))
+		plan.SQL = rec.sql
+		return plan, err
+	}
+	start := time.Now()
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := f(tx); err != nil {
+			return err
+		}
+		if !up {
+			return tx.Delete(&History{}, "version = ?", mg.Version).Error
+		}
+		return tx.Create(&History{
+			Version:   mg.Version,
+			Name:      mg.Name,
+			AppliedAt: start,
+			Duration:  time.Since(start).Milliseconds(),
+		}).Error
+	})
+	if err != nil {
+		log.Error("migration failed", "error", err)
+		return plan, err
+	}
+	log.Info("migration applied", "duration", time.Since(start).Milliseconds())
+	return plan, nil
+}
+
+// recorder 记录 dry-run 生成的SQL
+type recorder struct {
+	gormLogger.Interface
+	sql []string
+}
+
+func (r *recorder) LogMode(gormLogger.LogLevel) gormLogger.Interface {
+	return r
+}
+
+func (r *recorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	r.sql = append(r.sql, sql)
+}
+
+// Plugin 在 gorm 初始化时执行全部未执行的迁移, db.Use(migrate.Plugin())
+func Plugin(opts ...Option) gorm.Plugin {
+	return &plugin{opts: opts}
+}
+
+type plugin struct {
+	opts []Option
+}
+
+func (*plugin) Name() string {
+	return "migrate"
+}
+
+func (p *plugin) Initialize(db *gorm.DB) error {
+	_, err := New(db, p.opts...).Up(context.Background(), "", false)
+	return err
+}
@@ -0,0 +1,135 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bsm/redislock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+type memoryLocker struct {
+	mux    sync.Mutex
+	locked bool
+}
+
+func (*memoryLocker) String() string { return "memory" }
+
+func (l *memoryLocker) Lock(string, int64, *redislock.Options) (*redislock.Lock, error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if l.locked {
+		return nil, redislock.ErrNotObtained
+	}
+	l.locked = true
+	return nil, nil
+}
+
+func newDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func testMigrations() []*Migration {
+	return []*Migration{
+		{
+			Version: "20221020000002",
+			Name:    "seed user",
+			Up: func(tx *gorm.DB) error {
+				return tx.Create(&user{ID: 1, Name: "admin"}).Error
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Delete(&user{}, 1).Error
+			},
+		},
+		{
+			Version: "20221020000001",
+			Name:    "create user",
+			Up: func(tx *gorm.DB) error {
+				return tx.Migrator().CreateTable(&user{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&user{})
+			},
+		},
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	db := newDB(t)
+	ctx := context.Background()
+	m := New(db, WithMigrations(testMigrations()...))
+
+	plans, err := m.Up(ctx, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plans) != 2 || plans[0].Version != "20221020000001" || len(plans[0].SQL) == 0 ||
+		!strings.Contains(plans[0].SQL[0], "CREATE TABLE") {
+		t.Fatalf("plans = %+v", plans)
+	}
+	if db.Migrator().HasTable(&user{}) {
+		t.Fatal("dry run should not create table")
+	}
+
+	if plans, err = m.Up(ctx, "20221020000001", false); err != nil || len(plans) != 1 {
+		t.Fatalf("plans = %+v, err = %v", plans, err)
+	}
+	if plans, err = m.Up(ctx, "", false); err != nil || len(plans) != 1 {
+		t.Fatalf("plans = %+v, err = %v", plans, err)
+	}
+	status, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 2 || !status[0].Applied || !status[1].Applied {
+		t.Errorf("status = %+v", status)
+	}
+
+	if _, err = m.Down(ctx, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	db.Model(&user{}).Count(&count)
+	if count != 0 {
+		t.Errorf("count = %d", count)
+	}
+	if status, _ = m.Status(ctx); status[1].Applied {
+		t.Errorf("status = %+v", status)
+	}
+	if _, err = m.Up(ctx, "unknown", false); !errors.Is(err, ErrUnknownTarget) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestLocked(t *testing.T) {
+	l := &memoryLocker{locked: true}
+	_, err := New(newDB(t), WithMigrations(testMigrations()...), WithLocker(l, 0)).Up(context.Background(), "", false)
+	if !errors.Is(err, ErrLocked) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	db := newDB(t)
+	if err := db.Use(Plugin(WithMigrations(testMigrations()...))); err != nil {
+		t.Fatal(err)
+	}
+	var u user
+	if err := db.First(&u, 1).Error; err != nil || u.Name != "admin" {
+		t.Errorf("user = %+v, err = %v", u, err)
+	}
+}
@@ -0,0 +1,19 @@
+package oplock
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+)
+
+// Handler 查询操作锁的持有人, 路径参数 name 为锁名, 未被锁定时 data 为 null; 需自行挂载到有鉴权的路由
+func (m *Manager) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h, err := m.Holder(c.Request.Context(), c.Param("name"))
+		if err != nil {
+			response.Fail(c, err)
+			return
+		}
+		response.OK(c, h, "")
+	}
+}
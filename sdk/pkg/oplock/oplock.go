@@ -0,0 +1,352 @@
+// Package oplock 维护操作的互斥锁(如 "migration"、"bulk-delete"), 基于分布式锁,
+// 记录持有人便于提示, 加锁与释放自动写入审计
+//
+//	m := oplock.New(sdk.Runtime.GetLockerPrefix(""), sdk.Runtime.GetCacheAdapter())
+//	err := m.Do(ctx, "bulk-delete", "清理过期订单", func(ctx context.Context) error {
+//		// 同一 ctx 内再次对 bulk-delete 加锁直接进入
+//		return cleanup(ctx)
+//	})
+//	var le *oplock.LockedError
+//	if errors.As(err, &le) {
+//		// le.Holder.OperatorID 正在执行 le.Holder.Reason
+//	}
+package oplock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bsm/redislock"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/audit"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// 审计记录的操作类型, 资源为 Resource, 资源id为锁名
+const (
+	ActionLock   = "lock"
+	ActionUnlock = "unlock"
+	Resource     = "oplock"
+)
+
+var (
+	// ErrLocked 操作已被他人锁定, 实际返回 *LockedError
+	ErrLocked = errors.New("oplock: operation is locked")
+	// ErrNotHeld 释放未持有的锁
+	ErrNotHeld = errors.New("oplock: lock not held")
+)
+
+func init() {
+	response.RegisterError(ErrLocked, response.NewError(409, http.StatusConflict, "oplock.locked", "该操作正在由其他人执行, 请稍后再试"))
+}
+
+// Holder 锁的持有人
+type Holder struct {
+	Name       string    `json:"name"`
+	OperatorID string    `json:"operatorId,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	Host       string    `json:"host,omitempty"`
+	RequestID  string    `json:"requestId,omitempty"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+}
+
+func (h *Holder) String() string {
+	s := h.Name + " is held"
+	if h.OperatorID != "" {
+		s += " by " + h.OperatorID
+	}
+	if h.Reason != "" {
+		s += " (" + h.Reason + ")"
+	}
+	return s + " since " + h.AcquiredAt.Format(time.RFC3339)
+}
+
+// LockedError 加锁失败, Holder 为当前持有人, 持有人信息已过期时为 nil
+type LockedError struct {
+	Name   string
+	Holder *Holder
+}
+
+func (e *LockedError) Error() string {
+	if e.Holder == nil {
+		return fmt.Sprintf("%s: %s", ErrLocked, e.Name)
+	}
+	return fmt.Sprintf("%s: %s", ErrLocked, e.Holder)
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrLocked
+}
+
+type Option func(*options)
+
+type options struct {
+	prefix string
+	ttl    time.Duration
+	audit  bool
+}
+
+func setDefault() options {
+	return options{
+		prefix: "oplock:",
+		ttl:    time.Minute,
+		audit:  true,
+	}
+}
+
+// WithPrefix 锁与持有人信息的key前缀, 默认 oplock:, 锁为 <prefix>lock:<name>, 持有人信息为 <prefix>holder:<name>
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithTTL 锁的有效期, 持有期间每 1/3 有效期自动续期, 进程异常退出后最多经过该时间自动释放, 默认1分钟;
+// 按秒向上取整, 最小为1秒
+func WithTTL(d time.Duration) Option {
+	return func(o *options) {
+		o.ttl = d
+	}
+}
+
+// WithoutAudit 不写入审计
+func WithoutAudit() Option {
+	return func(o *options) {
+		o.audit = false
+	}
+}
+
+// Manager 操作锁
+type Manager struct {
+	locker storage.AdapterLocker
+	cache  storage.AdapterCache
+	o      options
+	// 未配置 Locker 时在本进程内互斥
+	mux   sync.Mutex
+	local map[string]bool
+}
+
+// New locker 为空时只在本进程内互斥, cache 保存持有人信息
+func New(locker storage.AdapterLocker, cache storage.AdapterCache, opts ...Option) *Manager {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	// 锁与持有人信息的有效期以秒为单位
+	if o.ttl < time.Second {
+		o.ttl = time.Second
+	}
+	o.ttl = (o.ttl + time.Second - 1).Truncate(time.Second)
+	return &Manager{locker: locker, cache: cache, o: o, local: make(map[string]bool)}
+}
+
+// Lock 持有的锁
+type Lock struct {
+	m      *Manager
+	holder *Holder
+	lock   *redislock.Lock
+	mux    sync.Mutex
+	depth  int
+	stop   chan struct{}
+	cancel context.CancelFunc
+}
+
+type heldKey struct{}
+
+// held ctx 中已持有的锁
+type held map[string]*Lock
+
+// Acquire 加锁, 返回的 ctx 记录了持有的锁, 用该 ctx 再次对同名操作加锁时直接返回同一个 Lock(可重入), 需要对应次数的 Release;
+// 续期失败(锁可能已被他人获得)或最外层 Release 后返回的 ctx 被取消
+func (m *Manager) Acquire(ctx context.Context, name, reason string) (context.Context, *Lock, error) {
+	if h, ok := ctx.Value(heldKey{}).(held); ok {
+		if l := h[name]; l != nil && l.m == m {
+			l.mux.Lock()
+			defer l.mux.Unlock()
+			if l.depth > 0 {
+				l.depth++
+				return ctx, l, nil
+			}
+		}
+	}
+
+	lock, err := m.lock(name)
+	if err != nil {
+		if errors.Is(err, redislock.ErrNotObtained) {
+			h, _ := m.Holder(ctx, name)
+			return ctx, nil, &LockedError{Name: name, Holder: h}
+		}
+		return ctx, nil, err
+	}
+	host, _ := os.Hostname()
+	l := &Lock{
+		m: m,
+		holder: &Holder{
+			Name:       name,
+			OperatorID: logger.OperatorID(ctx),
+			Reason:     reason,
+			Host:       host,
+			RequestID:  logger.RequestID(ctx),
+			AcquiredAt: time.Now(),
+		},
+		lock:  lock,
+		depth: 1,
+		stop:  make(chan struct{}),
+	}
+	ctx, l.cancel = context.WithCancel(ctx)
+	m.saveHolder(ctx, l.holder)
+	go l.refresh()
+	logger.Module("sdk.oplock").WithContext(ctx).Info("operation locked", "name", name, "reason", reason)
+	m.audit(ctx, ActionLock, nil, l.holder)
+
+	h := held{}
+	if parent, ok := ctx.Value(heldKey{}).(held); ok {
+		for k, v := range parent {
+			h[k] = v
+		}
+	}
+	h[name] = l
+	return context.WithValue(ctx, heldKey{}, h), l, nil
+}
+
+// Do 持有锁时执行 f, 结束后释放
+func (m *Manager) Do(ctx context.Context, name, reason string, f func(ctx context.Context) error) (err error) {
+	ctx, l, err := m.Acquire(ctx, name, reason)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rerr := l.Release(ctx); err == nil {
+			err = rerr
+		}
+	}()
+	return f(ctx)
+}
+
+// Holder 查询持有人, 未被锁定时返回 nil
+func (m *Manager) Holder(_ context.Context, name string) (*Holder, error) {
+	if m.cache == nil {
+		return nil, nil
+	}
+	v, err := m.cache.Get(m.holderKey(name))
+	if err != nil || v == "" {
+		return nil, nil
+	}
+	h := &Holder{}
+	if err = json.Unmarshal([]byte(v), h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Holder 持有人信息
+func (l *Lock) Holder() Holder {
+	return *l.holder
+}
+
+// Release 释放锁, 可重入时最外层的 Release 才真正释放
+func (l *Lock) Release(ctx context.Context) error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if l.depth == 0 {
+		return ErrNotHeld
+	}
+	l.depth--
+	if l.depth > 0 {
+		return nil
+	}
+	close(l.stop)
+	defer l.cancel()
+	m := l.m
+	var err error
+	if l.lock != nil {
+		err = l.lock.Release(context.Background())
+		if errors.Is(err, redislock.ErrLockNotHeld) {
+			err = ErrNotHeld
+		}
+	} else if m.locker == nil {
+		m.mux.Lock()
+		delete(m.local, l.holder.Name)
+		m.mux.Unlock()
+	}
+	// 锁已过期时持有人信息可能属于新的持有人, 不删除
+	if err == nil && m.cache != nil {
+		_ = m.cache.Del(m.holderKey(l.holder.Name))
+	}
+	logger.Module("sdk.oplock").WithContext(ctx).Info("operation unlocked",
+		"name", l.holder.Name, "duration", time.Since(l.holder.AcquiredAt).String())
+	m.audit(ctx, ActionUnlock, l.holder, nil)
+	return err
+}
+
+// refresh 定期续期锁与持有人信息, 直到 Release; 续期失败时取消操作的 ctx
+func (l *Lock) refresh() {
+	ticker := time.NewTicker(l.m.o.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			if l.lock == nil {
+				l.m.saveHolder(context.Background(), l.holder)
+				continue
+			}
+			if err := l.lock.Refresh(context.Background(), l.m.o.ttl, nil); err != nil {
+				logger.Module("sdk.oplock").Error("refresh lock failed, operation canceled", "name", l.holder.Name, "error", err)
+				l.cancel()
+				return
+			}
+			l.m.saveHolder(context.Background(), l.holder)
+		}
+	}
+}
+
+func (m *Manager) lock(name string) (*redislock.Lock, error) {
+	if m.locker != nil {
+		return m.locker.Lock(m.o.prefix+"lock:"+name, int64(m.o.ttl/time.Second), nil)
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.local[name] {
+		return nil, redislock.ErrNotObtained
+	}
+	m.local[name] = true
+	return nil, nil
+}
+
+func (m *Manager) holderKey(name string) string {
+	return m.o.prefix + "holder:" + name
+}
+
+func (m *Manager) saveHolder(ctx context.Context, h *Holder) {
+	if m.cache == nil {
+		return
+	}
+	b, _ := json.Marshal(h)
+	if err := m.cache.Set(m.holderKey(h.Name), string(b), int(m.o.ttl/time.Second)); err != nil {
+		logger.Module("sdk.oplock").WithContext(ctx).Warn("save holder failed", "name", h.Name, "error", err)
+	}
+}
+
+// audit 写入审计, 未设置 Sink 时跳过
+func (m *Manager) audit(ctx context.Context, action string, before, after *Holder) {
+	if !m.o.audit || audit.GetSink() == nil {
+		return
+	}
+	name := ""
+	if before != nil {
+		name = before.Name
+	} else if after != nil {
+		name = after.Name
+	}
+	_ = audit.Log(ctx, action, Resource, name, before, after)
+}
@@ -0,0 +1,134 @@
+package oplock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bsm/redislock"
+	"github.com/go-redis/redis/v8"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/audit"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+type memorySink struct {
+	records []*audit.Record
+}
+
+func (*memorySink) String() string { return "memory" }
+
+func (s *memorySink) Write(_ context.Context, r *audit.Record) error {
+	s.records = append(s.records, r)
+	return nil
+}
+
+func TestDo(t *testing.T) {
+	sink := &memorySink{}
+	audit.SetSink(sink)
+	defer audit.SetSink(nil)
+
+	m := New(nil, cache.NewMemory())
+	alice := logger.WithOperatorID(context.Background(), "alice")
+	bob := logger.WithOperatorID(context.Background(), "bob")
+
+	err := m.Do(alice, "migration", "升级表结构", func(ctx context.Context) error {
+		// 同一 ctx 可重入
+		if err := m.Do(ctx, "migration", "", func(context.Context) error { return nil }); err != nil {
+			t.Errorf("reentrant: err = %v", err)
+		}
+		h, _ := m.Holder(ctx, "migration")
+		if h == nil || h.OperatorID != "alice" || h.Reason != "升级表结构" {
+			t.Errorf("holder = %+v", h)
+		}
+		err := m.Do(bob, "migration", "", func(context.Context) error { return nil })
+		var le *LockedError
+		if !errors.As(err, &le) || !errors.Is(err, ErrLocked) || le.Holder.OperatorID != "alice" {
+			t.Errorf("conflict: err = %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h, _ := m.Holder(alice, "migration"); h != nil {
+		t.Errorf("holder after release = %+v", h)
+	}
+	if err = m.Do(bob, "migration", "", func(context.Context) error { return nil }); err != nil {
+		t.Errorf("after release: err = %v", err)
+	}
+
+	if len(sink.records) != 4 || sink.records[0].Action != ActionLock || sink.records[1].Action != ActionUnlock ||
+		sink.records[0].ResourceID != "migration" || sink.records[0].OperatorID != "alice" {
+		t.Errorf("records = %+v", sink.records)
+	}
+
+	_, l, _ := m.Acquire(alice, "bulk-delete", "")
+	_ = l.Release(alice)
+	if err = l.Release(alice); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("double release: err = %v", err)
+	}
+}
+
+// redisLocker 与 locker.Redis 相同, 直接使用 redislock 依赖的 redis 客户端
+type redisLocker struct {
+	c *redislock.Client
+}
+
+func (redisLocker) String() string { return "redis" }
+
+func (l redisLocker) Lock(key string, ttl int64, options *redislock.Options) (*redislock.Lock, error) {
+	return l.c.Obtain(context.Background(), key, time.Duration(ttl)*time.Second, options)
+}
+
+func TestRedisLock(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	c := cache.NewMemory()
+	// 不足1秒的有效期按1秒处理
+	m := New(redisLocker{redislock.New(client)}, c, WithTTL(time.Millisecond), WithoutAudit())
+	alice := logger.WithOperatorID(context.Background(), "alice")
+	bob := logger.WithOperatorID(context.Background(), "bob")
+
+	// 名为 holder:x 的锁与 x 的持有人信息不冲突
+	_, x, err := m.Acquire(alice, "x", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, hx, err := m.Acquire(alice, "holder:x", "")
+	if err != nil {
+		t.Fatalf("holder:x: err = %v", err)
+	}
+	if ttl := s.TTL("oplock:lock:x"); ttl != time.Second {
+		t.Errorf("ttl = %s", ttl)
+	}
+	_ = hx.Release(alice)
+	_ = x.Release(alice)
+
+	// 锁丢失后续期失败, 操作的 ctx 被取消
+	ctx, l, err := m.Acquire(alice, "migration", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Del("oplock:lock:migration")
+	select {
+	case <-ctx.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("ctx not canceled after losing the lock")
+	}
+	// 他人获得锁后, 释放已丢失的锁不删除新持有人的信息
+	_, b, err := m.Acquire(bob, "migration", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = l.Release(alice); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("release lost lock: err = %v", err)
+	}
+	if h, _ := m.Holder(bob, "migration"); h == nil || h.OperatorID != "bob" {
+		t.Errorf("holder = %+v", h)
+	}
+	_ = b.Release(bob)
+}
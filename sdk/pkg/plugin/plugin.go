@@ -0,0 +1,226 @@
+// Package plugin 可选模块(如 CMS、监控)的注册框架, 模块可以是独立的 Go module,
+// 在 init 中 Register 后由应用统一初始化、挂载路由、注册迁移、配置、队列消费者和定时任务
+//
+//	func init() {
+//		plugin.Register(&cms.Plugin{})
+//	}
+//
+//	// main: config.Setup 之后, Runtime.Start 之前
+//	if err := plugin.Init(ctx, sdk.Runtime); err != nil { ... }
+//	plugin.Mount(r.Group("/api/v1"))
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/config"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/cronjob"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/migrate"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/recovery"
+	"github.com/go-admin-team/go-admin-core/sdk/runtime"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+var (
+	ErrMissingDependency = errors.New("plugin: missing dependency")
+	ErrCycle             = errors.New("plugin: dependency cycle")
+	ErrNoQueue           = errors.New("plugin: queue adapter not set")
+)
+
+// Plugin 插件, 名称全局唯一; 按需实现下面的接口
+type Plugin interface {
+	Name() string
+}
+
+// Dependent 依赖的插件, 依赖先初始化、先挂载路由
+type Dependent interface {
+	Dependencies() []string
+}
+
+// Initializer 配置与 Runtime 就绪后初始化, 可以读取 config.GetExtend、Runtime 中的 db、缓存等
+type Initializer interface {
+	Init(ctx context.Context, rt runtime.Runtime) error
+}
+
+// Router 挂载路由
+type Router interface {
+	Routes(r gin.IRouter)
+}
+
+// Migrator 数据库迁移, Register 时注册到 migrate
+type Migrator interface {
+	Migrations() []*migrate.Migration
+}
+
+// Configurer 扩展配置, key 对应 settings.extend.<key>, Register 时注册到 config.RegisterExtend
+type Configurer interface {
+	ConfigSections() map[string]interface{}
+}
+
+// Consumer 队列消费者, key 为 stream, Init 时以 recovery.Consumer 包装后注册到 Runtime 的队列
+type Consumer interface {
+	Consumers() map[string]storage.ConsumerFunc
+}
+
+// CronHandlers 定时任务处理函数, key 对应任务的 Target, Register 时注册到 cronjob
+type CronHandlers interface {
+	CronHandlers() map[string]cronjob.Handler
+}
+
+// 插件同时实现 runtime.Component 时, Init 后以 runtime.OrderPlugin 加入生命周期管理
+
+var (
+	mux         sync.Mutex
+	registry    = make(map[string]Plugin)
+	initialized = make(map[string]bool)
+)
+
+// Register 注册插件, 在 init 中调用; 配置、迁移、定时任务处理函数在此时注册, 名称重复时 panic
+func Register(p Plugin) {
+	mux.Lock()
+	defer mux.Unlock()
+	name := p.Name()
+	if name == "" {
+		panic("plugin: name is required")
+	}
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("plugin: duplicate plugin %s", name))
+	}
+	registry[name] = p
+	if c, ok := p.(Configurer); ok {
+		for key, v := range c.ConfigSections() {
+			config.RegisterExtend(key, v)
+		}
+	}
+	if m, ok := p.(Migrator); ok {
+		migrate.Register(m.Migrations()...)
+	}
+	if c, ok := p.(CronHandlers); ok {
+		for name, h := range c.CronHandlers() {
+			cronjob.Register(name, h)
+		}
+	}
+}
+
+// Get 获取已注册的插件
+func Get(name string) (Plugin, bool) {
+	mux.Lock()
+	defer mux.Unlock()
+	p, ok := registry[name]
+	return p, ok
+}
+
+// List 按依赖排序的插件, 没有依赖关系的按名称排序, 与注册顺序无关
+func List() ([]Plugin, error) {
+	mux.Lock()
+	defer mux.Unlock()
+	return sorted()
+}
+
+func sorted() ([]Plugin, error) {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]Plugin, 0, len(names))
+	// state 0 未访问, 1 访问中, 2 已完成
+	state := make(map[string]int, len(names))
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("%w: %v", ErrCycle, append(path, name))
+		case 2:
+			return nil
+		}
+		state[name] = 1
+		path = append(path[:len(path):len(path)], name)
+		p := registry[name]
+		if d, ok := p.(Dependent); ok {
+			deps := append([]string(nil), d.Dependencies()...)
+			sort.Strings(deps)
+			for _, dep := range deps {
+				if _, ok := registry[dep]; !ok {
+					return fmt.Errorf("%w: %s requires %s", ErrMissingDependency, name, dep)
+				}
+				if err := visit(dep, path); err != nil {
+					return err
+				}
+			}
+		}
+		state[name] = 2
+		list = append(list, p)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// Init 按依赖顺序初始化插件, 注册队列消费者并加入生命周期管理;
+// 需在 config.Setup 之后、rt.Start 之前调用, 已初始化的插件不再重复初始化
+func Init(ctx context.Context, rt runtime.Runtime) error {
+	mux.Lock()
+	defer mux.Unlock()
+	list, err := sorted()
+	if err != nil {
+		return err
+	}
+	for _, p := range list {
+		name := p.Name()
+		if initialized[name] {
+			continue
+		}
+		if i, ok := p.(Initializer); ok {
+			if err = i.Init(ctx, rt); err != nil {
+				return fmt.Errorf("plugin %s init: %w", name, err)
+			}
+		}
+		if c, ok := p.(Consumer); ok {
+			q := rt.GetQueueAdapter()
+			if rq, ok := q.(*runtime.Queue); q == nil || ok && rq.Unwrap() == nil {
+				return fmt.Errorf("plugin %s: %w", name, ErrNoQueue)
+			}
+			consumers := c.Consumers()
+			streams := make([]string, 0, len(consumers))
+			for stream := range consumers {
+				streams = append(streams, stream)
+			}
+			sort.Strings(streams)
+			for _, stream := range streams {
+				q.Register(stream, recovery.Consumer(stream, consumers[stream]))
+			}
+		}
+		if c, ok := p.(runtime.Component); ok {
+			rt.AddComponent(runtime.OrderPlugin, c)
+		}
+		initialized[name] = true
+		logger.Module("sdk.plugin").WithContext(ctx).Info("plugin initialized", "plugin", name)
+	}
+	return nil
+}
+
+// Mount 按依赖顺序挂载插件路由, r 一般为需要鉴权的路由组
+func Mount(r gin.IRouter) error {
+	list, err := List()
+	if err != nil {
+		return err
+	}
+	for _, p := range list {
+		if router, ok := p.(Router); ok {
+			router.Routes(r)
+		}
+	}
+	return nil
+}
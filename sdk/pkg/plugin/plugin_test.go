@@ -0,0 +1,169 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/go-admin-team/go-admin-core/sdk/config"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/cronjob"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/migrate"
+	"github.com/go-admin-team/go-admin-core/sdk/runtime"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+type fake struct {
+	name    string
+	deps    []string
+	initLog *[]string
+}
+
+func (f *fake) Name() string           { return f.name }
+func (f *fake) Dependencies() []string { return f.deps }
+
+func (f *fake) Init(context.Context, runtime.Runtime) error {
+	*f.initLog = append(*f.initLog, f.name)
+	return nil
+}
+
+func (f *fake) Routes(r gin.IRouter) {
+	r.GET("/"+f.name, func(c *gin.Context) { c.String(http.StatusOK, f.name) })
+}
+
+type cmsConfig struct {
+	Title string `json:"title"`
+}
+
+// full 实现全部扩展接口
+type full struct {
+	fake
+	cfg *cmsConfig
+}
+
+func (f *full) ConfigSections() map[string]interface{} {
+	return map[string]interface{}{"cms": f.cfg}
+}
+
+func (f *full) Migrations() []*migrate.Migration {
+	return []*migrate.Migration{{Version: "plugin-test-1", Up: func(*gorm.DB) error { return nil }}}
+}
+
+func (f *full) Consumers() map[string]storage.ConsumerFunc {
+	return map[string]storage.ConsumerFunc{"cms.publish": func(storage.Messager) error { return nil }}
+}
+
+func (f *full) CronHandlers() map[string]cronjob.Handler {
+	return map[string]cronjob.Handler{"cms.sitemap": func(context.Context, *cronjob.Job) error { return nil }}
+}
+
+func (f *full) Start(context.Context) error { return nil }
+func (f *full) Stop(context.Context) error  { return nil }
+
+func reset() {
+	registry = make(map[string]Plugin)
+	initialized = make(map[string]bool)
+}
+
+func TestOrder(t *testing.T) {
+	defer reset()
+	var log []string
+	Register(&fake{name: "monitor", deps: []string{"cms", "auth"}, initLog: &log})
+	Register(&fake{name: "cms", deps: []string{"auth"}, initLog: &log})
+	Register(&fake{name: "auth", initLog: &log})
+	Register(&fake{name: "blog", initLog: &log})
+	list, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range list {
+		names = append(names, p.Name())
+	}
+	if strings.Join(names, ",") != "auth,blog,cms,monitor" {
+		t.Errorf("order = %v", names)
+	}
+
+	rt := runtime.NewConfig()
+	if err = Init(context.Background(), rt); err != nil {
+		t.Fatal(err)
+	}
+	Register(&fake{name: "late", initLog: &log})
+	if err = Init(context.Background(), rt); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(log, ",") != "auth,blog,cms,monitor,late" {
+		t.Errorf("init = %v", log)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	if err = Mount(r.Group("/api")); err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/cms", nil))
+	if w.Body.String() != "cms" {
+		t.Errorf("body = %s", w.Body.String())
+	}
+}
+
+func TestDependencyError(t *testing.T) {
+	defer reset()
+	var log []string
+	Register(&fake{name: "a", deps: []string{"b"}, initLog: &log})
+	Register(&fake{name: "b", deps: []string{"a"}, initLog: &log})
+	if _, err := List(); !errors.Is(err, ErrCycle) {
+		t.Errorf("cycle: err = %v", err)
+	}
+	reset()
+	Register(&fake{name: "a", deps: []string{"missing"}, initLog: &log})
+	if err := Init(context.Background(), runtime.NewConfig()); !errors.Is(err, ErrMissingDependency) || len(log) != 0 {
+		t.Errorf("missing: err = %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("duplicate should panic")
+		}
+	}()
+	Register(&fake{name: "a", initLog: &log})
+}
+
+func TestExtensions(t *testing.T) {
+	defer reset()
+	var log []string
+	p := &full{fake: fake{name: "cms", initLog: &log}, cfg: &cmsConfig{}}
+	Register(p)
+	if config.GetExtend("cms") != p.cfg {
+		t.Error("config section not registered")
+	}
+	var found bool
+	for _, m := range migrate.Migrations() {
+		found = found || m.Version == "plugin-test-1"
+	}
+	if !found {
+		t.Error("migration not registered")
+	}
+	found = false
+	for _, name := range cronjob.Handlers() {
+		found = found || name == "cms.sitemap"
+	}
+	if !found {
+		t.Error("cron handler not registered")
+	}
+
+	rt := runtime.NewConfig()
+	if err := Init(context.Background(), rt); !errors.Is(err, ErrNoQueue) {
+		t.Errorf("no queue: err = %v", err)
+	}
+	rt.SetQueueAdapter(queue.NewMemory(10))
+	if err := Init(context.Background(), rt); err != nil {
+		t.Fatal(err)
+	}
+}
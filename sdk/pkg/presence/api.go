@@ -0,0 +1,86 @@
+package presence
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/jwtauth"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+)
+
+// SessionID 当前请求的会话id, 即 jwt 的 jti
+func SessionID(c *gin.Context) string {
+	return jwtauth.ClaimsFromContext(c.Request.Context()).String("jti")
+}
+
+// Middleware 挂在 jwt 鉴权之后, 拒绝被强制下线的会话, 并按 WithTouchInterval 刷新心跳; 未登记的会话直接放行
+func (m *Manager) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := SessionID(c)
+		if id == "" {
+			c.Next()
+			return
+		}
+		s, err := m.Get(c.Request.Context(), id)
+		switch {
+		case errors.Is(err, ErrKicked):
+			response.Fail(c, err)
+			c.Abort()
+			return
+		case err == nil && time.Since(s.LastSeen) >= m.o.touchInterval:
+			s.LastSeen = time.Now()
+			_ = m.save(s)
+		}
+		c.Next()
+	}
+}
+
+// HeartbeatHandler 前端定时调用, 刷新当前会话
+func (m *Manager) HeartbeatHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s, err := m.Heartbeat(c.Request.Context(), SessionID(c))
+		if err != nil {
+			response.Fail(c, err)
+			return
+		}
+		response.OK(c, s, "")
+	}
+}
+
+// Routes 在线用户管理接口, 需自行挂载到有鉴权的路由
+//
+//	GET    ""               在线会话列表, 查询参数 userId 筛选账号
+//	DELETE /:id             强制下线会话
+//	DELETE /user/:userId    强制下线账号的全部会话
+func (m *Manager) Routes(r gin.IRouter) {
+	r.GET("", m.listHandler)
+	r.DELETE("/:id", m.kickHandler)
+	r.DELETE("/user/:userId", m.kickUserHandler)
+}
+
+func (m *Manager) listHandler(c *gin.Context) {
+	list, err := m.List(c.Request.Context(), c.Query("userId"))
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+	response.OK(c, list, "")
+}
+
+func (m *Manager) kickHandler(c *gin.Context) {
+	if err := m.Kick(c.Request.Context(), c.Param("id")); err != nil {
+		response.Fail(c, err)
+		return
+	}
+	response.OK(c, nil, "")
+}
+
+func (m *Manager) kickUserHandler(c *gin.Context) {
+	if err := m.KickUser(c.Request.Context(), c.Param("userId")); err != nil {
+		response.Fail(c, err)
+		return
+	}
+	response.OK(c, nil, "")
+}
@@ -0,0 +1,373 @@
+// Package presence 在线用户与设备, 保存在缓存中, 供系统监控的在线用户页面使用
+//
+// 会话id使用 jwt 的 jti, 登录成功后 Login, 鉴权中间件之后挂载 Middleware 刷新心跳并拦截被强制下线的会话
+//
+//	m := presence.New(sdk.Runtime.GetCacheAdapter(), presence.WithMaxSessions(3, presence.KickOldest))
+//	_ = m.Login(ctx, &presence.Session{ID: jti, UserID: "1", Username: "admin", IP: c.ClientIP(), UserAgent: c.Request.UserAgent()})
+//	r.Use(authMiddleware.MiddlewareFunc(), m.Middleware())
+//	m.Routes(r.Group("/api/v1/monitor/online"))
+package presence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bsm/redislock"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/tenant"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+var (
+	ErrNotFound        = errors.New("presence: session not found")
+	ErrKicked          = errors.New("presence: session was kicked")
+	ErrTooManySessions = errors.New("presence: too many sessions")
+	ErrInvalidSession  = errors.New("presence: session id and user id are required")
+)
+
+func init() {
+	response.RegisterError(ErrNotFound, response.ErrNotFound)
+	response.RegisterError(ErrKicked, response.NewError(401, http.StatusUnauthorized, "presence.kicked", "您已被强制下线, 请重新登录"))
+	response.RegisterError(ErrTooManySessions, response.NewError(409, http.StatusConflict, "presence.too_many_sessions", "登录设备数已达上限"))
+	response.RegisterError(ErrInvalidSession, response.ErrBadRequest)
+}
+
+// Policy 超出同时在线数时的处理方式
+type Policy int
+
+const (
+	// KickOldest 强制下线最早登录的会话
+	KickOldest Policy = iota
+	// RejectNew 拒绝新的登录
+	RejectNew
+)
+
+// Session 一个登录会话
+type Session struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"userId"`
+	Username  string    `json:"username,omitempty"`
+	Tenant    string    `json:"tenant,omitempty"`
+	Device    string    `json:"device,omitempty"`
+	UserAgent string    `json:"userAgent,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	Location  string    `json:"location,omitempty"`
+	LoginAt   time.Time `json:"loginAt"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+type Option func(*options)
+
+type options struct {
+	prefix        string
+	idle          time.Duration
+	maxLifetime   time.Duration
+	touchInterval time.Duration
+	maxSessions   int
+	policy        Policy
+	locate        func(ip string) string
+	locker        storage.AdapterLocker
+}
+
+func setDefault() options {
+	return options{
+		prefix:        "presence:",
+		idle:          30 * time.Minute,
+		maxLifetime:   7 * 24 * time.Hour,
+		touchInterval: time.Minute,
+	}
+}
+
+// WithPrefix 缓存key前缀, 默认 presence:
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithIdleTimeout 超过该时间没有心跳视为离线, 默认30分钟
+func WithIdleTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.idle = d
+	}
+}
+
+// WithMaxLifetime 会话的最长保存时间, 应不小于 token 的最长刷新时间, 默认7天; 也是被强制下线标记的保存时间
+func WithMaxLifetime(d time.Duration) Option {
+	return func(o *options) {
+		o.maxLifetime = d
+	}
+}
+
+// WithTouchInterval Middleware 刷新心跳的最小间隔, 默认1分钟
+func WithTouchInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.touchInterval = d
+	}
+}
+
+// WithMaxSessions 每个账号同时在线的会话数, 0为不限制
+func WithMaxSessions(max int, policy Policy) Option {
+	return func(o *options) {
+		o.maxSessions = max
+		o.policy = policy
+	}
+}
+
+// WithLocation 按ip解析登录地点, 如 func(ip string) string { return pkg.GetLocation(ip, key) }
+func WithLocation(f func(ip string) string) Option {
+	return func(o *options) {
+		o.locate = f
+	}
+}
+
+// WithLocker 多实例部署时用分布式锁保护同时在线数的判断
+func WithLocker(l storage.AdapterLocker) Option {
+	return func(o *options) {
+		o.locker = l
+	}
+}
+
+// Manager 在线会话管理
+type Manager struct {
+	cache storage.AdapterCache
+	o     options
+	mux   sync.Mutex
+}
+
+// New cache 需支持 storage.AdapterHashCache, 会话索引保存为hash
+func New(c storage.AdapterCache, opts ...Option) *Manager {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Manager{cache: c, o: o}
+}
+
+func (m *Manager) sessionKey(id string) string { return m.o.prefix + "session:" + id }
+func (m *Manager) kickedKey(id string) string  { return m.o.prefix + "kicked:" + id }
+func (m *Manager) userKey(uid string) string   { return m.o.prefix + "user:" + uid }
+func (m *Manager) allKey() string              { return m.o.prefix + "all" }
+
+// Login 登记会话, 租户取自 ctx; 超出同时在线数时按 Policy 处理
+func (m *Manager) Login(ctx context.Context, s *Session) error {
+	if s.ID == "" || s.UserID == "" {
+		return ErrInvalidSession
+	}
+	now := time.Now()
+	s.LoginAt, s.LastSeen = now, now
+	if t := tenant.FromContext(ctx); t != tenant.DefaultKey && s.Tenant == "" {
+		s.Tenant = t
+	}
+	if s.Location == "" && s.IP != "" && m.o.locate != nil {
+		s.Location = m.o.locate(s.IP)
+	}
+	if m.o.maxSessions <= 0 {
+		return m.save(s)
+	}
+	return m.locked(func() error {
+		sessions, err := m.userSessions(s.UserID)
+		if err != nil {
+			return err
+		}
+		if n := len(sessions) - m.o.maxSessions + 1; n > 0 {
+			if m.o.policy == RejectNew {
+				return ErrTooManySessions
+			}
+			// 按登录时间从早到晚
+			sort.Slice(sessions, func(i, j int) bool { return sessions[i].LoginAt.Before(sessions[j].LoginAt) })
+			for _, old := range sessions[:n] {
+				if err = m.kick(ctx, old); err != nil {
+					return err
+				}
+			}
+		}
+		return m.save(s)
+	})
+}
+
+// Heartbeat 刷新会话的最后活跃时间
+func (m *Manager) Heartbeat(ctx context.Context, id string) (*Session, error) {
+	s, err := m.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.LastSeen = time.Now()
+	if err = m.save(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get 查询会话, 已被强制下线时返回 ErrKicked
+func (m *Manager) Get(_ context.Context, id string) (*Session, error) {
+	if v, err := m.cache.Get(m.kickedKey(id)); err == nil && v != "" {
+		return nil, ErrKicked
+	}
+	v, err := m.cache.Get(m.sessionKey(id))
+	if err != nil || v == "" {
+		return nil, ErrNotFound
+	}
+	s := &Session{}
+	if err = json.Unmarshal([]byte(v), s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// List 在线会话, 按登录时间倒序; ctx 中有租户时只返回该租户的会话, userID 不为空时只返回该用户的会话
+func (m *Manager) List(ctx context.Context, userID string) ([]*Session, error) {
+	var (
+		sessions []*Session
+		err      error
+	)
+	if userID != "" {
+		sessions, err = m.userSessions(userID)
+	} else {
+		sessions, err = m.sessions(m.allKey())
+	}
+	if err != nil {
+		return nil, err
+	}
+	t := tenant.FromContext(ctx)
+	list := make([]*Session, 0, len(sessions))
+	for _, s := range sessions {
+		if t == tenant.DefaultKey || s.Tenant == t {
+			list = append(list, s)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].LoginAt.After(list[j].LoginAt) })
+	return list, nil
+}
+
+// Logout 正常退出登录, 删除会话
+func (m *Manager) Logout(ctx context.Context, id string) error {
+	s, err := m.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrKicked) {
+			return nil
+		}
+		return err
+	}
+	return m.remove(s)
+}
+
+// Kick 强制下线, 之后该会话的请求由 Middleware 拒绝; ctx 中有租户时只能下线该租户的会话
+func (m *Manager) Kick(ctx context.Context, id string) error {
+	s, err := m.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if t := tenant.FromContext(ctx); t != tenant.DefaultKey && s.Tenant != t {
+		return ErrNotFound
+	}
+	return m.kick(ctx, s)
+}
+
+// KickUser 强制下线账号的全部会话, 租户的处理同 Kick
+func (m *Manager) KickUser(ctx context.Context, userID string) error {
+	sessions, err := m.userSessions(userID)
+	if err != nil {
+		return err
+	}
+	t := tenant.FromContext(ctx)
+	for _, s := range sessions {
+		if t != tenant.DefaultKey && s.Tenant != t {
+			continue
+		}
+		if err = m.kick(ctx, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Manager) kick(ctx context.Context, s *Session) error {
+	if err := m.cache.Set(m.kickedKey(s.ID), 1, int(m.o.maxLifetime/time.Second)); err != nil {
+		return err
+	}
+	logger.Module("sdk.presence").WithContext(ctx).Info("session kicked",
+		"session", s.ID, "user", s.UserID, "ip", s.IP, "device", s.Device)
+	return m.remove(s)
+}
+
+func (m *Manager) remove(s *Session) error {
+	if err := m.cache.Del(m.sessionKey(s.ID)); err != nil {
+		return err
+	}
+	if err := m.cache.HashDel(m.userKey(s.UserID), s.ID); err != nil {
+		return err
+	}
+	return m.cache.HashDel(m.allKey(), s.ID)
+}
+
+// save 写入会话并更新账号与全部会话的索引; 索引为hash, 字段为会话id, 各实例只写入自己的字段, 互不覆盖
+func (m *Manager) save(s *Session) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err = m.cache.Set(m.sessionKey(s.ID), string(b), int(m.o.idle/time.Second)); err != nil {
+		return err
+	}
+	if err = cache.HashSet(m.cache, m.userKey(s.UserID), s.ID, string(b)); err != nil {
+		return err
+	}
+	return cache.HashSet(m.cache, m.allKey(), s.ID, string(b))
+}
+
+// userSessions 账号的在线会话
+func (m *Manager) userSessions(userID string) ([]*Session, error) {
+	return m.sessions(m.userKey(userID))
+}
+
+// sessions 索引中仍在线的会话, 一次读取整个索引; 超过 idle 没有心跳的会话从索引中删除
+func (m *Manager) sessions(indexKey string) ([]*Session, error) {
+	values, err := cache.HashGetAll(m.cache, indexKey)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	sessions := make([]*Session, 0, len(values))
+	for id, v := range values {
+		s := &Session{}
+		if json.Unmarshal([]byte(v), s) != nil || now.Sub(s.LastSeen) >= m.o.idle {
+			_ = m.cache.HashDel(indexKey, id)
+			if s.UserID != "" && indexKey == m.allKey() {
+				_ = m.cache.HashDel(m.userKey(s.UserID), id)
+			}
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+// locked 串行执行同时在线数的判断与登录, 配置 Locker 时同时持有分布式锁
+func (m *Manager) locked(f func() error) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.o.locker != nil {
+		lock, err := m.o.locker.Lock(strings.TrimSuffix(m.o.prefix, ":")+":lock", 5, &redislock.Options{
+			RetryStrategy: redislock.LimitRetry(redislock.LinearBackoff(50*time.Millisecond), 40),
+		})
+		if err != nil {
+			return err
+		}
+		if lock != nil {
+			defer func() {
+				_ = lock.Release(context.Background())
+			}()
+		}
+	}
+	return f()
+}
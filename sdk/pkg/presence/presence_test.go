@@ -0,0 +1,121 @@
+package presence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/jwtauth"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+func TestLogin(t *testing.T) {
+	ctx := context.Background()
+	m := New(cache.NewMemory(), WithMaxSessions(2, KickOldest),
+		WithLocation(func(ip string) string { return "内部IP" }))
+	for _, id := range []string{"a", "b", "c"} {
+		if err := m.Login(ctx, &Session{ID: id, UserID: "1", IP: "127.0.0.1"}); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	_ = m.Login(ctx, &Session{ID: "d", UserID: "2"})
+
+	if _, err := m.Get(ctx, "a"); !errors.Is(err, ErrKicked) {
+		t.Errorf("oldest session should be kicked, err = %v", err)
+	}
+	list, _ := m.List(ctx, "1")
+	if len(list) != 2 || list[0].ID != "c" || list[0].Location != "内部IP" {
+		t.Fatalf("list = %+v", list)
+	}
+	if all, _ := m.List(ctx, ""); len(all) != 3 {
+		t.Errorf("all = %d", len(all))
+	}
+
+	if err := m.Logout(ctx, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.KickUser(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if all, _ := m.List(ctx, ""); len(all) != 1 || all[0].ID != "d" {
+		t.Errorf("all = %+v", all)
+	}
+
+	r := New(cache.NewMemory(), WithMaxSessions(1, RejectNew))
+	_ = r.Login(ctx, &Session{ID: "a", UserID: "1"})
+	if err := r.Login(ctx, &Session{ID: "b", UserID: "1"}); !errors.Is(err, ErrTooManySessions) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestConcurrentLogin(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewMemory()
+	// 两个实例共用缓存, 并发登录不会互相覆盖索引
+	a, b := New(store), New(store, WithIdleTimeout(time.Second))
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m := a
+			if i%2 == 1 {
+				m = b
+			}
+			if err := m.Login(ctx, &Session{ID: fmt.Sprint(i), UserID: "1"}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if list, _ := a.List(ctx, "1"); len(list) != 20 {
+		t.Errorf("list = %d", len(list))
+	}
+	// 超过 idle 没有心跳的会话不再返回, 并从索引中删除
+	time.Sleep(time.Second)
+	if list, _ := b.List(ctx, ""); len(list) != 0 {
+		t.Errorf("idle sessions = %d", len(list))
+	}
+	if all, _ := store.HashGetAll(a.userKey("1")); len(all) != 0 {
+		t.Errorf("user index = %d", len(all))
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := New(cache.NewMemory(), WithTouchInterval(0))
+	_ = m.Login(context.Background(), &Session{ID: "a", UserID: "1"})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		claims := jwtauth.MapClaims{"jti": c.GetHeader("X-Session")}
+		c.Request = c.Request.WithContext(jwtauth.WithClaims(c.Request.Context(), claims))
+	}, m.Middleware())
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	m.Routes(r.Group("/online"))
+
+	do := func(method, path string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("X-Session", "a")
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	if code := do(http.MethodGet, "/ping"); code != http.StatusOK {
+		t.Fatalf("code = %d", code)
+	}
+	if code := do(http.MethodDelete, "/online/a"); code != http.StatusOK {
+		t.Fatalf("kick: code = %d", code)
+	}
+	if code := do(http.MethodGet, "/ping"); code != http.StatusUnauthorized {
+		t.Errorf("kicked: code = %d", code)
+	}
+}
@@ -0,0 +1,25 @@
+package progress
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+)
+
+// Handler 进度查询接口, 路径参数 id 为进度id, 只有创建人可以查看, 需自行挂载到有鉴权的路由
+func (m *Manager) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		p, err := m.Get(ctx, c.Param("id"))
+		if err != nil {
+			response.Fail(c, err)
+			return
+		}
+		if p.Owner != "" && p.Owner != logger.OperatorID(ctx) {
+			response.Fail(c, ErrNotFound)
+			return
+		}
+		response.OK(c, p, "")
+	}
+}
@@ -0,0 +1,333 @@
+// Package progress 长任务(导入、导出等)的进度, 保存在缓存中, 通过接口查询或 websocket 推送给前端
+//
+//	// 接口中创建进度, 把 id 放进队列消息并返回给前端
+//	p, _ := m.Create(ctx, "user-import")
+//	message.SetValues(map[string]interface{}{"progressId": p.ID, ...})
+//
+//	// 队列消费者中上报
+//	r, _ := m.Reporter(ctx, id)
+//	r.SetTotal(int64(len(rows)))
+//	for _, row := range rows {
+//		r.Advance(1)
+//	}
+//	_ = r.Succeed(result)
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/idgen"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/tenant"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/ws"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// ErrNotFound 进度不存在或已过期
+var ErrNotFound = errors.New("progress: not found")
+
+func init() {
+	response.RegisterError(ErrNotFound, response.ErrNotFound)
+}
+
+// 任务状态
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// MessageType websocket 推送消息的 type
+const MessageType = "progress"
+
+// Progress 任务进度
+type Progress struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Tenant string `json:"tenant,omitempty"`
+	// Owner 创建人, 只推送给创建人, 查询接口也只允许创建人查看
+	Owner   string  `json:"owner,omitempty"`
+	Status  string  `json:"status"`
+	Stage   string  `json:"stage,omitempty"`
+	Current int64   `json:"current"`
+	Total   int64   `json:"total"`
+	Percent float64 `json:"percent"`
+	Message string  `json:"message,omitempty"`
+	// Result 完成后的结果, 如导出文件的下载地址
+	Result     interface{} `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	UpdatedAt  time.Time   `json:"updatedAt"`
+	FinishedAt *time.Time  `json:"finishedAt,omitempty"`
+}
+
+// Finished 是否已结束
+func (p *Progress) Finished() bool {
+	return p.Status == StatusSucceeded || p.Status == StatusFailed
+}
+
+type Option func(*options)
+
+type options struct {
+	prefix   string
+	ttl      int
+	interval time.Duration
+	hub      *ws.Hub
+	queue    storage.AdapterQueue
+	stream   string
+}
+
+func setDefault() options {
+	return options{
+		prefix:   "progress:",
+		ttl:      24 * 3600,
+		interval: 500 * time.Millisecond,
+	}
+}
+
+// WithPrefix 缓存key前缀, 默认 progress:
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithTTL 进度的保存时间, 单位秒, 默认1天
+func WithTTL(ttl int) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithInterval 上报的最小间隔, 默认500ms, 期间的更新只保留最后一次; 阶段变化与结束时立即写入
+func WithInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.interval = d
+	}
+}
+
+// WithHub 写入后直接推送给本节点的 websocket 连接
+func WithHub(h *ws.Hub) Option {
+	return func(o *options) {
+		o.hub = h
+	}
+}
+
+// WithQueue 写入后通过 ws.Publish 推送, 任务与用户连接不在同一节点时使用; stream 为空时使用 ws.DefaultStream
+func WithQueue(q storage.AdapterQueue, stream string) Option {
+	return func(o *options) {
+		o.queue = q
+		o.stream = stream
+	}
+}
+
+// Manager 创建、查询进度
+type Manager struct {
+	cache storage.AdapterCache
+	o     options
+}
+
+func New(cache storage.AdapterCache, opts ...Option) *Manager {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Manager{cache: cache, o: o}
+}
+
+// Create 创建等待中的进度, 创建人与租户取自 ctx
+func (m *Manager) Create(ctx context.Context, name string) (*Progress, error) {
+	now := time.Now()
+	p := &Progress{
+		ID:        idgen.ULID(),
+		Name:      name,
+		Owner:     logger.OperatorID(ctx),
+		Status:    StatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if t := tenant.FromContext(ctx); t != tenant.DefaultKey {
+		p.Tenant = t
+	}
+	if err := m.save(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Get 查询进度
+func (m *Manager) Get(_ context.Context, id string) (*Progress, error) {
+	v, err := m.cache.Get(m.o.prefix + id)
+	if err != nil || v == "" {
+		return nil, ErrNotFound
+	}
+	p := &Progress{}
+	if err = json.Unmarshal([]byte(v), p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reporter 获取任务的上报器, 状态改为运行中
+func (m *Manager) Reporter(ctx context.Context, id string) (*Reporter, error) {
+	p, err := m.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r := &Reporter{m: m, ctx: ctx, p: p}
+	r.p.Status = StatusRunning
+	r.flush()
+	return r, nil
+}
+
+func (m *Manager) save(p *Progress) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return m.cache.Set(m.o.prefix+p.ID, string(b), m.o.ttl)
+}
+
+// push 推送给创建人, 没有创建人时不推送
+func (m *Manager) push(ctx context.Context, p *Progress) {
+	if p.Owner == "" || (m.o.hub == nil && m.o.queue == nil) {
+		return
+	}
+	data, err := json.Marshal(map[string]interface{}{"type": MessageType, "data": p})
+	if err != nil {
+		return
+	}
+	e := &ws.Envelope{Tenant: p.Tenant, UserID: p.Owner, Data: data}
+	if m.o.queue != nil {
+		if err = ws.Publish(ctx, m.o.queue, m.o.stream, e); err != nil {
+			logger.Module("sdk.progress").WithContext(ctx).Warn("publish progress failed", "id", p.ID, "error", err)
+		}
+		return
+	}
+	m.o.hub.Deliver(e)
+}
+
+// Reporter 上报进度, 可以在多个协程中使用
+type Reporter struct {
+	m       *Manager
+	ctx     context.Context
+	mux     sync.Mutex
+	p       *Progress
+	flushed time.Time
+}
+
+// SetTotal 设置总数
+func (r *Reporter) SetTotal(total int64) {
+	r.update(func(p *Progress) bool {
+		p.Total = total
+		return false
+	})
+}
+
+// Advance 完成数增加 n
+func (r *Reporter) Advance(n int64) {
+	r.update(func(p *Progress) bool {
+		p.Current += n
+		return false
+	})
+}
+
+// Set 设置完成数
+func (r *Reporter) Set(current int64) {
+	r.update(func(p *Progress) bool {
+		p.Current = current
+		return false
+	})
+}
+
+// Stage 进入新的阶段, 如 "解析文件"、"写入数据库", 立即写入
+func (r *Reporter) Stage(stage string) {
+	r.update(func(p *Progress) bool {
+		p.Stage = stage
+		return true
+	})
+}
+
+// Message 附加说明, 如当前处理的行
+func (r *Reporter) Message(format string, args ...interface{}) {
+	r.update(func(p *Progress) bool {
+		p.Message = fmt.Sprintf(format, args...)
+		return false
+	})
+}
+
+// Succeed 任务成功, result 为返回给前端的结果
+func (r *Reporter) Succeed(result interface{}) error {
+	return r.finish(StatusSucceeded, result, "")
+}
+
+// Fail 任务失败
+func (r *Reporter) Fail(err error) error {
+	msg := "unknown error"
+	if err != nil {
+		msg = err.Error()
+	}
+	return r.finish(StatusFailed, nil, msg)
+}
+
+// Progress 当前进度的副本
+func (r *Reporter) Progress() Progress {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return *r.p
+}
+
+func (r *Reporter) finish(status string, result interface{}, msg string) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	now := time.Now()
+	r.p.Status = status
+	r.p.Result = result
+	r.p.Error = msg
+	r.p.FinishedAt = &now
+	if status == StatusSucceeded && r.p.Total > 0 {
+		r.p.Current = r.p.Total
+	}
+	return r.flush()
+}
+
+// update force 为 true 或距上次写入超过间隔时写入
+func (r *Reporter) update(f func(p *Progress) bool) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if r.p.Finished() {
+		return
+	}
+	force := f(r.p)
+	if force || time.Since(r.flushed) >= r.m.o.interval {
+		if err := r.flush(); err != nil {
+			logger.Module("sdk.progress").WithContext(r.ctx).Warn("save progress failed", "id", r.p.ID, "error", err)
+		}
+	}
+}
+
+func (r *Reporter) flush() error {
+	p := r.p
+	p.UpdatedAt = time.Now()
+	if p.Total > 0 {
+		p.Percent = float64(p.Current) * 100 / float64(p.Total)
+		if p.Percent > 100 {
+			p.Percent = 100
+		}
+	} else if p.Status == StatusSucceeded {
+		p.Percent = 100
+	}
+	r.flushed = p.UpdatedAt
+	if err := r.m.save(p); err != nil {
+		return err
+	}
+	r.m.push(r.ctx, p)
+	return nil
+}
@@ -0,0 +1,106 @@
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/ws"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+type fakeQueue struct {
+	storage.AdapterQueue
+	envelopes []ws.Envelope
+}
+
+func (q *fakeQueue) Append(m storage.Messager) error {
+	e := ws.Envelope{}
+	_ = json.Unmarshal([]byte(m.GetValues()["envelope"].(string)), &e)
+	q.envelopes = append(q.envelopes, e)
+	return nil
+}
+
+func TestReporter(t *testing.T) {
+	q := &fakeQueue{}
+	m := New(cache.NewMemory(), WithInterval(time.Hour), WithQueue(q, ""))
+	ctx := logger.WithOperatorID(context.Background(), "1")
+	p, err := m.Create(ctx, "import")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := m.Reporter(context.Background(), p.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.SetTotal(4)
+	r.Advance(1)
+	// 间隔内只更新内存
+	if got, _ := m.Get(ctx, p.ID); got.Status != StatusRunning || got.Current != 0 {
+		t.Errorf("throttled: %+v", got)
+	}
+	r.Stage("写入数据库")
+	if got, _ := m.Get(ctx, p.ID); got.Stage != "写入数据库" || got.Percent != 25 {
+		t.Errorf("stage: %+v", got)
+	}
+	if err = r.Succeed(map[string]string{"file": "a.xlsx"}); err != nil {
+		t.Fatal(err)
+	}
+	r.Advance(1)
+	got, _ := m.Get(ctx, p.ID)
+	if got.Status != StatusSucceeded || got.Percent != 100 || got.Current != 4 || got.FinishedAt == nil {
+		t.Errorf("succeed: %+v", got)
+	}
+	if len(q.envelopes) != 3 || q.envelopes[2].UserID != "1" {
+		t.Fatalf("envelopes = %+v", q.envelopes)
+	}
+	msg := struct {
+		Type string   `json:"type"`
+		Data Progress `json:"data"`
+	}{}
+	_ = json.Unmarshal(q.envelopes[2].Data, &msg)
+	if msg.Type != MessageType || msg.Data.Status != StatusSucceeded {
+		t.Errorf("message = %+v", msg)
+	}
+
+	r, _ = m.Reporter(ctx, p.ID)
+	_ = r.Fail(errors.New("boom"))
+	if got, _ = m.Get(ctx, p.ID); got.Status != StatusFailed || got.Error != "boom" {
+		t.Errorf("fail: %+v", got)
+	}
+	if _, err = m.Reporter(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing: err = %v", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := New(cache.NewMemory())
+	p, _ := m.Create(logger.WithOperatorID(context.Background(), "1"), "export")
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		ctx := logger.WithOperatorID(c.Request.Context(), c.GetHeader("X-User"))
+		c.Request = c.Request.WithContext(ctx)
+	})
+	r.GET("/progress/:id", m.Handler())
+	for _, c := range []struct {
+		user string
+		code int
+	}{{"1", http.StatusOK}, {"2", http.StatusNotFound}} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/progress/"+p.ID, nil)
+		req.Header.Set("X-User", c.user)
+		r.ServeHTTP(w, req)
+		if w.Code != c.code {
+			t.Errorf("user %s: code = %d", c.user, w.Code)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package recovery 捕获 panic, 记录堆栈并上报, 用于 http 接口(middleware.Recovery)与队列消费者
+//
+//	recovery.AddReporter(recovery.NewWebhook("https://hooks.example.com/panic", nil))
+//	// 接入 sentry 时实现 Reporter 即可
+//	recovery.AddReporter(recovery.ReporterFunc(func(ctx context.Context, p *recovery.Panic) {
+//		sentry.CaptureException(p)
+//	}))
+//	q.Register("order", recovery.Consumer("order", consumeOrder))
+package recovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+// ErrPanic 消费者 panic 时返回的错误, 队列按普通错误重试
+var ErrPanic = errors.New("recovery: panic")
+
+// 来源
+const (
+	SourceHTTP  = "http"
+	SourceQueue = "queue"
+)
+
+// Panic 一次 panic 的信息
+type Panic struct {
+	Value      interface{}       `json:"-"`
+	Message    string            `json:"message"`
+	Stack      string            `json:"stack"`
+	Source     string            `json:"source"`
+	RequestID  string            `json:"requestId,omitempty"`
+	TraceID    string            `json:"traceId,omitempty"`
+	OperatorID string            `json:"operatorId,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Time       time.Time         `json:"time"`
+}
+
+func (p *Panic) Error() string {
+	return "panic: " + p.Message
+}
+
+// Unwrap panic 的值是 error 时返回该 error
+func (p *Panic) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// Reporter 上报 panic, 在单独的协程中调用
+type Reporter interface {
+	Report(ctx context.Context, p *Panic)
+}
+
+// ReporterFunc 函数形式的 Reporter
+type ReporterFunc func(ctx context.Context, p *Panic)
+
+func (f ReporterFunc) Report(ctx context.Context, p *Panic) {
+	f(ctx, p)
+}
+
+var (
+	mux       sync.RWMutex
+	reporters []Reporter
+)
+
+// AddReporter 添加上报方式
+func AddReporter(r ...Reporter) {
+	mux.Lock()
+	defer mux.Unlock()
+	reporters = append(reporters, r...)
+}
+
+// Capture 记录 panic 并异步上报, 在 recover() 之后调用; tags 为成对的键值, 如 "stream", "order"
+func Capture(ctx context.Context, source string, v interface{}, tags ...string) *Panic {
+	p := &Panic{
+		Value:      v,
+		Message:    fmt.Sprint(v),
+		Stack:      string(debug.Stack()),
+		Source:     source,
+		RequestID:  logger.RequestID(ctx),
+		TraceID:    logger.TraceID(ctx),
+		OperatorID: logger.OperatorID(ctx),
+		Time:       time.Now(),
+	}
+	kv := []interface{}{"source", source, "panic", p.Message}
+	if len(tags) > 1 {
+		p.Tags = make(map[string]string, len(tags)/2)
+		for i := 0; i+1 < len(tags); i += 2 {
+			p.Tags[tags[i]] = tags[i+1]
+			kv = append(kv, tags[i], tags[i+1])
+		}
+	}
+	logger.Module("sdk.recovery").WithContext(ctx).Error("panic recovered", append(kv, "stack", p.Stack)...)
+
+	mux.RLock()
+	rs := reporters
+	mux.RUnlock()
+	if len(rs) > 0 {
+		// 请求结束后 ctx 会被取消, 上报使用新的 ctx, 保留链路信息
+		rctx := logger.CopyTrace(context.Background(), ctx)
+		for _, r := range rs {
+			go report(rctx, r, p)
+		}
+	}
+	return p
+}
+
+func report(ctx context.Context, r Reporter, p *Panic) {
+	defer func() {
+		if v := recover(); v != nil {
+			logger.Module("sdk.recovery").WithContext(ctx).Error("reporter panic", "panic", v)
+		}
+	}()
+	r.Report(ctx, p)
+}
+
+// Consumer 包装队列消费者, panic 时记录并返回 ErrPanic, 由队列按失败重试
+func Consumer(stream string, f storage.ConsumerFunc) storage.ConsumerFunc {
+	return func(m storage.Messager) (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				p := Capture(queue.TraceContext(m), SourceQueue, v, "stream", stream, "id", m.GetID())
+				err = fmt.Errorf("%w: %s", ErrPanic, p.Message)
+			}
+		}()
+		return f(m)
+	}
+}
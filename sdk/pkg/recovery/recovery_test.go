@@ -0,0 +1,76 @@
+package recovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+func resetReporters() {
+	mux.Lock()
+	reporters = nil
+	mux.Unlock()
+}
+
+func TestConsumer(t *testing.T) {
+	defer resetReporters()
+	got := make(chan *Panic, 1)
+	AddReporter(ReporterFunc(func(ctx context.Context, p *Panic) {
+		got <- p
+	}))
+
+	m := new(queue.Message)
+	m.SetID("1")
+	m.SetValues(map[string]interface{}{queue.RequestIDKey: "req-1"})
+	f := Consumer("order", func(storage.Messager) error {
+		var s []int
+		_ = s[1]
+		return nil
+	})
+	if err := f(m); !errors.Is(err, ErrPanic) {
+		t.Fatalf("err = %v", err)
+	}
+	select {
+	case p := <-got:
+		if p.Source != SourceQueue || p.RequestID != "req-1" || p.Tags["stream"] != "order" ||
+			!strings.Contains(p.Stack, "recovery_test.go") || p.Unwrap() == nil {
+			t.Errorf("panic = %+v", p)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("reporter not called")
+	}
+
+	if err := Consumer("order", func(storage.Messager) error { return nil })(m); err != nil {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var (
+		wg   sync.WaitGroup
+		body Panic
+	)
+	wg.Add(1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer wg.Done()
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer srv.Close()
+
+	ctx := logger.WithTraceID(context.Background(), "trace-1")
+	NewWebhook(srv.URL, nil).Report(ctx, &Panic{Message: "boom", Source: SourceHTTP, TraceID: "trace-1"})
+	wg.Wait()
+	if body.Message != "boom" || body.TraceID != "trace-1" {
+		t.Errorf("body = %+v", body)
+	}
+}
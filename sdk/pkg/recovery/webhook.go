@@ -0,0 +1,50 @@
+package recovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/tools/httpclient"
+)
+
+// Webhook 以 json POST 到指定地址上报 panic
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook client 为空时使用5秒超时的 httpclient
+func NewWebhook(url string, client *http.Client) *Webhook {
+	if client == nil {
+		client = httpclient.New(httpclient.WithTimeout(5 * time.Second))
+	}
+	return &Webhook{url: url, client: client}
+}
+
+func (w *Webhook) Report(ctx context.Context, p *Panic) {
+	log := logger.Module("sdk.recovery").WithContext(ctx)
+	body, err := json.Marshal(p)
+	if err != nil {
+		log.Warn("marshal panic failed", "error", err)
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		log.Warn("report panic failed", "error", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		log.Warn("report panic failed", "error", err)
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		log.Warn("report panic failed", "status", resp.StatusCode)
+	}
+}
@@ -0,0 +1,177 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg"
+)
+
+// CodeError 携带业务码与http状态码的错误, 文案可通过 MsgID 国际化
+type CodeError struct {
+	// Code 业务码, 写入响应的 code
+	Code int
+	// Status http状态码
+	Status int
+	// MsgID 国际化文案id, 未设置翻译或找不到时使用 Msg
+	MsgID string
+	// Msg 默认文案
+	Msg string
+	// Data 文案模板参数
+	Data map[string]interface{}
+	// Details 写入响应 data 的错误详情, 如字段校验错误
+	Details interface{}
+	cause   error
+}
+
+// NewError 新建业务错误
+func NewError(code, status int, msgID, msg string) *CodeError {
+	return &CodeError{Code: code, Status: status, MsgID: msgID, Msg: msg}
+}
+
+var (
+	ErrBadRequest      = NewError(400, http.StatusBadRequest, "error.bad_request", "参数错误")
+	ErrUnauthorized    = NewError(401, http.StatusUnauthorized, "error.unauthorized", "未登录或登录已过期")
+	ErrForbidden       = NewError(403, http.StatusForbidden, "error.forbidden", "对不起，您没有该接口访问权限，请联系管理员")
+	ErrNotFound        = NewError(404, http.StatusNotFound, "error.not_found", "数据不存在")
+	ErrConflict        = NewError(409, http.StatusConflict, "error.conflict", "数据已存在")
+	ErrTooManyRequests = NewError(429, http.StatusTooManyRequests, "error.too_many_requests", "请求过于频繁")
+	ErrInternal        = NewError(500, http.StatusInternalServerError, "error.internal", "服务器内部错误")
+	ErrTimeout         = NewError(504, http.StatusGatewayTimeout, "error.timeout", "请求超时")
+)
+
+func (e *CodeError) Error() string {
+	if e.cause != nil {
+		return e.Msg + ": " + e.cause.Error()
+	}
+	return e.Msg
+}
+
+func (e *CodeError) Unwrap() error {
+	return e.cause
+}
+
+// Is 业务码相同即视为同一错误
+func (e *CodeError) Is(target error) bool {
+	t, ok := target.(*CodeError)
+	return ok && t.Code == e.Code
+}
+
+// Wrap 返回包装了 err 的副本, err 不会出现在响应文案中
+func (e *CodeError) Wrap(err error) *CodeError {
+	c := *e
+	c.cause = err
+	return &c
+}
+
+// WithMsg 返回替换默认文案的副本
+func (e *CodeError) WithMsg(msg string) *CodeError {
+	c := *e
+	c.Msg = msg
+	return &c
+}
+
+// WithData 返回设置模板参数的副本
+func (e *CodeError) WithData(data map[string]interface{}) *CodeError {
+	c := *e
+	c.Data = data
+	return &c
+}
+
+// WithDetails 返回设置错误详情的副本
+func (e *CodeError) WithDetails(details interface{}) *CodeError {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+// Message 当前请求语言下的文案
+func (e *CodeError) Message(c *gin.Context) string {
+	if t := getTranslator(); t != nil && e.MsgID != "" {
+		if s := t(c, e.MsgID, e.Data); s != "" {
+			return s
+		}
+	}
+	return e.Msg
+}
+
+// Translator 按请求语言翻译文案, 找不到时返回空串
+type Translator func(c *gin.Context, id string, data map[string]interface{}) string
+
+var (
+	mux        sync.RWMutex
+	translator Translator
+	mappings   = []mapping{
+		{gorm.ErrRecordNotFound, ErrNotFound},
+		{context.DeadlineExceeded, ErrTimeout},
+	}
+)
+
+type mapping struct {
+	target error
+	to     *CodeError
+}
+
+// SetTranslator 设置文案翻译, 一般由 i18n 模块调用
+func SetTranslator(t Translator) {
+	mux.Lock()
+	defer mux.Unlock()
+	translator = t
+}
+
+func getTranslator() Translator {
+	mux.RLock()
+	defer mux.RUnlock()
+	return translator
+}
+
+// RegisterError 注册 errors.Is(err, target) 时对应的业务错误, 后注册的优先
+func RegisterError(target error, to *CodeError) {
+	mux.Lock()
+	defer mux.Unlock()
+	mappings = append([]mapping{{target, to}}, mappings...)
+}
+
+// FromError 将任意错误转为业务错误: 链上已有 CodeError 时直接使用,
+// 其次按 RegisterError 的映射, 都不匹配时为 ErrInternal
+func FromError(err error) *CodeError {
+	if err == nil {
+		return nil
+	}
+	var ce *CodeError
+	if errors.As(err, &ce) {
+		return ce
+	}
+	mux.RLock()
+	defer mux.RUnlock()
+	for _, m := range mappings {
+		if errors.Is(err, m.target) {
+			return m.to.Wrap(err)
+		}
+	}
+	return ErrInternal.Wrap(err)
+}
+
+// Fail 按错误写入失败响应, http状态码与业务码取自 FromError,
+// 原始错误记入 c.Errors 供请求日志使用
+func Fail(c *gin.Context, err error) {
+	ce := ErrInternal
+	if err != nil {
+		ce = FromError(err)
+		_ = c.Error(err)
+	}
+	res := Default.Clone()
+	res.SetMsg(ce.Message(c))
+	res.SetData(ce.Details)
+	res.SetTraceID(pkg.GenerateMsgIDFromContext(c))
+	res.SetCode(int32(ce.Code))
+	res.SetSuccess(false)
+	c.Set("result", res)
+	c.Set("status", ce.Status)
+	Render(c, ce.Status, res)
+}
@@ -0,0 +1,67 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func TestFromError(t *testing.T) {
+	errDup := errors.New("duplicate")
+	RegisterError(errDup, ErrConflict)
+
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{fmt.Errorf("get user: %w", gorm.ErrRecordNotFound), 404},
+		{fmt.Errorf("save: %w", errDup), 409},
+		{fmt.Errorf("check: %w", ErrForbidden.WithMsg("no")), 403},
+		{errors.New("boom"), 500},
+	}
+	for _, tt := range tests {
+		ce := FromError(tt.err)
+		if ce.Code != tt.code {
+			t.Errorf("FromError(%v).Code = %d, want %d", tt.err, ce.Code, tt.code)
+		}
+	}
+	if FromError(nil) != nil {
+		t.Error("FromError(nil) should be nil")
+	}
+	if err := ErrNotFound.Wrap(gorm.ErrRecordNotFound); !errors.Is(err, ErrNotFound) || !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Error("wrapped error should match both code and cause")
+	}
+}
+
+func TestFail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	SetTranslator(func(c *gin.Context, id string, data map[string]interface{}) string {
+		if c.GetHeader("Accept-Language") == "en" && id == "error.not_found" {
+			return "not found"
+		}
+		return ""
+	})
+	defer SetTranslator(nil)
+
+	r := gin.New()
+	r.GET("/", func(c *gin.Context) {
+		Fail(c, fmt.Errorf("query: %w", gorm.ErrRecordNotFound))
+	})
+	for lang, msg := range map[string]string{"en": "not found", "zh": ErrNotFound.Msg} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", lang)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var res response
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+		if w.Code != http.StatusNotFound || res.Code != 404 || res.Msg != msg || res.RequestId == "" {
+			t.Errorf("lang %s: status = %d, body = %s", lang, w.Code, w.Body.String())
+		}
+	}
+}
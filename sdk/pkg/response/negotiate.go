@@ -0,0 +1,35 @@
+package response
+
+import (
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+)
+
+var msgpack int32
+
+// EnableMsgPack 开启后按请求的 Accept 协商响应格式, 客户端接受 msgpack 时输出 msgpack, 默认只输出 json;
+// msgpack 使用结构体的 json tag, 字段名与 json 一致
+func EnableMsgPack(enable bool) {
+	var v int32
+	if enable {
+		v = 1
+	}
+	atomic.StoreInt32(&msgpack, v)
+}
+
+// Render 按 Accept 写入响应并终止后续处理, 未开启 msgpack 或 Accept 为空、*/* 时为 json
+func Render(c *gin.Context, status int, obj interface{}) {
+	if atomic.LoadInt32(&msgpack) == 1 {
+		c.Writer.Header().Add("Vary", "Accept")
+		switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEMSGPACK, binding.MIMEMSGPACK2) {
+		case binding.MIMEMSGPACK, binding.MIMEMSGPACK2:
+			c.Abort()
+			c.Render(status, render.MsgPack{Data: obj})
+			return
+		}
+	}
+	c.AbortWithStatusJSON(status, obj)
+}
@@ -0,0 +1,46 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestRender(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", func(c *gin.Context) { OK(c, gin.H{"name": "admin"}, "") })
+	do := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do("application/msgpack"); w.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("disabled: content type = %q", w.Header().Get("Content-Type"))
+	}
+
+	EnableMsgPack(true)
+	defer EnableMsgPack(false)
+	w := do("application/x-msgpack")
+	if w.Header().Get("Content-Type") != "application/msgpack; charset=utf-8" {
+		t.Fatalf("content type = %q", w.Header().Get("Content-Type"))
+	}
+	var res map[string]interface{}
+	if err := binding.MsgPack.BindBody(w.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res["code"] == nil || res["data"] == nil {
+		t.Errorf("res = %v", res)
+	}
+	for _, accept := range []string{"", "*/*", "application/json"} {
+		if w = do(accept); w.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+			t.Errorf("%q: content type = %q", accept, w.Header().Get("Content-Type"))
+		}
+	}
+}
@@ -24,7 +24,7 @@ func Error(c *gin.Context, code int, err error, msg string) {
 	res.SetSuccess(false)
 	c.Set("result", res)
 	c.Set("status", code)
-	c.AbortWithStatusJSON(http.StatusOK, res)
+	Render(c, http.StatusOK, res)
 }
 
 // OK 通常成功数据处理
@@ -39,7 +39,7 @@ func OK(c *gin.Context, data interface{}, msg string) {
 	res.SetCode(http.StatusOK)
 	c.Set("result", res)
 	c.Set("status", http.StatusOK)
-	c.AbortWithStatusJSON(http.StatusOK, res)
+	Render(c, http.StatusOK, res)
 }
 
 // PageOK 分页数据处理
@@ -56,5 +56,5 @@ func PageOK(c *gin.Context, result interface{}, count int, pageIndex int, pageSi
 func Custum(c *gin.Context, data gin.H) {
 	data["requestId"] = pkg.GenerateMsgIDFromContext(c)
 	c.Set("result", data)
-	c.AbortWithStatusJSON(http.StatusOK, data)
+	Render(c, http.StatusOK, data)
 }
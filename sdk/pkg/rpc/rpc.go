@@ -0,0 +1,161 @@
+// Package rpc 按配置创建 gRPC 服务端与依赖服务的客户端, 与 http 服务共用 runtime 中的指标注册表与生命周期
+package rpc
+
+import (
+	"context"
+	"time"
+
+	grpcgo "google.golang.org/grpc"
+
+	"github.com/go-admin-team/go-admin-core/sdk/config"
+	"github.com/go-admin-team/go-admin-core/sdk/runtime"
+	"github.com/go-admin-team/go-admin-core/server/grpc"
+	"github.com/go-admin-team/go-admin-core/server/grpc/interceptors/auth"
+)
+
+type Option func(*options)
+
+type options struct {
+	name   string
+	auth   auth.AuthFunc
+	token  auth.TokenFunc
+	server []grpc.Option
+	client []grpc.ClientOption
+}
+
+func setDefault() options {
+	return options{name: "grpc"}
+}
+
+// WithName 服务端在生命周期中的名称
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// WithAuth 服务端校验 token, 一般使用 jwtauth 的 GrpcAuthFunc, 未设置时不鉴权
+func WithAuth(f auth.AuthFunc) Option {
+	return func(o *options) {
+		o.auth = f
+	}
+}
+
+// WithToken 客户端附加的 token, 默认转发当前请求中的 token
+func WithToken(f auth.TokenFunc) Option {
+	return func(o *options) {
+		o.token = f
+	}
+}
+
+// WithServerOptions 追加服务端选项, 如自定义拦截器
+func WithServerOptions(opts ...grpc.Option) Option {
+	return func(o *options) {
+		o.server = append(o.server, opts...)
+	}
+}
+
+// WithClientOptions 追加客户端选项
+func WithClientOptions(opts ...grpc.ClientOption) Option {
+	return func(o *options) {
+		o.client = append(o.client, opts...)
+	}
+}
+
+func evaluate(opts []Option) options {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// NewServer 按配置创建服务端, 调用耗时记录到 rt 的指标注册表
+func NewServer(rt runtime.Runtime, c *config.GrpcServer, opts ...Option) (*grpc.Server, error) {
+	o := evaluate(opts)
+	tls, err := c.TLS()
+	if err != nil {
+		return nil, err
+	}
+	serverOpts := []grpc.Option{
+		grpc.WithAddrOption(c.Addr),
+		grpc.WithTlsOption(tls),
+		grpc.WithMetricsOption(rt.GetMetrics()),
+	}
+	if c.Timeout > 0 {
+		serverOpts = append(serverOpts, grpc.WithTimeoutOption(time.Duration(c.Timeout)*time.Second))
+	}
+	if c.MaxMsgSize > 0 {
+		serverOpts = append(serverOpts, grpc.WithMaxMsgSizeOption(c.MaxMsgSize))
+	}
+	if o.auth != nil {
+		serverOpts = append(serverOpts, grpc.WithAuthOption(o.auth, c.SkipAuth...))
+	}
+	return grpc.New(o.name, append(serverOpts, o.server...)...), nil
+}
+
+// Component 服务端的生命周期, 启动时开始监听, 停止时等待请求处理完成
+func Component(s *grpc.Server) runtime.Component {
+	return &runtime.ComponentFunc{
+		Name: s.String(),
+		StartFunc: func(context.Context) error {
+			return s.Run()
+		},
+		StopFunc: s.Stop,
+	}
+}
+
+// Dial 按配置连接依赖服务, 附加 token 转发与调用耗时指标
+func Dial(ctx context.Context, rt runtime.Runtime, c *config.GrpcClient, opts ...Option) (*grpcgo.ClientConn, error) {
+	o := evaluate(opts)
+	tls, err := c.TLS()
+	if err != nil {
+		return nil, err
+	}
+	clientOpts := []grpc.ClientOption{
+		grpc.WithClientToken(o.token),
+		grpc.WithClientMetrics(rt.GetMetrics()),
+		grpc.WithClientTimeout(time.Duration(c.Timeout) * time.Second),
+	}
+	if tls != nil {
+		clientOpts = append(clientOpts, grpc.WithClientTLS(tls))
+	}
+	return grpc.Dial(ctx, c.Target, append(clientOpts, o.client...)...)
+}
+
+// Setup 连接配置中的依赖服务写入 rt, 配置了 Server 时创建服务端并加入 rt 的生命周期;
+// 返回的服务端需在 rt.Start 之前注册服务, 未配置时为nil
+func Setup(ctx context.Context, rt runtime.Runtime, c *config.Grpc, opts ...Option) (*grpc.Server, error) {
+	conns := make([]*grpcgo.ClientConn, 0, len(c.Clients))
+	for name, client := range c.Clients {
+		conn, err := Dial(ctx, rt, client, opts...)
+		if err != nil {
+			for _, conn := range conns {
+				_ = conn.Close()
+			}
+			return nil, err
+		}
+		conns = append(conns, conn)
+		rt.SetGrpcClient(name, conn)
+	}
+	if len(conns) > 0 {
+		rt.AddComponent(runtime.OrderStorage, &runtime.ComponentFunc{
+			Name: "grpc-clients",
+			StopFunc: func(context.Context) error {
+				for _, conn := range conns {
+					_ = conn.Close()
+				}
+				return nil
+			},
+		})
+	}
+	if c.Server == nil {
+		return nil, nil
+	}
+	s, err := NewServer(rt, c.Server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	rt.AddComponent(runtime.OrderServer, Component(s))
+	return s, nil
+}
@@ -0,0 +1,57 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-admin-team/go-admin-core/sdk/config"
+	"github.com/go-admin-team/go-admin-core/sdk/runtime"
+	"github.com/go-admin-team/go-admin-core/server/grpc"
+)
+
+func TestSetup(t *testing.T) {
+	rt := runtime.NewConfig()
+	s, err := Setup(context.Background(), rt, &config.Grpc{Server: &config.GrpcServer{Addr: "127.0.0.1:0"}},
+		WithAuth(func(ctx context.Context, token string) (context.Context, error) {
+			if token != "good" {
+				return nil, errors.New("invalid token")
+			}
+			return ctx, nil
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Register(func(s *grpc.Server) {
+		healthpb.RegisterHealthServer(s.Server(), health.NewServer())
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err = rt.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer rt.Shutdown(ctx)
+
+	check := func(token string) error {
+		conn, err := Dial(ctx, rt, &config.GrpcClient{Target: s.Addr(), Timeout: 1},
+			WithToken(func(context.Context) string { return token }))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		return err
+	}
+	if err = check("good"); err != nil {
+		t.Errorf("err = %v", err)
+	}
+	if err = check("bad"); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package security
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// BreachChecker 检查密码是否出现在已泄露的密码库中
+type BreachChecker interface {
+	Breached(ctx context.Context, password string) (bool, error)
+}
+
+// BreachFunc 函数形式的 BreachChecker
+type BreachFunc func(ctx context.Context, password string) (bool, error)
+
+func (f BreachFunc) Breached(ctx context.Context, password string) (bool, error) {
+	return f(ctx, password)
+}
+
+// CommonPasswords 常见弱密码
+var CommonPasswords = []string{
+	"123456", "12345678", "123456789", "1234567890", "111111", "000000", "123123", "654321",
+	"password", "password1", "passw0rd", "qwerty", "qwerty123", "abc123", "abc123456",
+	"a123456", "admin", "admin123", "admin888", "root", "iloveyou", "1q2w3e4r", "qwe123",
+	"aa123456", "123qwe", "5201314", "woaini1314", "zxcvbnm", "123456a", "letmein",
+}
+
+// BreachList 按列表检查, 不区分大小写
+func BreachList(passwords ...string) BreachChecker {
+	set := make(map[string]struct{}, len(passwords))
+	for _, p := range passwords {
+		set[strings.ToLower(p)] = struct{}{}
+	}
+	return BreachFunc(func(_ context.Context, password string) (bool, error) {
+		_, ok := set[strings.ToLower(password)]
+		return ok, nil
+	})
+}
+
+// Pwned 使用 Have I Been Pwned 的 k-匿名接口, 只发送 sha1 的前5位
+type Pwned struct {
+	Client *http.Client
+	// Endpoint 默认 https://api.pwnedpasswords.com/range/
+	Endpoint string
+}
+
+func (e *Pwned) Breached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	endpoint := e.Endpoint
+	if endpoint == "" {
+		endpoint = "https://api.pwnedpasswords.com/range/"
+	}
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+hash[:5], nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Add-Padding", "true")
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("pwned passwords: unexpected status %d", resp.StatusCode)
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		suffix, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		// 填充的结果次数为0
+		if ok && suffix == hash[5:] && count != "0" {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
@@ -0,0 +1,229 @@
+// Package security 密码哈希、复杂度与有效期策略, 以及泄露密码检查
+//
+//	hash, _ := security.Hash(password)
+//	ok := security.Verify(user.Password, password)
+//	if ok && security.NeedsRehash(user.Password) {
+//		// 登录成功后按当前算法重新计算并保存
+//	}
+package security
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrInvalidHash = errors.New("security: invalid password hash")
+
+// Hasher 密码哈希算法
+type Hasher interface {
+	String() string
+	Hash(password string) (string, error)
+	// Match 是否为该算法生成的hash
+	Match(hash string) bool
+	Verify(hash, password string) (bool, error)
+	// NeedsRehash 参数与当前配置不同, 应在登录成功后重新计算
+	NeedsRehash(hash string) bool
+}
+
+// Bcrypt bcrypt 算法, 兼容原有的密码
+type Bcrypt struct {
+	Cost int
+}
+
+// NewBcrypt cost 为0时使用 bcrypt.DefaultCost
+func NewBcrypt(cost int) *Bcrypt {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	return &Bcrypt{Cost: cost}
+}
+
+func (*Bcrypt) String() string {
+	return "bcrypt"
+}
+
+func (e *Bcrypt) Hash(password string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(password), e.Cost)
+	return string(b), err
+}
+
+func (*Bcrypt) Match(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func (*Bcrypt) Verify(hash, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (e *Bcrypt) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != e.Cost
+}
+
+// Argon2id argon2id 算法, hash 格式为 $argon2id$v=19$m=65536,t=3,p=2$salt$key
+type Argon2id struct {
+	Time    uint32
+	Memory  uint32 // KiB
+	Threads uint8
+	KeyLen  uint32
+	SaltLen uint32
+}
+
+// NewArgon2id 使用 RFC 9106 推荐的参数: 64MiB 内存, 3次迭代
+func NewArgon2id() *Argon2id {
+	return &Argon2id{Time: 3, Memory: 64 * 1024, Threads: 2, KeyLen: 32, SaltLen: 16}
+}
+
+func (*Argon2id) String() string {
+	return "argon2id"
+}
+
+func (e *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, e.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, e.Time, e.Memory, e.Threads, e.KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, e.Memory, e.Time, e.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (*Argon2id) Match(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+func (*Argon2id) Verify(hash, password string) (bool, error) {
+	p, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return false, err
+	}
+	other := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+func (e *Argon2id) NeedsRehash(hash string) bool {
+	p, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	return p.Time != e.Time || p.Memory != e.Memory || p.Threads != e.Threads ||
+		uint32(len(key)) != e.KeyLen || uint32(len(salt)) != e.SaltLen
+}
+
+func decodeArgon2id(hash string) (p Argon2id, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, ErrInvalidHash
+	}
+	var version int
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrInvalidHash
+	}
+	if _, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil {
+		return p, nil, nil, ErrInvalidHash
+	}
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return p, nil, nil, ErrInvalidHash
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(key) == 0 {
+		return p, nil, nil, ErrInvalidHash
+	}
+	return p, salt, key, nil
+}
+
+var (
+	hashMux sync.RWMutex
+	current Hasher = NewBcrypt(0)
+	hashers        = []Hasher{current, NewArgon2id()}
+	dummy   string
+)
+
+// SetDefault 设置新密码使用的算法, 已有的其他算法的hash仍可校验
+func SetDefault(h Hasher) {
+	hashMux.Lock()
+	defer hashMux.Unlock()
+	current = h
+	dummy = ""
+	for i := range hashers {
+		if hashers[i].String() == h.String() {
+			hashers[i] = h
+			return
+		}
+	}
+	hashers = append(hashers, h)
+}
+
+// Register 注册可校验的算法, 如迁移前系统使用的算法
+func Register(h Hasher) {
+	hashMux.Lock()
+	defer hashMux.Unlock()
+	for i := range hashers {
+		if hashers[i].String() == h.String() {
+			hashers[i] = h
+			return
+		}
+	}
+	hashers = append(hashers, h)
+}
+
+// Hash 使用当前算法计算密码hash
+func Hash(password string) (string, error) {
+	hashMux.RLock()
+	h := current
+	hashMux.RUnlock()
+	return h.Hash(password)
+}
+
+// Verify 校验密码; hash为空或无法识别时仍计算一次哈希, 避免通过响应时间判断用户是否存在
+func Verify(hash, password string) bool {
+	hashMux.RLock()
+	cur := current
+	var h Hasher
+	for _, v := range hashers {
+		if v.Match(hash) {
+			h = v
+			break
+		}
+	}
+	hashMux.RUnlock()
+	if h == nil {
+		_, _ = cur.Verify(dummyHash(), password)
+		return false
+	}
+	ok, _ := h.Verify(hash, password)
+	return ok
+}
+
+// NeedsRehash hash 不是当前算法生成的或参数已变化
+func NeedsRehash(hash string) bool {
+	hashMux.RLock()
+	h := current
+	hashMux.RUnlock()
+	return !h.Match(hash) || h.NeedsRehash(hash)
+}
+
+// Equal 常量时间比较, 用于比较 token、签名等
+func Equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func dummyHash() string {
+	hashMux.Lock()
+	defer hashMux.Unlock()
+	if dummy == "" {
+		dummy, _ = current.Hash("go-admin")
+	}
+	return dummy
+}
@@ -0,0 +1,178 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+var ErrWeakPassword = errors.New("security: weak password")
+
+// 不满足策略的原因
+const (
+	ViolationTooShort = "too_short"
+	ViolationTooLong  = "too_long"
+	ViolationUpper    = "upper"
+	ViolationLower    = "lower"
+	ViolationDigit    = "digit"
+	ViolationSymbol   = "symbol"
+	ViolationClasses  = "classes"
+	ViolationUsername = "username"
+	ViolationReused   = "reused"
+	ViolationBreached = "breached"
+)
+
+// PolicyError 密码不满足策略, errors.Is(err, ErrWeakPassword) 为true
+type PolicyError struct {
+	Violations []string
+}
+
+func (e *PolicyError) Error() string {
+	return ErrWeakPassword.Error() + ": " + strings.Join(e.Violations, ", ")
+}
+
+func (e *PolicyError) Is(target error) bool {
+	return target == ErrWeakPassword
+}
+
+// Policy 密码复杂度与有效期策略
+type Policy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// MinClasses 至少包含大写、小写、数字、符号中的几类
+	MinClasses int
+	// NotContainUsername 不能包含用户名(不区分大小写)
+	NotContainUsername bool
+	// History 不能与最近 History 个密码相同
+	History int
+	// MaxAge 密码有效期, 0为永不过期
+	MaxAge time.Duration
+	// Breach 泄露密码检查, 检查出错时放行并记录日志
+	Breach BreachChecker
+}
+
+// DefaultPolicy 8到64位, 至少包含两类字符
+func DefaultPolicy() *Policy {
+	return &Policy{MinLength: 8, MaxLength: 64, MinClasses: 2}
+}
+
+var (
+	policyMux sync.RWMutex
+	policy    = DefaultPolicy()
+)
+
+// SetPolicy 设置全局密码策略
+func SetPolicy(p *Policy) {
+	policyMux.Lock()
+	defer policyMux.Unlock()
+	policy = p
+}
+
+// GetPolicy 全局密码策略
+func GetPolicy() *Policy {
+	policyMux.RLock()
+	defer policyMux.RUnlock()
+	return policy
+}
+
+// Validate 检查新密码, history 为最近使用过的密码hash, 按时间倒序
+func (p *Policy) Validate(ctx context.Context, password, username string, history ...string) error {
+	var violations []string
+	n := utf8.RuneCountInString(password)
+	if p.MinLength > 0 && n < p.MinLength {
+		violations = append(violations, ViolationTooShort)
+	}
+	if p.MaxLength > 0 && n > p.MaxLength {
+		violations = append(violations, ViolationTooLong)
+	}
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	for _, c := range []struct {
+		required, ok bool
+		violation    string
+	}{
+		{p.RequireUpper, upper, ViolationUpper},
+		{p.RequireLower, lower, ViolationLower},
+		{p.RequireDigit, digit, ViolationDigit},
+		{p.RequireSymbol, symbol, ViolationSymbol},
+	} {
+		if c.required && !c.ok {
+			violations = append(violations, c.violation)
+		}
+	}
+	if p.MinClasses > 0 && count(upper, lower, digit, symbol) < p.MinClasses {
+		violations = append(violations, ViolationClasses)
+	}
+	if p.NotContainUsername && username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		violations = append(violations, ViolationUsername)
+	}
+	if p.History > 0 {
+		if len(history) > p.History {
+			history = history[:p.History]
+		}
+		for _, h := range history {
+			if Verify(h, password) {
+				violations = append(violations, ViolationReused)
+				break
+			}
+		}
+	}
+	if p.Breach != nil && len(violations) == 0 {
+		breached, err := p.Breach.Breached(ctx, password)
+		if err != nil {
+			logger.Module("sdk.security").WithContext(ctx).Warn("breach check failed", "error", err)
+		}
+		if breached {
+			violations = append(violations, ViolationBreached)
+		}
+	}
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// ExpiresAt 密码过期时间, 永不过期时返回零值
+func (p *Policy) ExpiresAt(changedAt time.Time) time.Time {
+	if p.MaxAge <= 0 {
+		return time.Time{}
+	}
+	return changedAt.Add(p.MaxAge)
+}
+
+// Expired 密码是否已过期, 需要用户修改
+func (p *Policy) Expired(changedAt time.Time) bool {
+	at := p.ExpiresAt(changedAt)
+	return !at.IsZero() && !time.Now().Before(at)
+}
+
+func count(b ...bool) int {
+	n := 0
+	for _, v := range b {
+		if v {
+			n++
+		}
+	}
+	return n
+}
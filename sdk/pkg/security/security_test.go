@@ -0,0 +1,131 @@
+package security
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fastArgon2id() *Argon2id {
+	return &Argon2id{Time: 1, Memory: 1024, Threads: 1, KeyLen: 32, SaltLen: 16}
+}
+
+func TestHashVerify(t *testing.T) {
+	defer SetDefault(NewBcrypt(0))
+	SetDefault(NewBcrypt(4))
+	legacy, err := Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Verify(legacy, "secret") || Verify(legacy, "Secret") {
+		t.Error("bcrypt verify failed")
+	}
+
+	SetDefault(fastArgon2id())
+	h, err := Hash("secret")
+	if err != nil || !strings.HasPrefix(h, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Fatalf("hash = %q, err = %v", h, err)
+	}
+	if !Verify(h, "secret") || Verify(h, "secret2") {
+		t.Error("argon2id verify failed")
+	}
+	// 切换算法后旧密码仍可校验, 并提示重新计算
+	if !Verify(legacy, "secret") || !NeedsRehash(legacy) || NeedsRehash(h) {
+		t.Error("rehash detection failed")
+	}
+	SetDefault(&Argon2id{Time: 2, Memory: 1024, Threads: 1, KeyLen: 32, SaltLen: 16})
+	if !NeedsRehash(h) {
+		t.Error("changed params should need rehash")
+	}
+	for _, bad := range []string{"", "plain", "$argon2id$v=19$m=1024$x$y", "$argon2id$v=18$m=1024,t=1,p=1$c2FsdA$a2V5"} {
+		if Verify(bad, "secret") {
+			t.Errorf("%q should not verify", bad)
+		}
+	}
+	if !Equal("abc", "abc") || Equal("abc", "abd") {
+		t.Error("equal failed")
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	defer SetDefault(NewBcrypt(0))
+	SetDefault(NewBcrypt(4))
+	old, _ := Hash("OldPass#1")
+	p := &Policy{
+		MinLength:          8,
+		MaxLength:          16,
+		RequireDigit:       true,
+		MinClasses:         3,
+		NotContainUsername: true,
+		History:            2,
+		Breach:             BreachList(CommonPasswords...),
+	}
+	tests := []struct {
+		password string
+		want     []string
+	}{
+		{"Ab1#efgh", nil},
+		{"Ab1#", []string{ViolationTooShort}},
+		{"abcdefghijklmnopq", []string{ViolationTooLong, ViolationDigit, ViolationClasses}},
+		{"xAdmin#1", []string{ViolationUsername}},
+		{"OldPass#1", []string{ViolationReused}},
+		{"Password1", []string{}},
+	}
+	for _, tt := range tests {
+		err := p.Validate(context.Background(), tt.password, "admin", old)
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("%s: %v", tt.password, err)
+			}
+			continue
+		}
+		var pe *PolicyError
+		if !errors.As(err, &pe) || !errors.Is(err, ErrWeakPassword) {
+			t.Errorf("%s: err = %v", tt.password, err)
+			continue
+		}
+		if len(tt.want) > 0 && strings.Join(pe.Violations, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: violations = %v, want %v", tt.password, pe.Violations, tt.want)
+		}
+	}
+	// 常见弱密码
+	p = &Policy{Breach: BreachList(CommonPasswords...)}
+	if err := p.Validate(context.Background(), "Admin123", ""); err == nil {
+		t.Error("common password should be rejected")
+	}
+
+	p = &Policy{MaxAge: time.Hour}
+	if p.Expired(time.Now()) || !p.Expired(time.Now().Add(-2*time.Hour)) {
+		t.Error("expired failed")
+	}
+	if (&Policy{}).Expired(time.Time{}) {
+		t.Error("no max age should never expire")
+	}
+}
+
+func TestPwned(t *testing.T) {
+	sum := sha1.Sum([]byte("P@ssw0rd"))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/"+hash[:5]) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, "0018A45C4D1DEF81644B54AB7F969B88D65:3\r\n%s:52579\r\nFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF:0\r\n", hash[5:])
+	}))
+	defer srv.Close()
+	c := &Pwned{Endpoint: srv.URL + "/range/"}
+	if ok, err := c.Breached(context.Background(), "P@ssw0rd"); err != nil || !ok {
+		t.Errorf("ok = %v, err = %v", ok, err)
+	}
+	if ok, err := c.Breached(context.Background(), "another"); err == nil || ok {
+		t.Errorf("ok = %v, err = %v", ok, err)
+	}
+}
@@ -0,0 +1,35 @@
+package shutdown
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+type Option func(*options)
+
+type options struct {
+	timeout time.Duration
+	signals []os.Signal
+}
+
+func setDefault() options {
+	return options{
+		timeout: 30 * time.Second,
+		signals: []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+	}
+}
+
+// WithTimeout 整个关闭流程的最长时间, 超时后剩余步骤不再等待
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithSignals 触发关闭的信号, 默认为 SIGINT SIGTERM
+func WithSignals(signals ...os.Signal) Option {
+	return func(o *options) {
+		o.signals = signals
+	}
+}
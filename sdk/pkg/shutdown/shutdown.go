@@ -0,0 +1,185 @@
+// Package shutdown 收到退出信号后按依赖顺序关闭应用:
+// 停止接收请求 -> 停止队列消费 -> 释放锁 -> 停止运行时组件 -> 关闭数据库、redis -> 刷新日志
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bsm/redislock"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	sdklogger "github.com/go-admin-team/go-admin-core/sdk/pkg/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/runtime"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// 关闭阶段, 数值小的先执行, 同一阶段的步骤并发执行
+const (
+	PhaseServer    = 100
+	PhaseConsumer  = 200
+	PhaseLocker    = 300
+	PhaseComponent = 400
+	PhaseStorage   = 500
+	PhaseLogger    = 1000
+)
+
+// Func 关闭步骤
+type Func func(ctx context.Context) error
+
+type step struct {
+	phase int
+	name  string
+	f     Func
+}
+
+// Manager 关闭流程管理
+type Manager struct {
+	opts  options
+	mux   sync.Mutex
+	steps []step
+	once  sync.Once
+	err   error
+}
+
+// New 创建关闭流程管理
+func New(opts ...Option) *Manager {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Manager{opts: o}
+}
+
+// Add 注册关闭步骤
+func (m *Manager) Add(phase int, name string, f Func) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.steps = append(m.steps, step{phase: phase, name: name, f: f})
+}
+
+// AddServer 停止接收新请求, 等待处理中的请求完成
+func (m *Manager) AddServer(name string, srv *http.Server) {
+	m.Add(PhaseServer, name, srv.Shutdown)
+}
+
+// AddQueue 停止队列消费
+func (m *Manager) AddQueue(name string, q storage.AdapterQueue) {
+	m.Add(PhaseConsumer, name, func(context.Context) error {
+		q.Shutdown()
+		return nil
+	})
+}
+
+// AddLock 释放持有的分布式锁
+func (m *Manager) AddLock(name string, lock *redislock.Lock) {
+	m.Add(PhaseLocker, name, func(ctx context.Context) error {
+		err := lock.Release(ctx)
+		if errors.Is(err, redislock.ErrLockNotHeld) {
+			return nil
+		}
+		return err
+	})
+}
+
+// AddRuntime 逆序停止运行时中注册的组件, 见 runtime.Application.AddComponent
+func (m *Manager) AddRuntime(r runtime.Runtime) {
+	m.Add(PhaseComponent, "runtime", r.Shutdown)
+}
+
+// AddCloser 关闭数据库、redis等连接
+func (m *Manager) AddCloser(name string, c io.Closer) {
+	m.Add(PhaseStorage, name, func(context.Context) error {
+		return c.Close()
+	})
+}
+
+// AddLogger 最后刷新并关闭日志输出
+func (m *Manager) AddLogger() {
+	m.Add(PhaseLogger, "logger", func(context.Context) error {
+		return sdklogger.Close()
+	})
+}
+
+// Wait 阻塞直到收到信号或ctx结束, 然后执行关闭流程
+func (m *Manager) Wait(ctx context.Context) error {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, m.opts.signals...)
+	defer signal.Stop(ch)
+	select {
+	case sig := <-ch:
+		logger.Module("sdk.shutdown").Info("shutdown signal received", "signal", sig.String())
+	case <-ctx.Done():
+	}
+	return m.Shutdown()
+}
+
+// Shutdown 按阶段执行关闭步骤, 多次调用只执行一次
+func (m *Manager) Shutdown() error {
+	m.once.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), m.opts.timeout)
+		defer cancel()
+		m.err = m.run(ctx)
+	})
+	return m.err
+}
+
+func (m *Manager) run(ctx context.Context) error {
+	m.mux.Lock()
+	steps := make([]step, len(m.steps))
+	copy(steps, m.steps)
+	m.mux.Unlock()
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].phase < steps[j].phase
+	})
+
+	var (
+		errs   []string
+		errMux sync.Mutex
+	)
+	for i := 0; i < len(steps); {
+		j := i
+		for j < len(steps) && steps[j].phase == steps[i].phase {
+			j++
+		}
+		if err := ctx.Err(); err != nil {
+			for _, s := range steps[i:] {
+				errs = append(errs, fmt.Sprintf("%s skipped, %s", s.name, err.Error()))
+			}
+			break
+		}
+		var wg sync.WaitGroup
+		for _, s := range steps[i:j] {
+			wg.Add(1)
+			go func(s step) {
+				defer wg.Done()
+				if err := s.f(ctx); err != nil {
+					if s.phase < PhaseLogger {
+						logger.Module("sdk.shutdown").Error("shutdown step failed", "step", s.name, "error", err)
+					}
+					errMux.Lock()
+					errs = append(errs, fmt.Sprintf("%s failed, %s", s.name, err.Error()))
+					errMux.Unlock()
+					return
+				}
+				if s.phase < PhaseLogger {
+					logger.Module("sdk.shutdown").Info("shutdown step done", "step", s.name)
+				}
+			}(s)
+		}
+		wg.Wait()
+		i = j
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
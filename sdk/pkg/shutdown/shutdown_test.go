@@ -0,0 +1,61 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestManager_Shutdown(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	var (
+		mux   sync.Mutex
+		order []string
+	)
+	add := func(phase int, name string, err error) {
+		m.Add(phase, name, func(context.Context) error {
+			mux.Lock()
+			order = append(order, name)
+			mux.Unlock()
+			return err
+		})
+	}
+	add(PhaseLogger, "logger", nil)
+	add(PhaseStorage, "db", errors.New("closed"))
+	add(PhaseServer, "http", nil)
+	add(PhaseConsumer, "queue", nil)
+
+	err := m.Shutdown()
+	if err == nil || !strings.Contains(err.Error(), "db failed") {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if got := strings.Join(order, ","); got != "http,queue,db,logger" {
+		t.Errorf("order = %s", got)
+	}
+	if m.Shutdown() != err {
+		t.Error("Shutdown() should run once")
+	}
+}
+
+func TestManager_Timeout(t *testing.T) {
+	m := New(WithTimeout(50 * time.Millisecond))
+	m.Add(PhaseServer, "http", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	called := false
+	m.Add(PhaseStorage, "db", func(context.Context) error {
+		called = true
+		return nil
+	})
+	err := m.Shutdown()
+	if err == nil || !strings.Contains(err.Error(), "db skipped") {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if called {
+		t.Error("db should be skipped after deadline")
+	}
+}
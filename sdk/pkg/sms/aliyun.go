@@ -0,0 +1,181 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AliyunConfig 阿里云短信配置
+type AliyunConfig struct {
+	AccessKeyID     string `json:"accessKeyId"`
+	AccessKeySecret string `json:"accessKeySecret"`
+	// RegionID 默认 cn-hangzhou
+	RegionID string `json:"regionId"`
+	// Endpoint 默认 https://dysmsapi.aliyuncs.com
+	Endpoint string `json:"endpoint"`
+}
+
+// Aliyun 阿里云短信服务
+type Aliyun struct {
+	c      AliyunConfig
+	client *http.Client
+	now    func() time.Time
+	nonce  func() string
+}
+
+func NewAliyun(c AliyunConfig) *Aliyun {
+	if c.RegionID == "" {
+		c.RegionID = "cn-hangzhou"
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = "https://dysmsapi.aliyuncs.com"
+	}
+	c.Endpoint = strings.TrimSuffix(c.Endpoint, "/")
+	return &Aliyun{c: c, client: &http.Client{Timeout: 10 * time.Second}, now: time.Now, nonce: nonce}
+}
+
+func (*Aliyun) String() string {
+	return "aliyun"
+}
+
+func (e *Aliyun) Send(ctx context.Context, m *Message) (string, error) {
+	param, err := json.Marshal(m.Params)
+	if err != nil {
+		return "", err
+	}
+	q := url.Values{}
+	q.Set("AccessKeyId", e.c.AccessKeyID)
+	q.Set("Action", "SendSms")
+	q.Set("Format", "JSON")
+	q.Set("PhoneNumbers", strings.TrimPrefix(m.Phone, "+86"))
+	q.Set("RegionId", e.c.RegionID)
+	q.Set("SignName", m.SignName)
+	q.Set("SignatureMethod", "HMAC-SHA1")
+	q.Set("SignatureNonce", e.nonce())
+	q.Set("SignatureVersion", "1.0")
+	q.Set("TemplateCode", m.Template.Code)
+	q.Set("TemplateParam", string(param))
+	q.Set("Timestamp", e.now().UTC().Format("2006-01-02T15:04:05Z"))
+	q.Set("Version", "2017-05-25")
+	q.Set("Signature", e.sign(http.MethodGet, q))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.c.Endpoint+"/?"+q.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	var rsp struct {
+		Code      string
+		Message   string
+		BizId     string
+		RequestId string
+	}
+	if err = doJSON(e.client, req, &rsp); err != nil {
+		return "", err
+	}
+	if rsp.Code != "OK" {
+		return "", fmt.Errorf("sms: aliyun: %s: %s", rsp.Code, rsp.Message)
+	}
+	return rsp.BizId, nil
+}
+
+// sign RPC 风格签名, HMAC-SHA1
+func (e *Aliyun) sign(method string, q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = percentEncode(k) + "=" + percentEncode(q.Get(k))
+	}
+	toSign := method + "&" + percentEncode("/") + "&" + percentEncode(strings.Join(pairs, "&"))
+	h := hmac.New(sha1.New, []byte(e.c.AccessKeySecret+"&"))
+	h.Write([]byte(toSign))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func percentEncode(s string) string {
+	s = url.QueryEscape(s)
+	s = strings.ReplaceAll(s, "+", "%20")
+	s = strings.ReplaceAll(s, "*", "%2A")
+	return strings.ReplaceAll(s, "%7E", "~")
+}
+
+// ParseCallback 解析短信回执(SmsReport), 请求体为 JSON 数组
+func (*Aliyun) ParseCallback(r *http.Request) ([]Status, error) {
+	var reports []struct {
+		PhoneNumber string `json:"phone_number"`
+		Success     bool   `json:"success"`
+		ErrCode     string `json:"err_code"`
+		ErrMsg      string `json:"err_msg"`
+		ReportTime  string `json:"report_time"`
+		BizID       string `json:"biz_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&reports); err != nil {
+		return nil, err
+	}
+	list := make([]Status, len(reports))
+	for i, report := range reports {
+		list[i] = Status{
+			ID:         report.BizID,
+			Phone:      report.PhoneNumber,
+			Status:     StatusDelivered,
+			Code:       report.ErrCode,
+			Message:    report.ErrMsg,
+			ReportTime: report.ReportTime,
+		}
+		if !report.Success {
+			list[i].Status = StatusFailed
+		}
+	}
+	return list, nil
+}
+
+func (*Aliyun) Ack(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"code":1,"msg":"invalid report"}`)
+		return
+	}
+	_, _ = io.WriteString(w, `{"code":0,"msg":"成功"}`)
+}
+
+func nonce() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// doJSON 发送请求并解析 JSON 响应, 非2xx且无法解析时返回错误
+func doJSON(client *http.Client, req *http.Request, v interface{}) error {
+	rsp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(rsp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		if rsp.StatusCode >= 300 {
+			return fmt.Errorf("sms: %s: %s", rsp.Status, strings.TrimSpace(string(b)))
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package sms
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// Config 短信配置, 可通过 config.RegisterExtend("sms", &sms.Config{}) 从配置文件加载
+type Config struct {
+	// Driver aliyun、tencent、twilio
+	Driver   string `json:"driver" validate:"oneof=aliyun tencent twilio"`
+	SignName string `json:"signName"`
+	// Interval 同一手机号的发送间隔(秒)
+	Interval   int                  `json:"interval"`
+	DailyLimit int                  `json:"dailyLimit"`
+	Templates  map[string]*Template `json:"templates"`
+	Aliyun     AliyunConfig         `json:"aliyun"`
+	Tencent    TencentConfig        `json:"tencent"`
+	Twilio     TwilioConfig         `json:"twilio"`
+}
+
+// Provider 按 Driver 创建短信服务
+func (c *Config) Provider() (Provider, error) {
+	switch c.Driver {
+	case "aliyun":
+		return NewAliyun(c.Aliyun), nil
+	case "tencent":
+		return NewTencent(c.Tencent), nil
+	case "twilio":
+		return NewTwilio(c.Twilio), nil
+	}
+	return nil, fmt.Errorf("sms: unknown driver %q", c.Driver)
+}
+
+// NewSender 按配置创建 Sender, cache 用于限流, 可为 nil
+func (c *Config) NewSender(cache storage.AdapterCache, opts ...Option) (*Sender, error) {
+	p, err := c.Provider()
+	if err != nil {
+		return nil, err
+	}
+	list := []Option{WithCache(cache), WithSignName(c.SignName)}
+	if c.Interval > 0 {
+		list = append(list, WithInterval(time.Duration(c.Interval)*time.Second))
+	}
+	if c.DailyLimit > 0 {
+		list = append(list, WithDailyLimit(c.DailyLimit))
+	}
+	for name, t := range c.Templates {
+		list = append(list, WithTemplate(name, t))
+	}
+	return New(p, append(list, opts...)...), nil
+}
@@ -0,0 +1,208 @@
+package sms
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/validate"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+type Option func(*options)
+
+type options struct {
+	cache      storage.AdapterCache
+	prefix     string
+	interval   time.Duration
+	dailyLimit int
+	signName   string
+	templates  map[string]*Template
+}
+
+func setDefault() options {
+	return options{
+		prefix:     "sms:",
+		interval:   time.Minute,
+		dailyLimit: 10,
+		templates:  make(map[string]*Template),
+	}
+}
+
+// WithCache 限流使用的缓存, 未设置时不限流
+func WithCache(c storage.AdapterCache) Option {
+	return func(o *options) {
+		o.cache = c
+	}
+}
+
+// WithKeyPrefix 限流缓存key的前缀, 默认 "sms:"
+func WithKeyPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithInterval 同一手机号两次发送的最小间隔, 默认1分钟, 0为不限制
+func WithInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.interval = d
+	}
+}
+
+// WithDailyLimit 同一手机号每天的发送上限, 默认10, 0为不限制
+func WithDailyLimit(n int) Option {
+	return func(o *options) {
+		o.dailyLimit = n
+	}
+}
+
+// WithSignName 默认签名
+func WithSignName(name string) Option {
+	return func(o *options) {
+		o.signName = name
+	}
+}
+
+// WithTemplate 注册模板, name 为业务中使用的名称, 如 "login"
+func WithTemplate(name string, t *Template) Option {
+	return func(o *options) {
+		o.templates[name] = t
+	}
+}
+
+// Sender 短信发送, 负责模板参数校验与按手机号限流
+type Sender struct {
+	provider Provider
+	opts     options
+}
+
+func New(p Provider, opts ...Option) *Sender {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Sender{provider: p, opts: o}
+}
+
+func (e *Sender) String() string {
+	return e.provider.String()
+}
+
+// Send 使用模板发送短信, 返回服务商的流水号
+func (e *Sender) Send(ctx context.Context, phone, name string, params map[string]string) (string, error) {
+	t, ok := e.opts.templates[name]
+	if !ok {
+		return "", ErrTemplateNotFound
+	}
+	if err := t.Validate(params); err != nil {
+		return "", err
+	}
+	if !isPhone(phone) {
+		return "", ErrInvalidPhone
+	}
+	if err := e.allow(phone); err != nil {
+		return "", err
+	}
+	log := logger.Module("sdk.sms").WithContext(ctx).
+		With("provider", e.provider.String(), "phone", phone, "template", t.Code)
+	id, err := e.provider.Send(ctx, &Message{
+		Phone:    phone,
+		SignName: e.opts.signName,
+		Template: t,
+		Params:   params,
+	})
+	if err != nil {
+		log.Error("send sms failed", "error", err)
+		return "", err
+	}
+	log.Info("sms sent", "id", id)
+	return id, nil
+}
+
+// isPhone 国际号码需带 + 前缀, 否则按大陆手机号校验
+func isPhone(phone string) bool {
+	if strings.HasPrefix(phone, "+") {
+		if len(phone) < 8 || len(phone) > 16 {
+			return false
+		}
+		for _, c := range phone[1:] {
+			if c < '0' || c > '9' {
+				return false
+			}
+		}
+		return true
+	}
+	return validate.IsMobile(phone)
+}
+
+// allow 检查并记录发送次数, 在调用服务商之前计数, 避免失败重试绕过限制
+func (e *Sender) allow(phone string) error {
+	if e.opts.cache == nil {
+		return nil
+	}
+	intervalKey := e.opts.prefix + "interval:" + phone
+	if e.opts.interval > 0 {
+		if v, _ := e.opts.cache.Get(intervalKey); v != "" {
+			return ErrTooFrequent
+		}
+	}
+	if e.opts.dailyLimit > 0 {
+		now := time.Now()
+		dailyKey := e.opts.prefix + "daily:" + now.Format("20060102") + ":" + phone
+		v, _ := e.opts.cache.Get(dailyKey)
+		if v == "" {
+			// 保留到次日零点之后
+			end := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+			if err := e.opts.cache.Set(dailyKey, 1, int(end.Sub(now).Seconds())+1); err != nil {
+				return err
+			}
+		} else {
+			n, _ := strconv.Atoi(v)
+			if n >= e.opts.dailyLimit {
+				return ErrDailyLimit
+			}
+			if err := e.opts.cache.Increase(dailyKey); err != nil {
+				return err
+			}
+		}
+	}
+	if e.opts.interval > 0 {
+		seconds := int(e.opts.interval / time.Second)
+		if seconds < 1 {
+			seconds = 1
+		}
+		return e.opts.cache.Set(intervalKey, 1, seconds)
+	}
+	return nil
+}
+
+// Callback 状态回执的处理接口, 服务商不支持回执时返回404
+func (e *Sender) Callback(fn func(ctx context.Context, s *Status)) gin.HandlerFunc {
+	cb, ok := e.provider.(Callbacker)
+	return func(c *gin.Context) {
+		if !ok {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		ctx := c.Request.Context()
+		list, err := cb.ParseCallback(c.Request)
+		if err != nil {
+			logger.Module("sdk.sms").WithContext(ctx).
+				Warn("invalid sms callback", "provider", e.provider.String(), "error", err)
+			cb.Ack(c.Writer, err)
+			c.Abort()
+			return
+		}
+		for i := range list {
+			list[i].Provider = e.provider.String()
+			fn(ctx, &list[i])
+		}
+		cb.Ack(c.Writer, nil)
+	}
+}
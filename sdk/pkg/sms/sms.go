@@ -0,0 +1,134 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+)
+
+var (
+	ErrTemplateNotFound = errors.New("sms: template not found")
+	ErrInvalidPhone     = errors.New("sms: invalid phone number")
+	ErrTooFrequent      = errors.New("sms: send too frequently")
+	ErrDailyLimit       = errors.New("sms: daily limit exceeded")
+)
+
+func init() {
+	response.RegisterError(ErrInvalidPhone, response.NewError(400, http.StatusBadRequest, "sms.invalid_phone", "手机号格式错误"))
+	response.RegisterError(ErrTooFrequent, response.NewError(429, http.StatusTooManyRequests, "sms.too_frequent", "短信发送过于频繁, 请稍后再试"))
+	response.RegisterError(ErrDailyLimit, response.NewError(429, http.StatusTooManyRequests, "sms.daily_limit", "今日短信发送次数已达上限"))
+}
+
+// Provider 短信服务
+type Provider interface {
+	String() string
+	// Send 发送短信, 返回服务商的流水号, 用于关联状态回执
+	Send(ctx context.Context, m *Message) (string, error)
+}
+
+// Callbacker 支持状态回执的服务
+type Callbacker interface {
+	// ParseCallback 解析并校验回执请求
+	ParseCallback(r *http.Request) ([]Status, error)
+	// Ack 按服务商要求应答回执, err 为解析或处理失败的原因
+	Ack(w http.ResponseWriter, err error)
+}
+
+// Message 短信
+type Message struct {
+	Phone    string
+	SignName string
+	Template *Template
+	Params   map[string]string
+}
+
+// Template 短信模板
+type Template struct {
+	// Code 服务商的模板id
+	Code string `json:"code"`
+	// Params 模板参数, 按顺序传给按位置填充参数的服务商(如腾讯云)
+	Params []string `json:"params"`
+	// Text 模板内容, 使用 text/template, 供直接发送内容的服务商(如Twilio)使用
+	Text string `json:"text"`
+	// MaxLen 单个参数的最大长度, 0为不限制
+	MaxLen int `json:"maxLen"`
+}
+
+// Validate 校验参数: 必须提供全部参数, 不能有多余参数, 长度不能超过 MaxLen
+func (t *Template) Validate(params map[string]string) error {
+	for _, name := range t.Params {
+		v, ok := params[name]
+		if !ok || v == "" {
+			return fmt.Errorf("sms: template %s: missing param %s", t.Code, name)
+		}
+		if t.MaxLen > 0 && utf8.RuneCountInString(v) > t.MaxLen {
+			return fmt.Errorf("sms: template %s: param %s exceeds %d characters", t.Code, name, t.MaxLen)
+		}
+	}
+	if len(params) > len(t.Params) {
+		for name := range params {
+			if !t.hasParam(name) {
+				return fmt.Errorf("sms: template %s: unknown param %s", t.Code, name)
+			}
+		}
+	}
+	return nil
+}
+
+func (t *Template) hasParam(name string) bool {
+	for _, p := range t.Params {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Values 按 Params 顺序排列的参数值
+func (t *Template) Values(params map[string]string) []string {
+	values := make([]string, len(t.Params))
+	for i, name := range t.Params {
+		values[i] = params[name]
+	}
+	return values
+}
+
+// Render 渲染 Text
+func (t *Template) Render(params map[string]string) (string, error) {
+	tpl, err := template.New(t.Code).Option("missingkey=error").Parse(t.Text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err = tpl.Execute(&buf, params); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// 回执状态
+const (
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+	// StatusSent 已提交运营商, 尚未收到终态
+	StatusSent = "sent"
+)
+
+// Status 短信状态回执
+type Status struct {
+	// ID 发送时返回的流水号
+	ID       string `json:"id"`
+	Phone    string `json:"phone"`
+	Status   string `json:"status"`
+	Code     string `json:"code,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Provider string `json:"provider"`
+	// ReportTime 运营商的回执时间, 格式由服务商决定
+	ReportTime string `json:"reportTime,omitempty"`
+}
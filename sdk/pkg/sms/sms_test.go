@@ -0,0 +1,171 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+func TestTemplateValidate(t *testing.T) {
+	tpl := &Template{Code: "SMS_1", Params: []string{"code", "minute"}, MaxLen: 6}
+	tests := []struct {
+		params map[string]string
+		ok     bool
+	}{
+		{map[string]string{"code": "123456", "minute": "5"}, true},
+		{map[string]string{"code": "123456"}, false},
+		{map[string]string{"code": "1234567", "minute": "5"}, false},
+		{map[string]string{"code": "1", "minute": "5", "x": "1"}, false},
+	}
+	for i, tt := range tests {
+		if err := tpl.Validate(tt.params); (err == nil) != tt.ok {
+			t.Errorf("%d: err = %v", i, err)
+		}
+	}
+	if v := tpl.Values(map[string]string{"minute": "5", "code": "1"}); strings.Join(v, ",") != "1,5" {
+		t.Errorf("values = %v", v)
+	}
+}
+
+func TestAliyunSign(t *testing.T) {
+	// 阿里云文档中的签名示例
+	e := NewAliyun(AliyunConfig{AccessKeyID: "testId", AccessKeySecret: "testSecret"})
+	q := url.Values{}
+	q.Set("AccessKeyId", "testId")
+	q.Set("Action", "SendSms")
+	q.Set("Format", "XML")
+	q.Set("OutId", "123")
+	q.Set("PhoneNumbers", "15300000001")
+	q.Set("RegionId", "cn-hangzhou")
+	q.Set("SignName", "阿里云短信测试专用")
+	q.Set("SignatureMethod", "HMAC-SHA1")
+	q.Set("SignatureNonce", "45e25e9b-0a6f-4070-8c85-2956eda1b466")
+	q.Set("SignatureVersion", "1.0")
+	q.Set("TemplateCode", "SMS_71390007")
+	q.Set("TemplateParam", `{"customer":"test"}`)
+	q.Set("Timestamp", "2017-07-12T02:42:19Z")
+	q.Set("Version", "2017-05-25")
+	if s := e.sign(http.MethodGet, q); s != "zJDF+Lrzhj/ThnlvIToysFRq6t4=" {
+		t.Errorf("signature = %s", s)
+	}
+}
+
+func TestSenderRateLimit(t *testing.T) {
+	var phones []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("Signature") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		phones = append(phones, r.URL.Query().Get("PhoneNumbers"))
+		_, _ = w.Write([]byte(`{"Code":"OK","BizId":"biz-1"}`))
+	}))
+	defer srv.Close()
+
+	s := New(NewAliyun(AliyunConfig{Endpoint: srv.URL}),
+		WithCache(cache.NewMemory()),
+		WithKeyPrefix("test:sms:"),
+		WithInterval(time.Hour),
+		WithDailyLimit(1),
+		WithTemplate("login", &Template{Code: "SMS_1", Params: []string{"code"}}))
+	ctx := context.Background()
+	params := map[string]string{"code": "1234"}
+
+	if _, err := s.Send(ctx, "13800000000", "missing", params); err != ErrTemplateNotFound {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := s.Send(ctx, "123", "login", params); err != ErrInvalidPhone {
+		t.Errorf("err = %v", err)
+	}
+	id, err := s.Send(ctx, "13800000000", "login", params)
+	if err != nil || id != "biz-1" {
+		t.Fatalf("id = %s, err = %v", id, err)
+	}
+	if _, err = s.Send(ctx, "13800000000", "login", params); err != ErrTooFrequent {
+		t.Errorf("err = %v", err)
+	}
+	if _, err = s.Send(ctx, "13900000000", "login", params); err != nil {
+		t.Errorf("err = %v", err)
+	}
+	if len(phones) != 2 {
+		t.Errorf("phones = %v", phones)
+	}
+
+	s = New(s.provider, WithCache(cache.NewMemory()), WithInterval(0), WithDailyLimit(1),
+		WithTemplate("login", &Template{Code: "SMS_1", Params: []string{"code"}}))
+	if _, err = s.Send(ctx, "13800000000", "login", params); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Send(ctx, "13800000000", "login", params); !errors.Is(err, ErrDailyLimit) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestTencentSend(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "TC3-HMAC-SHA256 Credential=id/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"Response":{"SendStatusSet":[{"SerialNo":"sn-1","Code":"Ok"}]}}`))
+	}))
+	defer srv.Close()
+
+	s := New(NewTencent(TencentConfig{SecretID: "id", SecretKey: "key", SdkAppID: "1400"}),
+		WithTemplate("login", &Template{Code: "100", Params: []string{"code", "minute"}}))
+	s.provider.(*Tencent).c.Endpoint = srv.URL
+	id, err := s.Send(context.Background(), "13800000000", "login", map[string]string{"minute": "5", "code": "1234"})
+	if err != nil || id != "sn-1" {
+		t.Fatalf("id = %s, err = %v", id, err)
+	}
+	if got, _ := json.Marshal(body["TemplateParamSet"]); string(got) != `["1234","5"]` {
+		t.Errorf("params = %s", got)
+	}
+	if got, _ := json.Marshal(body["PhoneNumberSet"]); string(got) != `["+8613800000000"]` {
+		t.Errorf("phones = %s", got)
+	}
+}
+
+func TestTwilioCallback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	p := NewTwilio(TwilioConfig{AuthToken: "token", CallbackURL: "https://example.com/sms/callback"})
+	var got []Status
+	r := gin.New()
+	r.POST("/sms/callback", New(p).Callback(func(_ context.Context, s *Status) {
+		got = append(got, *s)
+	}))
+
+	form := url.Values{"MessageSid": {"SM1"}, "MessageStatus": {"undelivered"}, "To": {"+15550001111"}, "ErrorCode": {"30003"}}
+	h := hmac.New(sha1.New, []byte("token"))
+	h.Write([]byte("https://example.com/sms/callback" + "ErrorCode30003MessageSidSM1MessageStatusundeliveredTo+15550001111"))
+	signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
+
+	for _, sig := range []string{"bad", signature} {
+		req := httptest.NewRequest(http.MethodPost, "/sms/callback", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Twilio-Signature", sig)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if sig == "bad" && w.Code != http.StatusForbidden {
+			t.Errorf("bad signature status = %d", w.Code)
+		}
+	}
+	if len(got) != 1 || got[0].ID != "SM1" || got[0].Status != StatusFailed || got[0].Provider != "twilio" {
+		t.Errorf("status = %+v", got)
+	}
+}
@@ -0,0 +1,181 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TencentConfig 腾讯云短信配置
+type TencentConfig struct {
+	SecretID  string `json:"secretId"`
+	SecretKey string `json:"secretKey"`
+	// SdkAppID 短信应用id
+	SdkAppID string `json:"sdkAppId"`
+	// Region 默认 ap-guangzhou
+	Region string `json:"region"`
+	// Endpoint 默认 https://sms.tencentcloudapi.com
+	Endpoint string `json:"endpoint"`
+}
+
+// Tencent 腾讯云短信服务, 模板参数按 Template.Params 的顺序传递
+type Tencent struct {
+	c      TencentConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewTencent(c TencentConfig) *Tencent {
+	if c.Region == "" {
+		c.Region = "ap-guangzhou"
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = "https://sms.tencentcloudapi.com"
+	}
+	c.Endpoint = strings.TrimSuffix(c.Endpoint, "/")
+	return &Tencent{c: c, client: &http.Client{Timeout: 10 * time.Second}, now: time.Now}
+}
+
+func (*Tencent) String() string {
+	return "tencent"
+}
+
+func (e *Tencent) Send(ctx context.Context, m *Message) (string, error) {
+	phone := m.Phone
+	if !strings.HasPrefix(phone, "+") {
+		phone = "+86" + phone
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"PhoneNumberSet":   []string{phone},
+		"SmsSdkAppId":      e.c.SdkAppID,
+		"SignName":         m.SignName,
+		"TemplateId":       m.Template.Code,
+		"TemplateParamSet": m.Template.Values(m.Params),
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.c.Endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("X-TC-Action", "SendSms")
+	req.Header.Set("X-TC-Version", "2021-01-11")
+	req.Header.Set("X-TC-Region", e.c.Region)
+	e.sign(req, body)
+
+	var rsp struct {
+		Response struct {
+			SendStatusSet []struct {
+				SerialNo string
+				Code     string
+				Message  string
+			}
+			Error *struct {
+				Code    string
+				Message string
+			}
+		}
+	}
+	if err = doJSON(e.client, req, &rsp); err != nil {
+		return "", err
+	}
+	if rsp.Response.Error != nil {
+		return "", fmt.Errorf("sms: tencent: %s: %s", rsp.Response.Error.Code, rsp.Response.Error.Message)
+	}
+	if len(rsp.Response.SendStatusSet) == 0 {
+		return "", fmt.Errorf("sms: tencent: empty response")
+	}
+	status := rsp.Response.SendStatusSet[0]
+	if status.Code != "Ok" {
+		return "", fmt.Errorf("sms: tencent: %s: %s", status.Code, status.Message)
+	}
+	return status.SerialNo, nil
+}
+
+// sign TC3-HMAC-SHA256 签名
+func (e *Tencent) sign(r *http.Request, body []byte) {
+	now := e.now().UTC()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	date := now.Format("2006-01-02")
+	u, _ := url.Parse(e.c.Endpoint)
+	host := u.Host
+	r.Header.Set("X-TC-Timestamp", timestamp)
+
+	payload := sha256.Sum256(body)
+	canonical := "POST\n/\n\n" +
+		"content-type:" + r.Header.Get("Content-Type") + "\n" +
+		"host:" + host + "\n\n" +
+		"content-type;host\n" +
+		hex.EncodeToString(payload[:])
+	hash := sha256.Sum256([]byte(canonical))
+	scope := date + "/sms/tc3_request"
+	toSign := "TC3-HMAC-SHA256\n" + timestamp + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("TC3"+e.c.SecretKey), date)
+	key = hmacSHA256(key, "sms")
+	key = hmacSHA256(key, "tc3_request")
+	r.Header.Set("Authorization", "TC3-HMAC-SHA256 Credential="+e.c.SecretID+"/"+scope+
+		", SignedHeaders=content-type;host, Signature="+hex.EncodeToString(hmacSHA256(key, toSign)))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// ParseCallback 解析短信下发状态回调, 请求体为 JSON 数组
+func (*Tencent) ParseCallback(r *http.Request) ([]Status, error) {
+	var reports []struct {
+		UserReceiveTime string `json:"user_receive_time"`
+		NationCode      string `json:"nationcode"`
+		Mobile          string `json:"mobile"`
+		ReportStatus    string `json:"report_status"`
+		ErrMsg          string `json:"errmsg"`
+		Description     string `json:"description"`
+		Sid             string `json:"sid"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&reports); err != nil {
+		return nil, err
+	}
+	list := make([]Status, len(reports))
+	for i, report := range reports {
+		list[i] = Status{
+			ID:         report.Sid,
+			Phone:      report.Mobile,
+			Status:     StatusDelivered,
+			ReportTime: report.UserReceiveTime,
+		}
+		if report.NationCode != "" && report.NationCode != "86" {
+			list[i].Phone = "+" + report.NationCode + report.Mobile
+		}
+		if report.ReportStatus != "SUCCESS" {
+			list[i].Status = StatusFailed
+			list[i].Code = report.ErrMsg
+			list[i].Message = report.Description
+		}
+	}
+	return list, nil
+}
+
+func (*Tencent) Ack(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"result":1,"errmsg":"invalid report"}`)
+		return
+	}
+	_, _ = io.WriteString(w, `{"result":0,"errmsg":"OK"}`)
+}
@@ -0,0 +1,142 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TwilioConfig Twilio 配置, From 与 MessagingServiceSid 二选一
+type TwilioConfig struct {
+	AccountSid          string `json:"accountSid"`
+	AuthToken           string `json:"authToken"`
+	From                string `json:"from"`
+	MessagingServiceSid string `json:"messagingServiceSid"`
+	// CallbackURL 状态回调地址, 同时用于校验回调签名, 需与 Twilio 请求的地址完全一致
+	CallbackURL string `json:"callbackUrl"`
+	// Endpoint 默认 https://api.twilio.com
+	Endpoint string `json:"endpoint"`
+}
+
+// Twilio 使用 Template.Text 渲染短信内容后发送
+type Twilio struct {
+	c      TwilioConfig
+	client *http.Client
+}
+
+func NewTwilio(c TwilioConfig) *Twilio {
+	if c.Endpoint == "" {
+		c.Endpoint = "https://api.twilio.com"
+	}
+	c.Endpoint = strings.TrimSuffix(c.Endpoint, "/")
+	return &Twilio{c: c, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (*Twilio) String() string {
+	return "twilio"
+}
+
+func (e *Twilio) Send(ctx context.Context, m *Message) (string, error) {
+	body, err := m.Template.Render(m.Params)
+	if err != nil {
+		return "", err
+	}
+	if m.SignName != "" {
+		body = "[" + m.SignName + "] " + body
+	}
+	form := url.Values{}
+	form.Set("To", m.Phone)
+	form.Set("Body", body)
+	if e.c.MessagingServiceSid != "" {
+		form.Set("MessagingServiceSid", e.c.MessagingServiceSid)
+	} else {
+		form.Set("From", e.c.From)
+	}
+	if e.c.CallbackURL != "" {
+		form.Set("StatusCallback", e.c.CallbackURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		e.c.Endpoint+"/2010-04-01/Accounts/"+e.c.AccountSid+"/Messages.json", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(e.c.AccountSid, e.c.AuthToken)
+	var rsp struct {
+		Sid     string `json:"sid"`
+		Status  string `json:"status"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err = doJSON(e.client, req, &rsp); err != nil {
+		return "", err
+	}
+	if rsp.Sid == "" {
+		return "", fmt.Errorf("sms: twilio: %d: %s", rsp.Code, rsp.Message)
+	}
+	return rsp.Sid, nil
+}
+
+// ParseCallback 校验 X-Twilio-Signature 并解析状态回调
+func (e *Twilio) ParseCallback(r *http.Request) ([]Status, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	if !e.verify(r.Header.Get("X-Twilio-Signature"), r.PostForm) {
+		return nil, errors.New("sms: twilio: invalid signature")
+	}
+	s := Status{
+		ID:      r.PostForm.Get("MessageSid"),
+		Phone:   r.PostForm.Get("To"),
+		Code:    r.PostForm.Get("ErrorCode"),
+		Message: r.PostForm.Get("ErrorMessage"),
+	}
+	switch r.PostForm.Get("MessageStatus") {
+	case "delivered":
+		s.Status = StatusDelivered
+	case "failed", "undelivered":
+		s.Status = StatusFailed
+	default:
+		s.Status = StatusSent
+	}
+	return []Status{s}, nil
+}
+
+// verify 签名为 CallbackURL 拼接按 key 排序的参数后的 HMAC-SHA1
+func (e *Twilio) verify(signature string, form url.Values) bool {
+	if signature == "" {
+		return false
+	}
+	keys := make([]string, 0, len(form))
+	for k := range form {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	sb.WriteString(e.c.CallbackURL)
+	for _, k := range keys {
+		for _, v := range form[k] {
+			sb.WriteString(k + v)
+		}
+	}
+	h := hmac.New(sha1.New, []byte(e.c.AuthToken))
+	h.Write([]byte(sb.String()))
+	expected := base64.StdEncoding.EncodeToString(h.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func (*Twilio) Ack(w http.ResponseWriter, err error) {
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
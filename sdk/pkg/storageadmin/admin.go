@@ -0,0 +1,331 @@
+// Package storageadmin 缓存、队列与锁的查看接口, 供监控页面使用: 缓存统计与占用分析、按前缀查看与删除key、
+// 队列积压、死信与当前持有的锁
+//
+//	a := storageadmin.New(storageadmin.WithCache("default", sdk.Runtime.GetCacheAdapter()), storageadmin.WithDelete(),
+//		storageadmin.WithQueue("default", sdk.Runtime.GetQueueAdapter()),
+//		storageadmin.WithLocks(sdk.Runtime.GetCacheAdapter(), "cronjob:", "queue:reaper:"))
+//	a.Routes(authed.Group("/storage"))
+package storageadmin
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+var (
+	// ErrUnknown 未注册的缓存或队列
+	ErrUnknown = response.ErrNotFound
+	// ErrUnsupported 适配器不支持该查询, 如内存队列没有死信
+	ErrUnsupported = response.NewError(501, 501, "error.storage_unsupported", "存储不支持该操作")
+)
+
+type Option func(*options)
+
+type options struct {
+	caches      map[string]storage.AdapterCache
+	queues      map[string]storage.AdapterQueue
+	locks       storage.AdapterCache
+	lockPrefix  []string
+	maxKeys     int
+	allowDelete bool
+}
+
+func setDefault() options {
+	return options{
+		caches:  make(map[string]storage.AdapterCache),
+		queues:  make(map[string]storage.AdapterQueue),
+		maxKeys: 1000,
+	}
+}
+
+// WithCache 可查看的缓存, 可多次调用
+func WithCache(name string, c storage.AdapterCache) Option {
+	return func(o *options) {
+		o.caches[name] = c
+	}
+}
+
+// WithQueue 可查看的队列, 可多次调用
+func WithQueue(name string, q storage.AdapterQueue) Option {
+	return func(o *options) {
+		o.queues[name] = q
+	}
+}
+
+// WithLocks 锁所在的缓存与锁key的前缀, 需与锁使用同一个redis且未加前缀, 如 cache.Redis
+func WithLocks(c storage.AdapterCache, prefixes ...string) Option {
+	return func(o *options) {
+		o.locks = c
+		o.lockPrefix = append(o.lockPrefix, prefixes...)
+	}
+}
+
+// WithMaxKeys 列出key的最大数量, 默认1000
+func WithMaxKeys(n int) Option {
+	return func(o *options) {
+		o.maxKeys = n
+	}
+}
+
+// WithDelete 允许删除key, 默认只读
+func WithDelete() Option {
+	return func(o *options) {
+		o.allowDelete = true
+	}
+}
+
+// WithReadOnly 禁止删除key, 默认即为只读
+func WithReadOnly() Option {
+	return func(o *options) {
+		o.allowDelete = false
+	}
+}
+
+// Admin 存储查看
+type Admin struct {
+	o options
+}
+
+func New(opts ...Option) *Admin {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Admin{o: o}
+}
+
+// CacheStats 缓存统计, Info 为 redis INFO 中的常用指标, 内存缓存为空
+type CacheStats struct {
+	Name   string            `json:"name"`
+	Driver string            `json:"driver"`
+	Keys   int               `json:"keys"`
+	Info   map[string]string `json:"info,omitempty"`
+}
+
+// QueueStats 队列各 stream 的积压
+type QueueStats struct {
+	Name    string        `json:"name"`
+	Driver  string        `json:"driver"`
+	Streams []queue.Depth `json:"streams"`
+}
+
+// Lock 持有中的锁, TTL 为剩余时间, 无法获取时为0; 锁的 token 可用于释放他人的锁, 不返回
+type Lock struct {
+	Key string        `json:"key"`
+	TTL time.Duration `json:"ttl"`
+}
+
+// infoFields redis INFO 中返回的指标
+var infoFields = []string{"used_memory_human", "keyspace_hits", "keyspace_misses", "expired_keys", "evicted_keys", "connected_clients"}
+
+// Caches 全部缓存的统计, 按名称排序; redis 的 Keys 为所在库的key数(DBSIZE), 不遍历key;
+// 其他缓存最多统计 WithMaxKeys 个, 不支持列出key的缓存 Keys 为 -1
+func (a *Admin) Caches(ctx context.Context) []CacheStats {
+	list := make([]CacheStats, 0, len(a.o.caches))
+	for name, c := range a.o.caches {
+		s := CacheStats{Name: name, Driver: c.String(), Keys: -1}
+		if client := redisClient(c); client != nil {
+			if n, err := client.DBSize(ctx).Result(); err == nil {
+				s.Keys = int(n)
+			}
+			s.Info = redisInfo(ctx, client)
+		} else if keys, err := a.Keys(name, ""); err == nil {
+			s.Keys = len(keys)
+		}
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Keys 以 prefix 开头的key, 排序后最多返回 WithMaxKeys 个
+func (a *Admin) Keys(name, prefix string) ([]string, error) {
+	c, err := a.cache(name)
+	if err != nil {
+		return nil, err
+	}
+	kc, ok := c.(storage.AdapterKeysCache)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	keys, err := kc.Keys(prefix)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	if a.o.maxKeys > 0 && len(keys) > a.o.maxKeys {
+		keys = keys[:a.o.maxKeys]
+	}
+	return keys, nil
+}
+
+// Get 缓存中的值, key 与 Keys 返回的相同
+func (a *Admin) Get(name, key string) (string, error) {
+	c, err := a.cache(name)
+	if err != nil {
+		return "", err
+	}
+	v, err := c.Get(key)
+	if err == redis.Nil || err == nil && v == "" {
+		return "", response.ErrNotFound
+	}
+	return v, err
+}
+
+// Del 删除缓存中的key, 未设置 WithDelete 时返回 response.ErrForbidden
+func (a *Admin) Del(name, key string) error {
+	if !a.o.allowDelete {
+		return response.ErrForbidden
+	}
+	c, err := a.cache(name)
+	if err != nil {
+		return err
+	}
+	return c.Del(key)
+}
+
+// Usage 缓存按前缀的key数量、占用与 TTL 分布, 见 cache.Analyze
+func (a *Admin) Usage(ctx context.Context, name string, opts ...cache.UsageOption) (*cache.UsageReport, error) {
+	c, err := a.cache(name)
+	if err != nil {
+		return nil, err
+	}
+	report, err := cache.Analyze(ctx, c, opts...)
+	if err == cache.ErrUsageUnsupported {
+		return nil, ErrUnsupported
+	}
+	return report, err
+}
+
+// cache 逐层 Unwrap 找到支持列出key的缓存, 查看与删除也使用这一层, 保证key一致; 都不支持时为注册的缓存
+func (a *Admin) cache(name string) (storage.AdapterCache, error) {
+	c, ok := a.o.caches[name]
+	if !ok {
+		return nil, ErrUnknown
+	}
+	for inner := c; inner != nil; {
+		if _, ok = inner.(storage.AdapterKeysCache); ok {
+			return inner, nil
+		}
+		u, ok := inner.(interface{ Unwrap() storage.AdapterCache })
+		if !ok {
+			break
+		}
+		inner = u.Unwrap()
+	}
+	return c, nil
+}
+
+// Queues 全部队列的积压, 按名称排序; 不支持查询的队列 Streams 为空
+func (a *Admin) Queues(ctx context.Context) []QueueStats {
+	list := make([]QueueStats, 0, len(a.o.queues))
+	for name, q := range a.o.queues {
+		s := QueueStats{Name: name, Driver: q.String(), Streams: make([]queue.Depth, 0)}
+		if d, ok := findQueue(q).(queue.Depther); ok {
+			if depths, err := d.Depths(ctx); err == nil {
+				s.Streams = depths
+			}
+		}
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// DeadLetters 队列 stream 最近的死信, 新的在前
+func (a *Admin) DeadLetters(ctx context.Context, name, stream string, count int64) ([]redis.XMessage, error) {
+	q, ok := a.o.queues[name]
+	if !ok {
+		return nil, ErrUnknown
+	}
+	r, ok := findQueue(q).(*queue.Redis)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return r.DeadLetters(ctx, stream, count)
+}
+
+// Locks WithLocks 指定前缀下持有中的锁, 按key排序
+func (a *Admin) Locks(ctx context.Context) ([]Lock, error) {
+	list := make([]Lock, 0)
+	if a.o.locks == nil {
+		return list, nil
+	}
+	kc, ok := a.o.locks.(storage.AdapterKeysCache)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	client := redisClient(a.o.locks)
+	for _, prefix := range a.o.lockPrefix {
+		keys, err := kc.Keys(prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			l := Lock{Key: key}
+			if client != nil {
+				l.TTL, _ = client.PTTL(ctx, key).Result()
+			}
+			list = append(list, l)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list, nil
+}
+
+// findQueue 逐层 Unwrap 找到最内层的队列
+func findQueue(q storage.AdapterQueue) storage.AdapterQueue {
+	for {
+		if _, ok := q.(queue.Depther); ok {
+			return q
+		}
+		u, ok := q.(interface{ Unwrap() storage.AdapterQueue })
+		if !ok || u.Unwrap() == nil {
+			return q
+		}
+		q = u.Unwrap()
+	}
+}
+
+func redisClient(c storage.AdapterCache) *redis.Client {
+	for c != nil {
+		if r, ok := c.(interface{ GetClient() *redis.Client }); ok {
+			return r.GetClient()
+		}
+		u, ok := c.(interface{ Unwrap() storage.AdapterCache })
+		if !ok {
+			return nil
+		}
+		c = u.Unwrap()
+	}
+	return nil
+}
+
+func redisInfo(ctx context.Context, client *redis.Client) map[string]string {
+	text, err := client.Info(ctx, "memory", "stats", "clients").Result()
+	if err != nil {
+		return nil
+	}
+	all := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(line), ":"); ok {
+			all[k] = v
+		}
+	}
+	info := make(map[string]string)
+	for _, f := range infoFields {
+		if v, ok := all[f]; ok {
+			info[f] = v
+		}
+	}
+	return info
+}
@@ -0,0 +1,103 @@
+package storageadmin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/sdk/runtime"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+	"github.com/go-admin-team/go-admin-core/storage/namespace"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+func TestRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mem := cache.NewMemory()
+	_ = mem.Set("cronjob:1:100", "token", 60)
+	c := namespace.NewCache(mem, namespace.Dev)
+	_ = c.Set("user:1", "tom", 60)
+	_ = c.Set("user:2", "jerry", 60)
+	q := queue.NewMemory(10)
+	q.Register("export", func(storage.Messager) error { return nil })
+	a := New(WithCache("default", runtime.NewCache("", c, "")), WithCache("plain", struct{ storage.AdapterCache }{mem}),
+		WithQueue("default", runtime.NewQueue("", q)), WithLocks(mem, "cronjob:"), WithDelete())
+	r := gin.New()
+	a.Routes(r.Group("/storage"))
+	do := func(method, path string) (int, interface{}) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		res := struct{ Data interface{} }{}
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+		return w.Code, res.Data
+	}
+
+	if _, data := do(http.MethodGet, "/storage/caches"); len(data.([]interface{})) != 2 ||
+		data.([]interface{})[0].(map[string]interface{})["keys"] != float64(2) ||
+		data.([]interface{})[1].(map[string]interface{})["keys"] != float64(-1) {
+		t.Errorf("caches = %v", data)
+	}
+	if _, data := do(http.MethodGet, "/storage/caches/default/keys?prefix=user:"); len(data.([]interface{})) != 2 {
+		t.Errorf("keys = %v", data)
+	}
+	if _, data := do(http.MethodGet, "/storage/caches/default/usage?prefix=dev:&depth=2"); data == nil ||
+		data.(map[string]interface{})["prefixes"].([]interface{})[0].(map[string]interface{})["prefix"] != "dev:user:" {
+		t.Errorf("usage = %v", data)
+	}
+	if code, _ := do(http.MethodGet, "/storage/caches/plain/usage"); code != 501 {
+		t.Errorf("plain usage: code = %d", code)
+	}
+	if _, data := do(http.MethodGet, "/storage/caches/default/key?key=user:1"); data.(map[string]interface{})["value"] != "tom" {
+		t.Errorf("get = %v", data)
+	}
+	if code, _ := do(http.MethodDelete, "/storage/caches/default/key?key=user:1"); code != http.StatusOK {
+		t.Errorf("delete: code = %d", code)
+	}
+	if code, _ := do(http.MethodGet, "/storage/caches/default/key?key=user:1"); code != http.StatusNotFound {
+		t.Errorf("deleted key: code = %d", code)
+	}
+	if code, _ := do(http.MethodGet, "/storage/caches/missing/keys"); code != http.StatusNotFound {
+		t.Errorf("unknown cache: code = %d", code)
+	}
+	if _, data := do(http.MethodGet, "/storage/queues"); data.([]interface{})[0].(map[string]interface{})["driver"] != "memory" {
+		t.Errorf("queues = %v", data)
+	}
+	if code, _ := do(http.MethodGet, "/storage/queues/default/dead?stream=export"); code != 501 {
+		t.Errorf("memory dead letters: code = %d", code)
+	}
+	if _, data := do(http.MethodGet, "/storage/locks"); len(data.([]interface{})) != 1 ||
+		data.([]interface{})[0].(map[string]interface{})["token"] != nil {
+		t.Errorf("locks = %v", data)
+	}
+
+	// 默认只读
+	ro := gin.New()
+	New(WithCache("default", c)).Routes(ro)
+	w := httptest.NewRecorder()
+	ro.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/caches/default/key?key=user:2", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("default read only: code = %d", w.Code)
+	}
+}
+
+func TestCachesRedis(t *testing.T) {
+	s := miniredis.RunT(t)
+	r, err := cache.NewRedis(nil, &redis.Options{Addr: s.Addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = r.Set("a", 1, 60)
+	_ = r.Set("b", 1, 60)
+	// redis 使用 DBSIZE 统计, 不受 WithMaxKeys 限制
+	list := New(WithCache("redis", r), WithMaxKeys(1)).Caches(context.Background())
+	if len(list) != 1 || list[0].Keys != 2 {
+		t.Errorf("caches = %+v", list)
+	}
+}
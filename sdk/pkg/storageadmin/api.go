@@ -0,0 +1,113 @@
+package storageadmin
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+// Routes 存储查看接口, 可读取与删除任意缓存, 需自行挂载到有鉴权的路由组
+//
+//	GET    /caches                             缓存统计
+//	GET    /caches/:name/keys?prefix=          按前缀列出key
+//	GET    /caches/:name/usage?prefix=&depth=  按前缀统计key数量、占用与 TTL 分布, depth 默认1
+//	GET    /caches/:name/key?key=              读取key的值
+//	DELETE /caches/:name/key?key=              删除key
+//	GET    /queues                             队列积压
+//	GET    /queues/:name/dead?stream=&count=   最近的死信, count 默认50
+//	GET    /locks                              持有中的锁
+func (a *Admin) Routes(r gin.IRouter) {
+	r.GET("/caches", a.cachesHandler)
+	r.GET("/caches/:name/keys", a.keysHandler)
+	r.GET("/caches/:name/usage", a.usageHandler)
+	r.GET("/caches/:name/key", a.getHandler)
+	r.DELETE("/caches/:name/key", a.delHandler)
+	r.GET("/queues", a.queuesHandler)
+	r.GET("/queues/:name/dead", a.deadHandler)
+	r.GET("/locks", a.locksHandler)
+}
+
+func (a *Admin) cachesHandler(c *gin.Context) {
+	response.OK(c, a.Caches(c.Request.Context()), "")
+}
+
+func (a *Admin) keysHandler(c *gin.Context) {
+	keys, err := a.Keys(c.Param("name"), c.Query("prefix"))
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+	response.OK(c, keys, "")
+}
+
+func (a *Admin) usageHandler(c *gin.Context) {
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", "1"))
+	if err != nil || depth <= 0 {
+		response.Fail(c, response.ErrBadRequest)
+		return
+	}
+	report, err := a.Usage(c.Request.Context(), c.Param("name"), cache.WithUsageMatch(c.Query("prefix")), cache.WithUsageDepth(depth))
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+	response.OK(c, report, "")
+}
+
+func (a *Admin) getHandler(c *gin.Context) {
+	key := c.Query("key")
+	if key == "" {
+		response.Fail(c, response.ErrBadRequest)
+		return
+	}
+	v, err := a.Get(c.Param("name"), key)
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+	response.OK(c, gin.H{"key": key, "value": v}, "")
+}
+
+func (a *Admin) delHandler(c *gin.Context) {
+	key := c.Query("key")
+	if key == "" {
+		response.Fail(c, response.ErrBadRequest)
+		return
+	}
+	if err := a.Del(c.Param("name"), key); err != nil {
+		response.Fail(c, err)
+		return
+	}
+	response.OK(c, key, "")
+}
+
+func (a *Admin) queuesHandler(c *gin.Context) {
+	response.OK(c, a.Queues(c.Request.Context()), "")
+}
+
+func (a *Admin) deadHandler(c *gin.Context) {
+	stream := c.Query("stream")
+	count, err := strconv.ParseInt(c.DefaultQuery("count", "50"), 10, 64)
+	if stream == "" || err != nil || count <= 0 {
+		response.Fail(c, response.ErrBadRequest)
+		return
+	}
+	list, err := a.DeadLetters(c.Request.Context(), c.Param("name"), stream, count)
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+	response.OK(c, list, "")
+}
+
+func (a *Admin) locksHandler(c *gin.Context) {
+	list, err := a.Locks(c.Request.Context())
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+	response.OK(c, list, "")
+}
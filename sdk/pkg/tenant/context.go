@@ -0,0 +1,66 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+)
+
+// DefaultHeader 默认的租户请求头
+const DefaultHeader = "X-Tenant-Id"
+
+type tenantKey struct{}
+
+// NewContext 在ctx中设置租户
+func NewContext(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, key)
+}
+
+// FromContext 获取ctx中的租户, 不存在时返回 DefaultKey
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return DefaultKey
+	}
+	if key, ok := ctx.Value(tenantKey{}).(string); ok && key != "" {
+		return key
+	}
+	return DefaultKey
+}
+
+// Middleware 按 WithResolver 或 WithHeader 确定租户, 都未设置时为 DefaultKey;
+// 设置 "db" 供 pkg.GetOrm 和 api.MakeOrm 使用, 请求结束前连接不会因淘汰而关闭
+func (m *Manager) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := m.resolve(c)
+		ctx := NewContext(c.Request.Context(), key)
+		c.Request = c.Request.WithContext(ctx)
+		db, release, err := m.Acquire(key)
+		if err != nil {
+			response.Error(c, http.StatusNotFound, err, "")
+			return
+		}
+		defer release()
+		c.Set("tenant", key)
+		c.Set("db", db.WithContext(ctx))
+		c.Next()
+	}
+}
+
+func (m *Manager) resolve(c *gin.Context) string {
+	if len(m.opts.resolvers) > 0 {
+		if key := Chain(m.opts.resolvers...)(c); key != "" {
+			return key
+		}
+		return DefaultKey
+	}
+	if m.opts.header == "" {
+		return DefaultKey
+	}
+	if key := c.GetHeader(m.opts.header); key != "" {
+		return key
+	}
+	return Host()(c)
+}
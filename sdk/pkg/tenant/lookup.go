@@ -0,0 +1,29 @@
+package tenant
+
+import (
+	"fmt"
+
+	"github.com/go-admin-team/go-admin-core/sdk/config"
+	"github.com/go-admin-team/go-admin-core/tools/database"
+	"gorm.io/gorm"
+)
+
+// ConfigLookup 从 settings.databases 查询租户, key为租户(host)
+// opens 为driver对应的gorm驱动, 如 {"mysql": mysql.Open}, 未指定的使用 database.RegisterDialector 注册的驱动
+func ConfigLookup(opens map[string]func(string) gorm.Dialector) Lookup {
+	return func(tenant string) (*Source, error) {
+		c, ok := config.DatabasesConfig[tenant]
+		if !ok || c == nil {
+			return nil, ErrTenantNotFound
+		}
+		open := opens[c.Driver]
+		if open == nil {
+			d, err := database.Dialect(c.Driver)
+			if err != nil {
+				return nil, fmt.Errorf("tenant %s driver %s not supported", tenant, c.Driver)
+			}
+			open = d
+		}
+		return &Source{Configure: c.Configure(), Open: open}, nil
+	}
+}
@@ -0,0 +1,74 @@
+package tenant
+
+import (
+	"gorm.io/gorm"
+)
+
+type Option func(*options)
+
+type options struct {
+	lookup     Lookup
+	tenants    func() []string
+	gormConfig *gorm.Config
+	maxTenants int
+	header     string
+	resolvers  []Resolver
+	fallback   bool
+}
+
+func setDefault() options {
+	return options{
+		gormConfig: &gorm.Config{},
+		maxTenants: DefaultMaxTenants,
+	}
+}
+
+// WithLookup 租户的数据库配置查询方法
+func WithLookup(l Lookup) Option {
+	return func(o *options) {
+		o.lookup = l
+	}
+}
+
+// WithTenants 返回全部租户, MigrateAll 使用; 设置后不在其中的租户视为不存在
+func WithTenants(f func() []string) Option {
+	return func(o *options) {
+		o.tenants = f
+	}
+}
+
+// WithGormConfig 创建连接使用的gorm配置
+func WithGormConfig(c *gorm.Config) Option {
+	return func(o *options) {
+		o.gormConfig = c
+	}
+}
+
+// WithMaxTenants 同时保持连接的租户数, 超出后关闭最久未使用的租户连接, 默认 DefaultMaxTenants, 0不限制
+func WithMaxTenants(n int) Option {
+	return func(o *options) {
+		o.maxTenants = n
+	}
+}
+
+// WithHeader 从请求头获取租户, 请求头为空时使用host; 请求头可由客户端任意设置,
+// 只在网关已校验或覆盖该请求头时使用, 否则使用 WithResolver(Claim(...))
+func WithHeader(h string) Option {
+	return func(o *options) {
+		o.header = h
+	}
+}
+
+// WithResolver 识别租户的方式, 依次尝试, 都未识别时为 DefaultKey; 设置后不再使用 WithHeader
+func WithResolver(rs ...Resolver) Option {
+	return func(o *options) {
+		o.resolvers = rs
+	}
+}
+
+// WithFallback 租户不存在时共用 DefaultKey 的连接
+func WithFallback(b bool) Option {
+	return func(o *options) {
+		o.fallback = b
+	}
+}
@@ -0,0 +1,59 @@
+package tenant
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/runtime"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// LogField 日志中租户的字段名
+const LogField = "tenant"
+
+func init() {
+	// 非默认租户时日志自动带上租户
+	logger.RegisterContextFields(func(ctx context.Context) map[string]interface{} {
+		if key := FromContext(ctx); key != DefaultKey {
+			return map[string]interface{}{LogField: key}
+		}
+		return nil
+	})
+}
+
+// Prefix ctx中租户的缓存key前缀 tenant:<长度>:<租户>:, 租户中含 ":" 时也不会与其他租户冲突;
+// 默认租户为 tenant:default:, 与未加前缀的key互不影响
+func Prefix(ctx context.Context) string {
+	key := FromContext(ctx)
+	if key == DefaultKey {
+		return "tenant:default:"
+	}
+	return "tenant:" + strconv.Itoa(len(key)) + ":" + key + ":"
+}
+
+// Cache 返回按ctx中租户加前缀的缓存, 不同租户的同名key互不影响
+func Cache(ctx context.Context, store storage.AdapterCache) storage.AdapterCache {
+	return runtime.NewCache(Prefix(ctx), store, "")
+}
+
+// Queue 返回在消息中写入ctx中租户的队列, 消费者用 FromMessage 恢复
+func Queue(ctx context.Context, q storage.AdapterQueue) storage.AdapterQueue {
+	// runtime.Queue 会覆盖租户标记, 使用底层的队列
+	if rq, ok := q.(*runtime.Queue); ok && rq.Unwrap() != nil {
+		q = rq.Unwrap()
+	}
+	key := FromContext(ctx)
+	if key == DefaultKey {
+		key = ""
+	}
+	return runtime.NewQueue(key, q)
+}
+
+// FromMessage 将消息中的租户写入ctx, 没有时不修改
+func FromMessage(ctx context.Context, m storage.Messager) context.Context {
+	if key, _ := m.GetValues()[storage.PrefixKey].(string); key != "" {
+		return NewContext(ctx, key)
+	}
+	return ctx
+}
@@ -0,0 +1,77 @@
+package tenant
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/jwtauth"
+)
+
+// Resolver 从请求中识别租户, 未识别时返回空
+type Resolver func(c *gin.Context) string
+
+// Header 从请求头识别租户
+func Header(name string) Resolver {
+	return func(c *gin.Context) string {
+		return c.GetHeader(name)
+	}
+}
+
+// Host 以小写的 host(不含端口)作为租户
+func Host() Resolver {
+	return func(c *gin.Context) string {
+		host := c.Request.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		return strings.ToLower(host)
+	}
+}
+
+// Subdomain 以 base 域名下的子域名作为租户, 如 base 为 example.com 时 a.example.com 的租户为 a
+func Subdomain(base string) Resolver {
+	suffix := "." + strings.ToLower(strings.TrimPrefix(base, "."))
+	host := Host()
+	return func(c *gin.Context) string {
+		h := host(c)
+		if !strings.HasSuffix(h, suffix) {
+			return ""
+		}
+		return strings.TrimSuffix(h, suffix)
+	}
+}
+
+// Claim 从 jwt 的 claim 识别租户, 需放在 jwt 鉴权中间件之后
+func Claim(key string) Resolver {
+	return func(c *gin.Context) string {
+		return jwtauth.ClaimsFromContext(c.Request.Context()).String(key)
+	}
+}
+
+// Chain 依次尝试, 返回第一个识别到的租户
+func Chain(rs ...Resolver) Resolver {
+	return func(c *gin.Context) string {
+		for _, r := range rs {
+			if key := r(c); key != "" {
+				return key
+			}
+		}
+		return ""
+	}
+}
+
+// Middleware 只在请求 ctx 中设置租户, 不切换数据库; 未识别时为 DefaultKey
+func Middleware(rs ...Resolver) gin.HandlerFunc {
+	resolve := Chain(rs...)
+	return func(c *gin.Context) {
+		key := resolve(c)
+		if key == "" {
+			key = DefaultKey
+		}
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), key))
+		c.Set("tenant", key)
+		c.Next()
+	}
+}
@@ -0,0 +1,302 @@
+// Package tenant 多租户: 从请求识别租户(请求头、host、子域名、jwt claim)写入ctx,
+// 据此切换数据库(Manager)、隔离缓存key(Cache)、在队列消息中传递(Queue、FromMessage), 日志自动带上租户字段
+package tenant
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-admin-team/go-admin-core/tools/database"
+	"gorm.io/gorm"
+)
+
+// DefaultKey 未匹配到租户时使用的配置, 与 config.DatabasesConfig 的 "*" 一致
+const DefaultKey = "*"
+
+// DefaultMaxTenants 默认同时保持连接的租户数
+const DefaultMaxTenants = 100
+
+var ErrTenantNotFound = errors.New("tenant not found")
+
+// Source 租户的数据库连接配置
+type Source struct {
+	Configure database.Configure
+	Open      func(string) gorm.Dialector
+}
+
+// Lookup 根据租户查询数据库配置, 租户不存在返回 ErrTenantNotFound
+type Lookup func(tenant string) (*Source, error)
+
+// MigrateFunc 租户库迁移
+type MigrateFunc func(tenant string, db *gorm.DB) error
+
+type entry struct {
+	key string
+	db  *gorm.DB
+	// refs 正在使用的请求数, 被淘汰时等其归零后再关闭连接
+	refs    int
+	evicted bool
+}
+
+// Manager 多租户数据库管理, 第一次使用时创建连接
+type Manager struct {
+	opts     options
+	mux      sync.Mutex
+	dbs      map[string]*list.Element
+	lru      *list.List
+	creating map[string]*sync.Mutex
+	migrates []MigrateFunc
+}
+
+// NewManager 多租户数据库管理
+func NewManager(opts ...Option) *Manager {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Manager{
+		opts:     o,
+		dbs:      make(map[string]*list.Element),
+		lru:      list.New(),
+		creating: make(map[string]*sync.Mutex),
+	}
+}
+
+// Get 获取ctx中租户的db
+func (m *Manager) Get(ctx context.Context) (*gorm.DB, error) {
+	db, err := m.GetByKey(FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return db.WithContext(ctx), nil
+}
+
+// GetByKey 获取租户的db, 不存在时创建; 返回的连接不计入使用中, 被淘汰后可能关闭,
+// 请求外长时间使用时改用 Acquire
+func (m *Manager) GetByKey(key string) (*gorm.DB, error) {
+	db, release, err := m.Acquire(key)
+	if err != nil {
+		return nil, err
+	}
+	release()
+	return db, nil
+}
+
+// Acquire 获取租户的db, 使用完后调用 release; 使用中的连接被淘汰时, 等全部 release 后再关闭
+func (m *Manager) Acquire(key string) (*gorm.DB, func(), error) {
+	e, err := m.acquire(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	var once sync.Once
+	return e.db, func() { once.Do(func() { m.release(e) }) }, nil
+}
+
+func (m *Manager) acquire(key string) (*entry, error) {
+	if e, ok := m.cached(key); ok {
+		return e, nil
+	}
+
+	// 同一租户只创建一次连接
+	m.mux.Lock()
+	lock, ok := m.creating[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.creating[key] = lock
+	}
+	m.mux.Unlock()
+	lock.Lock()
+	defer func() {
+		lock.Unlock()
+		m.mux.Lock()
+		if m.creating[key] == lock {
+			delete(m.creating, key)
+		}
+		m.mux.Unlock()
+	}()
+
+	if e, ok := m.cached(key); ok {
+		return e, nil
+	}
+	db, err := m.open(key)
+	if errors.Is(err, ErrTenantNotFound) && m.opts.fallback && key != DefaultKey {
+		// 不存在的租户共用 DefaultKey 的连接, 不为任意的租户名创建连接
+		return m.acquire(DefaultKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m.add(key, db), nil
+}
+
+func (m *Manager) cached(key string) (*entry, bool) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	e, ok := m.dbs[key]
+	if !ok {
+		return nil, false
+	}
+	m.lru.MoveToFront(e)
+	en := e.Value.(*entry)
+	en.refs++
+	return en, true
+}
+
+func (m *Manager) release(en *entry) {
+	m.mux.Lock()
+	en.refs--
+	closing := en.evicted && en.refs == 0
+	m.mux.Unlock()
+	if closing {
+		closeDB(en.db)
+	}
+}
+
+func (m *Manager) open(key string) (*gorm.DB, error) {
+	if m.opts.lookup == nil {
+		return nil, errors.New("tenant lookup not set")
+	}
+	if !m.known(key) {
+		return nil, ErrTenantNotFound
+	}
+	s, err := m.opts.lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	db, err := s.Configure.Init(m.opts.gormConfig, s.Open)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s connect error: %w", key, err)
+	}
+	return db, nil
+}
+
+// known 设置了 WithTenants 时只接受其中的租户
+func (m *Manager) known(key string) bool {
+	if m.opts.tenants == nil || key == DefaultKey {
+		return true
+	}
+	for _, t := range m.opts.tenants() {
+		if t == key {
+			return true
+		}
+	}
+	return false
+}
+
+// add 保存新连接, 返回的 entry 已计入使用中
+func (m *Manager) add(key string, db *gorm.DB) *entry {
+	m.mux.Lock()
+	en := &entry{key: key, db: db, refs: 1}
+	m.dbs[key] = m.lru.PushFront(en)
+	var closing []*entry
+	for m.opts.maxTenants > 0 && m.lru.Len() > m.opts.maxTenants {
+		if old := m.evict(m.lru.Back()); old != nil {
+			closing = append(closing, old)
+		}
+	}
+	m.mux.Unlock()
+	for _, old := range closing {
+		closeDB(old.db)
+	}
+	return en
+}
+
+// evict 移除连接, 没有使用中的请求时返回需要关闭的 entry; 需持有 m.mux
+func (m *Manager) evict(e *list.Element) *entry {
+	m.lru.Remove(e)
+	en := e.Value.(*entry)
+	delete(m.dbs, en.key)
+	en.evicted = true
+	if en.refs > 0 {
+		return nil
+	}
+	return en
+}
+
+// Remove 移除租户的连接, 租户配置变更后调用; 使用中的连接在请求结束后关闭
+func (m *Manager) Remove(key string) {
+	m.mux.Lock()
+	var closing *entry
+	if e, ok := m.dbs[key]; ok {
+		closing = m.evict(e)
+	}
+	m.mux.Unlock()
+	if closing != nil {
+		closeDB(closing.db)
+	}
+}
+
+// Keys 当前已连接的租户
+func (m *Manager) Keys() []string {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	keys := make([]string, 0, len(m.dbs))
+	for e := m.lru.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(*entry).key)
+	}
+	return keys
+}
+
+// RegisterMigrate 注册租户库的迁移方法, 新租户连接时不会自动执行
+func (m *Manager) RegisterMigrate(f MigrateFunc) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.migrates = append(m.migrates, f)
+}
+
+// Migrate 对单个租户执行迁移
+func (m *Manager) Migrate(key string) error {
+	db, err := m.GetByKey(key)
+	if err != nil {
+		return err
+	}
+	m.mux.Lock()
+	migrates := append([]MigrateFunc(nil), m.migrates...)
+	m.mux.Unlock()
+	for _, f := range migrates {
+		if err = f(key, db); err != nil {
+			return fmt.Errorf("tenant %s migrate error: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// MigrateAll 对 WithTenants 返回的全部租户执行迁移, 返回所有失败的租户
+func (m *Manager) MigrateAll() error {
+	if m.opts.tenants == nil {
+		return errors.New("tenant list not set")
+	}
+	var errs []string
+	for _, key := range m.opts.tenants() {
+		if err := m.Migrate(key); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Close 关闭全部连接
+func (m *Manager) Close() {
+	m.mux.Lock()
+	dbs := make([]*gorm.DB, 0, len(m.dbs))
+	for _, e := range m.dbs {
+		dbs = append(dbs, e.Value.(*entry).db)
+	}
+	m.dbs = make(map[string]*list.Element)
+	m.lru.Init()
+	m.mux.Unlock()
+	for _, db := range dbs {
+		closeDB(db)
+	}
+}
+
+func closeDB(db *gorm.DB) {
+	_ = database.Close(db)
+}
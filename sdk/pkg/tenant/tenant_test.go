@@ -0,0 +1,247 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/jwtauth"
+	"github.com/go-admin-team/go-admin-core/sdk/runtime"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+type fakeConfigure struct {
+	opened *int32
+}
+
+func (f fakeConfigure) Init(c *gorm.Config, _ func(string) gorm.Dialector) (*gorm.DB, error) {
+	atomic.AddInt32(f.opened, 1)
+	return gorm.Open(tests.DummyDialector{}, c)
+}
+
+func newTestManager(opened *int32, opts ...Option) *Manager {
+	lookup := func(key string) (*Source, error) {
+		if key != "a.com" && key != "b.com" && key != "c.com" && key != DefaultKey {
+			return nil, ErrTenantNotFound
+		}
+		return &Source{Configure: fakeConfigure{opened: opened}}, nil
+	}
+	return NewManager(append([]Option{WithLookup(lookup)}, opts...)...)
+}
+
+func TestManager(t *testing.T) {
+	var opened int32
+	m := newTestManager(&opened, WithMaxTenants(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := m.Get(NewContext(context.Background(), "a.com")); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if opened != 1 {
+		t.Fatalf("expected 1 connection, got %d", opened)
+	}
+
+	_, _ = m.GetByKey("b.com")
+	_, _ = m.GetByKey("a.com")
+	_, _ = m.GetByKey("c.com")
+	keys := m.Keys()
+	if len(keys) != 2 || keys[0] != "c.com" || keys[1] != "a.com" {
+		t.Fatalf("expected least recently used tenant evicted, got %v", keys)
+	}
+
+	if _, err := m.GetByKey("x.com"); !errors.Is(err, ErrTenantNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if len(m.creating) != 0 {
+		t.Errorf("creating locks not pruned: %d", len(m.creating))
+	}
+
+	// 不存在的租户共用 DefaultKey 的连接
+	opened = 0
+	m = newTestManager(&opened, WithFallback(true))
+	for _, key := range []string{"x.com", "y.com", "z.com"} {
+		if _, err := m.GetByKey(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if keys := m.Keys(); opened != 1 || len(keys) != 1 || keys[0] != DefaultKey {
+		t.Errorf("fallback: opened = %d, keys = %v", opened, keys)
+	}
+
+	// WithTenants 之外的租户视为不存在
+	m = newTestManager(&opened, WithTenants(func() []string { return []string{"a.com"} }))
+	if _, err := m.GetByKey("b.com"); !errors.Is(err, ErrTenantNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestManagerEvictInUse(t *testing.T) {
+	var opened int32
+	m := newTestManager(&opened, WithMaxTenants(1))
+	if m2 := newTestManager(&opened); m2.opts.maxTenants != DefaultMaxTenants {
+		t.Errorf("default maxTenants = %d", m2.opts.maxTenants)
+	}
+	en, err := m.acquire("a.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = m.GetByKey("b.com")
+	if !en.evicted || en.refs != 1 {
+		t.Fatalf("evicted = %v, refs = %d", en.evicted, en.refs)
+	}
+	m.release(en)
+	if en.refs != 0 {
+		t.Errorf("refs = %d", en.refs)
+	}
+}
+
+func TestMigrateAll(t *testing.T) {
+	var opened int32
+	m := newTestManager(&opened, WithTenants(func() []string {
+		return []string{"a.com", "b.com", "x.com"}
+	}))
+	var migrated []string
+	m.RegisterMigrate(func(tenant string, db *gorm.DB) error {
+		migrated = append(migrated, tenant)
+		return nil
+	})
+	if err := m.MigrateAll(); err == nil {
+		t.Fatal("expected error for unknown tenant")
+	}
+	if len(migrated) != 2 {
+		t.Fatalf("expected 2 tenants migrated, got %v", migrated)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var opened int32
+	m := newTestManager(&opened, WithHeader(DefaultHeader))
+	r := gin.New()
+	r.Use(m.Middleware())
+	var tenant string
+	r.GET("/", func(c *gin.Context) {
+		tenant = FromContext(c.Request.Context())
+		if _, ok := c.Get("db"); !ok {
+			t.Error("expected db in context")
+		}
+	})
+
+	req := httptest.NewRequest("GET", "http://A.com:8000/", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	if tenant != "a.com" {
+		t.Fatalf("expected tenant from host, got %s", tenant)
+	}
+
+	req = httptest.NewRequest("GET", "http://a.com/", nil)
+	req.Header.Set(DefaultHeader, "b.com")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	if tenant != "b.com" {
+		t.Fatalf("expected tenant from header, got %s", tenant)
+	}
+
+	// 未设置识别方式时不信任请求头与host
+	r = gin.New()
+	r.Use(newTestManager(&opened).Middleware())
+	r.GET("/", func(c *gin.Context) {
+		tenant = FromContext(c.Request.Context())
+	})
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	if tenant != DefaultKey {
+		t.Errorf("expected default tenant, got %s", tenant)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if id := c.GetHeader("X-Claim"); id != "" {
+			claims := jwtauth.MapClaims{"tenantId": id}
+			c.Request = c.Request.WithContext(jwtauth.WithClaims(c.Request.Context(), claims))
+		}
+	}, Middleware(Claim("tenantId"), Header(DefaultHeader), Subdomain("example.com")))
+	var tenant string
+	r.GET("/", func(c *gin.Context) {
+		tenant = FromContext(c.Request.Context())
+	})
+	for _, c := range []struct {
+		url, header, claim, want string
+	}{
+		{"http://a.example.com/", "", "", "a"},
+		{"http://a.example.com/", "b", "", "b"},
+		{"http://a.example.com/", "b", "c", "c"},
+		{"http://other.com/", "", "", DefaultKey},
+	} {
+		req := httptest.NewRequest("GET", c.url, nil)
+		if c.header != "" {
+			req.Header.Set(DefaultHeader, c.header)
+		}
+		if c.claim != "" {
+			req.Header.Set("X-Claim", c.claim)
+		}
+		r.ServeHTTP(httptest.NewRecorder(), req)
+		if tenant != c.want {
+			t.Errorf("%+v: tenant = %s", c, tenant)
+		}
+	}
+}
+
+func TestPropagate(t *testing.T) {
+	store := cache.NewMemory()
+	a := NewContext(context.Background(), "a")
+	_ = Cache(a, store).Set("menu", "1", 60)
+	_ = Cache(context.Background(), store).Set("menu", "2", 60)
+	_ = store.Set("menu", "3", 60)
+	if v, _ := store.Get("tenant:1:a:menu"); v != "1" {
+		t.Errorf("tenant cache = %s", v)
+	}
+	if v, _ := store.Get("tenant:default:menu"); v != "2" {
+		t.Errorf("default cache = %s", v)
+	}
+	// 租户 a:b 的 key 与租户 a 的 b:menu 不冲突
+	_ = Cache(NewContext(context.Background(), "a:b"), store).Set("menu", "4", 60)
+	if v, _ := Cache(a, store).Get("b:menu"); v != "" {
+		t.Errorf("tenant a:b collides with tenant a: %s", v)
+	}
+
+	q := queue.NewMemory(10)
+	got := make(chan string, 1)
+	q.Register("jobs", func(m storage.Messager) error {
+		got <- FromContext(FromMessage(context.Background(), m))
+		return nil
+	})
+	go q.Run()
+	defer q.Shutdown()
+	m := new(queue.Message)
+	m.SetStream("jobs")
+	m.SetValues(map[string]interface{}{})
+	if err := Queue(a, runtime.NewQueue("", q)).Append(m); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case key := <-got:
+		if key != "a" {
+			t.Errorf("tenant from message = %s", key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not consumed")
+	}
+}
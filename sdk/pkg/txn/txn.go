@@ -0,0 +1,166 @@
+// Package txn 事务管理, 事务保存在ctx中, 通过 DB(ctx, db) 获取的连接自动加入当前事务
+//
+//	err := txn.Do(ctx, db, func(ctx context.Context) error {
+//		if err := txn.DB(ctx, db).Create(&order).Error; err != nil {
+//			return err
+//		}
+//		return txn.Publish(ctx, q, msg) // 提交后才投递
+//	})
+package txn
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// Propagation 已存在事务时的处理方式
+type Propagation int
+
+const (
+	// Required 加入已有事务, 没有时新建, 默认
+	Required Propagation = iota
+	// Nested 已有事务时使用 savepoint, 内层回滚不影响外层
+	Nested
+	// RequiresNew 总是新建独立的事务, 与外层分别提交
+	RequiresNew
+)
+
+type txKey struct{}
+
+// state 一层事务, savepoint 成功后 hooks 并入上一层, 回滚时丢弃
+type state struct {
+	tx    *gorm.DB
+	mux   sync.Mutex
+	hooks []func()
+}
+
+func (s *state) add(f ...func()) {
+	s.mux.Lock()
+	s.hooks = append(s.hooks, f...)
+	s.mux.Unlock()
+}
+
+func (s *state) run() {
+	s.mux.Lock()
+	hooks := s.hooks
+	s.hooks = nil
+	s.mux.Unlock()
+	for _, f := range hooks {
+		f()
+	}
+}
+
+// bind 返回带事务的ctx, 事务本身也使用该ctx, gorm 回调中可通过 Statement.Context 判断是否在事务中
+func (s *state) bind(ctx context.Context, tx *gorm.DB) context.Context {
+	ctx = context.WithValue(ctx, txKey{}, s)
+	s.tx = tx.WithContext(ctx)
+	return ctx
+}
+
+func fromContext(ctx context.Context) *state {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(txKey{}).(*state)
+	return s
+}
+
+// DB 返回ctx中的事务, 没有事务时返回 db.WithContext(ctx)
+func DB(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if s := fromContext(ctx); s != nil {
+		return s.tx
+	}
+	return db.WithContext(ctx)
+}
+
+// InTransaction ctx中是否有事务
+func InTransaction(ctx context.Context) bool {
+	return fromContext(ctx) != nil
+}
+
+type Option func(*options)
+
+type options struct {
+	propagation Propagation
+	txOptions   []*sql.TxOptions
+}
+
+func setDefault() options {
+	return options{propagation: Required}
+}
+
+// WithPropagation 已存在事务时的处理方式, 默认 Required
+func WithPropagation(p Propagation) Option {
+	return func(o *options) {
+		o.propagation = p
+	}
+}
+
+// WithTxOptions 新建事务时的隔离级别等
+func WithTxOptions(opt *sql.TxOptions) Option {
+	return func(o *options) {
+		o.txOptions = []*sql.TxOptions{opt}
+	}
+}
+
+// Do 在事务中执行 f, f返回错误或panic时回滚; f 中应通过 DB(ctx, db) 访问数据库
+func Do(ctx context.Context, db *gorm.DB, f func(ctx context.Context) error, opts ...Option) error {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	parent := fromContext(ctx)
+	if parent != nil {
+		switch o.propagation {
+		case Required:
+			return f(ctx)
+		case Nested:
+			return parent.tx.Transaction(func(tx *gorm.DB) error {
+				s := &state{}
+				if err := f(s.bind(ctx, tx)); err != nil {
+					return err
+				}
+				parent.add(s.hooks...)
+				return nil
+			})
+		}
+	}
+	s := &state{}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return f(s.bind(ctx, tx))
+	}, o.txOptions...)
+	if err != nil {
+		return err
+	}
+	s.run()
+	return nil
+}
+
+// AfterCommit 最外层事务提交后执行 f, 事务回滚时不执行; ctx中没有事务时立即执行
+func AfterCommit(ctx context.Context, f func()) {
+	if s := fromContext(ctx); s != nil {
+		s.add(f)
+		return
+	}
+	f()
+}
+
+// Publish 事务提交后再投递消息, 避免消费方读到未提交的数据; ctx中没有事务时立即投递
+func Publish(ctx context.Context, q storage.AdapterQueue, message storage.Messager) error {
+	if !InTransaction(ctx) {
+		return q.Append(message)
+	}
+	AfterCommit(ctx, func() {
+		if err := q.Append(message); err != nil {
+			logger.Module("sdk.txn").WithContext(ctx).Error("publish after commit failed",
+				"queue", q.String(), "stream", message.GetStream(), "error", err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,158 @@
+package txn
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+type Order struct {
+	Id   int `gorm:"primaryKey"`
+	Name string
+}
+
+type fakeQueue struct {
+	storage.AdapterQueue
+	streams []string
+}
+
+func (*fakeQueue) String() string { return "fake" }
+
+func (q *fakeQueue) Append(m storage.Messager) error {
+	q.streams = append(q.streams, m.GetStream())
+	return nil
+}
+
+func newDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err = db.AutoMigrate(&Order{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func count(db *gorm.DB) int64 {
+	var n int64
+	db.Model(&Order{}).Count(&n)
+	return n
+}
+
+func message(stream string) storage.Messager {
+	m := new(queue.Message)
+	m.SetStream(stream)
+	return m
+}
+
+var errFail = errors.New("fail")
+
+func TestDo(t *testing.T) {
+	db := newDB(t)
+	q := &fakeQueue{}
+	err := Do(context.Background(), db, func(ctx context.Context) error {
+		if !InTransaction(ctx) {
+			t.Error("expect transaction")
+		}
+		if err := DB(ctx, db).Create(&Order{Id: 1}).Error; err != nil {
+			return err
+		}
+		if err := Publish(ctx, q, message("order")); err != nil {
+			return err
+		}
+		if len(q.streams) != 0 {
+			t.Error("published before commit")
+		}
+		return nil
+	})
+	if err != nil || count(db) != 1 || len(q.streams) != 1 {
+		t.Fatalf("err = %v, count = %d, published = %v", err, count(db), q.streams)
+	}
+
+	err = Do(context.Background(), db, func(ctx context.Context) error {
+		DB(ctx, db).Create(&Order{Id: 2})
+		_ = Publish(ctx, q, message("order"))
+		return errFail
+	})
+	if !errors.Is(err, errFail) || count(db) != 1 || len(q.streams) != 1 {
+		t.Fatalf("err = %v, count = %d, published = %v", err, count(db), q.streams)
+	}
+
+	func() {
+		defer func() { recover() }()
+		_ = Do(context.Background(), db, func(ctx context.Context) error {
+			DB(ctx, db).Create(&Order{Id: 3})
+			panic("boom")
+		})
+	}()
+	if count(db) != 1 {
+		t.Fatalf("panic should rollback, count = %d", count(db))
+	}
+}
+
+func TestNested(t *testing.T) {
+	db := newDB(t)
+	var committed []string
+	err := Do(context.Background(), db, func(ctx context.Context) error {
+		DB(ctx, db).Create(&Order{Id: 1})
+		AfterCommit(ctx, func() { committed = append(committed, "outer") })
+		// savepoint 回滚, 外层继续
+		err := Do(ctx, db, func(ctx context.Context) error {
+			DB(ctx, db).Create(&Order{Id: 2})
+			AfterCommit(ctx, func() { committed = append(committed, "rollback") })
+			return errFail
+		}, WithPropagation(Nested))
+		if !errors.Is(err, errFail) {
+			t.Errorf("err = %v", err)
+		}
+		return Do(ctx, db, func(ctx context.Context) error {
+			AfterCommit(ctx, func() { committed = append(committed, "nested") })
+			return DB(ctx, db).Create(&Order{Id: 3}).Error
+		}, WithPropagation(Nested))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int
+	db.Model(&Order{}).Order("id").Pluck("id", &ids)
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 3 {
+		t.Errorf("ids = %v", ids)
+	}
+	if len(committed) != 2 || committed[0] != "outer" || committed[1] != "nested" {
+		t.Errorf("committed = %v", committed)
+	}
+
+	// Required 加入外层事务, 内层失败整体回滚
+	err = Do(context.Background(), db, func(ctx context.Context) error {
+		DB(ctx, db).Create(&Order{Id: 4})
+		return Do(ctx, db, func(ctx context.Context) error {
+			DB(ctx, db).Create(&Order{Id: 5})
+			return errFail
+		})
+	})
+	if !errors.Is(err, errFail) || count(db) != 2 {
+		t.Errorf("err = %v, count = %d", err, count(db))
+	}
+}
+
+func TestAfterCommitWithoutTransaction(t *testing.T) {
+	called := false
+	AfterCommit(context.Background(), func() { called = true })
+	if !called {
+		t.Error("expect immediate call")
+	}
+	q := &fakeQueue{}
+	if err := Publish(context.Background(), q, message("order")); err != nil || len(q.streams) != 1 {
+		t.Errorf("err = %v, published = %v", err, q.streams)
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/security"
 )
 
 const (
@@ -17,10 +19,10 @@ const (
 	LoggerKey  = "_go-admin-logger-request"
 )
 
+// CompareHashAndPassword 校验密码, 支持 bcrypt、argon2id 等 security 包中注册的算法
 func CompareHashAndPassword(e string, p string) (bool, error) {
-	err := bcrypt.CompareHashAndPassword([]byte(e), []byte(p))
-	if err != nil {
-		return false, err
+	if !security.Verify(e, p) {
+		return false, bcrypt.ErrMismatchedHashAndPassword
 	}
 	return true, nil
 }
@@ -57,11 +59,16 @@ func HasError(err error, msg string, code ...int) {
 	}
 }
 
+// NewMsgID 生成请求id, 默认 uuid v4, 可替换为 idgen.UUIDv7 使请求id按时间有序
+var NewMsgID = func() string {
+	return uuid.New().String()
+}
+
 // GenerateMsgIDFromContext 生成msgID
 func GenerateMsgIDFromContext(c *gin.Context) string {
 	requestId := c.GetHeader(TrafficKey)
 	if requestId == "" {
-		requestId = uuid.New().String()
+		requestId = NewMsgID()
 		c.Header(TrafficKey, requestId)
 	}
 	return requestId
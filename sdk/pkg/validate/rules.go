@@ -0,0 +1,34 @@
+package validate
+
+import "regexp"
+
+var mobileRegexp = regexp.MustCompile(`^1[3-9]\d{9}$`)
+
+// IsMobile 中国大陆手机号
+func IsMobile(s string) bool {
+	return mobileRegexp.MatchString(s)
+}
+
+var (
+	idCardWeights = [17]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	idCardChecks  = "10X98765432"
+)
+
+// IsIDCard 18位居民身份证号, 校验末位校验码
+func IsIDCard(s string) bool {
+	if len(s) != 18 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+		sum += int(s[i]-'0') * idCardWeights[i]
+	}
+	last := s[17]
+	if last == 'x' {
+		last = 'X'
+	}
+	return idCardChecks[sum%11] == last
+}
@@ -0,0 +1,200 @@
+package validate
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	zhTranslations "github.com/go-playground/validator/v10/translations/zh"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/i18n"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+	"github.com/go-admin-team/go-admin-core/tools/language"
+)
+
+// FieldError 单个字段的校验错误, 作为失败响应的 data 返回
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Rule 自定义校验规则, Messages 为各语言的提示, {0} 为字段名, {1} 为参数
+type Rule struct {
+	Tag      string
+	Func     validator.Func
+	Messages map[string]string
+}
+
+var (
+	once  sync.Once
+	uni   *ut.UniversalTranslator
+	rules = []Rule{
+		{
+			Tag:  "mobile",
+			Func: func(fl validator.FieldLevel) bool { return IsMobile(fl.Field().String()) },
+			Messages: map[string]string{
+				"zh": "{0}必须是有效的手机号码",
+				"en": "{0} must be a valid mobile number",
+			},
+		},
+		{
+			Tag:  "idcard",
+			Func: func(fl validator.FieldLevel) bool { return IsIDCard(fl.Field().String()) },
+			Messages: map[string]string{
+				"zh": "{0}必须是有效的身份证号码",
+				"en": "{0} must be a valid ID card number",
+			},
+		},
+	}
+)
+
+// RegisterRule 注册自定义校验规则, 需在第一次 Bind 之前调用
+func RegisterRule(r Rule) {
+	rules = append(rules, r)
+}
+
+// setup 在 gin 的校验器上注册字段名、自定义规则和中英文提示
+func setup() {
+	uni = ut.New(en.New(), en.New(), zh.New())
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(fieldName)
+	zhT, _ := uni.GetTranslator("zh")
+	enT, _ := uni.GetTranslator("en")
+	_ = zhTranslations.RegisterDefaultTranslations(v, zhT)
+	_ = enTranslations.RegisterDefaultTranslations(v, enT)
+	for _, r := range rules {
+		_ = v.RegisterValidation(r.Tag, r.Func)
+		for lang, msg := range r.Messages {
+			trans, found := uni.GetTranslator(lang)
+			if !found {
+				continue
+			}
+			tag, msg := r.Tag, msg
+			_ = v.RegisterTranslation(tag, trans, func(t ut.Translator) error {
+				return t.Add(tag, msg, true)
+			}, func(t ut.Translator, fe validator.FieldError) string {
+				s, err := t.T(tag, fe.Field(), fe.Param())
+				if err != nil {
+					return fe.Error()
+				}
+				return s
+			})
+		}
+	}
+}
+
+// fieldName 字段名优先取 json, 其次 form、uri
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "form", "uri"} {
+		name := strings.SplitN(f.Tag.Get(key), ",", 2)[0]
+		if name == "-" {
+			break
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// Bind 绑定并校验请求参数, 未指定 bindings 时按路径参数和 Content-Type 绑定;
+// 多个 bindings 全部绑定后统一校验一次, 失败时返回 *response.CodeError,
+// 可直接交给 response.Fail
+func Bind(c *gin.Context, obj interface{}, bindings ...binding.Binding) error {
+	once.Do(setup)
+	if len(bindings) == 0 {
+		if len(c.Params) > 0 {
+			bindings = append(bindings, nil)
+		}
+		bindings = append(bindings, binding.Default(c.Request.Method, c.ContentType()))
+	}
+	var verr validator.ValidationErrors
+	for _, b := range bindings {
+		var err error
+		if b == nil {
+			err = c.ShouldBindUri(obj)
+		} else {
+			err = c.ShouldBindWith(obj, b)
+		}
+		if err != nil && !errors.Is(err, io.EOF) && !errors.As(err, &verr) {
+			return response.ErrBadRequest.Wrap(err)
+		}
+	}
+	if err := binding.Validator.ValidateStruct(obj); err != nil {
+		return Error(c, err)
+	}
+	return nil
+}
+
+// Error 将校验错误转换为 *response.CodeError, 文案为第一个字段的提示, data 为全部字段错误
+func Error(c *gin.Context, err error) error {
+	var ve validator.ValidationErrors
+	if !errors.As(err, &ve) {
+		return response.ErrBadRequest.Wrap(err)
+	}
+	fields := Translate(c, ve)
+	return response.NewError(response.ErrBadRequest.Code, response.ErrBadRequest.Status, "", fields[0].Message).
+		Wrap(err).WithDetails(fields)
+}
+
+// Translate 按请求语言翻译校验错误, i18n 中存在 validate.<tag> 文案时优先使用,
+// 模板参数为 Field 和 Param
+func Translate(c *gin.Context, ve validator.ValidationErrors) []FieldError {
+	once.Do(setup)
+	lang := i18n.Lang(c)
+	var locales []string
+	if c.GetString(i18n.LangKey) == "" {
+		// 未经过 i18n.Middleware 时 lang 只在已加载的文案中协商, 校验提示先按 Accept-Language 选择
+		for _, l := range language.ParseAcceptLanguage(c.GetHeader("Accept-Language"), nil) {
+			locales = append(locales, translatorLocale(l))
+		}
+	}
+	trans, _ := uni.FindTranslator(append(locales, translatorLocale(lang))...)
+	fields := make([]FieldError, 0, len(ve))
+	for _, fe := range ve {
+		f := FieldError{
+			Field: fieldPath(fe.Namespace()),
+			Tag:   fe.Tag(),
+			Param: fe.Param(),
+		}
+		f.Message = i18n.Default().Translate(lang, "validate."+fe.Tag(), map[string]interface{}{
+			"Field": fe.Field(),
+			"Param": fe.Param(),
+		})
+		if f.Message == "" {
+			f.Message = fe.Translate(trans)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// translatorLocale i18n 语言转为 universal-translator 的 locale, 如 zh-cn -> zh
+func translatorLocale(lang string) string {
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		return strings.ToLower(lang[:i])
+	}
+	return strings.ToLower(lang)
+}
+
+// fieldPath 去掉命名空间中的结构体名, 如 Req.user.name -> user.name
+func fieldPath(ns string) string {
+	if i := strings.IndexByte(ns, '.'); i >= 0 {
+		return ns[i+1:]
+	}
+	return ns
+}
@@ -0,0 +1,89 @@
+package validate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+)
+
+type createUser struct {
+	ID     int    `uri:"id" binding:"required"`
+	Name   string `json:"name" binding:"required"`
+	Phone  string `json:"phone" binding:"omitempty,mobile"`
+	IDCard string `json:"idCard" binding:"omitempty,idcard"`
+}
+
+func TestRules(t *testing.T) {
+	for s, want := range map[string]bool{"13800138000": true, "12800138000": false, "1380013800": false} {
+		if IsMobile(s) != want {
+			t.Errorf("IsMobile(%s) != %v", s, want)
+		}
+	}
+	for s, want := range map[string]bool{"11010519491231002X": true, "11010519491231002x": true, "110105194912310021": false, "1101051949123100": false} {
+		if IsIDCard(s) != want {
+			t.Errorf("IsIDCard(%s) != %v", s, want)
+		}
+	}
+}
+
+func TestBind(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/users/:id", func(c *gin.Context) {
+		var req createUser
+		if err := Bind(c, &req); err != nil {
+			response.Fail(c, err)
+			return
+		}
+		response.OK(c, req, "")
+	})
+
+	tests := []struct {
+		body, lang string
+		status     int
+		fields     []string
+		msg        string
+	}{
+		{`{"name":"admin","phone":"13800138000"}`, "zh", http.StatusOK, nil, ""},
+		{`{"phone":"123","idCard":"110105194912310021"}`, "zh", http.StatusBadRequest, []string{"name", "phone", "idCard"}, "name为必填字段"},
+		{`{"name":"admin","phone":"123"}`, "en", http.StatusBadRequest, []string{"phone"}, "phone must be a valid mobile number"},
+		{`{"name":`, "zh", http.StatusBadRequest, nil, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/users/1", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", tt.lang)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, body = %s", tt.body, w.Code, w.Body.String())
+			continue
+		}
+		var res struct {
+			Msg  string       `json:"msg"`
+			Data []FieldError `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+		if tt.msg != "" && res.Msg != tt.msg {
+			t.Errorf("%s: msg = %s, want %s", tt.body, res.Msg, tt.msg)
+		}
+		if tt.fields == nil {
+			continue
+		}
+		if len(res.Data) != len(tt.fields) {
+			t.Errorf("%s: fields = %+v", tt.body, res.Data)
+			continue
+		}
+		for i, f := range tt.fields {
+			if res.Data[i].Field != f {
+				t.Errorf("%s: field %d = %s, want %s", tt.body, i, res.Data[i].Field, f)
+			}
+		}
+	}
+}
@@ -0,0 +1,467 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/jwtauth/user"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/response"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+// DefaultStream 队列桥接的默认队列名
+const DefaultStream = "websocket"
+
+const writeWait = 10 * time.Second
+
+// Conn 单个 websocket 连接
+type Conn struct {
+	ID          string
+	Tenant      string
+	UserID      string
+	ConnectedAt time.Time
+	hub         *Hub
+	socket      *websocket.Conn
+	send        chan []byte
+	once        sync.Once
+	done        chan struct{}
+}
+
+// Send 发送给当前连接, 发送缓冲区已满时断开连接
+func (c *Conn) Send(data []byte) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+	select {
+	case c.send <- data:
+		return true
+	default:
+		logger.Module("sdk.ws").Warn("websocket send buffer full, closing",
+			"id", c.ID, "tenant", c.Tenant, "user", c.UserID)
+		c.Close()
+		return false
+	}
+}
+
+// Close 关闭连接并从 Hub 中移除
+func (c *Conn) Close() {
+	c.once.Do(func() {
+		close(c.done)
+		c.hub.remove(c)
+	})
+}
+
+func (c *Conn) readPump() {
+	defer func() {
+		c.Close()
+		_ = c.socket.Close()
+	}()
+	c.socket.SetReadLimit(c.hub.opts.readLimit)
+	_ = c.socket.SetReadDeadline(time.Now().Add(c.hub.opts.pongWait))
+	c.socket.SetPongHandler(func(string) error {
+		return c.socket.SetReadDeadline(time.Now().Add(c.hub.opts.pongWait))
+	})
+	for {
+		_, data, err := c.socket.ReadMessage()
+		if err != nil {
+			return
+		}
+		if c.hub.opts.onMessage != nil {
+			c.hub.opts.onMessage(c, data)
+		}
+	}
+}
+
+func (c *Conn) writePump() {
+	ticker := time.NewTicker(c.hub.opts.pingInterval)
+	defer func() {
+		ticker.Stop()
+		_ = c.socket.Close()
+	}()
+	for {
+		select {
+		case data := <-c.send:
+			_ = c.socket.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.socket.WriteMessage(websocket.TextMessage, data); err != nil {
+				c.Close()
+				return
+			}
+		case <-ticker.C:
+			_ = c.socket.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.socket.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.Close()
+				return
+			}
+		case <-c.done:
+			_ = c.socket.SetWriteDeadline(time.Now().Add(writeWait))
+			_ = c.socket.WriteMessage(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return
+		}
+	}
+}
+
+type HubOption func(*hubOptions)
+
+type hubOptions struct {
+	pingInterval time.Duration
+	pongWait     time.Duration
+	readLimit    int64
+	sendBuffer   int
+	upgrader     websocket.Upgrader
+	identify     func(c *gin.Context) (tenant, userID string, err error)
+	onMessage    func(c *Conn, data []byte)
+	onConnect    func(c *Conn)
+	onDisconnect func(c *Conn)
+	queue        storage.AdapterQueue
+	stream       string
+}
+
+func setHubDefault() hubOptions {
+	return hubOptions{
+		pingInterval: 30 * time.Second,
+		pongWait:     60 * time.Second,
+		readLimit:    4096,
+		sendBuffer:   256,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		identify: func(c *gin.Context) (string, string, error) {
+			id := user.GetUserIdStr(c)
+			if id == "" || id == "0" {
+				return "", "", response.ErrUnauthorized
+			}
+			return c.GetString("tenant"), id, nil
+		},
+		stream: DefaultStream,
+	}
+}
+
+// WithHeartbeat 心跳间隔与超时, 超时未收到 pong 时断开连接, timeout 应大于 interval
+func WithHeartbeat(interval, timeout time.Duration) HubOption {
+	return func(o *hubOptions) {
+		o.pingInterval = interval
+		o.pongWait = timeout
+	}
+}
+
+// WithReadLimit 客户端消息的最大字节数, 默认4096
+func WithReadLimit(n int64) HubOption {
+	return func(o *hubOptions) {
+		o.readLimit = n
+	}
+}
+
+// WithSendBuffer 每个连接的发送缓冲, 默认256条
+func WithSendBuffer(n int) HubOption {
+	return func(o *hubOptions) {
+		o.sendBuffer = n
+	}
+}
+
+// WithCheckOrigin 校验跨域来源, 默认允许全部
+func WithCheckOrigin(f func(r *http.Request) bool) HubOption {
+	return func(o *hubOptions) {
+		o.upgrader.CheckOrigin = f
+	}
+}
+
+// WithIdentify 识别连接的租户与用户, 默认取 jwt 中的用户id与租户中间件设置的租户, 需在这些中间件之后
+func WithIdentify(f func(c *gin.Context) (tenant, userID string, err error)) HubOption {
+	return func(o *hubOptions) {
+		o.identify = f
+	}
+}
+
+// WithOnMessage 处理客户端发送的消息
+func WithOnMessage(f func(c *Conn, data []byte)) HubOption {
+	return func(o *hubOptions) {
+		o.onMessage = f
+	}
+}
+
+// WithOnConnect 连接建立后调用, 可用于记录在线用户
+func WithOnConnect(f func(c *Conn)) HubOption {
+	return func(o *hubOptions) {
+		o.onConnect = f
+	}
+}
+
+// WithOnDisconnect 连接断开后调用
+func WithOnDisconnect(f func(c *Conn)) HubOption {
+	return func(o *hubOptions) {
+		o.onDisconnect = f
+	}
+}
+
+// WithQueue 消费队列中的 Envelope 并推送给本节点的连接, stream 为空时使用 DefaultStream;
+// 多节点部署时队列需保证每个节点都能收到消息
+func WithQueue(q storage.AdapterQueue, stream string) HubOption {
+	return func(o *hubOptions) {
+		o.queue = q
+		if stream != "" {
+			o.stream = stream
+		}
+	}
+}
+
+// Hub 按租户、用户管理 websocket 连接
+type Hub struct {
+	opts  hubOptions
+	mux   sync.RWMutex
+	conns map[string]*Conn
+	// users tenant -> userID -> connID
+	users map[string]map[string]map[string]*Conn
+}
+
+func NewHub(opts ...HubOption) *Hub {
+	o := setHubDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Hub{
+		opts:  o,
+		conns: make(map[string]*Conn),
+		users: make(map[string]map[string]map[string]*Conn),
+	}
+}
+
+func (*Hub) String() string {
+	return "websocket"
+}
+
+// Start 注册队列桥接的消费者
+func (h *Hub) Start(context.Context) error {
+	if h.opts.queue != nil {
+		h.opts.queue.Register(h.opts.stream, h.consume)
+	}
+	return nil
+}
+
+// Stop 关闭全部连接
+func (h *Hub) Stop(context.Context) error {
+	h.mux.RLock()
+	list := make([]*Conn, 0, len(h.conns))
+	for _, c := range h.conns {
+		list = append(list, c)
+	}
+	h.mux.RUnlock()
+	for _, c := range list {
+		c.Close()
+	}
+	return nil
+}
+
+// Handler websocket 接入
+func (h *Hub) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantKey, userID, err := h.opts.identify(c)
+		if err != nil {
+			response.Fail(c, err)
+			return
+		}
+		socket, err := h.opts.upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			logger.Module("sdk.ws").WithContext(c.Request.Context()).Warn("websocket upgrade failed", "error", err)
+			return
+		}
+		conn := &Conn{
+			ID:          uuid.New().String(),
+			Tenant:      tenantKey,
+			UserID:      userID,
+			ConnectedAt: time.Now(),
+			hub:         h,
+			socket:      socket,
+			send:        make(chan []byte, h.opts.sendBuffer),
+			done:        make(chan struct{}),
+		}
+		h.add(conn)
+		go conn.writePump()
+		go conn.readPump()
+	}
+}
+
+func (h *Hub) add(c *Conn) {
+	h.mux.Lock()
+	h.conns[c.ID] = c
+	users, ok := h.users[c.Tenant]
+	if !ok {
+		users = make(map[string]map[string]*Conn)
+		h.users[c.Tenant] = users
+	}
+	if users[c.UserID] == nil {
+		users[c.UserID] = make(map[string]*Conn)
+	}
+	users[c.UserID][c.ID] = c
+	h.mux.Unlock()
+	logger.Module("sdk.ws").Info("websocket connected", "id", c.ID, "tenant", c.Tenant, "user", c.UserID)
+	if h.opts.onConnect != nil {
+		h.opts.onConnect(c)
+	}
+}
+
+func (h *Hub) remove(c *Conn) {
+	h.mux.Lock()
+	delete(h.conns, c.ID)
+	if users, ok := h.users[c.Tenant]; ok {
+		if conns, ok := users[c.UserID]; ok {
+			delete(conns, c.ID)
+			if len(conns) == 0 {
+				delete(users, c.UserID)
+			}
+		}
+		if len(users) == 0 {
+			delete(h.users, c.Tenant)
+		}
+	}
+	h.mux.Unlock()
+	logger.Module("sdk.ws").Info("websocket disconnected", "id", c.ID, "tenant", c.Tenant, "user", c.UserID)
+	if h.opts.onDisconnect != nil {
+		h.opts.onDisconnect(c)
+	}
+}
+
+// SendUser 发送给用户的全部连接, 返回发送的连接数
+func (h *Hub) SendUser(tenantKey, userID string, data []byte) int {
+	h.mux.RLock()
+	list := make([]*Conn, 0, len(h.users[tenantKey][userID]))
+	for _, c := range h.users[tenantKey][userID] {
+		list = append(list, c)
+	}
+	h.mux.RUnlock()
+	return send(list, data)
+}
+
+// SendTenant 发送给租户下的全部连接
+func (h *Hub) SendTenant(tenantKey string, data []byte) int {
+	h.mux.RLock()
+	list := make([]*Conn, 0)
+	for _, conns := range h.users[tenantKey] {
+		for _, c := range conns {
+			list = append(list, c)
+		}
+	}
+	h.mux.RUnlock()
+	return send(list, data)
+}
+
+// Broadcast 发送给全部连接
+func (h *Hub) Broadcast(data []byte) int {
+	h.mux.RLock()
+	list := make([]*Conn, 0, len(h.conns))
+	for _, c := range h.conns {
+		list = append(list, c)
+	}
+	h.mux.RUnlock()
+	return send(list, data)
+}
+
+func send(list []*Conn, data []byte) int {
+	n := 0
+	for _, c := range list {
+		if c.Send(data) {
+			n++
+		}
+	}
+	return n
+}
+
+// Kick 断开用户的全部连接, 如用户被禁用或强制下线
+func (h *Hub) Kick(tenantKey, userID string) {
+	h.mux.RLock()
+	list := make([]*Conn, 0, len(h.users[tenantKey][userID]))
+	for _, c := range h.users[tenantKey][userID] {
+		list = append(list, c)
+	}
+	h.mux.RUnlock()
+	for _, c := range list {
+		c.Close()
+	}
+}
+
+// Online 租户下本节点在线的用户id
+func (h *Hub) Online(tenantKey string) []string {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	list := make([]string, 0, len(h.users[tenantKey]))
+	for id := range h.users[tenantKey] {
+		list = append(list, id)
+	}
+	return list
+}
+
+// IsOnline 用户在本节点是否有连接
+func (h *Hub) IsOnline(tenantKey, userID string) bool {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	return len(h.users[tenantKey][userID]) > 0
+}
+
+// Count 本节点的连接数
+func (h *Hub) Count() int {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	return len(h.conns)
+}
+
+// Envelope 经队列转发的消息, 按 Tenant、UserID 确定接收范围, 均为空时广播
+type Envelope struct {
+	Tenant string          `json:"tenant,omitempty"`
+	UserID string          `json:"userId,omitempty"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// Deliver 按 Envelope 的范围推送给本节点的连接
+func (h *Hub) Deliver(e *Envelope) int {
+	switch {
+	case e.UserID != "":
+		return h.SendUser(e.Tenant, e.UserID, e.Data)
+	case e.Tenant != "":
+		return h.SendTenant(e.Tenant, e.Data)
+	default:
+		return h.Broadcast(e.Data)
+	}
+}
+
+func (h *Hub) consume(message storage.Messager) error {
+	raw, _ := message.GetValues()["envelope"].(string)
+	e := &Envelope{}
+	if err := json.Unmarshal([]byte(raw), e); err != nil {
+		logger.Module("sdk.ws").WithContext(queue.TraceContext(message)).
+			Error("invalid websocket envelope", "id", message.GetID(), "error", err)
+		return nil
+	}
+	h.Deliver(e)
+	return nil
+}
+
+// Publish 写入队列, 由 WithQueue 的 Hub 消费后推送, 其他服务或异步任务可用于通知在线用户
+func Publish(ctx context.Context, q storage.AdapterQueue, stream string, e *Envelope) error {
+	if q == nil {
+		return errors.New("ws: queue is nil")
+	}
+	if stream == "" {
+		stream = DefaultStream
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	message := new(queue.Message)
+	message.SetStream(stream)
+	message.SetValues(map[string]interface{}{"envelope": string(b)})
+	queue.InjectTrace(ctx, message)
+	return q.Append(message)
+}
@@ -0,0 +1,144 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+func newTestHub(t *testing.T, opts ...HubOption) (*Hub, string) {
+	gin.SetMode(gin.TestMode)
+	opts = append([]HubOption{WithIdentify(func(c *gin.Context) (string, string, error) {
+		return c.Query("tenant"), c.Query("user"), nil
+	})}, opts...)
+	h := NewHub(opts...)
+	r := gin.New()
+	r.GET("/ws", h.Handler())
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return h, "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+}
+
+func dial(t *testing.T, url string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func waitCount(t *testing.T, h *Hub, n int) {
+	for i := 0; i < 100; i++ {
+		if h.Count() == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("count = %d, want %d", h.Count(), n)
+}
+
+func read(t *testing.T, conn *websocket.Conn) string {
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestHubSend(t *testing.T) {
+	h, url := newTestHub(t)
+	a1 := dial(t, url+"?tenant=t1&user=1")
+	a2 := dial(t, url+"?tenant=t1&user=1")
+	b := dial(t, url+"?tenant=t1&user=2")
+	c := dial(t, url+"?tenant=t2&user=1")
+	waitCount(t, h, 4)
+
+	if n := h.SendUser("t1", "1", []byte("user")); n != 2 {
+		t.Errorf("send user = %d", n)
+	}
+	if read(t, a1) != "user" || read(t, a2) != "user" {
+		t.Error("user message not received")
+	}
+	if n := h.SendTenant("t1", []byte("tenant")); n != 3 {
+		t.Errorf("send tenant = %d", n)
+	}
+	if read(t, b) != "tenant" {
+		t.Error("tenant message not received")
+	}
+	if n := h.Broadcast([]byte("all")); n != 4 {
+		t.Errorf("broadcast = %d", n)
+	}
+	if read(t, c) != "all" {
+		t.Error("broadcast not received")
+	}
+	if online := h.Online("t1"); len(online) != 2 || !h.IsOnline("t2", "1") || h.IsOnline("t2", "2") {
+		t.Errorf("online = %v", online)
+	}
+
+	h.Kick("t1", "1")
+	waitCount(t, h, 2)
+	_ = a1.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		if _, _, err := a1.ReadMessage(); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				t.Errorf("err = %v", err)
+			}
+			break
+		}
+	}
+	b.Close()
+	waitCount(t, h, 1)
+}
+
+func TestHubHeartbeat(t *testing.T) {
+	h, url := newTestHub(t, WithHeartbeat(20*time.Millisecond, 100*time.Millisecond))
+	// 不读取消息的客户端不会响应 ping
+	dial(t, url+"?tenant=t1&user=1")
+	waitCount(t, h, 1)
+	waitCount(t, h, 0)
+
+	// 读取时自动响应 ping, 连接保持
+	conn := dial(t, url+"?tenant=t1&user=2")
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	time.Sleep(300 * time.Millisecond)
+	if h.Count() != 1 {
+		t.Errorf("count = %d", h.Count())
+	}
+}
+
+func TestHubQueue(t *testing.T) {
+	q := queue.NewMemory(10)
+	h, url := newTestHub(t, WithQueue(q, ""))
+	if err := h.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	go q.Run()
+	defer q.Shutdown()
+	defer h.Stop(context.Background())
+
+	conn := dial(t, url+"?tenant=t1&user=1")
+	waitCount(t, h, 1)
+	data, _ := json.Marshal(map[string]string{"type": "notice"})
+	if err := Publish(context.Background(), q, "", &Envelope{Tenant: "t1", UserID: "1", Data: data}); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(t, conn); got != string(data) {
+		t.Errorf("got %s", got)
+	}
+}
@@ -2,8 +2,6 @@ package ws
 
 import (
 	"context"
-	"fmt"
-	"log"
 	"net/http"
 	"sync"
 	"time"
@@ -11,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 
+	"github.com/go-admin-team/go-admin-core/logger"
 	"github.com/go-admin-team/go-admin-core/sdk/pkg"
 )
 
@@ -56,9 +55,9 @@ type BroadCastMessageData struct {
 func (c *Client) Read(cxt context.Context) {
 	defer func(cxt context.Context) {
 		WebsocketManager.UnRegister <- c
-		log.Printf("client [%s] disconnect", c.Id)
+		logger.Module("sdk.ws").Info("websocket client disconnect", "id", c.Id, "group", c.Group)
 		if err := c.Socket.Close(); err != nil {
-			log.Printf("client [%s] disconnect err: %s", c.Id, err)
+			logger.Module("sdk.ws").Warn("websocket client close failed", "id", c.Id, "group", c.Group, "error", err)
 		}
 	}(cxt)
 
@@ -70,7 +69,7 @@ func (c *Client) Read(cxt context.Context) {
 		if err != nil || messageType == websocket.CloseMessage {
 			break
 		}
-		log.Printf("client [%s] receive message: %s", c.Id, string(message))
+		logger.Module("sdk.ws").Debug("websocket receive message", "id", c.Id, "group", c.Group, "size", len(message))
 		c.Message <- message
 	}
 }
@@ -78,9 +77,9 @@ func (c *Client) Read(cxt context.Context) {
 // 写信息，从 channel 变量 Send 中读取数据写入 websocket 连接
 func (c *Client) Write(cxt context.Context) {
 	defer func(cxt context.Context) {
-		log.Printf("client [%s] disconnect", c.Id)
+		logger.Module("sdk.ws").Info("websocket client disconnect", "id", c.Id, "group", c.Group)
 		if err := c.Socket.Close(); err != nil {
-			log.Printf("client [%s] disconnect err: %s", c.Id, err)
+			logger.Module("sdk.ws").Warn("websocket client close failed", "id", c.Id, "group", c.Group, "error", err)
 		}
 	}(cxt)
 
@@ -94,10 +93,10 @@ func (c *Client) Write(cxt context.Context) {
 				_ = c.Socket.WriteMessage(websocket.CloseMessage, []byte{})
 				return
 			}
-			log.Printf("client [%s] write message: %s", c.Id, string(message))
+			logger.Module("sdk.ws").Debug("websocket write message", "id", c.Id, "group", c.Group, "size", len(message))
 			err := c.Socket.WriteMessage(websocket.TextMessage, message)
 			if err != nil {
-				log.Printf("client [%s] writemessage err: %s", c.Id, err)
+				logger.Module("sdk.ws").Warn("websocket write message failed", "id", c.Id, "group", c.Group, "error", err)
 			}
 		case _ = <-c.Context.Done():
 			break
@@ -107,13 +106,12 @@ func (c *Client) Write(cxt context.Context) {
 
 // 启动 websocket 管理器
 func (manager *Manager) Start() {
-	log.Printf("websocket manage start")
+	logger.Module("sdk.ws").Info("websocket manager start")
 	for {
 		select {
 		// 注册
 		case client := <-manager.Register:
-			log.Printf("client [%s] connect", client.Id)
-			log.Printf("register client [%s] to group [%s]", client.Id, client.Group)
+			logger.Module("sdk.ws").Info("websocket client register", "id", client.Id, "group", client.Group)
 
 			manager.Lock.Lock()
 			if manager.Group[client.Group] == nil {
@@ -126,7 +124,7 @@ func (manager *Manager) Start() {
 
 		// 注销
 		case client := <-manager.UnRegister:
-			log.Printf("unregister client [%s] from group [%s]", client.Id, client.Group)
+			logger.Module("sdk.ws").Info("websocket client unregister", "id", client.Id, "group", client.Group)
 			manager.Lock.Lock()
 			if mGroup, ok := manager.Group[client.Group]; ok {
 				if mClient, ok := mGroup[client.Id]; ok {
@@ -286,12 +284,10 @@ func (manager *Manager) WsClient(c *gin.Context) {
 
 	conn, err := upGrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
-		log.Printf("websocket connect error: %s", c.Param("channel"))
+		logger.Module("sdk.ws").WithContext(c.Request.Context()).Error("websocket upgrade failed", "group", c.Param("channel"), "error", err)
 		return
 	}
 
-	fmt.Println("token: ", c.Query("token"))
-
 	client := &Client{
 		Id:         c.Param("id"),
 		Group:      c.Param("channel"),
@@ -323,20 +319,20 @@ func (manager *Manager) UnWsClient(c *gin.Context) {
 
 func SendGroup(msg []byte) {
 	WebsocketManager.SendGroup("leffss", []byte("{\"code\":200,\"data\":"+string(msg)+"}"))
-	fmt.Println(WebsocketManager.Info())
+	logger.Module("sdk.ws").Debug("websocket manager info", "info", WebsocketManager.Info())
 }
 
 func SendAll(msg []byte) {
 	WebsocketManager.SendAll([]byte("{\"code\":200,\"data\":" + string(msg) + "}"))
-	fmt.Println(WebsocketManager.Info())
+	logger.Module("sdk.ws").Debug("websocket manager info", "info", WebsocketManager.Info())
 }
 
 func SendOne(ctx context.Context, id string, group string, msg []byte) {
 	WebsocketManager.Send(ctx, id, group, []byte("{\"code\":200,\"data\":"+string(msg)+"}"))
-	fmt.Println(WebsocketManager.Info())
+	logger.Module("sdk.ws").Debug("websocket manager info", "info", WebsocketManager.Info())
 }
 
 func WsLogout(id string, group string) {
 	WebsocketManager.UnRegisterClient(&Client{Id: id, Group: group})
-	fmt.Println(WebsocketManager.Info())
+	logger.Module("sdk.ws").Debug("websocket manager info", "info", WebsocketManager.Info())
 }
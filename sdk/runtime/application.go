@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"context"
 	"net/http"
 	"sync"
 
@@ -8,9 +9,13 @@ import (
 
 	"github.com/casbin/casbin/v2"
 	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/idgen"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/ws"
+	"github.com/go-admin-team/go-admin-core/server/metrics"
 	"github.com/go-admin-team/go-admin-core/storage"
 	"github.com/go-admin-team/go-admin-core/storage/queue"
 	"github.com/robfig/cron/v3"
+	"google.golang.org/grpc"
 	"gorm.io/gorm"
 )
 
@@ -29,7 +34,16 @@ type Application struct {
 	routers     []Router
 	configs     map[string]interface{} // 系统参数
 	appRouters  []func()               // app路由
-
+	caches      map[string]storage.AdapterCache
+	queues      map[string]storage.AdapterQueue
+	lockers     map[string]storage.AdapterLocker
+	loggers     map[string]logger.Logger
+	engines     map[string]http.Handler
+	metrics     *metrics.Registry
+	websocket   *ws.Hub
+	grpcClients map[string]*grpc.ClientConn
+	idGenerator *idgen.Snowflake
+	lifecycle   lifecycle
 }
 
 type Router struct {
@@ -132,6 +146,12 @@ func NewConfig() *Application {
 		handler:     make(map[string][]func(r *gin.RouterGroup, hand ...*gin.HandlerFunc)),
 		routers:     make([]Router, 0),
 		configs:     make(map[string]interface{}),
+		caches:      make(map[string]storage.AdapterCache),
+		queues:      make(map[string]storage.AdapterQueue),
+		lockers:     make(map[string]storage.AdapterLocker),
+		loggers:     make(map[string]logger.Logger),
+		engines:     make(map[string]http.Handler),
+		grpcClients: make(map[string]*grpc.ClientConn),
 	}
 }
 
@@ -276,3 +296,166 @@ func (e *Application) SetAppRouters(appRouters func()) {
 func (e *Application) GetAppRouters() []func() {
 	return e.appRouters
 }
+
+// SetCacheByKey 设置对应key的缓存, 用于同时使用多个缓存
+func (e *Application) SetCacheByKey(key string, c storage.AdapterCache) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.caches[key] = c
+}
+
+// GetCacheByKey 根据key获取缓存, 不存在时返回nil
+func (e *Application) GetCacheByKey(key string) storage.AdapterCache {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+	if c, ok := e.caches[key]; ok {
+		return NewCache("", c, "")
+	}
+	return nil
+}
+
+// SetQueueByKey 设置对应key的队列
+func (e *Application) SetQueueByKey(key string, q storage.AdapterQueue) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.queues[key] = q
+}
+
+// GetQueueByKey 根据key获取队列, 不存在时返回nil
+func (e *Application) GetQueueByKey(key string) storage.AdapterQueue {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+	if q, ok := e.queues[key]; ok {
+		return NewQueue("", q)
+	}
+	return nil
+}
+
+// SetLockerByKey 设置对应key的分布式锁
+func (e *Application) SetLockerByKey(key string, l storage.AdapterLocker) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.lockers[key] = l
+}
+
+// GetLockerByKey 根据key获取分布式锁, 不存在时返回nil
+func (e *Application) GetLockerByKey(key string) storage.AdapterLocker {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+	if l, ok := e.lockers[key]; ok {
+		return NewLocker("", l)
+	}
+	return nil
+}
+
+// SetLoggerByKey 设置对应key的日志组件, 如审计、访问日志单独输出
+func (e *Application) SetLoggerByKey(key string, l logger.Logger) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.loggers[key] = l
+}
+
+// GetLoggerByKey 根据key获取日志组件, 不存在时返回默认日志组件
+func (e *Application) GetLoggerByKey(key string) logger.Logger {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+	if l, ok := e.loggers[key]; ok {
+		return l
+	}
+	return logger.DefaultLogger
+}
+
+// SetEngineByKey 设置对应key的路由引擎, 如管理端口单独的engine
+func (e *Application) SetEngineByKey(key string, engine http.Handler) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.engines[key] = engine
+}
+
+// GetEngineByKey 根据key获取路由引擎, 不存在时返回nil
+func (e *Application) GetEngineByKey(key string) http.Handler {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+	return e.engines[key]
+}
+
+// SetMetrics 设置指标注册表
+func (e *Application) SetMetrics(m *metrics.Registry) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.metrics = m
+}
+
+// GetMetrics 获取指标注册表, 未设置时返回默认注册表
+func (e *Application) GetMetrics() *metrics.Registry {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+	if e.metrics == nil {
+		return metrics.Default()
+	}
+	return e.metrics
+}
+
+// SetWebsocket 设置 websocket 连接管理
+func (e *Application) SetWebsocket(h *ws.Hub) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.websocket = h
+}
+
+// GetWebsocket 获取 websocket 连接管理, 未设置时创建默认的 Hub
+func (e *Application) GetWebsocket() *ws.Hub {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	if e.websocket == nil {
+		e.websocket = ws.NewHub()
+	}
+	return e.websocket
+}
+
+// SetGrpcClient 设置依赖服务的 gRPC 连接
+func (e *Application) SetGrpcClient(key string, conn *grpc.ClientConn) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.grpcClients[key] = conn
+}
+
+// GetGrpcClient 根据服务名获取 gRPC 连接, 不存在时返回nil
+func (e *Application) GetGrpcClient(key string) *grpc.ClientConn {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+	return e.grpcClients[key]
+}
+
+// SetIDGenerator 设置雪花id生成器, 同时作为 idgen 的默认生成器
+func (e *Application) SetIDGenerator(g *idgen.Snowflake) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.idGenerator = g
+	idgen.SetDefault(g)
+}
+
+// GetIDGenerator 获取雪花id生成器, 未设置时返回 idgen.Default()
+func (e *Application) GetIDGenerator() *idgen.Snowflake {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+	if e.idGenerator == nil {
+		return idgen.Default()
+	}
+	return e.idGenerator
+}
+
+// AddComponent 注册由 Runtime 管理生命周期的组件, order 小的先启动、后停止
+func (e *Application) AddComponent(order int, c Component) {
+	e.lifecycle.add(order, c)
+}
+
+// Start 按顺序启动未启动的组件, 失败时停止已启动的组件
+func (e *Application) Start(ctx context.Context) error {
+	return e.lifecycle.start(ctx)
+}
+
+// Shutdown 逆序停止已启动的组件, ctx超时后剩余组件不再等待
+func (e *Application) Shutdown(ctx context.Context) error {
+	return e.lifecycle.stop(ctx)
+}
@@ -2,11 +2,13 @@ package runtime
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/chanxuehong/wechat/oauth2"
 
 	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
 )
 
 const (
@@ -39,6 +41,11 @@ func (e *Cache) String() string {
 	return e.store.String()
 }
 
+// Unwrap 底层的缓存适配器, key 不带前缀
+func (e *Cache) Unwrap() storage.AdapterCache {
+	return e.store
+}
+
 // SetPrefix 设置前缀
 func (e *Cache) SetPrefix(prefix string) {
 	e.prefix = prefix
@@ -70,6 +77,55 @@ func (e Cache) HashGet(hk, key string) (string, error) {
 	return e.store.HashGet(hk, e.prefix+intervalTenant+key)
 }
 
+// HashSet 写入hash中本上下文的字段; 底层缓存不支持时返回 ErrHashGetAllUnsupported
+func (e Cache) HashSet(hk, key string, val interface{}) error {
+	store, ok := e.store.(storage.AdapterHashCache)
+	if !ok {
+		return cache.ErrHashGetAllUnsupported
+	}
+	return store.HashSet(hk, e.prefix+intervalTenant+key, val)
+}
+
+// HashGetAll 读取hash中本上下文的全部字段, 字段名去掉前缀; 底层缓存不支持时返回 ErrHashGetAllUnsupported
+func (e Cache) HashGetAll(hk string) (map[string]string, error) {
+	store, ok := e.store.(storage.AdapterHashCache)
+	if !ok {
+		return nil, cache.ErrHashGetAllUnsupported
+	}
+	all, err := store.HashGetAll(hk)
+	if err != nil {
+		return nil, err
+	}
+	prefix := e.prefix + intervalTenant
+	values := make(map[string]string, len(all))
+	for k, v := range all {
+		if strings.HasPrefix(k, prefix) {
+			values[k[len(prefix):]] = v
+		}
+	}
+	return values, nil
+}
+
+// GetTouch 读取并把有效期重置为 ttl
+func (e Cache) GetTouch(key string, ttl time.Duration) (string, error) {
+	return cache.GetTouch(e.store, e.prefix+intervalTenant+key, ttl)
+}
+
+// SetNX key 不存在时写入, 返回是否写入
+func (e Cache) SetNX(key string, val interface{}, expire int) (bool, error) {
+	return cache.SetNX(e.store, e.prefix+intervalTenant+key, val, expire)
+}
+
+// CompareAndSwap 当前值为 old 时写入 val; 底层缓存不支持时返回 cache.ErrCASUnsupported
+func (e Cache) CompareAndSwap(key, old string, val interface{}, expire int) (bool, error) {
+	return cache.CompareAndSwap(e.store, e.prefix+intervalTenant+key, old, val, expire)
+}
+
+// Tx 事务中的key同样带上前缀, 底层缓存不支持时返回 cache.ErrTxUnsupported
+func (e Cache) Tx(f func(p storage.Pipeliner) error) error {
+	return cache.Tx(e.store, e.prefix+intervalTenant, f)
+}
+
 // HashDel delete one key:value pair in hashtable cache
 func (e Cache) HashDel(hk, key string) error {
 	return e.store.HashDel(hk, e.prefix+intervalTenant+key)
@@ -0,0 +1,164 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/tools/database"
+)
+
+// 组件的默认启动顺序, 数值小的先启动、后停止
+const (
+	OrderStorage   = 100
+	OrderQueue     = 200
+	OrderCron      = 300
+	OrderPlugin    = 500
+	OrderWebsocket = 900
+	OrderServer    = 1000
+)
+
+// Component 由 Runtime 管理生命周期的组件
+type Component interface {
+	String() string
+	// Start 不应阻塞, 需要常驻的逻辑放到goroutine中
+	Start(ctx context.Context) error
+	// Stop 在ctx超时前释放资源
+	Stop(ctx context.Context) error
+}
+
+// ComponentFunc 由函数组成的组件, 方法为nil时跳过
+type ComponentFunc struct {
+	Name      string
+	StartFunc func(ctx context.Context) error
+	StopFunc  func(ctx context.Context) error
+}
+
+func (c *ComponentFunc) String() string {
+	return c.Name
+}
+
+func (c *ComponentFunc) Start(ctx context.Context) error {
+	if c.StartFunc == nil {
+		return nil
+	}
+	return c.StartFunc(ctx)
+}
+
+func (c *ComponentFunc) Stop(ctx context.Context) error {
+	if c.StopFunc == nil {
+		return nil
+	}
+	return c.StopFunc(ctx)
+}
+
+// QueueComponent 启动时运行消费者, 停止时关闭队列
+func QueueComponent(name string, q storage.AdapterQueue) Component {
+	return &ComponentFunc{
+		Name: name,
+		StartFunc: func(context.Context) error {
+			go q.Run()
+			return nil
+		},
+		StopFunc: func(context.Context) error {
+			q.Shutdown()
+			return nil
+		},
+	}
+}
+
+// DbComponent 停止时关闭数据库连接池
+func DbComponent(name string, db *gorm.DB) Component {
+	return &ComponentFunc{
+		Name: name,
+		StopFunc: func(context.Context) error {
+			return database.Close(db)
+		},
+	}
+}
+
+type component struct {
+	order int
+	c     Component
+}
+
+// lifecycle 按顺序启动、逆序停止组件
+type lifecycle struct {
+	mux        sync.Mutex
+	components []component
+	started    []component
+}
+
+func (l *lifecycle) add(order int, c Component) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.components = append(l.components, component{order: order, c: c})
+	// 同一顺序按注册先后
+	sort.SliceStable(l.components, func(i, j int) bool {
+		return l.components[i].order < l.components[j].order
+	})
+}
+
+func (l *lifecycle) start(ctx context.Context) error {
+	l.mux.Lock()
+	list := make([]component, 0, len(l.components))
+	for _, c := range l.components {
+		if !l.isStarted(c.c) {
+			list = append(list, c)
+		}
+	}
+	l.mux.Unlock()
+	for _, c := range list {
+		if err := c.c.Start(ctx); err != nil {
+			// 启动失败时停止已启动的组件
+			_ = l.stop(ctx)
+			return fmt.Errorf("start %s failed, %w", c.c.String(), err)
+		}
+		l.mux.Lock()
+		l.started = append(l.started, c)
+		l.mux.Unlock()
+		logger.Module("sdk.runtime").Info("component started", "component", c.c.String(), "order", c.order)
+	}
+	return nil
+}
+
+func (l *lifecycle) isStarted(c Component) bool {
+	for _, s := range l.started {
+		if s.c == c {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *lifecycle) stop(ctx context.Context) error {
+	l.mux.Lock()
+	list := l.started
+	l.started = nil
+	l.mux.Unlock()
+	var errs []string
+	for i := len(list) - 1; i >= 0; i-- {
+		c := list[i]
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Sprintf("stop %s skipped, %s", c.c.String(), err.Error()))
+			continue
+		}
+		if err := c.c.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Sprintf("stop %s failed, %s", c.c.String(), err.Error()))
+			logger.Module("sdk.runtime").Error("component stop failed", "component", c.c.String(), "error", err)
+			continue
+		}
+		logger.Module("sdk.runtime").Info("component stopped", "component", c.c.String())
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestApplication_Lifecycle(t *testing.T) {
+	var events []string
+	c := func(name string, fail bool) Component {
+		return &ComponentFunc{
+			Name: name,
+			StartFunc: func(context.Context) error {
+				if fail {
+					return errors.New("boom")
+				}
+				events = append(events, "start "+name)
+				return nil
+			},
+			StopFunc: func(context.Context) error {
+				events = append(events, "stop "+name)
+				return nil
+			},
+		}
+	}
+
+	e := NewConfig()
+	e.AddComponent(OrderServer, c("server", false))
+	e.AddComponent(OrderStorage, c("db", false))
+	e.AddComponent(OrderQueue, c("queue", false))
+	if err := e.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"start db", "start queue", "start server", "stop server", "stop queue", "stop db"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+
+	events = nil
+	e = NewConfig()
+	e.AddComponent(OrderStorage, c("db", false))
+	e.AddComponent(OrderQueue, c("queue", true))
+	if err := e.Start(context.Background()); err == nil {
+		t.Fatal("expected start error")
+	}
+	if want = []string{"start db", "stop db"}; !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
@@ -19,6 +19,11 @@ func (e *Queue) String() string {
 	return e.queue.String()
 }
 
+// Unwrap 底层的队列适配器, 未设置时为nil
+func (e *Queue) Unwrap() storage.AdapterQueue {
+	return e.queue
+}
+
 // Register 注册消费者
 func (e *Queue) Register(name string, f storage.ConsumerFunc) {
 	e.queue.Register(name, f)
@@ -1,14 +1,19 @@
 package runtime
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/idgen"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/ws"
+	"github.com/go-admin-team/go-admin-core/server/metrics"
 	"github.com/go-admin-team/go-admin-core/storage"
 	"github.com/robfig/cron/v3"
+	"google.golang.org/grpc"
 	"gorm.io/gorm"
 )
 
@@ -68,4 +73,37 @@ type Runtime interface {
 	// SetAppRouters set AppRouter
 	SetAppRouters(appRouters func())
 	GetAppRouters() []func()
+
+	// SetCacheByKey 多个命名的缓存、队列、分布式锁、日志、路由引擎
+	SetCacheByKey(key string, c storage.AdapterCache)
+	GetCacheByKey(key string) storage.AdapterCache
+	SetQueueByKey(key string, q storage.AdapterQueue)
+	GetQueueByKey(key string) storage.AdapterQueue
+	SetLockerByKey(key string, l storage.AdapterLocker)
+	GetLockerByKey(key string) storage.AdapterLocker
+	SetLoggerByKey(key string, l logger.Logger)
+	GetLoggerByKey(key string) logger.Logger
+	SetEngineByKey(key string, engine http.Handler)
+	GetEngineByKey(key string) http.Handler
+
+	// SetMetrics 应用自定义指标的注册入口, 未设置时使用 metrics.Default()
+	SetMetrics(m *metrics.Registry)
+	GetMetrics() *metrics.Registry
+
+	// SetWebsocket websocket 连接管理, 未设置时使用默认配置创建
+	SetWebsocket(h *ws.Hub)
+	GetWebsocket() *ws.Hub
+
+	// SetGrpcClient 依赖服务的 gRPC 连接, key 为服务名
+	SetGrpcClient(key string, conn *grpc.ClientConn)
+	GetGrpcClient(key string) *grpc.ClientConn
+
+	// SetIDGenerator 主键等使用的雪花id生成器, 使用 locker 分配机器id时需要同时 AddComponent
+	SetIDGenerator(g *idgen.Snowflake)
+	GetIDGenerator() *idgen.Snowflake
+
+	// AddComponent 生命周期管理, 按 order 顺序启动, 逆序停止
+	AddComponent(order int, c Component)
+	Start(ctx context.Context) error
+	Shutdown(ctx context.Context) error
 }
@@ -1,9 +1,11 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/sdk/pkg/txn"
 	"github.com/go-admin-team/go-admin-core/storage"
 	"gorm.io/gorm"
 )
@@ -25,3 +27,8 @@ func (db *Service) AddError(err error) error {
 	}
 	return db.Error
 }
+
+// DB 返回ctx中的事务(见 txn.Do), 没有事务时返回 Orm
+func (db *Service) DB(ctx context.Context) *gorm.DB {
+	return txn.DB(ctx, db.Orm)
+}
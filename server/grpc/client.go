@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/go-admin-team/go-admin-core/server/grpc/interceptors/auth"
+	"github.com/go-admin-team/go-admin-core/server/grpc/interceptors/tracing"
+	"github.com/go-admin-team/go-admin-core/server/metrics"
+)
+
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	tls         *tls.Config
+	timeout     time.Duration
+	block       bool
+	unary       []grpc.UnaryClientInterceptor
+	stream      []grpc.StreamClientInterceptor
+	dialOptions []grpc.DialOption
+}
+
+// WithClientTLS 使用 tls 连接, 默认不加密
+func WithClientTLS(c *tls.Config) ClientOption {
+	return func(o *clientOptions) {
+		o.tls = c
+	}
+}
+
+// WithClientTimeout 建立连接的超时时间, 设置后 Dial 会等待连接建立
+func WithClientTimeout(t time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = t
+		o.block = t > 0
+	}
+}
+
+// WithClientToken 请求时附加 token, f 为 nil 时转发当前请求中的 token
+func WithClientToken(f auth.TokenFunc) ClientOption {
+	return func(o *clientOptions) {
+		o.unary = append(o.unary, auth.UnaryClientInterceptor(f))
+		o.stream = append(o.stream, auth.StreamClientInterceptor(f))
+	}
+}
+
+// WithClientMetrics 调用耗时记录到 r
+func WithClientMetrics(r *metrics.Registry) ClientOption {
+	return func(o *clientOptions) {
+		o.unary = append(o.unary, r.UnaryClientInterceptor())
+		o.stream = append(o.stream, r.StreamClientInterceptor())
+	}
+}
+
+// WithClientInterceptors 追加自定义拦截器
+func WithClientInterceptors(unary []grpc.UnaryClientInterceptor, stream []grpc.StreamClientInterceptor) ClientOption {
+	return func(o *clientOptions) {
+		o.unary = append(o.unary, unary...)
+		o.stream = append(o.stream, stream...)
+	}
+}
+
+// WithClientDialOptions 追加原始的 grpc.DialOption, 如负载均衡配置
+func WithClientDialOptions(opts ...grpc.DialOption) ClientOption {
+	return func(o *clientOptions) {
+		o.dialOptions = append(o.dialOptions, opts...)
+	}
+}
+
+// Dial 连接 target, 默认附加链路、日志与请求标签拦截器
+func Dial(ctx context.Context, target string, opts ...ClientOption) (*grpc.ClientConn, error) {
+	o := &clientOptions{
+		unary:  append([]grpc.UnaryClientInterceptor{tracing.UnaryClientInterceptor()}, defaultUnaryClientInterceptors()...),
+		stream: append([]grpc.StreamClientInterceptor{tracing.StreamClientInterceptor()}, defaultStreamClientInterceptors()...),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	creds := insecure.NewCredentials()
+	if o.tls != nil {
+		creds = credentials.NewTLS(o.tls)
+	}
+	dialOptions := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUnaryInterceptor(middleware.ChainUnaryClient(o.unary...)),
+		grpc.WithStreamInterceptor(middleware.ChainStreamClient(o.stream...)),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(defaultMaxMsgSize)),
+	}, o.dialOptions...)
+	if o.block {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+		dialOptions = append(dialOptions, grpc.WithBlock())
+	}
+	conn, err := grpc.DialContext(ctx, target, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("connect gRPC service %s failed: %w", target, err)
+	}
+	return conn, nil
+}
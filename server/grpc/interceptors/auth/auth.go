@@ -0,0 +1,155 @@
+// Package auth 从 metadata 读取 bearer token 并校验, 客户端自动转发 token
+package auth
+
+import (
+	"context"
+	"strings"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// HeaderKey token 所在的 metadata key
+const HeaderKey = "authorization"
+
+// AuthFunc 校验 token, 返回附加了身份信息的 ctx, 失败时返回的错误不是 status 时按 Unauthenticated 处理
+type AuthFunc func(ctx context.Context, token string) (context.Context, error)
+
+type Option func(*options)
+
+type options struct {
+	skip map[string]bool
+}
+
+// WithSkip 不需要鉴权的方法全名, 如 /grpc.health.v1.Health/Check
+func WithSkip(methods ...string) Option {
+	return func(o *options) {
+		for _, m := range methods {
+			o.skip[m] = true
+		}
+	}
+}
+
+func evaluate(opts []Option) *options {
+	o := &options{skip: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// TokenFromContext 读取请求 metadata 中的 token, 去掉 Bearer 前缀
+func TokenFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(HeaderKey)
+	if len(values) == 0 {
+		return ""
+	}
+	token := strings.TrimSpace(values[0])
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+	return token
+}
+
+func authorize(ctx context.Context, f AuthFunc, o *options, method string) (context.Context, error) {
+	if o.skip[method] {
+		return ctx, nil
+	}
+	token := TokenFromContext(ctx)
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
+	}
+	newCtx, err := f(ctx, token)
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return newCtx, nil
+}
+
+// UnaryServerInterceptor 校验 unary 请求的 token
+func UnaryServerInterceptor(f AuthFunc, opts ...Option) grpc.UnaryServerInterceptor {
+	o := evaluate(opts)
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		newCtx, err := authorize(ctx, f, o, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(newCtx, req)
+	}
+}
+
+// StreamServerInterceptor 校验 stream 请求的 token
+func StreamServerInterceptor(f AuthFunc, opts ...Option) grpc.StreamServerInterceptor {
+	o := evaluate(opts)
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		newCtx, err := authorize(stream.Context(), f, o, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = newCtx
+		return handler(srv, wrapped)
+	}
+}
+
+// TokenFunc 客户端获取要发送的 token
+type TokenFunc func(ctx context.Context) string
+
+// ForwardToken 转发当前请求中的 token, 用于服务间以调用者身份继续调用
+func ForwardToken(ctx context.Context) string {
+	return TokenFromContext(ctx)
+}
+
+func appendToken(ctx context.Context, f TokenFunc) context.Context {
+	if f == nil {
+		f = ForwardToken
+	}
+	token := f(ctx)
+	if token == "" {
+		return ctx
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(HeaderKey)) > 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, HeaderKey, "Bearer "+token)
+}
+
+// UnaryClientInterceptor 请求时附加 token, f 为 nil 时使用 ForwardToken
+func UnaryClientInterceptor(f TokenFunc) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption) error {
+		return invoker(appendToken(ctx, f), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor 建立 stream 时附加 token, f 为 nil 时使用 ForwardToken
+func StreamClientInterceptor(f TokenFunc) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(appendToken(ctx, f), desc, cc, method, opts...)
+	}
+}
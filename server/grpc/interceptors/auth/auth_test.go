@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type userKey struct{}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	f := func(ctx context.Context, token string) (context.Context, error) {
+		if token != "good" {
+			return nil, errors.New("invalid token")
+		}
+		return context.WithValue(ctx, userKey{}, "1"), nil
+	}
+	interceptor := UnaryServerInterceptor(f, WithSkip("/svc/Health"))
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		return ctx.Value(userKey{}), nil
+	}
+	call := func(method, auth string) (interface{}, error) {
+		ctx := context.Background()
+		if auth != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(HeaderKey, auth))
+		}
+		return interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	}
+
+	if user, err := call("/svc/Get", "Bearer good"); err != nil || user != "1" {
+		t.Errorf("user = %v, err = %v", user, err)
+	}
+	for _, auth := range []string{"", "Bearer bad"} {
+		if _, err := call("/svc/Get", auth); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("auth %q: err = %v", auth, err)
+		}
+	}
+	if _, err := call("/svc/Health", ""); err != nil {
+		t.Errorf("skipped method: err = %v", err)
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	var got []string
+	invoker := func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		got = md.Get(HeaderKey)
+		return nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(HeaderKey, "Bearer abc"))
+	if err := UnaryClientInterceptor(nil)(ctx, "/svc/Get", nil, nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != "Bearer abc" {
+		t.Errorf("forwarded = %v", got)
+	}
+}
@@ -0,0 +1,113 @@
+// Package tracing 通过 metadata 在服务间传递请求id、链路id与操作人id, 使日志可以跨服务关联
+package tracing
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+// metadata key, 与 http 头保持一致
+const (
+	RequestIDKey  = "x-request-id"
+	TraceIDKey    = "x-trace-id"
+	OperatorIDKey = "x-operator-id"
+)
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Extract 从请求 metadata 读取链路字段写入 ctx, 没有请求id时生成新的, 没有链路id时沿用请求id
+func Extract(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestID := first(md, RequestIDKey)
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	traceID := first(md, TraceIDKey)
+	if traceID == "" {
+		traceID = requestID
+	}
+	ctx = logger.WithTraceID(logger.WithRequestID(ctx, requestID), traceID)
+	if id := first(md, OperatorIDKey); id != "" {
+		ctx = logger.WithOperatorID(ctx, id)
+	}
+	return ctx
+}
+
+// Inject 将 ctx 中的链路字段写入发出请求的 metadata
+func Inject(ctx context.Context) context.Context {
+	kv := make([]string, 0, 6)
+	if id := logger.RequestID(ctx); id != "" {
+		kv = append(kv, RequestIDKey, id)
+	}
+	if id := logger.TraceID(ctx); id != "" {
+		kv = append(kv, TraceIDKey, id)
+	}
+	if id := logger.OperatorID(ctx); id != "" {
+		kv = append(kv, OperatorIDKey, id)
+	}
+	if len(kv) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// UnaryServerInterceptor 读取链路字段, 并在响应头中返回请求id
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ctx = Extract(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, logger.RequestID(ctx)))
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor 读取链路字段, 并在响应头中返回请求id
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		ctx := Extract(stream.Context())
+		_ = stream.SetHeader(metadata.Pairs(RequestIDKey, logger.RequestID(ctx)))
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = ctx
+		return handler(srv, wrapped)
+	}
+}
+
+// UnaryClientInterceptor 发出请求时附加链路字段
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption) error {
+		return invoker(Inject(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor 建立 stream 时附加链路字段
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(Inject(ctx), desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,25 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+func TestPropagate(t *testing.T) {
+	ctx := logger.WithOperatorID(logger.WithRequestID(context.Background(), "req-1"), "7")
+	out, _ := metadata.FromOutgoingContext(Inject(ctx))
+
+	got := Extract(metadata.NewIncomingContext(context.Background(), out))
+	if logger.RequestID(got) != "req-1" || logger.TraceID(got) != "req-1" || logger.OperatorID(got) != "7" {
+		t.Errorf("request = %s, trace = %s, operator = %s",
+			logger.RequestID(got), logger.TraceID(got), logger.OperatorID(got))
+	}
+
+	if got = Extract(context.Background()); logger.RequestID(got) == "" {
+		t.Error("request id should be generated")
+	}
+}
@@ -11,12 +11,16 @@ import (
 	"context"
 	"crypto/tls"
 	"math"
+	"runtime/debug"
 	"time"
 
 	pbErr "github.com/go-admin-team/go-admin-core/errors"
 	log "github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/server/grpc/interceptors/auth"
 	"github.com/go-admin-team/go-admin-core/server/grpc/interceptors/logging"
 	requesttag "github.com/go-admin-team/go-admin-core/server/grpc/interceptors/request_tag"
+	"github.com/go-admin-team/go-admin-core/server/grpc/interceptors/tracing"
+	"github.com/go-admin-team/go-admin-core/server/metrics"
 	recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
 	opentracing "github.com/grpc-ecosystem/go-grpc-middleware/tracing/opentracing"
@@ -90,7 +94,7 @@ func WithKeepAliveOption(t time.Duration) Option {
 
 func WithTimeoutOption(t time.Duration) Option {
 	return func(o *Options) {
-		o.keepAlive = t
+		o.timeout = t
 	}
 }
 
@@ -136,6 +140,24 @@ func WithStreamServerInterceptorsOption(u ...grpc.StreamServerInterceptor) Optio
 	}
 }
 
+// WithAuthOption 使用 f 校验请求的 token, skip 为不需要鉴权的方法全名
+func WithAuthOption(f auth.AuthFunc, skip ...string) Option {
+	return func(o *Options) {
+		o.unaryServerInterceptors = append(o.unaryServerInterceptors,
+			auth.UnaryServerInterceptor(f, auth.WithSkip(skip...)))
+		o.streamServerInterceptors = append(o.streamServerInterceptors,
+			auth.StreamServerInterceptor(f, auth.WithSkip(skip...)))
+	}
+}
+
+// WithMetricsOption 调用耗时记录到 r, 与 http 指标共用同一注册表
+func WithMetricsOption(r *metrics.Registry) Option {
+	return func(o *Options) {
+		o.unaryServerInterceptors = append(o.unaryServerInterceptors, r.UnaryServerInterceptor())
+		o.streamServerInterceptors = append(o.streamServerInterceptors, r.StreamServerInterceptor())
+	}
+}
+
 func defaultOptions() *Options {
 	return &Options{
 		addr:                  ":0",
@@ -146,6 +168,7 @@ func defaultOptions() *Options {
 		maxConcurrentStreams:  defaultMaxConcurrentStreams,
 		maxMsgSize:            defaultMaxMsgSize,
 		unaryServerInterceptors: []grpc.UnaryServerInterceptor{
+			tracing.UnaryServerInterceptor(),
 			requesttag.UnaryServerInterceptor(),
 			ctxtags.UnaryServerInterceptor(),
 			opentracing.UnaryServerInterceptor(),
@@ -154,6 +177,7 @@ func defaultOptions() *Options {
 			recovery.UnaryServerInterceptor(recovery.WithRecoveryHandler(customRecovery("", ""))),
 		},
 		streamServerInterceptors: []grpc.StreamServerInterceptor{
+			tracing.StreamServerInterceptor(),
 			requesttag.StreamServerInterceptor(),
 			ctxtags.StreamServerInterceptor(),
 			opentracing.StreamServerInterceptor(),
@@ -166,7 +190,7 @@ func defaultOptions() *Options {
 
 func customRecovery(id, domain string) recovery.RecoveryHandlerFunc {
 	return func(p interface{}) (err error) {
-		log.Errorf("panic triggered: %v", p)
+		log.Module("server.grpc").Error("panic triggered", "panic", p, "stack", string(debug.Stack()))
 		return pbErr.New(id, domain, pbErr.InternalServerError)
 	}
 }
@@ -18,6 +18,7 @@ import (
 	middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
 )
 
@@ -26,6 +27,7 @@ type Server struct {
 	srv     *grpc.Server
 	mux     sync.Mutex
 	started bool
+	addr    net.Addr
 	options Options
 }
 
@@ -64,6 +66,14 @@ func (e *Server) Register(do func(server *Server)) {
 }
 
 func (e *Server) initGrpcServerOptions() []grpc.ServerOption {
+	opts := e.serverOptions()
+	if e.options.tls != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(e.options.tls)))
+	}
+	return opts
+}
+
+func (e *Server) serverOptions() []grpc.ServerOption {
 	return []grpc.ServerOption{
 		grpc.UnaryInterceptor(middleware.ChainUnaryServer(e.options.unaryServerInterceptors...)),
 		grpc.StreamInterceptor(middleware.ChainStreamServer(e.options.streamServerInterceptors...)),
@@ -82,6 +92,19 @@ func (e *Server) initGrpcServerOptions() []grpc.ServerOption {
 }
 
 func (e *Server) Start(ctx context.Context) error {
+	// Set the internal context
+	if e.options.ctx != nil {
+		ctx = e.options.ctx
+	}
+	if err := e.Run(); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return e.Shutdown(ctx)
+}
+
+// Run 开始监听并在后台处理请求, 不阻塞, 配合 Stop 使用
+func (e *Server) Run() error {
 	e.mux.Lock()
 	defer e.mux.Unlock()
 
@@ -89,10 +112,6 @@ func (e *Server) Start(ctx context.Context) error {
 		return errors.New("gRPC Server was started more than once. " +
 			"This is likely to be caused by being added to a manager multiple times")
 	}
-	// Set the internal context
-	if e.options.ctx != nil {
-		ctx = e.options.ctx
-	}
 
 	ts, err := net.Listen("tcp", e.options.addr)
 	if err != nil {
@@ -101,13 +120,39 @@ func (e *Server) Start(ctx context.Context) error {
 	log.Infof("gRPC Server listening on %s", ts.Addr().String())
 
 	go func() {
-		if err = e.srv.Serve(ts); err != nil {
+		if err := e.srv.Serve(ts); err != nil {
 			log.Errorf("gRPC Server start error: %s", err.Error())
 		}
 	}()
+	e.addr = ts.Addr()
 	e.started = true
-	<-ctx.Done()
-	return e.Shutdown(ctx)
+	return nil
+}
+
+// Addr 实际监听的地址, 未启动时为空
+func (e *Server) Addr() string {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	if e.addr == nil {
+		return ""
+	}
+	return e.addr.String()
+}
+
+// Stop 优雅停止, ctx 超时后强制关闭未结束的请求
+func (e *Server) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		e.srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		e.srv.Stop()
+		<-done
+	}
+	return nil
 }
 
 func (e *Server) Attempt() bool {
@@ -0,0 +1,43 @@
+package health
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v9"
+	"gorm.io/gorm"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// Pinger 可以探测连接状态的组件
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// DbCheck 检查数据库连接
+func DbCheck(db *gorm.DB) Checker {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// RedisCheck 检查redis连接
+func RedisCheck(client *redis.Client) Checker {
+	return func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	}
+}
+
+// QueueCheck 检查队列连接, 队列未实现 Pinger 时视为正常
+func QueueCheck(q storage.AdapterQueue) Checker {
+	return func(ctx context.Context) error {
+		if p, ok := q.(Pinger); ok {
+			return p.Ping(ctx)
+		}
+		return nil
+	}
+}
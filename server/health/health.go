@@ -0,0 +1,206 @@
+// Package health 组件注册健康检查, 提供 /healthz 与 /readyz 探针
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Status 检查状态
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+// Checker 健康检查, 返回nil表示正常
+type Checker func(ctx context.Context) error
+
+type check struct {
+	name     string
+	checker  Checker
+	optional bool
+	liveness bool
+}
+
+// Result 单个检查的结果
+type Result struct {
+	Name     string `json:"name"`
+	Status   Status `json:"status"`
+	Latency  string `json:"latency"`
+	Error    string `json:"error,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// Report 汇总结果
+type Report struct {
+	Status Status    `json:"status"`
+	Checks []Result  `json:"checks"`
+	Time   time.Time `json:"time"`
+}
+
+// Registry 健康检查注册表
+type Registry struct {
+	opts   options
+	mux    sync.RWMutex
+	checks []*check
+}
+
+// NewRegistry 创建注册表
+func NewRegistry(opts ...Option) *Registry {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Registry{opts: o}
+}
+
+// Register 注册检查, 同名检查会被替换
+func (r *Registry) Register(name string, checker Checker, opts ...CheckOption) {
+	c := &check{name: name, checker: checker}
+	for _, opt := range opts {
+		opt(c)
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for i := range r.checks {
+		if r.checks[i].name == name {
+			r.checks[i] = c
+			return
+		}
+	}
+	r.checks = append(r.checks, c)
+}
+
+// Unregister 移除检查
+func (r *Registry) Unregister(name string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for i := range r.checks {
+		if r.checks[i].name == name {
+			r.checks = append(r.checks[:i], r.checks[i+1:]...)
+			return
+		}
+	}
+}
+
+// Check 并发执行检查, liveness 为 true 时只执行存活检查
+func (r *Registry) Check(ctx context.Context, liveness bool) Report {
+	r.mux.RLock()
+	checks := make([]*check, 0, len(r.checks))
+	for _, c := range r.checks {
+		if !liveness || c.liveness {
+			checks = append(checks, c)
+		}
+	}
+	r.mux.RUnlock()
+
+	report := Report{Status: StatusUp, Checks: make([]Result, len(checks)), Time: time.Now()}
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c *check) {
+			defer wg.Done()
+			report.Checks[i] = r.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+	for _, res := range report.Checks {
+		switch {
+		case res.Status == StatusUp:
+		case res.Optional:
+			if report.Status == StatusUp {
+				report.Status = StatusDegraded
+			}
+		default:
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, c *check) (res Result) {
+	ctx, cancel := context.WithTimeout(ctx, r.opts.timeout)
+	defer cancel()
+	start := time.Now()
+	res = Result{Name: c.name, Status: StatusUp, Optional: c.optional}
+	defer func() {
+		res.Latency = time.Since(start).String()
+		if res.Error != "" {
+			res.Status = StatusDown
+			if c.optional {
+				res.Status = StatusDegraded
+			}
+		}
+	}()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				done <- fmt.Errorf("panic: %v", err)
+			}
+		}()
+		done <- c.checker(ctx)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			res.Error = err.Error()
+		}
+	case <-ctx.Done():
+		res.Error = ctx.Err().Error()
+	}
+	return
+}
+
+// Healthz 存活探针, 只执行 Liveness 检查
+func (r *Registry) Healthz() http.HandlerFunc {
+	return r.handler(true)
+}
+
+// Readyz 就绪探针, 执行全部检查, 可选检查失败时返回 200 degraded
+func (r *Registry) Readyz() http.HandlerFunc {
+	return r.handler(false)
+}
+
+func (r *Registry) handler(liveness bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		report := r.Check(req.Context(), liveness)
+		code := http.StatusOK
+		if report.Status == StatusDown {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(report)
+	}
+}
+
+var defaultRegistry = NewRegistry()
+
+// Default 默认注册表
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register 注册到默认注册表
+func Register(name string, checker Checker, opts ...CheckOption) {
+	defaultRegistry.Register(name, checker, opts...)
+}
+
+// Healthz 默认注册表的存活探针
+func Healthz() http.HandlerFunc {
+	return defaultRegistry.Healthz()
+}
+
+// Readyz 默认注册表的就绪探针
+func Readyz() http.HandlerFunc {
+	return defaultRegistry.Readyz()
+}
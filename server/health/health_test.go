@@ -0,0 +1,68 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(WithTimeout(50 * time.Millisecond))
+	r.Register("db", func(context.Context) error { return nil }, Liveness())
+	r.Register("cache", func(context.Context) error { return errors.New("refused") }, Optional())
+
+	report := r.Check(context.TODO(), false)
+	if report.Status != StatusDegraded {
+		t.Fatalf("status = %s, want degraded", report.Status)
+	}
+	if report.Checks[1].Status != StatusDegraded || report.Checks[1].Error != "refused" {
+		t.Errorf("cache result = %+v", report.Checks[1])
+	}
+
+	r.Register("queue", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	w := httptest.NewRecorder()
+	r.Readyz()(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("readyz code = %d", w.Code)
+	}
+	report = Report{}
+	_ = json.Unmarshal(w.Body.Bytes(), &report)
+	if report.Status != StatusDown || len(report.Checks) != 3 {
+		t.Errorf("readyz report = %+v", report)
+	}
+
+	w = httptest.NewRecorder()
+	r.Healthz()(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("healthz code = %d", w.Code)
+	}
+}
+
+func TestWaitReady(t *testing.T) {
+	r := NewRegistry(WithTimeout(50 * time.Millisecond))
+	var calls int
+	r.Register("db", func(context.Context) error {
+		if calls++; calls < 3 {
+			return errors.New("refused")
+		}
+		return nil
+	})
+	r.Register("cache", func(context.Context) error { return errors.New("refused") }, Optional())
+	if err := r.WaitReady(context.TODO(), time.Second, 10*time.Millisecond); err != nil || calls != 3 {
+		t.Fatalf("calls = %d, err = %v", calls, err)
+	}
+
+	r.Register("queue", func(context.Context) error { return errors.New("refused") })
+	err := r.WaitReady(context.TODO(), 50*time.Millisecond, 10*time.Millisecond)
+	var e *NotReadyError
+	if !errors.As(err, &e) || !errors.Is(err, ErrNotReady) || len(e.Failed) != 1 || e.Failed[0].Name != "queue" {
+		t.Errorf("err = %v", err)
+	}
+}
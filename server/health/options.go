@@ -0,0 +1,38 @@
+package health
+
+import "time"
+
+type Option func(*options)
+
+type options struct {
+	timeout time.Duration
+}
+
+func setDefault() options {
+	return options{
+		timeout: 3 * time.Second,
+	}
+}
+
+// WithTimeout 单个检查的超时时间
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+type CheckOption func(*check)
+
+// Optional 检查失败时只标记为 degraded, 不影响就绪状态
+func Optional() CheckOption {
+	return func(c *check) {
+		c.optional = true
+	}
+}
+
+// Liveness 同时用于存活检查 /healthz, 失败会导致容器重启, 只用于进程自身的检查
+func Liveness() CheckOption {
+	return func(c *check) {
+		c.liveness = true
+	}
+}
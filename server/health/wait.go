@@ -0,0 +1,79 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+// ErrNotReady 等待就绪超时
+var ErrNotReady = errors.New("health: dependencies not ready")
+
+// NotReadyError 超时时仍未通过的必需检查
+type NotReadyError struct {
+	Failed []Result
+}
+
+func (e *NotReadyError) Error() string {
+	list := make([]string, 0, len(e.Failed))
+	for _, res := range e.Failed {
+		list = append(list, res.Name+": "+res.Error)
+	}
+	return fmt.Sprintf("%s: %s", ErrNotReady, strings.Join(list, "; "))
+}
+
+func (e *NotReadyError) Unwrap() error {
+	return ErrNotReady
+}
+
+// WaitReady 启动前等待必需检查全部通过, 可选检查失败只记录警告; 超过 timeout 仍未通过时返回 *NotReadyError, ctx 取消时返回 ctx.Err(),
+// 避免依赖未就绪的实例挂到负载均衡后; timeout 为0时默认1分钟, interval 为0时默认1秒
+func (r *Registry) WaitReady(ctx context.Context, timeout, interval time.Duration) error {
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	log := logger.Module("server.health").WithContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report := r.Check(ctx, false)
+		var failed []Result
+		for _, res := range report.Checks {
+			if res.Status == StatusDown {
+				failed = append(failed, res)
+			}
+		}
+		if len(failed) == 0 {
+			for _, res := range report.Checks {
+				if res.Status == StatusDegraded {
+					log.Warn("optional dependency not ready", "check", res.Name, "error", res.Error)
+				}
+			}
+			return nil
+		}
+		for _, res := range failed {
+			log.Info("waiting for dependency", "check", res.Name, "error", res.Error)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return &NotReadyError{Failed: failed}
+		case <-ticker.C:
+		}
+	}
+}
+
+// WaitReady 等待默认注册表的检查通过
+func WaitReady(ctx context.Context, timeout, interval time.Duration) error {
+	return defaultRegistry.WaitReady(ctx, timeout, interval)
+}
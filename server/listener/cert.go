@@ -0,0 +1,87 @@
+package listener
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/go-admin-team/go-admin-core/logger"
+)
+
+// certLoader 按需检查证书文件的修改时间, 变更后重新加载
+type certLoader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	mux     sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time
+	checked time.Time
+}
+
+func newCertLoader(certFile, keyFile string, interval time.Duration) (*certLoader, error) {
+	l := &certLoader{certFile: certFile, keyFile: keyFile, interval: interval}
+	if err := l.load(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *certLoader) load() error {
+	modTime, err := l.stat()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		return err
+	}
+	l.mux.Lock()
+	l.cert = &cert
+	l.modTime = modTime
+	l.checked = time.Now()
+	l.mux.Unlock()
+	return nil
+}
+
+// stat 返回证书和私钥中较新的修改时间
+func (l *certLoader) stat() (time.Time, error) {
+	var t time.Time
+	for _, name := range []string{l.certFile, l.keyFile} {
+		fi, err := os.Stat(name)
+		if err != nil {
+			return t, err
+		}
+		if fi.ModTime().After(t) {
+			t = fi.ModTime()
+		}
+	}
+	return t, nil
+}
+
+func (l *certLoader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	l.mux.RLock()
+	cert, modTime, checked := l.cert, l.modTime, l.checked
+	l.mux.RUnlock()
+	if time.Since(checked) < l.interval {
+		return cert, nil
+	}
+	l.mux.Lock()
+	l.checked = time.Now()
+	l.mux.Unlock()
+	t, err := l.stat()
+	if err != nil || !t.After(modTime) {
+		return cert, nil
+	}
+	// 加载失败时继续使用旧证书
+	if err = l.load(); err != nil {
+		log.Module("server.listener").Error("reload certificate failed", "cert", l.certFile, "error", err)
+		return cert, nil
+	}
+	log.Module("server.listener").Info("certificate reloaded", "cert", l.certFile)
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+	return l.cert, nil
+}
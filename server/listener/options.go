@@ -9,6 +9,7 @@ package listener
 
 import (
 	"net/http"
+	"time"
 )
 
 // Option 参数设置类型
@@ -19,6 +20,13 @@ type options struct {
 	handler                 http.Handler
 	startedHook             func()
 	endHook                 func()
+	readTimeout             time.Duration
+	readHeaderTimeout       time.Duration
+	writeTimeout            time.Duration
+	idleTimeout             time.Duration
+	maxHeaderBytes          int
+	reloadInterval          time.Duration
+	h2c                     bool
 }
 
 func setDefaultOption() options {
@@ -27,6 +35,10 @@ func setDefaultOption() options {
 		handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 			w.WriteHeader(http.StatusOK)
 		}),
+		readHeaderTimeout: 10 * time.Second,
+		idleTimeout:       120 * time.Second,
+		maxHeaderBytes:    http.DefaultMaxHeaderBytes,
+		reloadInterval:    time.Minute,
 	}
 }
 
@@ -70,3 +82,52 @@ func WithKey(s string) Option {
 		o.keyFile = s
 	}
 }
+
+// WithReadTimeout 设置读取整个请求的超时时间
+func WithReadTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.readTimeout = d
+	}
+}
+
+// WithReadHeaderTimeout 设置读取请求头的超时时间
+func WithReadHeaderTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.readHeaderTimeout = d
+	}
+}
+
+// WithWriteTimeout 设置写响应的超时时间
+func WithWriteTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.writeTimeout = d
+	}
+}
+
+// WithIdleTimeout 设置keep-alive空闲连接的超时时间
+func WithIdleTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.idleTimeout = d
+	}
+}
+
+// WithMaxHeaderBytes 设置请求头最大字节数
+func WithMaxHeaderBytes(n int) Option {
+	return func(o *options) {
+		o.maxHeaderBytes = n
+	}
+}
+
+// WithReloadInterval 设置检查证书文件变更的最小间隔
+func WithReloadInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.reloadInterval = d
+	}
+}
+
+// WithH2C 未配置证书时支持明文 HTTP/2
+func WithH2C(enable bool) Option {
+	return func(o *options) {
+		o.h2c = enable
+	}
+}
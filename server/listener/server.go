@@ -9,12 +9,17 @@ package listener
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"net/http"
 
 	log "github.com/go-admin-team/go-admin-core/logger"
 	"github.com/go-admin-team/go-admin-core/server"
+	"github.com/go-admin-team/go-admin-core/server/health"
+	"github.com/go-admin-team/go-admin-core/tools/buildinfo"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 type Server struct {
@@ -49,7 +54,7 @@ func NewMetrics(opts ...Option) server.Runnable {
 	return s
 }
 
-// NewHealthz 默认健康检查服务
+// NewHealthz 默认健康检查服务, 执行 health 中注册的存活检查, 同时提供 /version 构建信息
 func NewHealthz(opts ...Option) server.Runnable {
 	s := &Server{
 		name: "healthz",
@@ -57,14 +62,14 @@ func NewHealthz(opts ...Option) server.Runnable {
 	}
 	s.opts.addr = ":4000"
 	h := http.NewServeMux()
-	h.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	})
+	h.HandleFunc("/healthz", health.Healthz())
+	h.HandleFunc("/version", buildinfo.Handler())
 	s.opts.handler = h
 	s.Options(opts...)
 	return s
 }
 
+// NewReadyz 默认就绪检查服务, 执行 health 中注册的全部检查
 func NewReadyz(opts ...Option) server.Runnable {
 	s := &Server{
 		name: "readyz",
@@ -72,9 +77,7 @@ func NewReadyz(opts ...Option) server.Runnable {
 	}
 	s.opts.addr = ":2000"
 	h := http.NewServeMux()
-	h.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	})
+	h.HandleFunc("/readyz", health.Readyz())
 	s.opts.handler = h
 	s.Options(opts...)
 	return s
@@ -97,9 +100,34 @@ func (e *Server) Start(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	tlsEnabled := e.opts.keyFile != "" && e.opts.certFile != ""
+	handler := e.opts.handler
+	if !tlsEnabled && e.opts.h2c {
+		handler = h2c.NewHandler(handler, &http2.Server{IdleTimeout: e.opts.idleTimeout})
+	}
+	e.srv = &http.Server{
+		Handler:           handler,
+		ReadTimeout:       e.opts.readTimeout,
+		ReadHeaderTimeout: e.opts.readHeaderTimeout,
+		WriteTimeout:      e.opts.writeTimeout,
+		IdleTimeout:       e.opts.idleTimeout,
+		MaxHeaderBytes:    e.opts.maxHeaderBytes,
+	}
+	if tlsEnabled {
+		// 证书文件变更后自动重新加载, 无需重启服务
+		cert, err := newCertLoader(e.opts.certFile, e.opts.keyFile, e.opts.reloadInterval)
+		if err != nil {
+			_ = l.Close()
+			return err
+		}
+		e.srv.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: cert.GetCertificate,
+			NextProtos:     []string{"h2", "http/1.1"},
+		}
+	}
 	e.ctx = ctx
 	e.started = true
-	e.srv = &http.Server{Handler: e.opts.handler}
 	if e.opts.endHook != nil {
 		e.srv.RegisterOnShutdown(e.opts.endHook)
 	}
@@ -108,12 +136,12 @@ func (e *Server) Start(ctx context.Context) error {
 	}
 	log.Infof("%s Server listening on %s", e.name, l.Addr().String())
 	go func() {
-		if e.opts.keyFile == "" || e.opts.certFile == "" {
+		if !tlsEnabled {
 			if err = e.srv.Serve(l); err != nil {
 				log.Errorf("%s Server start error: %s", e.name, err.Error())
 			}
 		} else {
-			if err = e.srv.ServeTLS(l, e.opts.certFile, e.opts.keyFile); err != nil {
+			if err = e.srv.ServeTLS(l, "", ""); err != nil {
 				log.Errorf("%s Server start error: %s", e.name, err.Error())
 			}
 		}
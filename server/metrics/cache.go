@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+// Cache 包装缓存, 按 hit、miss、error 统计 Get 结果, 命中率通过 PromQL 计算
+func (r *Registry) Cache(name string, c storage.AdapterCache) storage.AdapterCache {
+	return &instrumentedCache{
+		AdapterCache: c,
+		requests: r.Counter("cache_requests_total", "Cache get requests by result.", "cache", "result").
+			MustCurryWith(prometheus.Labels{"cache": name}),
+	}
+}
+
+type instrumentedCache struct {
+	storage.AdapterCache
+	requests *prometheus.CounterVec
+}
+
+// Unwrap 内部缓存
+func (c *instrumentedCache) Unwrap() storage.AdapterCache {
+	return c.AdapterCache
+}
+
+func (c *instrumentedCache) Get(key string) (string, error) {
+	val, err := c.AdapterCache.Get(key)
+	c.observe(val, err)
+	return val, err
+}
+
+// GetTouch 与 Get 一样统计结果
+func (c *instrumentedCache) GetTouch(key string, ttl time.Duration) (string, error) {
+	val, err := cache.GetTouch(c.AdapterCache, key, ttl)
+	c.observe(val, err)
+	return val, err
+}
+
+// 以下方法转发给内部缓存, 使包装后的缓存保留原子操作等能力
+
+func (c *instrumentedCache) SetNX(key string, val interface{}, expire int) (bool, error) {
+	return cache.SetNX(c.AdapterCache, key, val, expire)
+}
+
+func (c *instrumentedCache) CompareAndSwap(key, old string, val interface{}, expire int) (bool, error) {
+	return cache.CompareAndSwap(c.AdapterCache, key, old, val, expire)
+}
+
+func (c *instrumentedCache) HashSet(hk, key string, val interface{}) error {
+	return cache.HashSet(c.AdapterCache, hk, key, val)
+}
+
+func (c *instrumentedCache) HashGetAll(hk string) (map[string]string, error) {
+	return cache.HashGetAll(c.AdapterCache, hk)
+}
+
+func (c *instrumentedCache) Keys(prefix string) ([]string, error) {
+	return cache.Keys(c.AdapterCache, prefix)
+}
+
+func (c *instrumentedCache) observe(val string, err error) {
+	switch {
+	case errors.Is(err, redis.Nil), err == nil && val == "":
+		c.requests.WithLabelValues("miss").Inc()
+	case err != nil:
+		c.requests.WithLabelValues("error").Inc()
+	default:
+		c.requests.WithLabelValues("hit").Inc()
+	}
+}
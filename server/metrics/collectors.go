@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"github.com/go-redis/redis/v9"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+	"github.com/go-admin-team/go-admin-core/tools/buildinfo"
+	"github.com/go-admin-team/go-admin-core/tools/database"
+	"github.com/go-admin-team/go-admin-core/tools/pool"
+)
+
+// RegisterRuntime 注册 go 运行时与进程指标, 默认注册表已包含
+func (r *Registry) RegisterRuntime() error {
+	return r.Register(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// RegisterBuildInfo 注册值恒为1的 build_info 指标, 以标签区分版本, 可用于按版本聚合或发现版本不一致的实例
+func (r *Registry) RegisterBuildInfo() error {
+	i := buildinfo.Get()
+	return r.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: r.name("build_info"),
+		Help: "Build information, value is always 1.",
+		ConstLabels: prometheus.Labels{
+			"version":    i.Version,
+			"commit":     i.Commit,
+			"build_time": i.BuildTime,
+			"go_version": i.GoVersion,
+		},
+	}, func() float64 { return 1 }))
+}
+
+// RegisterDB 注册数据库连接池指标和使用率, name 区分多个数据库
+func (r *Registry) RegisterDB(name string, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return r.Register(
+		collectors.NewDBStatsCollector(sqlDB, name),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        r.name("db_pool_saturation"),
+			Help:        "Ratio of in-use connections to the max open connections.",
+			ConstLabels: prometheus.Labels{"db_name": name},
+		}, func() float64 {
+			return database.Saturation(sqlDB.Stats())
+		}),
+	)
+}
+
+// PoolWarn 统计连接池告警次数, 用于 database.PoolMonitor.OnWarn
+func (r *Registry) PoolWarn(name string, _ database.PoolStats) {
+	r.Counter("db_pool_warnings_total", "Connection pool saturation warnings.", "db_name").
+		WithLabelValues(name).Inc()
+}
+
+// Lener 可以获取待处理消息数量的队列
+type Lener interface {
+	Len() int
+}
+
+// RegisterQueue 注册队列长度指标, 队列未实现 Lener 时不注册
+func (r *Registry) RegisterQueue(name string, q storage.AdapterQueue) error {
+	l, ok := q.(Lener)
+	if !ok {
+		return nil
+	}
+	return r.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        r.name("queue_depth"),
+		Help:        "Number of messages waiting to be consumed.",
+		ConstLabels: prometheus.Labels{"queue": name},
+	}, func() float64 {
+		return float64(l.Len())
+	}))
+}
+
+// RegisterReaper 注册卡住消息的扫描、重新入队与转入死信数量, 以消费组区分
+func (r *Registry) RegisterReaper(group string, rp *queue.Reaper) error {
+	counter := func(name, help string, f func(s queue.ReaperStats) int64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: r.name(name), Help: help, ConstLabels: prometheus.Labels{"group": group},
+		}, func() float64 { return float64(f(rp.Stats())) })
+	}
+	return r.Register(
+		counter("queue_reaper_scans_total", "Pending entry list scans.", func(s queue.ReaperStats) int64 { return s.Scans }),
+		counter("queue_reaper_reclaimed_total", "Stuck messages re-enqueued.", func(s queue.ReaperStats) int64 { return s.Reclaimed }),
+		counter("queue_reaper_dead_lettered_total", "Stuck messages moved to the dead letter stream.", func(s queue.ReaperStats) int64 { return s.DeadLettered }),
+	)
+}
+
+// RegisterPool 注册协程池的并发、排队与任务数指标, 以池的名称区分
+func (r *Registry) RegisterPool(p *pool.Pool) error {
+	labels := prometheus.Labels{"pool": p.Stats().Name}
+	gauge := func(name, help string, f func(s pool.Stats) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: r.name(name), Help: help, ConstLabels: labels,
+		}, func() float64 { return f(p.Stats()) })
+	}
+	counter := func(name, help string, f func(s pool.Stats) float64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: r.name(name), Help: help, ConstLabels: labels,
+		}, func() float64 { return f(p.Stats()) })
+	}
+	return r.Register(
+		gauge("pool_workers", "Number of worker goroutines.", func(s pool.Stats) float64 { return float64(s.Workers) }),
+		gauge("pool_running", "Number of tasks being executed.", func(s pool.Stats) float64 { return float64(s.Running) }),
+		gauge("pool_waiting", "Number of tasks waiting for a worker.", func(s pool.Stats) float64 { return float64(s.Waiting) }),
+		counter("pool_completed_total", "Tasks completed.", func(s pool.Stats) float64 { return float64(s.Completed) }),
+		counter("pool_panics_total", "Tasks that panicked.", func(s pool.Stats) float64 { return float64(s.Panics) }),
+		counter("pool_rejected_total", "Tasks rejected because the queue was full.", func(s pool.Stats) float64 { return float64(s.Rejected) }),
+	)
+}
+
+// RedisPooler 可以获取连接池统计的redis连接, 如 *redis.Client、*cache.Redis
+type RedisPooler interface {
+	PoolStats() *redis.PoolStats
+}
+
+// RegisterRedisPool 注册redis连接池的连接数、命中与等待超时指标, 以名称区分
+func (r *Registry) RegisterRedisPool(name string, p RedisPooler) error {
+	labels := prometheus.Labels{"redis": name}
+	gauge := func(name, help string, f func(s *redis.PoolStats) uint32) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: r.name(name), Help: help, ConstLabels: labels,
+		}, func() float64 { return float64(f(p.PoolStats())) })
+	}
+	counter := func(name, help string, f func(s *redis.PoolStats) uint32) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: r.name(name), Help: help, ConstLabels: labels,
+		}, func() float64 { return float64(f(p.PoolStats())) })
+	}
+	return r.Register(
+		gauge("redis_pool_total_conns", "Number of connections in the pool.", func(s *redis.PoolStats) uint32 { return s.TotalConns }),
+		gauge("redis_pool_idle_conns", "Number of idle connections in the pool.", func(s *redis.PoolStats) uint32 { return s.IdleConns }),
+		counter("redis_pool_hits_total", "Times a free connection was found in the pool.", func(s *redis.PoolStats) uint32 { return s.Hits }),
+		counter("redis_pool_misses_total", "Times a free connection was not found in the pool.", func(s *redis.PoolStats) uint32 { return s.Misses }),
+		counter("redis_pool_timeouts_total", "Times a wait for a connection timed out.", func(s *redis.PoolStats) uint32 { return s.Timeouts }),
+		counter("redis_pool_stale_conns_total", "Stale connections removed from the pool.", func(s *redis.PoolStats) uint32 { return s.StaleConns }),
+	)
+}
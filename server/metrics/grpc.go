@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// ObserveGRPC 记录 gRPC 调用耗时, side 为 server 或 client, fullMethod 形如 /pkg.Service/Method
+func (r *Registry) ObserveGRPC(side, fullMethod string, err error, d time.Duration) {
+	service, method := path.Dir(fullMethod)[1:], path.Base(fullMethod)
+	r.Histogram("grpc_handling_seconds", "gRPC call latency in seconds.",
+		nil, "side", "service", "method", "code").
+		WithLabelValues(side, service, method, status.Code(err).String()).
+		Observe(d.Seconds())
+}
+
+// UnaryServerInterceptor 记录服务端 unary 调用耗时
+func (r *Registry) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		r.ObserveGRPC("server", info.FullMethod, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor 记录服务端 stream 的持续时间
+func (r *Registry) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, stream)
+		r.ObserveGRPC("server", info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+// UnaryClientInterceptor 记录客户端 unary 调用耗时
+func (r *Registry) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		r.ObserveGRPC("client", method, err, time.Since(start))
+		return err
+	}
+}
+
+// StreamClientInterceptor 记录客户端建立 stream 的耗时
+func (r *Registry) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		r.ObserveGRPC("client", method, err, time.Since(start))
+		return stream, err
+	}
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ObserveHTTP 记录请求耗时, route 应使用路由模板而不是实际路径, 避免标签过多
+func (r *Registry) ObserveHTTP(method, route string, code int, d time.Duration) {
+	r.Histogram("http_request_duration_seconds", "HTTP request latency in seconds.",
+		nil, "method", "route", "code").
+		WithLabelValues(method, route, strconv.Itoa(code)).
+		Observe(d.Seconds())
+}
+
+// InstrumentHandler 记录 net/http handler 的请求耗时
+func (r *Registry) InstrumentHandler(route string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, code: http.StatusOK}
+		h.ServeHTTP(sw, req)
+		r.ObserveHTTP(req.Method, route, sw.code, time.Since(start))
+	})
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	code int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	w.code = code
+	w.ResponseWriter.WriteHeader(code)
+}
@@ -0,0 +1,141 @@
+// Package metrics 注册标准的 prometheus 指标(http、数据库连接池、缓存命中率、队列长度、go运行时),
+// 并提供自定义指标的注册入口
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace 默认指标前缀
+const Namespace = "go_admin"
+
+// Registry 指标注册表, 同名指标只注册一次
+type Registry struct {
+	namespace  string
+	reg        prometheus.Registerer
+	gatherer   prometheus.Gatherer
+	mux        sync.Mutex
+	counters   map[string]*prometheus.CounterVec
+	gauges     map[string]*prometheus.GaugeVec
+	histograms map[string]*prometheus.HistogramVec
+}
+
+// New 使用独立的 prometheus.Registry 创建注册表
+func New(namespace string, reg *prometheus.Registry) *Registry {
+	return newRegistry(namespace, reg, reg)
+}
+
+func newRegistry(namespace string, reg prometheus.Registerer, gatherer prometheus.Gatherer) *Registry {
+	return &Registry{
+		namespace:  namespace,
+		reg:        reg,
+		gatherer:   gatherer,
+		counters:   make(map[string]*prometheus.CounterVec),
+		gauges:     make(map[string]*prometheus.GaugeVec),
+		histograms: make(map[string]*prometheus.HistogramVec),
+	}
+}
+
+var defaultRegistry = newRegistry(Namespace, prometheus.DefaultRegisterer, prometheus.DefaultGatherer)
+
+// Default 使用 prometheus 默认注册表, 与 listener.NewMetrics 输出一致
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register 注册自定义 collector, 已注册的忽略
+func (r *Registry) Register(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := r.reg.Register(c); err != nil {
+			var are prometheus.AlreadyRegisteredError
+			if errors.As(err, &are) {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Counter 获取或注册计数器
+func (r *Registry) Counter(name, help string, labels ...string) *prometheus.CounterVec {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if c, ok := r.counters[name]; ok {
+		return c
+	}
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: r.namespace,
+		Name:      name,
+		Help:      help,
+	}, labels)
+	c = r.mustRegister(c).(*prometheus.CounterVec)
+	r.counters[name] = c
+	return c
+}
+
+// Gauge 获取或注册仪表盘
+func (r *Registry) Gauge(name, help string, labels ...string) *prometheus.GaugeVec {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if g, ok := r.gauges[name]; ok {
+		return g
+	}
+	g := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: r.namespace,
+		Name:      name,
+		Help:      help,
+	}, labels)
+	g = r.mustRegister(g).(*prometheus.GaugeVec)
+	r.gauges[name] = g
+	return g
+}
+
+// Histogram 获取或注册直方图, buckets 为空时使用 prometheus.DefBuckets
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *prometheus.HistogramVec {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if h, ok := r.histograms[name]; ok {
+		return h
+	}
+	h := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: r.namespace,
+		Name:      name,
+		Help:      help,
+		Buckets:   buckets,
+	}, labels)
+	h = r.mustRegister(h).(*prometheus.HistogramVec)
+	r.histograms[name] = h
+	return h
+}
+
+// mustRegister 已被其他注册表实例注册时复用已有的 collector
+func (r *Registry) mustRegister(c prometheus.Collector) prometheus.Collector {
+	if err := r.reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			return are.ExistingCollector
+		}
+		panic(err)
+	}
+	return c
+}
+
+// Handler 输出 /metrics
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.gatherer, promhttp.HandlerOpts{})
+}
+
+// name 拼接指标全名, 用于 GaugeFunc 等不经过缓存的指标
+func (r *Registry) name(name string) string {
+	if r.namespace == "" {
+		return name
+	}
+	return strings.Join([]string{r.namespace, name}, "_")
+}
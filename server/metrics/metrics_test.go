@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+	"github.com/go-admin-team/go-admin-core/tools/database"
+	"github.com/go-admin-team/go-admin-core/tools/pool"
+)
+
+func TestRegistry(t *testing.T) {
+	r := New("test", prometheus.NewRegistry())
+	if r.Counter("jobs_total", "jobs", "name") != r.Counter("jobs_total", "jobs", "name") {
+		t.Fatal("Counter() should return the registered vector")
+	}
+	r.Counter("jobs_total", "jobs", "name").WithLabelValues("sync").Inc()
+
+	c := r.Cache("memory", cache.NewMemory())
+	_ = c.Set("k", "v", 60)
+	_, _ = c.Get("k")
+	_, _ = c.Get("missing")
+
+	q := queue.NewMemory(10)
+	if err := r.RegisterQueue("memory", q); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.RegisterReaper("go-admin", queue.NewReaper(nil, "go-admin", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.RegisterRedisPool("default", redis.NewClient(&redis.Options{})); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.RegisterBuildInfo(); err != nil {
+		t.Fatal(err)
+	}
+
+	p := pool.New(pool.WithName("jobs"))
+	if err := r.RegisterPool(p); err != nil {
+		t.Fatal(err)
+	}
+	_ = p.Submit(func() {})
+	_ = p.Close(context.Background())
+
+	h := r.InstrumentHandler("/ping", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+	r.ObserveGRPC("server", "/admin.User/Get", status.Error(codes.NotFound, ""), time.Millisecond)
+	r.PoolWarn("default", database.PoolStats{})
+
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(w.Body)
+	for _, want := range []string{
+		`test_jobs_total{name="sync"} 1`,
+		`test_cache_requests_total{cache="memory",result="hit"} 1`,
+		`test_cache_requests_total{cache="memory",result="miss"} 1`,
+		`test_queue_depth{queue="memory"} 0`,
+		`test_http_request_duration_seconds_count{code="418",method="GET",route="/ping"} 1`,
+		`test_grpc_handling_seconds_count{code="NotFound",method="Get",service="admin.User",side="server"} 1`,
+		`test_db_pool_warnings_total{db_name="default"} 1`,
+		`test_pool_completed_total{pool="jobs"} 1`,
+		`test_queue_reaper_dead_lettered_total{group="go-admin"} 0`,
+		`test_redis_pool_total_conns{redis="default"} 0`,
+		`test_build_info{build_time="`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics output missing %s", want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Package cachetest 缓存适配器的一致性测试, 第三方适配器在自己的测试中调用, 验证与内置实现的行为一致
+//
+//	func TestAdapter(t *testing.T) {
+//		cachetest.RunAdapterTests(t, func(t *testing.T) storage.AdapterCache {
+//			return mycache.New(...)
+//		})
+//	}
+package cachetest
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// Factory 为每个子测试创建适配器, 不同子测试使用不同的key, 可共享同一个后端
+type Factory func(t *testing.T) storage.AdapterCache
+
+// RunAdapterTests 执行全部一致性测试, 过期相关的测试需要等待约3秒
+func RunAdapterTests(t *testing.T, factory Factory) {
+	prefix := "cachetest:" + strconv.FormatInt(time.Now().UnixNano(), 36) + ":"
+	tests := []struct {
+		name string
+		f    func(t *testing.T, c storage.AdapterCache, key string)
+	}{
+		{"SetGet", testSetGet},
+		{"Del", testDel},
+		{"Missing", testMissing},
+		{"Expire", testExpire},
+		{"Counter", testCounter},
+		{"ConcurrentIncrease", testConcurrentIncrease},
+		{"Hash", testHash},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.f(t, factory(t), prefix+tt.name)
+		})
+	}
+}
+
+func get(t *testing.T, c storage.AdapterCache, key string) string {
+	t.Helper()
+	// 不存在的key返回空字符串, 是否同时返回错误由实现决定
+	v, _ := c.Get(key)
+	return v
+}
+
+func testSetGet(t *testing.T, c storage.AdapterCache, key string) {
+	if err := c.Set(key, "value", 60); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if v := get(t, c, key); v != "value" {
+		t.Errorf("Get = %q, want value", v)
+	}
+	if err := c.Set(key, 42, 60); err != nil {
+		t.Fatalf("Set int: %v", err)
+	}
+	if v := get(t, c, key); v != "42" {
+		t.Errorf("Get = %q, want 42", v)
+	}
+}
+
+func testDel(t *testing.T, c storage.AdapterCache, key string) {
+	_ = c.Set(key, "value", 60)
+	if err := c.Del(key); err != nil {
+		t.Fatalf("Del: %v", err)
+	}
+	if v := get(t, c, key); v != "" {
+		t.Errorf("Get after Del = %q", v)
+	}
+	if err := c.Del(key); err != nil {
+		t.Errorf("Del missing key: %v", err)
+	}
+}
+
+func testMissing(t *testing.T, c storage.AdapterCache, key string) {
+	if v := get(t, c, key); v != "" {
+		t.Errorf("Get missing key = %q", v)
+	}
+}
+
+func testExpire(t *testing.T, c storage.AdapterCache, key string) {
+	_ = c.Set(key, "value", 1)
+	_ = c.Set(key+":renew", "value", 1)
+	if err := c.Expire(key+":renew", 3*time.Second); err != nil {
+		t.Fatalf("Expire: %v", err)
+	}
+	time.Sleep(1500 * time.Millisecond)
+	if v := get(t, c, key); v != "" {
+		t.Errorf("Get after expiry = %q", v)
+	}
+	if v := get(t, c, key+":renew"); v != "value" {
+		t.Errorf("Get after Expire renew = %q", v)
+	}
+}
+
+func testCounter(t *testing.T, c storage.AdapterCache, key string) {
+	_ = c.Set(key, 10, 60)
+	if err := c.Increase(key); err != nil {
+		t.Fatalf("Increase: %v", err)
+	}
+	if err := c.Decrease(key); err != nil {
+		t.Fatalf("Decrease: %v", err)
+	}
+	if err := c.Decrease(key); err != nil {
+		t.Fatalf("Decrease: %v", err)
+	}
+	if v := get(t, c, key); v != "9" {
+		t.Errorf("counter = %q, want 9", v)
+	}
+}
+
+func testConcurrentIncrease(t *testing.T, c storage.AdapterCache, key string) {
+	const n = 50
+	_ = c.Set(key, 0, 60)
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Increase(key); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Increase: %v", err)
+	}
+	if v := get(t, c, key); v != fmt.Sprint(n) {
+		t.Errorf("counter = %q, want %d, increments lost", v, n)
+	}
+}
+
+func testHash(t *testing.T, c storage.AdapterCache, key string) {
+	if v, _ := c.HashGet(key, "field"); v != "" {
+		t.Errorf("HashGet missing field = %q", v)
+	}
+	if err := c.HashDel(key, "field"); err != nil {
+		t.Errorf("HashDel missing field: %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+package cachetest
+
+import (
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+func TestMemory(t *testing.T) {
+	RunAdapterTests(t, func(t *testing.T) storage.AdapterCache {
+		return cache.NewMemory()
+	})
+}
@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/tools/clock"
+)
+
+// EventType 缓存事件类型
+type EventType string
+
+const (
+	EventSet    EventType = "set"
+	EventDel    EventType = "del"
+	EventExpire EventType = "expire"
+)
+
+// Event 缓存事件, Key 为完整的key, Field 为 hash 的字段, 仅内存缓存的 HashSet、HashDel 有值
+type Event struct {
+	Type  EventType
+	Key   string
+	Field string
+}
+
+// eventBuffer 等待回调的事件数量, 超出后丢弃新的事件
+const eventBuffer = 1024
+
+// Events 缓存事件的回调注册, 回调在单独的协程中按事件顺序执行, 不阻塞缓存操作;
+// 回调中可以调用缓存的任何方法; 回调处理不及、等待的事件超过 1024 个时丢弃新的事件, 见 Dropped
+type Events struct {
+	mux      sync.RWMutex
+	handlers map[EventType][]func(Event)
+	once     sync.Once
+	ch       chan Event
+	dropped  uint64
+}
+
+// Dropped 因等待回调的事件过多而丢弃的事件数量
+func (e *Events) Dropped() uint64 {
+	return atomic.LoadUint64(&e.dropped)
+}
+
+// OnSet 写入key时执行
+func (e *Events) OnSet(f func(Event)) {
+	e.on(EventSet, f)
+}
+
+// OnDel 删除key时执行
+func (e *Events) OnDel(f func(Event)) {
+	e.on(EventDel, f)
+}
+
+// OnExpire key过期时执行, 如会话过期后通知客户端
+func (e *Events) OnExpire(f func(Event)) {
+	e.on(EventExpire, f)
+}
+
+func (e *Events) on(t EventType, f func(Event)) {
+	e.once.Do(func() {
+		e.ch = make(chan Event, eventBuffer)
+		go e.loop()
+	})
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	if e.handlers == nil {
+		e.handlers = make(map[EventType][]func(Event))
+	}
+	e.handlers[t] = append(e.handlers[t], f)
+}
+
+func (e *Events) emit(t EventType, key string) {
+	e.emitEvent(Event{Type: t, Key: key})
+}
+
+// emitEvent 未注册回调时直接返回; 可能在持有缓存的锁时调用, 不能等待
+func (e *Events) emitEvent(ev Event) {
+	e.mux.RLock()
+	n := len(e.handlers[ev.Type])
+	e.mux.RUnlock()
+	if n == 0 {
+		return
+	}
+	select {
+	case e.ch <- ev:
+	default:
+		if atomic.AddUint64(&e.dropped, 1)%eventBuffer == 1 {
+			logger.Module("storage.cache").Warn("cache event buffer full, events dropped", "dropped", e.Dropped())
+		}
+	}
+}
+
+func (e *Events) loop() {
+	for ev := range e.ch {
+		e.mux.RLock()
+		fs := e.handlers[ev.Type]
+		e.mux.RUnlock()
+		for _, f := range fs {
+			e.call(f, ev)
+		}
+	}
+}
+
+func (e *Events) call(f func(Event), ev Event) {
+	defer func() {
+		if err := recover(); err != nil {
+			logger.Module("storage.cache").Error("cache event handler panic", "event", ev.Type, "key", ev.Key, "panic", err)
+		}
+	}()
+	f(ev)
+}
+
+// StartJanitor 定期清理已过期的key并产生过期事件, 间隔使用 SetClock 设置的时间源; 未调用时过期的key在下次读取时清理
+func (m *Memory) StartJanitor(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	c := clock.OrReal(m.clock)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-c.After(interval):
+				m.items.Range(func(k, _ interface{}) bool {
+					key, _ := k.(string)
+					_, _ = m.readItem(key)
+					return true
+				})
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// ListenEvents 订阅 redis 的 keyevent 通知并产生事件, ctx 取消后停止; 会尝试开启 notify-keyspace-events,
+// 托管的 redis 禁止 CONFIG 命令时需在控制台开启 E、g、$、x; 多个实例都会收到其他实例的写入
+func (r *Redis) ListenEvents(ctx context.Context) error {
+	r.enableNotifications(ctx)
+	prefix := "__keyevent@" + strconv.Itoa(r.client.Options().DB) + "__:"
+	types := map[string]EventType{prefix + "set": EventSet, prefix + "del": EventDel, prefix + "expired": EventExpire}
+	sub := r.client.Subscribe(ctx, prefix+"set", prefix+"del", prefix+"expired")
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return err
+	}
+	go func() {
+		defer sub.Close()
+		ch := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				if t, ok := types[msg.Channel]; ok {
+					r.emit(t, msg.Payload)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// enableNotifications 在已有的设置上加上需要的类型
+func (r *Redis) enableNotifications(ctx context.Context) {
+	current := r.client.ConfigGet(ctx, "notify-keyspace-events").Val()["notify-keyspace-events"]
+	flags := current
+	for _, f := range "Eg$x" {
+		if !strings.ContainsRune(flags, f) && !(f != 'E' && strings.ContainsRune(flags, 'A')) {
+			flags += string(f)
+		}
+	}
+	if flags == current {
+		return
+	}
+	if err := r.client.ConfigSet(ctx, "notify-keyspace-events", flags).Err(); err != nil {
+		logger.Module("storage.cache").Warn("enable redis keyspace notifications failed", "error", err)
+	}
+}
@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/tools/clock"
+)
+
+func waitEvent(t *testing.T, ch chan Event, want Event) {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		if ev != want {
+			t.Errorf("event = %+v, want %+v", ev, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("event %+v not received", want)
+	}
+}
+
+func TestMemory_Events(t *testing.T) {
+	fake := clock.NewFake(time.Now())
+	m := NewMemory()
+	m.SetClock(fake)
+	ch := make(chan Event, 10)
+	m.OnSet(func(ev Event) { ch <- ev })
+	m.OnDel(func(ev Event) { ch <- ev })
+	m.OnExpire(func(ev Event) {
+		// 回调中可以调用缓存
+		_, _ = m.Get(ev.Key)
+		ch <- ev
+	})
+	_ = m.Set("a", "1", 10)
+	waitEvent(t, ch, Event{Type: EventSet, Key: "a"})
+	_ = m.Del("a")
+	waitEvent(t, ch, Event{Type: EventDel, Key: "a"})
+	_ = m.Del("a")
+	// 与事务中的 HashDel 一致
+	_ = m.HashSet("dict", "a", "1")
+	waitEvent(t, ch, Event{Type: EventSet, Key: "dict", Field: "a"})
+	_ = m.HashDel("dict", "a")
+	waitEvent(t, ch, Event{Type: EventDel, Key: "dict", Field: "a"})
+	_ = m.HashDel("dict", "a")
+
+	_ = m.Set("session:1", "tom", 10)
+	waitEvent(t, ch, Event{Type: EventSet, Key: "session:1"})
+	stop := m.StartJanitor(time.Minute)
+	defer stop()
+	for fake.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	fake.Advance(time.Minute)
+	waitEvent(t, ch, Event{Type: EventExpire, Key: "session:1"})
+	select {
+	case ev := <-ch:
+		t.Errorf("unexpected event %+v", ev)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestMemory_EventsDropped(t *testing.T) {
+	m := NewMemory()
+	release := make(chan struct{})
+	m.OnSet(func(ev Event) {
+		<-release
+		// 回调中访问同一个key不会因缓冲已满而死锁
+		_, _ = m.Get(ev.Key)
+	})
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < eventBuffer+100; i++ {
+			_ = m.Set("k", i, 10)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Set blocked by a full event buffer")
+	}
+	close(release)
+	if m.Dropped() == 0 {
+		t.Error("dropped events should be counted")
+	}
+}
+
+func TestRedis_ListenEvents(t *testing.T) {
+	s := miniredis.RunT(t)
+	r, err := NewRedis(nil, &redis.Options{Addr: s.Addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := make(chan Event, 10)
+	r.OnExpire(func(ev Event) { ch <- ev })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err = r.ListenEvents(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// miniredis 不产生 keyevent 通知, 直接发布
+	s.Publish("__keyevent@0__:del", "a")
+	s.Publish("__keyevent@0__:expired", "session:1")
+	waitEvent(t, ch, Event{Type: EventExpire, Key: "session:1"})
+}
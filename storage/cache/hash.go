@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/tools/clock"
+)
+
+// ErrHashGetAllUnsupported 缓存未实现 storage.AdapterHashCache
+var ErrHashGetAllUnsupported = errors.New("cache: HashGetAll not supported")
+
+type hashEntry struct {
+	values  map[string]string
+	expires time.Time
+}
+
+type hashCall struct {
+	wg     sync.WaitGroup
+	values map[string]string
+	err    error
+	// gen 开始读取时的 HashReader.gen, 期间调用过 Forget 时结果不保留
+	gen uint64
+}
+
+// DefaultHashReaderEntries HashReader 本地最多保留的hash数
+const DefaultHashReaderEntries = 1024
+
+// HashSet 写入hash的字段; 缓存未实现 storage.AdapterHashCache 时返回 ErrHashGetAllUnsupported
+func HashSet(c storage.AdapterCache, hk, key string, val interface{}) error {
+	hc, ok := c.(storage.AdapterHashCache)
+	if !ok {
+		return ErrHashGetAllUnsupported
+	}
+	return hc.HashSet(hk, key, val)
+}
+
+// HashGetAll hash的全部字段; 缓存未实现 storage.AdapterHashCache 时返回 ErrHashGetAllUnsupported
+func HashGetAll(c storage.AdapterCache, hk string) (map[string]string, error) {
+	hc, ok := c.(storage.AdapterHashCache)
+	if !ok {
+		return nil, ErrHashGetAllUnsupported
+	}
+	return hc.HashGetAll(hk)
+}
+
+// HashReader 合并同一个hash的读取: 并发读取同一个hash时只请求一次, 结果在本地保留 ttl,
+// 每次请求读取同一hash的多个字段(如权限校验)时只访问一次 redis
+//
+//	r := cache.NewHashReader(sdk.Runtime.GetCacheAdapter(), time.Second)
+//	perms, err := r.HGetAllCached("role:perms:" + roleKey)
+type HashReader struct {
+	c          storage.AdapterCache
+	ttl        time.Duration
+	clock      clock.Clock
+	mux        sync.Mutex
+	calls      map[string]*hashCall
+	local      map[string]hashEntry
+	maxEntries int
+	gen        uint64
+}
+
+// NewHashReader ttl 为本地保留时间, 为0时只合并并发的读取
+func NewHashReader(c storage.AdapterCache, ttl time.Duration) *HashReader {
+	return &HashReader{
+		c:          c,
+		ttl:        ttl,
+		clock:      clock.Real,
+		calls:      make(map[string]*hashCall),
+		local:      make(map[string]hashEntry),
+		maxEntries: DefaultHashReaderEntries,
+	}
+}
+
+// SetMaxEntries 本地最多保留的hash数, 超出时先清除过期的, 仍超出时随机淘汰; 默认 DefaultHashReaderEntries
+func (r *HashReader) SetMaxEntries(n int) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.maxEntries = n
+}
+
+// SetClock 设置判断本地过期使用的时间源; 需在使用前调用
+func (r *HashReader) SetClock(c clock.Clock) {
+	r.clock = c
+}
+
+// HGetAllCached hash的全部字段, 返回的 map 由调用方共享, 不要修改
+func (r *HashReader) HGetAllCached(hk string) (map[string]string, error) {
+	r.mux.Lock()
+	if e, ok := r.local[hk]; ok {
+		if r.clock.Now().Before(e.expires) {
+			r.mux.Unlock()
+			return e.values, nil
+		}
+		delete(r.local, hk)
+	}
+	if c, ok := r.calls[hk]; ok {
+		r.mux.Unlock()
+		c.wg.Wait()
+		return c.values, c.err
+	}
+	c := &hashCall{gen: r.gen}
+	c.wg.Add(1)
+	r.calls[hk] = c
+	r.mux.Unlock()
+
+	c.values, c.err = r.load(hk)
+	c.wg.Done()
+
+	r.mux.Lock()
+	if r.calls[hk] == c {
+		delete(r.calls, hk)
+	}
+	if c.err == nil && r.ttl > 0 && c.gen == r.gen {
+		r.store(hk, c.values)
+	}
+	r.mux.Unlock()
+	return c.values, c.err
+}
+
+// store 保留读取的结果, 需持有 r.mux
+func (r *HashReader) store(hk string, values map[string]string) {
+	now := r.clock.Now()
+	if r.maxEntries > 0 && len(r.local) >= r.maxEntries {
+		for k, e := range r.local {
+			if !now.Before(e.expires) {
+				delete(r.local, k)
+			}
+		}
+		for k := range r.local {
+			if len(r.local) < r.maxEntries {
+				break
+			}
+			delete(r.local, k)
+		}
+	}
+	r.local[hk] = hashEntry{values: values, expires: now.Add(r.ttl)}
+}
+
+// HGetCached hash中的一个字段, 不存在时返回空字符串
+func (r *HashReader) HGetCached(hk, key string) (string, error) {
+	values, err := r.HGetAllCached(hk)
+	if err != nil {
+		return "", err
+	}
+	return values[key], nil
+}
+
+// Forget 清除本地保留的结果, 修改hash后调用; 正在进行的读取可能读到旧值, 其结果不保留, 之后的读取重新请求;
+// 其他实例在 ttl 后读到新值
+func (r *HashReader) Forget(hk string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	delete(r.local, hk)
+	delete(r.calls, hk)
+	r.gen++
+}
+
+func (r *HashReader) load(hk string) (map[string]string, error) {
+	c, ok := r.c.(storage.AdapterHashCache)
+	if !ok {
+		return nil, ErrHashGetAllUnsupported
+	}
+	return c.HashGetAll(hk)
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/tools/clock"
+)
+
+type countHash struct {
+	*Memory
+	calls int32
+}
+
+func (c *countHash) HashGetAll(hk string) (map[string]string, error) {
+	atomic.AddInt32(&c.calls, 1)
+	time.Sleep(20 * time.Millisecond)
+	return c.Memory.HashGetAll(hk)
+}
+
+func TestHashReader(t *testing.T) {
+	m := &countHash{Memory: NewMemory()}
+	_ = m.HashSet("perms:1", "system:user:add", "1")
+	_ = m.HashSet("perms:1", "system:user:edit", "1")
+	_ = m.HashSet("perms:2", "system:role:add", "1")
+	c := clock.NewFake(time.Now())
+	r := NewHashReader(m, time.Second)
+	r.SetClock(c)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := r.HGetCached("perms:1", "system:user:edit"); err != nil || v != "1" {
+				t.Errorf("v = %q, err = %v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	values, _ := r.HGetAllCached("perms:1")
+	if len(values) != 2 || atomic.LoadInt32(&m.calls) != 1 {
+		t.Fatalf("values = %v, calls = %d", values, m.calls)
+	}
+
+	c.Advance(2 * time.Second)
+	_, _ = r.HGetAllCached("perms:1")
+	r.Forget("perms:1")
+	_, _ = r.HGetAllCached("perms:1")
+	if atomic.LoadInt32(&m.calls) != 3 {
+		t.Errorf("calls = %d after expiry and forget", m.calls)
+	}
+
+	// 读取期间调用 Forget, 读到的旧值不保留
+	done := make(chan struct{})
+	go func() {
+		_, _ = r.HGetAllCached("perms:2")
+		close(done)
+	}()
+	time.Sleep(5 * time.Millisecond)
+	r.Forget("perms:2")
+	<-done
+	_, _ = r.HGetAllCached("perms:2")
+	if atomic.LoadInt32(&m.calls) != 5 {
+		t.Errorf("calls = %d, stale value stored after forget", m.calls)
+	}
+
+	// 超出上限时淘汰
+	r.SetMaxEntries(2)
+	for _, hk := range []string{"a", "b", "c"} {
+		_, _ = r.HGetAllCached(hk)
+	}
+	r.mux.Lock()
+	n := len(r.local)
+	r.mux.Unlock()
+	if n > 2 {
+		t.Errorf("local entries = %d", n)
+	}
+
+	if _, err := NewHashReader(struct{ storage.AdapterCache }{NewMemory()}, 0).HGetAllCached("a"); err != ErrHashGetAllUnsupported {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestMemory_Hash(t *testing.T) {
+	m := NewMemory()
+	_ = m.HashSet("dict", "a", "1")
+	_ = m.HashSet("dict", "b", 2)
+	_ = m.HashSet("dictx", "a", "x")
+	_ = m.Set("dict_version", "3", 60)
+
+	// 前缀相同的其他hash与key不会混入
+	if values, err := m.HashGetAll("dict"); err != nil || len(values) != 2 || values["b"] != "2" {
+		t.Errorf("values = %v, err = %v", values, err)
+	}
+	if v, _ := m.HashGet("dictx", "a"); v != "x" {
+		t.Errorf("dictx.a = %q", v)
+	}
+	if _, err := m.HashGet("dict_version", "a"); err == nil {
+		t.Error("HashGet on a string should fail")
+	}
+	if _, err := m.Get("dict"); err == nil {
+		t.Error("Get on a hash should fail")
+	}
+
+	if err := m.Expire("dict", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	_ = m.HashDel("dict", "a")
+	if values, _ := m.HashGetAll("dict"); len(values) != 1 || values["b"] != "2" {
+		t.Errorf("after HashDel values = %v", values)
+	}
+	_ = m.HashDel("dict", "b")
+	if keys, _ := m.Keys("dict"); len(keys) != 2 {
+		t.Errorf("keys = %v, empty hash should be removed", keys)
+	}
+}
@@ -0,0 +1,19 @@
+package cache
+
+import (
+	"errors"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// ErrKeysUnsupported 缓存未实现 storage.AdapterKeysCache
+var ErrKeysUnsupported = errors.New("cache: listing keys not supported")
+
+// Keys 以 prefix 开头的key; 缓存未实现 storage.AdapterKeysCache 时返回 ErrKeysUnsupported
+func Keys(c storage.AdapterCache, prefix string) ([]string, error) {
+	kc, ok := c.(storage.AdapterKeysCache)
+	if !ok {
+		return nil, ErrKeysUnsupported
+	}
+	return kc.Keys(prefix)
+}
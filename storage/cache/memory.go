@@ -2,28 +2,69 @@ package cache
 
 import (
 	"fmt"
+	"hash/fnv"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/spf13/cast"
+
+	"github.com/go-admin-team/go-admin-core/tools/clock"
 )
 
+// item 保存的值, hash 的字段保存在 Fields 中; Expired 为零值时不过期
 type item struct {
 	Value   string
+	Fields  map[string]string
 	Expired time.Time
 }
 
+func typeError(key string) error {
+	return fmt.Errorf("value of %s type error", key)
+}
+
 // NewMemory memory模式
 func NewMemory() *Memory {
 	return &Memory{
 		items: new(sync.Map),
+		clock: clock.Real,
 	}
 }
 
+// stripeCount 分段锁的数量, key 按哈希分配到其中一个
+const stripeCount = 64
+
+// Memory 内存缓存, 按 key 的哈希分段加锁, 不同分段的 key 互不等待:
+//   - Get、HashGet 取分段读锁, 可并发执行
+//   - Set、Del 等写入与 Increase、Decrease、Expire、GetTouch 取分段写锁, 同一 key 的读改写不会丢失更新
+//   - Tx 按分段顺序取涉及的全部分段写锁, 期间这些 key 的其他操作等待, 不会死锁
+//   - 已保存的值不会被修改, 只整体替换, Keys、HashGetAll 等遍历不加锁
+//   - 过期的值只在分段写锁内确认仍然过期后删除, 不会误删其间新写入的值
 type Memory struct {
-	items *sync.Map
-	mutex sync.RWMutex
+	Events
+	items   *sync.Map
+	stripes [stripeCount]sync.RWMutex
+	clock   clock.Clock
+}
+
+func (m *Memory) stripe(key string) *sync.RWMutex {
+	return &m.stripes[stripeOf(key)]
+}
+
+func stripeOf(key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum32() % stripeCount
+}
+
+// SetClock 设置判断过期使用的时间源, 测试中使用 clock.Fake; 需在使用前调用
+func (m *Memory) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
+func (m *Memory) now() time.Time {
+	return clock.OrReal(m.clock).Now()
 }
 
 func (*Memory) String() string {
@@ -34,33 +75,60 @@ func (m *Memory) connect() {
 }
 
 func (m *Memory) Get(key string) (string, error) {
-	item, err := m.getItem(key)
+	item, err := m.readItem(key)
 	if err != nil || item == nil {
 		return "", err
 	}
+	if item.Fields != nil {
+		return "", typeError(key)
+	}
 	return item.Value, nil
 }
 
-func (m *Memory) getItem(key string) (*item, error) {
-	var err error
+// loadItem 保存的值, 不判断是否过期
+func (m *Memory) loadItem(key string) (*item, error) {
 	i, ok := m.items.Load(key)
 	if !ok {
 		return nil, nil
 	}
-	switch i.(type) {
-	case *item:
-		item := i.(*item)
-		if item.Expired.Before(time.Now()) {
-			//过期
-			_ = m.del(key)
-			//过期后删除
-			return nil, nil
-		}
-		return item, nil
-	default:
-		err = fmt.Errorf("value of %s type error", key)
+	item, ok := i.(*item)
+	if !ok {
+		return nil, typeError(key)
+	}
+	return item, nil
+}
+
+func (m *Memory) expired(item *item) bool {
+	return !item.Expired.IsZero() && item.Expired.Before(m.now())
+}
+
+// getItem 未过期的值, 已过期时删除并产生过期事件; 调用方需持有该 key 的分段写锁
+func (m *Memory) getItem(key string) (*item, error) {
+	item, err := m.loadItem(key)
+	if err != nil || item == nil {
 		return nil, err
 	}
+	if m.expired(item) {
+		//过期后删除
+		m.items.Delete(key)
+		m.emit(EventExpire, key)
+		return nil, nil
+	}
+	return item, nil
+}
+
+// readItem 未持有分段锁时读取: 读锁内读取, 已过期时取写锁重新读取, 由 getItem 删除
+func (m *Memory) readItem(key string) (*item, error) {
+	l := m.stripe(key)
+	l.RLock()
+	item, err := m.loadItem(key)
+	l.RUnlock()
+	if err != nil || item == nil || !m.expired(item) {
+		return item, err
+	}
+	l.Lock()
+	defer l.Unlock()
+	return m.getItem(key)
 }
 
 func (m *Memory) Set(key string, val interface{}, expire int) error {
@@ -70,9 +138,65 @@ func (m *Memory) Set(key string, val interface{}, expire int) error {
 	}
 	item := &item{
 		Value:   s,
-		Expired: time.Now().Add(time.Duration(expire) * time.Second),
+		Expired: m.now().Add(time.Duration(expire) * time.Second),
 	}
-	return m.setItem(key, item)
+	l := m.stripe(key)
+	l.Lock()
+	err = m.setItem(key, item)
+	l.Unlock()
+	if err != nil {
+		return err
+	}
+	m.emit(EventSet, key)
+	return nil
+}
+
+// SetNX key 不存在或已过期时写入, 返回是否写入
+func (m *Memory) SetNX(key string, val interface{}, expire int) (bool, error) {
+	s, err := cast.ToStringE(val)
+	if err != nil {
+		return false, err
+	}
+	l := m.stripe(key)
+	l.Lock()
+	current, err := m.getItem(key)
+	if err != nil || current != nil {
+		l.Unlock()
+		return false, err
+	}
+	err = m.setItem(key, &item{Value: s, Expired: m.now().Add(time.Duration(expire) * time.Second)})
+	l.Unlock()
+	if err != nil {
+		return false, err
+	}
+	m.emit(EventSet, key)
+	return true, nil
+}
+
+// CompareAndSwap 当前值为 old 时写入 val, expire 为有效期(秒), 0不过期; key 不存在时不写入
+func (m *Memory) CompareAndSwap(key, old string, val interface{}, expire int) (bool, error) {
+	s, err := cast.ToStringE(val)
+	if err != nil {
+		return false, err
+	}
+	next := &item{Value: s}
+	if expire > 0 {
+		next.Expired = m.now().Add(time.Duration(expire) * time.Second)
+	}
+	l := m.stripe(key)
+	l.Lock()
+	current, err := m.getItem(key)
+	if err != nil || current == nil || current.Fields != nil || current.Value != old {
+		l.Unlock()
+		return false, err
+	}
+	err = m.setItem(key, next)
+	l.Unlock()
+	if err != nil {
+		return false, err
+	}
+	m.emit(EventSet, key)
+	return true, nil
 }
 
 func (m *Memory) setItem(key string, item *item) error {
@@ -81,7 +205,14 @@ func (m *Memory) setItem(key string, item *item) error {
 }
 
 func (m *Memory) Del(key string) error {
-	return m.del(key)
+	l := m.stripe(key)
+	l.Lock()
+	_, loaded := m.items.LoadAndDelete(key)
+	l.Unlock()
+	if loaded {
+		m.emit(EventDel, key)
+	}
+	return nil
 }
 
 func (m *Memory) del(key string) error {
@@ -90,15 +221,125 @@ func (m *Memory) del(key string) error {
 }
 
 func (m *Memory) HashGet(hk, key string) (string, error) {
-	item, err := m.getItem(hk + key)
+	item, err := m.readItem(hk)
 	if err != nil || item == nil {
 		return "", err
 	}
-	return item.Value, err
+	if item.Fields == nil {
+		return "", typeError(hk)
+	}
+	return item.Fields[key], nil
+}
+
+// HashSet 写入hash的字段, 新建的hash不过期, 可用 Expire 设置整个hash的有效期
+func (m *Memory) HashSet(hk, key string, val interface{}) error {
+	s, err := cast.ToStringE(val)
+	if err != nil {
+		return err
+	}
+	l := m.stripe(hk)
+	l.Lock()
+	current, err := m.getItem(hk)
+	if err == nil && current != nil && current.Fields == nil {
+		err = typeError(hk)
+	}
+	if err != nil {
+		l.Unlock()
+		return err
+	}
+	next := &item{Fields: map[string]string{key: s}}
+	if current != nil {
+		next.Fields = copyFields(current.Fields, len(current.Fields)+1)
+		next.Fields[key] = s
+		next.Expired = current.Expired
+	}
+	err = m.setItem(hk, next)
+	l.Unlock()
+	if err != nil {
+		return err
+	}
+	m.emitEvent(Event{Type: EventSet, Key: hk, Field: key})
+	return nil
+}
+
+// copyFields 已保存的 Fields 不能修改, 修改前复制
+func copyFields(fields map[string]string, size int) map[string]string {
+	copied := make(map[string]string, size)
+	for k, v := range fields {
+		copied[k] = v
+	}
+	return copied
+}
+
+// HashGetAll 读取hash的全部字段
+func (m *Memory) HashGetAll(hk string) (map[string]string, error) {
+	item, err := m.readItem(hk)
+	if err != nil || item == nil {
+		return map[string]string{}, err
+	}
+	if item.Fields == nil {
+		return nil, typeError(hk)
+	}
+	return copyFields(item.Fields, len(item.Fields)), nil
 }
 
+// Keys 以 prefix 开头且未过期的key, 无序
+func (m *Memory) Keys(prefix string) ([]string, error) {
+	keys := make([]string, 0)
+	m.items.Range(func(k, _ interface{}) bool {
+		key, _ := k.(string)
+		if !strings.HasPrefix(key, prefix) {
+			return true
+		}
+		if item, err := m.readItem(key); err == nil && item != nil {
+			keys = append(keys, key)
+		}
+		return true
+	})
+	return keys, nil
+}
+
+// HashDel 删除hash的字段, 最后一个字段删除后hash一起删除
 func (m *Memory) HashDel(hk, key string) error {
-	return m.del(hk + key)
+	l := m.stripe(hk)
+	l.Lock()
+	current, err := m.getItem(hk)
+	if err != nil || current == nil {
+		l.Unlock()
+		return err
+	}
+	next, err := hashDel(hk, key, current)
+	if err != nil || next == current {
+		l.Unlock()
+		return err
+	}
+	if next == nil {
+		_ = m.del(hk)
+	} else {
+		_ = m.setItem(hk, next)
+	}
+	l.Unlock()
+	m.emitEvent(Event{Type: EventDel, Key: hk, Field: key})
+	return nil
+}
+
+// hashDel 删除字段后的hash, 字段不存在时返回 current, 全部字段删除后返回 nil
+func hashDel(hk, key string, current *item) (*item, error) {
+	if current == nil {
+		return nil, nil
+	}
+	if current.Fields == nil {
+		return nil, typeError(hk)
+	}
+	if _, ok := current.Fields[key]; !ok {
+		return current, nil
+	}
+	if len(current.Fields) == 1 {
+		return nil, nil
+	}
+	fields := copyFields(current.Fields, len(current.Fields))
+	delete(fields, key)
+	return &item{Fields: fields, Expired: current.Expired}, nil
 }
 
 func (m *Memory) Increase(key string) error {
@@ -110,38 +351,56 @@ func (m *Memory) Decrease(key string) error {
 }
 
 func (m *Memory) calculate(key string, num int) error {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
-	item, err := m.getItem(key)
+	l := m.stripe(key)
+	l.Lock()
+	defer l.Unlock()
+	current, err := m.getItem(key)
 	if err != nil {
 		return err
 	}
 
-	if item == nil {
+	if current == nil {
 		err = fmt.Errorf("%s not exist", key)
 		return err
 	}
+	if current.Fields != nil {
+		return typeError(key)
+	}
 	var n int
-	n, err = cast.ToIntE(item.Value)
+	n, err = cast.ToIntE(current.Value)
 	if err != nil {
 		return err
 	}
 	n += num
-	item.Value = strconv.Itoa(n)
-	return m.setItem(key, item)
+	return m.setItem(key, &item{Value: strconv.Itoa(n), Expired: current.Expired})
 }
 
 func (m *Memory) Expire(key string, dur time.Duration) error {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
-	item, err := m.getItem(key)
+	l := m.stripe(key)
+	l.Lock()
+	defer l.Unlock()
+	current, err := m.getItem(key)
 	if err != nil {
 		return err
 	}
-	if item == nil {
+	if current == nil {
 		err = fmt.Errorf("%s not exist", key)
 		return err
 	}
-	item.Expired = time.Now().Add(dur)
-	return m.setItem(key, item)
+	return m.setItem(key, &item{Value: current.Value, Fields: current.Fields, Expired: m.now().Add(dur)})
+}
+
+// GetTouch 读取并把有效期重置为 ttl, key 不存在时返回空
+func (m *Memory) GetTouch(key string, ttl time.Duration) (string, error) {
+	l := m.stripe(key)
+	l.Lock()
+	defer l.Unlock()
+	it, err := m.getItem(key)
+	if err != nil || it == nil {
+		return "", err
+	}
+	if it.Fields != nil {
+		return "", typeError(key)
+	}
+	return it.Value, m.setItem(key, &item{Value: it.Value, Expired: m.now().Add(ttl)})
 }
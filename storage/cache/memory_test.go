@@ -4,6 +4,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/go-admin-team/go-admin-core/tools/clock"
 )
 
 func TestMemory_Get(t *testing.T) {
@@ -68,3 +70,146 @@ func TestMemory_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestMemory_Clock(t *testing.T) {
+	c := clock.NewFake(time.Now())
+	m := NewMemory()
+	m.SetClock(c)
+	_ = m.Set("a", "1", 60)
+	c.Advance(59 * time.Second)
+	if v, _ := m.Get("a"); v != "1" {
+		t.Errorf("get = %q before expire", v)
+	}
+	_ = m.Expire("a", time.Minute)
+	c.Advance(61 * time.Second)
+	if v, _ := m.Get("a"); v != "" {
+		t.Errorf("get = %q after expire", v)
+	}
+}
+
+// 以下测试需配合 go test -race 运行
+
+func TestMemory_StripedLocks(t *testing.T) {
+	m := NewMemory()
+	a, b := "a", "b"
+	for stripeOf(b) == stripeOf(a) {
+		b += "b"
+	}
+	_ = m.Set(a, "0", 60)
+	_ = m.Set(b, "0", 60)
+	// 占用 a 的分段, 其他分段的 key 不受影响
+	m.stripe(a).Lock()
+	done := make(chan struct{})
+	go func() {
+		_ = m.Increase(b)
+		_ = m.Expire(b, time.Minute)
+		_, _ = m.Get(b)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("operation on an unrelated key was blocked")
+	}
+	blocked := make(chan struct{})
+	go func() {
+		_ = m.Increase(a)
+		close(blocked)
+	}()
+	select {
+	case <-blocked:
+		t.Fatal("operation on a locked key should wait")
+	case <-time.After(20 * time.Millisecond):
+	}
+	m.stripe(a).Unlock()
+	<-blocked
+	if v, _ := m.Get(a); v != "1" {
+		t.Errorf("a = %s", v)
+	}
+}
+
+func TestMemory_ConcurrentIncrease(t *testing.T) {
+	m := NewMemory()
+	keys := []string{"k1", "k2", "k3", "k4"}
+	for _, k := range keys {
+		_ = m.Set(k, "0", 60)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		for _, k := range keys {
+			wg.Add(1)
+			go func(k string) {
+				defer wg.Done()
+				for j := 0; j < 50; j++ {
+					_ = m.Increase(k)
+					_, _ = m.Get(k)
+					_, _ = m.Keys("k")
+				}
+			}(k)
+		}
+	}
+	wg.Wait()
+	for _, k := range keys {
+		if v, _ := m.Get(k); v != "1000" {
+			t.Errorf("%s = %s", k, v)
+		}
+	}
+}
+
+func TestMemory_SetNX(t *testing.T) {
+	m := NewMemory()
+	fake := clock.NewFake(time.Unix(0, 0))
+	m.SetClock(fake)
+	if ok, err := m.SetNX("nonce", "1", 10); err != nil || !ok {
+		t.Fatalf("first SetNX = %v, %v", ok, err)
+	}
+	if ok, _ := m.SetNX("nonce", "2", 10); ok {
+		t.Error("SetNX on existing key should not write")
+	}
+	if v, _ := m.Get("nonce"); v != "1" {
+		t.Errorf("value = %s, want 1", v)
+	}
+	fake.Advance(11 * time.Second)
+	if ok, _ := m.SetNX("nonce", "3", 10); !ok {
+		t.Error("SetNX on expired key should write")
+	}
+}
+
+// racingClock 判断过期时并发写入同一个key, 模拟遍历与写入交错
+type racingClock struct {
+	*clock.Fake
+	race func()
+}
+
+func (c *racingClock) Now() time.Time {
+	if f := c.race; f != nil {
+		c.race = nil
+		done := make(chan struct{})
+		go func() {
+			f()
+			close(done)
+		}()
+		// 写入需等待分段锁时不会完成
+		select {
+		case <-done:
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	return c.Fake.Now()
+}
+
+func TestMemory_ExpiredReplaced(t *testing.T) {
+	m := NewMemory()
+	c := &racingClock{Fake: clock.NewFake(time.Unix(0, 0))}
+	m.SetClock(c)
+	_ = m.Set("a", "old", 1)
+	c.Advance(2 * time.Second)
+	c.race = func() {
+		_ = m.Set("a", "new", 60)
+	}
+	_, _ = m.Keys("")
+	time.Sleep(60 * time.Millisecond)
+	if v, _ := m.Get("a"); v != "new" {
+		t.Errorf("value = %q, fresh value deleted", v)
+	}
+}
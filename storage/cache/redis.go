@@ -2,8 +2,10 @@ package cache
 
 import (
 	"context"
-	"github.com/go-redis/redis/v9"
+	"strings"
 	"time"
+
+	"github.com/go-redis/redis/v9"
 )
 
 // NewRedis redis模式
@@ -23,6 +25,7 @@ func NewRedis(client *redis.Client, options *redis.Options) (*Redis, error) {
 
 // Redis cache implement
 type Redis struct {
+	Events
 	client *redis.Client
 }
 
@@ -47,6 +50,11 @@ func (r *Redis) Set(key string, val interface{}, expire int) error {
 	return r.client.Set(context.TODO(), key, val, time.Duration(expire)*time.Second).Err()
 }
 
+// SetNX key 不存在时写入, 返回是否写入
+func (r *Redis) SetNX(key string, val interface{}, expire int) (bool, error) {
+	return r.client.SetNX(context.TODO(), key, val, time.Duration(expire)*time.Second).Result()
+}
+
 // Del delete key in redis
 func (r *Redis) Del(key string) error {
 	return r.client.Del(context.TODO(), key).Err()
@@ -57,6 +65,29 @@ func (r *Redis) HashGet(hk, key string) (string, error) {
 	return r.client.HGet(context.TODO(), hk, key).Result()
 }
 
+// HashSet 写入hash的字段
+func (r *Redis) HashSet(hk, key string, val interface{}) error {
+	return r.client.HSet(context.TODO(), hk, key, val).Err()
+}
+
+// HashGetAll 读取hash的全部字段
+func (r *Redis) HashGetAll(hk string) (map[string]string, error) {
+	return r.client.HGetAll(context.TODO(), hk).Result()
+}
+
+// Keys 以 prefix 开头的key, 使用 SCAN 遍历, 不阻塞redis; 无序
+func (r *Redis) Keys(prefix string) ([]string, error) {
+	keys := make([]string, 0)
+	iter := r.client.Scan(context.TODO(), 0, globEscaper.Replace(prefix)+"*", 1000).Iterator()
+	for iter.Next(context.TODO()) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+// globEscaper 转义 SCAN MATCH 中的通配符
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
 // HashDel delete key in specify redis's hashtable
 func (r *Redis) HashDel(hk, key string) error {
 	return r.client.HDel(context.TODO(), hk, key).Err()
@@ -76,6 +107,25 @@ func (r *Redis) Expire(key string, dur time.Duration) error {
 	return r.client.Expire(context.TODO(), key, dur).Err()
 }
 
+// GetTouch 读取并把有效期重置为 ttl, 在一个事务中执行 GET 与 PEXPIRE, 兼容 6.2 以前不支持 GETEX 的版本
+func (r *Redis) GetTouch(key string, ttl time.Duration) (string, error) {
+	var get *redis.StringCmd
+	_, err := r.client.TxPipelined(context.TODO(), func(pipe redis.Pipeliner) error {
+		get = pipe.Get(context.TODO(), key)
+		pipe.PExpire(context.TODO(), key, ttl)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return get.Result()
+}
+
+// PoolStats 连接池统计, 见 metrics.Registry.RegisterRedisPool
+func (r *Redis) PoolStats() *redis.PoolStats {
+	return r.client.PoolStats()
+}
+
 // GetClient 暴露原生client
 func (r *Redis) GetClient() *redis.Client {
 	return r.client
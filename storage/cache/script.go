@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+var (
+	// ErrScriptNotFound 脚本未注册
+	ErrScriptNotFound = errors.New("cache: script not registered")
+	// ErrCASUnsupported 缓存未实现 storage.AdapterCASCache
+	ErrCASUnsupported = errors.New("cache: CompareAndSwap not supported")
+)
+
+var (
+	_ storage.AdapterCASCache = (*Memory)(nil)
+	_ storage.AdapterCASCache = (*Redis)(nil)
+)
+
+var (
+	scriptMutex sync.RWMutex
+	scripts     = make(map[string]*redis.Script)
+)
+
+// RegisterScript 注册命名的 lua 脚本, 重复注册会覆盖之前的脚本; 通常在包的变量初始化时调用
+//
+//	var dedup = cache.RegisterScript("order.dedup", `return redis.call("SET", KEYS[1], 1, "NX", "EX", ARGV[1])`)
+func RegisterScript(name, src string) *redis.Script {
+	s := redis.NewScript(src)
+	scriptMutex.Lock()
+	defer scriptMutex.Unlock()
+	scripts[name] = s
+	return s
+}
+
+// Script 已注册的脚本
+func Script(name string) (*redis.Script, bool) {
+	scriptMutex.RLock()
+	defer scriptMutex.RUnlock()
+	s, ok := scripts[name]
+	return s, ok
+}
+
+// Scripts 已注册脚本的名称, 已排序
+func Scripts() []string {
+	scriptMutex.RLock()
+	defer scriptMutex.RUnlock()
+	names := make([]string, 0, len(scripts))
+	for name := range scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadScripts 预先加载全部已注册的脚本, 启动时调用可避免首次执行时回退到 EVAL
+func (r *Redis) LoadScripts(ctx context.Context) error {
+	for _, name := range Scripts() {
+		s, _ := Script(name)
+		if err := s.Load(ctx, r.client).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunScript 执行已注册的脚本, 使用 EVALSHA, 服务端返回 NOSCRIPT(如重启后)时自动改用 EVAL
+func (r *Redis) RunScript(ctx context.Context, name string, keys []string, args ...interface{}) *redis.Cmd {
+	s, ok := Script(name)
+	if !ok {
+		cmd := redis.NewCmd(ctx)
+		cmd.SetErr(ErrScriptNotFound)
+		return cmd
+	}
+	return s.Run(ctx, r.client, keys, args...)
+}
+
+// casScript 值等于 ARGV[1] 时写入 ARGV[2], ARGV[3] 为有效期(毫秒), 0不过期
+var casScript = RegisterScript("cache.cas", `
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+else
+	redis.call("SET", KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+// CompareAndSwap 在缓存 c 上比较后写入, 用于带前缀的包装; 缓存不支持时返回 ErrCASUnsupported
+func CompareAndSwap(c storage.AdapterCache, key, old string, val interface{}, expire int) (bool, error) {
+	cc, ok := c.(storage.AdapterCASCache)
+	if !ok {
+		return false, ErrCASUnsupported
+	}
+	return cc.CompareAndSwap(key, old, val, expire)
+}
+
+// CompareAndSwap 当前值为 old 时写入 val, expire 为有效期(秒); 返回是否写入
+func (r *Redis) CompareAndSwap(key, old string, val interface{}, expire int) (bool, error) {
+	n, err := casScript.Run(context.TODO(), r.client, []string{key}, old, val, expire*1000).Int()
+	return n == 1, err
+}
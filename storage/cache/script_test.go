@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+func TestRedis_Scripts(t *testing.T) {
+	s := miniredis.RunT(t)
+	r, err := NewRedis(nil, &redis.Options{Addr: s.Addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	RegisterScript("test.incr_by", `return redis.call("INCRBY", KEYS[1], ARGV[1])`)
+	if n, err := r.RunScript(ctx, "test.incr_by", []string{"n"}, 2).Int(); err != nil || n != 2 {
+		t.Fatalf("n = %d, err = %v", n, err)
+	}
+	// 服务端脚本缓存清空后回退到 EVAL
+	r.GetClient().ScriptFlush(ctx)
+	if n, err := r.RunScript(ctx, "test.incr_by", []string{"n"}, 3).Int(); err != nil || n != 5 {
+		t.Errorf("after flush: n = %d, err = %v", n, err)
+	}
+	if err = r.LoadScripts(ctx); err != nil {
+		t.Error(err)
+	}
+	if err = r.RunScript(ctx, "missing", nil).Err(); err != ErrScriptNotFound {
+		t.Errorf("err = %v", err)
+	}
+
+	_ = r.Set("ver", "1", 0)
+	if ok, err := r.CompareAndSwap("ver", "2", "3", 0); ok || err != nil {
+		t.Errorf("stale swap: ok = %v, err = %v", ok, err)
+	}
+	if ok, err := r.CompareAndSwap("ver", "1", "2", 60); !ok || err != nil {
+		t.Errorf("swap: ok = %v, err = %v", ok, err)
+	}
+	if v, _ := r.Get("ver"); v != "2" || s.TTL("ver") == 0 {
+		t.Errorf("v = %s, ttl = %s", v, s.TTL("ver"))
+	}
+
+	// 内存缓存与 redis 一致
+	m := NewMemory()
+	_ = m.Set("ver", "1", 60)
+	if ok, err := CompareAndSwap(m, "ver", "2", "3", 0); ok || err != nil {
+		t.Errorf("memory stale swap: ok = %v, err = %v", ok, err)
+	}
+	if ok, err := CompareAndSwap(m, "ver", "1", "2", 0); !ok || err != nil {
+		t.Errorf("memory swap: ok = %v, err = %v", ok, err)
+	}
+	if ok, _ := CompareAndSwap(m, "missing", "", "1", 0); ok {
+		t.Error("missing key should not be swapped")
+	}
+}
+
+func TestSetNX_Fallback(t *testing.T) {
+	s := miniredis.RunT(t)
+	r, err := NewRedis(nil, &redis.Options{Addr: s.Addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 未实现 SetNX 的包装, redis 以 redis.Nil 表示不存在
+	wrapped := struct{ storage.AdapterCache }{r}
+	if ok, err := SetNX(wrapped, "nonce", "1", 10); !ok || err != nil {
+		t.Fatalf("first SetNX = %v, %v", ok, err)
+	}
+	if ok, err := SetNX(wrapped, "nonce", "2", 10); ok || err != nil {
+		t.Errorf("second SetNX = %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+var setNXMux sync.Mutex
+
+// SetNX key 不存在时写入, 返回是否写入; 用于防重放、幂等等占位场景.
+// 缓存未实现 storage.AdapterSetNXCache 时退化为加锁的 Get+Set, 仅在当前进程内是原子的
+func SetNX(c storage.AdapterCache, key string, val interface{}, expire int) (bool, error) {
+	if s, ok := c.(storage.AdapterSetNXCache); ok {
+		return s.SetNX(key, val, expire)
+	}
+	setNXMux.Lock()
+	defer setNXMux.Unlock()
+	v, err := c.Get(key)
+	if IsMiss(err) {
+		v, err = "", nil
+	}
+	if err != nil || v != "" {
+		return false, err
+	}
+	return true, c.Set(key, val, expire)
+}
+
+// IsMiss Get 返回的错误表示 key 不存在; redis 以 redis.Nil 表示, 内存缓存返回空字符串且没有错误
+func IsMiss(err error) bool {
+	return errors.Is(err, redis.Nil)
+}
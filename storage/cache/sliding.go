@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// GetTouch 读取并把有效期重置为 ttl; 缓存未实现 storage.AdapterTouchCache 时分两步读取与设置有效期
+func GetTouch(c storage.AdapterCache, key string, ttl time.Duration) (string, error) {
+	if t, ok := c.(storage.AdapterTouchCache); ok {
+		return t.GetTouch(key, ttl)
+	}
+	v, err := c.Get(key)
+	if err != nil || v == "" {
+		return v, err
+	}
+	return v, c.Expire(key, ttl)
+}
+
+// Sliding 滑动过期: 指定前缀的 key 每次 Get 时有效期重置为 ttl, 用于会话等有访问就保持的数据,
+// 其他 key 与其他操作直接调用内部缓存
+//
+//	c := cache.NewSliding(sdk.Runtime.GetCacheAdapter(), 30*time.Minute, "session:")
+type Sliding struct {
+	storage.AdapterCache
+	ttl      time.Duration
+	prefixes []string
+}
+
+var _ storage.AdapterTouchCache = (*Sliding)(nil)
+
+// NewSliding prefixes 为空时全部 key 都滑动过期
+func NewSliding(c storage.AdapterCache, ttl time.Duration, prefixes ...string) *Sliding {
+	return &Sliding{AdapterCache: c, ttl: ttl, prefixes: prefixes}
+}
+
+// Unwrap 内部缓存
+func (s *Sliding) Unwrap() storage.AdapterCache {
+	return s.AdapterCache
+}
+
+func (s *Sliding) Get(key string) (string, error) {
+	if !s.match(key) {
+		return s.AdapterCache.Get(key)
+	}
+	return GetTouch(s.AdapterCache, key, s.ttl)
+}
+
+// GetTouch 使用指定的 ttl, 不受前缀限制
+func (s *Sliding) GetTouch(key string, ttl time.Duration) (string, error) {
+	return GetTouch(s.AdapterCache, key, ttl)
+}
+
+// SetNX key 不存在时写入, 返回是否写入
+func (s *Sliding) SetNX(key string, val interface{}, expire int) (bool, error) {
+	return SetNX(s.AdapterCache, key, val, expire)
+}
+
+func (s *Sliding) match(key string) bool {
+	if len(s.prefixes) == 0 {
+		return true
+	}
+	for _, p := range s.prefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/tools/clock"
+)
+
+func TestSliding(t *testing.T) {
+	fake := clock.NewFake(time.Unix(0, 0))
+	m := NewMemory()
+	m.SetClock(fake)
+	c := NewSliding(m, time.Minute, "session:")
+	_ = c.Set("session:1", "tom", 60)
+	_ = c.Set("user:1", "jerry", 60)
+	for i := 0; i < 3; i++ {
+		fake.Advance(40 * time.Second)
+		if v, _ := c.Get("session:1"); v != "tom" {
+			t.Fatalf("session expired after %d reads", i)
+		}
+	}
+	if v, _ := c.Get("user:1"); v != "" {
+		t.Errorf("key outside the namespace should not slide, got %q", v)
+	}
+	fake.Advance(61 * time.Second)
+	if v, _ := c.Get("session:1"); v != "" {
+		t.Error("idle session should expire")
+	}
+
+	// 未实现 GetTouch 的缓存分两步执行
+	plain := struct{ storage.AdapterCache }{m}
+	_ = m.Set("k", "v", 10)
+	if v, err := GetTouch(plain, "k", time.Hour); v != "v" || err != nil {
+		t.Errorf("v = %q, err = %v", v, err)
+	}
+	fake.Advance(time.Minute)
+	if v, _ := m.Get("k"); v != "v" {
+		t.Error("fallback should extend the ttl")
+	}
+	if v, err := GetTouch(plain, "missing", time.Hour); v != "" || err != nil {
+		t.Errorf("missing: v = %q, err = %v", v, err)
+	}
+}
+
+func TestRedis_GetTouch(t *testing.T) {
+	s := miniredis.RunT(t)
+	r, err := NewRedis(nil, &redis.Options{Addr: s.Addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = r.Set("session:1", "tom", 10)
+	if v, err := r.GetTouch("session:1", time.Minute); v != "tom" || err != nil {
+		t.Fatalf("v = %q, err = %v", v, err)
+	}
+	if ttl := s.TTL("session:1"); ttl != time.Minute {
+		t.Errorf("ttl = %s", ttl)
+	}
+	if _, err = r.GetTouch("missing", time.Minute); err != redis.Nil {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,304 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// ErrTxUnsupported 缓存未实现 storage.AdapterTxCache
+var ErrTxUnsupported = errors.New("cache: Tx not supported")
+
+var (
+	_ storage.AdapterTxCache = (*Memory)(nil)
+	_ storage.AdapterTxCache = (*Redis)(nil)
+)
+
+// Tx 在缓存 c 上执行事务, 事务中的key自动加上 prefix, 用于带前缀的包装;
+// f 返回错误时不执行任何写入
+//
+//	err := cache.Tx(c, "", func(p storage.Pipeliner) error {
+//		p.Set("user:1:profile", profile, 3600)
+//		p.Set("user:1:perm", perms, 3600)
+//		return nil
+//	})
+func Tx(c storage.AdapterCache, prefix string, f func(p storage.Pipeliner) error) error {
+	tc, ok := c.(storage.AdapterTxCache)
+	if !ok {
+		return ErrTxUnsupported
+	}
+	if prefix == "" {
+		return tc.Tx(f)
+	}
+	return tc.Tx(func(p storage.Pipeliner) error {
+		return f(&prefixPipeliner{p: p, prefix: prefix})
+	})
+}
+
+type prefixPipeliner struct {
+	p      storage.Pipeliner
+	prefix string
+}
+
+func (p *prefixPipeliner) Set(key string, val interface{}, expire int) {
+	p.p.Set(p.prefix+key, val, expire)
+}
+
+func (p *prefixPipeliner) Del(key string) {
+	p.p.Del(p.prefix + key)
+}
+
+func (p *prefixPipeliner) HashDel(hk, key string) {
+	p.p.HashDel(p.prefix+hk, key)
+}
+
+func (p *prefixPipeliner) Increase(key string) {
+	p.p.Increase(p.prefix + key)
+}
+
+func (p *prefixPipeliner) Decrease(key string) {
+	p.p.Decrease(p.prefix + key)
+}
+
+func (p *prefixPipeliner) Expire(key string, dur time.Duration) {
+	p.p.Expire(p.prefix+key, dur)
+}
+
+// txScript 先按顺序检查全部操作, 任一操作会失败时不写入并返回错误, 再依次执行;
+// KEYS 每个操作一个, ARGV 每个操作三个: 操作名与两个参数
+var txScript = RegisterScript("cache.tx", `
+local state = {}
+for i = 1, #KEYS do
+	local op, key = ARGV[3*i-2], KEYS[i]
+	local s = state[key]
+	if s == nil then
+		s = {type = redis.call("TYPE", key).ok}
+		if s.type == "string" then
+			s.value = redis.call("GET", key)
+		end
+	end
+	if op == "set" then
+		s = {type = "string", value = ARGV[3*i-1]}
+	elseif op == "del" then
+		s = {type = "none"}
+	elseif op == "hdel" then
+		if s.type ~= "none" and s.type ~= "hash" then
+			return redis.error_reply(key .. " is not a hash")
+		end
+	elseif s.type == "none" then
+		return redis.error_reply(key .. " not exist")
+	elseif (op == "incr" or op == "decr") and (s.type ~= "string" or not string.match(s.value, "^-?%d+$")) then
+		return redis.error_reply(key .. " is not an integer")
+	end
+	state[key] = s
+end
+for i = 1, #KEYS do
+	local op, key, a1, a2 = ARGV[3*i-2], KEYS[i], ARGV[3*i-1], ARGV[3*i]
+	if op == "set" then
+		if tonumber(a2) > 0 then
+			redis.call("SET", key, a1, "EX", a2)
+		else
+			redis.call("SET", key, a1)
+		end
+	elseif op == "del" then
+		redis.call("DEL", key)
+	elseif op == "hdel" then
+		redis.call("HDEL", key, a1)
+	elseif op == "incr" then
+		redis.call("INCR", key)
+	elseif op == "decr" then
+		redis.call("DECR", key)
+	elseif op == "expire" then
+		redis.call("PEXPIRE", key, a1)
+	end
+end
+return #KEYS
+`)
+
+// Tx 以 lua 脚本执行, 与 Memory 一致: Increase、Decrease、Expire 的key不存在或值不是整数时全部不写入;
+// f 返回错误时不发送. 集群模式下全部key需在同一个slot
+func (r *Redis) Tx(f func(p storage.Pipeliner) error) error {
+	p := new(redisPipeliner)
+	if err := f(p); err != nil {
+		return err
+	}
+	if len(p.keys) == 0 {
+		return nil
+	}
+	return txScript.Run(context.TODO(), r.client, p.keys, p.args...).Err()
+}
+
+type redisPipeliner struct {
+	keys []string
+	args []interface{}
+}
+
+func (p *redisPipeliner) add(op, key string, a1, a2 interface{}) {
+	p.keys = append(p.keys, key)
+	p.args = append(p.args, op, a1, a2)
+}
+
+func (p *redisPipeliner) Set(key string, val interface{}, expire int) {
+	p.add("set", key, val, expire)
+}
+
+func (p *redisPipeliner) Del(key string) {
+	p.add("del", key, "", "")
+}
+
+func (p *redisPipeliner) HashDel(hk, key string) {
+	p.add("hdel", hk, key, "")
+}
+
+func (p *redisPipeliner) Increase(key string) {
+	p.add("incr", key, "", "")
+}
+
+func (p *redisPipeliner) Decrease(key string) {
+	p.add("decr", key, "", "")
+}
+
+func (p *redisPipeliner) Expire(key string, dur time.Duration) {
+	p.add("expire", key, dur.Milliseconds(), "")
+}
+
+// memoryOp 事务中的一个操作, apply 修改暂存的值, 返回 nil 表示删除
+type memoryOp struct {
+	key   string
+	field string
+	event EventType
+	apply func(current *item) (*item, error)
+}
+
+type memoryPipeliner struct {
+	m   *Memory
+	ops []memoryOp
+	err error
+}
+
+func (p *memoryPipeliner) add(key string, event EventType, apply func(current *item) (*item, error)) {
+	p.ops = append(p.ops, memoryOp{key: key, event: event, apply: apply})
+}
+
+func (p *memoryPipeliner) Set(key string, val interface{}, expire int) {
+	s, err := cast.ToStringE(val)
+	if err != nil && p.err == nil {
+		p.err = err
+	}
+	p.add(key, EventSet, func(*item) (*item, error) {
+		return &item{Value: s, Expired: p.m.now().Add(time.Duration(expire) * time.Second)}, nil
+	})
+}
+
+func (p *memoryPipeliner) Del(key string) {
+	p.add(key, EventDel, func(*item) (*item, error) {
+		return nil, nil
+	})
+}
+
+func (p *memoryPipeliner) HashDel(hk, key string) {
+	p.ops = append(p.ops, memoryOp{key: hk, field: key, event: EventDel, apply: func(current *item) (*item, error) {
+		return hashDel(hk, key, current)
+	}})
+}
+
+func (p *memoryPipeliner) Increase(key string) {
+	p.add(key, "", calculate(key, 1))
+}
+
+func (p *memoryPipeliner) Decrease(key string) {
+	p.add(key, "", calculate(key, -1))
+}
+
+func (p *memoryPipeliner) Expire(key string, dur time.Duration) {
+	p.add(key, "", func(current *item) (*item, error) {
+		if current == nil {
+			return nil, fmt.Errorf("%s not exist", key)
+		}
+		return &item{Value: current.Value, Fields: current.Fields, Expired: p.m.now().Add(dur)}, nil
+	})
+}
+
+func calculate(key string, num int) func(current *item) (*item, error) {
+	return func(current *item) (*item, error) {
+		if current == nil {
+			return nil, fmt.Errorf("%s not exist", key)
+		}
+		if current.Fields != nil {
+			return nil, typeError(key)
+		}
+		n, err := cast.ToIntE(current.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &item{Value: strconv.Itoa(n + num), Expired: current.Expired}, nil
+	}
+}
+
+// Tx 在暂存区依次执行全部操作, 全部成功后在写锁内一起写入; 任何操作失败时不写入
+func (m *Memory) Tx(f func(p storage.Pipeliner) error) error {
+	p := &memoryPipeliner{m: m}
+	if err := f(p); err != nil {
+		return err
+	}
+	if p.err != nil {
+		return p.err
+	}
+	var events []Event
+	if err := m.commit(p.ops, &events); err != nil {
+		return err
+	}
+	for _, ev := range events {
+		m.emitEvent(ev)
+	}
+	return nil
+}
+
+func (m *Memory) commit(ops []memoryOp, events *[]Event) error {
+	// 按分段顺序加锁, 并发的事务不会互相等待形成死锁
+	locked := make([]bool, stripeCount)
+	for _, op := range ops {
+		locked[stripeOf(op.key)] = true
+	}
+	for i := range locked {
+		if locked[i] {
+			m.stripes[i].Lock()
+			defer m.stripes[i].Unlock()
+		}
+	}
+	staged := make(map[string]*item)
+	order := make([]string, 0, len(ops))
+	for _, op := range ops {
+		current, ok := staged[op.key]
+		if !ok {
+			var err error
+			if current, err = m.getItem(op.key); err != nil {
+				return err
+			}
+			order = append(order, op.key)
+		}
+		next, err := op.apply(current)
+		if err != nil {
+			return err
+		}
+		staged[op.key] = next
+		// 删除不存在的key或字段时不产生事件
+		if op.event == EventSet || op.event == EventDel && next != current {
+			*events = append(*events, Event{Type: op.event, Key: op.key, Field: op.field})
+		}
+	}
+	for _, key := range order {
+		if it := staged[key]; it != nil {
+			_ = m.setItem(key, it)
+		} else {
+			_ = m.del(key)
+		}
+	}
+	return nil
+}
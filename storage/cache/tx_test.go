@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+func TestMemory_Tx(t *testing.T) {
+	m := NewMemory()
+	_ = m.Set("n", "1", 60)
+	err := Tx(m, "user:1:", func(p storage.Pipeliner) error {
+		p.Set("profile", "tom", 60)
+		p.Set("perm", "admin", 60)
+		return nil
+	})
+	if v1, _ := m.Get("user:1:profile"); err != nil || v1 != "tom" {
+		t.Fatalf("profile = %q, err = %v", v1, err)
+	}
+
+	// 任一操作失败时全部不写入
+	err = m.Tx(func(p storage.Pipeliner) error {
+		p.Increase("n")
+		p.Del("user:1:perm")
+		p.Increase("missing")
+		return nil
+	})
+	if v, _ := m.Get("n"); err == nil || v != "1" {
+		t.Errorf("n = %q, err = %v", v, err)
+	}
+	if v, _ := m.Get("user:1:perm"); v != "admin" {
+		t.Error("failed tx should not delete")
+	}
+	// hash 字段的删除
+	_ = m.HashSet("dict", "a", "1")
+	_ = m.HashSet("dict", "b", "2")
+	if err = m.Tx(func(p storage.Pipeliner) error {
+		p.HashDel("dict", "a")
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if values, _ := m.HashGetAll("dict"); len(values) != 1 || values["b"] != "2" {
+		t.Errorf("dict = %v", values)
+	}
+	abort := errors.New("abort")
+	if err = m.Tx(func(p storage.Pipeliner) error {
+		p.Set("n", "9", 60)
+		return abort
+	}); err != abort {
+		t.Errorf("err = %v", err)
+	}
+
+	// 并发的事务依次执行
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = m.Tx(func(p storage.Pipeliner) error {
+				p.Increase("n")
+				p.Increase("n")
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	if v, _ := m.Get("n"); v != "101" {
+		t.Errorf("n = %s", v)
+	}
+}
+
+func TestRedis_Tx(t *testing.T) {
+	s := miniredis.RunT(t)
+	r, err := NewRedis(nil, &redis.Options{Addr: s.Addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = r.Set("n", "1", 0)
+	err = r.Tx(func(p storage.Pipeliner) error {
+		p.Set("user:1:profile", "tom", 60)
+		p.Increase("n")
+		p.Expire("n", time.Minute)
+		return nil
+	})
+	if v, _ := r.Get("n"); err != nil || v != "2" || s.TTL("n") != time.Minute {
+		t.Errorf("n = %s, err = %v", v, err)
+	}
+	_ = r.Tx(func(p storage.Pipeliner) error {
+		p.Del("user:1:profile")
+		return errors.New("abort")
+	})
+	if v, _ := r.Get("user:1:profile"); v != "tom" {
+		t.Error("aborted tx should not be sent")
+	}
+
+	// 与 Memory 一致, key 不存在或不是整数时全部不写入
+	_ = r.Set("name", "tom", 0)
+	for _, f := range []func(p storage.Pipeliner){
+		func(p storage.Pipeliner) { p.Increase("missing") },
+		func(p storage.Pipeliner) { p.Expire("missing", time.Minute) },
+		func(p storage.Pipeliner) { p.Decrease("name") },
+		func(p storage.Pipeliner) { p.HashDel("name", "field") },
+	} {
+		err = r.Tx(func(p storage.Pipeliner) error {
+			p.Increase("n")
+			p.Del("user:1:profile")
+			f(p)
+			return nil
+		})
+		if v, _ := r.Get("n"); err == nil || v != "2" {
+			t.Errorf("n = %s, err = %v", v, err)
+		}
+		if s.Exists("missing") || !s.Exists("user:1:profile") {
+			t.Error("failed tx should not write")
+		}
+	}
+
+	// 事务中先写入的key可以继续操作
+	err = r.Tx(func(p storage.Pipeliner) error {
+		p.Set("counter", 10, 0)
+		p.Increase("counter")
+		p.Expire("counter", time.Minute)
+		return nil
+	})
+	if v, _ := r.Get("counter"); err != nil || v != "11" || s.TTL("counter") != time.Minute {
+		t.Errorf("counter = %s, err = %v", v, err)
+	}
+}
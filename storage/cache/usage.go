@@ -0,0 +1,280 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// ErrUsageUnsupported 缓存不支持列出key或查看占用
+var ErrUsageUnsupported = errors.New("cache: usage analysis not supported")
+
+// TTL 分布的区间, 按顺序排列
+const (
+	TTLNone   = "none"
+	TTLMinute = "<1m"
+	TTLHour   = "<1h"
+	TTLDay    = "<1d"
+	TTLLong   = ">=1d"
+)
+
+// TTLBuckets 全部 TTL 区间
+var TTLBuckets = []string{TTLNone, TTLMinute, TTLHour, TTLDay, TTLLong}
+
+// KeyInfo key的占用, Bytes 为估算的字节数, TTL 小于0表示未设置过期
+type KeyInfo struct {
+	Key   string
+	Bytes int64
+	TTL   time.Duration
+}
+
+// Inspector 查看key的占用, 已不存在的key不返回
+type Inspector interface {
+	Inspect(ctx context.Context, keys []string) ([]KeyInfo, error)
+}
+
+// Usage 同一前缀的key的数量、占用与 TTL 分布
+type Usage struct {
+	Prefix string         `json:"prefix"`
+	Keys   int            `json:"keys"`
+	Bytes  int64          `json:"bytes"`
+	TTL    map[string]int `json:"ttl"`
+}
+
+// UsageReport 分析结果, Total 为匹配的key数量, Sampled 为实际查看的数量, Prefixes 按占用从大到小排列
+type UsageReport struct {
+	Total    int     `json:"total"`
+	Sampled  int     `json:"sampled"`
+	Prefixes []Usage `json:"prefixes"`
+}
+
+type UsageOption func(*usageOptions)
+
+type usageOptions struct {
+	match     string
+	separator string
+	depth     int
+	sample    int
+}
+
+func setUsageDefault() usageOptions {
+	return usageOptions{
+		separator: ":",
+		depth:     1,
+		sample:    10000,
+	}
+}
+
+// WithUsageMatch 只分析以 prefix 开头的key
+func WithUsageMatch(prefix string) UsageOption {
+	return func(o *usageOptions) {
+		o.match = prefix
+	}
+}
+
+// WithUsageSeparator key的分隔符, 默认 :
+func WithUsageSeparator(sep string) UsageOption {
+	return func(o *usageOptions) {
+		o.separator = sep
+	}
+}
+
+// WithUsageDepth 分组使用的前缀段数, 默认1, 即 user:1:name 归入 user:
+func WithUsageDepth(depth int) UsageOption {
+	return func(o *usageOptions) {
+		o.depth = depth
+	}
+}
+
+// WithUsageSample 最多查看的key数量, 默认10000, 小于等于0时查看全部
+func WithUsageSample(n int) UsageOption {
+	return func(o *usageOptions) {
+		o.sample = n
+	}
+}
+
+// Analyze 按前缀统计缓存的key数量、占用与 TTL 分布; 逐层 Unwrap 找到支持列出key与查看占用的缓存,
+// 前缀为这一层的key, 如 namespace 的环境前缀会包含在内; key 较多时只查看 WithUsageSample 个, Sampled 小于 Total 时结果为抽样
+func Analyze(ctx context.Context, c storage.AdapterCache, opts ...UsageOption) (*UsageReport, error) {
+	o := setUsageDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	kc, in := findInspector(c)
+	if in == nil {
+		return nil, ErrUsageUnsupported
+	}
+	keys, err := kc.Keys(o.match)
+	if err != nil {
+		return nil, err
+	}
+	report := &UsageReport{Total: len(keys), Prefixes: make([]Usage, 0)}
+	if o.sample > 0 && len(keys) > o.sample {
+		keys = keys[:o.sample]
+	}
+	infos, err := in.Inspect(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	report.Sampled = len(infos)
+	groups := make(map[string]*Usage)
+	for _, info := range infos {
+		prefix := usagePrefix(info.Key, o.separator, o.depth)
+		u, ok := groups[prefix]
+		if !ok {
+			u = &Usage{Prefix: prefix, TTL: make(map[string]int)}
+			groups[prefix] = u
+		}
+		u.Keys++
+		u.Bytes += info.Bytes
+		u.TTL[ttlBucket(info.TTL)]++
+	}
+	for _, u := range groups {
+		report.Prefixes = append(report.Prefixes, *u)
+	}
+	sort.Slice(report.Prefixes, func(i, j int) bool {
+		a, b := report.Prefixes[i], report.Prefixes[j]
+		if a.Bytes != b.Bytes {
+			return a.Bytes > b.Bytes
+		}
+		return a.Prefix < b.Prefix
+	})
+	return report, nil
+}
+
+func findInspector(c storage.AdapterCache) (storage.AdapterKeysCache, Inspector) {
+	for c != nil {
+		kc, ok := c.(storage.AdapterKeysCache)
+		if in, ok2 := c.(Inspector); ok && ok2 {
+			return kc, in
+		}
+		u, ok := c.(interface{ Unwrap() storage.AdapterCache })
+		if !ok {
+			return nil, nil
+		}
+		c = u.Unwrap()
+	}
+	return nil, nil
+}
+
+// usagePrefix key的前 depth 段, 包含末尾的分隔符; 段数不足时取到最后一个分隔符, 没有分隔符时为空
+func usagePrefix(key, sep string, depth int) string {
+	if sep == "" || depth <= 0 {
+		return ""
+	}
+	end := 0
+	for i := 0; i < depth; i++ {
+		n := strings.Index(key[end:], sep)
+		if n < 0 {
+			break
+		}
+		end += n + len(sep)
+	}
+	return key[:end]
+}
+
+func ttlBucket(ttl time.Duration) string {
+	switch {
+	case ttl < 0:
+		return TTLNone
+	case ttl < time.Minute:
+		return TTLMinute
+	case ttl < time.Hour:
+		return TTLHour
+	case ttl < 24*time.Hour:
+		return TTLDay
+	default:
+		return TTLLong
+	}
+}
+
+// Inspect 占用为key与值的长度之和, hash 为key与全部字段名、值的长度之和, 未设置过期时 TTL 为 -1
+func (m *Memory) Inspect(_ context.Context, keys []string) ([]KeyInfo, error) {
+	now := m.now()
+	list := make([]KeyInfo, 0, len(keys))
+	for _, key := range keys {
+		item, err := m.readItem(key)
+		if err != nil || item == nil {
+			continue
+		}
+		info := KeyInfo{Key: key, Bytes: int64(len(key) + len(item.Value)), TTL: -1}
+		for k, v := range item.Fields {
+			info.Bytes += int64(len(k) + len(v))
+		}
+		if !item.Expired.IsZero() {
+			info.TTL = item.Expired.Sub(now)
+		}
+		list = append(list, info)
+	}
+	return list, nil
+}
+
+// inspectBatch 每个 pipeline 查看的key数量
+const inspectBatch = 100
+
+// Inspect 占用使用 MEMORY USAGE, 服务端不支持时退化为key与字符串值的长度之和
+func (r *Redis) Inspect(ctx context.Context, keys []string) ([]KeyInfo, error) {
+	list := make([]KeyInfo, 0, len(keys))
+	for start := 0; start < len(keys); start += inspectBatch {
+		end := start + inspectBatch
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batch, err := r.inspect(ctx, keys[start:end])
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, batch...)
+	}
+	return list, nil
+}
+
+func (r *Redis) inspect(ctx context.Context, keys []string) ([]KeyInfo, error) {
+	usages := make([]*redis.IntCmd, len(keys))
+	ttls := make([]*redis.DurationCmd, len(keys))
+	pipe := r.client.Pipeline()
+	for i, key := range keys {
+		usages[i] = pipe.MemoryUsage(ctx, key)
+		ttls[i] = pipe.PTTL(ctx, key)
+	}
+	// 单个命令的错误在下面逐个处理
+	_, _ = pipe.Exec(ctx)
+
+	list := make([]KeyInfo, 0, len(keys))
+	var fallback []int
+	for i, key := range keys {
+		ttl, err := ttls[i].Result()
+		if err != nil {
+			return nil, err
+		}
+		if ttl == -2 {
+			continue
+		}
+		n, err := usages[i].Result()
+		if err != nil {
+			fallback = append(fallback, len(list))
+		}
+		list = append(list, KeyInfo{Key: key, Bytes: n, TTL: ttl})
+	}
+	if len(fallback) == 0 {
+		return list, nil
+	}
+	lens := make([]*redis.IntCmd, len(fallback))
+	pipe = r.client.Pipeline()
+	for i, idx := range fallback {
+		lens[i] = pipe.StrLen(ctx, list[idx].Key)
+	}
+	_, _ = pipe.Exec(ctx)
+	for i, idx := range fallback {
+		// 非字符串类型返回错误, 只计key的长度
+		n, _ := lens[i].Result()
+		list[idx].Bytes = int64(len(list[idx].Key)) + n
+	}
+	return list, nil
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+func TestAnalyze(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	_ = m.Set("user:1:name", "tom", 30)
+	_ = m.Set("user:2:name", "jerry", 7200)
+	_ = m.Set("dict:0:sys_user_sex", "[]", 3*24*3600)
+	_ = m.Set("version", "1", 600)
+	if _, err := Analyze(ctx, struct{ storage.AdapterCache }{m}); err != ErrUsageUnsupported {
+		t.Errorf("wrapped cache without Unwrap: err = %v", err)
+	}
+	report, err := Analyze(ctx, m)
+	if err != nil || report.Total != 4 || report.Sampled != 4 || len(report.Prefixes) != 3 {
+		t.Fatalf("report = %+v, err = %v", report, err)
+	}
+	user := report.Prefixes[0]
+	if user.Prefix != "user:" || user.Keys != 2 || user.Bytes != int64(len("user:1:nametomuser:2:namejerry")) ||
+		user.TTL[TTLMinute] != 1 || user.TTL[TTLDay] != 1 {
+		t.Errorf("user = %+v", user)
+	}
+
+	report, _ = Analyze(ctx, m, WithUsageMatch("user:"), WithUsageDepth(2), WithUsageSample(1))
+	if report.Total != 2 || report.Sampled != 1 || report.Prefixes[0].Prefix[:5] != "user:" || len(report.Prefixes[0].Prefix) != 7 {
+		t.Errorf("sampled report = %+v", report)
+	}
+}
+
+func TestRedis_Inspect(t *testing.T) {
+	s := miniredis.RunT(t)
+	r, err := NewRedis(nil, &redis.Options{Addr: s.Addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = r.Set("user:1", "tom", 60)
+	r.GetClient().HSet(context.Background(), "perm:1", "k", "v")
+	s.Set("config", "x")
+	// miniredis 不支持 MEMORY USAGE, 占用按长度估算
+	list, err := r.Inspect(context.Background(), []string{"user:1", "perm:1", "config", "missing"})
+	if err != nil || len(list) != 3 {
+		t.Fatalf("list = %+v, err = %v", list, err)
+	}
+	if list[0].Bytes != int64(len("user:1tom")) || list[0].TTL != time.Minute {
+		t.Errorf("user = %+v", list[0])
+	}
+	if list[1].Bytes != int64(len("perm:1")) || list[2].TTL >= 0 || ttlBucket(list[2].TTL) != TTLNone {
+		t.Errorf("list = %+v", list)
+	}
+	report, err := Analyze(context.Background(), r)
+	if err != nil || report.Total != 3 || len(report.Prefixes) != 3 {
+		t.Errorf("report = %+v, err = %v", report, err)
+	}
+}
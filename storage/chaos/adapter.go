@@ -0,0 +1,196 @@
+package chaos
+
+import (
+	"time"
+
+	"github.com/bsm/redislock"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+// Cache 包装缓存, 每次调用注入延迟与错误
+func (i *Injector) Cache(c storage.AdapterCache) storage.AdapterCache {
+	return &chaosCache{i: i, c: c}
+}
+
+// Queue 包装队列, 投递时注入延迟、错误与丢消息, 消费时注入延迟与错误(消费失败按队列自身的规则重试)
+func (i *Injector) Queue(q storage.AdapterQueue) storage.AdapterQueue {
+	return &chaosQueue{i: i, q: q}
+}
+
+// Locker 包装锁, 加锁时注入延迟与错误
+func (i *Injector) Locker(l storage.AdapterLocker) storage.AdapterLocker {
+	return &chaosLocker{i: i, l: l}
+}
+
+type chaosCache struct {
+	i *Injector
+	c storage.AdapterCache
+}
+
+func (c *chaosCache) Unwrap() storage.AdapterCache {
+	return c.c
+}
+
+func (c *chaosCache) String() string {
+	return c.c.String()
+}
+
+func (c *chaosCache) Get(key string) (string, error) {
+	if err := c.i.before(); err != nil {
+		return "", err
+	}
+	return c.c.Get(key)
+}
+
+func (c *chaosCache) Set(key string, val interface{}, expire int) error {
+	if err := c.i.before(); err != nil {
+		return err
+	}
+	return c.c.Set(key, val, expire)
+}
+
+func (c *chaosCache) Del(key string) error {
+	if err := c.i.before(); err != nil {
+		return err
+	}
+	return c.c.Del(key)
+}
+
+func (c *chaosCache) HashGet(hk, key string) (string, error) {
+	if err := c.i.before(); err != nil {
+		return "", err
+	}
+	return c.c.HashGet(hk, key)
+}
+
+func (c *chaosCache) HashDel(hk, key string) error {
+	if err := c.i.before(); err != nil {
+		return err
+	}
+	return c.c.HashDel(hk, key)
+}
+
+func (c *chaosCache) Increase(key string) error {
+	if err := c.i.before(); err != nil {
+		return err
+	}
+	return c.c.Increase(key)
+}
+
+func (c *chaosCache) Decrease(key string) error {
+	if err := c.i.before(); err != nil {
+		return err
+	}
+	return c.c.Decrease(key)
+}
+
+func (c *chaosCache) Expire(key string, dur time.Duration) error {
+	if err := c.i.before(); err != nil {
+		return err
+	}
+	return c.c.Expire(key, dur)
+}
+
+func (c *chaosCache) GetTouch(key string, ttl time.Duration) (string, error) {
+	if err := c.i.before(); err != nil {
+		return "", err
+	}
+	return cache.GetTouch(c.c, key, ttl)
+}
+
+func (c *chaosCache) SetNX(key string, val interface{}, expire int) (bool, error) {
+	if err := c.i.before(); err != nil {
+		return false, err
+	}
+	return cache.SetNX(c.c, key, val, expire)
+}
+
+func (c *chaosCache) CompareAndSwap(key, old string, val interface{}, expire int) (bool, error) {
+	if err := c.i.before(); err != nil {
+		return false, err
+	}
+	return cache.CompareAndSwap(c.c, key, old, val, expire)
+}
+
+func (c *chaosCache) HashSet(hk, key string, val interface{}) error {
+	if err := c.i.before(); err != nil {
+		return err
+	}
+	return cache.HashSet(c.c, hk, key, val)
+}
+
+func (c *chaosCache) HashGetAll(hk string) (map[string]string, error) {
+	if err := c.i.before(); err != nil {
+		return nil, err
+	}
+	return cache.HashGetAll(c.c, hk)
+}
+
+func (c *chaosCache) Keys(prefix string) ([]string, error) {
+	if err := c.i.before(); err != nil {
+		return nil, err
+	}
+	return cache.Keys(c.c, prefix)
+}
+
+type chaosQueue struct {
+	i *Injector
+	q storage.AdapterQueue
+}
+
+func (q *chaosQueue) Unwrap() storage.AdapterQueue {
+	return q.q
+}
+
+func (q *chaosQueue) String() string {
+	return q.q.String()
+}
+
+func (q *chaosQueue) Append(message storage.Messager) error {
+	if err := q.i.before(); err != nil {
+		return err
+	}
+	if q.i.drop() {
+		return nil
+	}
+	return q.q.Append(message)
+}
+
+func (q *chaosQueue) Register(name string, f storage.ConsumerFunc) {
+	q.q.Register(name, func(message storage.Messager) error {
+		if err := q.i.before(); err != nil {
+			return err
+		}
+		return f(message)
+	})
+}
+
+func (q *chaosQueue) Run() {
+	q.q.Run()
+}
+
+func (q *chaosQueue) Shutdown() {
+	q.q.Shutdown()
+}
+
+type chaosLocker struct {
+	i *Injector
+	l storage.AdapterLocker
+}
+
+func (l *chaosLocker) Unwrap() storage.AdapterLocker {
+	return l.l
+}
+
+func (l *chaosLocker) String() string {
+	return l.l.String()
+}
+
+func (l *chaosLocker) Lock(key string, ttl int64, options *redislock.Options) (*redislock.Lock, error) {
+	if err := l.i.before(); err != nil {
+		return nil, err
+	}
+	return l.l.Lock(key, ttl, options)
+}
@@ -0,0 +1,143 @@
+// Package chaos 为缓存、队列、锁适配器注入延迟、错误与丢消息, 用于下游项目的容错测试, 不修改正式代码路径
+//
+//	inj := chaos.New(chaos.WithLatency(50*time.Millisecond, 20*time.Millisecond), chaos.WithErrorRate(0.1))
+//	cache := inj.Cache(cache.NewMemory())
+//	inj.Disable() // 某一阶段关闭注入
+package chaos
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrInjected 注入的默认错误
+var ErrInjected = errors.New("chaos: injected error")
+
+type Option func(*options)
+
+type options struct {
+	latency   time.Duration
+	jitter    time.Duration
+	errorRate float64
+	dropRate  float64
+	err       error
+	seed      int64
+}
+
+func setDefault() options {
+	return options{
+		err:  ErrInjected,
+		seed: time.Now().UnixNano(),
+	}
+}
+
+// WithLatency 每次调用前等待 latency, 再加上 [0, jitter) 的随机时间
+func WithLatency(latency, jitter time.Duration) Option {
+	return func(o *options) {
+		o.latency = latency
+		o.jitter = jitter
+	}
+}
+
+// WithErrorRate 调用返回错误的概率, 0~1
+func WithErrorRate(rate float64) Option {
+	return func(o *options) {
+		o.errorRate = rate
+	}
+}
+
+// WithDropRate 队列投递时丢弃消息的概率, 0~1, 丢弃时 Append 仍返回成功
+func WithDropRate(rate float64) Option {
+	return func(o *options) {
+		o.dropRate = rate
+	}
+}
+
+// WithError 注入的错误, 默认 ErrInjected
+func WithError(err error) Option {
+	return func(o *options) {
+		o.err = err
+	}
+}
+
+// WithSeed 随机数种子, 固定后结果可复现
+func WithSeed(seed int64) Option {
+	return func(o *options) {
+		o.seed = seed
+	}
+}
+
+// Injector 注入配置, 同一个 Injector 包装的适配器共享配置与开关
+type Injector struct {
+	o        options
+	mux      sync.Mutex
+	rand     *rand.Rand
+	disabled int32
+}
+
+// New 创建后即启用
+func New(opts ...Option) *Injector {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Injector{o: o, rand: rand.New(rand.NewSource(o.seed))}
+}
+
+// Enable 启用注入
+func (i *Injector) Enable() {
+	atomic.StoreInt32(&i.disabled, 0)
+}
+
+// Disable 关闭注入, 包装的适配器直接调用原实现
+func (i *Injector) Disable() {
+	atomic.StoreInt32(&i.disabled, 1)
+}
+
+// Enabled 是否启用
+func (i *Injector) Enabled() bool {
+	return atomic.LoadInt32(&i.disabled) == 0
+}
+
+// before 调用前等待并按概率返回错误
+func (i *Injector) before() error {
+	if !i.Enabled() {
+		return nil
+	}
+	if d := i.delay(); d > 0 {
+		time.Sleep(d)
+	}
+	if i.hit(i.o.errorRate) {
+		return i.o.err
+	}
+	return nil
+}
+
+func (i *Injector) drop() bool {
+	return i.Enabled() && i.hit(i.o.dropRate)
+}
+
+func (i *Injector) delay() time.Duration {
+	d := i.o.latency
+	if i.o.jitter > 0 {
+		i.mux.Lock()
+		d += time.Duration(i.rand.Int63n(int64(i.o.jitter)))
+		i.mux.Unlock()
+	}
+	return d
+}
+
+func (i *Injector) hit(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	if rate >= 1 {
+		return true
+	}
+	i.mux.Lock()
+	defer i.mux.Unlock()
+	return i.rand.Float64() < rate
+}
@@ -0,0 +1,70 @@
+package chaos
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+func TestCache(t *testing.T) {
+	inj := New(WithErrorRate(1), WithLatency(10*time.Millisecond, 0))
+	c := inj.Cache(cache.NewMemory())
+	start := time.Now()
+	if err := c.Set("a", "1", 60); !errors.Is(err, ErrInjected) {
+		t.Errorf("err = %v", err)
+	}
+	if time.Since(start) < 10*time.Millisecond {
+		t.Error("latency not injected")
+	}
+	inj.Disable()
+	_ = c.Set("a", "1", 60)
+	if v, err := c.Get("a"); err != nil || v != "1" {
+		t.Errorf("disabled: v = %s, err = %v", v, err)
+	}
+
+	half := New(WithErrorRate(0.5), WithSeed(1)).Cache(cache.NewMemory())
+	var failed int
+	for n := 0; n < 200; n++ {
+		if half.Set("a", "1", 60) != nil {
+			failed++
+		}
+	}
+	if failed < 60 || failed > 140 {
+		t.Errorf("failed = %d of 200", failed)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	inj := New(WithDropRate(1))
+	q := inj.Queue(queue.NewMemory(10))
+	received := make(chan string, 10)
+	q.Register("chaos", func(m storage.Messager) error {
+		received <- m.GetValues()["n"].(string)
+		return nil
+	})
+	go q.Run()
+	defer q.Shutdown()
+	send := func(n string) {
+		m := new(queue.Message)
+		m.SetStream("chaos")
+		m.SetValues(map[string]interface{}{"n": n})
+		if err := q.Append(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	send("dropped")
+	inj.Disable()
+	send("kept")
+	select {
+	case n := <-received:
+		if n != "kept" {
+			t.Errorf("received %s", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+}
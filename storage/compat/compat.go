@@ -0,0 +1,105 @@
+// Package compat Redis 协议兼容服务(Dragonfly、Valkey、KeyDB)的识别与功能探测:
+// 通过 INFO 识别服务, 并实际执行一次 stream、消费组与脚本命令判断是否支持, 不支持的功能由调用方降级
+//
+//	f, err := compat.Detect(ctx, client)
+//	if !f.Queue() {
+//		q = queue.NewMemory(0) // 不支持消费组, 退回内存队列
+//	}
+package compat
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+)
+
+// Server 服务类型
+type Server string
+
+const (
+	Unknown   Server = "unknown"
+	Redis     Server = "redis"
+	Dragonfly Server = "dragonfly"
+	Valkey    Server = "valkey"
+	KeyDB     Server = "keydb"
+)
+
+// Features 探测到的服务与功能
+type Features struct {
+	Server  Server `json:"server"`
+	Version string `json:"version"`
+	// Streams XADD、XRANGE
+	Streams bool `json:"streams"`
+	// ConsumerGroups XGROUP、XREADGROUP、XACK, redis 队列需要
+	ConsumerGroups bool `json:"consumerGroups"`
+	// PendingIdle XPENDING 的 IDLE 参数(redis 6.2+), queue.Reaper 需要
+	PendingIdle bool `json:"pendingIdle"`
+	// Scripting EVAL, redis 锁需要
+	Scripting bool `json:"scripting"`
+}
+
+// Queue 是否可以使用 redis 队列
+func (f Features) Queue() bool {
+	return f.Streams && f.ConsumerGroups
+}
+
+// Locker 是否可以使用 redis 锁
+func (f Features) Locker() bool {
+	return f.Scripting
+}
+
+// probeKey 探测使用的key, 结束后删除
+func probeKey() string {
+	return "__compat:probe:" + strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+// Detect 识别服务并探测功能; 只有连接失败时返回错误, 单个命令不支持时对应功能为 false
+func Detect(ctx context.Context, client redis.UniversalClient) (Features, error) {
+	if err := client.Ping(ctx).Err(); err != nil {
+		return Features{}, err
+	}
+	f := Features{Server: Unknown}
+	if info, err := client.Info(ctx).Result(); err == nil {
+		f.Server, f.Version = ParseInfo(info)
+	}
+	key := probeKey()
+	defer client.Del(ctx, key)
+	f.Streams = client.XAdd(ctx, &redis.XAddArgs{Stream: key, Values: map[string]interface{}{"probe": "1"}}).Err() == nil
+	if f.Streams {
+		f.ConsumerGroups = client.XGroupCreate(ctx, key, "probe", "0").Err() == nil &&
+			client.XReadGroup(ctx, &redis.XReadGroupArgs{Group: "probe", Consumer: "probe", Streams: []string{key, ">"}, Count: 1, Block: -1}).Err() == nil
+	}
+	if f.ConsumerGroups {
+		err := client.XPendingExt(ctx, &redis.XPendingExtArgs{Stream: key, Group: "probe", Idle: time.Millisecond, Start: "-", End: "+", Count: 1}).Err()
+		f.PendingIdle = err == nil || err == redis.Nil
+	}
+	v, err := client.Eval(ctx, "return 1", nil).Int()
+	f.Scripting = err == nil && v == 1
+	return f, nil
+}
+
+// ParseInfo 从 INFO 的输出识别服务类型与版本
+func ParseInfo(info string) (Server, string) {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(info, "\n") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(line), ":"); ok {
+			fields[k] = v
+		}
+	}
+	switch {
+	case fields["dragonfly_version"] != "":
+		return Dragonfly, fields["dragonfly_version"]
+	case fields["valkey_version"] != "":
+		return Valkey, fields["valkey_version"]
+	case fields["server_name"] == "valkey":
+		return Valkey, fields["redis_version"]
+	case strings.Contains(strings.ToLower(fields["executable"]), "keydb"):
+		return KeyDB, fields["redis_version"]
+	case fields["redis_version"] != "":
+		return Redis, fields["redis_version"]
+	}
+	return Unknown, ""
+}
@@ -0,0 +1,66 @@
+package compat
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v9"
+)
+
+func TestParseInfo(t *testing.T) {
+	for _, c := range []struct {
+		info    string
+		server  Server
+		version string
+	}{
+		{"# Server\r\nredis_version:7.0.5\r\nexecutable:/usr/bin/redis-server\r\n", Redis, "7.0.5"},
+		{"# Server\r\nredis_version:6.2.11\r\ndragonfly_version:df-v1.4.0\r\n", Dragonfly, "df-v1.4.0"},
+		{"# Server\r\nredis_version:7.2.4\r\nserver_name:valkey\r\nvalkey_version:8.0.1\r\n", Valkey, "8.0.1"},
+		{"# Server\r\nredis_version:6.3.4\r\nexecutable:/usr/local/bin/keydb-server\r\n", KeyDB, "6.3.4"},
+		{"# Clients\r\nconnected_clients:1\r\n", Unknown, ""},
+	} {
+		if server, version := ParseInfo(c.info); server != c.server || version != c.version {
+			t.Errorf("ParseInfo() = %s %s, want %s %s", server, version, c.server, c.version)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer client.Close()
+	f, err := Detect(context.Background(), client)
+	if err != nil || !f.Queue() || !f.Locker() || !f.PendingIdle {
+		t.Errorf("features = %+v, err = %v", f, err)
+	}
+	if keys := s.Keys(); len(keys) != 0 {
+		t.Errorf("probe keys left: %v", keys)
+	}
+}
+
+// TestMatrix 对兼容服务运行探测, 通过环境变量指定地址, 如
+// REDIS_COMPAT_ADDRS=redis=127.0.0.1:6379,dragonfly=127.0.0.1:6380,valkey=127.0.0.1:6381,keydb=127.0.0.1:6382
+func TestMatrix(t *testing.T) {
+	addrs := os.Getenv("REDIS_COMPAT_ADDRS")
+	if addrs == "" {
+		t.Skip("REDIS_COMPAT_ADDRS not set")
+	}
+	for _, pair := range strings.Split(addrs, ",") {
+		name, addr, _ := strings.Cut(pair, "=")
+		t.Run(name, func(t *testing.T) {
+			client := redis.NewClient(&redis.Options{Addr: addr})
+			defer client.Close()
+			f, err := Detect(context.Background(), client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(f.Server) != name {
+				t.Errorf("server = %s", f.Server)
+			}
+			t.Logf("%+v", f)
+		})
+	}
+}
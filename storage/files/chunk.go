@@ -0,0 +1,151 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidUpload   = errors.New("files: invalid upload id")
+	ErrChunkIncomplete = errors.New("files: upload chunks incomplete")
+)
+
+var uploadIDRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// Chunker 分片上传, 分片先保存在本地临时目录, 全部上传后合并写入 Provider,
+// 与具体存储无关; 多实例部署时 dir 需为共享目录
+type Chunker struct {
+	dir      string
+	provider Provider
+}
+
+// NewChunker dir 为分片临时目录
+func NewChunker(p Provider, dir string) (*Chunker, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	return &Chunker{dir: dir, provider: p}, nil
+}
+
+func (c *Chunker) uploadDir(uploadID string) (string, error) {
+	if !uploadIDRegexp.MatchString(uploadID) {
+		return "", ErrInvalidUpload
+	}
+	return filepath.Join(c.dir, uploadID), nil
+}
+
+// WriteChunk 保存第 index 个分片(从0开始), 重复上传会覆盖
+func (c *Chunker) WriteChunk(uploadID string, index int, r io.Reader) (int64, error) {
+	dir, err := c.uploadDir(uploadID)
+	if err != nil {
+		return 0, err
+	}
+	if index < 0 {
+		return 0, fmt.Errorf("files: invalid chunk index %d", index)
+	}
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return 0, err
+	}
+	tmp, err := os.CreateTemp(dir, ".chunk-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	n, err := io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, err
+	}
+	return n, os.Rename(tmp.Name(), filepath.Join(dir, strconv.Itoa(index)))
+}
+
+// Uploaded 已上传的分片序号, 用于断点续传
+func (c *Chunker) Uploaded(uploadID string) ([]int, error) {
+	dir, err := c.uploadDir(uploadID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []int{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	list := make([]int, 0, len(entries))
+	for _, e := range entries {
+		if i, err := strconv.Atoi(e.Name()); err == nil {
+			list = append(list, i)
+		}
+	}
+	sort.Ints(list)
+	return list, nil
+}
+
+// Complete 按顺序合并 total 个分片写入 key, 成功后删除分片
+func (c *Chunker) Complete(ctx context.Context, uploadID string, total int, key string, opts ...PutOption) (*Object, error) {
+	dir, err := c.uploadDir(uploadID)
+	if err != nil {
+		return nil, err
+	}
+	var size int64
+	readers := make([]io.Reader, 0, total)
+	for i := 0; i < total; i++ {
+		f, err := os.Open(filepath.Join(dir, strconv.Itoa(i)))
+		if os.IsNotExist(err) {
+			return nil, ErrChunkIncomplete
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		size += info.Size()
+		readers = append(readers, f)
+	}
+	o, err := c.provider.Put(ctx, key, io.MultiReader(readers...), size, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return o, os.RemoveAll(dir)
+}
+
+// Abort 取消上传并删除分片
+func (c *Chunker) Abort(uploadID string) error {
+	dir, err := c.uploadDir(uploadID)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// Clean 删除超过 ttl 未更新的上传, 可由定时任务调用
+func (c *Chunker) Clean(ttl time.Duration) error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || !e.IsDir() || time.Since(info.ModTime()) < ttl {
+			continue
+		}
+		if err = os.RemoveAll(filepath.Join(c.dir, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// Package files 文件存储, 提供本地磁盘、S3兼容(含MinIO)、阿里云OSS、腾讯云COS实现,
+// 以及分片上传与上传策略
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+var (
+	ErrNotFound   = errors.New("files: object not found")
+	ErrInvalidKey = errors.New("files: invalid object key")
+)
+
+// Object 文件信息
+type Object struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"contentType,omitempty"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// Provider 文件存储
+type Provider interface {
+	String() string
+	// Put 写入文件, size 未知时传 -1
+	Put(ctx context.Context, key string, r io.Reader, size int64, opts ...PutOption) (*Object, error)
+	// Get 读取文件, 调用方负责关闭
+	Get(ctx context.Context, key string) (io.ReadCloser, *Object, error)
+	Delete(ctx context.Context, key string) error
+	// Presign 生成有效期为 expire 的临时访问地址, method 为 GET 或 PUT
+	Presign(ctx context.Context, key, method string, expire time.Duration) (string, error)
+	// List 列出前缀为 prefix 的文件, limit 为0时使用存储的默认值
+	List(ctx context.Context, prefix string, limit int) ([]Object, error)
+}
+
+type PutOption func(*putOptions)
+
+type putOptions struct {
+	contentType string
+}
+
+// WithContentType 文件类型, 默认 application/octet-stream
+func WithContentType(contentType string) PutOption {
+	return func(o *putOptions) {
+		o.contentType = contentType
+	}
+}
+
+func putDefault(opts []PutOption) putOptions {
+	o := putOptions{contentType: "application/octet-stream"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// CleanKey 规范化文件key, 去掉开头的 /, 拒绝 .. 等越界路径
+func CleanKey(key string) (string, error) {
+	if key == "" || strings.ContainsRune(key, 0) {
+		return "", ErrInvalidKey
+	}
+	cleaned := strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(key, "\\", "/")), "/")
+	if cleaned == "" || cleaned != strings.TrimPrefix(strings.ReplaceAll(key, "\\", "/"), "/") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
+
+// Factory 根据配置构造文件存储
+type Factory func(options map[string]interface{}) (Provider, error)
+
+var (
+	mux       sync.RWMutex
+	factories = map[string]Factory{
+		"local": func(options map[string]interface{}) (Provider, error) {
+			var c LocalConfig
+			if err := storage.DecodeOptions(options, &c); err != nil {
+				return nil, err
+			}
+			return NewLocal(c)
+		},
+		"s3": func(options map[string]interface{}) (Provider, error) {
+			var c S3Config
+			if err := storage.DecodeOptions(options, &c); err != nil {
+				return nil, err
+			}
+			return NewS3(c)
+		},
+		"minio": func(options map[string]interface{}) (Provider, error) {
+			c := S3Config{Region: "us-east-1", PathStyle: true}
+			if err := storage.DecodeOptions(options, &c); err != nil {
+				return nil, err
+			}
+			return NewS3(c)
+		},
+		"oss": func(options map[string]interface{}) (Provider, error) {
+			var c OSSConfig
+			if err := storage.DecodeOptions(options, &c); err != nil {
+				return nil, err
+			}
+			return NewOSS(c)
+		},
+		"cos": func(options map[string]interface{}) (Provider, error) {
+			var c COSConfig
+			if err := storage.DecodeOptions(options, &c); err != nil {
+				return nil, err
+			}
+			return NewCOS(c)
+		},
+	}
+)
+
+// Register 注册文件存储驱动, 重复注册会覆盖之前的实现
+func Register(driver string, f Factory) {
+	mux.Lock()
+	defer mux.Unlock()
+	factories[driver] = f
+}
+
+// New 根据驱动名称构造文件存储, 内置 local s3 minio oss cos
+func New(driver string, options map[string]interface{}) (Provider, error) {
+	mux.RLock()
+	f, ok := factories[driver]
+	mux.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("files driver %q not registered", driver)
+	}
+	return f(options)
+}
+
+// Drivers 已注册的文件存储驱动
+func Drivers() []string {
+	mux.RLock()
+	defer mux.RUnlock()
+	drivers := make([]string, 0, len(factories))
+	for k := range factories {
+		drivers = append(drivers, k)
+	}
+	sort.Strings(drivers)
+	return drivers
+}
@@ -0,0 +1,232 @@
+package files
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalConfig 本地磁盘存储配置
+type LocalConfig struct {
+	// Root 文件保存目录
+	Root string `json:"root"`
+	// BaseURL 访问地址前缀, 如 https://example.com/static/files
+	BaseURL string `json:"baseUrl"`
+	// Secret 临时访问地址的签名密钥, 为空时 Presign 返回不带签名的地址
+	Secret string `json:"secret"`
+}
+
+// Local 本地磁盘存储
+type Local struct {
+	root    string
+	baseURL string
+	secret  []byte
+}
+
+// NewLocal 本地磁盘存储, Root 不存在时自动创建
+func NewLocal(c LocalConfig) (*Local, error) {
+	if c.Root == "" {
+		return nil, errors.New("files: local root is required")
+	}
+	if err := os.MkdirAll(c.Root, os.ModePerm); err != nil {
+		return nil, err
+	}
+	return &Local{
+		root:    c.Root,
+		baseURL: strings.TrimSuffix(c.BaseURL, "/"),
+		secret:  []byte(c.Secret),
+	}, nil
+}
+
+func (*Local) String() string {
+	return "local"
+}
+
+func (l *Local) path(key string) (string, string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	return key, filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+// Put 先写入临时文件再重命名, 读取方不会看到写了一半的文件
+func (l *Local) Put(_ context.Context, key string, r io.Reader, _ int64, opts ...PutOption) (*Object, error) {
+	key, name, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	n, err := io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = os.Rename(tmp.Name(), name); err != nil {
+		return nil, err
+	}
+	o := putDefault(opts)
+	return &Object{Key: key, Size: n, ContentType: o.contentType, LastModified: time.Now()}, nil
+}
+
+func (l *Local) Get(_ context.Context, key string) (io.ReadCloser, *Object, error) {
+	key, name, err := l.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, l.object(key, info), nil
+}
+
+func (l *Local) Delete(_ context.Context, key string) error {
+	_, name, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Presign 本地存储只支持 GET, 地址由 Handler 校验
+func (l *Local) Presign(_ context.Context, key, method string, expire time.Duration) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	if method != http.MethodGet {
+		return "", errors.New("files: local presign only supports GET")
+	}
+	u := l.baseURL + "/" + (&url.URL{Path: key}).EscapedPath()
+	if len(l.secret) == 0 {
+		return u, nil
+	}
+	expires := strconv.FormatInt(time.Now().Add(expire).Unix(), 10)
+	return u + "?expires=" + expires + "&signature=" + l.signature(key, expires), nil
+}
+
+func (l *Local) signature(key, expires string) string {
+	h := hmac.New(sha256.New, l.secret)
+	h.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify 校验 Presign 生成的签名
+func (l *Local) Verify(key, expires, signature string) bool {
+	if len(l.secret) == 0 {
+		return true
+	}
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return false
+	}
+	return hmac.Equal([]byte(l.signature(key, expires)), []byte(signature))
+}
+
+// Handler 提供文件下载, 挂载在 BaseURL 对应的路径下, 如
+// http.StripPrefix("/static/files", l.Handler()); 设置了 Secret 时校验签名
+func (l *Local) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, name, err := l.path(r.URL.Path)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		if !l.Verify(key, q.Get("expires"), q.Get("signature")) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		// 只提供普通文件, 目录不列出内容, 上传中的临时文件不可访问
+		f, err := os.Open(name)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil || !info.Mode().IsRegular() || strings.HasPrefix(info.Name(), ".upload-") {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+	})
+}
+
+var errStopWalk = errors.New("stop walk")
+
+func (l *Local) List(_ context.Context, prefix string, limit int) ([]Object, error) {
+	list := make([]Object, 0)
+	err := filepath.WalkDir(l.root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(l.root, name)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		list = append(list, *l.object(key, info))
+		if limit > 0 && len(list) >= limit {
+			return errStopWalk
+		}
+		return nil
+	})
+	if err != nil && err != errStopWalk {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (l *Local) object(key string, info fs.FileInfo) *Object {
+	return &Object{
+		Key:          key,
+		Size:         info.Size(),
+		ContentType:  mime.TypeByExtension(path.Ext(key)),
+		LastModified: info.ModTime(),
+	}
+}
@@ -0,0 +1,25 @@
+package files
+
+import (
+	"net/http"
+	"time"
+)
+
+type Option func(*options)
+
+type options struct {
+	client *http.Client
+}
+
+func setDefault() options {
+	return options{
+		client: &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// WithHTTPClient 对象存储使用的 http.Client, 默认超时5分钟
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) {
+		o.client = c
+	}
+}
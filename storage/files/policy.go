@@ -0,0 +1,114 @@
+package files
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+)
+
+var (
+	ErrTooLarge       = errors.New("files: file too large")
+	ErrTypeNotAllowed = errors.New("files: file type not allowed")
+)
+
+// Policy 上传策略, 零值为不限制
+type Policy struct {
+	// MaxSize 最大字节数
+	MaxSize int64
+	// AllowedTypes 允许的文件类型, 支持 image/* 形式的通配
+	AllowedTypes []string
+	// AllowedExts 允许的扩展名, 如 .png, 不区分大小写
+	AllowedExts []string
+}
+
+// Check 按文件内容识别类型并校验, 返回的 reader 包含已读取的文件头;
+// size 未知时传 -1, 读取超过 MaxSize 时返回 ErrTooLarge
+func (p Policy) Check(name string, size int64, r io.Reader) (io.Reader, string, error) {
+	if p.MaxSize > 0 && size > p.MaxSize {
+		return nil, "", ErrTooLarge
+	}
+	ext := strings.ToLower(path.Ext(name))
+	if len(p.AllowedExts) > 0 && !containsFold(p.AllowedExts, ext) {
+		return nil, "", ErrTypeNotAllowed
+	}
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, "", err
+	}
+	head = head[:n]
+	contentType := DetectContentType(name, head)
+	if len(p.AllowedTypes) > 0 && !matchType(p.AllowedTypes, contentType) {
+		return nil, "", ErrTypeNotAllowed
+	}
+	r = io.MultiReader(bytes.NewReader(head), r)
+	if p.MaxSize > 0 && size < 0 {
+		r = &maxReader{r: r, n: p.MaxSize}
+	}
+	return r, contentType, nil
+}
+
+// DetectContentType 按文件内容识别类型, 无法识别时按扩展名
+func DetectContentType(name string, head []byte) string {
+	contentType := http.DetectContentType(head)
+	if contentType == "application/octet-stream" || strings.HasPrefix(contentType, "text/plain") {
+		if t := mime.TypeByExtension(path.Ext(name)); t != "" {
+			return t
+		}
+	}
+	return contentType
+}
+
+// Upload 按策略校验 multipart 文件并保存到 key
+func Upload(ctx context.Context, p Provider, key string, fh *multipart.FileHeader, policy Policy) (*Object, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r, contentType, err := policy.Check(fh.Filename, fh.Size, f)
+	if err != nil {
+		return nil, err
+	}
+	return p.Put(ctx, key, r, fh.Size, WithContentType(contentType))
+}
+
+func matchType(allowed []string, contentType string) bool {
+	contentType, _, _ = mime.ParseMediaType(contentType)
+	for _, t := range allowed {
+		if t == contentType || strings.HasSuffix(t, "/*") && strings.HasPrefix(contentType, t[:len(t)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxReader 读取超过 n 字节时返回 ErrTooLarge
+type maxReader struct {
+	r io.Reader
+	n int64
+}
+
+func (m *maxReader) Read(p []byte) (int, error) {
+	n, err := m.r.Read(p)
+	m.n -= int64(n)
+	if m.n < 0 {
+		return n, ErrTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,250 @@
+package files
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// signer 对象存储的请求签名
+type signer interface {
+	// sign 以请求头方式签名
+	sign(r *http.Request, now time.Time)
+	// presign 以查询参数方式签名, 用于临时访问地址
+	presign(r *http.Request, now time.Time, expire time.Duration)
+}
+
+// remote 基于 http 的对象存储, S3/OSS/COS 的接口形式一致, 只有地址与签名不同
+type remote struct {
+	name   string
+	client *http.Client
+	signer signer
+	// url 返回 key 对应的地址, key 为空时为 bucket 地址
+	url func(key string) *url.URL
+	now func() time.Time
+}
+
+func (e *remote) String() string {
+	return e.name
+}
+
+func (e *remote) request(ctx context.Context, method, key string, query url.Values, body io.Reader) (*http.Request, error) {
+	if key != "" {
+		var err error
+		if key, err = CleanKey(key); err != nil {
+			return nil, err
+		}
+	}
+	u := e.url(key)
+	if len(query) > 0 {
+		u.RawQuery = encodeQuery(query)
+	}
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+func (e *remote) do(r *http.Request, op string) (*http.Response, error) {
+	e.signer.sign(r, e.now())
+	rsp, err := e.client.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode < 300 {
+		return rsp, nil
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode == http.StatusNotFound && r.Method != http.MethodDelete {
+		return nil, ErrNotFound
+	}
+	b, _ := io.ReadAll(io.LimitReader(rsp.Body, 1<<10))
+	var res struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if xml.Unmarshal(b, &res) == nil && res.Code != "" {
+		return nil, fmt.Errorf("files: %s %s: %s: %s", e.name, op, res.Code, res.Message)
+	}
+	return nil, fmt.Errorf("files: %s %s: %s", e.name, op, rsp.Status)
+}
+
+func (e *remote) Put(ctx context.Context, key string, r io.Reader, size int64, opts ...PutOption) (*Object, error) {
+	if size < 0 {
+		// 对象存储需要 Content-Length, 长度未知时先读入内存
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		r, size = bytes.NewReader(b), int64(len(b))
+	}
+	req, err := e.request(ctx, http.MethodPut, key, nil, r)
+	if err != nil {
+		return nil, err
+	}
+	o := putDefault(opts)
+	req.ContentLength = size
+	req.Header.Set("Content-Type", o.contentType)
+	rsp, err := e.do(req, "put")
+	if err != nil {
+		return nil, err
+	}
+	rsp.Body.Close()
+	key, _ = CleanKey(key)
+	return &Object{
+		Key:          key,
+		Size:         size,
+		ContentType:  o.contentType,
+		ETag:         strings.Trim(rsp.Header.Get("ETag"), `"`),
+		LastModified: e.now(),
+	}, nil
+}
+
+func (e *remote) Get(ctx context.Context, key string) (io.ReadCloser, *Object, error) {
+	req, err := e.request(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	rsp, err := e.do(req, "get")
+	if err != nil {
+		return nil, nil, err
+	}
+	key, _ = CleanKey(key)
+	o := &Object{
+		Key:         key,
+		Size:        rsp.ContentLength,
+		ContentType: rsp.Header.Get("Content-Type"),
+		ETag:        strings.Trim(rsp.Header.Get("ETag"), `"`),
+	}
+	o.LastModified, _ = http.ParseTime(rsp.Header.Get("Last-Modified"))
+	return rsp.Body, o, nil
+}
+
+func (e *remote) Delete(ctx context.Context, key string) error {
+	req, err := e.request(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	rsp, err := e.do(req, "delete")
+	if err != nil {
+		return err
+	}
+	return rsp.Body.Close()
+}
+
+func (e *remote) Presign(ctx context.Context, key, method string, expire time.Duration) (string, error) {
+	req, err := e.request(ctx, method, key, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	e.signer.presign(req, e.now(), expire)
+	return req.URL.String(), nil
+}
+
+func (e *remote) List(ctx context.Context, prefix string, limit int) ([]Object, error) {
+	query := url.Values{}
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	if limit > 0 {
+		query.Set("max-keys", strconv.Itoa(limit))
+	}
+	req, err := e.request(ctx, http.MethodGet, "", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := e.do(req, "list")
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	var res struct {
+		Contents []struct {
+			Key          string    `xml:"Key"`
+			LastModified time.Time `xml:"LastModified"`
+			ETag         string    `xml:"ETag"`
+			Size         int64     `xml:"Size"`
+		} `xml:"Contents"`
+	}
+	if err = xml.NewDecoder(rsp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	list := make([]Object, 0, len(res.Contents))
+	for _, c := range res.Contents {
+		list = append(list, Object{
+			Key:          c.Key,
+			Size:         c.Size,
+			ETag:         strings.Trim(c.ETag, `"`),
+			LastModified: c.LastModified,
+		})
+	}
+	return list, nil
+}
+
+// endpoint 解析服务地址, 未指定协议时使用 https
+func endpoint(s string) (*url.URL, error) {
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("files: invalid endpoint %q", s)
+	}
+	return u, nil
+}
+
+// objectURL bucket 在域名中时的对象地址
+func objectURL(base *url.URL, host, key string) *url.URL {
+	return &url.URL{
+		Scheme:  base.Scheme,
+		Host:    host,
+		Path:    "/" + key,
+		RawPath: "/" + uriEncode(key, false),
+	}
+}
+
+// encodeQuery 按 key 排序并以 RFC 3986 编码, 空格编码为 %20 而不是 +
+func encodeQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		vs := append([]string(nil), query[k]...)
+		sort.Strings(vs)
+		for _, v := range vs {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(uriEncode(k, true))
+			b.WriteByte('=')
+			b.WriteString(uriEncode(v, true))
+		}
+	}
+	return b.String()
+}
+
+// uriEncode RFC 3986 编码, 只保留非保留字符, encodeSlash 为 false 时保留 /
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' && !encodeSlash {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
@@ -0,0 +1,138 @@
+package files
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Config S3 兼容存储配置, MinIO 等自建服务需开启 PathStyle
+type S3Config struct {
+	// Endpoint 服务地址, 如 https://s3.us-east-1.amazonaws.com, http://127.0.0.1:9000
+	Endpoint  string `json:"endpoint"`
+	Region    string `json:"region"`
+	Bucket    string `json:"bucket"`
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+	// PathStyle 使用 endpoint/bucket/key 形式的地址, 否则为 bucket.endpoint/key
+	PathStyle bool `json:"pathStyle"`
+}
+
+// NewS3 S3 兼容存储, 使用 AWS Signature V4 签名
+func NewS3(c S3Config, opts ...Option) (Provider, error) {
+	if c.Endpoint == "" || c.Bucket == "" {
+		return nil, errors.New("files: s3 endpoint and bucket are required")
+	}
+	base, err := endpoint(c.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if c.Region == "" {
+		c.Region = "us-east-1"
+	}
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	e := &remote{
+		name:   "s3",
+		client: o.client,
+		signer: &s3Signer{region: c.Region, accessKey: c.AccessKey, secretKey: c.SecretKey},
+		now:    time.Now,
+	}
+	if c.PathStyle {
+		e.url = func(key string) *url.URL {
+			p := "/" + c.Bucket + "/" + key
+			return &url.URL{Scheme: base.Scheme, Host: base.Host, Path: p, RawPath: uriEncode(p, false)}
+		}
+	} else {
+		e.url = func(key string) *url.URL {
+			return objectURL(base, c.Bucket+"."+base.Host, key)
+		}
+	}
+	return e, nil
+}
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3TimeFormat      = "20060102T150405Z"
+)
+
+type s3Signer struct {
+	region    string
+	accessKey string
+	secretKey string
+}
+
+func (s *s3Signer) sign(r *http.Request, now time.Time) {
+	now = now.UTC()
+	r.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	r.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if r.Header.Get("Content-Type") != "" {
+		headers = []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+	}
+	signature := s.signature(r, now, headers)
+	r.Header.Set("Authorization", s3Algorithm+" Credential="+s.accessKey+"/"+s.scope(now)+
+		", SignedHeaders="+strings.Join(headers, ";")+", Signature="+signature)
+}
+
+func (s *s3Signer) presign(r *http.Request, now time.Time, expire time.Duration) {
+	now = now.UTC()
+	q := r.URL.Query()
+	q.Set("X-Amz-Algorithm", s3Algorithm)
+	q.Set("X-Amz-Credential", s.accessKey+"/"+s.scope(now))
+	q.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	q.Set("X-Amz-Expires", strconv.Itoa(int(expire/time.Second)))
+	q.Set("X-Amz-SignedHeaders", "host")
+	r.URL.RawQuery = encodeQuery(q)
+	q.Set("X-Amz-Signature", s.signature(r, now, []string{"host"}))
+	r.URL.RawQuery = encodeQuery(q)
+}
+
+func (s *s3Signer) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+// signature 按 Signature V4 计算签名, headers 为小写且已排序的签名头
+func (s *s3Signer) signature(r *http.Request, now time.Time, headers []string) string {
+	var canonical strings.Builder
+	canonical.WriteString(r.Method + "\n")
+	canonical.WriteString(r.URL.EscapedPath() + "\n")
+	canonical.WriteString(encodeQuery(r.URL.Query()) + "\n")
+	for _, h := range headers {
+		v := r.Header.Get(h)
+		if h == "host" {
+			v = r.URL.Host
+		}
+		canonical.WriteString(h + ":" + strings.TrimSpace(v) + "\n")
+	}
+	canonical.WriteString("\n" + strings.Join(headers, ";") + "\n")
+	payload := r.Header.Get("X-Amz-Content-Sha256")
+	if payload == "" {
+		payload = s3UnsignedPayload
+	}
+	canonical.WriteString(payload)
+
+	hash := sha256.Sum256([]byte(canonical.String()))
+	toSign := s3Algorithm + "\n" + now.Format(s3TimeFormat) + "\n" + s.scope(now) + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, toSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,116 @@
+// Package lockertest 分布式锁适配器的一致性测试, 第三方适配器在自己的测试中调用, 验证与内置实现的行为一致
+//
+//	func TestAdapter(t *testing.T) {
+//		lockertest.RunAdapterTests(t, func(t *testing.T) storage.AdapterLocker {
+//			return mylocker.New(...)
+//		})
+//	}
+package lockertest
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bsm/redislock"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// Factory 为每个子测试创建适配器, 不同子测试使用不同的key, 可共享同一个后端
+type Factory func(t *testing.T) storage.AdapterLocker
+
+// RunAdapterTests 执行全部一致性测试, 过期相关的测试需要等待约1.5秒;
+// 加锁失败应返回 redislock.ErrNotObtained, 返回的 *redislock.Lock 为 nil 时跳过释放相关的检查
+func RunAdapterTests(t *testing.T, factory Factory) {
+	prefix := "lockertest:" + strconv.FormatInt(time.Now().UnixNano(), 36) + ":"
+	tests := []struct {
+		name string
+		f    func(t *testing.T, l storage.AdapterLocker, key string)
+	}{
+		{"Exclusive", testExclusive},
+		{"Keys", testKeys},
+		{"Expire", testExpire},
+		{"Release", testRelease},
+		{"Concurrent", testConcurrent},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.f(t, factory(t), prefix+tt.name)
+		})
+	}
+}
+
+func testExclusive(t *testing.T, l storage.AdapterLocker, key string) {
+	if _, err := l.Lock(key, 10, nil); err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	_, err := l.Lock(key, 10, nil)
+	if err == nil {
+		t.Fatal("second Lock on held key should fail")
+	}
+	if !errors.Is(err, redislock.ErrNotObtained) {
+		t.Errorf("second Lock err = %v, want redislock.ErrNotObtained", err)
+	}
+}
+
+func testKeys(t *testing.T, l storage.AdapterLocker, key string) {
+	if _, err := l.Lock(key+":a", 10, nil); err != nil {
+		t.Fatalf("Lock a: %v", err)
+	}
+	if _, err := l.Lock(key+":b", 10, nil); err != nil {
+		t.Errorf("Lock on other key should succeed: %v", err)
+	}
+}
+
+func testExpire(t *testing.T, l storage.AdapterLocker, key string) {
+	if _, err := l.Lock(key, 1, nil); err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := l.Lock(key, 1, nil); err != nil {
+		t.Errorf("Lock after ttl should succeed: %v", err)
+	}
+}
+
+func testRelease(t *testing.T, l storage.AdapterLocker, key string) {
+	lock, err := l.Lock(key, 10, nil)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	if lock == nil {
+		t.Skip("adapter returns nil lock, release not supported")
+	}
+	if err = lock.Release(context.TODO()); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if _, err = l.Lock(key, 10, nil); err != nil {
+		t.Errorf("Lock after Release should succeed: %v", err)
+	}
+}
+
+func testConcurrent(t *testing.T, l storage.AdapterLocker, key string) {
+	const n = 20
+	var (
+		wg       sync.WaitGroup
+		obtained int32
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := l.Lock(key, 10, nil); err == nil {
+				atomic.AddInt32(&obtained, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if obtained != 1 {
+		t.Errorf("%d of %d concurrent Lock calls obtained the lock, want 1", obtained, n)
+	}
+}
@@ -0,0 +1,35 @@
+package lockertest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsm/redislock"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// memoryLocker 进程内的锁, 只用于验证一致性测试本身
+type memoryLocker struct {
+	mux  sync.Mutex
+	keys map[string]time.Time
+}
+
+func (*memoryLocker) String() string { return "memory" }
+
+func (l *memoryLocker) Lock(key string, ttl int64, _ *redislock.Options) (*redislock.Lock, error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if time.Now().Before(l.keys[key]) {
+		return nil, redislock.ErrNotObtained
+	}
+	l.keys[key] = time.Now().Add(time.Duration(ttl) * time.Second)
+	return nil, nil
+}
+
+func TestMemory(t *testing.T) {
+	RunAdapterTests(t, func(t *testing.T) storage.AdapterLocker {
+		return &memoryLocker{keys: make(map[string]time.Time)}
+	})
+}
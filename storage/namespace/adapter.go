@@ -0,0 +1,192 @@
+package namespace
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+// Cache 为全部key加上环境前缀
+type Cache struct {
+	c      storage.AdapterCache
+	env    Environment
+	prefix string
+}
+
+var _ storage.AdapterCache = (*Cache)(nil)
+
+// NewCache 包装缓存, env 为空时不加前缀
+func NewCache(c storage.AdapterCache, env Environment) *Cache {
+	return &Cache{c: c, env: env, prefix: env.Prefix()}
+}
+
+// Unwrap 内部缓存
+func (c *Cache) Unwrap() storage.AdapterCache {
+	return c.c
+}
+
+// Environment 缓存所属的环境
+func (c *Cache) Environment() Environment {
+	return c.env
+}
+
+func (c *Cache) String() string {
+	return c.c.String()
+}
+
+func (c *Cache) Get(key string) (string, error) {
+	return c.c.Get(c.prefix + key)
+}
+
+func (c *Cache) Set(key string, val interface{}, expire int) error {
+	return c.c.Set(c.prefix+key, val, expire)
+}
+
+func (c *Cache) Del(key string) error {
+	return c.c.Del(c.prefix + key)
+}
+
+func (c *Cache) HashGet(hk, key string) (string, error) {
+	return c.c.HashGet(c.prefix+hk, key)
+}
+
+func (c *Cache) HashDel(hk, key string) error {
+	return c.c.HashDel(c.prefix+hk, key)
+}
+
+func (c *Cache) Increase(key string) error {
+	return c.c.Increase(c.prefix + key)
+}
+
+func (c *Cache) Decrease(key string) error {
+	return c.c.Decrease(c.prefix + key)
+}
+
+func (c *Cache) Expire(key string, dur time.Duration) error {
+	return c.c.Expire(c.prefix+key, dur)
+}
+
+// GetTouch 读取并把有效期重置为 ttl
+func (c *Cache) GetTouch(key string, ttl time.Duration) (string, error) {
+	return cache.GetTouch(c.c, c.prefix+key, ttl)
+}
+
+// SetNX key 不存在时写入, 返回是否写入
+func (c *Cache) SetNX(key string, val interface{}, expire int) (bool, error) {
+	return cache.SetNX(c.c, c.prefix+key, val, expire)
+}
+
+// HashSet 写入本环境hash的字段; 内部缓存不支持时返回 cache.ErrHashGetAllUnsupported
+func (c *Cache) HashSet(hk, key string, val interface{}) error {
+	return cache.HashSet(c.c, c.prefix+hk, key, val)
+}
+
+// HashGetAll 读取本环境hash的全部字段; 内部缓存不支持时返回 cache.ErrHashGetAllUnsupported
+func (c *Cache) HashGetAll(hk string) (map[string]string, error) {
+	return cache.HashGetAll(c.c, c.prefix+hk)
+}
+
+// CompareAndSwap 当前值为 old 时写入 val; 内部缓存不支持时返回 cache.ErrCASUnsupported
+func (c *Cache) CompareAndSwap(key, old string, val interface{}, expire int) (bool, error) {
+	return cache.CompareAndSwap(c.c, c.prefix+key, old, val, expire)
+}
+
+// Tx 事务中的key同样带上环境前缀
+func (c *Cache) Tx(f func(p storage.Pipeliner) error) error {
+	return cache.Tx(c.c, c.prefix, f)
+}
+
+// Keys 本环境以 prefix 开头的key, 已去掉环境前缀; 内部缓存不支持时返回 ErrKeysUnsupported
+func (c *Cache) Keys(prefix string) ([]string, error) {
+	keys, err := cache.Keys(c.c, c.prefix+prefix)
+	for i := range keys {
+		keys[i] = strings.TrimPrefix(keys[i], c.prefix)
+	}
+	return keys, err
+}
+
+// FlushNamespace 删除本环境的全部key, 返回删除的数量; 生产环境需传入 force,
+// 未设置环境时会删除整个缓存, 同样需要 force
+func (c *Cache) FlushNamespace(force bool) (int, error) {
+	if (c.env.IsProd() || c.env == "") && !force {
+		return 0, ErrFlushRefused
+	}
+	keys, err := c.Keys("")
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, key := range keys {
+		if err = c.Del(key); err != nil {
+			return n, err
+		}
+		n++
+	}
+	logger.Module("storage.namespace").Warn("namespace flushed", "environment", c.env, "keys", n, "force", force)
+	return n, nil
+}
+
+// Queue 为全部stream加上环境前缀, 消费者收到的消息 stream 已去掉前缀
+type Queue struct {
+	q      storage.AdapterQueue
+	env    Environment
+	prefix string
+}
+
+var _ storage.AdapterQueue = (*Queue)(nil)
+
+// NewQueue 包装队列, env 为空时不加前缀
+func NewQueue(q storage.AdapterQueue, env Environment) *Queue {
+	return &Queue{q: q, env: env, prefix: env.Prefix()}
+}
+
+// Unwrap 内部队列
+func (q *Queue) Unwrap() storage.AdapterQueue {
+	return q.q
+}
+
+// Environment 队列所属的环境
+func (q *Queue) Environment() Environment {
+	return q.env
+}
+
+func (q *Queue) String() string {
+	return q.q.String()
+}
+
+// Append 投递时使用带前缀的 stream, 不修改调用方的消息, 失败后重新投递不会重复加前缀
+func (q *Queue) Append(m storage.Messager) error {
+	return q.q.Append(&prefixedMessage{Messager: m, stream: q.prefix + m.GetStream()})
+}
+
+// prefixedMessage 替换 stream, 其余字段仍读写调用方的消息, 内部队列设置的id对调用方可见
+type prefixedMessage struct {
+	storage.Messager
+	stream string
+}
+
+func (m *prefixedMessage) GetStream() string {
+	return m.stream
+}
+
+func (m *prefixedMessage) SetStream(stream string) {
+	m.stream = stream
+}
+
+func (q *Queue) Register(name string, f storage.ConsumerFunc) {
+	q.q.Register(q.prefix+name, func(m storage.Messager) error {
+		m.SetStream(strings.TrimPrefix(m.GetStream(), q.prefix))
+		return f(m)
+	})
+}
+
+func (q *Queue) Run() {
+	q.q.Run()
+}
+
+func (q *Queue) Shutdown() {
+	q.q.Shutdown()
+}
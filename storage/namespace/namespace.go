@@ -0,0 +1,60 @@
+// Package namespace 按环境隔离缓存key与队列stream: 多个环境共用一个redis时, key与stream自动带上环境前缀,
+// 互不影响; 生产环境清空命名空间需显式传入 force
+//
+//	c := namespace.NewCache(cache, namespace.Prod) // key user:1 实际为 prod:user:1
+//	q := namespace.NewQueue(q, namespace.Prod)     // stream export 实际为 prod:export
+//	n, err := c.FlushNamespace(false)              // 生产环境返回 ErrFlushRefused
+package namespace
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+// Environment 环境名, 作为key与stream的前缀
+type Environment string
+
+const (
+	Dev     Environment = "dev"
+	Staging Environment = "staging"
+	Prod    Environment = "prod"
+)
+
+var (
+	// ErrFlushRefused 生产环境未传入 force 时拒绝清空
+	ErrFlushRefused = errors.New("namespace: refusing to flush prod namespace without force")
+	// ErrKeysUnsupported 内部缓存未实现 storage.AdapterKeysCache, 无法清空
+	ErrKeysUnsupported = cache.ErrKeysUnsupported
+)
+
+// Parse 环境名转为小写, 兼容 production、development 等写法, 其他名称原样使用
+func Parse(s string) Environment {
+	switch s = strings.ToLower(strings.TrimSpace(s)); s {
+	case "production":
+		return Prod
+	case "development":
+		return Dev
+	case "stage", "pre", "test":
+		return Staging
+	}
+	return Environment(s)
+}
+
+func (e Environment) String() string {
+	return string(e)
+}
+
+// IsProd 是否生产环境
+func (e Environment) IsProd() bool {
+	return e == Prod
+}
+
+// Prefix key与stream的前缀, 未设置环境时为空
+func (e Environment) Prefix() string {
+	if e == "" {
+		return ""
+	}
+	return string(e) + ":"
+}
@@ -0,0 +1,131 @@
+package namespace
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+func TestParse(t *testing.T) {
+	for in, want := range map[string]Environment{"Production": Prod, "dev": Dev, "test": Staging, "qa": "qa", "": ""} {
+		if got := Parse(in); got != want {
+			t.Errorf("Parse(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	shared := cache.NewMemory()
+	prod, dev := NewCache(shared, Prod), NewCache(shared, Dev)
+	_ = prod.Set("user:1", "p", 60)
+	_ = dev.Set("user:1", "d", 60)
+	if v, _ := shared.Get("prod:user:1"); v != "p" {
+		t.Errorf("raw key = %q", v)
+	}
+	if v, _ := dev.Get("user:1"); v != "d" {
+		t.Errorf("dev = %q", v)
+	}
+	if keys, _ := prod.Keys("user:"); len(keys) != 1 || keys[0] != "user:1" {
+		t.Errorf("keys = %v", keys)
+	}
+
+	if _, err := prod.FlushNamespace(false); !errors.Is(err, ErrFlushRefused) {
+		t.Errorf("prod flush err = %v", err)
+	}
+	if _, err := NewCache(shared, "").FlushNamespace(false); !errors.Is(err, ErrFlushRefused) {
+		t.Errorf("unnamespaced flush err = %v", err)
+	}
+	if n, err := dev.FlushNamespace(false); err != nil || n != 1 {
+		t.Errorf("dev flush = %d, %v", n, err)
+	}
+	if v, _ := prod.Get("user:1"); v != "p" {
+		t.Error("flush should not touch other environments")
+	}
+	if n, err := prod.FlushNamespace(true); err != nil || n != 1 {
+		t.Errorf("forced flush = %d, %v", n, err)
+	}
+	if _, err := NewCache(struct{ storage.AdapterCache }{shared}, Dev).FlushNamespace(false); !errors.Is(err, ErrKeysUnsupported) {
+		t.Errorf("err = %v", err)
+	}
+
+	// 可选接口同样带上前缀
+	_ = dev.HashSet("dict", "a", "1")
+	if values, err := dev.HashGetAll("dict"); err != nil || values["a"] != "1" {
+		t.Errorf("dev hash = %v, err = %v", values, err)
+	}
+	if values, _ := shared.HashGetAll("dev:dict"); values["a"] != "1" {
+		t.Errorf("raw hash = %v", values)
+	}
+	if values, _ := prod.HashGetAll("dict"); len(values) != 0 {
+		t.Errorf("prod hash = %v", values)
+	}
+	_ = dev.Set("ver", "1", 60)
+	if ok, err := dev.CompareAndSwap("ver", "1", "2", 60); !ok || err != nil {
+		t.Errorf("cas = %v, err = %v", ok, err)
+	}
+	if v, _ := shared.Get("dev:ver"); v != "2" {
+		t.Errorf("raw ver = %q", v)
+	}
+	if _, err := NewCache(struct{ storage.AdapterCache }{shared}, Dev).CompareAndSwap("ver", "2", "3", 60); err != cache.ErrCASUnsupported {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRedisKeys(t *testing.T) {
+	s := miniredis.RunT(t)
+	c, err := cache.NewRedis(nil, &redis.Options{Addr: s.Addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"dev:a", "dev:b", "dev*x", "prod:a"} {
+		_ = s.Set(key, "1")
+	}
+	keys, err := c.Keys("dev:")
+	sort.Strings(keys)
+	if err != nil || len(keys) != 2 || keys[0] != "dev:a" {
+		t.Errorf("keys = %v, err = %v", keys, err)
+	}
+	if keys, _ = c.Keys("dev*"); len(keys) != 1 {
+		t.Errorf("glob characters should be escaped: %v", keys)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	mem := queue.NewMemory(10)
+	q := NewQueue(mem, Staging)
+	got := make(chan string, 1)
+	q.Register("export", func(m storage.Messager) error {
+		got <- m.GetStream()
+		return nil
+	})
+	go q.Run()
+	defer q.Shutdown()
+	m := new(queue.Message)
+	m.SetStream("export")
+	m.SetValues(map[string]interface{}{"id": "1"})
+	if err := q.Append(m); err != nil {
+		t.Fatal(err)
+	}
+	if m.GetStream() != "export" {
+		t.Errorf("caller's message stream = %s, should not be prefixed", m.GetStream())
+	}
+	select {
+	case stream := <-got:
+		if stream != "export" {
+			t.Errorf("stream = %s", stream)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not consumed")
+	}
+	if mem.StreamLen("staging:export") != 0 || mem.Concurrency("staging:export") == 0 {
+		t.Error("stream should be registered with the environment prefix")
+	}
+}
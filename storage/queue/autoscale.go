@@ -0,0 +1,148 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+// Scalable 可以按 stream 调整消费并发的队列, 如 *Memory
+type Scalable interface {
+	StreamLen(stream string) int
+	Concurrency(stream string) int
+	SetConcurrency(stream string, n int)
+}
+
+var _ Scalable = (*Memory)(nil)
+
+type AutoscaleOption func(*autoscaleOptions)
+
+type autoscaleOptions struct {
+	min       int
+	max       int
+	interval  time.Duration
+	targetLag int
+}
+
+func setAutoscaleDefault() autoscaleOptions {
+	return autoscaleOptions{
+		min:       1,
+		max:       8,
+		interval:  10 * time.Second,
+		targetLag: 100,
+	}
+}
+
+// WithBounds 每个 stream 的并发范围, 默认 1~8
+func WithBounds(min, max int) AutoscaleOption {
+	return func(o *autoscaleOptions) {
+		o.min = min
+		o.max = max
+	}
+}
+
+// WithInterval 检查积压的间隔, 默认10秒
+func WithInterval(d time.Duration) AutoscaleOption {
+	return func(o *autoscaleOptions) {
+		o.interval = d
+	}
+}
+
+// WithTargetLag 每个消费协程期望承担的积压消息数, 默认100
+func WithTargetLag(n int) AutoscaleOption {
+	return func(o *autoscaleOptions) {
+		o.targetLag = n
+	}
+}
+
+// Autoscaler 按积压调整消费并发: 积压增加时立即扩到需要的数量, 积压减少时每次检查减少一个, 避免抖动;
+// 可作为 runtime.Component 启动
+//
+//	a := queue.NewAutoscaler(q, []string{"export"}, queue.WithBounds(1, 16))
+//	sdk.Runtime.AddComponent(runtime.OrderQueue+1, a)
+type Autoscaler struct {
+	s       Scalable
+	streams []string
+	o       autoscaleOptions
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func NewAutoscaler(s Scalable, streams []string, opts ...AutoscaleOption) *Autoscaler {
+	o := setAutoscaleDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.min < 1 {
+		o.min = 1
+	}
+	if o.max < o.min {
+		o.max = o.min
+	}
+	if o.targetLag < 1 {
+		o.targetLag = 1
+	}
+	return &Autoscaler{s: s, streams: streams, o: o}
+}
+
+func (a *Autoscaler) String() string {
+	return "queue.autoscaler"
+}
+
+// Start 开始定时检查, 不阻塞
+func (a *Autoscaler) Start(context.Context) error {
+	a.stop = make(chan struct{})
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(a.o.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.stop:
+				return
+			case <-ticker.C:
+				a.scale()
+			}
+		}
+	}()
+	return nil
+}
+
+func (a *Autoscaler) Stop(context.Context) error {
+	if a.stop != nil {
+		close(a.stop)
+		a.wg.Wait()
+		a.stop = nil
+	}
+	return nil
+}
+
+func (a *Autoscaler) scale() {
+	for _, stream := range a.streams {
+		current := a.s.Concurrency(stream)
+		if current == 0 {
+			// 尚未注册消费者
+			continue
+		}
+		lag := a.s.StreamLen(stream)
+		desired := (lag + a.o.targetLag - 1) / a.o.targetLag
+		if desired < current {
+			desired = current - 1
+		}
+		if desired < a.o.min {
+			desired = a.o.min
+		}
+		if desired > a.o.max {
+			desired = a.o.max
+		}
+		if desired == current {
+			continue
+		}
+		a.s.SetConcurrency(stream, desired)
+		logger.Module("storage.queue").Info("consumer concurrency changed",
+			"stream", stream, "lag", lag, "from", current, "to", desired)
+	}
+}
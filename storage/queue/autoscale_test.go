@@ -0,0 +1,42 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+func TestAutoscaler(t *testing.T) {
+	m := NewMemory(1000)
+	block := make(chan struct{})
+	defer close(block)
+	m.Register("export", func(storage.Messager) error {
+		<-block
+		return nil
+	})
+	for i := 0; i < 350; i++ {
+		msg := new(Message)
+		msg.SetStream("export")
+		msg.SetValues(map[string]interface{}{"i": i})
+		_ = m.Append(msg)
+	}
+	a := NewAutoscaler(m, []string{"export", "idle"}, WithBounds(1, 3), WithTargetLag(100))
+	a.scale()
+	if n := m.Concurrency("export"); n != 3 {
+		t.Fatalf("concurrency = %d, want max 3", n)
+	}
+	// 取出积压, 模拟消费完成
+	for m.StreamLen("export") > 0 {
+		v, _ := m.queue.Load("export")
+		<-v.(queue)
+	}
+	a.scale()
+	if n := m.Concurrency("export"); n != 2 {
+		t.Errorf("concurrency = %d, want scale down by one", n)
+	}
+	a.scale()
+	a.scale()
+	if n := m.Concurrency("export"); n != 1 || m.Concurrency("idle") != 0 {
+		t.Errorf("concurrency = %d, want min 1", n)
+	}
+}
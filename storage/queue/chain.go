@@ -0,0 +1,163 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// ErrHopNotFound 消息未记录或已过期
+var ErrHopNotFound = errors.New("queue: message not recorded")
+
+// maxChainDepth 查询因果链的最大层数, 防止记录异常时循环
+const maxChainDepth = 64
+
+// Hop 因果链中的一条消息
+type Hop struct {
+	ID         string    `json:"id"`
+	ParentID   string    `json:"parentId,omitempty"`
+	TraceID    string    `json:"traceId,omitempty"`
+	Stream     string    `json:"stream"`
+	Producer   string    `json:"producer,omitempty"`
+	EnqueuedAt time.Time `json:"enqueuedAt"`
+}
+
+// HopOf 消息中的因果链字段, 消费者中查看当前消息的来源
+func HopOf(message storage.Messager) Hop {
+	values := message.GetValues()
+	h := Hop{Stream: message.GetStream()}
+	h.ID, _ = values[MessageIDKey].(string)
+	h.ParentID, _ = values[ParentIDKey].(string)
+	h.TraceID, _ = values[TraceIDKey].(string)
+	h.Producer, _ = values[ProducerKey].(string)
+	if s, _ := values[EnqueuedAtKey].(string); s != "" {
+		if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+			h.EnqueuedAt = time.UnixMilli(ms)
+		}
+	}
+	return h
+}
+
+type ChainOption func(*chainOptions)
+
+type chainOptions struct {
+	prefix string
+	ttl    int
+}
+
+func setChainDefault() chainOptions {
+	return chainOptions{
+		prefix: "queue:chain:",
+		ttl:    24 * 3600,
+	}
+}
+
+// WithChainPrefix 记录的key前缀, 默认 queue:chain:
+func WithChainPrefix(prefix string) ChainOption {
+	return func(o *chainOptions) {
+		o.prefix = prefix
+	}
+}
+
+// WithChainTTL 记录的保存时间, 单位秒, 默认1天
+func WithChainTTL(ttl int) ChainOption {
+	return func(o *chainOptions) {
+		o.ttl = ttl
+	}
+}
+
+// ChainStore 在缓存中记录投递的消息, 按父消息id查询因果链, 用于排查多步骤的流程
+//
+//	chains := queue.NewChainStore(sdk.Runtime.GetCacheAdapter())
+//	q := chains.Wrap(sdk.Runtime.GetQueueAdapter())
+//	hops, _ := chains.Chain(queue.HopOf(message).ID) // 从最初的消息到当前消息
+type ChainStore struct {
+	cache storage.AdapterCache
+	o     chainOptions
+}
+
+func NewChainStore(c storage.AdapterCache, opts ...ChainOption) *ChainStore {
+	o := setChainDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &ChainStore{cache: c, o: o}
+}
+
+// Record 记录消息, 消息需已有消息id, 见 Stamp
+func (s *ChainStore) Record(message storage.Messager) error {
+	h := HopOf(message)
+	if h.ID == "" {
+		return ErrHopNotFound
+	}
+	b, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	return s.cache.Set(s.o.prefix+h.ID, string(b), s.o.ttl)
+}
+
+// Hop 查询一条记录, 未记录或已过期时返回 ErrHopNotFound
+func (s *ChainStore) Hop(id string) (Hop, error) {
+	var h Hop
+	v, err := s.cache.Get(s.o.prefix + id)
+	if err != nil || v == "" {
+		return h, ErrHopNotFound
+	}
+	err = json.Unmarshal([]byte(v), &h)
+	return h, err
+}
+
+// Chain 消息的因果链, 从最初的消息到 id 对应的消息; 中间的记录过期时从过期处之后开始
+func (s *ChainStore) Chain(id string) ([]Hop, error) {
+	h, err := s.Hop(id)
+	if err != nil {
+		return nil, err
+	}
+	chain := []Hop{h}
+	seen := map[string]bool{id: true}
+	for h.ParentID != "" && !seen[h.ParentID] && len(chain) < maxChainDepth {
+		seen[h.ParentID] = true
+		if h, err = s.Hop(h.ParentID); err != nil {
+			break
+		}
+		chain = append(chain, h)
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain, nil
+}
+
+// Wrap 投递时记录消息, 记录失败只输出日志, 不影响投递
+func (s *ChainStore) Wrap(q storage.AdapterQueue) *Recorded {
+	return &Recorded{AdapterQueue: q, store: s}
+}
+
+// Recorded 记录因果链的队列
+type Recorded struct {
+	storage.AdapterQueue
+	store *ChainStore
+}
+
+// Unwrap 内部队列
+func (r *Recorded) Unwrap() storage.AdapterQueue {
+	return r.AdapterQueue
+}
+
+func (r *Recorded) Append(message storage.Messager) error {
+	m := new(Message)
+	m.SetID(message.GetID())
+	m.SetStream(message.GetStream())
+	m.SetValues(message.GetValues())
+	Stamp(m)
+	if err := r.store.Record(m); err != nil {
+		logger.Module("storage.queue").WithContext(TraceContext(m)).
+			Warn("record message chain failed", "stream", m.GetStream(), "error", err)
+	}
+	return r.AdapterQueue.Append(m)
+}
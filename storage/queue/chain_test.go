@@ -0,0 +1,59 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+func TestChainStore(t *testing.T) {
+	chains := NewChainStore(cache.NewMemory())
+	q := chains.Wrap(NewMemory(10))
+	done := make(chan Hop, 1)
+	q.Register("order.created", func(m storage.Messager) error {
+		next := new(Message)
+		next.SetStream("order.paid")
+		next.SetValues(map[string]interface{}{"order": m.GetValues()["order"]})
+		InjectTrace(TraceContext(m), next)
+		return q.Append(next)
+	})
+	q.Register("order.paid", func(m storage.Messager) error {
+		done <- HopOf(m)
+		return nil
+	})
+	go q.Run()
+	defer q.Shutdown()
+
+	values := map[string]interface{}{"order": "1"}
+	m := new(Message)
+	m.SetStream("order.created")
+	m.SetValues(values)
+	InjectTrace(logger.WithTraceID(context.Background(), "t1"), m)
+	if err := q.Append(m); err != nil {
+		t.Fatal(err)
+	}
+	var last Hop
+	select {
+	case last = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not consumed")
+	}
+	if last.Producer != Instance() || last.EnqueuedAt.IsZero() || last.TraceID != "t1" || last.ParentID != values[MessageIDKey] {
+		t.Errorf("hop = %+v", last)
+	}
+	if _, ok := values[ProducerKey]; ok {
+		t.Error("Stamp should not modify the caller's values")
+	}
+
+	chain, err := chains.Chain(last.ID)
+	if err != nil || len(chain) != 2 || chain[0].Stream != "order.created" || chain[1].ID != last.ID {
+		t.Fatalf("chain = %+v, err = %v", chain, err)
+	}
+	if _, err = chains.Chain("missing"); err != ErrHopNotFound {
+		t.Errorf("missing: err = %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/spf13/cast"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// EncodedKey 经过 Codec 编码的字段, 多个以逗号分隔
+const EncodedKey = "__encoded"
+
+// ErrValueNotFound 消息中没有该字段
+var ErrValueNotFound = errors.New("queue: value not found")
+
+// Codec 非标量值(map、slice、struct 等)的编码方式, 生产者与消费者需一致
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+var codec atomic.Value
+
+func init() {
+	codec.Store(codecHolder{jsonCodec{}})
+}
+
+// codecHolder atomic.Value 要求每次保存的类型相同
+type codecHolder struct {
+	Codec
+}
+
+// SetCodec 设置非标量值的编码方式, 默认 json; 需在投递与消费前调用
+func SetCodec(c Codec) {
+	codec.Store(codecHolder{c})
+}
+
+func currentCodec() Codec {
+	return codec.Load().(codecHolder).Codec
+}
+
+// EncodeValues 复制 Values 并编码其中的非标量值, 以 __ 开头的系统字段不编码; redis 队列投递时自动调用,
+// 否则嵌套的 map 会被转为无法还原的字符串
+func EncodeValues(values map[string]interface{}) (map[string]interface{}, error) {
+	copied := make(map[string]interface{}, len(values)+1)
+	var encoded []string
+	for k, v := range values {
+		if strings.HasPrefix(k, "__") || isScalar(v) {
+			copied[k] = v
+			continue
+		}
+		b, err := currentCodec().Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		copied[k] = string(b)
+		encoded = append(encoded, k)
+	}
+	if len(encoded) > 0 {
+		sort.Strings(encoded)
+		copied[EncodedKey] = strings.Join(encoded, ",")
+	}
+	return copied, nil
+}
+
+func isScalar(v interface{}) bool {
+	switch v.(type) {
+	case nil, string, []byte, bool,
+		int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	}
+	return false
+}
+
+func encodedKeys(values map[string]interface{}) map[string]bool {
+	s, _ := values[EncodedKey].(string)
+	if s == "" {
+		return nil
+	}
+	keys := make(map[string]bool)
+	for _, k := range strings.Split(s, ",") {
+		keys[k] = true
+	}
+	return keys
+}
+
+// Decode 将字段解码到 v: 编码过的字段使用 Codec; 经过 redis 变为字符串的数字、布尔值按 json 解析;
+// 内存队列中未编码的原始值经 json 转换
+func Decode(message storage.Messager, key string, v interface{}) error {
+	values := message.GetValues()
+	raw, ok := values[key]
+	if !ok {
+		return ErrValueNotFound
+	}
+	s, isString := raw.(string)
+	switch {
+	case isString && encodedKeys(values)[key]:
+		return currentCodec().Unmarshal([]byte(s), v)
+	case isString:
+		if p, ok := v.(*string); ok {
+			*p = s
+			return nil
+		}
+		return json.Unmarshal([]byte(s), v)
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// DecodeValues 解码全部编码过的字段, 返回新的 Values, 其余字段不变
+func DecodeValues(message storage.Messager) (map[string]interface{}, error) {
+	values := message.GetValues()
+	keys := encodedKeys(values)
+	decoded := make(map[string]interface{}, len(values))
+	for k, raw := range values {
+		if k == EncodedKey {
+			continue
+		}
+		s, ok := raw.(string)
+		if !ok || !keys[k] {
+			decoded[k] = raw
+			continue
+		}
+		var v interface{}
+		if err := currentCodec().Unmarshal([]byte(s), &v); err != nil {
+			return nil, err
+		}
+		decoded[k] = v
+	}
+	return decoded, nil
+}
+
+// String 字段的字符串形式, 不存在时为空
+func String(message storage.Messager, key string) string {
+	return cast.ToString(message.GetValues()[key])
+}
+
+// Int64 字段的整数形式, 兼容经过 redis 后的字符串
+func Int64(message storage.Messager, key string) (int64, error) {
+	raw, ok := message.GetValues()[key]
+	if !ok {
+		return 0, ErrValueNotFound
+	}
+	return cast.ToInt64E(raw)
+}
+
+// Float64 字段的浮点数形式, 兼容经过 redis 后的字符串
+func Float64(message storage.Messager, key string) (float64, error) {
+	raw, ok := message.GetValues()[key]
+	if !ok {
+		return 0, ErrValueNotFound
+	}
+	return cast.ToFloat64E(raw)
+}
+
+// Bool 字段的布尔形式, 兼容经过 redis 后的字符串
+func Bool(message storage.Messager, key string) (bool, error) {
+	raw, ok := message.GetValues()[key]
+	if !ok {
+		return false, ErrValueNotFound
+	}
+	return cast.ToBoolE(raw)
+}
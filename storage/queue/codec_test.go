@@ -0,0 +1,65 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/spf13/cast"
+)
+
+type orderItem struct {
+	SKU   string `json:"sku"`
+	Count int    `json:"count"`
+}
+
+func TestEncodeValues(t *testing.T) {
+	values := map[string]interface{}{
+		"order": int64(10),
+		"paid":  true,
+		"items": []orderItem{{SKU: "a", Count: 2}},
+		"meta":  map[string]interface{}{"source": "web"},
+	}
+	encoded, err := EncodeValues(values)
+	if err != nil || encoded[EncodedKey] != "items,meta" || encoded["order"] != int64(10) {
+		t.Fatalf("encoded = %v, err = %v", encoded, err)
+	}
+	if _, ok := values[EncodedKey]; ok {
+		t.Error("EncodeValues should not modify the caller's values")
+	}
+
+	// 经过 redis 后全部为字符串
+	flattened := make(map[string]interface{}, len(encoded))
+	for k, v := range encoded {
+		flattened[k] = cast.ToString(v)
+	}
+	for name, vs := range map[string]map[string]interface{}{"redis": flattened, "memory": values} {
+		m := new(Message)
+		m.SetValues(vs)
+		var items []orderItem
+		if err = Decode(m, "items", &items); err != nil || len(items) != 1 || items[0].Count != 2 {
+			t.Errorf("%s: items = %+v, err = %v", name, items, err)
+		}
+		var order int
+		if err = Decode(m, "order", &order); err != nil || order != 10 {
+			t.Errorf("%s: order = %d, err = %v", name, order, err)
+		}
+		if n, err := Int64(m, "order"); err != nil || n != 10 {
+			t.Errorf("%s: Int64 = %d, err = %v", name, n, err)
+		}
+		if paid, err := Bool(m, "paid"); err != nil || !paid {
+			t.Errorf("%s: Bool = %v, err = %v", name, paid, err)
+		}
+		if err = Decode(m, "missing", &order); err != ErrValueNotFound {
+			t.Errorf("%s: missing err = %v", name, err)
+		}
+	}
+
+	m := new(Message)
+	m.SetValues(flattened)
+	decoded, err := DecodeValues(m)
+	if err != nil || decoded["meta"].(map[string]interface{})["source"] != "web" || decoded["order"] != "10" {
+		t.Errorf("decoded = %v, err = %v", decoded, err)
+	}
+	if _, ok := decoded[EncodedKey]; ok {
+		t.Error("decoded values should not contain the encoded marker")
+	}
+}
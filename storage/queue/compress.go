@@ -0,0 +1,135 @@
+package queue
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// 压缩消息在 Values 中的标记与内容
+const (
+	EncodingKey = "__encoding"
+	PayloadKey  = "__payload"
+
+	EncodingGzip = "gzip"
+)
+
+// DefaultCompressThreshold 默认的压缩阈值, 单位字节
+const DefaultCompressThreshold = 1024
+
+// Compressed 投递时将超过阈值的消息压缩, 消费时自动解压; 以 __ 开头的系统字段(如链路字段)不压缩;
+// 解压后的值经过 json, 数字会变为 float64, 与经过 redis 后都为字符串一样, 消费方不应依赖值的类型
+//
+//	q := queue.Compress(sdk.Runtime.GetQueueAdapter(), 0)
+type Compressed struct {
+	storage.AdapterQueue
+	threshold int
+}
+
+// Compress threshold 为0时使用 DefaultCompressThreshold; 生产者与消费者都需要使用包装后的队列,
+// 未包装的消费者可调用 Decompress
+func Compress(q storage.AdapterQueue, threshold int) *Compressed {
+	if threshold <= 0 {
+		threshold = DefaultCompressThreshold
+	}
+	return &Compressed{AdapterQueue: q, threshold: threshold}
+}
+
+// Unwrap 内部队列
+func (c *Compressed) Unwrap() storage.AdapterQueue {
+	return c.AdapterQueue
+}
+
+func (c *Compressed) Append(message storage.Messager) error {
+	values, err := compress(message.GetValues(), c.threshold)
+	if err != nil {
+		return err
+	}
+	m := new(Message)
+	m.SetID(message.GetID())
+	m.SetStream(message.GetStream())
+	m.SetValues(values)
+	return c.AdapterQueue.Append(m)
+}
+
+func (c *Compressed) Register(name string, f storage.ConsumerFunc) {
+	c.AdapterQueue.Register(name, func(message storage.Messager) error {
+		if err := Decompress(message); err != nil {
+			return err
+		}
+		return f(message)
+	})
+}
+
+// Decompress 解压消息, 未压缩的消息不做处理
+func Decompress(message storage.Messager) error {
+	values := message.GetValues()
+	encoding, _ := values[EncodingKey].(string)
+	if encoding == "" {
+		return nil
+	}
+	if encoding != EncodingGzip {
+		return fmt.Errorf("queue: unsupported encoding %s", encoding)
+	}
+	payload, _ := values[PayloadKey].(string)
+	b, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return err
+	}
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	b, err = io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	decoded := make(map[string]interface{})
+	if err = json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+	for k, v := range values {
+		if k != EncodingKey && k != PayloadKey {
+			decoded[k] = v
+		}
+	}
+	message.SetValues(decoded)
+	return nil
+}
+
+func compress(values map[string]interface{}, threshold int) (map[string]interface{}, error) {
+	body := make(map[string]interface{}, len(values))
+	system := make(map[string]interface{})
+	for k, v := range values {
+		if strings.HasPrefix(k, "__") {
+			system[k] = v
+			continue
+		}
+		body[k] = v
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < threshold {
+		return values, nil
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err = w.Write(b); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	system[EncodingKey] = EncodingGzip
+	system[PayloadKey] = base64.StdEncoding.EncodeToString(buf.Bytes())
+	return system, nil
+}
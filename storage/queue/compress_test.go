@@ -0,0 +1,63 @@
+package queue
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+func TestCompress(t *testing.T) {
+	inner := NewMemory(10)
+	q := Compress(inner, 100)
+	received := make(chan storage.Messager, 2)
+	q.Register("export", func(m storage.Messager) error {
+		received <- m
+		return nil
+	})
+	raw := make(chan storage.Messager, 2)
+	inner.Register("raw", func(m storage.Messager) error {
+		raw <- m
+		return nil
+	})
+
+	params := strings.Repeat(`{"dept":1,"status":"2"},`, 50)
+	m := new(Message)
+	m.SetStream("export")
+	m.SetValues(map[string]interface{}{"params": params, TraceIDKey: "t1"})
+	if err := q.Append(m); err != nil {
+		t.Fatal(err)
+	}
+	small := new(Message)
+	small.SetStream("export")
+	small.SetValues(map[string]interface{}{"id": "1"})
+	_ = q.Append(small)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case got := <-received:
+			v := got.GetValues()
+			if _, ok := v[EncodingKey]; ok {
+				t.Errorf("values not decompressed: %v", v)
+			}
+			if v["id"] == nil && (v["params"] != params || v[TraceIDKey] != "t1") {
+				t.Errorf("values = %v", v)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("message not received")
+		}
+	}
+
+	m.SetStream("raw")
+	m.SetValues(map[string]interface{}{"params": params, TraceIDKey: "t1"})
+	_ = q.Append(m)
+	got := <-raw
+	v := got.GetValues()
+	if v[EncodingKey] != EncodingGzip || v[TraceIDKey] != "t1" || len(v[PayloadKey].(string)) >= len(params) {
+		t.Errorf("stored values = %v", v)
+	}
+	if err := Decompress(got); err != nil || got.GetValues()["params"] != params {
+		t.Errorf("Decompress err = %v", err)
+	}
+}
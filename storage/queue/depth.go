@@ -0,0 +1,69 @@
+package queue
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-redis/redis/v9"
+)
+
+// Depth stream 的积压, Pending 为已投递未确认的数量
+type Depth struct {
+	Stream  string `json:"stream"`
+	Length  int64  `json:"length"`
+	Pending int64  `json:"pending"`
+}
+
+// Depther 可以查询各 stream 积压的队列
+type Depther interface {
+	Depths(ctx context.Context) ([]Depth, error)
+}
+
+var (
+	_ Depther = (*Memory)(nil)
+	_ Depther = (*Redis)(nil)
+)
+
+// Depths 已注册或已投递的 stream 中等待消费的消息数量, 按 stream 排序
+func (m *Memory) Depths(context.Context) ([]Depth, error) {
+	list := make([]Depth, 0)
+	m.queue.Range(func(k, _ interface{}) bool {
+		name, _ := k.(string)
+		list = append(list, Depth{Stream: name, Length: int64(m.StreamLen(name))})
+		return true
+	})
+	sort.Slice(list, func(i, j int) bool { return list[i].Stream < list[j].Stream })
+	return list, nil
+}
+
+// Depths 已注册 stream 的长度与消费组中未确认的数量, 需通过 ConsumerOptions.RedisClient 传入连接
+func (r *Redis) Depths(ctx context.Context) ([]Depth, error) {
+	if r.handover == nil {
+		return nil, ErrHandoverUnsupported
+	}
+	list := make([]Depth, 0, len(r.streams))
+	for _, stream := range r.streams {
+		d := Depth{Stream: stream}
+		var err error
+		if d.Length, err = r.handover.XLen(ctx, stream).Result(); err != nil {
+			return nil, err
+		}
+		pending, err := r.handover.XPending(ctx, stream, r.group).Result()
+		if err != nil && err != redis.Nil {
+			return nil, err
+		}
+		if pending != nil {
+			d.Pending = pending.Count
+		}
+		list = append(list, d)
+	}
+	return list, nil
+}
+
+// DeadLetters stream 最近转入死信的消息, 新的在前, 见 Reaper
+func (r *Redis) DeadLetters(ctx context.Context, stream string, count int64) ([]redis.XMessage, error) {
+	if r.handover == nil {
+		return nil, ErrHandoverUnsupported
+	}
+	return r.handover.XRevRangeN(ctx, stream+DeadLetterSuffix, "+", "-", count).Result()
+}
@@ -0,0 +1,93 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// ErrNoGroup 主题没有订阅的消费组
+var ErrNoGroup = errors.New("queue: topic has no consumer group")
+
+// GroupStream 消费组在主题下使用的 stream
+func GroupStream(topic, group string) string {
+	return topic + "#" + group
+}
+
+// Fanout 主题广播: 每个消费组使用独立的 stream, Broadcast 向每个组投递一份, 组内仍是竞争消费;
+// 一个 user.created 事件可同时交给审计、邮件、缓存失效等处理
+//
+//	f := queue.NewFanout(q)
+//	f.Subscribe("user.created", "audit", auditHandler)
+//	f.Subscribe("user.created", "mail", mailHandler)
+//	err := f.Broadcast(ctx, "user.created", map[string]interface{}{"id": "1"})
+type Fanout struct {
+	q      storage.AdapterQueue
+	mux    sync.RWMutex
+	groups map[string]map[string]bool
+}
+
+func NewFanout(q storage.AdapterQueue) *Fanout {
+	return &Fanout{q: q, groups: make(map[string]map[string]bool)}
+}
+
+// Declare 声明主题的消费组, 消费组在其他进程订阅时, 广播方需声明后才会投递
+func (f *Fanout) Declare(topic string, groups ...string) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	if f.groups[topic] == nil {
+		f.groups[topic] = make(map[string]bool)
+	}
+	for _, g := range groups {
+		f.groups[topic][g] = true
+	}
+}
+
+// Subscribe 以消费组订阅主题, 同时声明该组
+func (f *Fanout) Subscribe(topic, group string, consumer storage.ConsumerFunc) {
+	f.Declare(topic, group)
+	f.q.Register(GroupStream(topic, group), consumer)
+}
+
+// Groups 主题已声明的消费组
+func (f *Fanout) Groups(topic string) []string {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+	list := make([]string, 0, len(f.groups[topic]))
+	for g := range f.groups[topic] {
+		list = append(list, g)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Broadcast 向主题的每个消费组投递一份, 某个组投递失败不影响其他组, 返回第一个错误
+func (f *Fanout) Broadcast(ctx context.Context, topic string, values map[string]interface{}) error {
+	groups := f.Groups(topic)
+	if len(groups) == 0 {
+		return ErrNoGroup
+	}
+	var first error
+	for _, g := range groups {
+		copied := make(map[string]interface{}, len(values))
+		for k, v := range values {
+			copied[k] = v
+		}
+		m := new(Message)
+		m.SetStream(GroupStream(topic, g))
+		m.SetValues(copied)
+		InjectTrace(ctx, m)
+		if err := f.q.Append(m); err != nil {
+			logger.Module("storage.queue").WithContext(ctx).
+				Error("broadcast failed", "topic", topic, "group", g, "error", err)
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
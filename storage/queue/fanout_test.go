@@ -0,0 +1,39 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+func TestFanout(t *testing.T) {
+	f := NewFanout(NewMemory(10))
+	received := make(chan string, 4)
+	for _, group := range []string{"audit", "mail"} {
+		group := group
+		f.Subscribe("user.created", group, func(m storage.Messager) error {
+			received <- group + ":" + m.GetValues()["id"].(string)
+			return nil
+		})
+	}
+	if err := f.Broadcast(context.TODO(), "user.deleted", nil); err != ErrNoGroup {
+		t.Errorf("err = %v", err)
+	}
+	if err := f.Broadcast(context.TODO(), "user.created", map[string]interface{}{"id": "1"}); err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		select {
+		case s := <-received:
+			got[s] = true
+		case <-time.After(time.Second):
+			t.Fatal("message not received")
+		}
+	}
+	if !got["audit:1"] || !got["mail:1"] {
+		t.Errorf("received = %v", got)
+	}
+}
@@ -0,0 +1,97 @@
+package queue
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+// ErrHandoverUnsupported 未通过 ConsumerOptions.RedisClient 传入连接, 无法交还消息
+var ErrHandoverUnsupported = errors.New("queue: handover requires ConsumerOptions.RedisClient")
+
+// handoverBatch 每次读取的待确认消息数量
+const handoverBatch = 100
+
+// Handover 将 consumer 已领取但未确认的消息重新追加到 stream 末尾并确认、删除原消息,
+// 其他副本可立即消费, 不必等待可见性超时后认领; 返回交还的消息数量
+func Handover(ctx context.Context, client redis.UniversalClient, group, consumer string, streams ...string) (int, error) {
+	total := 0
+	for _, stream := range streams {
+		for {
+			pending, err := client.XPendingExt(ctx, &redis.XPendingExtArgs{
+				Stream:   stream,
+				Group:    group,
+				Start:    "-",
+				End:      "+",
+				Count:    handoverBatch,
+				Consumer: consumer,
+			}).Result()
+			if err != nil && err != redis.Nil {
+				return total, err
+			}
+			if len(pending) == 0 {
+				break
+			}
+			for _, p := range pending {
+				if err = requeue(ctx, client, stream, group, p.ID); err != nil {
+					return total, err
+				}
+				total++
+			}
+		}
+	}
+	return total, nil
+}
+
+// requeue 复制消息到 stream 末尾, 确认并删除原消息; 原消息已被删除时只确认
+func requeue(ctx context.Context, client redis.UniversalClient, stream, group, id string) error {
+	messages, err := client.XRangeN(ctx, stream, id, id, 1).Result()
+	if err != nil {
+		return err
+	}
+	return move(ctx, client, stream, stream, group, id, messages, nil)
+}
+
+// move 复制消息到 target 并写入 extra 中的字段, 确认并删除原消息; 消息已被删除时只确认
+func move(ctx context.Context, client redis.UniversalClient, stream, target, group, id string,
+	messages []redis.XMessage, extra map[string]interface{}) error {
+	_, err := client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if len(messages) > 0 {
+			values := make(map[string]interface{}, len(messages[0].Values)+len(extra))
+			for k, v := range messages[0].Values {
+				values[k] = v
+			}
+			for k, v := range extra {
+				values[k] = v
+			}
+			pipe.XAdd(ctx, &redis.XAddArgs{Stream: target, Values: values})
+		}
+		pipe.XAck(ctx, stream, group, id)
+		pipe.XDel(ctx, stream, id)
+		return nil
+	})
+	return err
+}
+
+// GracefulShutdown 停止消费, 等待处理中的消息完成或 ctx 超时, 再把仍未确认的消息交还给其他副本
+func (r *Redis) GracefulShutdown(ctx context.Context) (int, error) {
+	done := make(chan struct{})
+	go func() {
+		r.consumer.Shutdown()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	if r.handover == nil {
+		return 0, ErrHandoverUnsupported
+	}
+	// ctx 可能已超时, 交还使用独立的ctx
+	n, err := Handover(context.Background(), r.handover, r.group, r.name, r.streams...)
+	logger.Module("storage.queue").Info("pending messages handed over", "queue", "redis", "count", n, "error", err)
+	return n, err
+}
@@ -0,0 +1,44 @@
+package queue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v9"
+)
+
+func TestHandover(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer client.Close()
+	ctx := context.Background()
+	if err := client.XGroupCreateMkStream(ctx, "orders", "g", "0").Err(); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"1", "2"} {
+		client.XAdd(ctx, &redis.XAddArgs{Stream: "orders", Values: map[string]interface{}{"id": id}})
+	}
+	claimed, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{Group: "g", Consumer: "a", Streams: []string{"orders", ">"}, Count: 2}).Result()
+	if err != nil || len(claimed[0].Messages) != 2 {
+		t.Fatalf("claimed = %v, err = %v", claimed, err)
+	}
+	// 第二条已确认, 只交还第一条
+	client.XAck(ctx, "orders", "g", claimed[0].Messages[1].ID)
+
+	n, err := Handover(ctx, client, "g", "a", "orders")
+	if err != nil || n != 1 {
+		t.Fatalf("n = %d, err = %v", n, err)
+	}
+	pending, _ := client.XPending(ctx, "orders", "g").Result()
+	if pending.Count != 0 {
+		t.Errorf("pending = %d", pending.Count)
+	}
+	next, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{Group: "g", Consumer: "b", Streams: []string{"orders", ">"}, Count: 10}).Result()
+	if err != nil || len(next[0].Messages) != 1 || next[0].Messages[0].Values["id"] != "1" {
+		t.Errorf("next = %v, err = %v", next, err)
+	}
+	if all, _ := client.XRange(ctx, "orders", "-", "+").Result(); len(all) != 2 {
+		t.Errorf("stream = %v", all)
+	}
+}
@@ -1,29 +1,86 @@
 package queue
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/go-admin-team/go-admin-core/logger"
 	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/tools/clock"
+	"github.com/go-admin-team/go-admin-core/tools/pool"
 )
 
+// DefaultMaxBacklog 每个stream缓冲已满时最多等待投递的消息数
+const DefaultMaxBacklog = 10000
+
+// DefaultMemoryWorkers 内存队列协程池的大小, 需大于全部 stream 的消费协程数与同时积压的 stream 数之和
+const DefaultMemoryWorkers = 1024
+
+// ErrQueueFull 内存队列 stream 的缓冲与等待列表都已满
+var ErrQueueFull = errors.New("queue: memory stream is full")
+
 type queue chan storage.Messager
 
-// NewMemory 内存模式
+// NewMemory 内存模式, poolNum 为每个stream的缓冲长度
 func NewMemory(poolNum uint) *Memory {
 	return &Memory{
-		queue:   new(sync.Map),
-		PoolNum: poolNum,
+		queue:      new(sync.Map),
+		PoolNum:    poolNum,
+		clock:      clock.Real,
+		done:       make(chan struct{}),
+		maxBacklog: DefaultMaxBacklog,
+		pool: pool.New(pool.WithName("queue.memory"), pool.WithSize(DefaultMemoryWorkers),
+			pool.WithQueueSize(0)),
 	}
 }
 
 type Memory struct {
 	queue   *sync.Map
-	wait    sync.WaitGroup
+	done    chan struct{}
+	once    sync.Once
 	mutex   sync.RWMutex
 	PoolNum uint
+	clock   clock.Clock
+
+	// backlogs stream 缓冲已满时等待投递的消息
+	backlogs   sync.Map
+	maxBacklog int
+	// pool 运行消费协程与投递等待消息的协程
+	pool *pool.Pool
+
+	workerMux sync.Mutex
+	workers   map[string]*workers
+}
+
+// SetClock 设置消费失败后重试等待使用的时间源, 测试中使用 clock.Fake; 需在 Register 前调用
+func (m *Memory) SetClock(c clock.Clock) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.clock = c
+}
+
+// SetMaxBacklog 每个stream缓冲已满时最多等待投递的消息数, 超出后 Append 返回 ErrQueueFull; 默认 DefaultMaxBacklog
+func (m *Memory) SetMaxBacklog(n int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.maxBacklog = n
+}
+
+// SetPool 设置运行消费协程的协程池, 默认大小为 DefaultMemoryWorkers; 需在 Register 前调用
+func (m *Memory) SetPool(p *pool.Pool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.pool = p
+}
+
+// Stats 协程池的运行状态
+func (m *Memory) Stats() pool.Stats {
+	return m.pool.Stats()
 }
 
 func (*Memory) String() string {
@@ -43,7 +100,7 @@ func (m *Memory) Append(message storage.Messager) error {
 	memoryMessage := new(Message)
 	memoryMessage.SetID(message.GetID())
 	memoryMessage.SetStream(message.GetStream())
-	memoryMessage.SetValues(message.GetValues())
+	memoryMessage.SetValues(stamped(message.GetValues()))
 
 	v, ok := m.queue.Load(message.GetStream())
 
@@ -60,13 +117,119 @@ func (m *Memory) Append(message storage.Messager) error {
 		q = m.makeQueue()
 		m.queue.Store(message.GetStream(), q)
 	}
-	go func(gm storage.Messager, gq queue) {
-		gm.SetID(uuid.New().String())
-		gq <- gm
-	}(memoryMessage, q)
+	memoryMessage.SetID(uuid.New().String())
+	b := m.backlogOf(message.GetStream())
+	if b.len() == 0 {
+		select {
+		case q <- memoryMessage:
+			return nil
+		default:
+		}
+	}
+	// 缓冲已满时放入该 stream 的等待列表, 由一个协程按顺序投递, 不阻塞调用方, 也不影响其他 stream;
+	// 等待列表也满时返回 ErrQueueFull
+	if !b.push(memoryMessage, m.maxBacklog) {
+		return ErrQueueFull
+	}
+	m.drain(b, q)
 	return nil
 }
 
+// drain 在协程池中按顺序投递等待列表中的消息, 同一 stream 只有一个投递协程
+func (m *Memory) drain(b *backlog, q queue) {
+	if !b.start() {
+		return
+	}
+	if err := m.submit(func() { b.drain(q, m.done) }); err != nil {
+		b.stop()
+		logger.Module("storage.queue").Error("backlog drain not started", "queue", "memory", "error", err)
+	}
+}
+
+// submit 在协程池中运行, Shutdown 后不再等待空闲协程
+func (m *Memory) submit(f func()) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-m.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return m.pool.SubmitContext(ctx, f)
+}
+
+func (m *Memory) backlogOf(name string) *backlog {
+	v, _ := m.backlogs.LoadOrStore(name, new(backlog))
+	return v.(*backlog)
+}
+
+// backlog 一个 stream 等待投递的消息, 有消息时启动一个投递协程, 投递完后退出
+type backlog struct {
+	mux      sync.Mutex
+	messages []storage.Messager
+	running  bool
+}
+
+// start 没有投递协程时返回 true, 由调用方启动
+func (b *backlog) start() bool {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if b.running || len(b.messages) == 0 {
+		return false
+	}
+	b.running = true
+	return true
+}
+
+func (b *backlog) stop() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.running = false
+}
+
+func (b *backlog) len() int {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return len(b.messages)
+}
+
+// push max 大于0时限制等待的消息数, 已满时返回 false
+func (b *backlog) push(message storage.Messager, max int) bool {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if max > 0 && len(b.messages) >= max {
+		return false
+	}
+	b.messages = append(b.messages, message)
+	return true
+}
+
+// drain Shutdown 后退出, 未投递的消息丢弃
+func (b *backlog) drain(q queue, done chan struct{}) {
+	for {
+		b.mux.Lock()
+		if len(b.messages) == 0 {
+			b.running = false
+			b.mux.Unlock()
+			return
+		}
+		// 投递成功后才移出列表, 等待中的消息计入 Len
+		message := b.messages[0]
+		b.mux.Unlock()
+		select {
+		case q <- message:
+		case <-done:
+			return
+		}
+		b.mux.Lock()
+		b.messages[0] = nil
+		b.messages = b.messages[1:]
+		b.mux.Unlock()
+	}
+}
+
 func (m *Memory) Register(name string, f storage.ConsumerFunc) {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
@@ -83,29 +246,159 @@ func (m *Memory) Register(name string, f storage.ConsumerFunc) {
 		q = m.makeQueue()
 		m.queue.Store(name, q)
 	}
-	go func(out queue, gf storage.ConsumerFunc) {
-		var err error
-		for message := range q {
-			err = gf(message)
-			if err != nil {
+	m.workerMux.Lock()
+	defer m.workerMux.Unlock()
+	w := m.workersOf(name)
+	w.f = f
+	if stop := m.consume(name, q, f); stop != nil {
+		w.stops = append(w.stops, stop)
+	}
+}
+
+// workers 一个 stream 的消费协程, SetConcurrency 按最后注册的消费者增减
+type workers struct {
+	f     storage.ConsumerFunc
+	stops []chan struct{}
+}
+
+// workersOf 需持有 workerMux
+func (m *Memory) workersOf(name string) *workers {
+	if m.workers == nil {
+		m.workers = make(map[string]*workers)
+	}
+	w, ok := m.workers[name]
+	if !ok {
+		w = new(workers)
+		m.workers[name] = w
+	}
+	return w
+}
+
+// consume 在协程池中启动一个消费协程, 关闭返回的 chan 或 Shutdown 后在处理完当前消息时退出;
+// 协程池已满或已关闭时返回 nil
+func (m *Memory) consume(name string, q queue, f storage.ConsumerFunc) chan struct{} {
+	stop := make(chan struct{})
+	c := clock.OrReal(m.clock)
+	err := m.submit(func() {
+		for {
+			var message storage.Messager
+			select {
+			case <-stop:
+				return
+			case <-m.done:
+				return
+			case message = <-q:
+			}
+			if err := handle(f, message); err != nil {
+				log := logger.Module("storage.queue").WithContext(TraceContext(message)).
+					With("queue", "memory", "stream", name, "id", message.GetID())
 				if message.GetErrorCount() < 3 {
+					log.Warn("consume failed, retry", "retry", message.GetErrorCount()+1, "error", err)
 					message.SetErrorCount(message.GetErrorCount() + 1)
 					// 每次间隔时长放大
 					i := time.Second * time.Duration(message.GetErrorCount())
-					time.Sleep(i)
-					out <- message
+					c.Sleep(i)
+					// 经等待列表重新投递, 缓冲已满时不阻塞消费协程; 重试的消息不受等待列表长度限制
+					b := m.backlogOf(name)
+					b.push(message, 0)
+					m.drain(b, q)
+				} else {
+					log.Error("consume failed, message dropped", "error", err)
 				}
-				err = nil
 			}
 		}
-	}(q, f)
+	})
+	if err != nil {
+		logger.Module("storage.queue").Error("consumer not started", "queue", "memory", "stream", name, "error", err)
+		return nil
+	}
+	return stop
+}
+
+// handle 消费者 panic 时按消费失败处理, 消费协程不退出
+func handle(f storage.ConsumerFunc, message storage.Messager) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("consumer panic: %v", r)
+		}
+	}()
+	return f(message)
+}
+
+// Concurrency stream 的消费协程数量
+func (m *Memory) Concurrency(name string) int {
+	m.workerMux.Lock()
+	defer m.workerMux.Unlock()
+	if w, ok := m.workers[name]; ok {
+		return len(w.stops)
+	}
+	return 0
+}
+
+// SetConcurrency 调整 stream 的消费协程数量, 最少为1; stream 未注册消费者时不处理
+func (m *Memory) SetConcurrency(name string, n int) {
+	if n < 1 {
+		n = 1
+	}
+	v, ok := m.queue.Load(name)
+	if !ok {
+		return
+	}
+	q, _ := v.(queue)
+	m.workerMux.Lock()
+	defer m.workerMux.Unlock()
+	w, ok := m.workers[name]
+	if !ok || w.f == nil {
+		return
+	}
+	for len(w.stops) < n {
+		stop := m.consume(name, q, w.f)
+		if stop == nil {
+			break
+		}
+		w.stops = append(w.stops, stop)
+	}
+	for len(w.stops) > n {
+		close(w.stops[len(w.stops)-1])
+		w.stops = w.stops[:len(w.stops)-1]
+	}
 }
 
+// StreamLen stream 中等待消费的消息数量, 不含缓冲已满、等待投递的消息
+func (m *Memory) StreamLen(name string) int {
+	if v, ok := m.queue.Load(name); ok {
+		if q, ok := v.(queue); ok {
+			return len(q)
+		}
+	}
+	return 0
+}
+
+// Run 阻塞到 Shutdown, 并等待正在处理的消息完成; 消费者在 Register 时已开始消费
 func (m *Memory) Run() {
-	m.wait.Add(1)
-	m.wait.Wait()
+	<-m.done
+	_ = m.pool.Close(context.Background())
 }
 
+// Shutdown 可在 Run 之前调用, 多次调用只生效一次
 func (m *Memory) Shutdown() {
-	m.wait.Done()
+	m.once.Do(func() {
+		close(m.done)
+	})
+}
+
+// Len 所有stream中等待消费的消息数量, 包含缓冲已满、等待投递的消息
+func (m *Memory) Len() int {
+	var n int
+	m.queue.Range(func(_, v interface{}) bool {
+		if q, ok := v.(queue); ok {
+			n += len(q)
+		}
+		return true
+	})
+	m.backlogs.Range(func(_, v interface{}) bool {
+		n += v.(*backlog).len()
+		return true
+	})
+	return n
 }
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/tools/clock"
 )
 
 func TestMemory_Append(t *testing.T) {
@@ -105,3 +106,119 @@ func TestMemory_Register(t *testing.T) {
 		})
 	}
 }
+
+func TestMemory_Clock(t *testing.T) {
+	c := clock.NewFake(time.Now())
+	m := NewMemory(10)
+	m.SetClock(c)
+	calls := make(chan int, 3)
+	var n int
+	m.Register("clock", func(storage.Messager) error {
+		n++
+		calls <- n
+		if n == 1 {
+			return fmt.Errorf("failed")
+		}
+		return nil
+	})
+	message := new(Message)
+	message.SetStream("clock")
+	message.SetValues(map[string]interface{}{"key": "value"})
+	if err := m.Append(message); err != nil {
+		t.Fatal(err)
+	}
+	<-calls
+	for c.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-calls:
+		t.Fatal("retried before backoff elapsed")
+	case <-time.After(20 * time.Millisecond):
+	}
+	c.Advance(time.Second)
+	select {
+	case <-calls:
+	case <-time.After(time.Second):
+		t.Fatal("not retried after advance")
+	}
+}
+
+func TestMemory_Backlog(t *testing.T) {
+	m := NewMemory(0)
+	defer m.Shutdown()
+	// 没有消费者的 stream 积压不影响其他 stream
+	for i := 0; i < 2000; i++ {
+		msg := new(Message)
+		msg.SetStream("idle")
+		if err := m.Append(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := m.Len(); n != 2000 {
+		t.Errorf("len = %d", n)
+	}
+	done := make(chan string, 3)
+	m.Register("busy", func(msg storage.Messager) error {
+		done <- msg.GetValues()["n"].(string)
+		return nil
+	})
+	for _, n := range []string{"1", "2", "3"} {
+		msg := new(Message)
+		msg.SetStream("busy")
+		msg.SetValues(map[string]interface{}{"n": n})
+		_ = m.Append(msg)
+	}
+	for _, want := range []string{"1", "2", "3"} {
+		select {
+		case got := <-done:
+			if got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("busy stream blocked by idle stream")
+		}
+	}
+}
+
+func TestMemory_BacklogFull(t *testing.T) {
+	m := NewMemory(1)
+	defer m.Shutdown()
+	m.SetMaxBacklog(2)
+	var err error
+	for i := 0; i < 4 && err == nil; i++ {
+		msg := new(Message)
+		msg.SetStream("idle")
+		err = m.Append(msg)
+	}
+	// 缓冲1条, 等待列表2条
+	if err != ErrQueueFull {
+		t.Fatalf("err = %v", err)
+	}
+	if n := m.Len(); n != 3 {
+		t.Errorf("len = %d", n)
+	}
+
+	// 消费者在协程池中运行, panic 按消费失败重试
+	done := make(chan struct{})
+	var calls int
+	m.Register("panic", func(storage.Messager) error {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		close(done)
+		return nil
+	})
+	if s := m.Stats(); s.Workers == 0 {
+		t.Errorf("stats = %+v", s)
+	}
+	msg := new(Message)
+	msg.SetStream("panic")
+	_ = m.Append(msg)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not retried after panic")
+	}
+}
@@ -8,6 +8,8 @@
 package queue
 
 import (
+	"context"
+
 	"github.com/go-admin-team/go-admin-core/storage"
 	json "github.com/json-iterator/go"
 	"github.com/nsqio/go-nsq"
@@ -72,7 +74,7 @@ func (e *NSQ) newConsumer(topic string, h nsq.Handler) (err error) {
 
 // Append 消息入生产者
 func (e *NSQ) Append(message storage.Messager) error {
-	rb, err := json.Marshal(message.GetValues())
+	rb, err := json.Marshal(stamped(message.GetValues()))
 	if err != nil {
 		return err
 	}
@@ -115,3 +117,8 @@ func (e nsqConsumerHandler) HandleMessage(message *nsq.Message) error {
 	m.SetValues(data)
 	return e.f(m)
 }
+
+// Ping 检查nsqd连接
+func (e *NSQ) Ping(context.Context) error {
+	return e.producer.Ping()
+}
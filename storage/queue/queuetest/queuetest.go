@@ -0,0 +1,134 @@
+// Package queuetest 队列适配器的一致性测试, 第三方适配器在自己的测试中调用, 验证与内置实现的行为一致
+//
+//	func TestAdapter(t *testing.T) {
+//		queuetest.RunAdapterTests(t, func(t *testing.T) storage.AdapterQueue {
+//			return myqueue.New(...)
+//		})
+//	}
+package queuetest
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+// Factory 为每个子测试创建适配器, 子测试结束时调用 Shutdown
+type Factory func(t *testing.T) storage.AdapterQueue
+
+// Timeout 等待消息送达的时间
+var Timeout = 5 * time.Second
+
+// RunAdapterTests 执行全部一致性测试, 消费者在 Run 之前注册
+func RunAdapterTests(t *testing.T, factory Factory) {
+	prefix := "queuetest." + strconv.FormatInt(time.Now().UnixNano(), 36) + "."
+	t.Run("Delivery", func(t *testing.T) {
+		testDelivery(t, factory(t), prefix+"delivery")
+	})
+	t.Run("Streams", func(t *testing.T) {
+		testStreams(t, factory(t), prefix+"streams")
+	})
+	t.Run("Many", func(t *testing.T) {
+		testMany(t, factory(t), prefix+"many")
+	})
+}
+
+func run(t *testing.T, q storage.AdapterQueue) {
+	go q.Run()
+	t.Cleanup(q.Shutdown)
+}
+
+func send(t *testing.T, q storage.AdapterQueue, stream string, values map[string]interface{}) {
+	t.Helper()
+	m := new(queue.Message)
+	m.SetStream(stream)
+	m.SetValues(values)
+	if err := q.Append(m); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+}
+
+func testDelivery(t *testing.T, q storage.AdapterQueue, stream string) {
+	received := make(chan storage.Messager, 1)
+	q.Register(stream, func(m storage.Messager) error {
+		received <- m
+		return nil
+	})
+	run(t, q)
+	send(t, q, stream, map[string]interface{}{"name": "go-admin", "n": 1})
+	select {
+	case m := <-received:
+		// 经过网络的实现中值会变为字符串, 按字符串比较
+		if v := m.GetValues(); fmt.Sprint(v["name"]) != "go-admin" || fmt.Sprint(v["n"]) != "1" {
+			t.Errorf("values = %v", v)
+		}
+		if m.GetStream() != stream {
+			t.Errorf("stream = %s, want %s", m.GetStream(), stream)
+		}
+		if m.GetID() == "" {
+			t.Error("message id is empty")
+		}
+	case <-time.After(Timeout):
+		t.Fatal("message not delivered")
+	}
+}
+
+func testStreams(t *testing.T, q storage.AdapterQueue, stream string) {
+	a, b := make(chan string, 2), make(chan string, 2)
+	q.Register(stream+".a", func(m storage.Messager) error {
+		a <- fmt.Sprint(m.GetValues()["to"])
+		return nil
+	})
+	q.Register(stream+".b", func(m storage.Messager) error {
+		b <- fmt.Sprint(m.GetValues()["to"])
+		return nil
+	})
+	run(t, q)
+	send(t, q, stream+".b", map[string]interface{}{"to": "b"})
+	send(t, q, stream+".a", map[string]interface{}{"to": "a"})
+	for _, c := range []struct {
+		ch   chan string
+		want string
+	}{{a, "a"}, {b, "b"}} {
+		select {
+		case to := <-c.ch:
+			if to != c.want {
+				t.Errorf("consumer %s received message for %s", c.want, to)
+			}
+		case <-time.After(Timeout):
+			t.Fatalf("consumer %s not received", c.want)
+		}
+	}
+}
+
+func testMany(t *testing.T, q storage.AdapterQueue, stream string) {
+	const n = 20
+	var mux sync.Mutex
+	seen := make(map[string]bool)
+	done := make(chan struct{})
+	q.Register(stream, func(m storage.Messager) error {
+		mux.Lock()
+		defer mux.Unlock()
+		seen[fmt.Sprint(m.GetValues()["i"])] = true
+		if len(seen) == n {
+			close(done)
+		}
+		return nil
+	})
+	run(t, q)
+	for i := 0; i < n; i++ {
+		send(t, q, stream, map[string]interface{}{"i": i})
+	}
+	select {
+	case <-done:
+	case <-time.After(Timeout):
+		mux.Lock()
+		defer mux.Unlock()
+		t.Fatalf("delivered %d of %d messages", len(seen), n)
+	}
+}
@@ -0,0 +1,14 @@
+package queuetest
+
+import (
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+func TestMemory(t *testing.T) {
+	RunAdapterTests(t, func(t *testing.T) storage.AdapterQueue {
+		return queue.NewMemory(100)
+	})
+}
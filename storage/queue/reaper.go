@@ -0,0 +1,249 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bsm/redislock"
+	"github.com/go-redis/redis/v9"
+	"github.com/spf13/cast"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// DeadLetterSuffix 死信 stream 的后缀, 死信追加到 stream+DeadLetterSuffix
+const DeadLetterSuffix = ".dead"
+
+// 死信中记录的来源信息, DeliveriesKey 也记录在重新入队的消息中, 为累计的投递次数
+const (
+	SourceStreamKey = "__source_stream"
+	SourceIDKey     = "__source_id"
+	DeliveriesKey   = "__deliveries"
+)
+
+type ReaperOption func(*reaperOptions)
+
+type reaperOptions struct {
+	idle          time.Duration
+	interval      time.Duration
+	maxDeliveries int64
+	locker        storage.AdapterLocker
+	lockTTL       time.Duration
+}
+
+func setReaperDefault() reaperOptions {
+	return reaperOptions{
+		idle:          5 * time.Minute,
+		interval:      time.Minute,
+		maxDeliveries: 5,
+	}
+}
+
+// WithIdle 待确认超过该时间的消息视为卡住, 默认5分钟, 应大于消息的最长处理时间
+func WithIdle(d time.Duration) ReaperOption {
+	return func(o *reaperOptions) {
+		o.idle = d
+	}
+}
+
+// WithReapInterval 扫描间隔, 默认1分钟
+func WithReapInterval(d time.Duration) ReaperOption {
+	return func(o *reaperOptions) {
+		o.interval = d
+	}
+}
+
+// WithMaxDeliveries 累计投递次数(含之前重新入队前的次数)达到该值的消息转入死信, 否则重新入队, 默认5
+func WithMaxDeliveries(n int64) ReaperOption {
+	return func(o *reaperOptions) {
+		o.maxDeliveries = n
+	}
+}
+
+// WithLeader 多实例部署时通过锁选出一个实例扫描, 持有锁的实例每次扫描时续期; ttl 默认为扫描间隔的3倍
+func WithLeader(l storage.AdapterLocker, ttl time.Duration) ReaperOption {
+	return func(o *reaperOptions) {
+		o.locker = l
+		o.lockTTL = ttl
+	}
+}
+
+// ReaperStats 累计的扫描结果
+type ReaperStats struct {
+	Scans        int64
+	Reclaimed    int64
+	DeadLettered int64
+}
+
+// Reaper 定期扫描 redis stream 消费组中卡住的待确认消息: 消费者崩溃后其领取的消息不会再被处理,
+// 未超过投递次数的重新入队, 超过的转入死信; 可作为 runtime.Component 启动
+//
+//	r := queue.NewReaper(client, "go-admin", []string{"export"}, queue.WithLeader(locker, 0))
+//	sdk.Runtime.AddComponent(runtime.OrderQueue+1, r)
+type Reaper struct {
+	client  redis.UniversalClient
+	group   string
+	streams []string
+	o       reaperOptions
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	lock    *redislock.Lock
+
+	scans        int64
+	reclaimed    int64
+	deadLettered int64
+}
+
+func NewReaper(client redis.UniversalClient, group string, streams []string, opts ...ReaperOption) *Reaper {
+	o := setReaperDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.lockTTL <= 0 {
+		o.lockTTL = 3 * o.interval
+	}
+	return &Reaper{client: client, group: group, streams: streams, o: o}
+}
+
+func (r *Reaper) String() string {
+	return "queue.reaper"
+}
+
+// Start 开始定时扫描, 不阻塞
+func (r *Reaper) Start(context.Context) error {
+	r.stop = make(chan struct{})
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.o.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				if !r.leader() {
+					continue
+				}
+				if _, _, err := r.Reap(context.Background()); err != nil {
+					logger.Module("storage.queue").Error("reap pending messages failed", "group", r.group, "error", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop 停止扫描并释放锁
+func (r *Reaper) Stop(ctx context.Context) error {
+	if r.stop != nil {
+		close(r.stop)
+		r.wg.Wait()
+		r.stop = nil
+	}
+	if r.lock != nil {
+		_ = r.lock.Release(ctx)
+		r.lock = nil
+	}
+	return nil
+}
+
+// Stats 累计的扫描结果, 用于上报指标
+func (r *Reaper) Stats() ReaperStats {
+	return ReaperStats{
+		Scans:        atomic.LoadInt64(&r.scans),
+		Reclaimed:    atomic.LoadInt64(&r.reclaimed),
+		DeadLettered: atomic.LoadInt64(&r.deadLettered),
+	}
+}
+
+// Reap 扫描一次, 返回重新入队与转入死信的数量
+func (r *Reaper) Reap(ctx context.Context) (reclaimed, deadLettered int, err error) {
+	atomic.AddInt64(&r.scans, 1)
+	defer func() {
+		atomic.AddInt64(&r.reclaimed, int64(reclaimed))
+		atomic.AddInt64(&r.deadLettered, int64(deadLettered))
+		if reclaimed+deadLettered > 0 {
+			logger.Module("storage.queue").Warn("stuck pending messages reaped",
+				"group", r.group, "reclaimed", reclaimed, "dead_lettered", deadLettered)
+		}
+	}()
+	for _, stream := range r.streams {
+		for {
+			pending, err := r.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+				Stream: stream,
+				Group:  r.group,
+				Idle:   r.o.idle,
+				Start:  "-",
+				End:    "+",
+				Count:  handoverBatch,
+			}).Result()
+			if err != nil && err != redis.Nil {
+				return reclaimed, deadLettered, err
+			}
+			if len(pending) == 0 {
+				break
+			}
+			for _, p := range pending {
+				dead, err := r.reap(ctx, stream, p)
+				if err != nil {
+					return reclaimed, deadLettered, err
+				}
+				if dead {
+					deadLettered++
+				} else {
+					reclaimed++
+				}
+			}
+		}
+	}
+	return reclaimed, deadLettered, nil
+}
+
+// reap 重新入队会产生新的消息id, 投递次数从0开始, 因此累计的次数记录在 DeliveriesKey 中;
+// 累计达到上限时复制到死信 stream 并记录来源, 否则重新入队; 都会确认并删除原消息
+func (r *Reaper) reap(ctx context.Context, stream string, p redis.XPendingExt) (dead bool, err error) {
+	messages, err := r.client.XRangeN(ctx, stream, p.ID, p.ID, 1).Result()
+	if err != nil {
+		return false, err
+	}
+	deliveries := p.RetryCount
+	if len(messages) > 0 {
+		deliveries += cast.ToInt64(messages[0].Values[DeliveriesKey])
+	}
+	if deliveries >= r.o.maxDeliveries {
+		return true, move(ctx, r.client, stream, stream+DeadLetterSuffix, r.group, p.ID, messages, map[string]interface{}{
+			SourceStreamKey: stream,
+			SourceIDKey:     p.ID,
+			DeliveriesKey:   deliveries,
+		})
+	}
+	return false, move(ctx, r.client, stream, stream, r.group, p.ID, messages, map[string]interface{}{DeliveriesKey: deliveries})
+}
+
+// leader 未设置锁时总是扫描; 已持有锁时续期, 续期失败后重新竞争
+func (r *Reaper) leader() bool {
+	if r.o.locker == nil {
+		return true
+	}
+	ctx := context.Background()
+	if r.lock != nil {
+		if err := r.lock.Refresh(ctx, r.o.lockTTL, nil); err == nil {
+			return true
+		}
+		r.lock = nil
+	}
+	lock, err := r.o.locker.Lock("queue:reaper:"+r.group, int64(r.o.lockTTL/time.Second), nil)
+	if err != nil {
+		if !errors.Is(err, redislock.ErrNotObtained) {
+			logger.Module("storage.queue").Warn("reaper leader election failed", "group", r.group, "error", err)
+		}
+		return false
+	}
+	r.lock = lock
+	return true
+}
@@ -0,0 +1,121 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bsm/redislock"
+	"github.com/go-redis/redis/v9"
+)
+
+type busyLocker struct{}
+
+func (busyLocker) String() string { return "busy" }
+
+func (busyLocker) Lock(string, int64, *redislock.Options) (*redislock.Lock, error) {
+	return nil, redislock.ErrNotObtained
+}
+
+func TestReaper(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer client.Close()
+	ctx := context.Background()
+	_ = client.XGroupCreateMkStream(ctx, "orders", "g", "0").Err()
+	for _, id := range []string{"1", "2"} {
+		client.XAdd(ctx, &redis.XAddArgs{Stream: "orders", Values: map[string]interface{}{"id": id}})
+	}
+	read := func(consumer string) []redis.XMessage {
+		streams, _ := client.XReadGroup(ctx, &redis.XReadGroupArgs{Group: "g", Consumer: consumer, Streams: []string{"orders", ">"}, Count: 10}).Result()
+		if len(streams) == 0 {
+			return nil
+		}
+		return streams[0].Messages
+	}
+	claimed := read("crashed")
+	// 第二条已投递3次, 达到上限
+	client.XClaim(ctx, &redis.XClaimArgs{Stream: "orders", Group: "g", Consumer: "crashed", Messages: []string{claimed[1].ID}})
+	client.XClaim(ctx, &redis.XClaimArgs{Stream: "orders", Group: "g", Consumer: "crashed", Messages: []string{claimed[1].ID}})
+
+	r := NewReaper(client, "g", []string{"orders"}, WithIdle(time.Hour), WithMaxDeliveries(3))
+	if n, d, err := r.Reap(ctx); err != nil || n+d != 0 {
+		t.Fatalf("not idle yet: reclaimed = %d, dead = %d, err = %v", n, d, err)
+	}
+	r.o.idle = time.Millisecond
+	time.Sleep(5 * time.Millisecond)
+	if n, d, err := r.Reap(ctx); err != nil || n != 1 || d != 1 {
+		t.Fatalf("reclaimed = %d, dead = %d, err = %v", n, d, err)
+	}
+	if again := read("b"); len(again) != 1 || again[0].Values["id"] != "1" {
+		t.Errorf("reclaimed = %v", again)
+	}
+	dead, _ := client.XRange(ctx, "orders"+DeadLetterSuffix, "-", "+").Result()
+	if len(dead) != 1 || dead[0].Values["id"] != "2" || dead[0].Values[SourceIDKey] != claimed[1].ID || dead[0].Values[DeliveriesKey] != "3" {
+		t.Errorf("dead = %v", dead)
+	}
+	if st := r.Stats(); st.Scans != 2 || st.Reclaimed != 1 || st.DeadLettered != 1 {
+		t.Errorf("stats = %+v", st)
+	}
+
+	if NewReaper(client, "g", nil, WithLeader(busyLocker{}, 0)).leader() {
+		t.Error("should not lead without the lock")
+	}
+}
+
+func TestReaper_PoisonMessage(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer client.Close()
+	ctx := context.Background()
+	_ = client.XGroupCreateMkStream(ctx, "orders", "g", "0").Err()
+	client.XAdd(ctx, &redis.XAddArgs{Stream: "orders", Values: map[string]interface{}{"id": "1"}})
+
+	r := NewReaper(client, "g", []string{"orders"}, WithIdle(time.Millisecond), WithMaxDeliveries(3))
+	// 每次领取后消费者崩溃, 重新入队的消息id不同, 累计次数仍需达到上限
+	for i := 1; i <= 3; i++ {
+		streams, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{Group: "g", Consumer: "crashed", Streams: []string{"orders", ">"}, Count: 1}).Result()
+		if err != nil || len(streams) == 0 || len(streams[0].Messages) != 1 {
+			t.Fatalf("delivery %d: streams = %v, err = %v", i, streams, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+		n, d, err := r.Reap(ctx)
+		if err != nil || (i < 3 && n != 1) || (i == 3 && d != 1) {
+			t.Fatalf("delivery %d: reclaimed = %d, dead = %d, err = %v", i, n, d, err)
+		}
+	}
+	dead, _ := client.XRange(ctx, "orders"+DeadLetterSuffix, "-", "+").Result()
+	if len(dead) != 1 || dead[0].Values[DeliveriesKey] != "3" {
+		t.Errorf("dead = %v", dead)
+	}
+	if n, _ := client.XLen(ctx, "orders").Result(); n != 0 {
+		t.Errorf("orders len = %d", n)
+	}
+}
+
+func TestRedisDepths(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer client.Close()
+	ctx := context.Background()
+	_ = client.XGroupCreateMkStream(ctx, "orders", "g", "0").Err()
+	for _, id := range []string{"1", "2", "3"} {
+		client.XAdd(ctx, &redis.XAddArgs{Stream: "orders", Values: map[string]interface{}{"id": id}})
+	}
+	client.XReadGroup(ctx, &redis.XReadGroupArgs{Group: "g", Consumer: "a", Streams: []string{"orders", ">"}, Count: 1})
+	client.XAdd(ctx, &redis.XAddArgs{Stream: "orders" + DeadLetterSuffix, Values: map[string]interface{}{"id": "0"}})
+
+	r := &Redis{handover: client, group: "g", streams: []string{"orders"}}
+	depths, err := r.Depths(ctx)
+	if err != nil || len(depths) != 1 || depths[0].Length != 3 || depths[0].Pending != 1 {
+		t.Errorf("depths = %+v, err = %v", depths, err)
+	}
+	dead, err := r.DeadLetters(ctx, "orders", 10)
+	if err != nil || len(dead) != 1 {
+		t.Errorf("dead = %v, err = %v", dead, err)
+	}
+	if _, err = (&Redis{}).Depths(ctx); err != ErrHandoverUnsupported {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -1,6 +1,9 @@
 package queue
 
 import (
+	"context"
+
+	"github.com/go-admin-team/go-admin-core/logger"
 	"github.com/go-admin-team/go-admin-core/storage"
 	"github.com/go-admin-team/redisqueue/v2"
 	"github.com/go-redis/redis/v9"
@@ -21,6 +24,10 @@ func NewRedis(
 	if err != nil {
 		return nil, err
 	}
+	if consumerOptions != nil {
+		r.handover = consumerOptions.RedisClient
+		r.group, r.name = consumerOptions.GroupName, consumerOptions.Name
+	}
 	return r, nil
 }
 
@@ -29,6 +36,13 @@ type Redis struct {
 	client   *redis.Client
 	consumer *redisqueue.Consumer
 	producer *redisqueue.Producer
+
+	// handover 交还未确认消息使用的连接, 见 GracefulShutdown
+	handover redis.UniversalClient
+	group    string
+	name     string
+	// streams 已注册的stream, Register 在 Run 之前调用, 不需加锁
+	streams []string
 }
 
 func (Redis) String() string {
@@ -50,28 +64,50 @@ func (r *Redis) newProducer(options *redisqueue.ProducerOptions) (*redisqueue.Pr
 }
 
 func (r *Redis) Append(message storage.Messager) error {
-	err := r.producer.Enqueue(&redisqueue.Message{
+	values, err := EncodeValues(stamped(message.GetValues()))
+	if err != nil {
+		return err
+	}
+	return r.producer.Enqueue(&redisqueue.Message{
 		ID:     message.GetID(),
 		Stream: message.GetStream(),
-		Values: message.GetValues(),
+		Values: values,
 	})
-	return err
 }
 
 func (r *Redis) Register(name string, f storage.ConsumerFunc) {
+	r.streams = append(r.streams, name)
 	r.consumer.Register(name, func(message *redisqueue.Message) error {
 		m := new(Message)
 		m.SetValues(message.Values)
 		m.SetStream(message.Stream)
 		m.SetID(message.ID)
-		return f(m)
+		err := f(m)
+		if err != nil {
+			logger.Module("storage.queue").WithContext(TraceContext(m)).
+				Warn("consume failed", "queue", "redis", "stream", m.GetStream(), "id", m.GetID(), "error", err)
+		}
+		return err
 	})
 }
 
 func (r *Redis) Run() {
+	go func() {
+		for err := range r.consumer.Errors {
+			logger.Module("storage.queue").Error("redis queue consume failed", "error", err)
+		}
+	}()
 	r.consumer.Run()
 }
 
 func (r *Redis) Shutdown() {
 	r.consumer.Shutdown()
 }
+
+// DeleteStream 删除 stream, 如退出实例的应答 stream, 需通过 ConsumerOptions.RedisClient 传入连接
+func (r *Redis) DeleteStream(ctx context.Context, stream string) error {
+	if r.handover == nil {
+		return ErrHandoverUnsupported
+	}
+	return r.handover.Del(ctx, stream).Err()
+}
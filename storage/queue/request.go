@@ -0,0 +1,188 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// 请求与应答在 Values 中的key
+const (
+	ReplyToKey       = "__reply_to"
+	CorrelationIDKey = "__correlation_id"
+	ReplyErrorKey    = "__reply_error"
+)
+
+// DefaultRequestTimeout Request 的 timeout 小于等于0时使用
+const DefaultRequestTimeout = 30 * time.Second
+
+var (
+	ErrRequestTimeout = errors.New("queue: request timeout")
+	ErrNoReplyTo      = errors.New("queue: message has no reply-to stream")
+)
+
+// RemoteError 处理方返回的错误
+type RemoteError struct {
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return "queue: remote error: " + e.Message
+}
+
+// Requester 通过队列同步请求其他服务: 请求带上应答 stream 与关联id, 处理方用 Reply 或 RegisterReplier 应答;
+// 每个实例需使用自己的应答 stream, 默认为 queue.reply.实例id, 需在队列 Run 之前创建;
+// redis 队列中应答 stream 的长度由 ProducerOptions.StreamMaxLength 限制, 实例退出时调用 Close 删除
+//
+//	r := queue.NewRequester(q, "")
+//	reply, err := r.Request("user.lookup", map[string]interface{}{"id": 1}, 5*time.Second)
+type Requester struct {
+	q       storage.AdapterQueue
+	stream  string
+	mux     sync.Mutex
+	pending map[string]chan storage.Messager
+}
+
+// NewRequester stream 为空时使用 queue.reply.实例id, 见 SetInstance
+func NewRequester(q storage.AdapterQueue, stream string) *Requester {
+	if stream == "" {
+		stream = "queue.reply." + Instance()
+	}
+	r := &Requester{q: q, stream: stream, pending: make(map[string]chan storage.Messager)}
+	q.Register(stream, r.receive)
+	return r
+}
+
+// ReplyStream 应答 stream
+func (r *Requester) ReplyStream() string {
+	return r.stream
+}
+
+// Request 投递请求并等待应答, 超时返回 ErrRequestTimeout, 处理方返回错误时为 *RemoteError
+func (r *Requester) Request(stream string, values map[string]interface{}, timeout time.Duration) (storage.Messager, error) {
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return r.RequestContext(ctx, stream, values)
+}
+
+// RequestContext 同 Request, 等待时间由 ctx 决定, 并将 ctx 中的链路字段写入请求
+func (r *Requester) RequestContext(ctx context.Context, stream string, values map[string]interface{}) (storage.Messager, error) {
+	id := uuid.New().String()
+	ch := make(chan storage.Messager, 1)
+	r.mux.Lock()
+	r.pending[id] = ch
+	r.mux.Unlock()
+	defer func() {
+		r.mux.Lock()
+		delete(r.pending, id)
+		r.mux.Unlock()
+	}()
+
+	copied := make(map[string]interface{}, len(values)+2)
+	for k, v := range values {
+		copied[k] = v
+	}
+	copied[ReplyToKey] = r.stream
+	copied[CorrelationIDKey] = id
+	m := new(Message)
+	m.SetStream(stream)
+	m.SetValues(copied)
+	InjectTrace(ctx, m)
+	if err := r.q.Append(m); err != nil {
+		return nil, err
+	}
+
+	select {
+	case reply := <-ch:
+		if s, _ := reply.GetValues()[ReplyErrorKey].(string); s != "" {
+			return reply, &RemoteError{Message: s}
+		}
+		return reply, nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ErrRequestTimeout
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// Close 删除应答 stream, 在队列 Shutdown 之后调用; 内存队列不需要删除, 直接返回
+func (r *Requester) Close(ctx context.Context) error {
+	q := r.q
+	for {
+		if d, ok := q.(interface {
+			DeleteStream(ctx context.Context, stream string) error
+		}); ok {
+			return d.DeleteStream(ctx, r.stream)
+		}
+		u, ok := q.(interface{ Unwrap() storage.AdapterQueue })
+		if !ok || u.Unwrap() == nil {
+			return nil
+		}
+		q = u.Unwrap()
+	}
+}
+
+// receive 超时后才到达的应答直接丢弃
+func (r *Requester) receive(message storage.Messager) error {
+	id, _ := message.GetValues()[CorrelationIDKey].(string)
+	r.mux.Lock()
+	ch, ok := r.pending[id]
+	r.mux.Unlock()
+	if !ok {
+		logger.Module("storage.queue").WithContext(TraceContext(message)).
+			Warn("late or unknown reply dropped", "stream", r.stream, "correlation_id", id)
+		return nil
+	}
+	select {
+	case ch <- message:
+	default:
+	}
+	return nil
+}
+
+// Reply 应答请求, err 不为空时请求方收到 *RemoteError; 请求没有应答 stream 时返回 ErrNoReplyTo
+func Reply(q storage.AdapterQueue, request storage.Messager, values map[string]interface{}, err error) error {
+	replyTo, _ := request.GetValues()[ReplyToKey].(string)
+	if replyTo == "" {
+		return ErrNoReplyTo
+	}
+	copied := make(map[string]interface{}, len(values)+2)
+	for k, v := range values {
+		copied[k] = v
+	}
+	copied[CorrelationIDKey] = request.GetValues()[CorrelationIDKey]
+	if err != nil {
+		copied[ReplyErrorKey] = err.Error()
+	}
+	m := new(Message)
+	m.SetStream(replyTo)
+	m.SetValues(copied)
+	InjectTrace(TraceContext(request), m)
+	return q.Append(m)
+}
+
+// RegisterReplier 注册处理请求的消费者, 返回值作为应答; 处理出错时应答错误, 不再重试;
+// 应答投递失败时记录后丢弃, 不重新处理请求, 请求方等待超时
+func RegisterReplier(q storage.AdapterQueue, stream string, f func(request storage.Messager) (map[string]interface{}, error)) {
+	q.Register(stream, func(request storage.Messager) error {
+		values, err := f(request)
+		log := logger.Module("storage.queue").WithContext(TraceContext(request))
+		switch err = Reply(q, request, values, err); {
+		case err == ErrNoReplyTo:
+			log.Warn("request without reply-to ignored", "stream", stream, "id", request.GetID())
+		case err != nil:
+			log.Error("reply dropped", "stream", stream, "id", request.GetID(), "error", err)
+		}
+		return nil
+	})
+}
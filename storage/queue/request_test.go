@@ -0,0 +1,88 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+func TestRequester(t *testing.T) {
+	q := NewMemory(10)
+	RegisterReplier(q, "user.lookup", func(m storage.Messager) (map[string]interface{}, error) {
+		id, _ := Int64(m, "id")
+		if id == 0 {
+			return nil, errors.New("user not found")
+		}
+		return map[string]interface{}{"name": "tom"}, nil
+	})
+	q.Register("slow", func(storage.Messager) error { return nil })
+	r := NewRequester(q, "")
+	go q.Run()
+	defer q.Shutdown()
+
+	reply, err := r.Request("user.lookup", map[string]interface{}{"id": 1}, 5*time.Second)
+	if err != nil || String(reply, "name") != "tom" || HopOf(reply).ParentID == "" {
+		t.Fatalf("reply = %v, err = %v", reply, err)
+	}
+	_, err = r.Request("user.lookup", map[string]interface{}{"id": 0}, 5*time.Second)
+	if re, ok := err.(*RemoteError); !ok || re.Message != "user not found" {
+		t.Errorf("remote err = %v", err)
+	}
+	if _, err = r.Request("slow", nil, 50*time.Millisecond); err != ErrRequestTimeout {
+		t.Errorf("timeout err = %v", err)
+	}
+	if err = Reply(q, new(Message), nil, nil); err != ErrNoReplyTo {
+		t.Errorf("reply without reply-to: err = %v", err)
+	}
+}
+
+// replyQueue 记录注册的消费者, 投递应答失败
+type replyQueue struct {
+	storage.AdapterQueue
+	f storage.ConsumerFunc
+}
+
+func (q *replyQueue) Register(_ string, f storage.ConsumerFunc) {
+	q.f = f
+}
+
+func (q *replyQueue) Append(storage.Messager) error {
+	return errors.New("append failed")
+}
+
+func TestReplierAppendFailed(t *testing.T) {
+	q := &replyQueue{}
+	calls := 0
+	RegisterReplier(q, "user.lookup", func(storage.Messager) (map[string]interface{}, error) {
+		calls++
+		return nil, nil
+	})
+	m := new(Message)
+	m.SetValues(map[string]interface{}{ReplyToKey: "queue.reply.1", CorrelationIDKey: "1"})
+	// 应答失败不返回错误, 队列不会重新处理请求
+	if err := q.f(m); err != nil || calls != 1 {
+		t.Errorf("err = %v, calls = %d", err, calls)
+	}
+}
+
+func TestRequesterClose(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	_ = client.XAdd(context.Background(), &redis.XAddArgs{Stream: "queue.reply.1", Values: map[string]interface{}{"a": "1"}}).Err()
+	r := &Requester{q: &Redis{handover: client}, stream: "queue.reply.1"}
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if s.Exists("queue.reply.1") {
+		t.Error("reply stream not deleted")
+	}
+	if err := (&Requester{q: NewMemory(0)}).Close(context.Background()); err != nil {
+		t.Errorf("memory: err = %v", err)
+	}
+}
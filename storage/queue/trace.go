@@ -0,0 +1,131 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// 链路字段在消息 Values 中的key, 同 storage.PrefixKey
+const (
+	RequestIDKey  = "__request_id"
+	TraceIDKey    = "__trace_id"
+	OperatorIDKey = "__operator_id"
+)
+
+// 消息因果链的字段在 Values 中的key: 消息id在投递时生成, 重试时不变, 与队列分配的id不同;
+// 父消息id为消费者处理中投递的消息所属的消息
+const (
+	MessageIDKey  = "__message_id"
+	ParentIDKey   = "__parent_id"
+	ProducerKey   = "__producer"
+	EnqueuedAtKey = "__enqueued_at"
+)
+
+var instance atomic.Value
+
+func init() {
+	host, _ := os.Hostname()
+	instance.Store(fmt.Sprintf("%s-%d", host, os.Getpid()))
+}
+
+// SetInstance 设置写入消息的生产者实例id, 默认为 主机名-进程id
+func SetInstance(id string) {
+	instance.Store(id)
+}
+
+// Instance 生产者实例id
+func Instance() string {
+	return instance.Load().(string)
+}
+
+type parentKey struct{}
+
+// WithParent 设置父消息id, 之后 InjectTrace 的消息带上该id; TraceContext 已自动设置
+func WithParent(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, parentKey{}, id)
+}
+
+// ParentID ctx中的父消息id, 不在消费者中时为空
+func ParentID(ctx context.Context) string {
+	id, _ := ctx.Value(parentKey{}).(string)
+	return id
+}
+
+// InjectTrace 将ctx中的请求id、链路id、操作人id写入消息, 消费端用 TraceContext 取回;
+// 同时生成消息id, 在消费者中调用时写入父消息id
+func InjectTrace(ctx context.Context, message storage.Messager) {
+	fields := map[string]string{
+		RequestIDKey:  logger.RequestID(ctx),
+		TraceIDKey:    logger.TraceID(ctx),
+		OperatorIDKey: logger.OperatorID(ctx),
+		ParentIDKey:   ParentID(ctx),
+	}
+	values := message.GetValues()
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+	for k, v := range fields {
+		if v != "" {
+			values[k] = v
+		}
+	}
+	if id, _ := values[MessageIDKey].(string); id == "" {
+		values[MessageIDKey] = uuid.New().String()
+	}
+	message.SetValues(values)
+}
+
+// Stamp 补全消息id、生产者与投递时间, 已有的字段不覆盖; 复制 Values 后写入, 不修改调用方的 map,
+// 内存、redis 与 nsq 队列投递时自动调用
+func Stamp(message storage.Messager) {
+	message.SetValues(stamped(message.GetValues()))
+}
+
+func stamped(values map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(values)+3)
+	for k, v := range values {
+		copied[k] = v
+	}
+	if id, _ := copied[MessageIDKey].(string); id == "" {
+		copied[MessageIDKey] = uuid.New().String()
+	}
+	if _, ok := copied[ProducerKey]; !ok {
+		copied[ProducerKey] = Instance()
+	}
+	if _, ok := copied[EnqueuedAtKey]; !ok {
+		copied[EnqueuedAtKey] = strconv.FormatInt(time.Now().UnixMilli(), 10)
+	}
+	return copied
+}
+
+// TraceContext 返回带有消息链路字段的ctx, 可直接用于 logger.S().WithContext; 消息id作为父消息id,
+// 用该ctx InjectTrace 的消息记录为当前消息的后续
+func TraceContext(message storage.Messager) context.Context {
+	ctx := context.Background()
+	values := message.GetValues()
+	if values == nil {
+		return ctx
+	}
+	if id, _ := values[RequestIDKey].(string); id != "" {
+		ctx = logger.WithRequestID(ctx, id)
+	}
+	if id, _ := values[TraceIDKey].(string); id != "" {
+		ctx = logger.WithTraceID(ctx, id)
+	}
+	if id, _ := values[OperatorIDKey].(string); id != "" {
+		ctx = logger.WithOperatorID(ctx, id)
+	}
+	if id, _ := values[MessageIDKey].(string); id != "" {
+		ctx = WithParent(ctx, id)
+	}
+	return ctx
+}
@@ -0,0 +1,28 @@
+package queue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+func TestTraceContext(t *testing.T) {
+	ctx := logger.WithRequestID(context.Background(), "r1")
+	ctx = logger.WithOperatorID(ctx, "7")
+
+	m := &Message{}
+	m.SetValues(map[string]interface{}{"key": "value"})
+	InjectTrace(ctx, m)
+
+	if _, ok := m.GetValues()[TraceIDKey]; ok {
+		t.Errorf("empty trace id should not be injected")
+	}
+	got := TraceContext(m)
+	if logger.RequestID(got) != "r1" || logger.OperatorID(got) != "7" {
+		t.Errorf("unexpected trace fields: %v", logger.TraceFields(got))
+	}
+	if m.GetValues()["key"] != "value" {
+		t.Errorf("values should be kept")
+	}
+}
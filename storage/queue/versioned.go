@@ -0,0 +1,126 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// VersionKey 消息格式版本在 Values 中的key, 未设置时视为版本1
+const VersionKey = "__version"
+
+var (
+	ErrUnknownVersion = errors.New("queue: unknown message version")
+	ErrSchema         = errors.New("queue: message does not match schema")
+)
+
+// SetVersion 设置消息格式版本
+func SetVersion(message storage.Messager, version int) {
+	values := message.GetValues()
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+	values[VersionKey] = strconv.Itoa(version)
+	message.SetValues(values)
+}
+
+// Version 消息格式版本, 未设置或无法解析时为1
+func Version(message storage.Messager) int {
+	switch v := message.GetValues()[VersionKey].(type) {
+	case string:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 1
+}
+
+// RegisterVersioned 按消息版本分发到对应的处理函数; 没有对应版本时返回 ErrUnknownVersion,
+// 消息按队列规则重试, 滚动发布期间旧实例收到的新格式消息可由新实例重新消费
+//
+//	queue.RegisterVersioned(q, "user.created", map[int]storage.ConsumerFunc{
+//		1: handleV1,
+//		2: handleV2,
+//	})
+func RegisterVersioned(q storage.AdapterQueue, name string, handlers map[int]storage.ConsumerFunc) {
+	q.Register(name, func(message storage.Messager) error {
+		v := Version(message)
+		f, ok := handlers[v]
+		if !ok {
+			return fmt.Errorf("%w: %s v%d", ErrUnknownVersion, name, v)
+		}
+		return f(message)
+	})
+}
+
+// Schema 一个版本的消息格式, 只校验必填字段
+type Schema struct {
+	Stream  string
+	Version int
+	Fields  []string
+}
+
+var (
+	schemaMux sync.RWMutex
+	schemas   = make(map[string]map[int]Schema)
+)
+
+// RegisterSchema 登记 stream 某个版本的必填字段, 在 init 中调用
+func RegisterSchema(stream string, version int, fields ...string) {
+	schemaMux.Lock()
+	defer schemaMux.Unlock()
+	if schemas[stream] == nil {
+		schemas[stream] = make(map[int]Schema)
+	}
+	schemas[stream][version] = Schema{Stream: stream, Version: version, Fields: fields}
+}
+
+// Schemas 已登记的格式, 按 stream、版本排序
+func Schemas() []Schema {
+	schemaMux.RLock()
+	defer schemaMux.RUnlock()
+	var list []Schema
+	for _, versions := range schemas {
+		for _, s := range versions {
+			list = append(list, s)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Stream != list[j].Stream {
+			return list[i].Stream < list[j].Stream
+		}
+		return list[i].Version < list[j].Version
+	})
+	return list
+}
+
+// NewVersioned 创建带版本的消息, 该版本登记了格式时校验必填字段
+func NewVersioned(stream string, version int, values map[string]interface{}) (*Message, error) {
+	schemaMux.RLock()
+	s, ok := schemas[stream][version]
+	schemaMux.RUnlock()
+	if ok {
+		for _, f := range s.Fields {
+			if _, exists := values[f]; !exists {
+				return nil, fmt.Errorf("%w: %s v%d missing %s", ErrSchema, stream, version, f)
+			}
+		}
+	}
+	copied := make(map[string]interface{}, len(values)+1)
+	for k, v := range values {
+		copied[k] = v
+	}
+	m := new(Message)
+	m.SetStream(stream)
+	m.SetValues(copied)
+	SetVersion(m, version)
+	return m, nil
+}
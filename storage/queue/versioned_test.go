@@ -0,0 +1,50 @@
+package queue
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+type fakeQueue struct {
+	storage.AdapterQueue
+	consumers map[string]storage.ConsumerFunc
+}
+
+func (q *fakeQueue) Register(name string, f storage.ConsumerFunc) {
+	q.consumers[name] = f
+}
+
+func TestVersioned(t *testing.T) {
+	RegisterSchema("user.created", 2, "id", "username")
+	if _, err := NewVersioned("user.created", 2, map[string]interface{}{"id": "1"}); !errors.Is(err, ErrSchema) {
+		t.Errorf("err = %v", err)
+	}
+	v2, err := NewVersioned("user.created", 2, map[string]interface{}{"id": "1", "username": "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1 := new(Message)
+	v1.SetValues(map[string]interface{}{"id": "1"})
+
+	q := &fakeQueue{consumers: make(map[string]storage.ConsumerFunc)}
+	var got []int
+	RegisterVersioned(q, "user.created", map[int]storage.ConsumerFunc{
+		1: func(storage.Messager) error { got = append(got, 1); return nil },
+		2: func(storage.Messager) error { got = append(got, 2); return nil },
+	})
+	f := q.consumers["user.created"]
+	_ = f(v1)
+	_ = f(v2)
+	SetVersion(v2, 3)
+	if err = f(v2); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("err = %v", err)
+	}
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("dispatched = %v", got)
+	}
+	if s := Schemas(); len(s) != 1 || s[0].Version != 2 {
+		t.Errorf("schemas = %+v", s)
+	}
+}
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NewMemory 内存模式, 只适用于单实例
+func NewMemory() *Memory {
+	return &Memory{
+		items: make(map[string]time.Time),
+	}
+}
+
+type Memory struct {
+	mutex sync.Mutex
+	items map[string]time.Time
+	calls int
+}
+
+func (*Memory) String() string {
+	return "memory"
+}
+
+func (m *Memory) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.calls++
+	if m.calls%1000 == 0 {
+		m.clean(now)
+	}
+	res, tat := gcra(now, m.items[key], limit)
+	m.items[key] = tat
+	return res, nil
+}
+
+// clean 删除已恢复满额度的key
+func (m *Memory) clean(now time.Time) {
+	for k, tat := range m.items {
+		if !tat.After(now) {
+			delete(m.items, k)
+		}
+	}
+}
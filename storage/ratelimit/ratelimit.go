@@ -0,0 +1,74 @@
+// Package ratelimit GCRA 限流, 提供内存与redis两种实现
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit 每 Period 允许 Rate 次请求, 允许 Rate 次的突发
+type Limit struct {
+	Rate   int
+	Period time.Duration
+}
+
+// PerSecond 每秒 rate 次
+func PerSecond(rate int) Limit {
+	return Limit{Rate: rate, Period: time.Second}
+}
+
+// PerMinute 每分钟 rate 次
+func PerMinute(rate int) Limit {
+	return Limit{Rate: rate, Period: time.Minute}
+}
+
+// IsZero 未设置限制
+func (l Limit) IsZero() bool {
+	return l.Rate <= 0 || l.Period <= 0
+}
+
+func (l Limit) emission() time.Duration {
+	return l.Period / time.Duration(l.Rate)
+}
+
+// Result 限流结果
+type Result struct {
+	Limit   Limit
+	Allowed bool
+	// Remaining 剩余可用次数
+	Remaining int
+	// RetryAfter 被限流时下一次允许请求的等待时间
+	RetryAfter time.Duration
+	// ResetAfter 恢复到满额度的时间
+	ResetAfter time.Duration
+}
+
+// Limiter 限流器
+type Limiter interface {
+	String() string
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// gcra 根据上次的理论到达时间(tat)计算本次请求的结果与新的tat
+func gcra(now, tat time.Time, limit Limit) (Result, time.Time) {
+	emission := limit.emission()
+	burst := time.Duration(limit.Rate) * emission
+	if tat.Before(now) {
+		tat = now
+	}
+	newTat := tat.Add(emission)
+	allowAt := newTat.Add(-burst)
+	if now.Before(allowAt) {
+		return Result{
+			Limit:      limit,
+			RetryAfter: allowAt.Sub(now),
+			ResetAfter: tat.Sub(now),
+		}, tat
+	}
+	return Result{
+		Limit:      limit,
+		Allowed:    true,
+		Remaining:  int(now.Sub(allowAt) / emission),
+		ResetAfter: newTat.Sub(now),
+	}, newTat
+}
@@ -0,0 +1,29 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemory_Allow(t *testing.T) {
+	m := NewMemory()
+	limit := Limit{Rate: 3, Period: 300 * time.Millisecond}
+	for i := 2; i >= 0; i-- {
+		res, _ := m.Allow(context.TODO(), "ip:1", limit)
+		if !res.Allowed || res.Remaining != i {
+			t.Fatalf("Allow() = %+v, want remaining %d", res, i)
+		}
+	}
+	res, _ := m.Allow(context.TODO(), "ip:1", limit)
+	if res.Allowed || res.RetryAfter <= 0 || res.RetryAfter > 100*time.Millisecond {
+		t.Fatalf("Allow() = %+v, want limited", res)
+	}
+	if res, _ = m.Allow(context.TODO(), "ip:2", limit); !res.Allowed {
+		t.Error("keys should be limited separately")
+	}
+	time.Sleep(110 * time.Millisecond)
+	if res, _ = m.Allow(context.TODO(), "ip:1", limit); !res.Allowed {
+		t.Errorf("Allow() = %+v, want allowed after one emission interval", res)
+	}
+}
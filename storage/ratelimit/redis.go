@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+// gcraScript 与 gcra 相同的算法, 时间单位为微秒
+var gcraScript = cache.RegisterScript("ratelimit.gcra", `
+local emission = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2]) * emission
+local now = tonumber(ARGV[3])
+local tat = tonumber(redis.call("GET", KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+local new_tat = tat + emission
+local allow_at = new_tat - burst
+if now < allow_at then
+	return {0, 0, allow_at - now, tat - now}
+end
+redis.call("SET", KEYS[1], new_tat, "PX", math.ceil((new_tat - now) / 1000))
+return {1, math.floor((now - allow_at) / emission), 0, new_tat - now}
+`)
+
+// NewRedis redis模式, 多实例共享额度
+func NewRedis(client *redis.Client) *Redis {
+	return &Redis{client: client}
+}
+
+type Redis struct {
+	client *redis.Client
+}
+
+func (*Redis) String() string {
+	return "redis"
+}
+
+func (r *Redis) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	values, err := gcraScript.Run(ctx, r.client, []string{key},
+		limit.emission().Microseconds(), limit.Rate, time.Now().UnixMicro()).Int64Slice()
+	if err != nil {
+		return Result{Limit: limit}, err
+	}
+	return Result{
+		Limit:      limit,
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+		ResetAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// CacheFactory 根据配置构造缓存适配器
+type CacheFactory func(options map[string]interface{}) (AdapterCache, error)
+
+// QueueFactory 根据配置构造队列适配器
+type QueueFactory func(options map[string]interface{}) (AdapterQueue, error)
+
+// LockerFactory 根据配置构造分布式锁适配器
+type LockerFactory func(options map[string]interface{}) (AdapterLocker, error)
+
+var (
+	registryMutex sync.RWMutex
+	caches        = make(map[string]CacheFactory)
+	queues        = make(map[string]QueueFactory)
+	lockers       = make(map[string]LockerFactory)
+)
+
+// RegisterCache 注册缓存驱动，重复注册会覆盖之前的实现
+func RegisterCache(driver string, f CacheFactory) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	caches[driver] = f
+}
+
+// RegisterQueue 注册队列驱动，重复注册会覆盖之前的实现
+func RegisterQueue(driver string, f QueueFactory) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	queues[driver] = f
+}
+
+// RegisterLocker 注册分布式锁驱动，重复注册会覆盖之前的实现
+func RegisterLocker(driver string, f LockerFactory) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	lockers[driver] = f
+}
+
+// NewCache 根据驱动名称构造缓存适配器
+func NewCache(driver string, options map[string]interface{}) (AdapterCache, error) {
+	registryMutex.RLock()
+	f, ok := caches[driver]
+	registryMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("cache driver %q not registered", driver)
+	}
+	return f(options)
+}
+
+// NewQueue 根据驱动名称构造队列适配器
+func NewQueue(driver string, options map[string]interface{}) (AdapterQueue, error) {
+	registryMutex.RLock()
+	f, ok := queues[driver]
+	registryMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("queue driver %q not registered", driver)
+	}
+	return f(options)
+}
+
+// NewLocker 根据驱动名称构造分布式锁适配器
+func NewLocker(driver string, options map[string]interface{}) (AdapterLocker, error) {
+	registryMutex.RLock()
+	f, ok := lockers[driver]
+	registryMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("locker driver %q not registered", driver)
+	}
+	return f(options)
+}
+
+// CacheDrivers 已注册的缓存驱动
+func CacheDrivers() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	return sortedKeys(caches)
+}
+
+// QueueDrivers 已注册的队列驱动
+func QueueDrivers() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	return sortedKeys(queues)
+}
+
+// LockerDrivers 已注册的分布式锁驱动
+func LockerDrivers() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	return sortedKeys(lockers)
+}
+
+// DecodeOptions 将驱动配置解析到具体的结构体，供驱动实现使用
+func DecodeOptions(options map[string]interface{}, v interface{}) error {
+	if len(options) == 0 {
+		return nil
+	}
+	rb, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(rb, v)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+type testCache struct {
+	AdapterCache
+	addr string
+}
+
+func (*testCache) String() string {
+	return "test"
+}
+
+func TestRegisterCache(t *testing.T) {
+	RegisterCache("test", func(options map[string]interface{}) (AdapterCache, error) {
+		o := struct {
+			Addr string `json:"addr"`
+		}{}
+		if err := DecodeOptions(options, &o); err != nil {
+			return nil, err
+		}
+		return &testCache{addr: o.Addr}, nil
+	})
+	c, err := NewCache("test", map[string]interface{}{"addr": "127.0.0.1:11211"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.String() != "test" || c.(*testCache).addr != "127.0.0.1:11211" {
+		t.Errorf("NewCache() got = %v", c)
+	}
+	if _, err = NewCache("unknown", nil); err == nil {
+		t.Error("NewCache() want error for unregistered driver")
+	}
+	found := false
+	for _, d := range CacheDrivers() {
+		if d == "test" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("CacheDrivers() = %v, want contains test", CacheDrivers())
+	}
+}
+
+func TestDecodeOptions(t *testing.T) {
+	o := struct {
+		PoolSize int           `json:"pool_size"`
+		Timeout  time.Duration `json:"timeout"`
+	}{}
+	if err := DecodeOptions(map[string]interface{}{"pool_size": 10, "timeout": 5}, &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.PoolSize != 10 || o.Timeout != 5 {
+		t.Errorf("DecodeOptions() got = %+v", o)
+	}
+}
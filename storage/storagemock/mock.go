@@ -0,0 +1,147 @@
+// Package storagemock 单元测试使用的缓存、队列、锁适配器: 函数字段的 mock 用于模拟任意返回值,
+// Recorder 记录全部操作并提供断言, 应用的测试不需要启动 redis
+//
+//	c := storagemock.RecordCache(nil)
+//	svc := NewService(c)
+//	svc.Refresh(ctx)
+//	c.AssertSet(t, "menu:tree")
+package storagemock
+
+import (
+	"time"
+
+	"github.com/bsm/redislock"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+)
+
+// Cache 缓存 mock, 未设置的方法返回零值
+type Cache struct {
+	GetFunc      func(key string) (string, error)
+	SetFunc      func(key string, val interface{}, expire int) error
+	DelFunc      func(key string) error
+	HashGetFunc  func(hk, key string) (string, error)
+	HashDelFunc  func(hk, key string) error
+	IncreaseFunc func(key string) error
+	DecreaseFunc func(key string) error
+	ExpireFunc   func(key string, dur time.Duration) error
+}
+
+var _ storage.AdapterCache = (*Cache)(nil)
+
+func (*Cache) String() string {
+	return "mock"
+}
+
+func (m *Cache) Get(key string) (string, error) {
+	if m.GetFunc == nil {
+		return "", nil
+	}
+	return m.GetFunc(key)
+}
+
+func (m *Cache) Set(key string, val interface{}, expire int) error {
+	if m.SetFunc == nil {
+		return nil
+	}
+	return m.SetFunc(key, val, expire)
+}
+
+func (m *Cache) Del(key string) error {
+	if m.DelFunc == nil {
+		return nil
+	}
+	return m.DelFunc(key)
+}
+
+func (m *Cache) HashGet(hk, key string) (string, error) {
+	if m.HashGetFunc == nil {
+		return "", nil
+	}
+	return m.HashGetFunc(hk, key)
+}
+
+func (m *Cache) HashDel(hk, key string) error {
+	if m.HashDelFunc == nil {
+		return nil
+	}
+	return m.HashDelFunc(hk, key)
+}
+
+func (m *Cache) Increase(key string) error {
+	if m.IncreaseFunc == nil {
+		return nil
+	}
+	return m.IncreaseFunc(key)
+}
+
+func (m *Cache) Decrease(key string) error {
+	if m.DecreaseFunc == nil {
+		return nil
+	}
+	return m.DecreaseFunc(key)
+}
+
+func (m *Cache) Expire(key string, dur time.Duration) error {
+	if m.ExpireFunc == nil {
+		return nil
+	}
+	return m.ExpireFunc(key, dur)
+}
+
+// Queue 队列 mock, 未设置的方法不执行任何操作
+type Queue struct {
+	AppendFunc   func(message storage.Messager) error
+	RegisterFunc func(name string, f storage.ConsumerFunc)
+	RunFunc      func()
+	ShutdownFunc func()
+}
+
+var _ storage.AdapterQueue = (*Queue)(nil)
+
+func (*Queue) String() string {
+	return "mock"
+}
+
+func (m *Queue) Append(message storage.Messager) error {
+	if m.AppendFunc == nil {
+		return nil
+	}
+	return m.AppendFunc(message)
+}
+
+func (m *Queue) Register(name string, f storage.ConsumerFunc) {
+	if m.RegisterFunc != nil {
+		m.RegisterFunc(name, f)
+	}
+}
+
+func (m *Queue) Run() {
+	if m.RunFunc != nil {
+		m.RunFunc()
+	}
+}
+
+func (m *Queue) Shutdown() {
+	if m.ShutdownFunc != nil {
+		m.ShutdownFunc()
+	}
+}
+
+// Locker 锁 mock, 未设置 LockFunc 时总是加锁成功并返回 nil 的锁
+type Locker struct {
+	LockFunc func(key string, ttl int64, options *redislock.Options) (*redislock.Lock, error)
+}
+
+var _ storage.AdapterLocker = (*Locker)(nil)
+
+func (*Locker) String() string {
+	return "mock"
+}
+
+func (m *Locker) Lock(key string, ttl int64, options *redislock.Options) (*redislock.Lock, error) {
+	if m.LockFunc == nil {
+		return nil, nil
+	}
+	return m.LockFunc(key, ttl, options)
+}
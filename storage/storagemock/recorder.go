@@ -0,0 +1,289 @@
+package storagemock
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+)
+
+// Op 一次缓存操作
+type Op struct {
+	Method string
+	Key    string
+	// Field HashGet、HashSet、HashDel 的字段
+	Field  string
+	Value  string
+	Expire time.Duration
+	Err    error
+}
+
+// CacheRecorder 记录缓存操作, 操作转发给内部的缓存
+type CacheRecorder struct {
+	c   storage.AdapterCache
+	mux sync.Mutex
+	ops []Op
+}
+
+var _ storage.AdapterCache = (*CacheRecorder)(nil)
+
+// RecordCache 记录 c 的操作, c 为 nil 时使用内存缓存
+func RecordCache(c storage.AdapterCache) *CacheRecorder {
+	if c == nil {
+		c = cache.NewMemory()
+	}
+	return &CacheRecorder{c: c}
+}
+
+func (r *CacheRecorder) record(op Op) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.ops = append(r.ops, op)
+}
+
+// Ops 按顺序的全部操作
+func (r *CacheRecorder) Ops() []Op {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return append([]Op(nil), r.ops...)
+}
+
+// Reset 清空记录, 不影响缓存中的数据
+func (r *CacheRecorder) Reset() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.ops = nil
+}
+
+func (r *CacheRecorder) find(method, key string) (Op, bool) {
+	ops := r.Ops()
+	for i := len(ops) - 1; i >= 0; i-- {
+		if ops[i].Method == method && ops[i].Key == key {
+			return ops[i], true
+		}
+	}
+	return Op{}, false
+}
+
+// AssertSet 断言 key 被 Set 过, 返回最后一次 Set 的记录
+func (r *CacheRecorder) AssertSet(t testing.TB, key string) Op {
+	t.Helper()
+	op, ok := r.find("Set", key)
+	if !ok {
+		t.Errorf("storagemock: expected Set(%q), got %s", key, r.summary())
+	}
+	return op
+}
+
+// AssertNotSet 断言 key 没有被 Set 过
+func (r *CacheRecorder) AssertNotSet(t testing.TB, key string) {
+	t.Helper()
+	if op, ok := r.find("Set", key); ok {
+		t.Errorf("storagemock: unexpected Set(%q, %q)", key, op.Value)
+	}
+}
+
+// AssertDeleted 断言 key 被 Del 过
+func (r *CacheRecorder) AssertDeleted(t testing.TB, key string) {
+	t.Helper()
+	if _, ok := r.find("Del", key); !ok {
+		t.Errorf("storagemock: expected Del(%q), got %s", key, r.summary())
+	}
+}
+
+func (r *CacheRecorder) summary() string {
+	ops := r.Ops()
+	list := make([]string, 0, len(ops))
+	for _, op := range ops {
+		list = append(list, op.Method+"("+op.Key+")")
+	}
+	return fmt.Sprint(list)
+}
+
+func (r *CacheRecorder) String() string {
+	return r.c.String()
+}
+
+func (r *CacheRecorder) Get(key string) (string, error) {
+	v, err := r.c.Get(key)
+	r.record(Op{Method: "Get", Key: key, Value: v, Err: err})
+	return v, err
+}
+
+func (r *CacheRecorder) Set(key string, val interface{}, expire int) error {
+	err := r.c.Set(key, val, expire)
+	r.record(Op{Method: "Set", Key: key, Value: fmt.Sprint(val), Expire: time.Duration(expire) * time.Second, Err: err})
+	return err
+}
+
+func (r *CacheRecorder) Del(key string) error {
+	err := r.c.Del(key)
+	r.record(Op{Method: "Del", Key: key, Err: err})
+	return err
+}
+
+func (r *CacheRecorder) HashGet(hk, key string) (string, error) {
+	v, err := r.c.HashGet(hk, key)
+	r.record(Op{Method: "HashGet", Key: hk, Field: key, Value: v, Err: err})
+	return v, err
+}
+
+func (r *CacheRecorder) HashDel(hk, key string) error {
+	err := r.c.HashDel(hk, key)
+	r.record(Op{Method: "HashDel", Key: hk, Field: key, Err: err})
+	return err
+}
+
+func (r *CacheRecorder) Increase(key string) error {
+	err := r.c.Increase(key)
+	r.record(Op{Method: "Increase", Key: key, Err: err})
+	return err
+}
+
+func (r *CacheRecorder) Decrease(key string) error {
+	err := r.c.Decrease(key)
+	r.record(Op{Method: "Decrease", Key: key, Err: err})
+	return err
+}
+
+func (r *CacheRecorder) Expire(key string, dur time.Duration) error {
+	err := r.c.Expire(key, dur)
+	r.record(Op{Method: "Expire", Key: key, Expire: dur, Err: err})
+	return err
+}
+
+func (r *CacheRecorder) GetTouch(key string, ttl time.Duration) (string, error) {
+	v, err := cache.GetTouch(r.c, key, ttl)
+	r.record(Op{Method: "GetTouch", Key: key, Value: v, Expire: ttl, Err: err})
+	return v, err
+}
+
+func (r *CacheRecorder) SetNX(key string, val interface{}, expire int) (bool, error) {
+	ok, err := cache.SetNX(r.c, key, val, expire)
+	r.record(Op{Method: "SetNX", Key: key, Value: fmt.Sprint(val), Expire: time.Duration(expire) * time.Second, Err: err})
+	return ok, err
+}
+
+func (r *CacheRecorder) CompareAndSwap(key, old string, val interface{}, expire int) (bool, error) {
+	ok, err := cache.CompareAndSwap(r.c, key, old, val, expire)
+	r.record(Op{Method: "CompareAndSwap", Key: key, Value: fmt.Sprint(val), Expire: time.Duration(expire) * time.Second, Err: err})
+	return ok, err
+}
+
+func (r *CacheRecorder) HashSet(hk, key string, val interface{}) error {
+	err := cache.HashSet(r.c, hk, key, val)
+	r.record(Op{Method: "HashSet", Key: hk, Field: key, Value: fmt.Sprint(val), Err: err})
+	return err
+}
+
+func (r *CacheRecorder) HashGetAll(hk string) (map[string]string, error) {
+	v, err := cache.HashGetAll(r.c, hk)
+	r.record(Op{Method: "HashGetAll", Key: hk, Err: err})
+	return v, err
+}
+
+func (r *CacheRecorder) Keys(prefix string) ([]string, error) {
+	keys, err := cache.Keys(r.c, prefix)
+	r.record(Op{Method: "Keys", Key: prefix, Err: err})
+	return keys, err
+}
+
+// QueueRecorder 记录投递的消息; 内部队列为 nil 时在 Append 中同步调用该 stream 的消费者
+type QueueRecorder struct {
+	q         storage.AdapterQueue
+	mux       sync.Mutex
+	messages  []storage.Messager
+	consumers map[string]storage.ConsumerFunc
+}
+
+var _ storage.AdapterQueue = (*QueueRecorder)(nil)
+
+// RecordQueue 记录 q 的投递, q 为 nil 时同步消费, 测试不需要等待
+func RecordQueue(q storage.AdapterQueue) *QueueRecorder {
+	return &QueueRecorder{q: q, consumers: make(map[string]storage.ConsumerFunc)}
+}
+
+// Messages stream 中投递的消息, stream 为空时返回全部
+func (r *QueueRecorder) Messages(stream string) []storage.Messager {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	var list []storage.Messager
+	for _, m := range r.messages {
+		if stream == "" || m.GetStream() == stream {
+			list = append(list, m)
+		}
+	}
+	return list
+}
+
+// Reset 清空记录
+func (r *QueueRecorder) Reset() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.messages = nil
+}
+
+// AssertEnqueued 断言 stream 中有投递, 返回最后一条消息
+func (r *QueueRecorder) AssertEnqueued(t testing.TB, stream string) storage.Messager {
+	t.Helper()
+	list := r.Messages(stream)
+	if len(list) == 0 {
+		t.Errorf("storagemock: expected message in stream %q, got %d messages in other streams", stream, len(r.Messages("")))
+		return nil
+	}
+	return list[len(list)-1]
+}
+
+// AssertNotEnqueued 断言 stream 中没有投递
+func (r *QueueRecorder) AssertNotEnqueued(t testing.TB, stream string) {
+	t.Helper()
+	if n := len(r.Messages(stream)); n > 0 {
+		t.Errorf("storagemock: unexpected %d messages in stream %q", n, stream)
+	}
+}
+
+func (r *QueueRecorder) String() string {
+	if r.q == nil {
+		return "recorder"
+	}
+	return r.q.String()
+}
+
+func (r *QueueRecorder) Append(message storage.Messager) error {
+	r.mux.Lock()
+	r.messages = append(r.messages, message)
+	f := r.consumers[message.GetStream()]
+	r.mux.Unlock()
+	if r.q != nil {
+		return r.q.Append(message)
+	}
+	if f != nil {
+		return f(message)
+	}
+	return nil
+}
+
+func (r *QueueRecorder) Register(name string, f storage.ConsumerFunc) {
+	if r.q != nil {
+		r.q.Register(name, f)
+		return
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.consumers[name] = f
+}
+
+func (r *QueueRecorder) Run() {
+	if r.q != nil {
+		r.q.Run()
+	}
+}
+
+func (r *QueueRecorder) Shutdown() {
+	if r.q != nil {
+		r.q.Shutdown()
+	}
+}
@@ -0,0 +1,61 @@
+package storagemock
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+func TestCacheRecorder(t *testing.T) {
+	c := RecordCache(nil)
+	_ = c.Set("menu:tree", "[]", 60)
+	_ = c.Del("menu:role")
+	if v, _ := c.Get("menu:tree"); v != "[]" {
+		t.Errorf("get = %q", v)
+	}
+	if op := c.AssertSet(t, "menu:tree"); op.Value != "[]" || op.Expire.Seconds() != 60 {
+		t.Errorf("op = %+v", op)
+	}
+	c.AssertDeleted(t, "menu:role")
+	c.AssertNotSet(t, "menu:role")
+
+	var failed testing.T
+	c.Reset()
+	c.AssertSet(&failed, "menu:tree")
+	if !failed.Failed() || len(c.Ops()) != 0 {
+		t.Error("AssertSet should fail after Reset")
+	}
+}
+
+func TestQueueRecorder(t *testing.T) {
+	q := RecordQueue(nil)
+	var got string
+	q.Register("user.created", func(m storage.Messager) error {
+		got = m.GetValues()["name"].(string)
+		return nil
+	})
+	m := new(queue.Message)
+	m.SetStream("user.created")
+	m.SetValues(map[string]interface{}{"name": "admin"})
+	_ = q.Append(m)
+	if got != "admin" {
+		t.Error("consumer should be called synchronously")
+	}
+	if msg := q.AssertEnqueued(t, "user.created"); msg == nil || msg.GetValues()["name"] != "admin" {
+		t.Errorf("message = %v", msg)
+	}
+	q.AssertNotEnqueued(t, "user.deleted")
+}
+
+func TestMock(t *testing.T) {
+	boom := errors.New("boom")
+	c := &Cache{GetFunc: func(string) (string, error) { return "", boom }}
+	if _, err := c.Get("a"); err != boom || c.Set("a", 1, 1) != nil {
+		t.Error("cache mock")
+	}
+	if _, err := (&Locker{}).Lock("a", 1, nil); err != nil {
+		t.Error("locker mock")
+	}
+}
@@ -0,0 +1,72 @@
+// Package testsupport 集成测试使用的 redis: 启动进程内的 miniredis 并创建 redis 的缓存、队列与锁适配器,
+// 测试结束时通过 t.Cleanup 自动关闭
+//
+//	r := testsupport.NewRedis(t)
+//	svc := NewService(r.Cache, r.Locker)
+//	r.Server.FastForward(time.Minute) // 推进过期时间
+package testsupport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-admin-team/redisqueue/v2"
+	"github.com/go-redis/redis/v9"
+
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+	"github.com/go-admin-team/go-admin-core/storage/locker"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+// Redis miniredis 与连接到它的适配器
+type Redis struct {
+	// Server 可用于直接读写数据、FastForward 推进过期时间
+	Server *miniredis.Miniredis
+	Client *redis.Client
+	Cache  *cache.Redis
+	Queue  *queue.Redis
+	Locker *locker.Redis
+}
+
+// NewRedis 启动 miniredis, 失败时终止测试; 队列需注册消费者后调用 RunQueue
+func NewRedis(t testing.TB) *Redis {
+	t.Helper()
+	s := miniredis.NewMiniRedis()
+	if err := s.Start(); err != nil {
+		t.Fatalf("testsupport: start miniredis: %v", err)
+	}
+	t.Cleanup(s.Close)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	r := &Redis{Server: s, Client: client, Locker: locker.NewRedis(client)}
+	var err error
+	if r.Cache, err = cache.NewRedis(client, nil); err != nil {
+		t.Fatalf("testsupport: redis cache: %v", err)
+	}
+	r.Queue, err = queue.NewRedis(
+		&redisqueue.ProducerOptions{StreamMaxLength: 1000, ApproximateMaxLength: true, RedisClient: client},
+		&redisqueue.ConsumerOptions{
+			Name:              "testsupport",
+			GroupName:         "testsupport",
+			VisibilityTimeout: time.Second,
+			BlockingTimeout:   100 * time.Millisecond,
+			ReclaimInterval:   time.Second,
+			BufferSize:        100,
+			Concurrency:       4,
+			RedisClient:       client,
+		},
+	)
+	if err != nil {
+		t.Fatalf("testsupport: redis queue: %v", err)
+	}
+	return r
+}
+
+// RunQueue 在后台开始消费, 测试结束时停止; 需在注册全部消费者之后调用
+func (r *Redis) RunQueue(t testing.TB) {
+	go r.Queue.Run()
+	t.Cleanup(r.Queue.Shutdown)
+}
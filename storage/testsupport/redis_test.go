@@ -0,0 +1,21 @@
+package testsupport
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewRedis(t *testing.T) {
+	r := NewRedis(t)
+	if err := r.Cache.Set("a", 1, 60); err != nil {
+		t.Fatal(err)
+	}
+	_ = r.Cache.Increase("a")
+	if v, _ := r.Server.Get("a"); v != "2" {
+		t.Errorf("server value = %q", v)
+	}
+	r.Server.FastForward(time.Minute)
+	if v, _ := r.Cache.Get("a"); v != "" {
+		t.Errorf("value after expiry = %q", v)
+	}
+}
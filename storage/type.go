@@ -22,6 +22,47 @@ type AdapterCache interface {
 	Expire(key string, dur time.Duration) error
 }
 
+// AdapterHashCache 支持写入字段与一次读取整个hash的缓存, 见 cache.HashReader
+type AdapterHashCache interface {
+	HashSet(hk, key string, val interface{}) error
+	HashGetAll(hk string) (map[string]string, error)
+}
+
+// AdapterKeysCache 支持按前缀列出key的缓存, 见 namespace.Cache.FlushNamespace
+type AdapterKeysCache interface {
+	Keys(prefix string) ([]string, error)
+}
+
+// AdapterTouchCache 读取时同时延长有效期的缓存, 见 cache.GetTouch
+type AdapterTouchCache interface {
+	GetTouch(key string, ttl time.Duration) (string, error)
+}
+
+// AdapterSetNXCache key 不存在时才写入的缓存, 判断与写入是原子的, 见 cache.SetNX
+type AdapterSetNXCache interface {
+	SetNX(key string, val interface{}, expire int) (bool, error)
+}
+
+// AdapterCASCache 支持比较后写入的缓存, 见 cache.CompareAndSwap
+type AdapterCASCache interface {
+	CompareAndSwap(key, old string, val interface{}, expire int) (bool, error)
+}
+
+// Pipeliner 事务中的写操作, 在 Tx 的函数返回后一起执行
+type Pipeliner interface {
+	Set(key string, val interface{}, expire int)
+	Del(key string)
+	HashDel(hk, key string)
+	Increase(key string)
+	Decrease(key string)
+	Expire(key string, dur time.Duration)
+}
+
+// AdapterTxCache 支持事务的缓存, 多个key的写入全部成功或全部不执行, 见 cache.Tx
+type AdapterTxCache interface {
+	Tx(f func(p Pipeliner) error) error
+}
+
 type AdapterQueue interface {
 	String() string
 	Append(message Messager) error
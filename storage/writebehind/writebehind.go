@@ -0,0 +1,211 @@
+// Package writebehind 缓存的异步持久化: 指定前缀的 key 在 Set、Del 写入缓存后同时投递到队列,
+// 由消费者写入数据库等持久存储, 请求路径不等待持久化
+//
+//	c := writebehind.New(cache, q, writebehind.WithNamespace("setting:"))
+//	writebehind.Register(q, "", func(ctx context.Context, e writebehind.Entry) error {
+//		return saveSetting(ctx, e.Key, e.Value, e.Deleted)
+//	})
+package writebehind
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/storage"
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+	"github.com/go-admin-team/go-admin-core/storage/queue"
+)
+
+// DefaultStream 持久化消息的队列
+const DefaultStream = "cache.writebehind"
+
+type Option func(*options)
+
+type options struct {
+	stream     string
+	namespaces []string
+}
+
+func setDefault() options {
+	return options{
+		stream: DefaultStream,
+	}
+}
+
+// WithStream 投递的队列, 默认 DefaultStream
+func WithStream(stream string) Option {
+	return func(o *options) {
+		o.stream = stream
+	}
+}
+
+// WithNamespace 需要持久化的 key 前缀, 可多次调用; 未设置时全部 key 都持久化
+func WithNamespace(prefixes ...string) Option {
+	return func(o *options) {
+		o.namespaces = append(o.namespaces, prefixes...)
+	}
+}
+
+// Entry 一次写入, 消费者按 Time 丢弃比已持久化的数据更旧的写入
+type Entry struct {
+	Key     string
+	Value   string
+	Expire  int
+	Deleted bool
+	Time    time.Time
+}
+
+// Cache 写入缓存后投递持久化消息, 其他操作直接调用内部缓存
+type Cache struct {
+	storage.AdapterCache
+	q storage.AdapterQueue
+	o options
+}
+
+var _ storage.AdapterCache = (*Cache)(nil)
+
+func New(c storage.AdapterCache, q storage.AdapterQueue, opts ...Option) *Cache {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Cache{AdapterCache: c, q: q, o: o}
+}
+
+// Unwrap 内部缓存
+func (c *Cache) Unwrap() storage.AdapterCache {
+	return c.AdapterCache
+}
+
+// Set 写入缓存成功后投递, 投递失败时返回错误, 缓存中的值保留
+func (c *Cache) Set(key string, val interface{}, expire int) error {
+	if err := c.AdapterCache.Set(key, val, expire); err != nil {
+		return err
+	}
+	return c.persist(key, val, expire)
+}
+
+// Del 删除缓存成功后投递
+func (c *Cache) Del(key string) error {
+	if err := c.AdapterCache.Del(key); err != nil {
+		return err
+	}
+	if !c.match(key) {
+		return nil
+	}
+	return c.append(Entry{Key: key, Deleted: true, Time: time.Now()})
+}
+
+// SetNX 写入成功后投递
+func (c *Cache) SetNX(key string, val interface{}, expire int) (bool, error) {
+	ok, err := cache.SetNX(c.AdapterCache, key, val, expire)
+	if err != nil || !ok {
+		return ok, err
+	}
+	return true, c.persist(key, val, expire)
+}
+
+// CompareAndSwap 写入成功后投递
+func (c *Cache) CompareAndSwap(key, old string, val interface{}, expire int) (bool, error) {
+	ok, err := cache.CompareAndSwap(c.AdapterCache, key, old, val, expire)
+	if err != nil || !ok {
+		return ok, err
+	}
+	return true, c.persist(key, val, expire)
+}
+
+// 以下方法不修改普通的 key, 直接转发给内部缓存
+
+func (c *Cache) GetTouch(key string, ttl time.Duration) (string, error) {
+	return cache.GetTouch(c.AdapterCache, key, ttl)
+}
+
+func (c *Cache) HashSet(hk, key string, val interface{}) error {
+	return cache.HashSet(c.AdapterCache, hk, key, val)
+}
+
+func (c *Cache) HashGetAll(hk string) (map[string]string, error) {
+	return cache.HashGetAll(c.AdapterCache, hk)
+}
+
+func (c *Cache) Keys(prefix string) ([]string, error) {
+	return cache.Keys(c.AdapterCache, prefix)
+}
+
+func (c *Cache) persist(key string, val interface{}, expire int) error {
+	if !c.match(key) {
+		return nil
+	}
+	v, err := cast.ToStringE(val)
+	if err != nil {
+		return err
+	}
+	return c.append(Entry{Key: key, Value: v, Expire: expire, Time: time.Now()})
+}
+
+func (c *Cache) match(key string) bool {
+	if len(c.o.namespaces) == 0 {
+		return true
+	}
+	for _, p := range c.o.namespaces {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Cache) append(e Entry) error {
+	m := new(queue.Message)
+	m.SetStream(c.o.stream)
+	m.SetValues(map[string]interface{}{
+		"key":     e.Key,
+		"value":   e.Value,
+		"expire":  strconv.Itoa(e.Expire),
+		"deleted": strconv.FormatBool(e.Deleted),
+		"time":    strconv.FormatInt(e.Time.UnixNano(), 10),
+	})
+	return c.q.Append(m)
+}
+
+// Register 注册持久化消费者, stream 为空时使用 DefaultStream; f 返回错误时按队列的规则重试
+func Register(q storage.AdapterQueue, stream string, f func(ctx context.Context, e Entry) error) {
+	if stream == "" {
+		stream = DefaultStream
+	}
+	q.Register(stream, func(m storage.Messager) error {
+		e, err := decode(m.GetValues())
+		if err != nil {
+			// 无法解析的消息重试也不会成功, 记录后丢弃
+			logger.Module("storage.writebehind").Error("invalid message", "stream", stream, "id", m.GetID(), "error", err)
+			return nil
+		}
+		return f(queue.TraceContext(m), e)
+	})
+}
+
+func decode(values map[string]interface{}) (Entry, error) {
+	s := func(k string) string {
+		v, _ := values[k].(string)
+		return v
+	}
+	e := Entry{Key: s("key"), Value: s("value")}
+	var err error
+	if e.Expire, err = strconv.Atoi(s("expire")); err != nil {
+		return e, err
+	}
+	if e.Deleted, err = strconv.ParseBool(s("deleted")); err != nil {
+		return e, err
+	}
+	ns, err := strconv.ParseInt(s("time"), 10, 64)
+	if err != nil {
+		return e, err
+	}
+	e.Time = time.Unix(0, ns)
+	return e, nil
+}
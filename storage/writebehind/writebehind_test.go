@@ -0,0 +1,35 @@
+package writebehind
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-admin-team/go-admin-core/storage/cache"
+	"github.com/go-admin-team/go-admin-core/storage/storagemock"
+)
+
+func TestCache(t *testing.T) {
+	q := storagemock.RecordQueue(nil)
+	var entries []Entry
+	Register(q, "", func(_ context.Context, e Entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	c := New(cache.NewMemory(), q, WithNamespace("setting:"))
+	_ = c.Set("setting:site_name", "go-admin", 60)
+	_ = c.Set("captcha:1", "1234", 60)
+	_ = c.Del("setting:site_name")
+
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if e := entries[0]; e.Key != "setting:site_name" || e.Value != "go-admin" || e.Expire != 60 || e.Deleted || e.Time.IsZero() {
+		t.Errorf("set entry = %+v", e)
+	}
+	if e := entries[1]; !e.Deleted || e.Time.Before(entries[0].Time) {
+		t.Errorf("del entry = %+v", e)
+	}
+	if v, _ := c.Get("captcha:1"); v != "1234" {
+		t.Errorf("get = %q", v)
+	}
+}
@@ -0,0 +1,117 @@
+// Package buildinfo 构建信息, 通过 -ldflags 注入, 未注入时从 go 1.18 写入二进制的 vcs 信息中读取
+//
+//	go build -ldflags "-X github.com/go-admin-team/go-admin-core/tools/buildinfo.Version=v1.0.0 \
+//		-X github.com/go-admin-team/go-admin-core/tools/buildinfo.Commit=$(git rev-parse --short HEAD) \
+//		-X github.com/go-admin-team/go-admin-core/tools/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package buildinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// 通过 -ldflags 注入
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Info 构建信息
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"buildTime,omitempty"`
+	// Modified 构建时工作区有未提交的修改, 只在从 vcs 信息读取时有效
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+}
+
+var (
+	vcsOnce sync.Once
+	vcs     Info
+)
+
+// readVCS 读取 go build 写入的模块版本与 vcs 信息
+func readVCS() Info {
+	vcsOnce.Do(func() {
+		bi, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		if v := bi.Main.Version; v != "" && v != "(devel)" {
+			vcs.Version = v
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				vcs.Commit = s.Value
+			case "vcs.time":
+				vcs.BuildTime = s.Value
+			case "vcs.modified":
+				vcs.Modified = s.Value == "true"
+			}
+		}
+	})
+	return vcs
+}
+
+// Get 当前构建信息, ldflags 注入的优先
+func Get() Info {
+	i := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	v := readVCS()
+	if (i.Version == "" || i.Version == "dev") && v.Version != "" {
+		i.Version = v.Version
+	}
+	if i.Commit == "" {
+		i.Commit, i.Modified = v.Commit, v.Modified
+	}
+	if i.BuildTime == "" {
+		i.BuildTime = v.BuildTime
+	}
+	return i
+}
+
+// String 如 v1.0.0 (commit 1a2b3c4, built 2022-10-20T08:00:00Z, go1.18 linux/amd64)
+func (i Info) String() string {
+	s := i.Version + " ("
+	if i.Commit != "" {
+		s += "commit " + i.Commit
+		if i.Modified {
+			s += "-dirty"
+		}
+		s += ", "
+	}
+	if i.BuildTime != "" {
+		s += "built " + i.BuildTime + ", "
+	}
+	return s + i.GoVersion + " " + i.Platform + ")"
+}
+
+// Fields 日志字段, 如 logger.Info("server starting", buildinfo.Get().Fields()...)
+func (i Info) Fields() []interface{} {
+	return []interface{}{
+		"version", i.Version,
+		"commit", i.Commit,
+		"build_time", i.BuildTime,
+		"go_version", i.GoVersion,
+	}
+}
+
+// Handler /version 接口, 返回 Info 的json
+func Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_ = json.NewEncoder(w).Encode(Get())
+	}
+}
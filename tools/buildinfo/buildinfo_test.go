@@ -0,0 +1,32 @@
+package buildinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	defer func(v, c, b string) { Version, Commit, BuildTime = v, c, b }(Version, Commit, BuildTime)
+	Version, Commit, BuildTime = "v1.2.3", "abc1234", "2022-10-20T08:00:00Z"
+	i := Get()
+	if i.Version != "v1.2.3" || i.Commit != "abc1234" || i.GoVersion != runtime.Version() {
+		t.Errorf("info = %+v", i)
+	}
+	if s := i.String(); !strings.HasPrefix(s, "v1.2.3 (commit abc1234, built 2022-10-20T08:00:00Z, go") {
+		t.Errorf("string = %s", s)
+	}
+	if f := i.Fields(); len(f) != 8 || f[1] != "v1.2.3" {
+		t.Errorf("fields = %v", f)
+	}
+
+	w := httptest.NewRecorder()
+	Handler()(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+	got := Info{}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil || got != i {
+		t.Errorf("handler = %s, err = %v", w.Body.String(), err)
+	}
+}
@@ -0,0 +1,127 @@
+// Package clock 可替换的时间源, 正式代码使用 Real, 测试中使用 Fake 手动推进时间, 使过期、延迟等逻辑可确定地测试
+//
+//	c := clock.NewFake(time.Now())
+//	m := cache.NewMemory()
+//	m.SetClock(c)
+//	c.Advance(time.Minute)
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock 时间源
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+}
+
+// Real 系统时间
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// OrReal c 为 nil 时返回 Real
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return Real
+	}
+	return c
+}
+
+type waiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// Fake 手动推进的时间源, Sleep 与 After 在 Advance 到期后返回
+type Fake struct {
+	mux     sync.Mutex
+	now     time.Time
+	waiters []waiter
+}
+
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return f.now
+}
+
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+func (f *Fake) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	ch := make(chan time.Time, 1)
+	at := f.now.Add(d)
+	if d <= 0 {
+		ch <- f.now
+		return ch
+	}
+	f.waiters = append(f.waiters, waiter{at: at, ch: ch})
+	return ch
+}
+
+// Advance 推进时间, 唤醒到期的 Sleep 与 After
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set 设置当前时间, 不能早于当前时间
+func (f *Fake) Set(t time.Time) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	if t.Before(f.now) {
+		return
+	}
+	f.now = t
+	sort.Slice(f.waiters, func(i, j int) bool {
+		return f.waiters[i].at.Before(f.waiters[j].at)
+	})
+	n := 0
+	for _, w := range f.waiters {
+		if w.at.After(t) {
+			break
+		}
+		w.ch <- t
+		n++
+	}
+	f.waiters = f.waiters[n:]
+}
+
+// Waiters 等待中的 Sleep 与 After 数量, 测试中用于确认协程已进入等待
+func (f *Fake) Waiters() int {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return len(f.waiters)
+}
@@ -0,0 +1,39 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+	done := make(chan struct{})
+	go func() {
+		c.Sleep(time.Minute)
+		close(done)
+	}()
+	after := c.After(2 * time.Minute)
+	for c.Waiters() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	c.Advance(30 * time.Second)
+	select {
+	case <-done:
+		t.Fatal("sleep returned early")
+	default:
+	}
+	c.Advance(30 * time.Second)
+	<-done
+	if c.Since(start) != time.Minute || c.Waiters() != 1 {
+		t.Errorf("since = %s, waiters = %d", c.Since(start), c.Waiters())
+	}
+	c.Set(start)
+	if !c.Now().Equal(start.Add(time.Minute)) {
+		t.Error("time should not go backwards")
+	}
+	c.Advance(time.Hour)
+	if at := <-after; !at.Equal(start.Add(61 * time.Minute)) {
+		t.Errorf("after = %s", at)
+	}
+}
@@ -0,0 +1,91 @@
+// Package crypto 常用加解密与签名: AES-GCM、RSA 以及国密 SM2/SM3/SM4, 和 gorm 字段加密
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+
+	"github.com/tjfoc/gmsm/sm4"
+)
+
+var ErrCiphertext = errors.New("crypto: ciphertext too short")
+
+// Cipher 对称加密, 密文为 nonce+密文+tag
+type Cipher interface {
+	String() string
+	Encrypt(plain []byte) ([]byte, error)
+	Decrypt(data []byte) ([]byte, error)
+}
+
+type gcm struct {
+	name string
+	aead cipher.AEAD
+}
+
+// NewAESGCM AES-GCM, key 长度为16、24或32字节
+func NewAESGCM(key []byte) (Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return newGCM("aes-gcm", block)
+}
+
+// NewSM4GCM SM4-GCM (GB/T 32907), key 长度为16字节
+func NewSM4GCM(key []byte) (Cipher, error) {
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return newGCM("sm4-gcm", block)
+}
+
+func newGCM(name string, block cipher.Block) (Cipher, error) {
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &gcm{name: name, aead: aead}, nil
+}
+
+func (e *gcm) String() string {
+	return e.name
+}
+
+func (e *gcm) Encrypt(plain []byte) ([]byte, error) {
+	nonce := make([]byte, e.aead.NonceSize(), e.aead.NonceSize()+len(plain)+e.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return e.aead.Seal(nonce, nonce, plain, nil), nil
+}
+
+func (e *gcm) Decrypt(data []byte) ([]byte, error) {
+	n := e.aead.NonceSize()
+	if len(data) < n+e.aead.Overhead() {
+		return nil, ErrCiphertext
+	}
+	return e.aead.Open(nil, data[:n], data[n:], nil)
+}
+
+// EncryptString 加密并返回base64
+func EncryptString(c Cipher, plain string) (string, error) {
+	b, err := c.Encrypt([]byte(plain))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// DecryptString 解密 EncryptString 的结果
+func DecryptString(c Cipher, s string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+	plain, err := c.Decrypt(b)
+	return string(plain), err
+}
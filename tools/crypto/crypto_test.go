@@ -0,0 +1,162 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"reflect"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+func TestCipher(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	for _, f := range []func([]byte) (Cipher, error){NewAESGCM, NewSM4GCM} {
+		c, err := f(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := EncryptString(c, "13800000000")
+		if err != nil {
+			t.Fatal(err)
+		}
+		other, _ := EncryptString(c, "13800000000")
+		if s == other {
+			t.Errorf("%s: ciphertext should be randomized", c)
+		}
+		if plain, err := DecryptString(c, s); err != nil || plain != "13800000000" {
+			t.Errorf("%s: plain = %q, err = %v", c, plain, err)
+		}
+		if _, err = c.Decrypt([]byte("short")); err != ErrCiphertext {
+			t.Errorf("%s: err = %v", c, err)
+		}
+	}
+	if _, err := NewSM4GCM([]byte("short")); err == nil {
+		t.Error("invalid sm4 key should fail")
+	}
+}
+
+func TestRSA(t *testing.T) {
+	key, err := GenerateRSAKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privPEM, _ := MarshalRSAPrivateKey(key)
+	pubPEM, _ := MarshalRSAPublicKey(&key.PublicKey)
+	priv, err := ParseRSAPrivateKey(privPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ParseRSAPublicKey(pubPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := RSAEncrypt(pub, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain, err := RSADecrypt(priv, data); err != nil || string(plain) != "hello" {
+		t.Errorf("plain = %q, err = %v", plain, err)
+	}
+	sig, _ := RSASign(priv, []byte("hello"))
+	if !RSAVerify(pub, []byte("hello"), sig) || RSAVerify(pub, []byte("hello!"), sig) {
+		t.Error("rsa verify failed")
+	}
+	if _, err = ParseRSAPublicKey([]byte("invalid")); err != ErrInvalidKey {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSM(t *testing.T) {
+	// GB/T 32905 示例
+	if got := SM3Hex([]byte("abc")); got != "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0" {
+		t.Errorf("sm3 = %s", got)
+	}
+	if len(HMACSM3([]byte("key"), []byte("abc"))) != 32 {
+		t.Error("hmac-sm3 length")
+	}
+
+	key, err := GenerateSM2Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	privPEM, err := MarshalSM2PrivateKey(key, []byte("pwd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubPEM, _ := MarshalSM2PublicKey(&key.PublicKey)
+	priv, err := ParseSM2PrivateKey(privPEM, []byte("pwd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ParseSM2PublicKey(pubPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := SM2Encrypt(pub, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain, err := SM2Decrypt(priv, data); err != nil || string(plain) != "hello" {
+		t.Errorf("plain = %q, err = %v", plain, err)
+	}
+	sig, err := SM2Sign(priv, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !SM2Verify(pub, []byte("hello"), sig) || SM2Verify(pub, []byte("hello!"), sig) {
+		t.Error("sm2 verify failed")
+	}
+}
+
+type Profile struct {
+	City string `json:"city"`
+}
+
+type User struct {
+	Id      int
+	Phone   string   `gorm:"serializer:test_encrypt"`
+	Secret  []byte   `gorm:"serializer:test_encrypt"`
+	Profile *Profile `gorm:"serializer:test_encrypt"`
+}
+
+func TestSerializer(t *testing.T) {
+	c, _ := NewSM4GCM([]byte("0123456789abcdef"))
+	RegisterSerializer("test_encrypt", c)
+	s, err := schema.Parse(&User{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	src := &User{Phone: "13800000000", Secret: []byte{1, 2, 3}, Profile: &Profile{City: "北京"}}
+	dst := &User{}
+	for name, fv := range map[string]interface{}{"Phone": src.Phone, "Secret": src.Secret, "Profile": src.Profile} {
+		field := s.LookUpField(name)
+		if field.Serializer == nil {
+			t.Fatalf("%s: serializer not registered", name)
+		}
+		v, err := field.Serializer.Value(ctx, field, reflect.ValueOf(src), fv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if str, ok := v.(string); !ok || bytes.Contains([]byte(str), []byte("1380")) {
+			t.Errorf("%s: stored = %v", name, v)
+		}
+		if err = field.Serializer.Scan(ctx, field, reflect.ValueOf(dst), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if dst.Phone != src.Phone || hex.EncodeToString(dst.Secret) != "010203" || dst.Profile == nil || dst.Profile.City != "北京" {
+		t.Errorf("dst = %+v", dst)
+	}
+
+	field := s.LookUpField("Profile")
+	if v, err := field.Serializer.Value(ctx, field, reflect.ValueOf(dst), (*Profile)(nil)); err != nil || v != nil {
+		t.Errorf("nil value = %v, err = %v", v, err)
+	}
+	if err := field.Serializer.Scan(ctx, field, reflect.ValueOf(dst), "not base64"); err == nil {
+		t.Error("invalid ciphertext should fail")
+	}
+}
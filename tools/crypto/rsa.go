@@ -0,0 +1,96 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+)
+
+var ErrInvalidKey = errors.New("crypto: invalid key")
+
+// GenerateRSAKey 生成RSA私钥, bits 建议不小于2048
+func GenerateRSAKey(bits int) (*rsa.PrivateKey, error) {
+	return rsa.GenerateKey(rand.Reader, bits)
+}
+
+// MarshalRSAPrivateKey PKCS#8 格式的PEM
+func MarshalRSAPrivateKey(key *rsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// MarshalRSAPublicKey PKIX 格式的PEM
+func MarshalRSAPublicKey(key *rsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// ParseRSAPrivateKey 解析PEM格式的私钥, 支持 PKCS#1 与 PKCS#8
+func ParseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if k, ok := key.(*rsa.PrivateKey); ok {
+		return k, nil
+	}
+	return nil, ErrInvalidKey
+}
+
+// ParseRSAPublicKey 解析PEM格式的公钥, 支持 PKIX 与 PKCS#1
+func ParseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if k, ok := key.(*rsa.PublicKey); ok {
+		return k, nil
+	}
+	return nil, ErrInvalidKey
+}
+
+// RSAEncrypt RSA-OAEP(SHA-256) 加密
+func RSAEncrypt(pub *rsa.PublicKey, plain []byte) ([]byte, error) {
+	return rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, plain, nil)
+}
+
+// RSADecrypt RSA-OAEP(SHA-256) 解密
+func RSADecrypt(priv *rsa.PrivateKey, data []byte) ([]byte, error) {
+	return rsa.DecryptOAEP(sha256.New(), rand.Reader, priv, data, nil)
+}
+
+// RSASign SHA256WithRSA(PKCS#1 v1.5) 签名, 与多数开放平台兼容
+func RSASign(priv *rsa.PrivateKey, data []byte) ([]byte, error) {
+	sum := sha256.Sum256(data)
+	return rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, sum[:])
+}
+
+// RSAVerify 校验 RSASign 的签名
+func RSAVerify(pub *rsa.PublicKey, data, sig []byte) bool {
+	sum := sha256.Sum256(data)
+	return rsa.VerifyPKCS1v15(pub, crypto.SHA256, sum[:], sig) == nil
+}
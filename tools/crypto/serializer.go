@@ -0,0 +1,101 @@
+package crypto
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// RegisterSerializer 注册gorm字段加密, 字段使用 gorm:"serializer:<name>", 数据库中保存base64密文;
+// string、[]byte 直接加密, 其他类型先转为json; 每次加密结果不同, 无法按密文查询, 需要查询时配合 HMACSM3 建索引列
+//
+//	crypto.RegisterSerializer("encrypt", c)
+//	type User struct {
+//		Phone string `gorm:"type:varchar(255);serializer:encrypt"`
+//	}
+func RegisterSerializer(name string, c Cipher) {
+	schema.RegisterSerializer(name, &Serializer{Cipher: c})
+}
+
+// Serializer 字段加密的 gorm serializer
+type Serializer struct {
+	Cipher Cipher
+}
+
+// Scan 解密数据库中的值
+func (e *Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+	if dbValue != nil {
+		var s string
+		switch v := dbValue.(type) {
+		case []byte:
+			s = string(v)
+		case string:
+			s = v
+		default:
+			return fmt.Errorf("crypto: failed to decrypt field %s: unsupported value %T", field.Name, dbValue)
+		}
+		if s != "" {
+			data, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return fmt.Errorf("crypto: failed to decrypt field %s: %w", field.Name, err)
+			}
+			plain, err := e.Cipher.Decrypt(data)
+			if err != nil {
+				return fmt.Errorf("crypto: failed to decrypt field %s: %w", field.Name, err)
+			}
+			if err = decode(plain, fieldValue); err != nil {
+				return err
+			}
+		}
+	}
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// Value 加密字段的值, nil 保存为 NULL
+func (e *Serializer) Value(_ context.Context, _ *schema.Field, _ reflect.Value, fieldValue interface{}) (interface{}, error) {
+	var plain []byte
+	switch v := fieldValue.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		plain = []byte(v)
+	case []byte:
+		if v == nil {
+			return nil, nil
+		}
+		plain = v
+	default:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil, nil
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		plain = b
+	}
+	data, err := e.Cipher.Encrypt(plain)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// decode 按字段类型还原明文, v 为 reflect.New 的结果
+func decode(plain []byte, v reflect.Value) error {
+	switch elem := v.Elem(); {
+	case elem.Kind() == reflect.String:
+		elem.SetString(string(plain))
+	case elem.Kind() == reflect.Slice && elem.Type().Elem().Kind() == reflect.Uint8:
+		elem.SetBytes(plain)
+	default:
+		return json.Unmarshal(plain, v.Interface())
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/tjfoc/gmsm/sm2"
+	"github.com/tjfoc/gmsm/sm3"
+	"github.com/tjfoc/gmsm/x509"
+)
+
+// SM3 摘要
+func SM3(data []byte) []byte {
+	return sm3.Sm3Sum(data)
+}
+
+// SM3Hex 十六进制的SM3摘要
+func SM3Hex(data []byte) string {
+	return hex.EncodeToString(sm3.Sm3Sum(data))
+}
+
+// HMACSM3 HMAC-SM3, 也可用于加密字段的等值查询索引
+func HMACSM3(key, data []byte) []byte {
+	h := hmac.New(sm3.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// GenerateSM2Key 生成SM2私钥
+func GenerateSM2Key() (*sm2.PrivateKey, error) {
+	return sm2.GenerateKey(rand.Reader)
+}
+
+// MarshalSM2PrivateKey PKCS#8 格式的PEM, pwd 不为空时加密私钥
+func MarshalSM2PrivateKey(key *sm2.PrivateKey, pwd []byte) ([]byte, error) {
+	return x509.WritePrivateKeyToPem(key, pwd)
+}
+
+// MarshalSM2PublicKey PKIX 格式的PEM
+func MarshalSM2PublicKey(key *sm2.PublicKey) ([]byte, error) {
+	return x509.WritePublicKeyToPem(key)
+}
+
+// ParseSM2PrivateKey 解析PEM格式的私钥, 私钥未加密时 pwd 传nil
+func ParseSM2PrivateKey(data, pwd []byte) (*sm2.PrivateKey, error) {
+	return x509.ReadPrivateKeyFromPem(data, pwd)
+}
+
+// ParseSM2PublicKey 解析PEM格式的公钥
+func ParseSM2PublicKey(data []byte) (*sm2.PublicKey, error) {
+	return x509.ReadPublicKeyFromPem(data)
+}
+
+// SM2Encrypt 加密, 密文按 GM/T 0003 使用 C1C3C2 排列
+func SM2Encrypt(pub *sm2.PublicKey, plain []byte) ([]byte, error) {
+	return sm2.Encrypt(pub, plain, rand.Reader, sm2.C1C3C2)
+}
+
+// SM2Decrypt 解密 C1C3C2 排列的密文
+func SM2Decrypt(priv *sm2.PrivateKey, data []byte) ([]byte, error) {
+	return sm2.Decrypt(priv, data, sm2.C1C3C2)
+}
+
+// SM2Sign 使用默认用户标识 1234567812345678 签名, 签名为ASN.1编码
+func SM2Sign(priv *sm2.PrivateKey, data []byte) ([]byte, error) {
+	return priv.Sign(rand.Reader, data, nil)
+}
+
+// SM2Verify 校验 SM2Sign 的签名
+func SM2Verify(pub *sm2.PublicKey, data, sig []byte) bool {
+	return pub.Verify(data, sig)
+}
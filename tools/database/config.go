@@ -1,6 +1,7 @@
 package database
 
 import (
+	"sync"
 	"time"
 
 	"gorm.io/gorm"
@@ -13,9 +14,20 @@ type DBConfig struct {
 	connMaxLifetime int
 	maxIdleConns    int
 	maxOpenConns    int
+	prepareStmt     bool
 	registers       []ResolverConfigure
 }
 
+// Option Configure 的可选参数
+type Option func(*DBConfig)
+
+// WithPrepareStmt 缓存预编译语句, 重复执行的SQL不再重新编译
+func WithPrepareStmt(enabled bool) Option {
+	return func(e *DBConfig) {
+		e.prepareStmt = enabled
+	}
+}
+
 // NewConfigure 初始化 Configure
 func NewConfigure(
 	dsn string,
@@ -23,8 +35,9 @@ func NewConfigure(
 	maxOpenConns,
 	connMaxIdleTime,
 	connMaxLifetime int,
-	registers []ResolverConfigure) Configure {
-	return &DBConfig{
+	registers []ResolverConfigure,
+	opts ...Option) Configure {
+	e := &DBConfig{
 		dsn:             dsn,
 		connMaxIdleTime: connMaxIdleTime,
 		connMaxLifetime: connMaxLifetime,
@@ -32,17 +45,39 @@ func NewConfigure(
 		maxOpenConns:    maxOpenConns,
 		registers:       registers,
 	}
+	for _, o := range opts {
+		o(e)
+	}
+	return e
 }
 
 // Init 获取db，⚠️注意：读写分离只能配置一组
 func (e *DBConfig) Init(config *gorm.Config, open func(string) gorm.Dialector) (*gorm.DB, error) {
+	if e.prepareStmt {
+		c := gorm.Config{}
+		if config != nil {
+			c = *config
+		}
+		c.PrepareStmt = true
+		config = &c
+	}
 	db, err := gorm.Open(open(e.dsn), config)
 	if err != nil {
 		return nil, err
 	}
 	var register *dbresolver.DBResolver
+	var checks healthChecks
 	for i := range e.registers {
 		register = e.registers[i].Init(register, open)
+		if r, ok := e.registers[i].(*DBResolverConfig); ok {
+			checks = append(checks, r.takeHealthChecks()...)
+		}
+	}
+	if len(checks) > 0 {
+		// Close(db) 时停止
+		if err = db.Use(checks); err != nil {
+			return db, err
+		}
 	}
 	if register == nil {
 		register = dbresolver.Register(dbresolver.Config{})
@@ -66,24 +101,57 @@ func (e *DBConfig) Init(config *gorm.Config, open func(string) gorm.Dialector) (
 }
 
 type DBResolverConfig struct {
-	sources  []string
-	replicas []string
-	policy   string
-	tables   []interface{}
+	sources     []string
+	replicas    []string
+	policy      string
+	tables      []interface{}
+	healthCheck time.Duration
+	open        func(string) gorm.Dialector
+	// checks Init 创建的健康检查, 由 DBConfig.Init 取出后随 db 关闭
+	mux    sync.Mutex
+	checks []*healthPolicy
+}
+
+// ResolverOption ResolverConfigure 的可选参数
+type ResolverOption func(*DBResolverConfig)
+
+// WithHealthCheck 按间隔ping从库, 不可用的从库不再参与读请求, 恢复后自动加入
+func WithHealthCheck(interval time.Duration) ResolverOption {
+	return func(e *DBResolverConfig) {
+		e.healthCheck = interval
+	}
+}
+
+// WithDialector 使用与主库不同的驱动, 如分析类的表路由到 clickhouse
+func WithDialector(open func(string) gorm.Dialector) ResolverOption {
+	return func(e *DBResolverConfig) {
+		e.open = open
+	}
+}
+
+// WithModels 按模型路由, 与tables一起注册
+func WithModels(models ...interface{}) ResolverOption {
+	return func(e *DBResolverConfig) {
+		e.tables = append(e.tables, models...)
+	}
 }
 
 // NewResolverConfigure 初始化 ResolverConfigure
-func NewResolverConfigure(sources, replicas []string, policy string, tables []string) ResolverConfigure {
+func NewResolverConfigure(sources, replicas []string, policy string, tables []string, opts ...ResolverOption) ResolverConfigure {
 	data := make([]interface{}, len(tables))
 	for i := range tables {
 		data[i] = tables[i]
 	}
-	return &DBResolverConfig{
+	e := &DBResolverConfig{
 		sources:  sources,
 		replicas: replicas,
 		policy:   policy,
 		tables:   data,
 	}
+	for _, o := range opts {
+		o(e)
+	}
+	return e
 }
 
 func (e *DBResolverConfig) Init(
@@ -92,6 +160,9 @@ func (e *DBResolverConfig) Init(
 	if len(e.tables) == 0 && len(e.sources) == 0 && len(e.replicas) == 0 {
 		return register
 	}
+	if e.open != nil {
+		open = e.open
+	}
 	var config dbresolver.Config
 	if len(e.sources) > 0 {
 		config.Sources = make([]gorm.Dialector, len(e.sources))
@@ -108,8 +179,18 @@ func (e *DBResolverConfig) Init(
 	if e.policy != "" {
 		policy, ok := policies[e.policy]
 		if ok {
-			config.Policy = policy
+			config.Policy = policy()
+		}
+	}
+	if e.healthCheck > 0 && len(e.replicas) > 1 {
+		if config.Policy == nil {
+			config.Policy = dbresolver.RandomPolicy{}
 		}
+		h := newHealthPolicy(config.Policy, e.healthCheck)
+		config.Policy = h
+		e.mux.Lock()
+		e.checks = append(e.checks, h)
+		e.mux.Unlock()
 	}
 	if register == nil {
 		register = dbresolver.Register(config, e.tables...)
@@ -118,3 +199,12 @@ func (e *DBResolverConfig) Init(
 	register = register.Register(config, e.tables...)
 	return register
 }
+
+// takeHealthChecks 取出 Init 创建的健康检查
+func (e *DBResolverConfig) takeHealthChecks() []*healthPolicy {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	checks := e.checks
+	e.checks = nil
+	return checks
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// healthPolicy 定时ping从库, 剔除不可用的从库, 恢复后重新加入
+// 全部不可用时退回到原始列表, 由底层policy选择; 由 Close(db) 停止
+type healthPolicy struct {
+	policy    dbresolver.Policy
+	interval  time.Duration
+	once      sync.Once
+	mux       sync.RWMutex
+	bad       map[gorm.ConnPool]bool
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+func newHealthPolicy(policy dbresolver.Policy, interval time.Duration) *healthPolicy {
+	return &healthPolicy{
+		policy:   policy,
+		interval: interval,
+		bad:      make(map[gorm.ConnPool]bool),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Close 停止检查
+func (h *healthPolicy) Close() {
+	h.closeOnce.Do(func() {
+		close(h.stop)
+	})
+}
+
+func (h *healthPolicy) Resolve(pools []gorm.ConnPool) gorm.ConnPool {
+	h.once.Do(func() {
+		go h.check(append([]gorm.ConnPool(nil), pools...))
+	})
+	h.mux.RLock()
+	healthy := make([]gorm.ConnPool, 0, len(pools))
+	for i := range pools {
+		if !h.bad[pools[i]] {
+			healthy = append(healthy, pools[i])
+		}
+	}
+	h.mux.RUnlock()
+	if len(healthy) == 0 {
+		healthy = pools
+	}
+	return h.policy.Resolve(healthy)
+}
+
+func (h *healthPolicy) check(pools []gorm.ConnPool) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+		}
+		for i := range pools {
+			p, ok := pools[i].(pinger)
+			if !ok {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), h.interval)
+			err := p.PingContext(ctx)
+			cancel()
+			h.mux.Lock()
+			h.bad[pools[i]] = err != nil
+			h.mux.Unlock()
+		}
+	}
+}
+
+// healthChecks db 的从库健康检查, 以插件的形式保存在 db 中, 关闭 db 时停止
+type healthChecks []*healthPolicy
+
+func (healthChecks) Name() string {
+	return "database:health_checks"
+}
+
+func (healthChecks) Initialize(*gorm.DB) error {
+	return nil
+}
+
+// Close 停止 db 的从库健康检查并关闭连接池, Init 打开的 db 应使用它关闭
+func Close(db *gorm.DB) error {
+	if db == nil || db.Config == nil {
+		return nil
+	}
+	if checks, ok := db.Config.Plugins[healthChecks(nil).Name()].(healthChecks); ok {
+		for _, h := range checks {
+			h.Close()
+		}
+	}
+	if db.ConnPool == nil {
+		return nil
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// roundRobinPolicy 轮询
+type roundRobinPolicy struct {
+	i uint64
+}
+
+func (r *roundRobinPolicy) Resolve(pools []gorm.ConnPool) gorm.ConnPool {
+	n := atomic.AddUint64(&r.i, 1)
+	return pools[n%uint64(len(pools))]
+}
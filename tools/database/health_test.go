@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type fakePool struct {
+	gorm.ConnPool
+	down int32
+}
+
+func (p *fakePool) PingContext(ctx context.Context) error {
+	if atomic.LoadInt32(&p.down) == 1 {
+		return errors.New("down")
+	}
+	return nil
+}
+
+func TestHealthPolicy(t *testing.T) {
+	a, b := &fakePool{}, &fakePool{down: 1}
+	pools := []gorm.ConnPool{a, b}
+	h := newHealthPolicy(&roundRobinPolicy{}, 10*time.Millisecond)
+	h.Resolve(pools)
+	time.Sleep(50 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		if h.Resolve(pools) != a {
+			t.Fatal("expected unhealthy replica removed")
+		}
+	}
+
+	atomic.StoreInt32(&b.down, 0)
+	atomic.StoreInt32(&a.down, 1)
+	time.Sleep(50 * time.Millisecond)
+	if h.Resolve(pools) != b {
+		t.Fatal("expected recovered replica used")
+	}
+
+	atomic.StoreInt32(&b.down, 1)
+	time.Sleep(50 * time.Millisecond)
+	if h.Resolve(pools) == nil {
+		t.Fatal("expected fallback when all replicas down")
+	}
+}
+
+func TestHealthPolicyClose(t *testing.T) {
+	p := &countPool{}
+	h := newHealthPolicy(&roundRobinPolicy{}, 5*time.Millisecond)
+	h.Resolve([]gorm.ConnPool{p})
+	time.Sleep(20 * time.Millisecond)
+	db := &gorm.DB{Config: &gorm.Config{Plugins: map[string]gorm.Plugin{}}}
+	_ = db.Use(healthChecks{h})
+	if err := Close(db); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	n := atomic.LoadInt32(&p.pings)
+	time.Sleep(30 * time.Millisecond)
+	if atomic.LoadInt32(&p.pings) != n {
+		t.Error("health check still running after Close")
+	}
+}
+
+type countPool struct {
+	gorm.ConnPool
+	pings int32
+}
+
+func (p *countPool) PingContext(context.Context) error {
+	atomic.AddInt32(&p.pings, 1)
+	return nil
+}
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+// DefaultSaturation 默认的连接池使用率告警阈值
+const DefaultSaturation = 0.8
+
+// StatsGetter 连接池状态, *sql.DB 已实现
+type StatsGetter interface {
+	Stats() sql.DBStats
+}
+
+// PoolStats 连接池状态
+type PoolStats struct {
+	sql.DBStats
+	// Saturation 使用中的连接占最大连接数的比例, 未限制最大连接数时为0
+	Saturation float64
+	// Waits 距上次检查新增的等待次数
+	Waits int64
+}
+
+// Saturation 计算连接池使用率
+func Saturation(s sql.DBStats) float64 {
+	if s.MaxOpenConnections <= 0 {
+		return 0
+	}
+	return float64(s.InUse) / float64(s.MaxOpenConnections)
+}
+
+// PoolMonitor 定时检查连接池, 使用率达到阈值或出现等待连接时告警
+type PoolMonitor struct {
+	name      string
+	db        StatsGetter
+	interval  time.Duration
+	threshold float64
+	onWarn    []func(name string, s PoolStats)
+
+	mux       sync.Mutex
+	waitCount int64
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// NewPoolMonitor 创建连接池监控, threshold 为0时使用 DefaultSaturation
+func NewPoolMonitor(name string, db StatsGetter, interval time.Duration, threshold float64) *PoolMonitor {
+	if threshold <= 0 {
+		threshold = DefaultSaturation
+	}
+	return &PoolMonitor{
+		name:      name,
+		db:        db,
+		interval:  interval,
+		threshold: threshold,
+		waitCount: db.Stats().WaitCount,
+	}
+}
+
+// OnWarn 追加告警处理, 如 metrics.Registry.PoolWarn, 告警时总会输出warn日志
+func (m *PoolMonitor) OnWarn(f func(name string, s PoolStats)) *PoolMonitor {
+	m.onWarn = append(m.onWarn, f)
+	return m
+}
+
+func (m *PoolMonitor) String() string {
+	return "database-pool:" + m.name
+}
+
+// Check 检查一次, 返回当前状态以及是否告警
+func (m *PoolMonitor) Check() (PoolStats, bool) {
+	m.mux.Lock()
+	s := PoolStats{DBStats: m.db.Stats()}
+	s.Saturation = Saturation(s.DBStats)
+	s.Waits = s.WaitCount - m.waitCount
+	m.waitCount = s.WaitCount
+	m.mux.Unlock()
+	warn := s.Waits > 0 || (s.MaxOpenConnections > 0 && s.Saturation >= m.threshold)
+	if warn {
+		logWarn(m.name, s)
+		for _, f := range m.onWarn {
+			f(m.name, s)
+		}
+	}
+	return s, warn
+}
+
+// Start 后台定时检查, 不阻塞
+func (m *PoolMonitor) Start(ctx context.Context) error {
+	if m.interval <= 0 {
+		return nil
+	}
+	ctx, m.cancel = context.WithCancel(context.Background())
+	m.done = make(chan struct{})
+	go func() {
+		defer close(m.done)
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.Check()
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop 停止检查
+func (m *PoolMonitor) Stop(ctx context.Context) error {
+	if m.cancel == nil {
+		return nil
+	}
+	m.cancel()
+	select {
+	case <-m.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+func logWarn(name string, s PoolStats) {
+	logger.Module("database").Warn("connection pool saturated",
+		"db", name,
+		"in_use", s.InUse,
+		"idle", s.Idle,
+		"max_open", s.MaxOpenConnections,
+		"saturation", s.Saturation,
+		"waits", s.Waits,
+		"wait_duration", s.WaitDuration.String(),
+	)
+}
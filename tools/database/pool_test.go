@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeStats struct {
+	mux   sync.Mutex
+	stats sql.DBStats
+}
+
+func (f *fakeStats) Stats() sql.DBStats {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return f.stats
+}
+
+func (f *fakeStats) set(s sql.DBStats) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.stats = s
+}
+
+func TestPoolMonitor(t *testing.T) {
+	db := &fakeStats{stats: sql.DBStats{MaxOpenConnections: 10, InUse: 2, WaitCount: 5}}
+	var warned []PoolStats
+	m := NewPoolMonitor("default", db, 0, 0).OnWarn(func(_ string, s PoolStats) {
+		warned = append(warned, s)
+	})
+	if s, warn := m.Check(); warn || s.Saturation != 0.2 {
+		t.Errorf("stats = %+v, warn = %v", s, warn)
+	}
+
+	db.set(sql.DBStats{MaxOpenConnections: 10, InUse: 8, WaitCount: 5})
+	if _, warn := m.Check(); !warn {
+		t.Error("saturation should warn")
+	}
+	db.set(sql.DBStats{MaxOpenConnections: 10, InUse: 1, WaitCount: 7})
+	if s, warn := m.Check(); !warn || s.Waits != 2 {
+		t.Errorf("stats = %+v, warn = %v", s, warn)
+	}
+	if len(warned) != 2 {
+		t.Errorf("warned = %d", len(warned))
+	}
+
+	// 未限制最大连接数时只按等待告警
+	db.set(sql.DBStats{InUse: 100, WaitCount: 7})
+	if _, warn := m.Check(); warn {
+		t.Error("unlimited pool should not warn")
+	}
+}
+
+func TestPoolMonitorStart(t *testing.T) {
+	db := &fakeStats{stats: sql.DBStats{MaxOpenConnections: 1, InUse: 1}}
+	ch := make(chan PoolStats, 10)
+	m := NewPoolMonitor("default", db, 10*time.Millisecond, 0.5).OnWarn(func(_ string, s PoolStats) {
+		ch <- s
+	})
+	ctx := context.Background()
+	if err := m.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case s := <-ch:
+		if s.Saturation != 1 {
+			t.Errorf("saturation = %v", s.Saturation)
+		}
+	case <-time.After(time.Second):
+		t.Error("monitor should warn")
+	}
+	if err := m.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -5,8 +5,13 @@ import (
 	"gorm.io/plugin/dbresolver"
 )
 
-var policies = map[string]dbresolver.Policy{
-	"random": dbresolver.RandomPolicy{},
+var policies = map[string]func() dbresolver.Policy{
+	"random": func() dbresolver.Policy {
+		return dbresolver.RandomPolicy{}
+	},
+	"round_robin": func() dbresolver.Policy {
+		return &roundRobinPolicy{}
+	},
 }
 
 type Configure interface {
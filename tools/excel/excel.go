@@ -0,0 +1,222 @@
+package excel
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Export 逐行读取查询结果并写入 w, 不会一次加载全部数据; db 为已设置条件的查询,
+// 未指定 Model/Table 时使用 T; 返回写入的数据行数
+func Export[T any](db *gorm.DB, w Writer) (int, error) {
+	cols, err := schemaOf(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return 0, err
+	}
+	if err = writeHeader(w, cols); err != nil {
+		return 0, err
+	}
+	if db.Statement.Model == nil && db.Statement.Table == "" {
+		db = db.Model(new(T))
+	}
+	rows, err := db.Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	n := 0
+	for rows.Next() {
+		var v T
+		if err = db.ScanRows(rows, &v); err != nil {
+			return n, err
+		}
+		if err = writeRow(w, cols, reflect.ValueOf(&v).Elem()); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, rows.Err()
+}
+
+// ExportSlice 将已查询的数据写入 w
+func ExportSlice[T any](list []T, w Writer) error {
+	cols, err := schemaOf(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return err
+	}
+	if err = writeHeader(w, cols); err != nil {
+		return err
+	}
+	for i := range list {
+		if err = writeRow(w, cols, reflect.ValueOf(&list[i]).Elem()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeHeader(w Writer, cols []*column) error {
+	widths := make([]float64, len(cols))
+	for i, c := range cols {
+		widths[i] = c.width
+	}
+	if err := w.SetWidths(widths); err != nil {
+		return err
+	}
+	row := make([]interface{}, len(cols))
+	for i, title := range headers(cols) {
+		row[i] = title
+	}
+	return w.WriteRow(row)
+}
+
+func writeRow(w Writer, cols []*column, v reflect.Value) error {
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	row := make([]interface{}, len(cols))
+	for i, c := range cols {
+		row[i] = c.cell(v.FieldByIndex(c.index))
+	}
+	return w.WriteRow(row)
+}
+
+// RowError 导入时某一行的错误, Row 为表格中的行号(表头为第1行)
+type RowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// Report 导入结果
+type Report struct {
+	// Total 数据行数, 不含表头与空行
+	Total   int        `json:"total"`
+	Success int        `json:"success"`
+	Errors  []RowError `json:"errors"`
+}
+
+// WriteErrors 输出错误报告, 供用户下载后修正重新导入
+func (r *Report) WriteErrors(w Writer) error {
+	if err := w.SetWidths([]float64{8, 20, 60}); err != nil {
+		return err
+	}
+	if err := w.WriteRow([]interface{}{"行号", "列", "错误"}); err != nil {
+		return err
+	}
+	for _, e := range r.Errors {
+		if err := w.WriteRow([]interface{}{e.Row, e.Column, e.Message}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type Option func(*options)
+
+type options struct {
+	validate  func(interface{}) error
+	maxErrors int
+}
+
+// WithValidate 每行解析后的校验, 如 binding.Validator.ValidateStruct
+func WithValidate(f func(interface{}) error) Option {
+	return func(o *options) {
+		o.validate = f
+	}
+}
+
+// WithMaxErrors 错误数达到 n 时停止导入, 0为不限制
+func WithMaxErrors(n int) Option {
+	return func(o *options) {
+		o.maxErrors = n
+	}
+}
+
+// Import 读取表格, 第一行为表头, 按 title 匹配列; 每行解析并校验通过后调用 fn,
+// fn 返回的错误也记入报告. 只有表头缺少必填列或读取失败时返回 error
+func Import[T any](r Reader, fn func(row int, v *T) error, opts ...Option) (*Report, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	cols, err := schemaOf(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+	if !r.Next() {
+		return nil, fmt.Errorf("excel: missing header row")
+	}
+	header, err := r.Row()
+	if err != nil {
+		return nil, err
+	}
+	// positions[i] 为第 i 个字段在表格中的列, -1 为表格中没有该列
+	positions := make([]int, len(cols))
+	for i, c := range cols {
+		positions[i] = -1
+		for j, title := range header {
+			if strings.TrimSpace(title) == c.title {
+				positions[i] = j
+				break
+			}
+		}
+		if positions[i] < 0 && c.required {
+			return nil, fmt.Errorf("excel: missing column %s", c.title)
+		}
+	}
+
+	report := &Report{Errors: make([]RowError, 0)}
+	for r.Next() {
+		line := r.Line()
+		if o.maxErrors > 0 && len(report.Errors) >= o.maxErrors {
+			break
+		}
+		record, err := r.Row()
+		if err != nil {
+			return report, err
+		}
+		if isEmpty(record) {
+			continue
+		}
+		report.Total++
+		var v T
+		rv := reflect.ValueOf(&v).Elem()
+		failed := false
+		for i, c := range cols {
+			s := ""
+			if p := positions[i]; p >= 0 && p < len(record) {
+				s = record[p]
+			}
+			if err := c.set(rv.FieldByIndex(c.index), s); err != nil {
+				report.Errors = append(report.Errors, RowError{Row: line, Column: c.title, Message: err.Error()})
+				failed = true
+			}
+		}
+		if !failed && o.validate != nil {
+			if err = o.validate(&v); err != nil {
+				report.Errors = append(report.Errors, RowError{Row: line, Message: err.Error()})
+				failed = true
+			}
+		}
+		if !failed {
+			if err = fn(line, &v); err != nil {
+				report.Errors = append(report.Errors, RowError{Row: line, Message: err.Error()})
+				continue
+			}
+			report.Success++
+		}
+	}
+	return report, nil
+}
+
+func isEmpty(record []string) bool {
+	for _, s := range record {
+		if strings.TrimSpace(s) != "" {
+			return false
+		}
+	}
+	return true
+}
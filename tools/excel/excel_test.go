@@ -0,0 +1,117 @@
+package excel
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type base struct {
+	ID int `excel:"编号"`
+}
+
+type user struct {
+	base
+	Name     string    `excel:"title:用户名;width:20;required"`
+	Status   string    `excel:"title:状态;dict:1=正常,2=停用"`
+	Age      *int      `excel:"年龄"`
+	Birthday time.Time `excel:"title:生日;format:2006-01-02"`
+	Password string    `excel:"-"`
+}
+
+func TestExportImport(t *testing.T) {
+	age := 18
+	list := []user{
+		{base: base{ID: 1}, Name: "admin", Status: "1", Age: &age, Birthday: time.Date(2000, 1, 2, 0, 0, 0, 0, time.Local)},
+		{base: base{ID: 2}, Name: "guest", Status: "2"},
+	}
+	for name, open := range map[string]struct {
+		writer func(*bytes.Buffer) (Writer, error)
+		reader func(*bytes.Buffer) (Reader, error)
+	}{
+		"xlsx": {
+			func(b *bytes.Buffer) (Writer, error) { return NewXLSXWriter(b, "用户") },
+			func(b *bytes.Buffer) (Reader, error) { return NewXLSXReader(b, "") },
+		},
+		"csv": {
+			func(b *bytes.Buffer) (Writer, error) { return NewCSVWriter(b) },
+			func(b *bytes.Buffer) (Reader, error) { return NewCSVReader(b) },
+		},
+	} {
+		var buf bytes.Buffer
+		w, err := open.writer(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = ExportSlice(list, w); err != nil {
+			t.Fatal(err)
+		}
+		if err = w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		r, err := open.reader(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []user
+		report, err := Import(r, func(row int, v *user) error {
+			got = append(got, *v)
+			return nil
+		})
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Success != 2 || len(got) != 2 {
+			t.Fatalf("%s: report = %+v", name, report)
+		}
+		if got[0].ID != 1 || got[0].Status != "1" || got[0].Age == nil || *got[0].Age != 18 ||
+			!got[0].Birthday.Equal(list[0].Birthday) || got[1].Status != "2" || got[1].Age != nil {
+			t.Errorf("%s: imported = %+v", name, got)
+		}
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	data := "用户名,状态,年龄,生日\n" +
+		"admin,正常,18,2000-01-02\n" +
+		",未知,abc,2000/01/02\n" +
+		"\n" +
+		"exists,停用,,\n"
+	r, _ := NewCSVReader(strings.NewReader(data))
+	report, err := Import(r, func(row int, v *user) error {
+		if v.Name == "exists" {
+			return errors.New("用户名已存在")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 3 || report.Success != 1 || len(report.Errors) != 5 {
+		t.Fatalf("report = %+v", report)
+	}
+	if e := report.Errors[0]; e.Row != 3 || e.Column != "用户名" {
+		t.Errorf("first error = %+v", e)
+	}
+	if e := report.Errors[4]; e.Row != 5 || e.Message != "用户名已存在" {
+		t.Errorf("last error = %+v", e)
+	}
+
+	var buf bytes.Buffer
+	w, _ := NewCSVWriter(&buf)
+	if err = report.WriteErrors(w); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+	if !strings.Contains(buf.String(), "3,年龄,年龄应为整数") {
+		t.Errorf("error report = %s", buf.String())
+	}
+
+	r, _ = NewCSVReader(strings.NewReader("状态\n正常\n"))
+	if _, err = Import(r, func(int, *user) error { return nil }); err == nil {
+		t.Error("missing required column should fail")
+	}
+}
@@ -0,0 +1,220 @@
+// Package excel XLSX/CSV 导入导出, 按结构体 tag 映射列, 导出时逐行流式写入,
+// 导入时逐行校验并生成错误报告
+package excel
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tag 列定义的 tag, 如
+//
+//	Name   string    `excel:"title:用户名;width:20;required"`
+//	Status string    `excel:"title:状态;dict:1=正常,2=停用"`
+//	Birth  time.Time `excel:"title:生日;format:2006-01-02"`
+//
+// 只有 title 时可简写为 `excel:"用户名"`, `excel:"-"` 为忽略
+const Tag = "excel"
+
+// DefaultTimeFormat 时间列默认格式
+const DefaultTimeFormat = "2006-01-02 15:04:05"
+
+type column struct {
+	index    []int
+	title    string
+	width    float64
+	format   string
+	required bool
+	// dict 值到显示文本, reverse 显示文本到值
+	dict    map[string]string
+	reverse map[string]string
+}
+
+var schemas sync.Map
+
+// schemaOf 解析结构体的列定义, 嵌入的结构体展开, 没有 excel tag 的字段忽略
+func schemaOf(t reflect.Type) ([]*column, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("excel: %s is not a struct", t)
+	}
+	if v, ok := schemas.Load(t); ok {
+		return v.([]*column), nil
+	}
+	cols := parseStruct(t, nil)
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("excel: %s has no %s tag", t, Tag)
+	}
+	schemas.Store(t, cols)
+	return cols, nil
+}
+
+func parseStruct(t reflect.Type, parent []int) []*column {
+	cols := make([]*column, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		index := append(append([]int(nil), parent...), i)
+		tag, ok := f.Tag.Lookup(Tag)
+		if !ok {
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				cols = append(cols, parseStruct(f.Type, index)...)
+			}
+			continue
+		}
+		if tag == "-" || !f.IsExported() {
+			continue
+		}
+		cols = append(cols, parseTag(tag, f, index))
+	}
+	return cols
+}
+
+func parseTag(tag string, f reflect.StructField, index []int) *column {
+	c := &column{index: index, title: f.Name}
+	if !strings.Contains(tag, ":") && !strings.Contains(tag, ";") {
+		c.title = tag
+		return c
+	}
+	for _, part := range strings.Split(tag, ";") {
+		kv := strings.SplitN(strings.TrimSpace(part), ":", 2)
+		switch kv[0] {
+		case "title":
+			c.title = value(kv)
+		case "width":
+			c.width, _ = strconv.ParseFloat(value(kv), 64)
+		case "format":
+			c.format = value(kv)
+		case "required":
+			c.required = true
+		case "dict":
+			c.dict = make(map[string]string)
+			c.reverse = make(map[string]string)
+			for _, item := range strings.Split(value(kv), ",") {
+				if p := strings.SplitN(item, "=", 2); len(p) == 2 {
+					c.dict[p[0]] = p[1]
+					c.reverse[p[1]] = p[0]
+				}
+			}
+		}
+	}
+	return c
+}
+
+func value(kv []string) string {
+	if len(kv) < 2 {
+		return ""
+	}
+	return strings.TrimSpace(kv[1])
+}
+
+func headers(cols []*column) []string {
+	list := make([]string, len(cols))
+	for i, c := range cols {
+		list[i] = c.title
+	}
+	return list
+}
+
+// cell 导出时的单元格值, 数字保持原类型, 时间与字典转为文本
+func (c *column) cell(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		if t.IsZero() {
+			return ""
+		}
+		format := c.format
+		if format == "" {
+			format = DefaultTimeFormat
+		}
+		return t.Format(format)
+	}
+	if c.dict != nil {
+		s := fmt.Sprint(v.Interface())
+		if label, ok := c.dict[s]; ok {
+			return label
+		}
+		return s
+	}
+	return v.Interface()
+}
+
+// set 导入时把文本写入字段
+func (c *column) set(v reflect.Value, s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		if c.required {
+			return fmt.Errorf("%s不能为空", c.title)
+		}
+		return nil
+	}
+	if c.reverse != nil {
+		raw, ok := c.reverse[s]
+		if !ok {
+			if _, isValue := c.dict[s]; !isValue {
+				return fmt.Errorf("%s的值%q无效", c.title, s)
+			}
+			raw = s
+		}
+		s = raw
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if _, ok := v.Interface().(time.Time); ok {
+		format := c.format
+		if format == "" {
+			format = DefaultTimeFormat
+		}
+		t, err := time.ParseInLocation(format, s, time.Local)
+		if err != nil {
+			return fmt.Errorf("%s的格式应为%s", c.title, format)
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || v.OverflowInt(n) {
+			return fmt.Errorf("%s应为整数", c.title)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || v.OverflowUint(n) {
+			return fmt.Errorf("%s应为非负整数", c.title)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("%s应为数字", c.title)
+		}
+		v.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("%s应为true或false", c.title)
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("excel: unsupported field type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package excel
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Writer 按行写入表格
+type Writer interface {
+	// SetWidths 列宽, 需在写入第一行之前调用, 0为默认宽度
+	SetWidths(widths []float64) error
+	WriteRow(values []interface{}) error
+	// Close 写完剩余数据, 不关闭底层的 io.Writer
+	Close() error
+}
+
+// Reader 按行读取表格
+type Reader interface {
+	Next() bool
+	Row() ([]string, error)
+	// Line 当前行在表格中的行号, 从1开始
+	Line() int
+	Close() error
+}
+
+type xlsxWriter struct {
+	w    io.Writer
+	file *excelize.File
+	sw   *excelize.StreamWriter
+	row  int
+}
+
+// NewXLSXWriter XLSX 写入, 行数据以流的方式写入临时文件, Close 时输出到 w
+func NewXLSXWriter(w io.Writer, sheet string) (Writer, error) {
+	f := excelize.NewFile()
+	if sheet == "" {
+		sheet = "Sheet1"
+	}
+	if err := f.SetSheetName(f.GetSheetName(0), sheet); err != nil {
+		return nil, err
+	}
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxWriter{w: w, file: f, sw: sw}, nil
+}
+
+func (e *xlsxWriter) SetWidths(widths []float64) error {
+	for i, width := range widths {
+		if width <= 0 {
+			continue
+		}
+		if err := e.sw.SetColWidth(i+1, i+1, width); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *xlsxWriter) WriteRow(values []interface{}) error {
+	e.row++
+	cell, err := excelize.CoordinatesToCellName(1, e.row)
+	if err != nil {
+		return err
+	}
+	return e.sw.SetRow(cell, values)
+}
+
+func (e *xlsxWriter) Close() error {
+	defer e.file.Close()
+	if err := e.sw.Flush(); err != nil {
+		return err
+	}
+	return e.file.Write(e.w)
+}
+
+const bom = "\xEF\xBB\xBF"
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+// NewCSVWriter CSV 写入, 先写入 UTF-8 BOM, 避免 Excel 打开中文乱码
+func NewCSVWriter(w io.Writer) (Writer, error) {
+	if _, err := io.WriteString(w, bom); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (*csvWriter) SetWidths([]float64) error {
+	return nil
+}
+
+func (e *csvWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = fmt.Sprint(v)
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvWriter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type xlsxReader struct {
+	file *excelize.File
+	rows *excelize.Rows
+	line int
+}
+
+// NewXLSXReader XLSX 读取, sheet 为空时读取第一个工作表
+func NewXLSXReader(r io.Reader, sheet string) (Reader, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	if sheet == "" {
+		sheet = f.GetSheetName(0)
+	}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &xlsxReader{file: f, rows: rows}, nil
+}
+
+func (e *xlsxReader) Next() bool {
+	e.line++
+	return e.rows.Next()
+}
+
+func (e *xlsxReader) Line() int {
+	return e.line
+}
+
+func (e *xlsxReader) Row() ([]string, error) {
+	return e.rows.Columns()
+}
+
+func (e *xlsxReader) Close() error {
+	_ = e.rows.Close()
+	return e.file.Close()
+}
+
+type csvReader struct {
+	r   *csv.Reader
+	row []string
+	err error
+}
+
+// NewCSVReader CSV 读取, 忽略开头的 UTF-8 BOM
+func NewCSVReader(r io.Reader) (Reader, error) {
+	br := bufio.NewReader(r)
+	if b, err := br.Peek(3); err == nil && string(b) == bom {
+		_, _ = br.Discard(3)
+	}
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+	return &csvReader{r: cr}, nil
+}
+
+func (e *csvReader) Next() bool {
+	e.row, e.err = e.r.Read()
+	return e.err != io.EOF
+}
+
+func (e *csvReader) Row() ([]string, error) {
+	return e.row, e.err
+}
+
+// Line csv.Reader 会跳过空行, 行号取自读取位置
+func (e *csvReader) Line() int {
+	if len(e.row) == 0 {
+		return 0
+	}
+	line, _ := e.r.FieldPos(0)
+	return line
+}
+
+func (*csvReader) Close() error {
+	return nil
+}
@@ -3,6 +3,8 @@ package logger
 import (
 	"context"
 	"fmt"
+	"math/rand"
+	"regexp"
 	"time"
 
 	"gorm.io/gorm/logger"
@@ -27,22 +29,67 @@ const (
 	YellowBold  = "\033[33;1m"
 )
 
+// Module 日志模块名, 可通过 SetModuleLevel("gorm", ...) 单独调整sql日志级别
+const Module = "gorm"
+
+// literalRegexp sql中的字符串和数字参数
+var literalRegexp = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'|\b\d+(?:\.\d+)?\b`)
+
 type gormLogger struct {
 	logger.Config
+	opts                                Options
 	infoStr, warnStr, errStr            string
 	traceStr, traceErrStr, traceWarnStr string
 }
 
+// sql 按配置脱敏
+func (l *gormLogger) sql(sql string) string {
+	if !l.opts.redact {
+		return sql
+	}
+	return literalRegexp.ReplaceAllString(sql, "?")
+}
+
+// sampled 是否记录本次普通sql
+func (l *gormLogger) sampled() bool {
+	if l.opts.sampleRate <= 0 || l.opts.sampleRate >= 1 {
+		return true
+	}
+	return rand.Float64() < l.opts.sampleRate
+}
+
+// traceLogger 附加 sql 相关字段, 便于按字段检索
+func (l *gormLogger) traceLogger(ctx context.Context, caller string, elapsed time.Duration, rows int64, extra map[string]interface{}) loggerCore.Logger {
+	fields := l.fields(ctx)
+	fields["caller"] = caller
+	fields["elapsed"] = float64(elapsed.Nanoseconds()) / 1e6
+	fields["rows"] = rows
+	for k, v := range extra {
+		fields[k] = v
+	}
+	return loggerCore.DefaultLogger.Fields(fields)
+}
+
 func (l *gormLogger) getLogger(ctx context.Context) loggerCore.Logger {
-	requestId := ctx.Value("X-Request-Id")
-	if requestId != nil {
-		return loggerCore.DefaultLogger.Fields(map[string]interface{}{
-			"x-request-id": requestId,
-		})
+	fields := l.fields(ctx)
+	if len(fields) > 0 {
+		return loggerCore.DefaultLogger.Fields(fields)
 	}
 	return loggerCore.DefaultLogger
 }
 
+// fields ctx中的链路字段
+func (l *gormLogger) fields(ctx context.Context) map[string]interface{} {
+	fields := loggerCore.TraceFields(ctx)
+	if _, ok := fields[loggerCore.RequestIDKey]; !ok {
+		// 兼容直接传入 gin.Context 的情况
+		if requestId := ctx.Value("X-Request-Id"); requestId != nil {
+			fields[loggerCore.RequestIDKey] = requestId
+		}
+	}
+	return fields
+}
+
 // LogMode log mode
 func (l *gormLogger) LogMode(level logger.LogLevel) logger.Interface {
 	newLogger := *l
@@ -52,7 +99,7 @@ func (l *gormLogger) LogMode(level logger.LogLevel) logger.Interface {
 
 // Info print info
 func (l gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
-	if l.LogLevel >= logger.Info {
+	if l.LogLevel >= logger.Info && loggerCore.Enabled(Module, loggerCore.InfoLevel) {
 		//l.Printf(l.infoStr+msg, append([]interface{}{utils.FileWithLineNum()}, data...)...)
 		log := l.getLogger(ctx)
 		log.Logf(loggerCore.InfoLevel, l.infoStr+msg, append([]interface{}{utils.FileWithLineNum()}, data...)...)
@@ -61,7 +108,7 @@ func (l gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
 
 // Warn print warn messages
 func (l gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
-	if l.LogLevel >= logger.Warn {
+	if l.LogLevel >= logger.Warn && loggerCore.Enabled(Module, loggerCore.WarnLevel) {
 		//l.Printf(l.warnStr+msg, append([]interface{}{utils.FileWithLineNum()}, data...)...)
 		log := l.getLogger(ctx)
 		log.Logf(loggerCore.WarnLevel, l.warnStr+msg, append([]interface{}{utils.FileWithLineNum()}, data...)...)
@@ -70,7 +117,7 @@ func (l gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
 
 // Error print error messages
 func (l gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
-	if l.LogLevel >= logger.Error {
+	if l.LogLevel >= logger.Error && loggerCore.Enabled(Module, loggerCore.ErrorLevel) {
 		//l.Printf(l.errStr+msg, append([]interface{}{utils.FileWithLineNum()}, data...)...)
 		log := l.getLogger(ctx)
 		log.Logf(loggerCore.ErrorLevel, l.errStr+msg, append([]interface{}{utils.FileWithLineNum()}, data...)...)
@@ -78,38 +125,39 @@ func (l gormLogger) Error(ctx context.Context, msg string, data ...interface{})
 }
 
 // Trace print sql message
+// 错误输出为 error, 慢查询输出为 warn, 其余按采样率输出为 trace
 func (l gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
 	if l.LogLevel > logger.Silent {
-		log := l.getLogger(ctx)
 		elapsed := time.Since(begin)
 		switch {
-		case err != nil && l.LogLevel >= logger.Error:
+		case err != nil && l.LogLevel >= logger.Error && loggerCore.Enabled(Module, loggerCore.ErrorLevel):
 			sql, rows := fc()
+			caller := utils.FileWithLineNum()
+			log := l.traceLogger(ctx, caller, elapsed, rows, map[string]interface{}{"error": err})
 			if rows == -1 {
-				//l.Printf(l.traceErrStr, utils.FileWithLineNum(), err, float64(elapsed.Nanoseconds())/1e6, "-", sql)
-				log.Logf(loggerCore.TraceLevel, l.traceErrStr, utils.FileWithLineNum(), err, float64(elapsed.Nanoseconds())/1e6, "-", sql)
+				log.Logf(loggerCore.ErrorLevel, l.traceErrStr, caller, err, float64(elapsed.Nanoseconds())/1e6, "-", l.sql(sql))
 			} else {
-				//l.Printf(l.traceErrStr, utils.FileWithLineNum(), err, float64(elapsed.Nanoseconds())/1e6, rows, sql)
-				log.Logf(loggerCore.TraceLevel, l.traceErrStr, utils.FileWithLineNum(), err, float64(elapsed.Nanoseconds())/1e6, rows, sql)
+				log.Logf(loggerCore.ErrorLevel, l.traceErrStr, caller, err, float64(elapsed.Nanoseconds())/1e6, rows, l.sql(sql))
 			}
-		case elapsed > l.SlowThreshold && l.SlowThreshold != 0 && l.LogLevel >= logger.Warn:
+		case elapsed > l.SlowThreshold && l.SlowThreshold != 0 && l.LogLevel >= logger.Warn &&
+			loggerCore.Enabled(Module, loggerCore.WarnLevel):
 			sql, rows := fc()
+			caller := utils.FileWithLineNum()
 			slowLog := fmt.Sprintf("SLOW SQL >= %v", l.SlowThreshold)
+			log := l.traceLogger(ctx, caller, elapsed, rows, map[string]interface{}{"slow": true})
 			if rows == -1 {
-				//l.Printf(l.traceWarnStr, utils.FileWithLineNum(), slowLog, float64(elapsed.Nanoseconds())/1e6, "-", sql)
-				log.Logf(loggerCore.TraceLevel, l.traceWarnStr, utils.FileWithLineNum(), slowLog, float64(elapsed.Nanoseconds())/1e6, "-", sql)
+				log.Logf(loggerCore.WarnLevel, l.traceWarnStr, caller, slowLog, float64(elapsed.Nanoseconds())/1e6, "-", l.sql(sql))
 			} else {
-				//l.Printf(l.traceWarnStr, utils.FileWithLineNum(), slowLog, float64(elapsed.Nanoseconds())/1e6, rows, sql)
-				log.Logf(loggerCore.TraceLevel, l.traceWarnStr, utils.FileWithLineNum(), slowLog, float64(elapsed.Nanoseconds())/1e6, rows, sql)
+				log.Logf(loggerCore.WarnLevel, l.traceWarnStr, caller, slowLog, float64(elapsed.Nanoseconds())/1e6, rows, l.sql(sql))
 			}
-		case l.LogLevel == logger.Info:
+		case l.LogLevel == logger.Info && loggerCore.Enabled(Module, loggerCore.TraceLevel) && l.sampled():
 			sql, rows := fc()
+			caller := utils.FileWithLineNum()
+			log := l.traceLogger(ctx, caller, elapsed, rows, nil)
 			if rows == -1 {
-				//l.Printf(l.traceStr, utils.FileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, "-", sql)
-				log.Logf(loggerCore.TraceLevel, l.traceStr, utils.FileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, "-", sql)
+				log.Logf(loggerCore.TraceLevel, l.traceStr, caller, float64(elapsed.Nanoseconds())/1e6, "-", l.sql(sql))
 			} else {
-				//l.Printf(l.traceStr, utils.FileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, rows, sql)
-				log.Logf(loggerCore.TraceLevel, l.traceStr, utils.FileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, rows, sql)
+				log.Logf(loggerCore.TraceLevel, l.traceStr, caller, float64(elapsed.Nanoseconds())/1e6, rows, l.sql(sql))
 			}
 		}
 	}
@@ -133,7 +181,8 @@ func (l *traceRecorder) Trace(ctx context.Context, begin time.Time, fc func() (s
 	l.Err = err
 }
 
-func New(config logger.Config) logger.Interface {
+// New 基于core logger的gorm logger, 可选慢查询阈值(config.SlowThreshold)、采样及脱敏
+func New(config logger.Config, opts ...Option) logger.Interface {
 	var (
 		infoStr      = "%s\n[info] "
 		warnStr      = "%s\n[warn] "
@@ -152,8 +201,13 @@ func New(config logger.Config) logger.Interface {
 		traceErrStr = RedBold + "%s " + MagentaBold + "%s " + Reset + Yellow + "[%.3fms] " + BlueBold + "[rows:%v]" + Reset + " %s"
 	}
 
+	options := Options{}
+	for _, o := range opts {
+		o(&options)
+	}
 	return &gormLogger{
 		Config:       config,
+		opts:         options,
 		infoStr:      infoStr,
 		warnStr:      warnStr,
 		errStr:       errStr,
@@ -1,7 +1,9 @@
 package logger
 
 import (
+	"bytes"
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,3 +20,26 @@ func TestNew(t *testing.T) {
 	})
 	l.Info(context.TODO(), "test")
 }
+
+func TestTrace(t *testing.T) {
+	var buf bytes.Buffer
+	prev := logCore.DefaultLogger
+	logCore.DefaultLogger = logCore.NewLogger(logCore.WithLevel(logCore.InfoLevel), logCore.WithOutput(&buf))
+	defer func() { logCore.DefaultLogger = prev }()
+
+	l := New(logger.Config{SlowThreshold: time.Millisecond, LogLevel: logger.Info}, WithRedact(true))
+	ctx := logCore.WithTraceID(context.Background(), "t1")
+	l.Trace(ctx, time.Now().Add(-time.Second), func() (string, int64) {
+		return "SELECT * FROM `sys_user` WHERE password = 'secret' AND id = 12", 1
+	}, nil)
+
+	out := buf.String()
+	for _, want := range []string{"SLOW SQL", "trace-id=t1", "slow=true", "rows=1", "password = ? AND id = ?"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in %q", want, out)
+		}
+	}
+	if strings.Contains(out, "secret") {
+		t.Errorf("parameter should be redacted: %q", out)
+	}
+}
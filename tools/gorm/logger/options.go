@@ -0,0 +1,24 @@
+package logger
+
+// Options 可配置参数
+type Options struct {
+	sampleRate float64
+	redact     bool
+}
+
+// Option set options
+type Option func(*Options)
+
+// WithSampleRate 普通sql的采样率(0, 1), 慢查询和错误始终记录, 默认全部记录
+func WithSampleRate(rate float64) Option {
+	return func(o *Options) {
+		o.sampleRate = rate
+	}
+}
+
+// WithRedact 将sql中的字符串、数字参数替换为 ?, 避免敏感数据写入日志
+func WithRedact(redact bool) Option {
+	return func(o *Options) {
+		o.redact = redact
+	}
+}
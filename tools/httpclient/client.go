@@ -0,0 +1,174 @@
+// Package httpclient 创建调用第三方接口的 http.Client, 预置超时、连接池、代理、重试、按host限流,
+// 并把 ctx 中的请求id、链路id透传给下游
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/tools/resilience"
+)
+
+// 透传给下游的链路header, 与 sdk/middleware.Trace 读取的一致
+const (
+	RequestIDHeader = "X-Request-Id"
+	TraceIDHeader   = "X-Trace-Id"
+)
+
+// New 创建 http.Client
+//
+//	c := httpclient.New(
+//		httpclient.WithTimeout(10*time.Second),
+//		httpclient.WithRetry(resilience.WithAttempts(3)),
+//		httpclient.WithRateLimit(20, 5),
+//		httpclient.WithTransport(otel.Transport),
+//	)
+func New(opts ...Option) *http.Client {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &http.Client{Timeout: o.timeout, Transport: newTransport(o)}
+}
+
+// NewTransport 只创建 Transport, 用于需要自行构造 http.Client 的场景
+func NewTransport(opts ...Option) http.RoundTripper {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return newTransport(o)
+}
+
+func newTransport(o options) http.RoundTripper {
+	dialer := &net.Dialer{Timeout: o.dialTimeout, KeepAlive: 30 * time.Second}
+	var rt http.RoundTripper = &http.Transport{
+		Proxy:                 o.proxy,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		TLSClientConfig:       o.tlsConfig,
+		TLSHandshakeTimeout:   o.tlsTimeout,
+		MaxIdleConns:          o.maxIdleConns,
+		MaxIdleConnsPerHost:   o.maxIdlePerHost,
+		MaxConnsPerHost:       o.maxConnsPerHost,
+		IdleConnTimeout:       o.idleConnTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+	for i := len(o.wrappers) - 1; i >= 0; i-- {
+		rt = o.wrappers[i](rt)
+	}
+	rt = &headerTransport{next: rt, header: o.header}
+	if o.limit.rps > 0 || len(o.hostLimits) > 0 {
+		rt = &limitTransport{next: rt, limiter: newLimiter(o.limit, o.hostLimits)}
+	}
+	if o.retry != nil {
+		rt = &retryTransport{next: rt, opts: o.retry, status: o.retryStatus}
+	}
+	return rt
+}
+
+// headerTransport 设置默认header与链路header
+type headerTransport struct {
+	next   http.RoundTripper
+	header http.Header
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	requestID, traceID := logger.RequestID(ctx), logger.TraceID(ctx)
+	if len(t.header) == 0 && requestID == "" && traceID == "" {
+		return t.next.RoundTrip(req)
+	}
+	// RoundTripper 不能修改传入的请求
+	r := req.Clone(ctx)
+	for k, v := range t.header {
+		if _, ok := r.Header[k]; !ok {
+			r.Header[k] = v
+		}
+	}
+	if requestID != "" && r.Header.Get(RequestIDHeader) == "" {
+		r.Header.Set(RequestIDHeader, requestID)
+	}
+	if traceID != "" && r.Header.Get(TraceIDHeader) == "" {
+		r.Header.Set(TraceIDHeader, traceID)
+	}
+	return t.next.RoundTrip(r)
+}
+
+// statusError 需要重试的响应, 不再重试时把响应原样返回
+type statusError struct {
+	resp *http.Response
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("httpclient: status %d", e.resp.StatusCode)
+}
+
+type retryTransport struct {
+	next   http.RoundTripper
+	opts   []resilience.RetryOption
+	status map[int]bool
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !retryable(req) {
+		return t.next.RoundTrip(req)
+	}
+	first := true
+	opts := append(t.opts[:len(t.opts):len(t.opts)], resilience.WithOnRetry(func(attempt int, err error, wait time.Duration) {
+		var se *statusError
+		if errors.As(err, &se) {
+			// 丢弃响应体以复用连接
+			_, _ = io.Copy(io.Discard, io.LimitReader(se.resp.Body, 4<<10))
+			_ = se.resp.Body.Close()
+		}
+		logger.Module("httpclient").WithContext(req.Context()).Warn("retry request",
+			"method", req.Method, "host", req.URL.Host, "attempt", attempt, "wait", wait.String(), "error", err.Error())
+	}))
+	resp, err := resilience.Do(req.Context(), func(ctx context.Context) (*http.Response, error) {
+		r := req
+		if !first && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, resilience.Permanent(err)
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+		first = false
+		resp, err := t.next.RoundTrip(r)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, resilience.Permanent(err)
+			}
+			return nil, err
+		}
+		if t.status[resp.StatusCode] {
+			return nil, &statusError{resp: resp}
+		}
+		return resp, nil
+	}, opts...)
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.resp, nil
+	}
+	return resp, err
+}
+
+// retryable 幂等方法或带 Idempotency-Key 的请求, 有请求体时需要能重新读取
+func retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
@@ -0,0 +1,130 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+	"github.com/go-admin-team/go-admin-core/tools/resilience"
+)
+
+func TestRetry(t *testing.T) {
+	var n int32
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if atomic.AddInt32(&n, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	c := New(WithRetry(resilience.WithAttempts(3), resilience.WithBackoff(time.Millisecond, time.Millisecond)))
+
+	resp, err := c.Post(srv.URL, "text/plain", strings.NewReader("body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	// POST 不是幂等方法, 不重试
+	if resp.StatusCode != http.StatusServiceUnavailable || n != 1 {
+		t.Fatalf("post: status = %d, n = %d", resp.StatusCode, n)
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader("body"))
+	resp, err = c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(b) != "ok" || n != 3 || bodies[2] != "body" {
+		t.Errorf("put: body = %s, n = %d, bodies = %v", b, n, bodies)
+	}
+
+	// 重试用完后返回最后一次的响应
+	atomic.StoreInt32(&n, -10)
+	resp, err = c.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || n != -7 {
+		t.Errorf("exhausted: status = %d, n = %d", resp.StatusCode, n)
+	}
+}
+
+func TestHeader(t *testing.T) {
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+	}))
+	defer srv.Close()
+	c := New(WithHeader("User-Agent", "go-admin"), WithHeader("X-App", "a"))
+	ctx := logger.WithTraceID(logger.WithRequestID(context.Background(), "req-1"), "trace-1")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	req.Header.Set("X-App", "b")
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if header.Get(RequestIDHeader) != "req-1" || header.Get(TraceIDHeader) != "trace-1" ||
+		header.Get("User-Agent") != "go-admin" || header.Get("X-App") != "b" {
+		t.Errorf("header = %v", header)
+	}
+	if req.Header.Get(RequestIDHeader) != "" {
+		t.Errorf("request modified")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+	c := New(WithRateLimit(1000, 10), WithHostRateLimit(host, 50, 1))
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		resp, err := c.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+	// 第一个立即发出, 之后每个间隔20ms
+	if d := time.Since(start); d < 35*time.Millisecond {
+		t.Errorf("elapsed = %v", d)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if _, err := c.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestProxy(t *testing.T) {
+	var path string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.String()
+	}))
+	defer proxy.Close()
+	resp, err := New(WithProxy(proxy.URL)).Get("http://example.invalid/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if path != "http://example.invalid/a" {
+		t.Errorf("path = %s", path)
+	}
+}
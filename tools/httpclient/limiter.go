@@ -0,0 +1,96 @@
+package httpclient
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type limit struct {
+	rps   float64
+	burst int
+}
+
+// bucket 令牌桶, tokens 可以为负数, 表示已被预占的等待者
+type bucket struct {
+	limit
+	tokens float64
+	last   time.Time
+}
+
+// reserve 取一个令牌, 返回需要等待的时间
+func (b *bucket) reserve(now time.Time) time.Duration {
+	burst := math.Max(float64(b.burst), 1)
+	if b.last.IsZero() {
+		b.tokens = burst
+	} else {
+		b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*b.rps)
+	}
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rps * float64(time.Second))
+}
+
+// limiter 按host限流, 进程内生效
+type limiter struct {
+	mux     sync.Mutex
+	def     limit
+	hosts   map[string]limit
+	buckets map[string]*bucket
+}
+
+func newLimiter(def limit, hosts map[string]limit) *limiter {
+	return &limiter{def: def, hosts: hosts, buckets: make(map[string]*bucket)}
+}
+
+// wait 等待到可以发出请求, ctx 结束时返回错误
+func (l *limiter) wait(ctx context.Context, host string) error {
+	l.mux.Lock()
+	b, ok := l.buckets[host]
+	if !ok {
+		lim, ok := l.hosts[host]
+		if !ok {
+			lim = l.def
+		}
+		if lim.rps <= 0 {
+			l.mux.Unlock()
+			return nil
+		}
+		b = &bucket{limit: lim}
+		l.buckets[host] = b
+	}
+	d := b.reserve(time.Now())
+	l.mux.Unlock()
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// 归还预占的令牌
+		l.mux.Lock()
+		b.tokens++
+		l.mux.Unlock()
+		return ctx.Err()
+	}
+}
+
+type limitTransport struct {
+	next    http.RoundTripper
+	limiter *limiter
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.wait(req.Context(), req.URL.Host); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
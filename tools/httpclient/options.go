@@ -0,0 +1,146 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/tools/resilience"
+)
+
+type Option func(*options)
+
+type options struct {
+	timeout         time.Duration
+	dialTimeout     time.Duration
+	tlsTimeout      time.Duration
+	idleConnTimeout time.Duration
+	maxIdleConns    int
+	maxIdlePerHost  int
+	maxConnsPerHost int
+	proxy           func(*http.Request) (*url.URL, error)
+	tlsConfig       *tls.Config
+	header          http.Header
+	retry           []resilience.RetryOption
+	retryStatus     map[int]bool
+	limit           limit
+	hostLimits      map[string]limit
+	wrappers        []func(http.RoundTripper) http.RoundTripper
+}
+
+func setDefault() options {
+	return options{
+		timeout:         30 * time.Second,
+		dialTimeout:     5 * time.Second,
+		tlsTimeout:      5 * time.Second,
+		idleConnTimeout: 90 * time.Second,
+		maxIdleConns:    100,
+		maxIdlePerHost:  10,
+		proxy:           http.ProxyFromEnvironment,
+		retryStatus: map[int]bool{
+			http.StatusTooManyRequests:    true,
+			http.StatusBadGateway:         true,
+			http.StatusServiceUnavailable: true,
+			http.StatusGatewayTimeout:     true,
+		},
+		hostLimits: make(map[string]limit),
+	}
+}
+
+// WithTimeout 整个请求(含重试与读取响应体)的超时, 默认30s
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithDialTimeout 建立连接与tls握手的超时, 默认都为5s
+func WithDialTimeout(dial, tls time.Duration) Option {
+	return func(o *options) {
+		o.dialTimeout = dial
+		o.tlsTimeout = tls
+	}
+}
+
+// WithPool 连接池, 默认最多100个空闲连接, 每个host 10个, 不限制每个host的连接数
+func WithPool(maxIdle, maxIdlePerHost, maxConnsPerHost int, idleTimeout time.Duration) Option {
+	return func(o *options) {
+		o.maxIdleConns = maxIdle
+		o.maxIdlePerHost = maxIdlePerHost
+		o.maxConnsPerHost = maxConnsPerHost
+		o.idleConnTimeout = idleTimeout
+	}
+}
+
+// WithProxy 代理地址, 如 http://127.0.0.1:7890, socks5://127.0.0.1:1080;
+// 默认读取 HTTP_PROXY、HTTPS_PROXY、NO_PROXY 环境变量, 地址错误时请求返回错误
+func WithProxy(proxy string) Option {
+	return func(o *options) {
+		if proxy == "" {
+			o.proxy = nil
+			return
+		}
+		u, err := url.Parse(proxy)
+		o.proxy = func(*http.Request) (*url.URL, error) {
+			return u, err
+		}
+	}
+}
+
+// WithTLSConfig 自定义证书、跳过校验等
+func WithTLSConfig(c *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = c
+	}
+}
+
+// WithHeader 每个请求都带上的header, 请求中已设置的不覆盖
+func WithHeader(key, value string) Option {
+	return func(o *options) {
+		if o.header == nil {
+			o.header = make(http.Header)
+		}
+		o.header.Add(key, value)
+	}
+}
+
+// WithRetry 启用重试, 网络错误与 429、502、503、504 时重试;
+// 只重试幂等方法或带 Idempotency-Key 的请求, 有请求体时需要 req.GetBody(http.NewRequest 会自动设置)
+func WithRetry(opts ...resilience.RetryOption) Option {
+	return func(o *options) {
+		o.retry = append([]resilience.RetryOption{resilience.WithAttempts(3)}, opts...)
+	}
+}
+
+// WithRetryStatus 替换需要重试的响应状态码
+func WithRetryStatus(codes ...int) Option {
+	return func(o *options) {
+		o.retryStatus = make(map[int]bool, len(codes))
+		for _, code := range codes {
+			o.retryStatus[code] = true
+		}
+	}
+}
+
+// WithRateLimit 每个host每秒最多 rps 个请求, 允许突发 burst 个, 超出时等待
+func WithRateLimit(rps float64, burst int) Option {
+	return func(o *options) {
+		o.limit = limit{rps: rps, burst: burst}
+	}
+}
+
+// WithHostRateLimit 单独设置某个host的限制, host 与 req.URL.Host 相同, 含端口
+func WithHostRateLimit(host string, rps float64, burst int) Option {
+	return func(o *options) {
+		o.hostLimits[host] = limit{rps: rps, burst: burst}
+	}
+}
+
+// WithTransport 包装 Transport, 如 otel.Transport 链路追踪、discovery.Resolver.Transport 服务发现;
+// 按添加顺序由外到内, 位于重试与限流之内, 每次重试都会经过
+func WithTransport(wrap func(http.RoundTripper) http.RoundTripper) Option {
+	return func(o *options) {
+		o.wrappers = append(o.wrappers, wrap)
+	}
+}
@@ -0,0 +1,263 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+var (
+	// ErrClosed 协程池已关闭
+	ErrClosed = errors.New("pool: closed")
+	// ErrFull 非阻塞模式下等待队列已满
+	ErrFull = errors.New("pool: queue full")
+)
+
+type Option func(*options)
+
+type options struct {
+	name     string
+	size     int
+	queue    int
+	nonBlock bool
+	panicFn  func(r interface{})
+}
+
+func setDefault() options {
+	return options{
+		name:  "default",
+		size:  100,
+		queue: 1024,
+	}
+}
+
+// WithName 名称, 用于日志与指标
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// WithSize 最大并发数, 默认100, 协程按需创建
+func WithSize(n int) Option {
+	return func(o *options) {
+		o.size = n
+	}
+}
+
+// WithQueueSize 等待队列长度, 默认1024, 满了之后 Submit 阻塞
+func WithQueueSize(n int) Option {
+	return func(o *options) {
+		o.queue = n
+	}
+}
+
+// WithNonBlocking 等待队列满时 Submit 直接返回 ErrFull, 不阻塞
+func WithNonBlocking() Option {
+	return func(o *options) {
+		o.nonBlock = true
+	}
+}
+
+// WithPanicHandler 任务panic时的回调, 默认记录Error日志
+func WithPanicHandler(f func(r interface{})) Option {
+	return func(o *options) {
+		o.panicFn = f
+	}
+}
+
+// Stats 运行状态
+type Stats struct {
+	Name string
+	// Size 最大并发数
+	Size int
+	// Workers 已创建的协程数
+	Workers int
+	// Running 正在执行的任务数
+	Running int
+	// Waiting 等待执行的任务数
+	Waiting   int
+	Completed uint64
+	Panics    uint64
+	// Rejected 非阻塞模式下因队列满被拒绝的任务数
+	Rejected uint64
+}
+
+// Pool 有界协程池, 替代不受控的 go func(), 任务panic不会导致进程退出;
+// 实现了 runtime.Component, Stop 时等待已提交的任务执行完
+type Pool struct {
+	o         options
+	tasks     chan func()
+	quit      chan struct{}
+	mux       sync.RWMutex
+	closed    int32
+	wg        sync.WaitGroup
+	workers   int32
+	running   int32
+	completed uint64
+	panics    uint64
+	rejected  uint64
+}
+
+// New 创建协程池
+func New(opts ...Option) *Pool {
+	o := setDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.size <= 0 {
+		o.size = 1
+	}
+	if o.queue < 0 {
+		o.queue = 0
+	}
+	return &Pool{o: o, tasks: make(chan func(), o.queue), quit: make(chan struct{})}
+}
+
+func (p *Pool) String() string {
+	return "pool." + p.o.name
+}
+
+// Start 协程按需创建, 无需启动
+func (p *Pool) Start(context.Context) error {
+	return nil
+}
+
+// Stop 同 Close
+func (p *Pool) Stop(ctx context.Context) error {
+	return p.Close(ctx)
+}
+
+// Submit 提交任务, 等待队列满时阻塞, 非阻塞模式下返回 ErrFull
+func (p *Pool) Submit(f func()) error {
+	return p.submit(context.Background(), f)
+}
+
+// SubmitContext 提交任务, 等待队列满时阻塞到 ctx 结束
+func (p *Pool) SubmitContext(ctx context.Context, f func()) error {
+	return p.submit(ctx, f)
+}
+
+func (p *Pool) submit(ctx context.Context, f func()) error {
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+	if atomic.LoadInt32(&p.closed) == 1 {
+		return ErrClosed
+	}
+	select {
+	case p.tasks <- f:
+		p.spawn(0)
+		return nil
+	default:
+	}
+	p.spawn(1)
+	if p.o.nonBlock {
+		atomic.AddUint64(&p.rejected, 1)
+		return ErrFull
+	}
+	select {
+	case p.tasks <- f:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.quit:
+		return ErrClosed
+	}
+}
+
+// spawn 执行中、等待中与尚未入队的 pending 个任务多于协程数, 且未达到最大并发时创建协程
+func (p *Pool) spawn(pending int) {
+	for {
+		n := atomic.LoadInt32(&p.workers)
+		if int(n) >= p.o.size || int(atomic.LoadInt32(&p.running))+len(p.tasks)+pending <= int(n) {
+			return
+		}
+		if atomic.CompareAndSwapInt32(&p.workers, n, n+1) {
+			p.wg.Add(1)
+			go p.worker()
+			return
+		}
+	}
+}
+
+func (p *Pool) worker() {
+	defer p.wg.Done()
+	for f := range p.tasks {
+		p.run(f)
+	}
+}
+
+func (p *Pool) run(f func()) {
+	atomic.AddInt32(&p.running, 1)
+	defer func() {
+		atomic.AddInt32(&p.running, -1)
+		atomic.AddUint64(&p.completed, 1)
+		if r := recover(); r != nil {
+			atomic.AddUint64(&p.panics, 1)
+			if p.o.panicFn != nil {
+				p.o.panicFn(r)
+				return
+			}
+			logger.Module("pool").Error("task panic", "pool", p.o.name, "panic", r, "stack", string(debug.Stack()))
+		}
+	}()
+	f()
+}
+
+// Close 不再接受新任务, 等待已提交的任务执行完, ctx 结束时不再等待
+func (p *Pool) Close(ctx context.Context) error {
+	if atomic.CompareAndSwapInt32(&p.closed, 0, 1) {
+		// 先唤醒阻塞的 Submit, 等它们退出后再关闭任务队列
+		close(p.quit)
+		p.mux.Lock()
+		close(p.tasks)
+		p.mux.Unlock()
+	}
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats 当前运行状态
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Name:      p.o.name,
+		Size:      p.o.size,
+		Workers:   int(atomic.LoadInt32(&p.workers)),
+		Running:   int(atomic.LoadInt32(&p.running)),
+		Waiting:   len(p.tasks),
+		Completed: atomic.LoadUint64(&p.completed),
+		Panics:    atomic.LoadUint64(&p.panics),
+		Rejected:  atomic.LoadUint64(&p.rejected),
+	}
+}
+
+var (
+	stdOnce sync.Once
+	std     *Pool
+)
+
+// Default 默认协程池, 用于临时的异步任务
+func Default() *Pool {
+	stdOnce.Do(func() {
+		std = New()
+	})
+	return std
+}
+
+// Go 使用默认协程池执行 f
+func Go(f func()) error {
+	return Default().Submit(f)
+}
@@ -0,0 +1,102 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool(t *testing.T) {
+	p := New(WithName("test"), WithSize(4), WithQueueSize(100))
+	var running, max, done int32
+	for i := 0; i < 50; i++ {
+		if err := p.Submit(func() {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&max)
+				if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&done, 1)
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if done != 50 || max > 4 || max < 2 {
+		t.Errorf("done = %d, max concurrency = %d", done, max)
+	}
+	s := p.Stats()
+	if s.Workers != 4 || s.Completed != 50 || s.Running != 0 || s.Waiting != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+	if err := p.Submit(func() {}); !errors.Is(err, ErrClosed) {
+		t.Errorf("submit after close: err = %v", err)
+	}
+}
+
+func TestPanic(t *testing.T) {
+	var recovered interface{}
+	p := New(WithSize(1), WithPanicHandler(func(r interface{}) { recovered = r }))
+	_ = p.Submit(func() { panic("boom") })
+	var ok bool
+	_ = p.Submit(func() { ok = true })
+	_ = p.Close(context.Background())
+	if recovered != "boom" || !ok || p.Stats().Panics != 1 {
+		t.Errorf("recovered = %v, ok = %v, stats = %+v", recovered, ok, p.Stats())
+	}
+}
+
+func TestBackpressure(t *testing.T) {
+	block := make(chan struct{})
+	p := New(WithSize(1), WithQueueSize(1), WithNonBlocking())
+	_ = p.Submit(func() { <-block })
+	// 等待唯一的协程取走第一个任务
+	for p.Stats().Running == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if err := p.Submit(func() {}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Submit(func() {}); !errors.Is(err, ErrFull) || p.Stats().Rejected != 1 {
+		t.Errorf("err = %v, stats = %+v", err, p.Stats())
+	}
+
+	b := New(WithSize(1), WithQueueSize(0))
+	_ = b.Submit(func() { <-block })
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.SubmitContext(ctx, func() {}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("blocking submit: err = %v", err)
+	}
+
+	// Close 唤醒阻塞的 Submit, 超时后不再等待执行中的任务
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var err error
+	go func() {
+		defer wg.Done()
+		err = b.Submit(func() {})
+	}()
+	time.Sleep(5 * time.Millisecond)
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if e := b.Close(ctx); !errors.Is(e, context.DeadlineExceeded) {
+		t.Errorf("close: err = %v", e)
+	}
+	wg.Wait()
+	if !errors.Is(err, ErrClosed) {
+		t.Errorf("blocked submit: err = %v", err)
+	}
+	close(block)
+	_ = p.Close(context.Background())
+	_ = b.Close(context.Background())
+}
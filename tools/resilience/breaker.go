@@ -0,0 +1,294 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-admin-team/go-admin-core/logger"
+)
+
+var (
+	// ErrOpen 熔断器打开, 请求被直接拒绝
+	ErrOpen = errors.New("resilience: circuit breaker is open")
+	// ErrTooManyProbes 半开状态下探测请求已满
+	ErrTooManyProbes = errors.New("resilience: too many probe requests")
+)
+
+// State 熔断器状态
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	default:
+		return "half-open"
+	}
+}
+
+// Counts 当前统计窗口内的请求数
+type Counts struct {
+	Requests             int
+	Failures             int
+	ConsecutiveFailures  int
+	ConsecutiveSuccesses int
+}
+
+type BreakerOption func(*breakerOptions)
+
+type breakerOptions struct {
+	consecutive int
+	ratio       float64
+	minRequests int
+	window      time.Duration
+	openTimeout time.Duration
+	probes      int
+	isFailure   func(err error) bool
+	onChange    func(name string, from, to State)
+}
+
+func setBreakerDefault() breakerOptions {
+	return breakerOptions{
+		consecutive: 5,
+		window:      time.Minute,
+		openTimeout: 30 * time.Second,
+		probes:      1,
+	}
+}
+
+// WithConsecutiveFailures 连续失败 n 次后打开, 默认5, 0为不按连续失败判断
+func WithConsecutiveFailures(n int) BreakerOption {
+	return func(o *breakerOptions) {
+		o.consecutive = n
+	}
+}
+
+// WithFailureRatio 统计窗口内请求数不少于 minRequests 且失败率达到 ratio 时打开
+func WithFailureRatio(ratio float64, minRequests int) BreakerOption {
+	return func(o *breakerOptions) {
+		o.ratio = ratio
+		o.minRequests = minRequests
+	}
+}
+
+// WithWindow 关闭状态下的统计窗口, 到期后清零, 默认1分钟
+func WithWindow(d time.Duration) BreakerOption {
+	return func(o *breakerOptions) {
+		o.window = d
+	}
+}
+
+// WithOpenTimeout 打开后经过 d 进入半开状态, 默认30s
+func WithOpenTimeout(d time.Duration) BreakerOption {
+	return func(o *breakerOptions) {
+		o.openTimeout = d
+	}
+}
+
+// WithProbes 半开状态允许的探测请求数, 全部成功后关闭, 任一失败重新打开, 默认1
+func WithProbes(n int) BreakerOption {
+	return func(o *breakerOptions) {
+		o.probes = n
+	}
+}
+
+// WithIsFailure 判断错误是否计为失败, 默认 Permanent 与 ctx 取消之外的错误都计为失败
+func WithIsFailure(f func(err error) bool) BreakerOption {
+	return func(o *breakerOptions) {
+		o.isFailure = f
+	}
+}
+
+// WithStateChange 状态变化时的回调
+func WithStateChange(f func(name string, from, to State)) BreakerOption {
+	return func(o *breakerOptions) {
+		o.onChange = f
+	}
+}
+
+// Breaker 熔断器, 下游持续失败时快速失败, 一段时间后放行少量请求探测是否恢复
+type Breaker struct {
+	name     string
+	o        breakerOptions
+	mux      sync.Mutex
+	state    State
+	counts   Counts
+	expiry   time.Time
+	inflight int
+	// generation 每次状态变化或窗口重置后递增, 丢弃之前发出的请求的结果
+	generation uint64
+}
+
+// NewBreaker name 用于日志与回调
+func NewBreaker(name string, opts ...BreakerOption) *Breaker {
+	o := setBreakerDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.probes <= 0 {
+		o.probes = 1
+	}
+	b := &Breaker{name: name, o: o}
+	b.toState(StateClosed, time.Now())
+	return b
+}
+
+func (b *Breaker) String() string {
+	return b.name
+}
+
+// State 当前状态
+func (b *Breaker) State() State {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.current(time.Now())
+	return b.state
+}
+
+// Counts 当前统计窗口内的请求数
+func (b *Breaker) Counts() Counts {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.current(time.Now())
+	return b.counts
+}
+
+// Do 通过熔断器执行 f, 打开时返回 ErrOpen
+func (b *Breaker) Do(ctx context.Context, f func(ctx context.Context) error) error {
+	_, err := Execute(b, ctx, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, f(ctx)
+	})
+	return err
+}
+
+// Execute 带返回值的 Breaker.Do
+func Execute[T any](b *Breaker, ctx context.Context, f func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	generation, err := b.before()
+	if err != nil {
+		return zero, err
+	}
+	done := false
+	defer func() {
+		// f panic 时计为失败
+		if !done {
+			b.after(generation, true)
+		}
+	}()
+	v, err := f(ctx)
+	done = true
+	b.after(generation, b.failed(err))
+	return v, err
+}
+
+func (b *Breaker) failed(err error) bool {
+	if err == nil {
+		return false
+	}
+	if b.o.isFailure != nil {
+		return b.o.isFailure(err)
+	}
+	return !IsPermanent(err) && !errors.Is(err, context.Canceled)
+}
+
+func (b *Breaker) before() (uint64, error) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.current(time.Now())
+	switch b.state {
+	case StateOpen:
+		return 0, ErrOpen
+	case StateHalfOpen:
+		if b.inflight >= b.o.probes {
+			return 0, ErrTooManyProbes
+		}
+	}
+	b.inflight++
+	b.counts.Requests++
+	return b.generation, nil
+}
+
+func (b *Breaker) after(generation uint64, failed bool) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	now := time.Now()
+	b.current(now)
+	if generation != b.generation {
+		return
+	}
+	b.inflight--
+	if failed {
+		b.counts.Failures++
+		b.counts.ConsecutiveFailures++
+		b.counts.ConsecutiveSuccesses = 0
+		if b.state == StateHalfOpen || b.tripped() {
+			b.toState(StateOpen, now)
+		}
+		return
+	}
+	b.counts.ConsecutiveSuccesses++
+	b.counts.ConsecutiveFailures = 0
+	if b.state == StateHalfOpen && b.counts.ConsecutiveSuccesses >= b.o.probes {
+		b.toState(StateClosed, now)
+	}
+}
+
+func (b *Breaker) tripped() bool {
+	c := b.counts
+	if b.o.consecutive > 0 && c.ConsecutiveFailures >= b.o.consecutive {
+		return true
+	}
+	return b.o.ratio > 0 && c.Requests >= b.o.minRequests &&
+		float64(c.Failures)/float64(c.Requests) >= b.o.ratio
+}
+
+// current 按时间推进状态: 打开超时后半开, 关闭状态下窗口到期后清零
+func (b *Breaker) current(now time.Time) {
+	if b.expiry.IsZero() || now.Before(b.expiry) {
+		return
+	}
+	switch b.state {
+	case StateOpen:
+		b.toState(StateHalfOpen, now)
+	case StateClosed:
+		b.reset(now)
+	}
+}
+
+func (b *Breaker) reset(now time.Time) {
+	b.generation++
+	b.counts = Counts{}
+	b.inflight = 0
+	b.expiry = time.Time{}
+	switch b.state {
+	case StateClosed:
+		if b.o.window > 0 {
+			b.expiry = now.Add(b.o.window)
+		}
+	case StateOpen:
+		b.expiry = now.Add(b.o.openTimeout)
+	}
+}
+
+func (b *Breaker) toState(s State, now time.Time) {
+	from := b.state
+	b.state = s
+	b.reset(now)
+	if from == s {
+		return
+	}
+	logger.Module("resilience").Warn("circuit breaker state changed", "name", b.name, "from", from.String(), "to", s.String())
+	if b.o.onChange != nil {
+		b.o.onChange(b.name, from, s)
+	}
+}
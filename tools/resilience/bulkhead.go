@@ -0,0 +1,59 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrBulkheadFull 并发已满且等待超时
+var ErrBulkheadFull = errors.New("resilience: bulkhead is full")
+
+// Bulkhead 舱壁隔离, 限制调用某个依赖的并发数, 避免一个慢依赖占满所有协程与连接
+type Bulkhead struct {
+	sem     chan struct{}
+	maxWait time.Duration
+}
+
+// NewBulkhead 最多 max 个并发, 已满时最多等待 maxWait, 0为不等待
+func NewBulkhead(max int, maxWait time.Duration) *Bulkhead {
+	if max <= 0 {
+		max = 1
+	}
+	return &Bulkhead{sem: make(chan struct{}, max), maxWait: maxWait}
+}
+
+// Do 获取到并发名额后执行 f
+func (b *Bulkhead) Do(ctx context.Context, f func(ctx context.Context) error) error {
+	if err := b.acquire(ctx); err != nil {
+		return err
+	}
+	defer func() { <-b.sem }()
+	return f(ctx)
+}
+
+// Running 正在执行的数量
+func (b *Bulkhead) Running() int {
+	return len(b.sem)
+}
+
+func (b *Bulkhead) acquire(ctx context.Context) error {
+	select {
+	case b.sem <- struct{}{}:
+		return nil
+	default:
+	}
+	if b.maxWait <= 0 {
+		return ErrBulkheadFull
+	}
+	timer := time.NewTimer(b.maxWait)
+	defer timer.Stop()
+	select {
+	case b.sem <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrBulkheadFull
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
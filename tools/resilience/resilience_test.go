@@ -0,0 +1,205 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errTest = errors.New("test")
+
+func TestRetry(t *testing.T) {
+	var n int
+	var waits []time.Duration
+	err := Retry(context.Background(), func(ctx context.Context) error {
+		n++
+		if n < 3 {
+			return errTest
+		}
+		return nil
+	}, WithAttempts(5), WithBackoff(time.Millisecond, 10*time.Millisecond), WithJitter(0),
+		WithOnRetry(func(attempt int, err error, wait time.Duration) {
+			waits = append(waits, wait)
+		}))
+	if err != nil || n != 3 {
+		t.Fatalf("err = %v, n = %d", err, n)
+	}
+	if len(waits) != 2 || waits[0] != time.Millisecond || waits[1] != 2*time.Millisecond {
+		t.Errorf("waits = %v", waits)
+	}
+
+	n = 0
+	v, err := Do(context.Background(), func(ctx context.Context) (int, error) {
+		n++
+		return 0, Permanent(errTest)
+	})
+	if err != errTest || n != 1 || v != 0 {
+		t.Errorf("permanent: err = %v, n = %d", err, n)
+	}
+
+	n = 0
+	err = Retry(context.Background(), func(ctx context.Context) error {
+		n++
+		return errTest
+	}, WithAttempts(3), WithBackoff(time.Microsecond, time.Microsecond))
+	if err != errTest || n != 3 {
+		t.Errorf("exhausted: err = %v, n = %d", err, n)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = Retry(ctx, func(ctx context.Context) error {
+		return errTest
+	}, WithAttempts(10), WithBackoff(time.Second, time.Second))
+	if err != errTest || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("ctx: err = %v, elapsed = %v", err, time.Since(start))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	o := setRetryDefault()
+	o.initial, o.max = 100*time.Millisecond, time.Second
+	for i := 0; i < 100; i++ {
+		if d := o.backoff(1); d < 50*time.Millisecond || d > 150*time.Millisecond {
+			t.Fatalf("backoff(1) = %v", d)
+		}
+		if d := o.backoff(10); d < 500*time.Millisecond || d > 1500*time.Millisecond {
+			t.Fatalf("backoff(10) = %v", d)
+		}
+	}
+}
+
+func TestBudget(t *testing.T) {
+	b := NewBudget(0.5, 0)
+	var calls int
+	for i := 0; i < 4; i++ {
+		_ = Retry(context.Background(), func(ctx context.Context) error {
+			calls++
+			return errTest
+		}, WithAttempts(2), WithBackoff(time.Microsecond, time.Microsecond), WithBudget(b))
+	}
+	// 4次请求存入2次重试的预算
+	if calls != 6 {
+		t.Errorf("calls = %d", calls)
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	var changes []State
+	var mux sync.Mutex
+	b := NewBreaker("test", WithConsecutiveFailures(3), WithOpenTimeout(20*time.Millisecond), WithProbes(2),
+		WithStateChange(func(name string, from, to State) {
+			mux.Lock()
+			changes = append(changes, to)
+			mux.Unlock()
+		}))
+	ctx := context.Background()
+	fail := func(ctx context.Context) error { return errTest }
+	ok := func(ctx context.Context) error { return nil }
+
+	for i := 0; i < 3; i++ {
+		_ = b.Do(ctx, fail)
+	}
+	if b.State() != StateOpen {
+		t.Fatalf("state = %v", b.State())
+	}
+	if err := b.Do(ctx, ok); err != ErrOpen {
+		t.Fatalf("open: err = %v", err)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	if b.State() != StateHalfOpen {
+		t.Fatalf("state = %v", b.State())
+	}
+	// 半开状态下最多2个探测请求同时执行
+	block := make(chan struct{})
+	started := make(chan struct{}, 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = b.Do(ctx, func(ctx context.Context) error {
+				started <- struct{}{}
+				<-block
+				return nil
+			})
+		}()
+	}
+	<-started
+	<-started
+	if err := b.Do(ctx, ok); err != ErrTooManyProbes {
+		t.Errorf("probe: err = %v", err)
+	}
+	close(block)
+	wg.Wait()
+	if b.State() != StateClosed {
+		t.Fatalf("state = %v", b.State())
+	}
+
+	// 探测失败重新打开
+	for i := 0; i < 3; i++ {
+		_ = b.Do(ctx, fail)
+	}
+	time.Sleep(25 * time.Millisecond)
+	_ = b.Do(ctx, fail)
+	if b.State() != StateOpen {
+		t.Errorf("state = %v", b.State())
+	}
+	mux.Lock()
+	defer mux.Unlock()
+	want := []State{StateOpen, StateHalfOpen, StateClosed, StateOpen, StateHalfOpen, StateOpen}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %v", changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("changes = %v", changes)
+		}
+	}
+}
+
+func TestBreakerRatio(t *testing.T) {
+	b := NewBreaker("ratio", WithConsecutiveFailures(0), WithFailureRatio(0.5, 4))
+	ctx := context.Background()
+	_ = b.Do(ctx, func(ctx context.Context) error { return errTest })
+	_ = b.Do(ctx, func(ctx context.Context) error { return nil })
+	_ = b.Do(ctx, func(ctx context.Context) error { return Permanent(errTest) })
+	if b.State() != StateClosed {
+		t.Fatalf("state = %v", b.State())
+	}
+	_ = b.Do(ctx, func(ctx context.Context) error { return errTest })
+	if b.State() != StateOpen {
+		t.Errorf("state = %v, counts = %+v", b.State(), b.Counts())
+	}
+}
+
+func TestBulkhead(t *testing.T) {
+	b := NewBulkhead(1, 0)
+	block := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_ = b.Do(context.Background(), func(ctx context.Context) error {
+			close(started)
+			<-block
+			return nil
+		})
+	}()
+	<-started
+	if err := b.Do(context.Background(), func(ctx context.Context) error { return nil }); err != ErrBulkheadFull {
+		t.Errorf("err = %v", err)
+	}
+	w := NewBulkhead(1, time.Second)
+	w.sem <- struct{}{}
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		<-w.sem
+	}()
+	if err := w.Do(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Errorf("wait: err = %v", err)
+	}
+	close(block)
+}
@@ -0,0 +1,214 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent 标记为不可重试的错误, 如参数错误、鉴权失败; Retry 返回时会去掉这层包装
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent 是否为 Permanent 标记的错误
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+type RetryOption func(*retryOptions)
+
+type retryOptions struct {
+	attempts   int
+	initial    time.Duration
+	max        time.Duration
+	multiplier float64
+	jitter     float64
+	retryIf    func(err error) bool
+	budget     *Budget
+	onRetry    func(attempt int, err error, wait time.Duration)
+}
+
+func setRetryDefault() retryOptions {
+	return retryOptions{
+		attempts:   3,
+		initial:    100 * time.Millisecond,
+		max:        10 * time.Second,
+		multiplier: 2,
+		jitter:     0.5,
+	}
+}
+
+// WithAttempts 最多执行次数, 包含第一次, 默认3
+func WithAttempts(n int) RetryOption {
+	return func(o *retryOptions) {
+		o.attempts = n
+	}
+}
+
+// WithBackoff 首次重试间隔与最大间隔, 默认100ms、10s
+func WithBackoff(initial, max time.Duration) RetryOption {
+	return func(o *retryOptions) {
+		o.initial = initial
+		o.max = max
+	}
+}
+
+// WithMultiplier 间隔的增长倍数, 默认2
+func WithMultiplier(f float64) RetryOption {
+	return func(o *retryOptions) {
+		o.multiplier = f
+	}
+}
+
+// WithJitter 间隔的随机浮动比例, 0~1, 默认0.5, 即在 [0.5, 1.5] 倍之间, 避免大量客户端同时重试
+func WithJitter(f float64) RetryOption {
+	return func(o *retryOptions) {
+		o.jitter = f
+	}
+}
+
+// WithRetryIf 判断错误是否需要重试, 默认 Permanent 与 ctx 取消之外的错误都重试
+func WithRetryIf(f func(err error) bool) RetryOption {
+	return func(o *retryOptions) {
+		o.retryIf = f
+	}
+}
+
+// WithBudget 使用重试预算, 预算用完后不再重试
+func WithBudget(b *Budget) RetryOption {
+	return func(o *retryOptions) {
+		o.budget = b
+	}
+}
+
+// WithOnRetry 每次重试前的回调, attempt 从1开始, 可用于记录日志与指标
+func WithOnRetry(f func(attempt int, err error, wait time.Duration)) RetryOption {
+	return func(o *retryOptions) {
+		o.onRetry = f
+	}
+}
+
+// Retry 执行 f, 失败时按指数退避重试, 返回最后一次的错误; ctx 结束时立即返回
+//
+//	err := resilience.Retry(ctx, func(ctx context.Context) error {
+//		return client.Send(ctx, msg)
+//	}, resilience.WithAttempts(5))
+func Retry(ctx context.Context, f func(ctx context.Context) error, opts ...RetryOption) error {
+	_, err := Do(ctx, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, f(ctx)
+	}, opts...)
+	return err
+}
+
+// Do 带返回值的 Retry
+func Do[T any](ctx context.Context, f func(ctx context.Context) (T, error), opts ...RetryOption) (T, error) {
+	o := setRetryDefault()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.budget != nil {
+		o.budget.deposit()
+	}
+	var (
+		v   T
+		err error
+	)
+	for attempt := 1; ; attempt++ {
+		v, err = f(ctx)
+		if err == nil {
+			return v, nil
+		}
+		var p *permanentError
+		if errors.As(err, &p) {
+			return v, p.err
+		}
+		if attempt >= o.attempts || ctx.Err() != nil ||
+			(o.retryIf != nil && !o.retryIf(err)) ||
+			(o.budget != nil && !o.budget.withdraw()) {
+			return v, err
+		}
+		wait := o.backoff(attempt)
+		if o.onRetry != nil {
+			o.onRetry(attempt, err, wait)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return v, err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff 第 n 次重试前的等待时间
+func (o *retryOptions) backoff(n int) time.Duration {
+	d := float64(o.initial) * math.Pow(o.multiplier, float64(n-1))
+	if o.max > 0 && d > float64(o.max) {
+		d = float64(o.max)
+	}
+	if o.jitter > 0 {
+		d *= 1 - o.jitter + 2*o.jitter*rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// Budget 重试预算, 重试次数不超过请求数的 ratio 倍, 另外每秒保底允许 min 次;
+// 下游故障时限制重试放大的流量, 多个调用方可共用
+type Budget struct {
+	mux     sync.Mutex
+	ratio   float64
+	tokens  float64
+	max     float64
+	min     int
+	second  int64
+	minUsed int
+}
+
+// NewBudget 如 NewBudget(0.1, 10) 表示重试不超过请求数的10%, 每秒至少允许10次
+func NewBudget(ratio float64, min int) *Budget {
+	return &Budget{ratio: ratio, min: min, max: math.Max(ratio*1000, 1)}
+}
+
+func (b *Budget) deposit() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.tokens = math.Min(b.max, b.tokens+b.ratio)
+}
+
+func (b *Budget) withdraw() bool {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if now := time.Now().Unix(); now != b.second {
+		b.second, b.minUsed = now, 0
+	}
+	if b.minUsed < b.min {
+		b.minUsed++
+		return true
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return true
+	}
+	return false
+}
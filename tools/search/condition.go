@@ -72,6 +72,20 @@ type resolveSearchTag struct {
 	Join   string
 }
 
+// column 带表名的列, 未设置 table 时只使用列名
+func (e *resolveSearchTag) column(driver string) string {
+	if driver == Postgres {
+		if e.Table == "" {
+			return e.Column
+		}
+		return e.Table + "." + e.Column
+	}
+	if e.Table == "" {
+		return "`" + e.Column + "`"
+	}
+	return "`" + e.Table + "`.`" + e.Column + "`"
+}
+
 // makeTag 解析search的tag标签
 func makeTag(tag string) *resolveSearchTag {
 	r := &resolveSearchTag{}
@@ -0,0 +1,141 @@
+package search
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Page 列表接口的分页与排序参数, 嵌入查询结构体时需要加 `search:"-"`
+//
+//	type UserQuery struct {
+//		search.Page `search:"-"`
+//		Name string `search:"type:contains;column:name" form:"name"`
+//	}
+type Page struct {
+	PageIndex int `form:"pageIndex" json:"pageIndex"`
+	PageSize  int `form:"pageSize" json:"pageSize"`
+	// Sort 排序字段, 逗号分隔, - 开头为倒序, 如 -createdAt,name
+	Sort string `form:"sort" json:"sort"`
+}
+
+type Option func(*options)
+
+type options struct {
+	defaultSize int
+	maxSize     int
+	sortable    map[string]string
+	defaultSort string
+}
+
+func (o *options) setDefault() {
+	if o.defaultSize <= 0 {
+		o.defaultSize = 10
+	}
+	if o.maxSize <= 0 {
+		o.maxSize = 100
+	}
+}
+
+// WithDefaultPageSize 未传 pageSize 时的每页条数, 默认10
+func WithDefaultPageSize(n int) Option {
+	return func(o *options) {
+		o.defaultSize = n
+	}
+}
+
+// WithMaxPageSize 每页条数上限, 超出时按上限查询, 默认100
+func WithMaxPageSize(n int) Option {
+	return func(o *options) {
+		o.maxSize = n
+	}
+}
+
+// WithSortable 允许排序的字段, key 为请求中的字段名, value 为数据库列, 可带表名;
+// 不在其中的排序字段会被忽略, 未设置时不允许排序
+func WithSortable(fields map[string]string) Option {
+	return func(o *options) {
+		o.sortable = fields
+	}
+}
+
+// WithDefaultSort 未传 sort 时的排序, 格式同 Page.Sort
+func WithDefaultSort(sort string) Option {
+	return func(o *options) {
+		o.defaultSort = sort
+	}
+}
+
+// Normalize 按选项修正页码与每页条数
+func (p *Page) Normalize(opts ...Option) {
+	o := newOptions(opts)
+	p.normalize(o)
+}
+
+func (p *Page) normalize(o *options) {
+	if p.PageIndex <= 0 {
+		p.PageIndex = 1
+	}
+	if p.PageSize <= 0 {
+		p.PageSize = o.defaultSize
+	}
+	if p.PageSize > o.maxSize {
+		p.PageSize = o.maxSize
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.setDefault()
+	return o
+}
+
+// Order 按白名单生成排序
+func Order(sort string, opts ...Option) func(db *gorm.DB) *gorm.DB {
+	o := newOptions(opts)
+	return func(db *gorm.DB) *gorm.DB {
+		return order(db, sort, o)
+	}
+}
+
+func order(db *gorm.DB, sort string, o *options) *gorm.DB {
+	if sort == "" {
+		sort = o.defaultSort
+	}
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimLeft(field, "+-")
+		column, ok := o.sortable[field]
+		if !ok || field == "" {
+			continue
+		}
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc})
+	}
+	return db
+}
+
+// List 按 search 标签、排序白名单与分页生成查询, 会修正 p 的页码与每页条数
+// e.g. db.Model(&User{}).Scopes(search.List(&q, &q.Page, search.WithSortable(fields))).Find(&list)
+func List(q interface{}, p *Page, opts ...Option) func(db *gorm.DB) *gorm.DB {
+	o := newOptions(opts)
+	p.normalize(o)
+	return func(db *gorm.DB) *gorm.DB {
+		db = MakeCondition(q)(db)
+		db = order(db, p.Sort, o)
+		return Paginate(p.PageSize, p.PageIndex)(db)
+	}
+}
+
+// FindPage 查询一页数据与总数, list 为切片指针
+// e.g. count, err := search.FindPage(db.Model(&User{}), &list, &q, &q.Page)
+func FindPage(db *gorm.DB, list interface{}, q interface{}, p *Page, opts ...Option) (int64, error) {
+	var count int64
+	err := db.Scopes(List(q, p, opts...)).Find(list).
+		Limit(-1).Offset(-1).Count(&count).Error
+	return count, err
+}
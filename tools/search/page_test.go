@@ -0,0 +1,67 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type user struct {
+	Id   int
+	Name string
+}
+
+type userQuery struct {
+	Page   `search:"-"`
+	Name   string `search:"type:contains;column:name" form:"name"`
+	Status int    `search:"type:exact;column:status;table:sys_user" form:"status"`
+}
+
+func dryRun(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestList(t *testing.T) {
+	db := dryRun(t)
+	sortable := WithSortable(map[string]string{"name": "name", "createdAt": "sys_user.created_at"})
+
+	q := userQuery{Name: "ad", Status: 1, Page: Page{PageIndex: 3, PageSize: 1000, Sort: "-createdAt,password,name"}}
+	var list []user
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&user{}).Scopes(List(&q, &q.Page, sortable)).Find(&list)
+	})
+	for _, want := range []string{
+		"`name` like '%ad%'",
+		"`sys_user`.`status` = 1",
+		"ORDER BY `sys_user`.`created_at` DESC,`name`",
+		"LIMIT 100 OFFSET 200",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("sql = %s, want %s", sql, want)
+		}
+	}
+	if strings.Contains(sql, "password") {
+		t.Errorf("sort column not in allowlist: %s", sql)
+	}
+	if q.PageSize != 100 {
+		t.Errorf("page size = %d", q.PageSize)
+	}
+
+	q = userQuery{}
+	sql = db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&user{}).Scopes(List(&q, &q.Page, sortable, WithDefaultSort("-createdAt"), WithDefaultPageSize(20))).Find(&list)
+	})
+	if !strings.HasSuffix(sql, "ORDER BY `sys_user`.`created_at` DESC LIMIT 20") || strings.Contains(sql, "WHERE") {
+		t.Errorf("sql = %s", sql)
+	}
+	if q.PageIndex != 1 {
+		t.Errorf("page index = %d", q.PageIndex)
+	}
+}
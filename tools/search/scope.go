@@ -0,0 +1,58 @@
+package search
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// MakeCondition 按 search 标签生成查询条件
+// e.g. db.Scopes(MakeCondition(req)).Find(&list)
+func MakeCondition(q interface{}) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		v := reflect.ValueOf(q)
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return db
+			}
+			v = v.Elem()
+		}
+		condition := &GormCondition{
+			GormPublic: GormPublic{},
+			Join:       make([]*GormJoin, 0),
+		}
+		ResolveSearchQuery(db.Dialector.Name(), v.Interface(), condition)
+		for _, join := range condition.Join {
+			if join == nil {
+				continue
+			}
+			db = db.Joins(join.JoinOn)
+			db = join.GormPublic.apply(db)
+		}
+		return condition.GormPublic.apply(db)
+	}
+}
+
+func (e *GormPublic) apply(db *gorm.DB) *gorm.DB {
+	for k, v := range e.Where {
+		db = db.Where(k, v...)
+	}
+	for k, v := range e.Or {
+		db = db.Or(k, v...)
+	}
+	for _, o := range e.Order {
+		db = db.Order(o)
+	}
+	return db
+}
+
+// Paginate 分页, pageIndex 从1开始
+func Paginate(pageSize, pageIndex int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		offset := (pageIndex - 1) * pageSize
+		if offset < 0 {
+			offset = 0
+		}
+		return db.Offset(offset).Limit(pageSize)
+	}
+}
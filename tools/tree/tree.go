@@ -0,0 +1,197 @@
+package tree
+
+import "errors"
+
+var (
+	// ErrCycle 父子关系存在环, 如节点的父节点是自己或自己的子孙
+	ErrCycle = errors.New("tree: cycle detected")
+	// ErrDuplicateID 存在重复的id
+	ErrDuplicateID = errors.New("tree: duplicate id")
+)
+
+// Node 树节点, 用于没有 Children 字段的结构体
+type Node[T any] struct {
+	Data     T
+	Children []*Node[T]
+}
+
+// Build 按 id 与父id构建树, 父节点不存在的节点(如 parentId 为0)作为根节点, 同级节点保持 items 中的顺序
+func Build[T any, K comparable](items []T, id, parent func(T) K) ([]*Node[T], error) {
+	roots, children, err := index(items, id, parent)
+	if err != nil {
+		return nil, err
+	}
+	var build func(i int) *Node[T]
+	build = func(i int) *Node[T] {
+		n := &Node[T]{Data: items[i]}
+		for _, c := range children[id(items[i])] {
+			n.Children = append(n.Children, build(c))
+		}
+		return n
+	}
+	nodes := make([]*Node[T], 0, len(roots))
+	for _, i := range roots {
+		nodes = append(nodes, build(i))
+	}
+	return nodes, nil
+}
+
+// BuildTree 与 Build 相同, 子节点通过 setChildren 写入结构体自身的 Children 字段, 没有子节点时不调用
+//
+//	menus, err := tree.BuildTree(list,
+//		func(m SysMenu) int { return m.MenuId },
+//		func(m SysMenu) int { return m.ParentId },
+//		func(m *SysMenu, c []SysMenu) { m.Children = c })
+func BuildTree[T any, K comparable](items []T, id, parent func(T) K, setChildren func(*T, []T)) ([]T, error) {
+	roots, children, err := index(items, id, parent)
+	if err != nil {
+		return nil, err
+	}
+	var build func(i int) T
+	build = func(i int) T {
+		item := items[i]
+		if cs := children[id(item)]; len(cs) > 0 {
+			list := make([]T, 0, len(cs))
+			for _, c := range cs {
+				list = append(list, build(c))
+			}
+			setChildren(&item, list)
+		}
+		return item
+	}
+	list := make([]T, 0, len(roots))
+	for _, i := range roots {
+		list = append(list, build(i))
+	}
+	return list, nil
+}
+
+// index 返回根节点与各节点的子节点下标, 并检查重复id与环
+func index[T any, K comparable](items []T, id, parent func(T) K) ([]int, map[K][]int, error) {
+	ids := make(map[K]struct{}, len(items))
+	for _, item := range items {
+		k := id(item)
+		if _, ok := ids[k]; ok {
+			return nil, nil, ErrDuplicateID
+		}
+		ids[k] = struct{}{}
+	}
+	var roots []int
+	children := make(map[K][]int)
+	for i, item := range items {
+		p := parent(item)
+		if _, ok := ids[p]; ok {
+			children[p] = append(children[p], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+	// 环上的节点无法从根节点到达
+	n := 0
+	stack := append([]int(nil), roots...)
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = append(stack[:len(stack)-1], children[id(items[i])]...)
+		n++
+	}
+	if n != len(items) {
+		return nil, nil, ErrCycle
+	}
+	return roots, children, nil
+}
+
+// Flatten 先序展开树, children 返回节点的子节点
+//
+//	nodes := tree.Flatten(roots, func(n *tree.Node[Dept]) []*tree.Node[Dept] { return n.Children })
+func Flatten[T any](roots []T, children func(T) []T) []T {
+	var list []T
+	var walk func(items []T)
+	walk = func(items []T) {
+		for _, item := range items {
+			list = append(list, item)
+			walk(children(item))
+		}
+	}
+	walk(roots)
+	return list
+}
+
+// Walk 先序遍历树, depth 从0开始, f 返回false时不再遍历该节点的子节点
+func Walk[T any](roots []T, children func(T) []T, f func(item T, depth int) bool) {
+	var walk func(items []T, depth int)
+	walk = func(items []T, depth int) {
+		for _, item := range items {
+			if f(item, depth) {
+				walk(children(item), depth+1)
+			}
+		}
+	}
+	walk(roots, 0)
+}
+
+// Subtree 返回 root 及其所有子孙, 如部门及下级部门; 顺序为广度优先, root 不存在时只返回子孙
+func Subtree[T any, K comparable](items []T, id, parent func(T) K, root K) []T {
+	children := make(map[K][]int)
+	var list []T
+	for i, item := range items {
+		if id(item) == root {
+			list = append(list, item)
+		}
+		children[parent(item)] = append(children[parent(item)], i)
+	}
+	visited := map[K]bool{root: true}
+	queue := []K{root}
+	for len(queue) > 0 {
+		k := queue[0]
+		queue = queue[1:]
+		for _, i := range children[k] {
+			c := id(items[i])
+			if visited[c] {
+				continue
+			}
+			visited[c] = true
+			list = append(list, items[i])
+			queue = append(queue, c)
+		}
+	}
+	return list
+}
+
+// Ancestors 返回从根节点到 k 的路径, 包含 k 自身, 可用于生成 /0/1/3/ 形式的路径
+func Ancestors[T any, K comparable](items []T, id, parent func(T) K, k K) ([]T, error) {
+	byID := make(map[K]T, len(items))
+	for _, item := range items {
+		byID[id(item)] = item
+	}
+	var path []T
+	visited := make(map[K]bool)
+	for {
+		item, ok := byID[k]
+		if !ok {
+			break
+		}
+		if visited[k] {
+			return nil, ErrCycle
+		}
+		visited[k] = true
+		path = append(path, item)
+		k = parent(item)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, nil
+}
+
+// CheckParent 检查把 k 的父节点改为 newParent 是否会产生环, 用于修改菜单、部门的上级前校验
+func CheckParent[T any, K comparable](items []T, id, parent func(T) K, k, newParent K) error {
+	if k == newParent {
+		return ErrCycle
+	}
+	for _, item := range Subtree(items, id, parent, k) {
+		if id(item) == newParent {
+			return ErrCycle
+		}
+	}
+	return nil
+}
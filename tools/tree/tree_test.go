@@ -0,0 +1,108 @@
+package tree
+
+import (
+	"errors"
+	"testing"
+)
+
+type dept struct {
+	Id       int
+	ParentId int
+	Name     string
+	Children []dept
+}
+
+func deptID(d dept) int     { return d.Id }
+func deptParent(d dept) int { return d.ParentId }
+
+var depts = []dept{
+	{Id: 1, ParentId: 0, Name: "总部"},
+	{Id: 2, ParentId: 1, Name: "研发"},
+	{Id: 3, ParentId: 1, Name: "销售"},
+	{Id: 4, ParentId: 2, Name: "后端"},
+	{Id: 5, ParentId: 9, Name: "孤立"},
+}
+
+func names(list []dept) string {
+	var s string
+	for _, d := range list {
+		s += d.Name + ","
+	}
+	return s
+}
+
+func TestBuildTree(t *testing.T) {
+	roots, err := BuildTree(depts, deptID, deptParent, func(d *dept, c []dept) { d.Children = c })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roots) != 2 || roots[0].Name != "总部" || roots[1].Name != "孤立" {
+		t.Fatalf("roots = %s", names(roots))
+	}
+	if names(roots[0].Children) != "研发,销售," || names(roots[0].Children[0].Children) != "后端," || roots[0].Children[1].Children != nil {
+		t.Errorf("children = %+v", roots[0].Children)
+	}
+	if depts[0].Children != nil {
+		t.Error("items should not be modified")
+	}
+
+	children := func(d dept) []dept { return d.Children }
+	if got := names(Flatten(roots, children)); got != "总部,研发,后端,销售,孤立," {
+		t.Errorf("flatten = %s", got)
+	}
+	var depth []int
+	Walk(roots, children, func(d dept, n int) bool {
+		depth = append(depth, n)
+		return d.Id != 2
+	})
+	if len(depth) != 4 || depth[2] != 1 {
+		t.Errorf("depth = %v", depth)
+	}
+
+	nodes, err := Build(depts, deptID, deptParent)
+	if err != nil || len(nodes) != 2 || len(nodes[0].Children) != 2 || nodes[0].Children[0].Children[0].Data.Name != "后端" {
+		t.Errorf("nodes = %+v, err = %v", nodes, err)
+	}
+}
+
+func TestCycle(t *testing.T) {
+	cycle := append([]dept{}, depts...)
+	cycle = append(cycle, dept{Id: 6, ParentId: 7, Name: "甲"}, dept{Id: 7, ParentId: 6, Name: "乙"})
+	if _, err := Build(cycle, deptID, deptParent); !errors.Is(err, ErrCycle) {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := Build([]dept{{Id: 1, ParentId: 1}}, deptID, deptParent); !errors.Is(err, ErrCycle) {
+		t.Errorf("self parent: err = %v", err)
+	}
+	if _, err := Build([]dept{{Id: 1}, {Id: 1}}, deptID, deptParent); !errors.Is(err, ErrDuplicateID) {
+		t.Errorf("duplicate: err = %v", err)
+	}
+	if _, err := Ancestors(cycle, deptID, deptParent, 6); !errors.Is(err, ErrCycle) {
+		t.Errorf("ancestors: err = %v", err)
+	}
+	if got := names(Subtree(cycle, deptID, deptParent, 6)); got != "甲,乙," {
+		t.Errorf("subtree in cycle = %q", got)
+	}
+}
+
+func TestSubtree(t *testing.T) {
+	if got := names(Subtree(depts, deptID, deptParent, 1)); got != "总部,研发,销售,后端," {
+		t.Errorf("subtree = %s", got)
+	}
+	if got := names(Subtree(depts, deptID, deptParent, 0)); got != "总部,研发,销售,后端," {
+		t.Errorf("subtree of 0 = %s", got)
+	}
+	path, err := Ancestors(depts, deptID, deptParent, 4)
+	if err != nil || names(path) != "总部,研发,后端," {
+		t.Errorf("ancestors = %s, err = %v", names(path), err)
+	}
+	if err = CheckParent(depts, deptID, deptParent, 2, 4); !errors.Is(err, ErrCycle) {
+		t.Errorf("move under descendant: err = %v", err)
+	}
+	if err = CheckParent(depts, deptID, deptParent, 2, 2); !errors.Is(err, ErrCycle) {
+		t.Errorf("move under self: err = %v", err)
+	}
+	if err = CheckParent(depts, deptID, deptParent, 4, 3); err != nil {
+		t.Errorf("valid move: err = %v", err)
+	}
+}